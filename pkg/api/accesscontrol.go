@@ -20,12 +20,15 @@ const (
 
 // API related scopes
 var (
-	ScopeProvisionersAll           = ac.Scope("provisioners", "*")
-	ScopeProvisionersDashboards    = ac.Scope("provisioners", "dashboards")
-	ScopeProvisionersPlugins       = ac.Scope("provisioners", "plugins")
-	ScopeProvisionersDatasources   = ac.Scope("provisioners", "datasources")
-	ScopeProvisionersNotifications = ac.Scope("provisioners", "notifications")
-	ScopeProvisionersAlertRules    = ac.Scope("provisioners", "alerting")
+	ScopeProvisionersAll             = ac.Scope("provisioners", "*")
+	ScopeProvisionersDashboards      = ac.Scope("provisioners", "dashboards")
+	ScopeProvisionersPlugins         = ac.Scope("provisioners", "plugins")
+	ScopeProvisionersDatasources     = ac.Scope("provisioners", "datasources")
+	ScopeProvisionersNotifications   = ac.Scope("provisioners", "notifications")
+	ScopeProvisionersAlertRules      = ac.Scope("provisioners", "alerting")
+	ScopeProvisionersAccess          = ac.Scope("provisioners", "access")
+	ScopeProvisionersServiceAccounts = ac.Scope("provisioners", "serviceaccounts")
+	ScopeProvisionersLibraryPanels   = ac.Scope("provisioners", "librarypanels")
 )
 
 // declareFixedRoles declares to the AccessControl service fixed roles and their
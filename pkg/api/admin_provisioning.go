@@ -3,9 +3,11 @@ package api
 import (
 	"context"
 	"errors"
+	"net/http"
 
 	"github.com/grafana/grafana/pkg/api/response"
 	"github.com/grafana/grafana/pkg/models"
+	"github.com/grafana/grafana/pkg/services/provisioning/validation"
 )
 
 // swagger:route POST /admin/provisioning/dashboards/reload admin_provisioning adminProvisioningReloadDashboards
@@ -107,3 +109,101 @@ func (hs *HTTPServer) AdminProvisioningReloadAlerting(c *models.ReqContext) resp
 	}
 	return response.Success("Alerting config reloaded")
 }
+
+// swagger:route POST /admin/provisioning/access/reload admin_provisioning adminProvisioningReloadAccess
+//
+// Reload folder, team and permission provisioning configurations.
+//
+// Reloads the provisioning config files for folders, teams and folder and dashboard permissions again. It won’t return until the new provisioned entities are already stored in the database.
+// If you are running Grafana Enterprise and have Fine-grained access control enabled, you need to have a permission with action `provisioning:reload` and scope `provisioners:access`.
+//
+// Security:
+// - basic:
+//
+// Responses:
+// 200: okResponse
+// 401: unauthorisedError
+// 403: forbiddenError
+// 500: internalServerError
+func (hs *HTTPServer) AdminProvisioningReloadAccess(c *models.ReqContext) response.Response {
+	err := hs.ProvisioningService.ProvisionAccess(c.Req.Context())
+	if err != nil {
+		return response.Error(500, "", err)
+	}
+	return response.Success("Access config reloaded")
+}
+
+// swagger:route POST /admin/provisioning/serviceaccounts/reload admin_provisioning adminProvisioningReloadServiceAccounts
+//
+// Reload service account provisioning configurations.
+//
+// Reloads the provisioning config files for service accounts and their tokens again. It won’t return until the new provisioned entities are already stored in the database.
+// If you are running Grafana Enterprise and have Fine-grained access control enabled, you need to have a permission with action `provisioning:reload` and scope `provisioners:serviceaccounts`.
+//
+// Security:
+// - basic:
+//
+// Responses:
+// 200: okResponse
+// 401: unauthorisedError
+// 403: forbiddenError
+// 500: internalServerError
+func (hs *HTTPServer) AdminProvisioningReloadServiceAccounts(c *models.ReqContext) response.Response {
+	err := hs.ProvisioningService.ProvisionServiceAccounts(c.Req.Context())
+	if err != nil {
+		return response.Error(500, "", err)
+	}
+	return response.Success("Service accounts config reloaded")
+}
+
+// swagger:route POST /admin/provisioning/librarypanels/reload admin_provisioning adminProvisioningReloadLibraryPanels
+//
+// Reload library panel provisioning configurations.
+//
+// Reloads the provisioning config files for library panels again. It won’t return until the new provisioned entities are already stored in the database.
+// If you are running Grafana Enterprise and have Fine-grained access control enabled, you need to have a permission with action `provisioning:reload` and scope `provisioners:librarypanels`.
+//
+// Security:
+// - basic:
+//
+// Responses:
+// 200: okResponse
+// 401: unauthorisedError
+// 403: forbiddenError
+// 500: internalServerError
+func (hs *HTTPServer) AdminProvisioningReloadLibraryPanels(c *models.ReqContext) response.Response {
+	err := hs.ProvisioningService.ProvisionLibraryPanels(c.Req.Context())
+	if err != nil {
+		return response.Error(500, "", err)
+	}
+	return response.Success("Library panels config reloaded")
+}
+
+// swagger:route POST /admin/provisioning/validate admin_provisioning adminProvisioningValidate
+//
+// Validate provisioning configurations.
+//
+// Validates the provisioning config files of data sources, plugins, dashboards and alerting without applying them, and returns the problems of each file and the resources that provisioning would create, update or delete.
+// If you are running Grafana Enterprise and have Fine-grained access control enabled, you need to have a permission with action `provisioning:reload` and scope `provisioners:*`.
+//
+// Security:
+// - basic:
+//
+// Responses:
+// 200: adminProvisioningValidateResponse
+// 401: unauthorisedError
+// 403: forbiddenError
+// 500: internalServerError
+func (hs *HTTPServer) AdminProvisioningValidate(c *models.ReqContext) response.Response {
+	reports, err := hs.ProvisioningService.ValidateProvisioning(c.Req.Context())
+	if err != nil {
+		return response.Error(500, "Failed to validate provisioning config", err)
+	}
+	return response.JSON(http.StatusOK, reports)
+}
+
+// swagger:response adminProvisioningValidateResponse
+type AdminProvisioningValidateResponse struct {
+	// in:body
+	Body []*validation.Report `json:"body"`
+}
@@ -8,6 +8,7 @@ import (
 
 	"github.com/grafana/grafana/pkg/services/accesscontrol"
 	"github.com/grafana/grafana/pkg/services/provisioning"
+	"github.com/grafana/grafana/pkg/services/provisioning/validation"
 	"github.com/grafana/grafana/pkg/setting"
 	"github.com/stretchr/testify/assert"
 )
@@ -162,6 +163,96 @@ func TestAPI_AdminProvisioningReload_AccessControl(t *testing.T) {
 			url:          "/api/admin/provisioning/alerting/reload",
 			exit:         true,
 		},
+		{
+			desc:         "should work for access with specific scope",
+			expectedCode: http.StatusOK,
+			expectedBody: `{"message":"Access config reloaded"}`,
+			permissions: []accesscontrol.Permission{
+				{
+					Action: ActionProvisioningReload,
+					Scope:  ScopeProvisionersAccess,
+				},
+			},
+			url: "/api/admin/provisioning/access/reload",
+			checkCall: func(mock provisioning.ProvisioningServiceMock) {
+				assert.Len(t, mock.Calls.ProvisionAccess, 1)
+			},
+		},
+		{
+			desc:         "should fail for access with no permission",
+			expectedCode: http.StatusForbidden,
+			url:          "/api/admin/provisioning/access/reload",
+			exit:         true,
+		},
+		{
+			desc:         "should work for service accounts with specific scope",
+			expectedCode: http.StatusOK,
+			expectedBody: `{"message":"Service accounts config reloaded"}`,
+			permissions: []accesscontrol.Permission{
+				{
+					Action: ActionProvisioningReload,
+					Scope:  ScopeProvisionersServiceAccounts,
+				},
+			},
+			url: "/api/admin/provisioning/serviceaccounts/reload",
+			checkCall: func(mock provisioning.ProvisioningServiceMock) {
+				assert.Len(t, mock.Calls.ProvisionServiceAccounts, 1)
+			},
+		},
+		{
+			desc:         "should fail for service accounts with no permission",
+			expectedCode: http.StatusForbidden,
+			url:          "/api/admin/provisioning/serviceaccounts/reload",
+			exit:         true,
+		},
+		{
+			desc:         "should work for library panels with specific scope",
+			expectedCode: http.StatusOK,
+			expectedBody: `{"message":"Library panels config reloaded"}`,
+			permissions: []accesscontrol.Permission{
+				{
+					Action: ActionProvisioningReload,
+					Scope:  ScopeProvisionersLibraryPanels,
+				},
+			},
+			url: "/api/admin/provisioning/librarypanels/reload",
+			checkCall: func(mock provisioning.ProvisioningServiceMock) {
+				assert.Len(t, mock.Calls.ProvisionLibraryPanels, 1)
+			},
+		},
+		{
+			desc:         "should fail for library panels with no permission",
+			expectedCode: http.StatusForbidden,
+			url:          "/api/admin/provisioning/librarypanels/reload",
+			exit:         true,
+		},
+		{
+			desc:         "should work for validation with broader scope",
+			expectedCode: http.StatusOK,
+			expectedBody: `[{"provisioner":"datasources","problems":[],"changes":[{"action":"create","kind":"data source","orgId":1,"name":"Graphite","file":"datasources/graphite.yaml"}]}]`,
+			permissions: []accesscontrol.Permission{
+				{
+					Action: ActionProvisioningReload,
+					Scope:  ScopeProvisionersAll,
+				},
+			},
+			url: "/api/admin/provisioning/validate",
+			checkCall: func(mock provisioning.ProvisioningServiceMock) {
+				assert.Len(t, mock.Calls.ValidateProvisioning, 1)
+			},
+		},
+		{
+			desc:         "should fail for validation with specific scope",
+			expectedCode: http.StatusForbidden,
+			permissions: []accesscontrol.Permission{
+				{
+					Action: ActionProvisioningReload,
+					Scope:  ScopeProvisionersDashboards,
+				},
+			},
+			url:  "/api/admin/provisioning/validate",
+			exit: true,
+		},
 	}
 
 	cfg := setting.NewCfg()
@@ -172,6 +263,11 @@ func TestAPI_AdminProvisioningReload_AccessControl(t *testing.T) {
 
 			// Setup the mock
 			provisioningMock := provisioning.NewProvisioningServiceMock(context.Background())
+			provisioningMock.ValidateProvisioningFunc = func() ([]*validation.Report, error) {
+				report := validation.NewReport("datasources")
+				report.AddChange(validation.ActionCreate, "data source", 1, "Graphite", "datasources/graphite.yaml")
+				return []*validation.Report{report}, nil
+			}
 			hs.ProvisioningService = provisioningMock
 
 			sc.resp = httptest.NewRecorder()
@@ -1267,6 +1267,11 @@ func (l *mockLibraryElementService) GetElement(c context.Context, signedInUser *
 	return libraryelements.LibraryElementDTO{}, nil
 }
 
+// PatchElement updates an element from a UID.
+func (l *mockLibraryElementService) PatchElement(c context.Context, signedInUser *user.SignedInUser, cmd libraryelements.PatchLibraryElementCommand, UID string) (libraryelements.LibraryElementDTO, error) {
+	return libraryelements.LibraryElementDTO{}, nil
+}
+
 // GetElementsForDashboard gets all connected elements for a specific dashboard.
 func (l *mockLibraryElementService) GetElementsForDashboard(c context.Context, dashboardID int64) (map[string]libraryelements.LibraryElementDTO, error) {
 	return map[string]libraryelements.LibraryElementDTO{}, nil
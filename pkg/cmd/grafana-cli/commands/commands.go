@@ -194,6 +194,17 @@ var adminCommands = []*cli.Command{
 			},
 		},
 	},
+	{
+		Name:  "provisioning",
+		Usage: "Runs commands on the provisioning files",
+		Subcommands: []*cli.Command{
+			{
+				Name:   "validate",
+				Usage:  "validate <provisioning dir>. Validates the provisioning files and lists the changes provisioning would make, without applying them. Uses the configured provisioning directory when no directory is given.",
+				Action: runRunnerCommand(validateProvisioningCommand),
+			},
+		},
+	},
 	{
 		Name:  "user-manager",
 		Usage: "Runs different helpful user commands",
@@ -0,0 +1,54 @@
+package commands
+
+import (
+	"context"
+	"fmt"
+	"strings"
+
+	"github.com/fatih/color"
+	"github.com/grafana/grafana/pkg/cmd/grafana-cli/logger"
+	"github.com/grafana/grafana/pkg/cmd/grafana-cli/runner"
+	"github.com/grafana/grafana/pkg/cmd/grafana-cli/utils"
+)
+
+func validateProvisioningCommand(c utils.CommandLine, runner runner.Runner) error {
+	if dir := c.Args().First(); dir != "" {
+		runner.Cfg.ProvisioningPath = dir
+	}
+
+	reports, err := runner.Provisioning.ValidateProvisioning(context.Background())
+	if err != nil {
+		return fmt.Errorf("failed to validate provisioning files: %w", err)
+	}
+
+	problems := 0
+	for _, report := range reports {
+		logger.Infof("%s:\n", report.Provisioner)
+		for _, problem := range report.Problems {
+			if problem.Line > 0 {
+				logger.Infof("  %s %s:%d: %s\n", color.RedString("✘"), problem.File, problem.Line, problem.Message)
+			} else {
+				logger.Infof("  %s %s: %s\n", color.RedString("✘"), problem.File, problem.Message)
+			}
+		}
+		for _, change := range report.Changes {
+			if len(change.Fields) > 0 {
+				logger.Infof("  %s %s %q (org %d) from %s: %s\n", change.Action, change.Kind, change.Name, change.OrgID, change.File, strings.Join(change.Fields, ", "))
+				continue
+			}
+			logger.Infof("  %s %s %q (org %d) from %s\n", change.Action, change.Kind, change.Name, change.OrgID, change.File)
+		}
+		if len(report.Problems) == 0 && len(report.Changes) == 0 {
+			logger.Infof("  no changes\n")
+		}
+		problems += len(report.Problems)
+	}
+
+	logger.Infof("\n")
+	if problems > 0 {
+		return fmt.Errorf("found %d problems in provisioning files", problems)
+	}
+	logger.Infof("Provisioning files are valid %s\n", color.GreenString("✔"))
+
+	return nil
+}
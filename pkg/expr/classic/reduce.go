@@ -3,20 +3,31 @@ package classic
 import (
 	"math"
 	"sort"
+	"strings"
 
 	"github.com/grafana/grafana/pkg/expr/mathexp"
 )
 
 type reducer string
 
+// ValidReduceFunc returns whether the reducer is supported. Reducer names are case-insensitive, like the names of
+// the reducers of math reduce expressions.
 func (cr reducer) ValidReduceFunc() bool {
+	cr = cr.normalize()
 	switch cr {
 	case "avg", "sum", "min", "max", "count", "last", "median":
 		return true
 	case "diff", "diff_abs", "percent_diff", "percent_diff_abs", "count_non_null":
 		return true
+	case "first", "range", "stddev":
+		return true
 	}
-	return false
+	_, ok := mathexp.ParsePercentile(string(cr))
+	return ok
+}
+
+func (cr reducer) normalize() reducer {
+	return reducer(strings.ToLower(string(cr)))
 }
 
 //nolint:gocyclo
@@ -39,6 +50,7 @@ func (cr reducer) Reduce(series mathexp.Series) mathexp.Number {
 	vF := series.Frame.Fields[1]
 	ff := mathexp.Float64Field(*vF)
 
+	cr = cr.normalize()
 	switch cr {
 	case "avg":
 		validPointsCount := 0
@@ -144,6 +156,51 @@ func (cr reducer) Reduce(series mathexp.Series) mathexp.Number {
 		if value > 0 {
 			allNull = false
 		}
+	case "first":
+		for i := 0; i < ff.Len(); i++ {
+			f := ff.GetValue(i)
+			if !nilOrNaN(f) {
+				value = *f
+				allNull = false
+				break
+			}
+		}
+	case "range":
+		minValue, maxValue := math.MaxFloat64, -math.MaxFloat64
+		for i := 0; i < ff.Len(); i++ {
+			f := ff.GetValue(i)
+			if nilOrNaN(f) {
+				continue
+			}
+			allNull = false
+			minValue = math.Min(minValue, *f)
+			maxValue = math.Max(maxValue, *f)
+		}
+		if !allNull {
+			value = maxValue - minValue
+		}
+	case "stddev":
+		values := nonNullValues(ff)
+		if len(values) > 0 {
+			allNull = false
+			var mean float64
+			for _, v := range values {
+				mean += v
+			}
+			mean /= float64(len(values))
+			for _, v := range values {
+				value += math.Pow(v-mean, 2)
+			}
+			value = math.Sqrt(value / float64(len(values)))
+		}
+	default:
+		if p, ok := mathexp.ParsePercentile(string(cr)); ok {
+			values := nonNullValues(ff)
+			if len(values) > 0 {
+				allNull = false
+				value = mathexp.PercentileOf(values, p)
+			}
+		}
 	}
 
 	if allNull {
@@ -182,6 +239,19 @@ func calculateDiff(ff mathexp.Float64Field, allNull bool, value float64, fn func
 	return allNull, value
 }
 
+// nonNullValues returns the values of the field that are neither null nor NaN.
+func nonNullValues(ff mathexp.Float64Field) []float64 {
+	var values []float64
+	for i := 0; i < ff.Len(); i++ {
+		f := ff.GetValue(i)
+		if nilOrNaN(f) {
+			continue
+		}
+		values = append(values, *f)
+	}
+	return values
+}
+
 func nilOrNaN(f *float64) bool {
 	return f == nil || math.IsNaN(*f)
 }
@@ -115,6 +115,72 @@ func TestReducer(t *testing.T) {
 			inputSeries:    newSeries(nil, nil),
 			expectedNumber: newNumber(nil),
 		},
+		{
+			name:           "first should ignore null values",
+			reducer:        reducer("first"),
+			inputSeries:    newSeries(nil, ptr.Float64(math.NaN()), ptr.Float64(3), ptr.Float64(4)),
+			expectedNumber: newNumber(ptr.Float64(3)),
+		},
+		{
+			name:           "first with only nulls",
+			reducer:        reducer("first"),
+			inputSeries:    newSeries(nil, nil),
+			expectedNumber: newNumber(nil),
+		},
+		{
+			name:           "range should ignore null values",
+			reducer:        reducer("range"),
+			inputSeries:    newSeries(ptr.Float64(3), nil, ptr.Float64(-1), ptr.Float64(7)),
+			expectedNumber: newNumber(ptr.Float64(8)),
+		},
+		{
+			name:           "range with only nulls",
+			reducer:        reducer("range"),
+			inputSeries:    newSeries(nil, ptr.Float64(math.NaN())),
+			expectedNumber: newNumber(nil),
+		},
+		{
+			name:           "stddev",
+			reducer:        reducer("stddev"),
+			inputSeries:    newSeries(ptr.Float64(2), ptr.Float64(4), ptr.Float64(4), ptr.Float64(4), ptr.Float64(5), ptr.Float64(5), ptr.Float64(7), ptr.Float64(9)),
+			expectedNumber: newNumber(ptr.Float64(2)),
+		},
+		{
+			name:           "stddev should ignore null values",
+			reducer:        reducer("stddev"),
+			inputSeries:    newSeries(nil, ptr.Float64(1), ptr.Float64(1)),
+			expectedNumber: newNumber(ptr.Float64(0)),
+		},
+		{
+			name:           "stddev with only nulls",
+			reducer:        reducer("stddev"),
+			inputSeries:    newSeries(nil),
+			expectedNumber: newNumber(nil),
+		},
+		{
+			name:           "p50 is the same as median",
+			reducer:        reducer("p50"),
+			inputSeries:    newSeries(ptr.Float64(1), ptr.Float64(2), ptr.Float64(4), ptr.Float64(3000)),
+			expectedNumber: newNumber(ptr.Float64(3)),
+		},
+		{
+			name:           "p75 interpolates between values",
+			reducer:        reducer("p75"),
+			inputSeries:    newSeries(ptr.Float64(40), ptr.Float64(10), nil, ptr.Float64(30), ptr.Float64(20), ptr.Float64(50)),
+			expectedNumber: newNumber(ptr.Float64(40)),
+		},
+		{
+			name:           "p99.5 with one value",
+			reducer:        reducer("p99.5"),
+			inputSeries:    newSeries(ptr.Float64(1)),
+			expectedNumber: newNumber(ptr.Float64(1)),
+		},
+		{
+			name:           "p95 with only nulls",
+			reducer:        reducer("p95"),
+			inputSeries:    newSeries(nil, nil),
+			expectedNumber: newNumber(nil),
+		},
 	}
 
 	for _, tt := range tests {
@@ -126,6 +192,15 @@ func TestReducer(t *testing.T) {
 	}
 }
 
+func TestValidReduceFunc(t *testing.T) {
+	for _, r := range []reducer{"p0", "p95", "p99.9", "p100", "P95", "AVG"} {
+		require.Truef(t, r.ValidReduceFunc(), "%s should be valid", r)
+	}
+	for _, r := range []reducer{"p", "p101", "p-1", "pNaN", "p1e1", "p+50", "p0x32", "p95.", "p.5", "foo"} {
+		require.Falsef(t, r.ValidReduceFunc(), "%s should be invalid", r)
+	}
+}
+
 func TestDiffReducer(t *testing.T) {
 	var tests = []struct {
 		name           string
@@ -0,0 +1,117 @@
+package expr
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/grafana/grafana-plugin-sdk-go/data"
+
+	"github.com/grafana/grafana/pkg/expr/mathexp"
+)
+
+// HysteresisCommand is a threshold command with a recovery threshold. It applies one of two thresholds
+// to each dimension, depending on whether the dimension is alerting ("loaded") at the moment:
+//   - the loading threshold is applied to dimensions that are not alerting. It returns 1 when the dimension should start alerting.
+//   - the unloading threshold is applied to dimensions that are alerting. It returns 1 as long as the recovery threshold is not crossed.
+//
+// The dimensions that are alerting are identified by their labels in LoadedDimensions.
+type HysteresisCommand struct {
+	RefID                  string
+	ReferenceVar           string
+	LoadingThresholdFunc   ThresholdCommand
+	UnloadingThresholdFunc ThresholdCommand
+	LoadedDimensions       map[string]struct{}
+}
+
+// NewHysteresisCommand creates a new HysteresisCommand.
+func NewHysteresisCommand(refID, referenceVar string, loadCondition, unloadCondition ConditionEvalJSON, loadedDimensions []data.Labels) (*HysteresisCommand, error) {
+	loadingThresholdFunc, err := NewThresholdCommand(refID, referenceVar, loadCondition.Type, loadCondition.Params)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create threshold: %w", err)
+	}
+	unloadingThresholdFunc, err := NewThresholdCommand(refID, referenceVar, unloadCondition.Type, unloadCondition.Params)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create recovery threshold: %w", err)
+	}
+	// the recovery threshold describes when the dimension stops alerting, therefore its result is inverted.
+	unloadingThresholdFunc.Invert = true
+
+	loaded := make(map[string]struct{}, len(loadedDimensions))
+	for _, l := range loadedDimensions {
+		loaded[l.String()] = struct{}{}
+	}
+
+	return &HysteresisCommand{
+		RefID:                  refID,
+		ReferenceVar:           referenceVar,
+		LoadingThresholdFunc:   *loadingThresholdFunc,
+		UnloadingThresholdFunc: *unloadingThresholdFunc,
+		LoadedDimensions:       loaded,
+	}, nil
+}
+
+// NeedsVars returns the variable names (refIds) that are dependencies
+// to execute the command and allows the command to fulfill the Command interface.
+func (h *HysteresisCommand) NeedsVars() []string {
+	return []string{h.ReferenceVar}
+}
+
+func (h *HysteresisCommand) Execute(ctx context.Context, now time.Time, vars mathexp.Vars) (mathexp.Results, error) {
+	if len(h.LoadedDimensions) == 0 {
+		return h.LoadingThresholdFunc.Execute(ctx, now, vars)
+	}
+
+	var loadedVals, unloadedVals mathexp.Values
+	for _, value := range vars[h.ReferenceVar].Values {
+		if _, ok := h.LoadedDimensions[value.GetLabels().String()]; ok {
+			loadedVals = append(loadedVals, value)
+		} else {
+			unloadedVals = append(unloadedVals, value)
+		}
+	}
+	if len(loadedVals) == 0 {
+		return h.LoadingThresholdFunc.Execute(ctx, now, vars)
+	}
+	if len(unloadedVals) == 0 {
+		return h.UnloadingThresholdFunc.Execute(ctx, now, vars)
+	}
+
+	loadingResults, err := h.LoadingThresholdFunc.Execute(ctx, now, mathexp.Vars{h.ReferenceVar: mathexp.Results{Values: unloadedVals}})
+	if err != nil {
+		return mathexp.Results{}, fmt.Errorf("failed to execute threshold: %w", err)
+	}
+	unloadingResults, err := h.UnloadingThresholdFunc.Execute(ctx, now, mathexp.Vars{h.ReferenceVar: mathexp.Results{Values: loadedVals}})
+	if err != nil {
+		return mathexp.Results{}, fmt.Errorf("failed to execute recovery threshold: %w", err)
+	}
+	return mathexp.Results{Values: append(loadingResults.Values, unloadingResults.Values...)}, nil
+}
+
+// IsHysteresisExpression returns true if the query model is a threshold expression with a recovery threshold.
+func IsHysteresisExpression(query map[string]interface{}) bool {
+	if query["type"] != TypeThreshold.String() {
+		return false
+	}
+	conditions, ok := query["conditions"].([]interface{})
+	if !ok || len(conditions) != 1 {
+		return false
+	}
+	condition, ok := conditions[0].(map[string]interface{})
+	if !ok {
+		return false
+	}
+	unloadEvaluator, ok := condition["unloadEvaluator"]
+	return ok && unloadEvaluator != nil
+}
+
+// SetLoadedDimensionsToHysteresisCommand sets the labels of the dimensions that are currently alerting
+// to the query model of a threshold expression with a recovery threshold.
+func SetLoadedDimensionsToHysteresisCommand(query map[string]interface{}, dimensions []data.Labels) error {
+	if !IsHysteresisExpression(query) {
+		return fmt.Errorf("not a threshold expression with a recovery threshold")
+	}
+	condition := query["conditions"].([]interface{})[0].(map[string]interface{})
+	condition["loadedDimensions"] = dimensions
+	return nil
+}
@@ -0,0 +1,197 @@
+package expr
+
+import (
+	"context"
+	"encoding/json"
+	"testing"
+	"time"
+
+	"github.com/grafana/grafana-plugin-sdk-go/data"
+	"github.com/stretchr/testify/require"
+
+	"github.com/grafana/grafana/pkg/expr/mathexp"
+)
+
+const hysteresisQuery = `{
+	"expression" : "A",
+	"type": "threshold",
+	"conditions": [{
+		"evaluator": {
+			"type": "gt",
+			"params": [80]
+		},
+		"unloadEvaluator": {
+			"type": "lt",
+			"params": [70]
+		}
+	}]
+}`
+
+func TestUnmarshalHysteresisCommand(t *testing.T) {
+	t.Run("creates hysteresis command if condition has a recovery threshold", func(t *testing.T) {
+		cmd, err := UnmarshalThresholdCommand(&rawNode{RefID: "B", Query: queryMap(t, hysteresisQuery)})
+
+		require.NoError(t, err)
+		require.IsType(t, &HysteresisCommand{}, cmd)
+		h := cmd.(*HysteresisCommand)
+		require.Equal(t, "A", h.ReferenceVar)
+		require.Equal(t, ThresholdIsAbove, h.LoadingThresholdFunc.ThresholdFunc)
+		require.False(t, h.LoadingThresholdFunc.Invert)
+		require.Equal(t, ThresholdIsBelow, h.UnloadingThresholdFunc.ThresholdFunc)
+		require.True(t, h.UnloadingThresholdFunc.Invert)
+		require.Empty(t, h.LoadedDimensions)
+		require.Equal(t, []string{"A"}, h.NeedsVars())
+	})
+
+	t.Run("creates threshold command if condition has no recovery threshold", func(t *testing.T) {
+		cmd, err := UnmarshalThresholdCommand(&rawNode{RefID: "B", Query: queryMap(t, `{
+			"expression" : "A",
+			"type": "threshold",
+			"conditions": [{ "evaluator": { "type": "gt", "params": [80] } }]
+		}`)})
+
+		require.NoError(t, err)
+		require.IsType(t, &ThresholdCommand{}, cmd)
+	})
+
+	t.Run("fails if recovery threshold function is not supported", func(t *testing.T) {
+		_, err := UnmarshalThresholdCommand(&rawNode{RefID: "B", Query: queryMap(t, `{
+			"expression" : "A",
+			"type": "threshold",
+			"conditions": [{
+				"evaluator": { "type": "gt", "params": [80] },
+				"unloadEvaluator": { "type": "foo", "params": [70] }
+			}]
+		}`)})
+
+		require.ErrorContains(t, err, "expected recovery threshold function to be one of")
+	})
+
+	t.Run("reads loaded dimensions set to the query", func(t *testing.T) {
+		query := queryMap(t, hysteresisQuery)
+		require.True(t, IsHysteresisExpression(query))
+
+		loaded := []data.Labels{{"host": "a"}, {}}
+		require.NoError(t, SetLoadedDimensionsToHysteresisCommand(query, loaded))
+
+		// the query model is sent as JSON, so it must survive a round-trip.
+		b, err := json.Marshal(query)
+		require.NoError(t, err)
+		cmd, err := UnmarshalThresholdCommand(&rawNode{RefID: "B", Query: queryMap(t, string(b))})
+		require.NoError(t, err)
+
+		h := cmd.(*HysteresisCommand)
+		require.Equal(t, map[string]struct{}{
+			data.Labels{"host": "a"}.String(): {},
+			data.Labels{}.String():            {},
+		}, h.LoadedDimensions)
+	})
+}
+
+func TestIsHysteresisExpression(t *testing.T) {
+	require.True(t, IsHysteresisExpression(queryMap(t, hysteresisQuery)))
+	require.False(t, IsHysteresisExpression(queryMap(t, `{"type": "math", "expression": "$A > 1"}`)))
+	require.False(t, IsHysteresisExpression(queryMap(t, `{
+		"expression" : "A",
+		"type": "threshold",
+		"conditions": [{ "evaluator": { "type": "gt", "params": [80] } }]
+	}`)))
+
+	require.Error(t, SetLoadedDimensionsToHysteresisCommand(queryMap(t, `{"type": "math"}`), nil))
+}
+
+func TestHysteresisExecute(t *testing.T) {
+	number := func(host string, value float64) mathexp.Number {
+		n := mathexp.NewNumber("", data.Labels{"host": host})
+		n.SetValue(&value)
+		return n
+	}
+	vars := mathexp.Vars{
+		"A": mathexp.Results{Values: mathexp.Values{
+			number("alerting-above-recovery", 75),
+			number("alerting-below-recovery", 65),
+			number("normal-above-recovery", 75),
+			number("normal-above-threshold", 85),
+		}},
+	}
+	resultsByHost := func(t *testing.T, results mathexp.Results) map[string]float64 {
+		t.Helper()
+		res := make(map[string]float64, len(results.Values))
+		for _, v := range results.Values {
+			f := v.(mathexp.Number).GetFloat64Value()
+			require.NotNil(t, f)
+			res[v.GetLabels()["host"]] = *f
+		}
+		return res
+	}
+
+	t.Run("applies the recovery threshold to loaded dimensions", func(t *testing.T) {
+		cmd, err := NewHysteresisCommand("B", "A",
+			ConditionEvalJSON{Type: ThresholdIsAbove, Params: []float64{80}},
+			ConditionEvalJSON{Type: ThresholdIsBelow, Params: []float64{70}},
+			[]data.Labels{{"host": "alerting-above-recovery"}, {"host": "alerting-below-recovery"}},
+		)
+		require.NoError(t, err)
+
+		results, err := cmd.Execute(context.Background(), time.Now(), vars)
+
+		require.NoError(t, err)
+		require.Equal(t, map[string]float64{
+			"alerting-above-recovery": 1,
+			"alerting-below-recovery": 0,
+			"normal-above-recovery":   0,
+			"normal-above-threshold":  1,
+		}, resultsByHost(t, results))
+		require.Len(t, vars["A"].Values, 4, "the input variables should not be modified")
+	})
+
+	t.Run("applies the threshold if there are no loaded dimensions", func(t *testing.T) {
+		cmd, err := NewHysteresisCommand("B", "A",
+			ConditionEvalJSON{Type: ThresholdIsAbove, Params: []float64{80}},
+			ConditionEvalJSON{Type: ThresholdIsBelow, Params: []float64{70}},
+			nil,
+		)
+		require.NoError(t, err)
+
+		results, err := cmd.Execute(context.Background(), time.Now(), vars)
+
+		require.NoError(t, err)
+		require.Equal(t, map[string]float64{
+			"alerting-above-recovery": 0,
+			"alerting-below-recovery": 0,
+			"normal-above-recovery":   0,
+			"normal-above-threshold":  1,
+		}, resultsByHost(t, results))
+	})
+
+	t.Run("applies the recovery threshold if all dimensions are loaded", func(t *testing.T) {
+		cmd, err := NewHysteresisCommand("B", "A",
+			ConditionEvalJSON{Type: ThresholdIsAbove, Params: []float64{80}},
+			ConditionEvalJSON{Type: ThresholdIsBelow, Params: []float64{70}},
+			[]data.Labels{
+				{"host": "alerting-above-recovery"},
+				{"host": "alerting-below-recovery"},
+				{"host": "normal-above-recovery"},
+				{"host": "normal-above-threshold"},
+			},
+		)
+		require.NoError(t, err)
+
+		results, err := cmd.Execute(context.Background(), time.Now(), vars)
+
+		require.NoError(t, err)
+		require.Equal(t, map[string]float64{
+			"alerting-above-recovery": 1,
+			"alerting-below-recovery": 0,
+			"normal-above-recovery":   1,
+			"normal-above-threshold":  1,
+		}, resultsByHost(t, results))
+	})
+}
+
+func queryMap(t *testing.T, query string) map[string]interface{} {
+	t.Helper()
+	var qmap = make(map[string]interface{})
+	require.NoError(t, json.Unmarshal([]byte(query), &qmap))
+	return qmap
+}
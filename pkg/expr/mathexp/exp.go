@@ -257,6 +257,14 @@ func (e *State) walkBinary(node *parse.BinaryNode) (Results, error) {
 	if err != nil {
 		return res, err
 	}
+	return e.biOp(node.OpStr, ar, br)
+}
+
+// biOp performs the binary operation op between the unions of the two results. It is
+// shared by binary operators and by functions that take two variant arguments.
+func (e *State) biOp(op string, ar, br Results) (Results, error) {
+	res := Results{Values{}}
+	var err error
 	unions := union(ar, br)
 	for _, uni := range unions {
 		var value Value
@@ -273,7 +281,7 @@ func (e *State) walkBinary(node *parse.BinaryNode) (Results, error) {
 				}
 				f := math.NaN()
 				if aFloat != nil && bFloat != nil {
-					f, err = binaryOp(node.OpStr, *aFloat, *bFloat)
+					f, err = binaryOp(op, *aFloat, *bFloat)
 					if err != nil {
 						return res, err
 					}
@@ -281,45 +289,45 @@ func (e *State) walkBinary(node *parse.BinaryNode) (Results, error) {
 				value = NewScalar(e.RefID, &f)
 			// Scalar op Scalar
 			case Number:
-				value, err = e.biScalarNumber(uni.Labels, node.OpStr, bt, aFloat, false)
+				value, err = e.biScalarNumber(uni.Labels, op, bt, aFloat, false)
 			// Scalar op Series
 			case Series:
-				value, err = e.biSeriesNumber(uni.Labels, node.OpStr, bt, aFloat, false)
+				value, err = e.biSeriesNumber(uni.Labels, op, bt, aFloat, false)
 			default:
-				return res, fmt.Errorf("not implemented: binary %v on %T and %T", node.OpStr, uni.A, uni.B)
+				return res, fmt.Errorf("not implemented: binary %v on %T and %T", op, uni.A, uni.B)
 			}
 		case Series:
 			switch bt := uni.B.(type) {
 			// Series Op Scalar
 			case Scalar:
 				bFloat := bt.GetFloat64Value()
-				value, err = e.biSeriesNumber(uni.Labels, node.OpStr, at, bFloat, true)
+				value, err = e.biSeriesNumber(uni.Labels, op, at, bFloat, true)
 			// case Series Op Number
 			case Number:
 				bFloat := bt.GetFloat64Value()
-				value, err = e.biSeriesNumber(uni.Labels, node.OpStr, at, bFloat, true)
+				value, err = e.biSeriesNumber(uni.Labels, op, at, bFloat, true)
 			// case Series op Series
 			case Series:
-				value, err = e.biSeriesSeries(uni.Labels, node.OpStr, at, bt)
+				value, err = e.biSeriesSeries(uni.Labels, op, at, bt)
 			default:
-				return res, fmt.Errorf("not implemented: binary %v on %T and %T", node.OpStr, uni.A, uni.B)
+				return res, fmt.Errorf("not implemented: binary %v on %T and %T", op, uni.A, uni.B)
 			}
 		case Number:
 			aFloat := at.GetFloat64Value()
 			switch bt := uni.B.(type) {
 			case Scalar:
 				bFloat := bt.GetFloat64Value()
-				value, err = e.biScalarNumber(uni.Labels, node.OpStr, at, bFloat, true)
+				value, err = e.biScalarNumber(uni.Labels, op, at, bFloat, true)
 			case Number:
 				bFloat := bt.GetFloat64Value()
-				value, err = e.biScalarNumber(uni.Labels, node.OpStr, at, bFloat, true)
+				value, err = e.biScalarNumber(uni.Labels, op, at, bFloat, true)
 			case Series:
-				value, err = e.biSeriesNumber(uni.Labels, node.OpStr, bt, aFloat, false)
+				value, err = e.biSeriesNumber(uni.Labels, op, bt, aFloat, false)
 			default:
-				return res, fmt.Errorf("not implemented: binary %v on %T and %T", node.OpStr, uni.A, uni.B)
+				return res, fmt.Errorf("not implemented: binary %v on %T and %T", op, uni.A, uni.B)
 			}
 		default:
-			return res, fmt.Errorf("not implemented: binary %v on %T and %T", node.OpStr, uni.A, uni.B)
+			return res, fmt.Errorf("not implemented: binary %v on %T and %T", op, uni.A, uni.B)
 		}
 		if err != nil {
 			return res, err
@@ -360,6 +368,11 @@ func binaryOp(op string, a, b float64) (r float64, err error) {
 		r = math.Pow(a, b)
 	case "%":
 		r = math.Mod(a, b)
+	// min and max are not operators of the grammar, they back the min() and max() functions.
+	case "min":
+		r = math.Min(a, b)
+	case "max":
+		r = math.Max(a, b)
 	case "==":
 		if a == b {
 			r = 1
@@ -1,7 +1,9 @@
 package mathexp
 
 import (
+	"fmt"
 	"math"
+	"time"
 
 	"github.com/grafana/grafana/pkg/expr/mathexp/parse"
 )
@@ -68,6 +70,51 @@ var builtins = map[string]parse.Func{
 		VariantReturn: true,
 		F:             floor,
 	},
+	"sqrt": {
+		Args:          []parse.ReturnType{parse.TypeVariantSet},
+		VariantReturn: true,
+		F:             sqrt,
+	},
+	"exp": {
+		Args:          []parse.ReturnType{parse.TypeVariantSet},
+		VariantReturn: true,
+		F:             exp,
+	},
+	"pow": {
+		Args:          []parse.ReturnType{parse.TypeVariantSet, parse.TypeVariantSet},
+		VariantReturn: true,
+		F:             pow,
+	},
+	"min": {
+		Args:          []parse.ReturnType{parse.TypeVariantSet, parse.TypeVariantSet},
+		VariantReturn: true,
+		F:             minimum,
+	},
+	"max": {
+		Args:          []parse.ReturnType{parse.TypeVariantSet, parse.TypeVariantSet},
+		VariantReturn: true,
+		F:             maximum,
+	},
+	"clamp": {
+		Args:          []parse.ReturnType{parse.TypeVariantSet, parse.TypeScalar, parse.TypeScalar},
+		VariantReturn: true,
+		F:             clamp,
+	},
+	"diff": {
+		Args:   []parse.ReturnType{parse.TypeSeriesSet},
+		Return: parse.TypeSeriesSet,
+		F:      diff,
+	},
+	"delta": {
+		Args:   []parse.ReturnType{parse.TypeSeriesSet},
+		Return: parse.TypeNumberSet,
+		F:      delta,
+	},
+	"rate": {
+		Args:   []parse.ReturnType{parse.TypeSeriesSet},
+		Return: parse.TypeNumberSet,
+		F:      rate,
+	},
 }
 
 // abs returns the absolute value for each result in NumberSet, SeriesSet, or Scalar
@@ -303,3 +350,196 @@ func floor(e *State, varSet Results) (Results, error) {
 	}
 	return newRes, nil
 }
+
+// sqrt returns the square root value for each result in NumberSet, SeriesSet, or Scalar
+func sqrt(e *State, varSet Results) (Results, error) {
+	newRes := Results{}
+	for _, res := range varSet.Values {
+		newVal, err := perFloat(e, res, math.Sqrt)
+		if err != nil {
+			return newRes, err
+		}
+		newRes.Values = append(newRes.Values, newVal)
+	}
+	return newRes, nil
+}
+
+// exp returns e**x for each result x in NumberSet, SeriesSet, or Scalar
+func exp(e *State, varSet Results) (Results, error) {
+	newRes := Results{}
+	for _, res := range varSet.Values {
+		newVal, err := perFloat(e, res, math.Exp)
+		if err != nil {
+			return newRes, err
+		}
+		newRes.Values = append(newRes.Values, newVal)
+	}
+	return newRes, nil
+}
+
+// pow returns base**exponent. The arguments are joined the same way as for binary operations,
+// so pow($A, 2) is equivalent to $A ** 2.
+func pow(e *State, base, exponent Results) (Results, error) {
+	return e.biOp("**", base, exponent)
+}
+
+// minimum returns the smaller value of the two arguments, which are joined the same way as for
+// binary operations. If either value is null then null is returned, and if either value is NaN then NaN is returned.
+func minimum(e *State, a, b Results) (Results, error) {
+	return e.biOp("min", a, b)
+}
+
+// maximum returns the larger value of the two arguments, which are joined the same way as for
+// binary operations. If either value is null then null is returned, and if either value is NaN then NaN is returned.
+func maximum(e *State, a, b Results) (Results, error) {
+	return e.biOp("max", a, b)
+}
+
+// clamp limits the value for each result in NumberSet, SeriesSet, or Scalar to the range [min, max].
+// Null values stay null and NaN values stay NaN.
+func clamp(e *State, varSet, minSet, maxSet Results) (Results, error) {
+	newRes := Results{}
+	minF, err := scalarArg("clamp", "min", minSet)
+	if err != nil {
+		return newRes, err
+	}
+	maxF, err := scalarArg("clamp", "max", maxSet)
+	if err != nil {
+		return newRes, err
+	}
+	if minF > maxF {
+		return newRes, fmt.Errorf("clamp: min (%v) must not be greater than max (%v)", minF, maxF)
+	}
+	for _, res := range varSet.Values {
+		newVal, err := perNullableFloat(e, res, func(f *float64) *float64 {
+			if f == nil || math.IsNaN(*f) {
+				return f
+			}
+			nF := math.Max(minF, math.Min(maxF, *f))
+			return &nF
+		})
+		if err != nil {
+			return newRes, err
+		}
+		newRes.Values = append(newRes.Values, newVal)
+	}
+	return newRes, nil
+}
+
+// scalarArg returns the value of a scalar argument of a function. Null is not a valid value.
+func scalarArg(funcName, argName string, res Results) (float64, error) {
+	if len(res.Values) != 1 {
+		return 0, fmt.Errorf("%s: expected a single scalar for argument %s", funcName, argName)
+	}
+	s, ok := res.Values[0].(Scalar)
+	if !ok {
+		return 0, fmt.Errorf("%s: expected a scalar for argument %s, got %v", funcName, argName, res.Values[0].Type())
+	}
+	f := s.GetFloat64Value()
+	if f == nil {
+		return 0, fmt.Errorf("%s: argument %s must not be null", funcName, argName)
+	}
+	return *f, nil
+}
+
+// diff returns, for each series in SeriesSet, a series of the differences between each point and
+// the point before it. The first point of each series is dropped. If either of the two points is null,
+// the resulting point is null.
+func diff(e *State, seriesSet Results) (Results, error) {
+	newRes := Results{}
+	for _, res := range seriesSet.Values {
+		series, ok, err := asSeries("diff", res)
+		if err != nil {
+			return newRes, err
+		}
+		if !ok {
+			newRes.Values = append(newRes.Values, res)
+			continue
+		}
+		newSeries := NewSeries(e.RefID, series.GetLabels(), 0)
+		for i := 1; i < series.Len(); i++ {
+			t, f := series.GetPoint(i)
+			_, prev := series.GetPoint(i - 1)
+			if f == nil || prev == nil {
+				newSeries.AppendPoint(t, nil)
+				continue
+			}
+			nF := *f - *prev
+			newSeries.AppendPoint(t, &nF)
+		}
+		newRes.Values = append(newRes.Values, newSeries)
+	}
+	return newRes, nil
+}
+
+// delta returns, for each series in SeriesSet, a number that is the difference between the last
+// and the first non-null values of the series. If the series has less than two non-null values, null is returned.
+func delta(e *State, seriesSet Results) (Results, error) {
+	return perSeriesSpan(e, "delta", seriesSet, func(first, last float64, _ time.Duration) *float64 {
+		nF := last - first
+		return &nF
+	})
+}
+
+// rate returns, for each series in SeriesSet, a number that is the per-second rate of change
+// between the first and the last non-null values of the series. If the series has less than two non-null values,
+// or if both values have the same time, null is returned.
+func rate(e *State, seriesSet Results) (Results, error) {
+	return perSeriesSpan(e, "rate", seriesSet, func(first, last float64, d time.Duration) *float64 {
+		if d == 0 {
+			return nil
+		}
+		nF := (last - first) / d.Seconds()
+		return &nF
+	})
+}
+
+// perSeriesSpan passes the first and last non-null values of each series, and the time between them,
+// to spanF and returns the result as a Number with the labels of the series.
+func perSeriesSpan(e *State, funcName string, seriesSet Results, spanF func(first, last float64, d time.Duration) *float64) (Results, error) {
+	newRes := Results{}
+	for _, res := range seriesSet.Values {
+		series, ok, err := asSeries(funcName, res)
+		if err != nil {
+			return newRes, err
+		}
+		if !ok {
+			newRes.Values = append(newRes.Values, res)
+			continue
+		}
+		n := NewNumber(e.RefID, series.GetLabels())
+		first, last := -1, -1
+		for i := 0; i < series.Len(); i++ {
+			if series.GetValue(i) == nil {
+				continue
+			}
+			if first == -1 {
+				first = i
+			}
+			last = i
+		}
+		if first == last {
+			n.SetValue(nil)
+			newRes.Values = append(newRes.Values, n)
+			continue
+		}
+		firstT, firstF := series.GetPoint(first)
+		lastT, lastF := series.GetPoint(last)
+		n.SetValue(spanF(*firstF, *lastF, lastT.Sub(firstT)))
+		newRes.Values = append(newRes.Values, n)
+	}
+	return newRes, nil
+}
+
+// asSeries returns the value as a Series. If the value is NoData, false is returned
+// so that it can be passed through. Any other type is an error.
+func asSeries(funcName string, val Value) (Series, bool, error) {
+	switch v := val.(type) {
+	case Series:
+		return v, true, nil
+	case NoData:
+		return Series{}, false, nil
+	default:
+		return Series{}, false, fmt.Errorf("%s: expected a series, got %v", funcName, val.Type())
+	}
+}
@@ -5,7 +5,11 @@ import (
 	"testing"
 	"time"
 
+	"github.com/google/go-cmp/cmp"
+	"github.com/grafana/grafana-plugin-sdk-go/data"
 	"github.com/stretchr/testify/require"
+
+	"github.com/grafana/grafana/pkg/expr/mathexp/parse"
 )
 
 func TestAbsFunc(t *testing.T) {
@@ -159,3 +163,256 @@ func TestIsNumberFunc(t *testing.T) {
 		})
 	}
 }
+
+func TestMathFuncs(t *testing.T) {
+	var tests = []struct {
+		name     string
+		expr     string
+		vars     Vars
+		newErrIs require.ErrorAssertionFunc
+		results  Results
+	}{
+		{
+			name:     "sqrt on scalar",
+			expr:     "sqrt(16)",
+			vars:     Vars{},
+			newErrIs: require.NoError,
+			results:  Results{[]Value{NewScalar("", float64Pointer(4))}},
+		},
+		{
+			name: "sqrt on number with null value",
+			expr: "sqrt($A)",
+			vars: Vars{
+				"A": Results{[]Value{makeNumber("", nil, nil)}},
+			},
+			newErrIs: require.NoError,
+			results:  Results{[]Value{makeNumber("", nil, NaN)}},
+		},
+		{
+			name: "exp on series",
+			expr: "exp($A)",
+			vars: Vars{
+				"A": Results{[]Value{
+					makeSeries("", nil, tp{time.Unix(5, 0), float64Pointer(0)}, tp{time.Unix(10, 0), float64Pointer(1)}),
+				}},
+			},
+			newErrIs: require.NoError,
+			results: Results{[]Value{
+				makeSeries("", nil, tp{time.Unix(5, 0), float64Pointer(1)}, tp{time.Unix(10, 0), float64Pointer(math.E)}),
+			}},
+		},
+		{
+			name: "pow on number and scalar",
+			expr: "pow($A, 2)",
+			vars: Vars{
+				"A": Results{[]Value{makeNumber("", data.Labels{"host": "a"}, float64Pointer(3))}},
+			},
+			newErrIs: require.NoError,
+			results:  Results{[]Value{makeNumber("", data.Labels{"host": "a"}, float64Pointer(9))}},
+		},
+		{
+			name: "pow on scalar and series returns a series",
+			expr: "pow(2, $A)",
+			vars: Vars{
+				"A": Results{[]Value{
+					makeSeries("", nil, tp{time.Unix(5, 0), float64Pointer(3)}, tp{time.Unix(10, 0), nil}),
+				}},
+			},
+			newErrIs: require.NoError,
+			results: Results{[]Value{
+				makeSeries("", nil, tp{time.Unix(5, 0), float64Pointer(8)}, tp{time.Unix(10, 0), nil}),
+			}},
+		},
+		{
+			name: "min on numbers joins on labels",
+			expr: "min($A, $B)",
+			vars: Vars{
+				"A": Results{[]Value{
+					makeNumber("", data.Labels{"host": "a"}, float64Pointer(1)),
+					makeNumber("", data.Labels{"host": "b"}, float64Pointer(5)),
+				}},
+				"B": Results{[]Value{
+					makeNumber("", data.Labels{"host": "a"}, float64Pointer(2)),
+					makeNumber("", data.Labels{"host": "b"}, float64Pointer(4)),
+				}},
+			},
+			newErrIs: require.NoError,
+			results: Results{[]Value{
+				makeNumber("", data.Labels{"host": "a"}, float64Pointer(1)),
+				makeNumber("", data.Labels{"host": "b"}, float64Pointer(4)),
+			}},
+		},
+		{
+			name: "max on series and scalar",
+			expr: "max($A, 2)",
+			vars: Vars{
+				"A": Results{[]Value{
+					makeSeries("", nil,
+						tp{time.Unix(5, 0), float64Pointer(1)},
+						tp{time.Unix(10, 0), float64Pointer(3)},
+						tp{time.Unix(15, 0), nil},
+						tp{time.Unix(20, 0), NaN}),
+				}},
+			},
+			newErrIs: require.NoError,
+			results: Results{[]Value{
+				makeSeries("", nil,
+					tp{time.Unix(5, 0), float64Pointer(2)},
+					tp{time.Unix(10, 0), float64Pointer(3)},
+					tp{time.Unix(15, 0), nil},
+					tp{time.Unix(20, 0), NaN}),
+			}},
+		},
+		{
+			name:     "max requires two arguments",
+			expr:     "max(1)",
+			vars:     Vars{},
+			newErrIs: require.Error,
+		},
+		{
+			name: "clamp on series keeps null and NaN",
+			expr: "clamp($A, -1, 1)",
+			vars: Vars{
+				"A": Results{[]Value{
+					makeSeries("", nil,
+						tp{time.Unix(5, 0), float64Pointer(-5)},
+						tp{time.Unix(10, 0), float64Pointer(0.5)},
+						tp{time.Unix(15, 0), float64Pointer(5)},
+						tp{time.Unix(20, 0), nil},
+						tp{time.Unix(25, 0), NaN}),
+				}},
+			},
+			newErrIs: require.NoError,
+			results: Results{[]Value{
+				makeSeries("", nil,
+					tp{time.Unix(5, 0), float64Pointer(-1)},
+					tp{time.Unix(10, 0), float64Pointer(0.5)},
+					tp{time.Unix(15, 0), float64Pointer(1)},
+					tp{time.Unix(20, 0), nil},
+					tp{time.Unix(25, 0), NaN}),
+			}},
+		},
+		{
+			name: "clamp on number",
+			expr: "clamp($A, 0, 10)",
+			vars: Vars{
+				"A": Results{[]Value{makeNumber("", nil, float64Pointer(11))}},
+			},
+			newErrIs: require.NoError,
+			results:  Results{[]Value{makeNumber("", nil, float64Pointer(10))}},
+		},
+		{
+			name:     "clamp requires scalar bounds",
+			expr:     "clamp($A, $B, 1)",
+			vars:     Vars{},
+			newErrIs: require.Error,
+		},
+		{
+			name: "diff on series",
+			expr: "diff($A)",
+			vars: Vars{
+				"A": Results{[]Value{
+					makeSeries("", data.Labels{"host": "a"},
+						tp{time.Unix(5, 0), float64Pointer(1)},
+						tp{time.Unix(10, 0), float64Pointer(4)},
+						tp{time.Unix(15, 0), nil},
+						tp{time.Unix(20, 0), float64Pointer(2)},
+						tp{time.Unix(25, 0), float64Pointer(1)}),
+				}},
+			},
+			newErrIs: require.NoError,
+			results: Results{[]Value{
+				makeSeries("", data.Labels{"host": "a"},
+					tp{time.Unix(10, 0), float64Pointer(3)},
+					tp{time.Unix(15, 0), nil},
+					tp{time.Unix(20, 0), nil},
+					tp{time.Unix(25, 0), float64Pointer(-1)}),
+			}},
+		},
+		{
+			name: "delta on series uses first and last non-null values",
+			expr: "delta($A)",
+			vars: Vars{
+				"A": Results{[]Value{
+					makeSeries("", data.Labels{"host": "a"},
+						tp{time.Unix(5, 0), nil},
+						tp{time.Unix(10, 0), float64Pointer(4)},
+						tp{time.Unix(15, 0), float64Pointer(6)},
+						tp{time.Unix(20, 0), float64Pointer(10)},
+						tp{time.Unix(25, 0), nil}),
+				}},
+			},
+			newErrIs: require.NoError,
+			results:  Results{[]Value{makeNumber("", data.Labels{"host": "a"}, float64Pointer(6))}},
+		},
+		{
+			name: "rate on series",
+			expr: "rate($A)",
+			vars: Vars{
+				"A": Results{[]Value{
+					makeSeries("", nil,
+						tp{time.Unix(0, 0), float64Pointer(10)},
+						tp{time.Unix(10, 0), float64Pointer(20)},
+						tp{time.Unix(20, 0), float64Pointer(40)}),
+				}},
+			},
+			newErrIs: require.NoError,
+			results:  Results{[]Value{makeNumber("", nil, float64Pointer(1.5))}},
+		},
+		{
+			name: "rate on series with a single value is null",
+			expr: "rate($A)",
+			vars: Vars{
+				"A": Results{[]Value{
+					makeSeries("", nil, tp{time.Unix(0, 0), float64Pointer(10)}, tp{time.Unix(10, 0), nil}),
+				}},
+			},
+			newErrIs: require.NoError,
+			results:  Results{[]Value{makeNumber("", nil, nil)}},
+		},
+		{
+			name:     "rate on scalar - should error",
+			expr:     "rate(1)",
+			vars:     Vars{},
+			newErrIs: require.Error,
+		},
+	}
+	opt := cmp.Comparer(func(x, y float64) bool {
+		return (math.IsNaN(x) && math.IsNaN(y)) || x == y
+	})
+	options := append([]cmp.Option{opt}, data.FrameTestCompareOptions()...)
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			e, err := New(tt.expr)
+			tt.newErrIs(t, err)
+			if e != nil {
+				res, err := e.Execute("", tt.vars)
+				require.NoError(t, err)
+				if diff := cmp.Diff(tt.results, res, options...); diff != "" {
+					t.Errorf("Result mismatch (-want +got):\n%s", diff)
+				}
+			}
+		})
+	}
+}
+
+func TestFuncsOnNonSeries(t *testing.T) {
+	e, err := New("diff($A)")
+	require.NoError(t, err)
+
+	_, err = e.Execute("", Vars{"A": Results{[]Value{makeNumber("", nil, float64Pointer(1))}}})
+	require.Error(t, err)
+
+	res, err := e.Execute("", Vars{"A": Results{[]Value{NoData{}.New()}}})
+	require.NoError(t, err)
+	require.Equal(t, parse.TypeNoData, res.Values[0].Type())
+}
+
+func TestClampBounds(t *testing.T) {
+	e, err := New("clamp(1, 2, 0)")
+	require.NoError(t, err)
+
+	_, err = e.Execute("", Vars{})
+	require.Error(t, err)
+}
@@ -320,8 +320,12 @@ func (t *Tree) Func() (f *FuncNode) {
 			t.backup()
 			node := t.O()
 			f.append(node)
-			if len(f.Args) == 1 && f.F.VariantReturn {
-				f.F.Return = node.Return()
+			if f.F.VariantReturn && isVariantArg(f.F, len(f.Args)-1) {
+				// The return type of a function that takes several variant arguments
+				// is the widest type among them, the same as for binary operations.
+				if rt := node.Return(); len(f.Args) == 1 || rt > f.F.Return {
+					f.F.Return = rt
+				}
 			}
 		case itemString:
 			s, err := strconv.Unquote(token.val)
@@ -332,9 +336,22 @@ func (t *Tree) Func() (f *FuncNode) {
 		case itemRightParen:
 			return
 		}
+		switch token = t.next(); token.typ {
+		case itemComma:
+			// continue with the next param
+		case itemRightParen:
+			return
+		default:
+			t.unexpected(token, "func")
+		}
 	}
 }
 
+// isVariantArg returns true if the argument of f at index i is a TypeVariantSet.
+func isVariantArg(f *Func, i int) bool {
+	return i < len(f.Args) && f.Args[i] == TypeVariantSet
+}
+
 // GetFunction gets a parsed Func from the functions available on the tree's func property.
 func (t *Tree) GetFunction(name string) (v Func, ok bool) {
 	for _, funcMap := range t.funcs {
@@ -3,6 +3,9 @@ package mathexp
 import (
 	"fmt"
 	"math"
+	"regexp"
+	"sort"
+	"strconv"
 	"strings"
 
 	"github.com/grafana/grafana-plugin-sdk-go/data"
@@ -81,6 +84,113 @@ func Last(fv *Float64Field) *float64 {
 	return fv.GetValue(fv.Len() - 1)
 }
 
+func First(fv *Float64Field) *float64 {
+	var f float64
+	if fv.Len() == 0 {
+		f = math.NaN()
+		return &f
+	}
+	return fv.GetValue(0)
+}
+
+// Diff returns the difference between the last and the first value.
+func Diff(fv *Float64Field) *float64 {
+	first, last := First(fv), Last(fv)
+	if first == nil || last == nil {
+		nan := math.NaN()
+		return &nan
+	}
+	f := *last - *first
+	return &f
+}
+
+// Range returns the difference between the largest and the smallest value.
+func Range(fv *Float64Field) *float64 {
+	f := *Max(fv) - *Min(fv)
+	return &f
+}
+
+// Stddev returns the population standard deviation of the values.
+func Stddev(fv *Float64Field) *float64 {
+	avg := Avg(fv)
+	// Avg is NaN if there are no values or if any value is null or NaN.
+	if math.IsNaN(*avg) {
+		return avg
+	}
+	var sum float64
+	for i := 0; i < fv.Len(); i++ {
+		sum += math.Pow(*fv.GetValue(i)-*avg, 2)
+	}
+	f := math.Sqrt(sum / float64(fv.Len()))
+	return &f
+}
+
+// CountNonNull returns the number of values that are neither null nor NaN.
+func CountNonNull(fv *Float64Field) *float64 {
+	var f float64
+	for i := 0; i < fv.Len(); i++ {
+		v := fv.GetValue(i)
+		if v != nil && !math.IsNaN(*v) {
+			f++
+		}
+	}
+	return &f
+}
+
+func Median(fv *Float64Field) *float64 {
+	return Percentile(50)(fv)
+}
+
+// Percentile returns a ReducerFunc that calculates the p-th percentile of the values,
+// interpolating linearly between the two closest ranks. p must be in the range [0, 100].
+func Percentile(p float64) ReducerFunc {
+	return func(fv *Float64Field) *float64 {
+		nan := math.NaN()
+		if fv.Len() == 0 {
+			return &nan
+		}
+		values := make([]float64, 0, fv.Len())
+		for i := 0; i < fv.Len(); i++ {
+			v := fv.GetValue(i)
+			if v == nil || math.IsNaN(*v) {
+				return &nan
+			}
+			values = append(values, *v)
+		}
+		f := PercentileOf(values, p)
+		return &f
+	}
+}
+
+// PercentileOf calculates the p-th percentile of the values, interpolating linearly between the two closest ranks.
+// The values must not be empty and are sorted in place.
+func PercentileOf(values []float64, p float64) float64 {
+	sort.Float64s(values)
+	rank := p / 100 * float64(len(values)-1)
+	lower := math.Floor(rank)
+	f := values[int(lower)]
+	if upper := math.Ceil(rank); upper != lower {
+		f += (values[int(upper)] - f) * (rank - lower)
+	}
+	return f
+}
+
+var percentileRegexp = regexp.MustCompile(`^[pP](\d+(?:\.\d+)?)$`)
+
+// ParsePercentile parses reducers in the form pNN, e.g. p95 or p99.9, and returns the percentile. Like the names of
+// the other reducers, the name is case-insensitive.
+func ParsePercentile(rFunc string) (float64, bool) {
+	m := percentileRegexp.FindStringSubmatch(rFunc)
+	if m == nil {
+		return 0, false
+	}
+	p, err := strconv.ParseFloat(m[1], 64)
+	if err != nil || p > 100 {
+		return 0, false
+	}
+	return p, true
+}
+
 func GetReduceFunc(rFunc string) (ReducerFunc, error) {
 	switch strings.ToLower(rFunc) {
 	case "sum":
@@ -95,14 +205,30 @@ func GetReduceFunc(rFunc string) (ReducerFunc, error) {
 		return Count, nil
 	case "last":
 		return Last, nil
+	case "first":
+		return First, nil
+	case "median":
+		return Median, nil
+	case "stddev":
+		return Stddev, nil
+	case "diff":
+		return Diff, nil
+	case "range":
+		return Range, nil
+	case "count_non_null":
+		return CountNonNull, nil
 	default:
+		if p, ok := ParsePercentile(rFunc); ok {
+			return Percentile(p), nil
+		}
 		return nil, fmt.Errorf("reduction %v not implemented", rFunc)
 	}
 }
 
-// GetSupportedReduceFuncs returns collection of supported function names
+// GetSupportedReduceFuncs returns collection of supported function names.
+// In addition to these, percentiles are supported in the form pNN, e.g. p95 or p99.9.
 func GetSupportedReduceFuncs() []string {
-	return []string{"sum", "mean", "min", "max", "count", "last"}
+	return []string{"sum", "mean", "min", "max", "count", "last", "first", "median", "stddev", "diff", "range", "count_non_null"}
 }
 
 // Reduce turns the Series into a Number based on the given reduction function
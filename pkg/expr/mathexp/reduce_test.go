@@ -31,6 +31,19 @@ var seriesEmpty = Vars{
 	},
 }
 
+var seriesFiveValues = Vars{
+	"A": Results{
+		[]Value{
+			makeSeries("temp", nil,
+				tp{time.Unix(5, 0), float64Pointer(4)},
+				tp{time.Unix(10, 0), float64Pointer(1)},
+				tp{time.Unix(15, 0), float64Pointer(3)},
+				tp{time.Unix(20, 0), float64Pointer(5)},
+				tp{time.Unix(25, 0), float64Pointer(2)}),
+		},
+	},
+}
+
 func TestSeriesReduce(t *testing.T) {
 	var tests = []struct {
 		name        string
@@ -241,6 +254,287 @@ func TestSeriesReduce(t *testing.T) {
 				},
 			},
 		},
+		{
+			name:        "p101 reduction will error",
+			red:         "p101",
+			varToReduce: "A",
+			vars:        aSeries,
+			errIs:       require.Error,
+			resultsIs:   require.Equal,
+		},
+		{
+			name:        "median series",
+			red:         "median",
+			varToReduce: "A",
+			vars:        seriesFiveValues,
+			errIs:       require.NoError,
+			resultsIs:   require.Equal,
+			results: Results{
+				[]Value{
+					makeNumber("", nil, float64Pointer(3)),
+				},
+			},
+		},
+		{
+			name:        "median series with even number of values",
+			red:         "median",
+			varToReduce: "A",
+			vars:        aSeries,
+			errIs:       require.NoError,
+			resultsIs:   require.Equal,
+			results: Results{
+				[]Value{
+					makeNumber("", nil, float64Pointer(1.5)),
+				},
+			},
+		},
+		{
+			name:        "median series with a nil value",
+			red:         "median",
+			varToReduce: "A",
+			vars:        seriesWithNil,
+			errIs:       require.NoError,
+			resultsIs:   require.Equal,
+			results: Results{
+				[]Value{
+					makeNumber("", nil, NaN),
+				},
+			},
+		},
+		{
+			name:        "median empty series",
+			red:         "median",
+			varToReduce: "A",
+			vars:        seriesEmpty,
+			errIs:       require.NoError,
+			resultsIs:   require.Equal,
+			results: Results{
+				[]Value{
+					makeNumber("", nil, NaN),
+				},
+			},
+		},
+		{
+			name:        "p90 series",
+			red:         "p90",
+			varToReduce: "A",
+			vars:        seriesFiveValues,
+			errIs:       require.NoError,
+			resultsIs:   require.Equal,
+			results: Results{
+				[]Value{
+					makeNumber("", nil, float64Pointer(4.6)),
+				},
+			},
+		},
+		{
+			name:        "p0 series",
+			red:         "p0",
+			varToReduce: "A",
+			vars:        seriesFiveValues,
+			errIs:       require.NoError,
+			resultsIs:   require.Equal,
+			results: Results{
+				[]Value{
+					makeNumber("", nil, float64Pointer(1)),
+				},
+			},
+		},
+		{
+			name:        "p100 series",
+			red:         "p100",
+			varToReduce: "A",
+			vars:        seriesFiveValues,
+			errIs:       require.NoError,
+			resultsIs:   require.Equal,
+			results: Results{
+				[]Value{
+					makeNumber("", nil, float64Pointer(5)),
+				},
+			},
+		},
+		{
+			name:        "p99.9 series with a nil value",
+			red:         "p99.9",
+			varToReduce: "A",
+			vars:        seriesWithNil,
+			errIs:       require.NoError,
+			resultsIs:   require.Equal,
+			results: Results{
+				[]Value{
+					makeNumber("", nil, NaN),
+				},
+			},
+		},
+		{
+			name:        "stddev series",
+			red:         "stddev",
+			varToReduce: "A",
+			vars:        seriesFiveValues,
+			errIs:       require.NoError,
+			resultsIs:   require.Equal,
+			results: Results{
+				[]Value{
+					makeNumber("", nil, float64Pointer(math.Sqrt2)),
+				},
+			},
+		},
+		{
+			name:        "stddev series with a nil value",
+			red:         "stddev",
+			varToReduce: "A",
+			vars:        seriesWithNil,
+			errIs:       require.NoError,
+			resultsIs:   require.Equal,
+			results: Results{
+				[]Value{
+					makeNumber("", nil, NaN),
+				},
+			},
+		},
+		{
+			name:        "stddev empty series",
+			red:         "stddev",
+			varToReduce: "A",
+			vars:        seriesEmpty,
+			errIs:       require.NoError,
+			resultsIs:   require.Equal,
+			results: Results{
+				[]Value{
+					makeNumber("", nil, NaN),
+				},
+			},
+		},
+		{
+			name:        "first series",
+			red:         "first",
+			varToReduce: "A",
+			vars:        seriesFiveValues,
+			errIs:       require.NoError,
+			resultsIs:   require.Equal,
+			results: Results{
+				[]Value{
+					makeNumber("", nil, float64Pointer(4)),
+				},
+			},
+		},
+		{
+			name:        "first empty series",
+			red:         "first",
+			varToReduce: "A",
+			vars:        seriesEmpty,
+			errIs:       require.NoError,
+			resultsIs:   require.Equal,
+			results: Results{
+				[]Value{
+					makeNumber("", nil, NaN),
+				},
+			},
+		},
+		{
+			name:        "diff series",
+			red:         "diff",
+			varToReduce: "A",
+			vars:        seriesFiveValues,
+			errIs:       require.NoError,
+			resultsIs:   require.Equal,
+			results: Results{
+				[]Value{
+					makeNumber("", nil, float64Pointer(-2)),
+				},
+			},
+		},
+		{
+			name:        "diff series with a nil value",
+			red:         "diff",
+			varToReduce: "A",
+			vars:        seriesWithNil,
+			errIs:       require.NoError,
+			resultsIs:   require.Equal,
+			results: Results{
+				[]Value{
+					makeNumber("", nil, NaN),
+				},
+			},
+		},
+		{
+			name:        "diff empty series",
+			red:         "diff",
+			varToReduce: "A",
+			vars:        seriesEmpty,
+			errIs:       require.NoError,
+			resultsIs:   require.Equal,
+			results: Results{
+				[]Value{
+					makeNumber("", nil, NaN),
+				},
+			},
+		},
+		{
+			name:        "range series",
+			red:         "range",
+			varToReduce: "A",
+			vars:        seriesFiveValues,
+			errIs:       require.NoError,
+			resultsIs:   require.Equal,
+			results: Results{
+				[]Value{
+					makeNumber("", nil, float64Pointer(4)),
+				},
+			},
+		},
+		{
+			name:        "range series with a nil value",
+			red:         "range",
+			varToReduce: "A",
+			vars:        seriesWithNil,
+			errIs:       require.NoError,
+			resultsIs:   require.Equal,
+			results: Results{
+				[]Value{
+					makeNumber("", nil, NaN),
+				},
+			},
+		},
+		{
+			name:        "count_non_null series with a nil value",
+			red:         "count_non_null",
+			varToReduce: "A",
+			vars:        seriesWithNil,
+			errIs:       require.NoError,
+			resultsIs:   require.Equal,
+			results: Results{
+				[]Value{
+					makeNumber("", nil, float64Pointer(1)),
+				},
+			},
+		},
+		{
+			name:        "count_non_null series of non-numbers",
+			red:         "count_non_null",
+			varToReduce: "A",
+			vars:        seriesNonNumbers,
+			errIs:       require.NoError,
+			resultsIs:   require.Equal,
+			results: Results{
+				[]Value{
+					makeNumber("", nil, float64Pointer(2)),
+				},
+			},
+		},
+		{
+			name:        "count_non_null empty series",
+			red:         "count_non_null",
+			varToReduce: "A",
+			vars:        seriesEmpty,
+			errIs:       require.NoError,
+			resultsIs:   require.Equal,
+			results: Results{
+				[]Value{
+					makeNumber("", nil, float64Pointer(0)),
+				},
+			},
+		},
 	}
 
 	for _, tt := range tests {
@@ -374,6 +668,72 @@ func TestSeriesReduceDropNN(t *testing.T) {
 				},
 			},
 		},
+		{
+			name:        "DropNN: median series with a nil value",
+			red:         "median",
+			varToReduce: "A",
+			vars:        seriesWithNil,
+			results: Results{
+				[]Value{
+					makeNumber("", nil, float64Pointer(2)),
+				},
+			},
+		},
+		{
+			name:        "DropNN: p95 series that becomes empty after filtering non-number",
+			red:         "p95",
+			varToReduce: "A",
+			vars:        seriesNonNumbers,
+			results: Results{
+				[]Value{
+					makeNumber("", nil, nil),
+				},
+			},
+		},
+		{
+			name:        "DropNN: stddev series with a nil value",
+			red:         "stddev",
+			varToReduce: "A",
+			vars:        seriesWithNil,
+			results: Results{
+				[]Value{
+					makeNumber("", nil, float64Pointer(0)),
+				},
+			},
+		},
+		{
+			name:        "DropNN: diff series that becomes empty after filtering non-number",
+			red:         "diff",
+			varToReduce: "A",
+			vars:        seriesNonNumbers,
+			results: Results{
+				[]Value{
+					makeNumber("", nil, nil),
+				},
+			},
+		},
+		{
+			name:        "DropNN: range series with a nil value",
+			red:         "range",
+			varToReduce: "A",
+			vars:        seriesWithNil,
+			results: Results{
+				[]Value{
+					makeNumber("", nil, float64Pointer(0)),
+				},
+			},
+		},
+		{
+			name:        "DropNN: count_non_null series of non-numbers",
+			red:         "count_non_null",
+			varToReduce: "A",
+			vars:        seriesNonNumbers,
+			results: Results{
+				[]Value{
+					makeNumber("", nil, float64Pointer(0)),
+				},
+			},
+		},
 	}
 
 	for _, tt := range tests {
@@ -493,6 +853,61 @@ func TestSeriesReduceReplaceNN(t *testing.T) {
 				},
 			},
 		},
+		{
+			name:        "replaceNN: median series with a nil value",
+			red:         "median",
+			varToReduce: "A",
+			vars:        seriesWithNil,
+			results: Results{
+				[]Value{
+					makeNumber("", nil, float64Pointer((2+replaceWith)/2e0)),
+				},
+			},
+		},
+		{
+			name:        "replaceNN: first series that becomes empty after filtering non-number",
+			red:         "first",
+			varToReduce: "A",
+			vars:        seriesNonNumbers,
+			results: Results{
+				[]Value{
+					makeNumber("", nil, float64Pointer(replaceWith)),
+				},
+			},
+		},
+		{
+			name:        "replaceNN: diff series with a nil value",
+			red:         "diff",
+			varToReduce: "A",
+			vars:        seriesWithNil,
+			results: Results{
+				[]Value{
+					makeNumber("", nil, float64Pointer(replaceWith-2)),
+				},
+			},
+		},
+		{
+			name:        "replaceNN: range empty series",
+			red:         "range",
+			varToReduce: "A",
+			vars:        seriesEmpty,
+			results: Results{
+				[]Value{
+					makeNumber("", nil, float64Pointer(replaceWith)),
+				},
+			},
+		},
+		{
+			name:        "replaceNN: count_non_null series with nil and value should count replaced values",
+			red:         "count_non_null",
+			varToReduce: "A",
+			vars:        seriesWithNil,
+			results: Results{
+				[]Value{
+					makeNumber("", nil, float64Pointer(2)),
+				},
+			},
+		},
 	}
 
 	for _, tt := range tests {
@@ -514,3 +929,17 @@ func TestSeriesReduceReplaceNN(t *testing.T) {
 		})
 	}
 }
+
+func TestParsePercentile(t *testing.T) {
+	for name, expected := range map[string]float64{"p0": 0, "p95": 95, "P95": 95, "p99.9": 99.9, "p100": 100} {
+		p, ok := ParsePercentile(name)
+		require.Truef(t, ok, "%s should be valid", name)
+		require.Equal(t, expected, p)
+	}
+	for _, name := range []string{"p", "p101", "p-1", "pNaN", "p1e1", "p+50", "p0x32", "p95.", "p.5", "q95"} {
+		_, ok := ParsePercentile(name)
+		require.Falsef(t, ok, "%s should be invalid", name)
+		_, err := GetReduceFunc(name)
+		require.Errorf(t, err, "%s should be invalid", name)
+	}
+}
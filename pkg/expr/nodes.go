@@ -229,7 +229,7 @@ func (dn *DSNode) Execute(ctx context.Context, now time.Time, _ mathexp.Vars, s
 		Headers: dn.request.Headers,
 	}
 
-	resp, err := s.dataService.QueryData(ctx, req)
+	resp, err := s.queryDataSource(ctx, dn.datasource, req)
 	if err != nil {
 		return mathexp.Results{}, err
 	}
@@ -362,39 +362,60 @@ func isNumberTable(frame *data.Frame) bool {
 	return numericCount == 1 && otherCount == 0
 }
 
+// extractNumberSet converts a table with one numeric column and zero or more string columns, for example
+// the result of a SQL query with a GROUP BY clause, to a set of numbers. A number is created for each row
+// of the table with the string columns as labels. A null in a string column results in the label being
+// omitted, and a null in the numeric column results in a number without value.
 func extractNumberSet(frame *data.Frame) ([]mathexp.Number, error) {
 	numericField := 0
 	stringFieldIdxs := []int{}
 	stringFieldNames := []string{}
+	seenNames := make(map[string]struct{})
 	for i, field := range frame.Fields {
 		fType := field.Type()
 		switch {
 		case fType.Numeric():
 			numericField = i
 		case fType == data.FieldTypeString || fType == data.FieldTypeNullableString:
+			if _, ok := seenNames[field.Name]; ok {
+				return nil, fmt.Errorf("failed to convert table to numbers: duplicate string column %q", field.Name)
+			}
+			seenNames[field.Name] = struct{}{}
 			stringFieldIdxs = append(stringFieldIdxs, i)
 			stringFieldNames = append(stringFieldNames, field.Name)
 		}
 	}
 	numbers := make([]mathexp.Number, frame.Rows())
+	seenLabels := make(map[string]int, frame.Rows())
 
 	for rowIdx := 0; rowIdx < frame.Rows(); rowIdx++ {
-		val, _ := frame.FloatAt(numericField, rowIdx)
+		val, err := frame.Fields[numericField].NullableFloatAt(rowIdx)
+		if err != nil {
+			return nil, fmt.Errorf("failed to read value of row %d as float: %w", rowIdx, err)
+		}
 		var labels data.Labels
 		for i := 0; i < len(stringFieldIdxs); i++ {
 			if i == 0 {
 				labels = make(data.Labels)
 			}
-			key := stringFieldNames[i] // TODO check for duplicate string column names
-			val, _ := frame.ConcreteAt(stringFieldIdxs[i], rowIdx)
-			labels[key] = val.(string) // TODO check assertion / return error
+			v, ok := frame.ConcreteAt(stringFieldIdxs[i], rowIdx)
+			if !ok {
+				continue
+			}
+			labels[stringFieldNames[i]] = v.(string)
+		}
+
+		key := labels.String()
+		if prevIdx, ok := seenLabels[key]; ok {
+			return nil, fmt.Errorf("failed to convert table to numbers: rows %d and %d have the same labels {%s}", prevIdx, rowIdx, key)
 		}
+		seenLabels[key] = rowIdx
 
 		n := mathexp.NewNumber(frame.Fields[numericField].Name, labels)
 
 		// The new value fields' configs gets pointed to the one in the original frame
 		n.Frame.Fields[0].Config = frame.Fields[numericField].Config
-		n.SetValue(&val)
+		n.SetValue(val)
 
 		numbers[rowIdx] = n
 	}
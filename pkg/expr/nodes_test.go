@@ -3,8 +3,11 @@ package expr
 import (
 	"errors"
 	"testing"
+	"time"
 
+	"github.com/grafana/grafana-plugin-sdk-go/data"
 	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
 )
 
 type expectedError struct{}
@@ -40,3 +43,98 @@ func TestQueryError_Unwrap(t *testing.T) {
 		assert.True(t, errors.As(e, &expectedAsError))
 	})
 }
+
+func TestIsNumberTable(t *testing.T) {
+	tests := []struct {
+		name     string
+		frame    *data.Frame
+		expected bool
+	}{{
+		name: "string columns and one numeric column",
+		frame: data.NewFrame("",
+			data.NewField("host", nil, []string{"a", "b"}),
+			data.NewField("region", nil, []*string{strp("eu"), nil}),
+			data.NewField("count", nil, []*int64{i64p(1), i64p(2)})),
+		expected: true,
+	}, {
+		name: "one numeric column",
+		frame: data.NewFrame("",
+			data.NewField("count", nil, []int64{1})),
+		expected: true,
+	}, {
+		name: "more than one numeric column",
+		frame: data.NewFrame("",
+			data.NewField("host", nil, []string{"a"}),
+			data.NewField("count", nil, []int64{1}),
+			data.NewField("avg", nil, []float64{1})),
+		expected: false,
+	}, {
+		name: "no numeric column",
+		frame: data.NewFrame("",
+			data.NewField("host", nil, []string{"a"})),
+		expected: false,
+	}, {
+		name: "time column",
+		frame: data.NewFrame("",
+			data.NewField("time", nil, []time.Time{time.Unix(1, 0)}),
+			data.NewField("count", nil, []int64{1})),
+		expected: false,
+	}}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			assert.Equal(t, tt.expected, isNumberTable(tt.frame))
+		})
+	}
+}
+
+func TestExtractNumberSet(t *testing.T) {
+	t.Run("should create a number for each row with string columns as labels", func(t *testing.T) {
+		frame := data.NewFrame("",
+			data.NewField("host", nil, []string{"a", "b", "c"}),
+			data.NewField("count", nil, []*int64{i64p(1), nil, i64p(3)}),
+			data.NewField("region", nil, []*string{strp("eu"), strp("us"), nil}))
+		frame.Fields[1].Config = &data.FieldConfig{Unit: "short"}
+
+		numbers, err := extractNumberSet(frame)
+		require.NoError(t, err)
+		require.Len(t, numbers, 3)
+
+		assert.Equal(t, data.Labels{"host": "a", "region": "eu"}, numbers[0].GetLabels())
+		assert.Equal(t, fp(1), numbers[0].GetFloat64Value())
+		assert.Equal(t, "count", numbers[0].Frame.Fields[0].Name)
+		assert.Equal(t, "short", numbers[0].Frame.Fields[0].Config.Unit)
+
+		assert.Equal(t, data.Labels{"host": "b", "region": "us"}, numbers[1].GetLabels())
+		assert.Nil(t, numbers[1].GetFloat64Value())
+
+		assert.Equal(t, data.Labels{"host": "c"}, numbers[2].GetLabels())
+		assert.Equal(t, fp(3), numbers[2].GetFloat64Value())
+	})
+
+	t.Run("should fail when string columns have the same name", func(t *testing.T) {
+		frame := data.NewFrame("",
+			data.NewField("host", nil, []string{"a"}),
+			data.NewField("host", nil, []string{"b"}),
+			data.NewField("count", nil, []int64{1}))
+
+		_, err := extractNumberSet(frame)
+		require.EqualError(t, err, "failed to convert table to numbers: duplicate string column \"host\"")
+	})
+
+	t.Run("should fail when rows have the same labels", func(t *testing.T) {
+		frame := data.NewFrame("",
+			data.NewField("host", nil, []string{"a", "b", "a"}),
+			data.NewField("count", nil, []int64{1, 2, 3}))
+
+		_, err := extractNumberSet(frame)
+		require.EqualError(t, err, "failed to convert table to numbers: rows 0 and 2 have the same labels {host=a}")
+	})
+}
+
+func strp(s string) *string {
+	return &s
+}
+
+func i64p(i int64) *int64 {
+	return &i
+}
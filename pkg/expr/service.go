@@ -35,19 +35,37 @@ func IsDataSource(uid string) bool {
 	return uid == DatasourceUID || uid == OldDatasourceUID
 }
 
+// QueryCache caches the responses of the queries to data sources.
+type QueryCache interface {
+	// QueryData returns the cached response of the request to the data source, or else queries it with the handler.
+	QueryData(ctx context.Context, ds *datasources.DataSource, req *backend.QueryDataRequest, handler backend.QueryDataHandler) (*backend.QueryDataResponse, error)
+}
+
 // Service is service representation for expression handling.
 type Service struct {
 	cfg               *setting.Cfg
 	dataService       backend.QueryDataHandler
 	dataSourceService datasources.DataSourceService
+	queryCache        QueryCache
 }
 
-func ProvideService(cfg *setting.Cfg, pluginClient plugins.Client, dataSourceService datasources.DataSourceService) *Service {
+// ProvideService returns the expression service. The queries of data sources are cached by the query cache,
+// which is optional.
+func ProvideService(cfg *setting.Cfg, pluginClient plugins.Client, dataSourceService datasources.DataSourceService, queryCache QueryCache) *Service {
 	return &Service{
 		cfg:               cfg,
 		dataService:       pluginClient,
 		dataSourceService: dataSourceService,
+		queryCache:        queryCache,
+	}
+}
+
+// queryDataSource queries the data source, through the query cache if there is one.
+func (s *Service) queryDataSource(ctx context.Context, ds *datasources.DataSource, req *backend.QueryDataRequest) (*backend.QueryDataResponse, error) {
+	if s.queryCache != nil {
+		return s.queryCache.QueryData(ctx, ds, req, s.dataService)
 	}
+	return s.dataService.QueryData(ctx, req)
 }
 
 func (s *Service) isDisabled() bool {
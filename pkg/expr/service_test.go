@@ -93,6 +93,55 @@ func TestService(t *testing.T) {
 	}
 }
 
+func TestServiceNumberTable(t *testing.T) {
+	// A table as returned by a SQL data source for a query with a GROUP BY clause and no time column.
+	dsDF := data.NewFrame("",
+		data.NewField("host", nil, []*string{strp("a"), strp("b")}),
+		data.NewField("count", nil, []*int64{i64p(5), i64p(20)}))
+
+	s := Service{
+		cfg:               setting.NewCfg(),
+		dataService:       &mockEndpoint{Frames: []*data.Frame{dsDF}},
+		dataSourceService: &datafakes.FakeDataSourceService{},
+	}
+
+	queries := []Query{
+		{
+			RefID: "A",
+			DataSource: &datasources.DataSource{
+				OrgId: 1,
+				Uid:   "test",
+				Type:  datasources.DS_MYSQL,
+			},
+			JSON: json.RawMessage(`{ "datasource": { "uid": "1" }, "format": "table", "intervalMs": 1000, "maxDataPoints": 1000 }`),
+			TimeRange: AbsoluteTimeRange{
+				From: time.Time{},
+				To:   time.Time{},
+			},
+		},
+		{
+			RefID:      "B",
+			DataSource: DataSourceModel(),
+			JSON:       json.RawMessage(`{ "datasource": { "uid": "__expr__", "type": "__expr__"}, "type": "math", "expression": "$A > 10" }`),
+		},
+	}
+
+	pl, err := s.BuildPipeline(&Request{Queries: queries})
+	require.NoError(t, err)
+
+	res, err := s.ExecutePipeline(context.Background(), time.Now(), pl)
+	require.NoError(t, err)
+
+	frames := res.Responses["B"].Frames
+	require.Len(t, frames, 2)
+	results := make(map[string]*float64, len(frames))
+	for _, f := range frames {
+		require.Len(t, f.Fields, 1)
+		results[f.Fields[0].Labels["host"]] = f.Fields[0].At(0).(*float64)
+	}
+	require.Equal(t, map[string]*float64{"a": fp(0), "b": fp(1)}, results)
+}
+
 func fp(f float64) *float64 {
 	return &f
 }
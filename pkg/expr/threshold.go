@@ -7,6 +7,8 @@ import (
 	"strings"
 	"time"
 
+	"github.com/grafana/grafana-plugin-sdk-go/data"
+
 	"github.com/grafana/grafana/pkg/expr/mathexp"
 )
 
@@ -15,6 +17,8 @@ type ThresholdCommand struct {
 	RefID         string
 	ThresholdFunc string
 	Conditions    []float64
+	// Invert negates the result of the threshold function, so the command returns 1 when the threshold is not crossed.
+	Invert bool
 }
 
 const (
@@ -39,6 +43,12 @@ func NewThresholdCommand(refID, referenceVar, thresholdFunc string, conditions [
 
 type ThresholdConditionJSON struct {
 	Evaluator ConditionEvalJSON `json:"evaluator"`
+	// UnloadEvaluator is the optional recovery threshold. If it is set, a dimension that is currently
+	// alerting only recovers once the UnloadEvaluator condition is met.
+	UnloadEvaluator *ConditionEvalJSON `json:"unloadEvaluator,omitempty"`
+	// LoadedDimensions are the labels of the dimensions that are currently alerting.
+	// It is set by the caller before the expression is executed and is only used together with UnloadEvaluator.
+	LoadedDimensions []data.Labels `json:"loadedDimensions,omitempty"`
 }
 
 type ConditionEvalJSON struct {
@@ -46,8 +56,9 @@ type ConditionEvalJSON struct {
 	Type   string    `json:"type"` // e.g. "gt"
 }
 
-// UnmarshalResampleCommand creates a ResampleCMD from Grafana's frontend query.
-func UnmarshalThresholdCommand(rn *rawNode) (*ThresholdCommand, error) {
+// UnmarshalThresholdCommand creates a ThresholdCommand from Grafana's frontend query.
+// If the condition has a recovery threshold, a HysteresisCommand is created instead.
+func UnmarshalThresholdCommand(rn *rawNode) (Command, error) {
 	rawQuery := rn.Query
 
 	rawExpression, ok := rawQuery["expression"]
@@ -80,6 +91,13 @@ func UnmarshalThresholdCommand(rn *rawNode) (*ThresholdCommand, error) {
 	}
 	firstCondition := conditions[0]
 
+	if firstCondition.UnloadEvaluator != nil {
+		if !IsSupportedThresholdFunc(firstCondition.UnloadEvaluator.Type) {
+			return nil, fmt.Errorf("expected recovery threshold function to be one of %s, got %s", strings.Join(supportedThresholdFuncs, ", "), firstCondition.UnloadEvaluator.Type)
+		}
+		return NewHysteresisCommand(rn.RefID, referenceVar, firstCondition.Evaluator, *firstCondition.UnloadEvaluator, firstCondition.LoadedDimensions)
+	}
+
 	return NewThresholdCommand(rn.RefID, referenceVar, firstCondition.Evaluator.Type, firstCondition.Evaluator.Params)
 }
 
@@ -94,6 +112,9 @@ func (tc *ThresholdCommand) Execute(ctx context.Context, now time.Time, vars mat
 	if err != nil {
 		return mathexp.Results{}, err
 	}
+	if tc.Invert {
+		mathExpression = fmt.Sprintf("!(%s)", mathExpression)
+	}
 
 	mathCommand, err := NewMathCommand(tc.ReferenceVar, mathExpression)
 	if err != nil {
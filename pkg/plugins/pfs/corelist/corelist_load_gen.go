@@ -36,6 +36,7 @@ func coreTreeList(rt *thema.Runtime) pfs.TreeList {
 		makeTreeOrPanic("public/app/plugins/datasource/cloud-monitoring", "stackdriver", rt),
 		makeTreeOrPanic("public/app/plugins/datasource/cloudwatch", "cloudwatch", rt),
 		makeTreeOrPanic("public/app/plugins/datasource/dashboard", "dashboard", rt),
+		makeTreeOrPanic("public/app/plugins/datasource/duckdb", "duckdb", rt),
 		makeTreeOrPanic("public/app/plugins/datasource/elasticsearch", "elasticsearch", rt),
 		makeTreeOrPanic("public/app/plugins/datasource/grafana", "grafana", rt),
 		makeTreeOrPanic("public/app/plugins/datasource/grafana-azure-monitor-datasource", "grafana_azure_monitor_datasource", rt),
@@ -48,6 +49,7 @@ func coreTreeList(rt *thema.Runtime) pfs.TreeList {
 		makeTreeOrPanic("public/app/plugins/datasource/phlare", "phlare", rt),
 		makeTreeOrPanic("public/app/plugins/datasource/postgres", "postgres", rt),
 		makeTreeOrPanic("public/app/plugins/datasource/prometheus", "prometheus", rt),
+		makeTreeOrPanic("public/app/plugins/datasource/sqlite", "sqlite", rt),
 		makeTreeOrPanic("public/app/plugins/datasource/tempo", "tempo", rt),
 		makeTreeOrPanic("public/app/plugins/datasource/testdata", "testdata", rt),
 		makeTreeOrPanic("public/app/plugins/datasource/zipkin", "zipkin", rt),
@@ -23,6 +23,8 @@ const (
 	DS_MYSQL          = "mysql"
 	DS_POSTGRES       = "postgres"
 	DS_MSSQL          = "mssql"
+	DS_SQLITE         = "sqlite"
+	DS_DUCKDB         = "duckdb"
 	DS_ACCESS_DIRECT  = "direct"
 	DS_ACCESS_PROXY   = "proxy"
 	DS_ES_OPEN_DISTRO = "grafana-es-open-distro-datasource"
@@ -20,6 +20,7 @@ func (l *LibraryElementService) registerAPIEndpoints() {
 		entities.Get("/", middleware.ReqSignedIn, routing.Wrap(l.getAllHandler))
 		entities.Get("/:uid", middleware.ReqSignedIn, routing.Wrap(l.getHandler))
 		entities.Get("/:uid/connections/", middleware.ReqSignedIn, routing.Wrap(l.getConnectionsHandler))
+		entities.Get("/:uid/export", middleware.ReqSignedIn, routing.Wrap(l.exportHandler))
 		entities.Get("/name/:name", middleware.ReqSignedIn, routing.Wrap(l.getByNameHandler))
 		entities.Patch("/:uid", middleware.ReqSignedIn, routing.Wrap(l.patchHandler))
 	})
@@ -222,6 +223,27 @@ func (l *LibraryElementService) getConnectionsHandler(c *models.ReqContext) resp
 	return response.JSON(http.StatusOK, LibraryElementConnectionsResponse{Result: connections})
 }
 
+// swagger:route GET /library-elements/{library_element_uid}/export library_elements exportLibraryElement
+//
+// Export library element.
+//
+// Returns the library element with the given UID and the dashboards it's connected to, in the format of library
+// panel provisioning files.
+//
+// Responses:
+// 200: exportLibraryElementResponse
+// 401: unauthorisedError
+// 404: notFoundError
+// 500: internalServerError
+func (l *LibraryElementService) exportHandler(c *models.ReqContext) response.Response {
+	export, err := l.getLibraryElementExport(c.Req.Context(), c.SignedInUser, web.Params(c.Req)[":uid"])
+	if err != nil {
+		return toLibraryElementError(err, "Failed to export library element")
+	}
+
+	return response.JSON(http.StatusOK, export)
+}
+
 // swagger:route GET /library-elements/name/{library_element_name} library_elements getLibraryElementByName
 //
 // Get library element by name.
@@ -273,7 +295,7 @@ func toLibraryElementError(err error, message string) response.Response {
 	return response.Error(500, message, err)
 }
 
-// swagger:parameters getLibraryElementByUID getLibraryElementConnections
+// swagger:parameters getLibraryElementByUID getLibraryElementConnections exportLibraryElement
 type LibraryElementByUID struct {
 	// in:path
 	// required:true
@@ -388,3 +410,9 @@ type GetLibraryElementConnectionsResponse struct {
 	// in: body
 	Body LibraryElementConnectionsResponse `json:"body"`
 }
+
+// swagger:response exportLibraryElementResponse
+type ExportLibraryElementResponse struct {
+	// in: body
+	Body LibraryElementExport `json:"body"`
+}
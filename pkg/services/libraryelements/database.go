@@ -603,6 +603,37 @@ func (l *LibraryElementService) getConnections(c context.Context, signedInUser *
 	return connections, err
 }
 
+// getLibraryElementExport gets a Library Element by uid with the dashboards it's connected to.
+func (l *LibraryElementService) getLibraryElementExport(c context.Context, signedInUser *user.SignedInUser, uid string) (LibraryElementExport, error) {
+	element, err := l.getLibraryElementByUid(c, signedInUser, uid)
+	if err != nil {
+		return LibraryElementExport{}, err
+	}
+
+	export := LibraryElementExport{
+		UID:                 element.UID,
+		Name:                element.Name,
+		Kind:                element.Kind,
+		FolderUID:           element.FolderUID,
+		Model:               element.Model,
+		ConnectedDashboards: make([]LibraryElementExportDashboard, 0),
+	}
+	err = l.SQLStore.WithDbSession(c, func(session *db.Session) error {
+		builder := db.NewSqlBuilder(l.Cfg)
+		builder.Write("SELECT dashboard.uid, dashboard.title")
+		builder.Write(" FROM " + models.LibraryElementConnectionTableName + " AS lec")
+		builder.Write(" INNER JOIN dashboard AS dashboard on lec.connection_id = dashboard.id")
+		builder.Write(` WHERE lec.element_id=?`, element.ID)
+		if signedInUser.OrgRole != org.RoleAdmin {
+			builder.WriteDashboardPermissionFilter(signedInUser, models.PERMISSION_VIEW)
+		}
+		builder.Write(" ORDER BY dashboard.title")
+		return session.SQL(builder.GetSQLString(), builder.GetParams()...).Find(&export.ConnectedDashboards)
+	})
+
+	return export, err
+}
+
 // getElementsForDashboardID gets all elements for a specific dashboard
 func (l *LibraryElementService) getElementsForDashboardID(c context.Context, dashboardID int64) (map[string]LibraryElementDTO, error) {
 	libraryElementMap := make(map[string]LibraryElementDTO)
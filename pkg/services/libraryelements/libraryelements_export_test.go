@@ -0,0 +1,82 @@
+package libraryelements
+
+import (
+	"encoding/json"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/grafana/grafana/pkg/components/simplejson"
+	"github.com/grafana/grafana/pkg/models"
+	"github.com/grafana/grafana/pkg/web"
+)
+
+func TestExportLibraryElement(t *testing.T) {
+	scenarioWithPanel(t, "When an admin tries to export a library panel that does not exist, it should fail",
+		func(t *testing.T, sc scenarioContext) {
+			sc.ctx.Req = web.SetURLParams(sc.ctx.Req, map[string]string{":uid": "unknown"})
+			resp := sc.service.exportHandler(sc.reqContext)
+			require.Equal(t, 404, resp.Status())
+		})
+
+	scenarioWithPanel(t, "When an admin tries to export a connected library panel, it should succeed and return the connected dashboards",
+		func(t *testing.T, sc scenarioContext) {
+			for _, title := range []string{"Second dashboard", "First dashboard"} {
+				dash := models.Dashboard{
+					Title: title,
+					Data: simplejson.NewFromAny(map[string]interface{}{
+						"panels": []interface{}{
+							map[string]interface{}{
+								"id": int64(1),
+								"libraryPanel": map[string]interface{}{
+									"uid":  sc.initialResult.Result.UID,
+									"name": sc.initialResult.Result.Name,
+								},
+							},
+						},
+					}),
+				}
+				dashInDB := createDashboard(t, sc.sqlStore, sc.user, &dash, sc.folder.ID)
+				err := sc.service.ConnectElementsToDashboard(sc.reqContext.Req.Context(), sc.reqContext.SignedInUser, []string{sc.initialResult.Result.UID}, dashInDB.Id)
+				require.NoError(t, err)
+			}
+
+			sc.ctx.Req = web.SetURLParams(sc.ctx.Req, map[string]string{":uid": sc.initialResult.Result.UID})
+			resp := sc.service.exportHandler(sc.reqContext)
+			require.Equal(t, 200, resp.Status())
+
+			var result struct {
+				UID                 string                          `json:"uid"`
+				Name                string                          `json:"name"`
+				Kind                int64                           `json:"kind"`
+				FolderUID           string                          `json:"folderUid"`
+				Model               map[string]interface{}          `json:"model"`
+				ConnectedDashboards []LibraryElementExportDashboard `json:"connectedDashboards"`
+			}
+			require.NoError(t, json.Unmarshal(resp.Body(), &result))
+
+			require.Equal(t, sc.initialResult.Result.UID, result.UID)
+			require.Equal(t, "Text - Library Panel", result.Name)
+			require.Equal(t, int64(models.PanelElement), result.Kind)
+			require.Equal(t, sc.folder.UID, result.FolderUID)
+			require.Equal(t, map[string]interface{}{
+				"datasource":  "${DS_GDEV-TESTDATA}",
+				"description": "A description",
+				"id":          float64(1),
+				"title":       "Text - Library Panel",
+				"type":        "text",
+			}, result.Model)
+			require.Len(t, result.ConnectedDashboards, 2)
+			require.Equal(t, "First dashboard", result.ConnectedDashboards[0].Title)
+			require.Equal(t, "Second dashboard", result.ConnectedDashboards[1].Title)
+			require.NotEmpty(t, result.ConnectedDashboards[0].UID)
+		})
+
+	scenarioWithPanel(t, "When an admin tries to export a library panel that is not connected, it should return an empty list of dashboards",
+		func(t *testing.T, sc scenarioContext) {
+			sc.ctx.Req = web.SetURLParams(sc.ctx.Req, map[string]string{":uid": sc.initialResult.Result.UID})
+			resp := sc.service.exportHandler(sc.reqContext)
+			require.Equal(t, 200, resp.Status())
+			require.Contains(t, string(resp.Body()), `"connectedDashboards":[]`)
+		})
+}
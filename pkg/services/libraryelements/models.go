@@ -138,6 +138,23 @@ type LibraryElementConnectionDTO struct {
 	CreatedBy     LibraryElementDTOMetaUser `json:"createdBy"`
 }
 
+// LibraryElementExport is the model of an exported library element, that can be provisioned again.
+type LibraryElementExport struct {
+	UID       string          `json:"uid"`
+	Name      string          `json:"name"`
+	Kind      int64           `json:"kind"`
+	FolderUID string          `json:"folderUid"`
+	Model     json.RawMessage `json:"model"`
+	// The dashboards that use the library element.
+	ConnectedDashboards []LibraryElementExportDashboard `json:"connectedDashboards"`
+}
+
+// LibraryElementExportDashboard is a dashboard connected to an exported library element.
+type LibraryElementExportDashboard struct {
+	UID   string `json:"uid" xorm:"uid"`
+	Title string `json:"title" xorm:"title"`
+}
+
 var (
 	// errLibraryElementAlreadyExists is an error for when the user tries to add a library element that already exists.
 	errLibraryElementAlreadyExists = errors.New("library element with that name or UID already exists")
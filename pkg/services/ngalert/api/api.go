@@ -11,7 +11,9 @@ import (
 	"github.com/grafana/grafana/pkg/services/datasourceproxy"
 	"github.com/grafana/grafana/pkg/services/datasources"
 	apimodels "github.com/grafana/grafana/pkg/services/ngalert/api/tooling/definitions"
+	"github.com/grafana/grafana/pkg/services/ngalert/backtesting"
 	"github.com/grafana/grafana/pkg/services/ngalert/eval"
+	"github.com/grafana/grafana/pkg/services/ngalert/image"
 	"github.com/grafana/grafana/pkg/services/ngalert/metrics"
 	"github.com/grafana/grafana/pkg/services/ngalert/models"
 	"github.com/grafana/grafana/pkg/services/ngalert/notifier"
@@ -51,6 +53,14 @@ type Alertmanager interface {
 	// Receivers
 	GetReceivers(ctx context.Context) []apimodels.Receiver
 	TestReceivers(ctx context.Context, c apimodels.TestReceiversConfigBodyParams) (*notifier.TestReceiversResult, error)
+
+	// Notification history
+	GetNotificationHistory(ctx context.Context, query *models.NotificationHistoryQuery) ([]models.NotificationHistoryEntry, error)
+
+	// Dead letters
+	GetDeadLetters(ctx context.Context, query *models.NotificationDeadLetterQuery) ([]models.NotificationDeadLetter, error)
+	ReplayDeadLetter(ctx context.Context, id int64) error
+	DeleteDeadLetter(ctx context.Context, id int64) error
 }
 
 type AlertingStore interface {
@@ -81,6 +91,9 @@ type API struct {
 	AlertRules           *provisioning.AlertRuleService
 	AlertsRouter         *sender.AlertsRouter
 	EvaluatorFactory     eval.EvaluatorFactory
+	Historian            Historian
+	// ImageStorage is nil if screenshots are not stored in file storage.
+	ImageStorage *image.StoringService
 }
 
 // RegisterAPIEndpoints registers API handlers
@@ -126,6 +139,8 @@ func (api *API) RegisterAPIEndpoints(m *metrics.API) {
 			log:             logger,
 			accessControl:   api.AccessControl,
 			evaluator:       api.EvaluatorFactory,
+			cfg:             &api.Cfg.UnifiedAlerting,
+			backtesting:     backtesting.NewEngine(api.EvaluatorFactory),
 		}), m)
 	api.RegisterConfigurationApiEndpoints(NewConfiguration(
 		&ConfigSrv{
@@ -144,6 +159,20 @@ func (api *API) RegisterAPIEndpoints(m *metrics.API) {
 		muteTimings:         api.MuteTimings,
 		alertRules:          api.AlertRules,
 	}), m)
+
+	api.RegisterHistoryApiEndpoints(NewStateHistoryApi(&HistorySrv{
+		logger: logger,
+		hist:   api.Historian,
+		store:  api.RuleStore,
+		ac:     api.AccessControl,
+	}), m)
+
+	if api.ImageStorage != nil {
+		api.RegisterImageApiEndpoints(&ImageSrv{
+			logger:  logger,
+			storage: api.ImageStorage,
+		}, m)
+	}
 }
 
 func (api *API) Usage(ctx context.Context, scopeParams *quota.ScopeParameters) (*quota.Map, error) {
@@ -2,6 +2,7 @@ package api
 
 import (
 	"context"
+	"encoding/json"
 	"errors"
 	"fmt"
 	"net/http"
@@ -16,6 +17,7 @@ import (
 	"github.com/grafana/grafana/pkg/models"
 	"github.com/grafana/grafana/pkg/services/accesscontrol"
 	apimodels "github.com/grafana/grafana/pkg/services/ngalert/api/tooling/definitions"
+	ngmodels "github.com/grafana/grafana/pkg/services/ngalert/models"
 	"github.com/grafana/grafana/pkg/services/ngalert/notifier"
 	"github.com/grafana/grafana/pkg/services/ngalert/store"
 	"github.com/grafana/grafana/pkg/util"
@@ -257,6 +259,144 @@ func (srv AlertmanagerSrv) RouteGetReceivers(c *models.ReqContext) response.Resp
 	return response.JSON(http.StatusOK, rcvs)
 }
 
+func (srv AlertmanagerSrv) RouteGetNotificationHistory(c *models.ReqContext) response.Response {
+	query := ngmodels.NotificationHistoryQuery{
+		OrgID:       c.OrgID,
+		Receiver:    c.Query("receiver"),
+		Integration: c.Query("integration"),
+		Status:      c.Query("status"),
+		Limit:       c.QueryInt("limit"),
+	}
+	switch query.Status {
+	case "", ngmodels.NotificationHistoryStatusSuccess, ngmodels.NotificationHistoryStatusFailed:
+	default:
+		return ErrResp(http.StatusBadRequest, fmt.Errorf("invalid status %q, must be %q or %q", query.Status, ngmodels.NotificationHistoryStatusSuccess, ngmodels.NotificationHistoryStatusFailed), "")
+	}
+	if from := c.QueryInt64("from"); from != 0 {
+		query.From = time.Unix(from, 0)
+	}
+	if to := c.QueryInt64("to"); to != 0 {
+		query.To = time.Unix(to, 0)
+	}
+	if !query.From.IsZero() && !query.To.IsZero() && query.From.After(query.To) {
+		return ErrResp(http.StatusBadRequest, errors.New("'from' must not be after 'to'"), "")
+	}
+
+	am, errResp := srv.AlertmanagerFor(c.OrgID)
+	if errResp != nil {
+		return errResp
+	}
+
+	entries, err := am.GetNotificationHistory(c.Req.Context(), &query)
+	if err != nil {
+		return ErrResp(http.StatusInternalServerError, err, "failed to get notification history")
+	}
+
+	result := make(apimodels.NotificationHistory, 0, len(entries))
+	for _, e := range entries {
+		result = append(result, apimodels.NotificationHistoryEntry{
+			Receiver:         e.Receiver,
+			Integration:      e.Integration,
+			IntegrationIndex: e.IntegrationIndex,
+			GroupKey:         e.GroupKey,
+			Status:           e.Status,
+			Error:            e.Error,
+			Duration:         e.Duration,
+			Retries:          e.Retries,
+			FiringAlerts:     e.FiringAlerts,
+			ResolvedAlerts:   e.ResolvedAlerts,
+			Timestamp:        e.CreatedAt,
+		})
+	}
+	return response.JSON(http.StatusOK, result)
+}
+
+func (srv AlertmanagerSrv) RouteGetNotificationDeadLetters(c *models.ReqContext) response.Response {
+	query := ngmodels.NotificationDeadLetterQuery{
+		OrgID:    c.OrgID,
+		Receiver: c.Query("receiver"),
+		Limit:    c.QueryInt("limit"),
+	}
+
+	am, errResp := srv.AlertmanagerFor(c.OrgID)
+	if errResp != nil {
+		return errResp
+	}
+
+	deadLetters, err := am.GetDeadLetters(c.Req.Context(), &query)
+	if err != nil {
+		return ErrResp(http.StatusInternalServerError, err, "failed to get notification dead letters")
+	}
+
+	result := make(apimodels.NotificationDeadLetters, 0, len(deadLetters))
+	for _, d := range deadLetters {
+		deadLetter := apimodels.NotificationDeadLetter{
+			ID:               d.ID,
+			Receiver:         d.Receiver,
+			Integration:      d.Integration,
+			IntegrationIndex: d.IntegrationIndex,
+			GroupKey:         d.GroupKey,
+			Error:            d.Error,
+			Timestamp:        d.CreatedAt,
+		}
+		if err := json.Unmarshal([]byte(d.GroupLabels), &deadLetter.GroupLabels); err != nil {
+			return ErrResp(http.StatusInternalServerError, err, "failed to decode group labels of notification dead letter")
+		}
+		if err := json.Unmarshal([]byte(d.Alerts), &deadLetter.Alerts); err != nil {
+			return ErrResp(http.StatusInternalServerError, err, "failed to decode alerts of notification dead letter")
+		}
+		result = append(result, deadLetter)
+	}
+	return response.JSON(http.StatusOK, result)
+}
+
+func (srv AlertmanagerSrv) RoutePostNotificationDeadLetterReplay(c *models.ReqContext, id string) response.Response {
+	deadLetterID, err := strconv.ParseInt(id, 10, 64)
+	if err != nil {
+		return ErrResp(http.StatusBadRequest, fmt.Errorf("invalid dead letter ID %q", id), "")
+	}
+
+	am, errResp := srv.AlertmanagerFor(c.OrgID)
+	if errResp != nil {
+		return errResp
+	}
+
+	if err := am.ReplayDeadLetter(c.Req.Context(), deadLetterID); err != nil {
+		if errors.Is(err, ngmodels.ErrNotificationDeadLetterNotFound) {
+			return ErrResp(http.StatusNotFound, err, "")
+		}
+		if errors.Is(err, notifier.ErrDeadLetterIntegrationNotFound) {
+			return ErrResp(http.StatusBadRequest, err, "")
+		}
+		var replayErr notifier.DeadLetterReplayError
+		if errors.As(err, &replayErr) {
+			return ErrResp(http.StatusBadGateway, err, "")
+		}
+		return ErrResp(http.StatusInternalServerError, err, "failed to replay notification dead letter")
+	}
+	return response.JSON(http.StatusOK, util.DynMap{"message": "notification sent"})
+}
+
+func (srv AlertmanagerSrv) RouteDeleteNotificationDeadLetter(c *models.ReqContext, id string) response.Response {
+	deadLetterID, err := strconv.ParseInt(id, 10, 64)
+	if err != nil {
+		return ErrResp(http.StatusBadRequest, fmt.Errorf("invalid dead letter ID %q", id), "")
+	}
+
+	am, errResp := srv.AlertmanagerFor(c.OrgID)
+	if errResp != nil {
+		return errResp
+	}
+
+	if err := am.DeleteDeadLetter(c.Req.Context(), deadLetterID); err != nil {
+		if errors.Is(err, ngmodels.ErrNotificationDeadLetterNotFound) {
+			return ErrResp(http.StatusNotFound, err, "")
+		}
+		return ErrResp(http.StatusInternalServerError, err, "failed to delete notification dead letter")
+	}
+	return response.JSON(http.StatusOK, util.DynMap{"message": "notification dead letter deleted"})
+}
+
 func (srv AlertmanagerSrv) RoutePostTestReceivers(c *models.ReqContext, body apimodels.TestReceiversConfigBodyParams) response.Response {
 	if err := srv.crypto.LoadSecureSettings(c.Req.Context(), c.OrgID, body.Receivers); err != nil {
 		var unknownReceiverError UnknownReceiverError
@@ -85,6 +85,9 @@ func checkContactPoints(currReceivers []*apimodels.GettableApiReceiver, newRecei
 			if contactPoint.DisableResolveMessage != postedContactPoint.DisableResolveMessage {
 				return editErr
 			}
+			if !cmp.Equal(contactPoint.RetryPolicy, postedContactPoint.RetryPolicy) {
+				return editErr
+			}
 			if contactPoint.Name != postedContactPoint.Name {
 				return editErr
 			}
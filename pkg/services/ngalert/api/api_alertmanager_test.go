@@ -482,6 +482,88 @@ func TestRouteCreateSilence(t *testing.T) {
 	}
 }
 
+func TestRouteGetNotificationHistory(t *testing.T) {
+	sut := createSut(t, nil)
+
+	requestWithQuery := func(orgID int64, query string) *models.ReqContext {
+		req, err := http.NewRequest(http.MethodGet, "https://grafana.net/api/alertmanager/grafana/api/v1/notifications?"+query, nil)
+		require.NoError(t, err)
+		return &models.ReqContext{
+			Context: &web.Context{
+				Req: req,
+			},
+			SignedInUser: &user.SignedInUser{
+				OrgID: orgID,
+			},
+		}
+	}
+
+	t.Run("assert 200 when query is valid", func(t *testing.T) {
+		resp := sut.RouteGetNotificationHistory(requestWithQuery(1, "receiver=team-a&status=failed&from=1000&to=2000&limit=10"))
+		require.Equal(t, http.StatusOK, resp.Status())
+		require.JSONEq(t, "[]", string(resp.Body()))
+	})
+
+	t.Run("assert 400 when status is invalid", func(t *testing.T) {
+		resp := sut.RouteGetNotificationHistory(requestWithQuery(1, "status=pending"))
+		require.Equal(t, http.StatusBadRequest, resp.Status())
+	})
+
+	t.Run("assert 400 when from is after to", func(t *testing.T) {
+		resp := sut.RouteGetNotificationHistory(requestWithQuery(1, "from=2000&to=1000"))
+		require.Equal(t, http.StatusBadRequest, resp.Status())
+	})
+
+	t.Run("assert 404 when alertmanager does not exist for the organization", func(t *testing.T) {
+		resp := sut.RouteGetNotificationHistory(requestWithQuery(12, ""))
+		require.Equal(t, http.StatusNotFound, resp.Status())
+	})
+}
+
+func TestRouteNotificationDeadLetters(t *testing.T) {
+	sut := createSut(t, nil)
+
+	requestInOrg := func(orgID int64) *models.ReqContext {
+		req, err := http.NewRequest(http.MethodGet, "https://grafana.net/api/alertmanager/grafana/api/v1/notifications/dead-letters", nil)
+		require.NoError(t, err)
+		return &models.ReqContext{
+			Context: &web.Context{
+				Req: req,
+			},
+			SignedInUser: &user.SignedInUser{
+				OrgID: orgID,
+			},
+		}
+	}
+
+	t.Run("assert 200 when getting dead letters", func(t *testing.T) {
+		resp := sut.RouteGetNotificationDeadLetters(requestInOrg(1))
+		require.Equal(t, http.StatusOK, resp.Status())
+		require.JSONEq(t, "[]", string(resp.Body()))
+	})
+
+	t.Run("assert 400 when dead letter ID is invalid", func(t *testing.T) {
+		resp := sut.RoutePostNotificationDeadLetterReplay(requestInOrg(1), "abc")
+		require.Equal(t, http.StatusBadRequest, resp.Status())
+
+		resp = sut.RouteDeleteNotificationDeadLetter(requestInOrg(1), "abc")
+		require.Equal(t, http.StatusBadRequest, resp.Status())
+	})
+
+	t.Run("assert 404 when dead letter does not exist", func(t *testing.T) {
+		resp := sut.RoutePostNotificationDeadLetterReplay(requestInOrg(1), "1")
+		require.Equal(t, http.StatusNotFound, resp.Status())
+
+		resp = sut.RouteDeleteNotificationDeadLetter(requestInOrg(1), "1")
+		require.Equal(t, http.StatusNotFound, resp.Status())
+	})
+
+	t.Run("assert 404 when alertmanager does not exist for the organization", func(t *testing.T) {
+		resp := sut.RouteGetNotificationDeadLetters(requestInOrg(12))
+		require.Equal(t, http.StatusNotFound, resp.Status())
+	})
+}
+
 func createSut(t *testing.T, accessControl accesscontrol.AccessControl) AlertmanagerSrv {
 	t.Helper()
 
@@ -0,0 +1,146 @@
+package api
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/http"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/grafana/grafana-plugin-sdk-go/data"
+
+	"github.com/grafana/grafana/pkg/api/response"
+	"github.com/grafana/grafana/pkg/infra/log"
+	"github.com/grafana/grafana/pkg/models"
+	"github.com/grafana/grafana/pkg/services/accesscontrol"
+	apimodels "github.com/grafana/grafana/pkg/services/ngalert/api/tooling/definitions"
+	ngmodels "github.com/grafana/grafana/pkg/services/ngalert/models"
+	"github.com/grafana/grafana/pkg/services/ngalert/state/historian"
+)
+
+const labelQueryPrefix = "labels_"
+
+// defaultHistoryRange is the time range queried when the request does not specify one.
+const defaultHistoryRange = time.Hour
+
+// Historian reads alert state history back from the configured state history backend.
+type Historian interface {
+	QueryStates(ctx context.Context, query ngmodels.HistoryQuery) (*data.Frame, error)
+}
+
+type HistorySrv struct {
+	logger log.Logger
+	hist   Historian
+	store  RuleStore
+	ac     accesscontrol.AccessControl
+}
+
+func (srv *HistorySrv) RouteQueryStateHistory(c *models.ReqContext) response.Response {
+	to := c.QueryInt64("to")
+	from := c.QueryInt64("from")
+
+	toTime := timeNow()
+	if to != 0 {
+		toTime = time.Unix(to, 0)
+	}
+	fromTime := toTime.Add(-defaultHistoryRange)
+	if from != 0 {
+		fromTime = time.Unix(from, 0)
+	}
+	if fromTime.After(toTime) {
+		return ErrResp(http.StatusBadRequest, fmt.Errorf("'from' must not be after 'to'"), "")
+	}
+
+	query := ngmodels.HistoryQuery{
+		RuleUID:      c.Query("ruleUID"),
+		OrgID:        c.OrgID,
+		SignedInUser: c.SignedInUser,
+		From:         fromTime,
+		To:           toTime,
+		Limit:        c.QueryInt("limit"),
+		Labels:       make(map[string]string),
+	}
+	for k, v := range c.Req.URL.Query() {
+		if strings.HasPrefix(k, labelQueryPrefix) && len(v) > 0 {
+			query.Labels[strings.TrimPrefix(k, labelQueryPrefix)] = v[0]
+		}
+	}
+
+	ruleUIDs, all, err := srv.readableRuleUIDs(c)
+	if err != nil {
+		return ErrResp(http.StatusInternalServerError, err, "failed to get alert rules")
+	}
+	if query.RuleUID != "" {
+		if !ruleUIDs[query.RuleUID] {
+			return ErrResp(http.StatusForbidden, fmt.Errorf("%w to access the history of rule %s", ErrAuthorization, query.RuleUID), "")
+		}
+	} else {
+		if len(ruleUIDs) == 0 {
+			return response.JSON(http.StatusOK, apimodels.StateHistory{Results: historian.NewStatesFrame(0)})
+		}
+		// The history is only restricted to the readable rules when the user can't read every rule of the organization.
+		if !all {
+			for uid := range ruleUIDs {
+				query.RuleUIDs = append(query.RuleUIDs, uid)
+			}
+			sort.Strings(query.RuleUIDs)
+		}
+	}
+
+	frame, err := srv.hist.QueryStates(c.Req.Context(), query)
+	if err != nil {
+		if errors.Is(err, ngmodels.ErrHistoryQueryUnsupported) {
+			return ErrResp(http.StatusBadRequest, err, "")
+		}
+		srv.logger.Error("Failed to query alert state history", "error", err)
+		return ErrResp(http.StatusInternalServerError, err, "failed to query alert state history")
+	}
+	return response.JSON(http.StatusOK, apimodels.StateHistory{Results: frame})
+}
+
+// readableRuleUIDs returns the UIDs of the rules the user can read, and whether these are all the rules of the
+// organization. A rule is readable when the user can see its folder and query the data sources of every rule in its
+// group, which is how the ruler API decides what groups to return.
+func (srv *HistorySrv) readableRuleUIDs(c *models.ReqContext) (map[string]bool, bool, error) {
+	namespaceMap, err := srv.store.GetUserVisibleNamespaces(c.Req.Context(), c.OrgID, c.SignedInUser)
+	if err != nil {
+		return nil, false, err
+	}
+	if len(namespaceMap) == 0 {
+		return nil, false, nil
+	}
+
+	// All rules of the organization are listed to find out whether the user can read all of them.
+	q := ngmodels.ListAlertRulesQuery{
+		OrgID: c.OrgID,
+	}
+	if err := srv.store.ListAlertRules(c.Req.Context(), &q); err != nil {
+		return nil, false, err
+	}
+
+	hasAccess := func(evaluator accesscontrol.Evaluator) bool {
+		return accesscontrol.HasAccess(srv.ac, c)(accesscontrol.ReqViewer, evaluator)
+	}
+
+	groups := make(map[ngmodels.AlertRuleGroupKey]ngmodels.RulesGroup)
+	for _, r := range q.Result {
+		groupKey := r.GetGroupKey()
+		groups[groupKey] = append(groups[groupKey], r)
+	}
+
+	uids := make(map[string]bool)
+	for groupKey, rules := range groups {
+		if _, ok := namespaceMap[groupKey.NamespaceUID]; !ok {
+			continue
+		}
+		if !authorizeAccessToRuleGroup(rules, hasAccess) {
+			continue
+		}
+		for _, r := range rules {
+			uids[r.UID] = true
+		}
+	}
+	return uids, len(uids) == len(q.Result), nil
+}
@@ -0,0 +1,133 @@
+package api
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"math/rand"
+	"net/http"
+	"testing"
+
+	"github.com/grafana/grafana-plugin-sdk-go/data"
+	"github.com/stretchr/testify/require"
+
+	"github.com/grafana/grafana/pkg/infra/log"
+	acMock "github.com/grafana/grafana/pkg/services/accesscontrol/mock"
+	"github.com/grafana/grafana/pkg/services/folder"
+	apimodels "github.com/grafana/grafana/pkg/services/ngalert/api/tooling/definitions"
+	"github.com/grafana/grafana/pkg/services/ngalert/models"
+	"github.com/grafana/grafana/pkg/services/ngalert/tests/fakes"
+)
+
+func TestRouteQueryStateHistory(t *testing.T) {
+	orgID := rand.Int63()
+	ruleStore := fakes.NewRuleStore(t)
+	folder1 := randFolder()
+	folder2 := randFolder()
+	ruleStore.Folders[orgID] = []*folder.Folder{folder1, folder2}
+
+	group1Key := models.GenerateGroupKey(orgID)
+	group1Key.NamespaceUID = folder1.UID
+	group2Key := models.GenerateGroupKey(orgID)
+	group2Key.NamespaceUID = folder2.UID
+
+	group1 := models.GenerateAlertRules(rand.Intn(4)+2, models.AlertRuleGen(withGroupKey(group1Key)))
+	group2 := models.GenerateAlertRules(rand.Intn(4)+2, models.AlertRuleGen(withGroupKey(group2Key)))
+	ruleStore.PutRule(context.Background(), append(group1, group2...)...)
+
+	// The user can't query one of the data sources of group2, so none of its rules are readable
+	ac := acMock.New().WithPermissions(createPermissionsForRules(append(group1, group2[1:]...)))
+
+	createSrv := func() (*HistorySrv, *fakeHistorian) {
+		hist := &fakeHistorian{}
+		return &HistorySrv{logger: log.NewNopLogger(), hist: hist, store: ruleStore, ac: ac}, hist
+	}
+
+	t.Run("should restrict the query to the rules the user can read", func(t *testing.T) {
+		srv, hist := createSrv()
+
+		response := srv.RouteQueryStateHistory(createRequestContext(orgID, "", nil))
+
+		require.Equal(t, http.StatusOK, response.Status())
+		require.Len(t, hist.queries, 1)
+		expected := make([]string, 0, len(group1))
+		for _, rule := range group1 {
+			expected = append(expected, rule.UID)
+		}
+		require.ElementsMatch(t, expected, hist.queries[0].RuleUIDs)
+	})
+
+	t.Run("should not restrict the query if the user can read every rule", func(t *testing.T) {
+		hist := &fakeHistorian{}
+		srv := &HistorySrv{logger: log.NewNopLogger(), hist: hist, store: ruleStore, ac: acMock.New().WithPermissions(createPermissionsForRules(append(group1, group2...)))}
+
+		response := srv.RouteQueryStateHistory(createRequestContext(orgID, "", nil))
+
+		require.Equal(t, http.StatusOK, response.Status())
+		require.Len(t, hist.queries, 1)
+		require.Empty(t, hist.queries[0].RuleUIDs)
+	})
+
+	t.Run("should return 400 if the backend does not support the query", func(t *testing.T) {
+		srv, hist := createSrv()
+		hist.err = fmt.Errorf("%w: ruleUID is required", models.ErrHistoryQueryUnsupported)
+
+		response := srv.RouteQueryStateHistory(createRequestContext(orgID, "", nil))
+
+		require.Equal(t, http.StatusBadRequest, response.Status())
+	})
+
+	t.Run("should return the history of a readable rule", func(t *testing.T) {
+		srv, hist := createSrv()
+		request := createRequestContext(orgID, "", nil)
+		request.Req.URL.RawQuery = "ruleUID=" + group1[0].UID
+
+		response := srv.RouteQueryStateHistory(request)
+
+		require.Equal(t, http.StatusOK, response.Status())
+		require.Len(t, hist.queries, 1)
+		require.Equal(t, group1[0].UID, hist.queries[0].RuleUID)
+	})
+
+	t.Run("should forbid the history of a rule the user can't read", func(t *testing.T) {
+		srv, hist := createSrv()
+		request := createRequestContext(orgID, "", nil)
+		request.Req.URL.RawQuery = "ruleUID=" + group2[0].UID
+
+		response := srv.RouteQueryStateHistory(request)
+
+		require.Equal(t, http.StatusForbidden, response.Status())
+		require.Empty(t, hist.queries)
+	})
+
+	t.Run("should not query the historian if the user can't read any rule", func(t *testing.T) {
+		srv, hist := createSrv()
+
+		response := srv.RouteQueryStateHistory(createRequestContext(rand.Int63(), "", nil))
+
+		require.Equal(t, http.StatusOK, response.Status())
+		require.Empty(t, hist.queries)
+
+		// The empty frame has the same fields as the frames of the historian backends.
+		var body apimodels.StateHistory
+		require.NoError(t, json.Unmarshal(response.Body(), &body))
+		require.Len(t, body.Results.Fields, 3)
+		require.Equal(t, "time", body.Results.Fields[0].Name)
+		require.Equal(t, "line", body.Results.Fields[1].Name)
+		require.Equal(t, "labels", body.Results.Fields[2].Name)
+		require.Zero(t, body.Results.Rows())
+	})
+}
+
+type fakeHistorian struct {
+	queries []models.HistoryQuery
+	err     error
+}
+
+func (f *fakeHistorian) QueryStates(_ context.Context, query models.HistoryQuery) (*data.Frame, error) {
+	f.queries = append(f.queries, query)
+	if f.err != nil {
+		return nil, f.err
+	}
+	return data.NewFrame("states"), nil
+}
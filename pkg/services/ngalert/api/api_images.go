@@ -0,0 +1,63 @@
+package api
+
+import (
+	"context"
+	"errors"
+	"net/http"
+
+	"github.com/grafana/grafana/pkg/api/response"
+	"github.com/grafana/grafana/pkg/api/routing"
+	"github.com/grafana/grafana/pkg/infra/filestorage"
+	"github.com/grafana/grafana/pkg/infra/log"
+	"github.com/grafana/grafana/pkg/models"
+	"github.com/grafana/grafana/pkg/services/ngalert/image"
+	"github.com/grafana/grafana/pkg/services/ngalert/metrics"
+	"github.com/grafana/grafana/pkg/web"
+)
+
+// ImageStorage returns images stored in file storage using their signed URLs.
+type ImageStorage interface {
+	Get(ctx context.Context, token, expires, signature string) (*filestorage.File, error)
+}
+
+type ImageSrv struct {
+	logger  log.Logger
+	storage ImageStorage
+}
+
+// RegisterImageApiEndpoints registers the endpoint that serves images of alert notifications.
+// The endpoint does not require a Grafana session as the signature of the URL authenticates
+// the request, so images can be shown by contact points.
+func (api *API) RegisterImageApiEndpoints(srv *ImageSrv, m *metrics.API) {
+	api.RouteRegister.Group("", func(group routing.RouteRegister) {
+		group.Get(
+			toMacaronPath("/api/alerting/images/{Token}"),
+			metrics.Instrument(
+				http.MethodGet,
+				"/api/alerting/images/{Token}",
+				srv.RouteGetImage,
+				m,
+			),
+		)
+	})
+}
+
+func (srv *ImageSrv) RouteGetImage(c *models.ReqContext) response.Response {
+	token := web.Params(c.Req)[":Token"]
+	file, err := srv.storage.Get(c.Req.Context(), token, c.Query("expires"), c.Query("signature"))
+	if err != nil {
+		if errors.Is(err, image.ErrInvalidSignature) || errors.Is(err, image.ErrURLExpired) {
+			return ErrResp(http.StatusForbidden, err, "")
+		}
+		if errors.Is(err, image.ErrImageNotFound) {
+			return ErrResp(http.StatusNotFound, err, "")
+		}
+		srv.logger.Error("Failed to get image", "token", token, "error", err)
+		return ErrResp(http.StatusInternalServerError, err, "failed to get image")
+	}
+
+	header := http.Header{}
+	header.Set("Content-Type", file.MimeType)
+	header.Set("Cache-Control", "private, max-age=3600")
+	return response.CreateNormalResponse(header, file.Contents, http.StatusOK)
+}
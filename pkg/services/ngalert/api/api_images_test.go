@@ -0,0 +1,81 @@
+package api
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/grafana/grafana/pkg/api/response"
+	"github.com/grafana/grafana/pkg/infra/filestorage"
+	"github.com/grafana/grafana/pkg/infra/log"
+	"github.com/grafana/grafana/pkg/models"
+	"github.com/grafana/grafana/pkg/services/ngalert/image"
+	"github.com/grafana/grafana/pkg/web"
+)
+
+type fakeImageStorage struct {
+	file *filestorage.File
+	err  error
+}
+
+func (f *fakeImageStorage) Get(_ context.Context, _, _, _ string) (*filestorage.File, error) {
+	return f.file, f.err
+}
+
+func TestRouteGetImage(t *testing.T) {
+	testCases := []struct {
+		name      string
+		storage   *fakeImageStorage
+		expStatus int
+	}{
+		{
+			name: "returns the image",
+			storage: &fakeImageStorage{file: &filestorage.File{
+				Contents:     []byte("foo"),
+				FileMetadata: filestorage.FileMetadata{MimeType: "image/png"},
+			}},
+			expStatus: http.StatusOK,
+		},
+		{
+			name:      "returns 403 if the signature is invalid",
+			storage:   &fakeImageStorage{err: image.ErrInvalidSignature},
+			expStatus: http.StatusForbidden,
+		},
+		{
+			name:      "returns 403 if the URL has expired",
+			storage:   &fakeImageStorage{err: image.ErrURLExpired},
+			expStatus: http.StatusForbidden,
+		},
+		{
+			name:      "returns 404 if the image does not exist",
+			storage:   &fakeImageStorage{err: image.ErrImageNotFound},
+			expStatus: http.StatusNotFound,
+		},
+		{
+			name:      "returns 500 if the image cannot be read",
+			storage:   &fakeImageStorage{err: errors.New("failed to read file")},
+			expStatus: http.StatusInternalServerError,
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			srv := &ImageSrv{logger: log.NewNopLogger(), storage: tc.storage}
+
+			req, err := http.NewRequest(http.MethodGet, "/api/alerting/images/foo?expires=1&signature=bar", nil)
+			require.NoError(t, err)
+			req = web.SetURLParams(req, map[string]string{":Token": "foo"})
+			c := &models.ReqContext{Context: &web.Context{Req: req}}
+
+			resp := srv.RouteGetImage(c).(*response.NormalResponse)
+			require.Equal(t, tc.expStatus, resp.Status())
+			if tc.expStatus == http.StatusOK {
+				require.Equal(t, "image/png", resp.Header().Get("Content-Type"))
+				require.Equal(t, []byte("foo"), resp.Body())
+			}
+		})
+	}
+}
@@ -189,12 +189,31 @@ func (srv PrometheusSrv) toRuleGroup(groupName string, folder *folder.Folder, ru
 	}
 	ngmodels.RulesGroup(rules).SortByGroupIndex()
 	for _, rule := range rules {
+		newGroup.Interval = float64(rule.IntervalSeconds)
+		if rule.IsRecordingRule() {
+			// recording rules do not have alert instances, so they are returned without the alerting state and fields.
+			query := ruleToQuery(srv.log, rule)
+			newGroup.Rules = append(newGroup.Rules, apimodels.AlertingRule{
+				Name:  rule.Title,
+				Query: query,
+				Rule: apimodels.Rule{
+					Name:   rule.Title,
+					Query:  query,
+					Labels: rule.GetLabels(labelOptions...),
+					Health: "ok",
+					Type:   apiv1.RuleTypeRecording,
+				},
+			})
+			continue
+		}
+
 		alertingRule := apimodels.AlertingRule{
-			State:       "inactive",
-			Name:        rule.Title,
-			Query:       ruleToQuery(srv.log, rule),
-			Duration:    rule.For.Seconds(),
-			Annotations: rule.Annotations,
+			State:         "inactive",
+			Name:          rule.Title,
+			Query:         ruleToQuery(srv.log, rule),
+			Duration:      rule.For.Seconds(),
+			KeepFiringFor: rule.KeepFiringFor.Seconds(),
+			Annotations:   rule.Annotations,
 		}
 
 		newRule := apimodels.Rule{
@@ -253,7 +272,6 @@ func (srv PrometheusSrv) toRuleGroup(groupName string, folder *folder.Folder, ru
 
 		alertingRule.Rule = newRule
 		newGroup.Rules = append(newGroup.Rules, alertingRule)
-		newGroup.Interval = float64(rule.IntervalSeconds)
 		// TODO yuri. Change that when scheduler will process alerts in groups
 		newGroup.EvaluationTime = newRule.EvaluationTime
 		newGroup.LastEvaluation = newRule.LastEvaluation
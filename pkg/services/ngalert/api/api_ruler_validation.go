@@ -66,9 +66,39 @@ func validateRuleNode(
 		}
 	}
 
+	isPaused := false
+	if ruleNode.GrafanaManagedAlert.IsPaused != nil {
+		isPaused = *ruleNode.GrafanaManagedAlert.IsPaused
+	}
+
+	condition := ruleNode.GrafanaManagedAlert.Condition
+	var record *ngmodels.Record
+	if ruleNode.GrafanaManagedAlert.Record != nil {
+		if !cfg.RecordingRules.Enabled {
+			return nil, ngmodels.ErrRecordingRulesDisabled
+		}
+		record = &ngmodels.Record{
+			Metric: ruleNode.GrafanaManagedAlert.Record.Metric,
+			From:   ruleNode.GrafanaManagedAlert.Record.From,
+		}
+		// recording rules evaluate the query or expression they write, so it is the condition of the rule.
+		if condition == "" {
+			condition = record.From
+		}
+		if condition != record.From {
+			return nil, fmt.Errorf("%w: condition '%s' of recording rule must be the same as the query or expression it records '%s'", ngmodels.ErrAlertRuleFailedValidation, condition, record.From)
+		}
+		if len(ruleNode.GrafanaManagedAlert.Data) == 0 {
+			return nil, fmt.Errorf("%w: queries and expressions of recording rule must be specified", ngmodels.ErrAlertRuleFailedValidation)
+		}
+		if err := record.Validate(ruleNode.GrafanaManagedAlert.Data); err != nil {
+			return nil, err
+		}
+	}
+
 	if len(ruleNode.GrafanaManagedAlert.Data) == 0 {
 		if canPatch {
-			if ruleNode.GrafanaManagedAlert.Condition != "" {
+			if condition != "" {
 				return nil, fmt.Errorf("%w: query is not specified by condition is. You must specify both query and condition to update existing alert rule", ngmodels.ErrAlertRuleFailedValidation)
 			}
 		} else {
@@ -78,7 +108,7 @@ func validateRuleNode(
 
 	if len(ruleNode.GrafanaManagedAlert.Data) != 0 {
 		cond := ngmodels.Condition{
-			Condition: ruleNode.GrafanaManagedAlert.Condition,
+			Condition: condition,
 			Data:      ruleNode.GrafanaManagedAlert.Data,
 		}
 		if err = conditionValidator(cond); err != nil {
@@ -89,7 +119,7 @@ func validateRuleNode(
 	newAlertRule := ngmodels.AlertRule{
 		OrgID:           orgId,
 		Title:           ruleNode.GrafanaManagedAlert.Title,
-		Condition:       ruleNode.GrafanaManagedAlert.Condition,
+		Condition:       condition,
 		Data:            ruleNode.GrafanaManagedAlert.Data,
 		UID:             ruleNode.GrafanaManagedAlert.UID,
 		IntervalSeconds: intervalSeconds,
@@ -97,6 +127,8 @@ func validateRuleNode(
 		RuleGroup:       groupName,
 		NoDataState:     noDataState,
 		ExecErrState:    errorState,
+		IsPaused:        isPaused,
+		Record:          record,
 	}
 
 	newAlertRule.For, err = validateForInterval(ruleNode)
@@ -104,6 +136,11 @@ func validateRuleNode(
 		return nil, err
 	}
 
+	newAlertRule.KeepFiringFor, err = validateKeepFiringForInterval(ruleNode)
+	if err != nil {
+		return nil, err
+	}
+
 	if ruleNode.ApiRuleNode != nil {
 		newAlertRule.Annotations = ruleNode.ApiRuleNode.Annotations
 		newAlertRule.Labels = ruleNode.ApiRuleNode.Labels
@@ -147,6 +184,21 @@ func validateForInterval(ruleNode *apimodels.PostableExtendedRuleNode) (time.Dur
 	return duration, nil
 }
 
+// validateKeepFiringForInterval validates ApiRuleNode.KeepFiringFor and converts it to time.Duration. If the field is not specified returns 0 if GrafanaManagedAlert.UID is empty and -1 if it is not.
+func validateKeepFiringForInterval(ruleNode *apimodels.PostableExtendedRuleNode) (time.Duration, error) {
+	if ruleNode.ApiRuleNode == nil || ruleNode.ApiRuleNode.KeepFiringFor == nil {
+		if ruleNode.GrafanaManagedAlert.UID != "" {
+			return -1, nil // will be patched later with the real value of the current version of the rule
+		}
+		return 0, nil // if it's a new rule, use the 0 as the default
+	}
+	duration := time.Duration(*ruleNode.ApiRuleNode.KeepFiringFor)
+	if duration < 0 {
+		return 0, fmt.Errorf("field `keep_firing_for` cannot be negative [%v]. 0 or any positive duration are allowed", *ruleNode.ApiRuleNode.KeepFiringFor)
+	}
+	return duration, nil
+}
+
 // validateRuleGroup validates API model (definitions.PostableRuleGroupConfig) and converts it to a collection of models.AlertRule.
 // Returns a slice that contains all rules described by API model or error if either group specification or an alert definition is not valid.
 func validateRuleGroup(
@@ -154,7 +206,7 @@ func validateRuleGroup(
 	orgId int64,
 	namespace *folder.Folder,
 	conditionValidator func(ngmodels.Condition) error,
-	cfg *setting.UnifiedAlertingSettings) ([]*ngmodels.AlertRule, error) {
+	cfg *setting.UnifiedAlertingSettings) ([]*ngmodels.AlertRuleWithOptionals, error) {
 	if ruleGroupConfig.Name == "" {
 		return nil, errors.New("rule group name cannot be empty")
 	}
@@ -175,7 +227,7 @@ func validateRuleGroup(
 
 	// TODO should we validate that interval is >= cfg.MinInterval? Currently, we allow to save but fix the specified interval if it is < cfg.MinInterval
 
-	result := make([]*ngmodels.AlertRule, 0, len(ruleGroupConfig.Rules))
+	result := make([]*ngmodels.AlertRuleWithOptionals, 0, len(ruleGroupConfig.Rules))
 	uids := make(map[string]int, cap(result))
 	for idx := range ruleGroupConfig.Rules {
 		rule, err := validateRuleNode(&ruleGroupConfig.Rules[idx], ruleGroupConfig.Name, interval, orgId, namespace, conditionValidator, cfg)
@@ -190,7 +242,11 @@ func validateRuleGroup(
 			uids[rule.UID] = idx
 		}
 		rule.RuleGroupIndex = idx + 1
-		result = append(result, rule)
+		result = append(result, &ngmodels.AlertRuleWithOptionals{
+			AlertRule: *rule,
+			// rules without is_paused keep the pause of their current version
+			HasPause: ruleGroupConfig.Rules[idx].GrafanaManagedAlert.IsPaused != nil,
+		})
 	}
 	return result, nil
 }
@@ -1,6 +1,8 @@
 package api
 
 import (
+	"context"
+	"encoding/json"
 	"errors"
 	"fmt"
 	"strconv"
@@ -15,6 +17,7 @@ import (
 	apimodels "github.com/grafana/grafana/pkg/services/ngalert/api/tooling/definitions"
 	"github.com/grafana/grafana/pkg/services/ngalert/models"
 	"github.com/grafana/grafana/pkg/services/ngalert/store"
+	"github.com/grafana/grafana/pkg/services/ngalert/tests/fakes"
 	"github.com/grafana/grafana/pkg/setting"
 	"github.com/grafana/grafana/pkg/util"
 )
@@ -36,6 +39,7 @@ func config(t *testing.T) *setting.UnifiedAlertingSettings {
 	result := &setting.UnifiedAlertingSettings{
 		BaseInterval:                  baseInterval,
 		DefaultRuleEvaluationInterval: baseInterval * time.Duration(rand.Intn(9)+1),
+		RecordingRules:                setting.UnifiedAlertingRecordingRuleSettings{Enabled: true},
 	}
 	t.Logf("Config Base interval is [%v]", result.BaseInterval)
 	return result
@@ -130,6 +134,42 @@ func TestValidateRuleGroup(t *testing.T) {
 			require.Equal(t, int64(cfg.DefaultRuleEvaluationInterval.Seconds()), alert.IntervalSeconds)
 		}
 	})
+	t.Run("should keep paused rules paused when updated without is_paused", func(t *testing.T) {
+		paused := validRule()
+		isPaused := true
+		paused.GrafanaManagedAlert.IsPaused = &isPaused
+		paused.GrafanaManagedAlert.Data[0].Model = json.RawMessage(`{}`)
+		g := validGroup(cfg, paused)
+		alerts, err := validateRuleGroup(&g, orgId, folder, func(condition models.Condition) error {
+			return nil
+		}, cfg)
+		require.NoError(t, err)
+		require.True(t, alerts[0].HasPause)
+
+		ruleStore := fakes.NewRuleStore(t)
+		ruleStore.PutRule(context.Background(), &alerts[0].AlertRule)
+
+		updated := paused
+		updated.GrafanaManagedAlert = &apimodels.PostableGrafanaRule{
+			UID:       paused.GrafanaManagedAlert.UID,
+			Title:     "updated title",
+			Condition: paused.GrafanaManagedAlert.Condition,
+			Data:      paused.GrafanaManagedAlert.Data,
+		}
+		g.Rules = []apimodels.PostableExtendedRuleNode{updated}
+		alerts, err = validateRuleGroup(&g, orgId, folder, func(condition models.Condition) error {
+			return nil
+		}, cfg)
+		require.NoError(t, err)
+		require.False(t, alerts[0].HasPause)
+
+		groupKey := models.AlertRuleGroupKey{OrgID: orgId, NamespaceUID: folder.UID, RuleGroup: g.Name}
+		changes, err := store.CalculateChanges(context.Background(), ruleStore, groupKey, alerts)
+		require.NoError(t, err)
+		require.Len(t, changes.Update, 1)
+		require.Equal(t, "updated title", changes.Update[0].New.Title)
+		require.True(t, changes.Update[0].New.IsPaused)
+	})
 }
 
 func TestValidateRuleGroupFailures(t *testing.T) {
@@ -281,6 +321,65 @@ func TestValidateRuleNode_NoUID(t *testing.T) {
 				require.Equal(t, models.AlertingErrState, alert.ExecErrState)
 			},
 		},
+		{
+			name: "keep firing for is 0 if not specified",
+			rule: func() *apimodels.PostableExtendedRuleNode {
+				r := validRule()
+				r.ApiRuleNode.KeepFiringFor = nil
+				return &r
+			},
+			assert: func(t *testing.T, api *apimodels.PostableExtendedRuleNode, alert *models.AlertRule) {
+				require.Equal(t, time.Duration(0), alert.KeepFiringFor)
+			},
+		},
+		{
+			name: "converts keep firing for",
+			rule: func() *apimodels.PostableExtendedRuleNode {
+				r := validRule()
+				keepFiringFor := model.Duration(5 * time.Minute)
+				r.ApiRuleNode.KeepFiringFor = &keepFiringFor
+				return &r
+			},
+			assert: func(t *testing.T, api *apimodels.PostableExtendedRuleNode, alert *models.AlertRule) {
+				require.Equal(t, 5*time.Minute, alert.KeepFiringFor)
+			},
+		},
+		{
+			name: "is not paused if IsPaused is not specified",
+			rule: func() *apimodels.PostableExtendedRuleNode {
+				r := validRule()
+				r.GrafanaManagedAlert.IsPaused = nil
+				return &r
+			},
+			assert: func(t *testing.T, api *apimodels.PostableExtendedRuleNode, alert *models.AlertRule) {
+				require.False(t, alert.IsPaused)
+			},
+		},
+		{
+			name: "is paused if IsPaused is true",
+			rule: func() *apimodels.PostableExtendedRuleNode {
+				r := validRule()
+				isPaused := true
+				r.GrafanaManagedAlert.IsPaused = &isPaused
+				return &r
+			},
+			assert: func(t *testing.T, api *apimodels.PostableExtendedRuleNode, alert *models.AlertRule) {
+				require.True(t, alert.IsPaused)
+			},
+		},
+		{
+			name: "converts recording rule and uses the recorded query as condition",
+			rule: func() *apimodels.PostableExtendedRuleNode {
+				r := validRule()
+				r.GrafanaManagedAlert.Condition = ""
+				r.GrafanaManagedAlert.Record = &apimodels.Record{Metric: "test_metric", From: "A"}
+				return &r
+			},
+			assert: func(t *testing.T, api *apimodels.PostableExtendedRuleNode, alert *models.AlertRule) {
+				require.Equal(t, &models.Record{Metric: "test_metric", From: "A"}, alert.Record)
+				require.Equal(t, "A", alert.Condition)
+			},
+		},
 		{
 			name: "extracts Dashboard UID and Panel Id from annotations",
 			rule: func() *apimodels.PostableExtendedRuleNode {
@@ -428,6 +527,41 @@ func TestValidateRuleNodeFailures_NoUID(t *testing.T) {
 				return &r
 			},
 		},
+		{
+			name: "fail if keep firing for is negative",
+			rule: func() *apimodels.PostableExtendedRuleNode {
+				r := validRule()
+				keepFiringFor := model.Duration(-time.Minute)
+				r.ApiRuleNode.KeepFiringFor = &keepFiringFor
+				return &r
+			},
+		},
+		{
+			name: "fail if metric name of recording rule is not valid",
+			rule: func() *apimodels.PostableExtendedRuleNode {
+				r := validRule()
+				r.GrafanaManagedAlert.Record = &apimodels.Record{Metric: "test metric", From: "A"}
+				return &r
+			},
+		},
+		{
+			name: "fail if recording rule records a query that does not exist",
+			rule: func() *apimodels.PostableExtendedRuleNode {
+				r := validRule()
+				r.GrafanaManagedAlert.Condition = ""
+				r.GrafanaManagedAlert.Record = &apimodels.Record{Metric: "test_metric", From: "B"}
+				return &r
+			},
+		},
+		{
+			name: "fail if condition of recording rule is not the recorded query",
+			rule: func() *apimodels.PostableExtendedRuleNode {
+				r := validRule()
+				r.GrafanaManagedAlert.Condition = "B"
+				r.GrafanaManagedAlert.Record = &apimodels.Record{Metric: "test_metric", From: "A"}
+				return &r
+			},
+		},
 		{
 			name: "fail if PanelID is specified but not Dashboard UID ",
 			rule: func() *apimodels.PostableExtendedRuleNode {
@@ -465,6 +599,24 @@ func TestValidateRuleNodeFailures_NoUID(t *testing.T) {
 	}
 }
 
+func TestValidateRuleNode_RecordingRulesDisabled(t *testing.T) {
+	cfg := config(t)
+	cfg.RecordingRules.Enabled = false
+	successValidation := func(condition models.Condition) error {
+		return nil
+	}
+
+	r := validRule()
+	r.GrafanaManagedAlert.Record = &apimodels.Record{Metric: "test_metric", From: "A"}
+	_, err := validateRuleNode(&r, "", cfg.BaseInterval, rand.Int63(), randFolder(), successValidation, cfg)
+	require.ErrorIs(t, err, models.ErrRecordingRulesDisabled)
+	require.ErrorIs(t, err, models.ErrAlertRuleFailedValidation)
+
+	r = validRule()
+	_, err = validateRuleNode(&r, "", cfg.BaseInterval, rand.Int63(), randFolder(), successValidation, cfg)
+	require.NoError(t, err)
+}
+
 func TestValidateRuleNode_UID(t *testing.T) {
 	orgId := rand.Int63()
 	folder := randFolder()
@@ -1,10 +1,12 @@
 package api
 
 import (
+	"errors"
 	"fmt"
 	"net/http"
 	"net/url"
 	"strconv"
+	"time"
 
 	"github.com/grafana/grafana-plugin-sdk-go/data"
 
@@ -14,8 +16,10 @@ import (
 	"github.com/grafana/grafana/pkg/services/accesscontrol"
 	"github.com/grafana/grafana/pkg/services/datasources"
 	apimodels "github.com/grafana/grafana/pkg/services/ngalert/api/tooling/definitions"
+	"github.com/grafana/grafana/pkg/services/ngalert/backtesting"
 	"github.com/grafana/grafana/pkg/services/ngalert/eval"
 	ngmodels "github.com/grafana/grafana/pkg/services/ngalert/models"
+	"github.com/grafana/grafana/pkg/setting"
 	"github.com/grafana/grafana/pkg/util"
 )
 
@@ -25,6 +29,8 @@ type TestingApiSrv struct {
 	log             log.Logger
 	accessControl   accesscontrol.AccessControl
 	evaluator       eval.EvaluatorFactory
+	cfg             *setting.UnifiedAlertingSettings
+	backtesting     *backtesting.Engine
 }
 
 func (srv TestingApiSrv) RouteTestGrafanaRuleConfig(c *models.ReqContext, body apimodels.TestRulePayload) response.Response {
@@ -136,3 +142,69 @@ func (srv TestingApiSrv) RouteEvalQueries(c *models.ReqContext, cmd apimodels.Ev
 
 	return response.JSONStreaming(http.StatusOK, evalResults)
 }
+
+func (srv TestingApiSrv) BacktestAlertRule(c *models.ReqContext, cmd apimodels.BacktestConfig) response.Response {
+	if !authorizeDatasourceAccessForRule(&ngmodels.AlertRule{Data: cmd.Data}, func(evaluator accesscontrol.Evaluator) bool {
+		return accesscontrol.HasAccess(srv.accessControl, c)(accesscontrol.ReqSignedIn, evaluator)
+	}) {
+		return errorToResponse(fmt.Errorf("%w to query one or many data sources used by the rule", ErrAuthorization))
+	}
+
+	intervalSeconds := int64(srv.cfg.DefaultRuleEvaluationInterval.Seconds())
+	if cmd.Interval != 0 {
+		intervalSeconds = int64(time.Duration(cmd.Interval).Seconds())
+	}
+	if err := ngmodels.ValidateRuleGroupInterval(intervalSeconds, int64(srv.cfg.BaseInterval.Seconds())); err != nil {
+		return ErrResp(http.StatusBadRequest, err, "")
+	}
+
+	if cmd.For < 0 {
+		return ErrResp(http.StatusBadRequest, fmt.Errorf("field `for` cannot be negative [%v]", cmd.For), "")
+	}
+	if cmd.KeepFiringFor < 0 {
+		return ErrResp(http.StatusBadRequest, fmt.Errorf("field `keep_firing_for` cannot be negative [%v]", cmd.KeepFiringFor), "")
+	}
+
+	noDataState := ngmodels.NoData
+	if cmd.NoDataState != "" {
+		var err error
+		noDataState, err = ngmodels.NoDataStateFromString(string(cmd.NoDataState))
+		if err != nil {
+			return ErrResp(http.StatusBadRequest, err, "")
+		}
+	}
+	errorState := ngmodels.AlertingErrState
+	if cmd.ExecErrState != "" {
+		var err error
+		errorState, err = ngmodels.ErrStateFromString(string(cmd.ExecErrState))
+		if err != nil {
+			return ErrResp(http.StatusBadRequest, err, "")
+		}
+	}
+
+	rule := &ngmodels.AlertRule{
+		OrgID:           c.OrgID,
+		Title:           cmd.Title,
+		Condition:       cmd.Condition,
+		Data:            cmd.Data,
+		IntervalSeconds: intervalSeconds,
+		// the rule is never saved, this UID is only used to identify its states in the throwaway state manager
+		UID:           "backtesting-" + util.GenerateShortUID(),
+		NoDataState:   noDataState,
+		ExecErrState:  errorState,
+		For:           time.Duration(cmd.For),
+		KeepFiringFor: time.Duration(cmd.KeepFiringFor),
+		Annotations:   cmd.Annotations,
+		Labels:        cmd.Labels,
+	}
+
+	result, err := srv.backtesting.Test(c.Req.Context(), c.SignedInUser, rule, cmd.From, cmd.To)
+	if err != nil {
+		if errors.Is(err, backtesting.ErrInvalidInputData) {
+			return ErrResp(http.StatusBadRequest, err, "")
+		}
+		return ErrResp(http.StatusInternalServerError, err, "Failed to evaluate")
+	}
+
+	return response.JSON(http.StatusOK, result)
+}
@@ -6,6 +6,8 @@ import (
 	"time"
 
 	"github.com/grafana/grafana-plugin-sdk-go/backend"
+	"github.com/grafana/grafana-plugin-sdk-go/data"
+	"github.com/prometheus/common/model"
 	"github.com/stretchr/testify/mock"
 	"github.com/stretchr/testify/require"
 
@@ -15,10 +17,12 @@ import (
 	"github.com/grafana/grafana/pkg/services/datasources"
 	fakes "github.com/grafana/grafana/pkg/services/datasources/fakes"
 	"github.com/grafana/grafana/pkg/services/ngalert/api/tooling/definitions"
+	"github.com/grafana/grafana/pkg/services/ngalert/backtesting"
 	"github.com/grafana/grafana/pkg/services/ngalert/eval"
 	"github.com/grafana/grafana/pkg/services/ngalert/eval/eval_mocks"
 	"github.com/grafana/grafana/pkg/services/ngalert/models"
 	"github.com/grafana/grafana/pkg/services/user"
+	"github.com/grafana/grafana/pkg/setting"
 	"github.com/grafana/grafana/pkg/web"
 )
 
@@ -277,6 +281,125 @@ func TestRouteEvalQueries(t *testing.T) {
 	})
 }
 
+func TestRouteBacktest(t *testing.T) {
+	rc := &models2.ReqContext{
+		Context: &web.Context{
+			Req: &http.Request{},
+		},
+		IsSignedIn: true,
+		SignedInUser: &user.SignedInUser{
+			OrgID: 1,
+		},
+	}
+	from := time.Unix(0, 0)
+
+	validConfig := func(data ...models.AlertQuery) definitions.BacktestConfig {
+		return definitions.BacktestConfig{
+			From:      from,
+			To:        from.Add(time.Minute),
+			Interval:  model.Duration(10 * time.Second),
+			Condition: data[0].RefID,
+			Data:      data,
+			Title:     "test",
+		}
+	}
+
+	t.Run("should return 401 if user cannot query a data source", func(t *testing.T) {
+		data1 := models.GenerateAlertQuery()
+		data2 := models.GenerateAlertQuery()
+
+		ac := acMock.New().WithPermissions([]accesscontrol.Permission{
+			{Action: datasources.ActionQuery, Scope: datasources.ScopeProvider.GetResourceScopeUID(data1.DatasourceUID)},
+		})
+
+		srv := createTestingApiSrv(nil, ac, nil)
+
+		response := srv.BacktestAlertRule(rc, validConfig(data1, data2))
+
+		require.Equal(t, http.StatusUnauthorized, response.Status())
+	})
+
+	t.Run("should return 400 if the interval is not a multiple of the base interval", func(t *testing.T) {
+		data1 := models.GenerateAlertQuery()
+		cmd := validConfig(data1)
+		cmd.Interval = model.Duration(15 * time.Second)
+
+		srv := createTestingApiSrv(nil, nil, nil)
+
+		response := srv.BacktestAlertRule(rc, cmd)
+
+		require.Equal(t, http.StatusBadRequest, response.Status())
+	})
+
+	t.Run("should return 400 if the no data state is not valid", func(t *testing.T) {
+		data1 := models.GenerateAlertQuery()
+		cmd := validConfig(data1)
+		cmd.NoDataState = "invalid"
+
+		srv := createTestingApiSrv(nil, nil, nil)
+
+		response := srv.BacktestAlertRule(rc, cmd)
+
+		require.Equal(t, http.StatusBadRequest, response.Status())
+	})
+
+	t.Run("should return 400 if for is negative", func(t *testing.T) {
+		data1 := models.GenerateAlertQuery()
+		cmd := validConfig(data1)
+		cmd.For = model.Duration(-time.Minute)
+
+		srv := createTestingApiSrv(nil, nil, nil)
+
+		response := srv.BacktestAlertRule(rc, cmd)
+
+		require.Equal(t, http.StatusBadRequest, response.Status())
+	})
+
+	t.Run("should return 400 if keep firing for is negative", func(t *testing.T) {
+		data1 := models.GenerateAlertQuery()
+		cmd := validConfig(data1)
+		cmd.KeepFiringFor = model.Duration(-time.Minute)
+
+		srv := createTestingApiSrv(nil, nil, nil)
+
+		response := srv.BacktestAlertRule(rc, cmd)
+
+		require.Equal(t, http.StatusBadRequest, response.Status())
+	})
+
+	t.Run("should return 400 if the range is not valid", func(t *testing.T) {
+		data1 := models.GenerateAlertQuery()
+		cmd := validConfig(data1)
+		cmd.To = cmd.From
+
+		evaluator := &eval_mocks.ConditionEvaluatorMock{}
+		srv := createTestingApiSrv(nil, nil, eval_mocks.NewEvaluatorFactory(evaluator))
+
+		response := srv.BacktestAlertRule(rc, cmd)
+
+		require.Equal(t, http.StatusBadRequest, response.Status())
+		evaluator.AssertNotCalled(t, "Evaluate", mock.Anything, mock.Anything)
+	})
+
+	t.Run("should evaluate the rule at every interval", func(t *testing.T) {
+		data1 := models.GenerateAlertQuery()
+
+		evaluator := &eval_mocks.ConditionEvaluatorMock{}
+		evaluator.EXPECT().Evaluate(mock.Anything, mock.Anything).Return(eval.Results{
+			{Instance: data.Labels{"instance": "a"}, State: eval.Alerting},
+		}, nil)
+
+		srv := createTestingApiSrv(nil, nil, eval_mocks.NewEvaluatorFactory(evaluator))
+
+		response := srv.BacktestAlertRule(rc, validConfig(data1))
+
+		require.Equal(t, http.StatusOK, response.Status())
+		evaluator.AssertNumberOfCalls(t, "Evaluate", 7)
+		evaluator.AssertCalled(t, "Evaluate", mock.Anything, from)
+		evaluator.AssertCalled(t, "Evaluate", mock.Anything, from.Add(time.Minute))
+	})
+}
+
 func createTestingApiSrv(ds *fakes.FakeCacheService, ac *acMock.Mock, evaluator eval.EvaluatorFactory) *TestingApiSrv {
 	if ac == nil {
 		ac = acMock.New().WithDisabled()
@@ -286,5 +409,10 @@ func createTestingApiSrv(ds *fakes.FakeCacheService, ac *acMock.Mock, evaluator
 		DatasourceCache: ds,
 		accessControl:   ac,
 		evaluator:       evaluator,
+		cfg: &setting.UnifiedAlertingSettings{
+			BaseInterval:                  10 * time.Second,
+			DefaultRuleEvaluationInterval: time.Minute,
+		},
+		backtesting: backtesting.NewEngine(evaluator),
 	}
 }
@@ -178,6 +178,22 @@ func (f *AlertmanagerApiHandler) handleRouteGetGrafanaReceivers(ctx *models.ReqC
 	return f.GrafanaSvc.RouteGetReceivers(ctx)
 }
 
+func (f *AlertmanagerApiHandler) handleRouteGetGrafanaNotificationHistory(ctx *models.ReqContext) response.Response {
+	return f.GrafanaSvc.RouteGetNotificationHistory(ctx)
+}
+
+func (f *AlertmanagerApiHandler) handleRouteGetGrafanaNotificationDeadLetters(ctx *models.ReqContext) response.Response {
+	return f.GrafanaSvc.RouteGetNotificationDeadLetters(ctx)
+}
+
+func (f *AlertmanagerApiHandler) handleRoutePostGrafanaNotificationDeadLetterReplay(ctx *models.ReqContext, id string) response.Response {
+	return f.GrafanaSvc.RoutePostNotificationDeadLetterReplay(ctx, id)
+}
+
+func (f *AlertmanagerApiHandler) handleRouteDeleteGrafanaNotificationDeadLetter(ctx *models.ReqContext, id string) response.Response {
+	return f.GrafanaSvc.RouteDeleteNotificationDeadLetter(ctx, id)
+}
+
 func (f *AlertmanagerApiHandler) handleRoutePostTestGrafanaReceivers(ctx *models.ReqContext, conf apimodels.TestReceiversConfigBodyParams) response.Response {
 	return f.GrafanaSvc.RoutePostTestReceivers(ctx, conf)
 }
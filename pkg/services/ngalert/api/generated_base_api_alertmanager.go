@@ -23,6 +23,7 @@ type AlertmanagerApi interface {
 	RouteCreateSilence(*models.ReqContext) response.Response
 	RouteDeleteAlertingConfig(*models.ReqContext) response.Response
 	RouteDeleteGrafanaAlertingConfig(*models.ReqContext) response.Response
+	RouteDeleteGrafanaNotificationDeadLetter(*models.ReqContext) response.Response
 	RouteDeleteGrafanaSilence(*models.ReqContext) response.Response
 	RouteDeleteSilence(*models.ReqContext) response.Response
 	RouteGetAMAlertGroups(*models.ReqContext) response.Response
@@ -33,6 +34,8 @@ type AlertmanagerApi interface {
 	RouteGetGrafanaAMAlerts(*models.ReqContext) response.Response
 	RouteGetGrafanaAMStatus(*models.ReqContext) response.Response
 	RouteGetGrafanaAlertingConfig(*models.ReqContext) response.Response
+	RouteGetGrafanaNotificationDeadLetters(*models.ReqContext) response.Response
+	RouteGetGrafanaNotificationHistory(*models.ReqContext) response.Response
 	RouteGetGrafanaReceivers(*models.ReqContext) response.Response
 	RouteGetGrafanaSilence(*models.ReqContext) response.Response
 	RouteGetGrafanaSilences(*models.ReqContext) response.Response
@@ -41,6 +44,7 @@ type AlertmanagerApi interface {
 	RoutePostAMAlerts(*models.ReqContext) response.Response
 	RoutePostAlertingConfig(*models.ReqContext) response.Response
 	RoutePostGrafanaAlertingConfig(*models.ReqContext) response.Response
+	RoutePostGrafanaNotificationDeadLetterReplay(*models.ReqContext) response.Response
 	RoutePostTestGrafanaReceivers(*models.ReqContext) response.Response
 }
 
@@ -70,6 +74,11 @@ func (f *AlertmanagerApiHandler) RouteDeleteAlertingConfig(ctx *models.ReqContex
 func (f *AlertmanagerApiHandler) RouteDeleteGrafanaAlertingConfig(ctx *models.ReqContext) response.Response {
 	return f.handleRouteDeleteGrafanaAlertingConfig(ctx)
 }
+func (f *AlertmanagerApiHandler) RouteDeleteGrafanaNotificationDeadLetter(ctx *models.ReqContext) response.Response {
+	// Parse Path Parameters
+	deadLetterIDParam := web.Params(ctx.Req)[":DeadLetterID"]
+	return f.handleRouteDeleteGrafanaNotificationDeadLetter(ctx, deadLetterIDParam)
+}
 func (f *AlertmanagerApiHandler) RouteDeleteGrafanaSilence(ctx *models.ReqContext) response.Response {
 	// Parse Path Parameters
 	silenceIdParam := web.Params(ctx.Req)[":SilenceId"]
@@ -113,6 +122,12 @@ func (f *AlertmanagerApiHandler) RouteGetGrafanaAMStatus(ctx *models.ReqContext)
 func (f *AlertmanagerApiHandler) RouteGetGrafanaAlertingConfig(ctx *models.ReqContext) response.Response {
 	return f.handleRouteGetGrafanaAlertingConfig(ctx)
 }
+func (f *AlertmanagerApiHandler) RouteGetGrafanaNotificationDeadLetters(ctx *models.ReqContext) response.Response {
+	return f.handleRouteGetGrafanaNotificationDeadLetters(ctx)
+}
+func (f *AlertmanagerApiHandler) RouteGetGrafanaNotificationHistory(ctx *models.ReqContext) response.Response {
+	return f.handleRouteGetGrafanaNotificationHistory(ctx)
+}
 func (f *AlertmanagerApiHandler) RouteGetGrafanaReceivers(ctx *models.ReqContext) response.Response {
 	return f.handleRouteGetGrafanaReceivers(ctx)
 }
@@ -163,6 +178,11 @@ func (f *AlertmanagerApiHandler) RoutePostGrafanaAlertingConfig(ctx *models.ReqC
 	}
 	return f.handleRoutePostGrafanaAlertingConfig(ctx, conf)
 }
+func (f *AlertmanagerApiHandler) RoutePostGrafanaNotificationDeadLetterReplay(ctx *models.ReqContext) response.Response {
+	// Parse Path Parameters
+	deadLetterIDParam := web.Params(ctx.Req)[":DeadLetterID"]
+	return f.handleRoutePostGrafanaNotificationDeadLetterReplay(ctx, deadLetterIDParam)
+}
 func (f *AlertmanagerApiHandler) RoutePostTestGrafanaReceivers(ctx *models.ReqContext) response.Response {
 	// Parse Request Body
 	conf := apimodels.TestReceiversConfigBodyParams{}
@@ -214,6 +234,16 @@ func (api *API) RegisterAlertmanagerApiEndpoints(srv AlertmanagerApi, m *metrics
 				m,
 			),
 		)
+		group.Delete(
+			toMacaronPath("/api/alertmanager/grafana/api/v1/notifications/dead-letters/{DeadLetterID}"),
+			api.authorize(http.MethodDelete, "/api/alertmanager/grafana/api/v1/notifications/dead-letters/{DeadLetterID}"),
+			metrics.Instrument(
+				http.MethodDelete,
+				"/api/alertmanager/grafana/api/v1/notifications/dead-letters/{DeadLetterID}",
+				srv.RouteDeleteGrafanaNotificationDeadLetter,
+				m,
+			),
+		)
 		group.Delete(
 			toMacaronPath("/api/alertmanager/grafana/api/v2/silence/{SilenceId}"),
 			api.authorize(http.MethodDelete, "/api/alertmanager/grafana/api/v2/silence/{SilenceId}"),
@@ -314,6 +344,26 @@ func (api *API) RegisterAlertmanagerApiEndpoints(srv AlertmanagerApi, m *metrics
 				m,
 			),
 		)
+		group.Get(
+			toMacaronPath("/api/alertmanager/grafana/api/v1/notifications/dead-letters"),
+			api.authorize(http.MethodGet, "/api/alertmanager/grafana/api/v1/notifications/dead-letters"),
+			metrics.Instrument(
+				http.MethodGet,
+				"/api/alertmanager/grafana/api/v1/notifications/dead-letters",
+				srv.RouteGetGrafanaNotificationDeadLetters,
+				m,
+			),
+		)
+		group.Get(
+			toMacaronPath("/api/alertmanager/grafana/api/v1/notifications"),
+			api.authorize(http.MethodGet, "/api/alertmanager/grafana/api/v1/notifications"),
+			metrics.Instrument(
+				http.MethodGet,
+				"/api/alertmanager/grafana/api/v1/notifications",
+				srv.RouteGetGrafanaNotificationHistory,
+				m,
+			),
+		)
 		group.Get(
 			toMacaronPath("/api/alertmanager/grafana/config/api/v1/receivers"),
 			api.authorize(http.MethodGet, "/api/alertmanager/grafana/config/api/v1/receivers"),
@@ -394,6 +444,16 @@ func (api *API) RegisterAlertmanagerApiEndpoints(srv AlertmanagerApi, m *metrics
 				m,
 			),
 		)
+		group.Post(
+			toMacaronPath("/api/alertmanager/grafana/api/v1/notifications/dead-letters/{DeadLetterID}/replay"),
+			api.authorize(http.MethodPost, "/api/alertmanager/grafana/api/v1/notifications/dead-letters/{DeadLetterID}/replay"),
+			metrics.Instrument(
+				http.MethodPost,
+				"/api/alertmanager/grafana/api/v1/notifications/dead-letters/{DeadLetterID}/replay",
+				srv.RoutePostGrafanaNotificationDeadLetterReplay,
+				m,
+			),
+		)
 		group.Post(
 			toMacaronPath("/api/alertmanager/grafana/config/api/v1/receivers/test"),
 			api.authorize(http.MethodPost, "/api/alertmanager/grafana/config/api/v1/receivers/test"),
@@ -0,0 +1,21 @@
+package api
+
+import (
+	"github.com/grafana/grafana/pkg/api/response"
+	"github.com/grafana/grafana/pkg/models"
+)
+
+// HistoryApiHandler always forwards requests to grafana backend
+type HistoryApiHandler struct {
+	svc *HistorySrv
+}
+
+func NewStateHistoryApi(svc *HistorySrv) *HistoryApiHandler {
+	return &HistoryApiHandler{
+		svc: svc,
+	}
+}
+
+func (f *HistoryApiHandler) handleRouteGetStateHistory(ctx *models.ReqContext) response.Response {
+	return f.svc.RouteQueryStateHistory(ctx)
+}
@@ -155,6 +155,41 @@ import (
 //       408: Failure
 //       409: AlertManagerNotReady
 
+// swagger:route GET /api/alertmanager/grafana/api/v1/notifications alertmanager RouteGetGrafanaNotificationHistory
+//
+// Get the history of notifications sent by the integrations of Grafana managed receivers
+//
+//     Responses:
+//       200: NotificationHistory
+//       400: ValidationError
+
+// swagger:route GET /api/alertmanager/grafana/api/v1/notifications/dead-letters alertmanager RouteGetGrafanaNotificationDeadLetters
+//
+// Get the notifications that integrations of Grafana managed receivers failed to send after all retries
+//
+//     Responses:
+//       200: NotificationDeadLetters
+//       400: ValidationError
+
+// swagger:route POST /api/alertmanager/grafana/api/v1/notifications/dead-letters/{DeadLetterID}/replay alertmanager RoutePostGrafanaNotificationDeadLetterReplay
+//
+// Send a notification that failed after all retries once more, and delete it if it is sent
+//
+//     Responses:
+//       200: Ack
+//       400: ValidationError
+//       404: NotFound
+//       502: Failure
+
+// swagger:route DELETE /api/alertmanager/grafana/api/v1/notifications/dead-letters/{DeadLetterID} alertmanager RouteDeleteGrafanaNotificationDeadLetter
+//
+// Delete a notification that failed after all retries
+//
+//     Responses:
+//       200: Ack
+//       400: ValidationError
+//       404: NotFound
+
 // swagger:route GET /api/alertmanager/grafana/api/v2/silences alertmanager RouteGetGrafanaSilences
 //
 // get silences
@@ -403,6 +438,106 @@ type ReceiversResponse struct {
 // swagger:model integration
 type Integration = amv2.Integration
 
+// swagger:parameters RouteGetGrafanaNotificationHistory
+type NotificationHistoryParams struct {
+	// Name of the receiver to filter notifications by
+	// in: query
+	// required: false
+	Receiver string `json:"receiver"`
+
+	// Type of the integration to filter notifications by, for example slack
+	// in: query
+	// required: false
+	Integration string `json:"integration"`
+
+	// Status to filter notifications by
+	// in: query
+	// required: false
+	// enum: success,failed
+	Status string `json:"status"`
+
+	// Unix timestamp in seconds of the beginning of the time range
+	// in: query
+	// required: false
+	From int64 `json:"from"`
+
+	// Unix timestamp in seconds of the end of the time range
+	// in: query
+	// required: false
+	To int64 `json:"to"`
+
+	// Maximum number of notifications to return, up to 1000
+	// in: query
+	// required: false
+	// default: 100
+	Limit int `json:"limit"`
+}
+
+// swagger:model
+type NotificationHistory []NotificationHistoryEntry
+
+// NotificationHistoryEntry is an attempt of an integration of a receiver to send a notification for an alert group.
+// swagger:model
+type NotificationHistoryEntry struct {
+	Receiver         string `json:"receiver"`
+	Integration      string `json:"integration"`
+	IntegrationIndex int    `json:"integrationIndex"`
+	GroupKey         string `json:"groupKey"`
+	Status           string `json:"status"`
+	Error            string `json:"error,omitempty"`
+	// Duration of the attempt, including retries, in milliseconds.
+	Duration       int64     `json:"duration"`
+	Retries        int       `json:"retries"`
+	FiringAlerts   int       `json:"firingAlerts"`
+	ResolvedAlerts int       `json:"resolvedAlerts"`
+	Timestamp      time.Time `json:"timestamp"`
+}
+
+// swagger:parameters RouteGetGrafanaNotificationDeadLetters
+type NotificationDeadLettersParams struct {
+	// Name of the receiver to filter notifications by
+	// in: query
+	// required: false
+	Receiver string `json:"receiver"`
+
+	// Maximum number of notifications to return, up to 1000
+	// in: query
+	// required: false
+	// default: 100
+	Limit int `json:"limit"`
+}
+
+// swagger:parameters RoutePostGrafanaNotificationDeadLetterReplay RouteDeleteGrafanaNotificationDeadLetter
+type NotificationDeadLetterParams struct {
+	// in:path
+	DeadLetterID int64
+}
+
+// swagger:model
+type NotificationDeadLetters []NotificationDeadLetter
+
+// NotificationDeadLetter is a notification that an integration of a receiver failed to send after all retries.
+// swagger:model
+type NotificationDeadLetter struct {
+	ID               int64                         `json:"id"`
+	Receiver         string                        `json:"receiver"`
+	Integration      string                        `json:"integration"`
+	IntegrationIndex int                           `json:"integrationIndex"`
+	GroupKey         string                        `json:"groupKey"`
+	GroupLabels      model.LabelSet                `json:"groupLabels"`
+	Alerts           []NotificationDeadLetterAlert `json:"alerts"`
+	Error            string                        `json:"error"`
+	Timestamp        time.Time                     `json:"timestamp"`
+}
+
+// NotificationDeadLetterAlert is an alert of a notification that failed after all retries.
+type NotificationDeadLetterAlert struct {
+	Labels      model.LabelSet `json:"labels"`
+	Annotations model.LabelSet `json:"annotations"`
+	StartsAt    time.Time      `json:"startsAt"`
+	EndsAt      time.Time      `json:"endsAt"`
+}
+
 // swagger:parameters RouteGetAMAlerts RouteGetAMAlertGroups RouteGetGrafanaAMAlerts RouteGetGrafanaAMAlertGroups
 type AlertsParams struct {
 
@@ -971,6 +1106,7 @@ type GettableGrafanaReceiver struct {
 	DisableResolveMessage bool              `json:"disableResolveMessage"`
 	Settings              *simplejson.Json  `json:"settings"`
 	SecureFields          map[string]bool   `json:"secureFields"`
+	RetryPolicy           *RetryPolicy      `json:"retryPolicy,omitempty"`
 	Provenance            models.Provenance `json:"provenance,omitempty"`
 }
 
@@ -981,6 +1117,35 @@ type PostableGrafanaReceiver struct {
 	DisableResolveMessage bool              `json:"disableResolveMessage"`
 	Settings              *simplejson.Json  `json:"settings"`
 	SecureSettings        map[string]string `json:"secureSettings"`
+	RetryPolicy           *RetryPolicy      `json:"retryPolicy,omitempty"`
+}
+
+// RetryPolicy is the policy to retry the failed notifications of an integration with exponential backoff.
+type RetryPolicy struct {
+	// MaxAttempts is the maximum number of attempts to send a notification, including the first attempt.
+	MaxAttempts int `json:"maxAttempts"`
+	// InitialInterval is the time to wait before the first retry. The time doubles for each retry.
+	// If empty, the default from the configuration of Grafana is used.
+	InitialInterval model.Duration `json:"initialInterval,omitempty"`
+	// MaxInterval is the maximum time to wait between retries. If empty, the default from the
+	// configuration of Grafana is used.
+	MaxInterval model.Duration `json:"maxInterval,omitempty"`
+}
+
+func (p *RetryPolicy) Validate() error {
+	if p.MaxAttempts < 1 {
+		return fmt.Errorf("maxAttempts must be at least 1")
+	}
+	if p.InitialInterval < 0 {
+		return fmt.Errorf("initialInterval must not be negative")
+	}
+	if p.MaxInterval < 0 {
+		return fmt.Errorf("maxInterval must not be negative")
+	}
+	if p.InitialInterval > 0 && p.MaxInterval > 0 && p.MaxInterval < p.InitialInterval {
+		return fmt.Errorf("maxInterval must be greater than or equal to initialInterval")
+	}
+	return nil
 }
 
 type ReceiverType int
@@ -2,6 +2,7 @@ package definitions
 
 import (
 	"testing"
+	"time"
 
 	"github.com/prometheus/alertmanager/config"
 	"github.com/prometheus/alertmanager/timeinterval"
@@ -365,3 +366,48 @@ func TestValidateMuteTimeInterval(t *testing.T) {
 		}
 	})
 }
+
+func TestValidateRetryPolicy(t *testing.T) {
+	type testCase struct {
+		desc   string
+		policy RetryPolicy
+		expErr string
+	}
+
+	cases := []testCase{
+		{
+			desc:   "valid policy",
+			policy: RetryPolicy{MaxAttempts: 3, InitialInterval: model.Duration(time.Second), MaxInterval: model.Duration(time.Minute)},
+		},
+		{
+			desc:   "valid policy without intervals",
+			policy: RetryPolicy{MaxAttempts: 1},
+		},
+		{
+			desc:   "zero max attempts",
+			policy: RetryPolicy{},
+			expErr: "maxAttempts must be at least 1",
+		},
+		{
+			desc:   "negative initial interval",
+			policy: RetryPolicy{MaxAttempts: 3, InitialInterval: model.Duration(-time.Second)},
+			expErr: "initialInterval must not be negative",
+		},
+		{
+			desc:   "max interval less than initial interval",
+			policy: RetryPolicy{MaxAttempts: 3, InitialInterval: model.Duration(time.Minute), MaxInterval: model.Duration(time.Second)},
+			expErr: "maxInterval must be greater than or equal to initialInterval",
+		},
+	}
+
+	for _, c := range cases {
+		t.Run(c.desc, func(t *testing.T) {
+			err := c.policy.Validate()
+			if c.expErr == "" {
+				require.NoError(t, err)
+				return
+			}
+			require.EqualError(t, err, c.expErr)
+		})
+	}
+}
@@ -0,0 +1,49 @@
+package definitions
+
+import (
+	"github.com/grafana/grafana-plugin-sdk-go/data"
+)
+
+// swagger:route GET /api/v1/rules/history history RouteGetStateHistory
+//
+// Query state history.
+//
+//     Produces:
+//     - application/json
+//
+//     Responses:
+//       200: StateHistory
+//       400: ValidationError
+
+// swagger:parameters RouteGetStateHistory
+type HistoryQueryParams struct {
+	// The UID of the rule to get the history of. Required when the state history backend is "annotations".
+	// in: query
+	// required: false
+	RuleUID string `json:"ruleUID"`
+
+	// Start of the time range, in seconds since the epoch. Defaults to one hour before To.
+	// in: query
+	// required: false
+	From int64 `json:"from"`
+
+	// End of the time range, in seconds since the epoch. Defaults to now.
+	// in: query
+	// required: false
+	To int64 `json:"to"`
+
+	// Maximum number of state transitions to return.
+	// in: query
+	// required: false
+	Limit int `json:"limit"`
+
+	// Only return transitions of instances with the given labels, passed as labels_<name>=<value>.
+	// in: query
+	// required: false
+	Labels map[string]string `json:"labels"`
+}
+
+// swagger:model StateHistory
+type StateHistory struct {
+	Results *data.Frame `json:"results"`
+}
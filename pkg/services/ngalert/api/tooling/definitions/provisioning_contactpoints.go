@@ -90,7 +90,8 @@ type EmbeddedContactPoint struct {
 	// required: true
 	Settings *simplejson.Json `json:"settings" binding:"required"`
 	// example: false
-	DisableResolveMessage bool `json:"disableResolveMessage"`
+	DisableResolveMessage bool         `json:"disableResolveMessage"`
+	RetryPolicy           *RetryPolicy `json:"retryPolicy,omitempty"`
 	// readonly: true
 	Provenance string `json:"provenance,omitempty"`
 }
@@ -108,6 +109,11 @@ func (e *EmbeddedContactPoint) Valid(decryptFunc channels.GetDecryptedValueFn) e
 	if !exists {
 		return fmt.Errorf("unknown type '%s'", e.Type)
 	}
+	if e.RetryPolicy != nil {
+		if err := e.RetryPolicy.Validate(); err != nil {
+			return fmt.Errorf("invalid retry policy: %w", err)
+		}
+	}
 	cfg, _ := channels.NewFactoryConfig(&channels.NotificationChannelConfig{
 		Settings: e.Settings,
 		Type:     e.Type,
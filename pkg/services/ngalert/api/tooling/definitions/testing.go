@@ -6,8 +6,10 @@ import (
 	"time"
 
 	"github.com/grafana/grafana-plugin-sdk-go/backend"
+	"github.com/grafana/grafana-plugin-sdk-go/data"
 
 	"github.com/prometheus/alertmanager/config"
+	"github.com/prometheus/common/model"
 	"github.com/prometheus/prometheus/promql"
 
 	"github.com/grafana/grafana/pkg/services/ngalert/models"
@@ -53,6 +55,20 @@ import (
 //     Responses:
 //       200: EvalQueriesResponse
 
+// swagger:route Post /api/v1/rule/backtest testing BacktestConfig
+//
+// Test rule against historical data
+//
+//     Consumes:
+//     - application/json
+//
+//     Produces:
+//     - application/json
+//
+//     Responses:
+//       200: BacktestResult
+//       400: ValidationError
+
 // swagger:parameters RouteTestReceiverConfig
 type TestReceiverRequest struct {
 	// in:body
@@ -85,6 +101,40 @@ type EvalQueriesPayload struct {
 	Now  time.Time           `json:"now"`
 }
 
+// swagger:parameters BacktestConfig
+type BacktestConfigRequest struct {
+	// in:body
+	Body BacktestConfig
+}
+
+// swagger:model
+type BacktestConfig struct {
+	// required: true
+	From time.Time `json:"from"`
+	// required: true
+	To time.Time `json:"to"`
+	// Interval is the interval between evaluations. If it is not set, the default evaluation interval is used.
+	Interval model.Duration `json:"interval,omitempty"`
+
+	// required: true
+	Condition string `json:"condition"`
+	// required: true
+	Data []models.AlertQuery `json:"data"`
+
+	// required: true
+	Title         string              `json:"title"`
+	Labels        map[string]string   `json:"labels,omitempty"`
+	Annotations   map[string]string   `json:"annotations,omitempty"`
+	For           model.Duration      `json:"for,omitempty"`
+	KeepFiringFor model.Duration      `json:"keep_firing_for,omitempty"`
+	NoDataState   NoDataState         `json:"no_data_state"`
+	ExecErrState  ExecutionErrorState `json:"exec_err_state"`
+}
+
+// BacktestResult contains a time field and a field per alert instance with the state of the instance at each evaluation.
+// swagger:model
+type BacktestResult = data.Frame
+
 func (p *TestRulePayload) UnmarshalJSON(b []byte) error {
 	type plain TestRulePayload
 	if err := json.Unmarshal(b, (*plain)(p)); err != nil {
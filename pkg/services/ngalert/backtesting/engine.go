@@ -0,0 +1,130 @@
+package backtesting
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"sort"
+	"time"
+
+	"github.com/benbjohnson/clock"
+	"github.com/grafana/grafana-plugin-sdk-go/data"
+
+	"github.com/grafana/grafana/pkg/services/ngalert/eval"
+	"github.com/grafana/grafana/pkg/services/ngalert/models"
+	"github.com/grafana/grafana/pkg/services/ngalert/schedule"
+	"github.com/grafana/grafana/pkg/services/ngalert/state"
+	"github.com/grafana/grafana/pkg/services/user"
+)
+
+// MaxEvaluations is the maximum number of evaluations a single backtesting run can make.
+const MaxEvaluations = 1000
+
+var ErrInvalidInputData = errors.New("invalid input data")
+
+type stateManager interface {
+	schedule.RuleStatesReader
+	ProcessEvalResults(ctx context.Context, evaluatedAt time.Time, alertRule *models.AlertRule, results eval.Results, extraLabels data.Labels) []state.StateTransition
+}
+
+// Engine evaluates an alert rule over a historical time range, as if it had been scheduled during that time.
+type Engine struct {
+	evalFactory        eval.EvaluatorFactory
+	createStateManager func() stateManager
+}
+
+func NewEngine(evalFactory eval.EvaluatorFactory) *Engine {
+	return &Engine{
+		evalFactory: evalFactory,
+		createStateManager: func() stateManager {
+			// The state manager is not persisted and does not record the history, so the backtesting has no side effects.
+			return state.NewManager(nil, nil, nil, &state.NoopImageService{}, clock.New(), nil)
+		},
+	}
+}
+
+// Test evaluates the rule at every interval of the rule between from and to, and processes the results with a
+// state manager that is discarded afterwards. It returns a data frame with a time field and a field per alert
+// instance that contains the state of the instance at each evaluation.
+func (e *Engine) Test(ctx context.Context, user *user.SignedInUser, rule *models.AlertRule, from, to time.Time) (*data.Frame, error) {
+	interval := time.Duration(rule.IntervalSeconds) * time.Second
+	if interval <= 0 {
+		return nil, fmt.Errorf("%w: interval of the rule must be positive", ErrInvalidInputData)
+	}
+	if !from.Before(to) {
+		return nil, fmt.Errorf("%w: from must be before to", ErrInvalidInputData)
+	}
+	length := int(to.Sub(from)/interval) + 1
+	if length > MaxEvaluations {
+		return nil, fmt.Errorf("%w: the range between from and to is too large for the interval, the rule would be evaluated %d times but the maximum is %d", ErrInvalidInputData, length, MaxEvaluations)
+	}
+
+	stateManager := e.createStateManager()
+	// Like the scheduler, the evaluator is created for every evaluation, so that expressions that depend on the
+	// previous state, such as recovery thresholds, see the results that were alerting at the previous evaluation.
+	evalCtx := eval.NewContextWithPreviousResults(ctx, user, schedule.AlertingResultsFromRuleState{
+		Manager: stateManager,
+		Rule:    rule,
+	})
+	tl := newTimeline(length)
+	for idx := 0; idx < length; idx++ {
+		if err := ctx.Err(); err != nil {
+			return nil, err
+		}
+		ruleEval, err := e.evalFactory.Create(evalCtx, rule.GetEvalCondition())
+		if err != nil {
+			return nil, fmt.Errorf("%w: %s", ErrInvalidInputData, err)
+		}
+		now := from.Add(time.Duration(idx) * interval)
+		results, err := ruleEval.Evaluate(ctx, now)
+		if err != nil {
+			results = eval.Results{eval.NewResultFromError(err, now, 0)}
+		}
+		tl.add(idx, now, stateManager.ProcessEvalResults(ctx, now, rule, results, nil))
+	}
+	return tl.toFrame(), nil
+}
+
+// timeline collects the states of every alert instance at each evaluation.
+type timeline struct {
+	length    int
+	times     []time.Time
+	instances map[string]*data.Field
+}
+
+func newTimeline(length int) *timeline {
+	return &timeline{
+		length:    length,
+		times:     make([]time.Time, length),
+		instances: make(map[string]*data.Field),
+	}
+}
+
+func (t *timeline) add(idx int, now time.Time, states []state.StateTransition) {
+	t.times[idx] = now
+	for _, s := range states {
+		field, ok := t.instances[s.CacheID]
+		if !ok {
+			field = data.NewField("", s.Labels.Copy(), make([]*string, t.length))
+			t.instances[s.CacheID] = field
+		}
+		value := state.FormatStateAndReason(s.State.State, s.StateReason)
+		field.Set(idx, &value)
+	}
+}
+
+func (t *timeline) toFrame() *data.Frame {
+	fields := make([]*data.Field, 0, len(t.instances)+1)
+	fields = append(fields, data.NewField("Time", nil, t.times))
+
+	instances := make([]*data.Field, 0, len(t.instances))
+	for _, field := range t.instances {
+		instances = append(instances, field)
+	}
+	sort.Slice(instances, func(i, j int) bool {
+		return instances[i].Labels.String() < instances[j].Labels.String()
+	})
+	fields = append(fields, instances...)
+
+	return data.NewFrame("Backtesting results", fields...)
+}
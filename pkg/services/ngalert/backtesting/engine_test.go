@@ -0,0 +1,212 @@
+package backtesting
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/grafana/grafana-plugin-sdk-go/backend"
+	"github.com/grafana/grafana-plugin-sdk-go/data"
+	"github.com/stretchr/testify/require"
+
+	"github.com/grafana/grafana/pkg/services/ngalert/eval"
+	"github.com/grafana/grafana/pkg/services/ngalert/eval/eval_mocks"
+	"github.com/grafana/grafana/pkg/services/ngalert/models"
+	"github.com/grafana/grafana/pkg/services/user"
+)
+
+func TestEngineTest(t *testing.T) {
+	from := time.Unix(0, 0).UTC()
+	testUser := &user.SignedInUser{OrgID: 1}
+
+	t.Run("should validate the input", func(t *testing.T) {
+		engine := NewEngine(eval_mocks.NewEvaluatorFactory(&fakeConditionEvaluator{}))
+
+		testCases := []struct {
+			name     string
+			interval int64
+			to       time.Time
+		}{
+			{name: "interval is not positive", interval: 0, to: from.Add(time.Minute)},
+			{name: "to is not after from", interval: 10, to: from},
+			{name: "too many evaluations", interval: 10, to: from.Add(10 * time.Second * MaxEvaluations)},
+		}
+		for _, testCase := range testCases {
+			t.Run(testCase.name, func(t *testing.T) {
+				rule := testRule(func(rule *models.AlertRule) {
+					rule.IntervalSeconds = testCase.interval
+				})
+				_, err := engine.Test(context.Background(), testUser, rule, from, testCase.to)
+				require.ErrorIs(t, err, ErrInvalidInputData)
+			})
+		}
+	})
+
+	t.Run("should fail if the evaluator cannot be created", func(t *testing.T) {
+		engine := NewEngine(eval_mocks.NewFailingEvaluatorFactory(errors.New("bad condition")))
+
+		_, err := engine.Test(context.Background(), testUser, testRule(), from, from.Add(time.Minute))
+		require.ErrorIs(t, err, ErrInvalidInputData)
+		require.ErrorContains(t, err, "bad condition")
+	})
+
+	t.Run("should return the state of every instance at each evaluation", func(t *testing.T) {
+		evaluator := &fakeConditionEvaluator{
+			results: func(now time.Time) (eval.Results, error) {
+				step := now.Sub(from) / (10 * time.Second)
+				result := eval.Result{Instance: data.Labels{"instance": "a"}, State: eval.Alerting, EvaluatedAt: now}
+				if step == 4 {
+					result.State = eval.Normal
+				}
+				return eval.Results{result}, nil
+			},
+		}
+		engine := NewEngine(eval_mocks.NewEvaluatorFactory(evaluator))
+		rule := testRule(func(rule *models.AlertRule) {
+			rule.For = 20 * time.Second
+		})
+
+		frame, err := engine.Test(context.Background(), testUser, rule, from, from.Add(40*time.Second))
+		require.NoError(t, err)
+
+		require.Len(t, frame.Fields, 2)
+		require.Equal(t, 5, frame.Rows())
+		for i := 0; i < frame.Rows(); i++ {
+			require.Equal(t, from.Add(time.Duration(i)*10*time.Second), frame.Fields[0].At(i))
+		}
+		require.Equal(t, "a", frame.Fields[1].Labels["instance"])
+		require.Equal(t, []string{"Pending", "Pending", "Alerting", "Alerting", "Normal"}, stringValues(frame.Fields[1]))
+	})
+
+	t.Run("should set the instances that do not exist yet to null", func(t *testing.T) {
+		evaluator := &fakeConditionEvaluator{
+			results: func(now time.Time) (eval.Results, error) {
+				results := eval.Results{{Instance: data.Labels{"instance": "a"}, State: eval.Normal, EvaluatedAt: now}}
+				if now.After(from) {
+					results = append(results, eval.Result{Instance: data.Labels{"instance": "b"}, State: eval.Alerting, EvaluatedAt: now})
+				}
+				return results, nil
+			},
+		}
+		engine := NewEngine(eval_mocks.NewEvaluatorFactory(evaluator))
+
+		frame, err := engine.Test(context.Background(), testUser, testRule(), from, from.Add(10*time.Second))
+		require.NoError(t, err)
+
+		require.Len(t, frame.Fields, 3)
+		require.Equal(t, "b", frame.Fields[2].Labels["instance"])
+		require.Nil(t, frame.Fields[2].At(0))
+		require.Equal(t, "Alerting", *frame.Fields[2].At(1).(*string))
+	})
+
+	t.Run("should keep alerting instances firing for the keep firing for duration", func(t *testing.T) {
+		evaluator := &fakeConditionEvaluator{
+			results: func(now time.Time) (eval.Results, error) {
+				result := eval.Result{Instance: data.Labels{"instance": "a"}, State: eval.Normal, EvaluatedAt: now}
+				if now.Equal(from) {
+					result.State = eval.Alerting
+				}
+				return eval.Results{result}, nil
+			},
+		}
+		engine := NewEngine(eval_mocks.NewEvaluatorFactory(evaluator))
+		rule := testRule(func(rule *models.AlertRule) {
+			rule.KeepFiringFor = 20 * time.Second
+		})
+
+		frame, err := engine.Test(context.Background(), testUser, rule, from, from.Add(40*time.Second))
+		require.NoError(t, err)
+
+		require.Len(t, frame.Fields, 2)
+		require.Equal(t, []string{"Alerting", "Alerting", "Alerting", "Normal", "Normal"}, stringValues(frame.Fields[1]))
+	})
+
+	t.Run("should provide the results that were alerting at the previous evaluation to the evaluator", func(t *testing.T) {
+		evaluator := &fakeConditionEvaluator{
+			results: func(now time.Time) (eval.Results, error) {
+				step := now.Sub(from) / (10 * time.Second)
+				result := eval.Result{Instance: data.Labels{"instance": "a"}, State: eval.Normal, EvaluatedAt: now}
+				if step == 1 {
+					result.State = eval.Alerting
+				}
+				return eval.Results{result}, nil
+			},
+		}
+		factory := &recordingEvaluatorFactory{evaluator: evaluator}
+		engine := NewEngine(factory)
+
+		_, err := engine.Test(context.Background(), testUser, testRule(), from, from.Add(20*time.Second))
+		require.NoError(t, err)
+
+		require.Equal(t, [][]data.Labels{{}, {}, {{"instance": "a"}}}, factory.alertingResults)
+	})
+
+	t.Run("should use the execution error state of the rule when the evaluation fails", func(t *testing.T) {
+		evaluator := &fakeConditionEvaluator{
+			results: func(now time.Time) (eval.Results, error) {
+				return nil, errors.New("failed to query")
+			},
+		}
+		engine := NewEngine(eval_mocks.NewEvaluatorFactory(evaluator))
+		rule := testRule(func(rule *models.AlertRule) {
+			rule.ExecErrState = models.ErrorErrState
+		})
+
+		frame, err := engine.Test(context.Background(), testUser, rule, from, from.Add(10*time.Second))
+		require.NoError(t, err)
+
+		require.Len(t, frame.Fields, 2)
+		require.Equal(t, []string{"Error", "Error"}, stringValues(frame.Fields[1]))
+	})
+}
+
+func testRule(mutators ...models.AlertRuleMutator) *models.AlertRule {
+	mutators = append([]models.AlertRuleMutator{func(rule *models.AlertRule) {
+		rule.IntervalSeconds = 10
+		rule.For = 0
+		rule.Labels = nil
+		rule.Annotations = nil
+		rule.NoDataState = models.NoData
+		rule.ExecErrState = models.AlertingErrState
+	}}, mutators...)
+	return models.AlertRuleGen(mutators...)()
+}
+
+func stringValues(field *data.Field) []string {
+	result := make([]string, 0, field.Len())
+	for i := 0; i < field.Len(); i++ {
+		result = append(result, *field.At(i).(*string))
+	}
+	return result
+}
+
+type fakeConditionEvaluator struct {
+	results func(now time.Time) (eval.Results, error)
+}
+
+func (f *fakeConditionEvaluator) EvaluateRaw(ctx context.Context, now time.Time) (*backend.QueryDataResponse, error) {
+	return nil, errors.New("not implemented")
+}
+
+func (f *fakeConditionEvaluator) Evaluate(ctx context.Context, now time.Time) (eval.Results, error) {
+	if f.results == nil {
+		return nil, nil
+	}
+	return f.results(now)
+}
+
+// recordingEvaluatorFactory records the alerting results that were provided to the evaluator each time it is created.
+type recordingEvaluatorFactory struct {
+	evaluator       eval.ConditionEvaluator
+	alertingResults [][]data.Labels
+}
+
+func (f *recordingEvaluatorFactory) Validate(_ eval.EvaluationContext, _ models.Condition) error {
+	return nil
+}
+
+func (f *recordingEvaluatorFactory) Create(ctx eval.EvaluationContext, _ models.Condition) (eval.ConditionEvaluator, error) {
+	f.alertingResults = append(f.alertingResults, ctx.AlertingResultsReader.Read())
+	return f.evaluator, nil
+}
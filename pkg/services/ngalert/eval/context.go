@@ -3,13 +3,23 @@ package eval
 import (
 	"context"
 
+	"github.com/grafana/grafana-plugin-sdk-go/data"
+
 	"github.com/grafana/grafana/pkg/services/user"
 )
 
+// AlertingResultsReader provides the labels of the results of the condition
+// that are currently alerting. It is used by expressions that depend on the previous state, such as recovery thresholds.
+type AlertingResultsReader interface {
+	Read() []data.Labels
+}
+
 // EvaluationContext represents the context in which a condition is evaluated.
 type EvaluationContext struct {
 	Ctx  context.Context
 	User *user.SignedInUser
+	// AlertingResultsReader is optional. If it is not set, the condition is evaluated as if nothing was alerting before.
+	AlertingResultsReader AlertingResultsReader
 }
 
 func Context(ctx context.Context, user *user.SignedInUser) EvaluationContext {
@@ -18,3 +28,13 @@ func Context(ctx context.Context, user *user.SignedInUser) EvaluationContext {
 		User: user,
 	}
 }
+
+// NewContextWithPreviousResults creates an EvaluationContext that provides the results
+// that are currently alerting to the expressions that depend on them.
+func NewContextWithPreviousResults(ctx context.Context, user *user.SignedInUser, reader AlertingResultsReader) EvaluationContext {
+	return EvaluationContext{
+		Ctx:                   ctx,
+		User:                  user,
+		AlertingResultsReader: reader,
+	}
+}
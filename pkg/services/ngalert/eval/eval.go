@@ -4,6 +4,7 @@ package eval
 
 import (
 	"context"
+	"encoding/json"
 	"errors"
 	"fmt"
 	"runtime/debug"
@@ -238,19 +239,29 @@ func buildDatasourceHeaders(ctx EvaluationContext) map[string]string {
 }
 
 // getExprRequest validates the condition, gets the datasource information and creates an expr.Request from it.
-func getExprRequest(ctx EvaluationContext, data []models.AlertQuery, dsCacheService datasources.CacheService) (*expr.Request, error) {
+func getExprRequest(ctx EvaluationContext, queries []models.AlertQuery, dsCacheService datasources.CacheService) (*expr.Request, error) {
 	req := &expr.Request{
 		OrgId:   ctx.User.OrgID,
 		Headers: buildDatasourceHeaders(ctx),
 	}
 
-	datasources := make(map[string]*datasources.DataSource, len(data))
+	datasources := make(map[string]*datasources.DataSource, len(queries))
+	var alertingResults []data.Labels
 
-	for _, q := range data {
+	for _, q := range queries {
 		model, err := q.GetModel()
 		if err != nil {
 			return nil, fmt.Errorf("failed to get query model from '%s': %w", q.RefID, err)
 		}
+		if ctx.AlertingResultsReader != nil && expr.IsDataSource(q.DatasourceUID) {
+			if alertingResults == nil {
+				alertingResults = ctx.AlertingResultsReader.Read()
+			}
+			model, err = setLoadedDimensions(model, alertingResults)
+			if err != nil {
+				return nil, fmt.Errorf("failed to set the previous results to '%s': %w", q.RefID, err)
+			}
+		}
 		interval, err := q.GetIntervalDuration()
 		if err != nil {
 			return nil, fmt.Errorf("failed to retrieve intervalMs from '%s': %w", q.RefID, err)
@@ -287,6 +298,22 @@ func getExprRequest(ctx EvaluationContext, data []models.AlertQuery, dsCacheServ
 	return req, nil
 }
 
+// setLoadedDimensions adds the dimensions that are currently alerting to the model of a threshold
+// expression with a recovery threshold. Other models are returned unchanged.
+func setLoadedDimensions(model []byte, dimensions []data.Labels) ([]byte, error) {
+	var query map[string]interface{}
+	if err := json.Unmarshal(model, &query); err != nil {
+		return nil, err
+	}
+	if !expr.IsHysteresisExpression(query) {
+		return model, nil
+	}
+	if err := expr.SetLoadedDimensionsToHysteresisCommand(query, dimensions); err != nil {
+		return nil, err
+	}
+	return json.Marshal(query)
+}
+
 type NumberValueCapture struct {
 	Var    string // RefID
 	Labels data.Labels
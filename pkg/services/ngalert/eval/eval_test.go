@@ -2,6 +2,7 @@ package eval
 
 import (
 	"context"
+	"encoding/json"
 	"fmt"
 	"math/rand"
 	"testing"
@@ -532,7 +533,7 @@ func TestValidate(t *testing.T) {
 				pluginsStore: store,
 			})
 
-			evaluator := NewEvaluatorFactory(setting.UnifiedAlertingSettings{}, cacheService, expr.ProvideService(&setting.Cfg{ExpressionsEnabled: true}, nil, nil), store)
+			evaluator := NewEvaluatorFactory(setting.UnifiedAlertingSettings{}, cacheService, expr.ProvideService(&setting.Cfg{ExpressionsEnabled: true}, nil, nil, nil), store)
 			evalCtx := Context(context.Background(), u)
 
 			err := evaluator.Validate(evalCtx, condition)
@@ -544,3 +545,55 @@ func TestValidate(t *testing.T) {
 		})
 	}
 }
+
+func TestGetExprRequestWithPreviousResults(t *testing.T) {
+	thresholdQuery := func(refID string, unloadEvaluator bool) models.AlertQuery {
+		condition := `{"evaluator": {"type": "gt", "params": [80]}}`
+		if unloadEvaluator {
+			condition = `{"evaluator": {"type": "gt", "params": [80]}, "unloadEvaluator": {"type": "lt", "params": [70]}}`
+		}
+		return models.AlertQuery{
+			RefID:         refID,
+			DatasourceUID: expr.DatasourceUID,
+			Model:         json.RawMessage(fmt.Sprintf(`{"type": "threshold", "expression": "A", "conditions": [%s]}`, condition)),
+		}
+	}
+	loadedDimensions := func(t *testing.T, q expr.Query) interface{} {
+		t.Helper()
+		var model map[string]interface{}
+		require.NoError(t, json.Unmarshal(q.JSON, &model))
+		return model["conditions"].([]interface{})[0].(map[string]interface{})["loadedDimensions"]
+	}
+
+	t.Run("sets alerting results to threshold expressions with a recovery threshold", func(t *testing.T) {
+		reader := &fakeAlertingResultsReader{results: []data.Labels{{"host": "a"}}}
+		ctx := NewContextWithPreviousResults(context.Background(), &user.SignedInUser{}, reader)
+
+		req, err := getExprRequest(ctx, []models.AlertQuery{thresholdQuery("B", true), thresholdQuery("C", false)}, &fakes.FakeCacheService{})
+
+		require.NoError(t, err)
+		require.Len(t, req.Queries, 2)
+		require.Equal(t, []interface{}{map[string]interface{}{"host": "a"}}, loadedDimensions(t, req.Queries[0]))
+		require.Nil(t, loadedDimensions(t, req.Queries[1]))
+		require.Equal(t, 1, reader.calls)
+	})
+
+	t.Run("does not set alerting results without a reader", func(t *testing.T) {
+		ctx := Context(context.Background(), &user.SignedInUser{})
+
+		req, err := getExprRequest(ctx, []models.AlertQuery{thresholdQuery("B", true)}, &fakes.FakeCacheService{})
+
+		require.NoError(t, err)
+		require.Nil(t, loadedDimensions(t, req.Queries[0]))
+	})
+}
+
+type fakeAlertingResultsReader struct {
+	results []data.Labels
+	calls   int
+}
+
+func (f *fakeAlertingResultsReader) Read() []data.Labels {
+	f.calls++
+	return f.results
+}
@@ -11,6 +11,7 @@ import (
 	"golang.org/x/sync/singleflight"
 
 	"github.com/grafana/grafana/pkg/components/imguploader"
+	"github.com/grafana/grafana/pkg/infra/filestorage"
 	"github.com/grafana/grafana/pkg/infra/log"
 	"github.com/grafana/grafana/pkg/services/dashboards"
 	"github.com/grafana/grafana/pkg/services/ngalert/models"
@@ -28,14 +29,33 @@ const (
 // DeleteExpiredService is a service to delete expired images.
 type DeleteExpiredService struct {
 	store store.ImageAdminStore
+	// files is the file storage of images, if screenshots are stored in file storage.
+	files  filestorage.FileStorage
+	logger log.Logger
 }
 
 func (s *DeleteExpiredService) DeleteExpired(ctx context.Context) (int64, error) {
-	return s.store.DeleteExpiredImages(ctx)
+	rowsAffected, err := s.store.DeleteExpiredImages(ctx)
+	if err != nil {
+		return rowsAffected, err
+	}
+	if s.files != nil {
+		deleted, err := deleteExpiredFiles(ctx, s.files, time.Now())
+		if err != nil {
+			return rowsAffected, err
+		}
+		s.logger.FromContext(ctx).Debug("Deleted expired images from file storage", "files", deleted)
+	}
+	return rowsAffected, nil
 }
 
 func ProvideDeleteExpiredService(store *store.DBstore) *DeleteExpiredService {
-	return &DeleteExpiredService{store: store}
+	logger := log.New("ngalert.image")
+	files, err := newFileStorage(store.Cfg.Screenshots, store.SQLStore)
+	if err != nil {
+		logger.Error("Failed to create file storage for images", "error", err)
+	}
+	return &DeleteExpiredService{store: store, files: files, logger: logger}
 }
 
 type ImageService interface {
@@ -55,6 +75,7 @@ type ScreenshotImageService struct {
 	singleflight singleflight.Group
 	store        store.ImageStore
 	uploads      *UploadingService
+	storage      *StoringService
 }
 
 // NewScreenshotImageService returns a new ScreenshotImageService.
@@ -64,7 +85,8 @@ func NewScreenshotImageService(
 	logger log.Logger,
 	screenshots screenshot.ScreenshotService,
 	store store.ImageStore,
-	uploads *UploadingService) ImageService {
+	uploads *UploadingService,
+	storage *StoringService) ImageService {
 	return &ScreenshotImageService{
 		cache:       cache,
 		limiter:     limiter,
@@ -72,13 +94,14 @@ func NewScreenshotImageService(
 		screenshots: screenshots,
 		store:       store,
 		uploads:     uploads,
+		storage:     storage,
 	}
 }
 
 // NewScreenshotImageServiceFromCfg returns a new ScreenshotImageService
 // from the configuration.
 func NewScreenshotImageServiceFromCfg(cfg *setting.Cfg, db *store.DBstore, ds dashboards.DashboardService,
-	rs rendering.Service, r prometheus.Registerer) (ImageService, error) {
+	rs rendering.Service, storage *StoringService, r prometheus.Registerer) (ImageService, error) {
 	var (
 		cache       CacheService                 = &NoOpCacheService{}
 		limiter     screenshot.RateLimiter       = &screenshot.NoOpRateLimiter{}
@@ -102,7 +125,7 @@ func NewScreenshotImageServiceFromCfg(cfg *setting.Cfg, db *store.DBstore, ds da
 		}
 	}
 
-	return NewScreenshotImageService(cache, limiter, log.New("ngalert.image"), screenshots, db, uploads), nil
+	return NewScreenshotImageService(cache, limiter, log.New("ngalert.image"), screenshots, db, uploads, storage), nil
 }
 
 // NewImage returns a screenshot of the alert rule or an error.
@@ -182,6 +205,20 @@ func (s *ScreenshotImageService) NewImage(ctx context.Context, r *models.AlertRu
 		}
 		logger.Debug("Saved image", "token", image.Token)
 
+		// Storing images is optional. The image is stored after it has been saved as
+		// it needs a token and an expiration time for its signed URL.
+		if s.storage != nil {
+			stored, err := s.storage.Store(ctx, image)
+			if err != nil {
+				logger.Warn("Failed to store image", "error", err)
+			} else if err := s.store.SaveImage(ctx, &stored); err != nil {
+				logger.Warn("Failed to save URL of stored image", "token", image.Token, "error", err)
+			} else {
+				logger.Debug("Stored image", "token", image.Token)
+				image = stored
+			}
+		}
+
 		return image, nil
 	})
 	if err != nil {
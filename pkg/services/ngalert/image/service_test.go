@@ -3,6 +3,8 @@ package image
 import (
 	"context"
 	"errors"
+	"os"
+	"path/filepath"
 	"testing"
 
 	"github.com/golang/mock/gomock"
@@ -10,8 +12,10 @@ import (
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/require"
 	"github.com/xorcare/pointer"
+	"gocloud.dev/blob/memblob"
 
 	"github.com/grafana/grafana/pkg/components/imguploader"
+	"github.com/grafana/grafana/pkg/infra/filestorage"
 	"github.com/grafana/grafana/pkg/infra/log"
 	"github.com/grafana/grafana/pkg/services/ngalert/models"
 	"github.com/grafana/grafana/pkg/services/ngalert/store"
@@ -31,7 +35,7 @@ func TestScreenshotImageService(t *testing.T) {
 	)
 
 	s := NewScreenshotImageService(cache, &limiter, log.NewNopLogger(), screenshots, images,
-		NewUploadingService(uploads, prometheus.NewRegistry()))
+		NewUploadingService(uploads, prometheus.NewRegistry()), nil)
 
 	ctx := context.Background()
 
@@ -117,3 +121,43 @@ func TestScreenshotImageService(t *testing.T) {
 	require.NoError(t, err)
 	assert.Equal(t, expected, *image)
 }
+
+func TestScreenshotImageService_StoresImage(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	var (
+		cache       = NewMockCacheService(ctrl)
+		images      = store.NewFakeImageStore(t)
+		limiter     = screenshot.NoOpRateLimiter{}
+		screenshots = screenshot.NewMockScreenshotService(ctrl)
+		files       = filestorage.NewCdkBlobStorage(log.NewNopLogger(), memblob.OpenBucket(nil), imagesFolder, nil)
+		storage     = NewStoringService(files, NewURLSigner("https://grafana.example.com", "secret"), prometheus.NewRegistry())
+	)
+
+	s := NewScreenshotImageService(cache, &limiter, log.NewNopLogger(), screenshots, images, nil, storage)
+
+	ctx := context.Background()
+	path := filepath.Join(t.TempDir(), "foo.png")
+	require.NoError(t, os.WriteFile(path, []byte("foo"), 0600))
+
+	cache.EXPECT().Get(gomock.Any(), "M2DGZaRLXtg=").Return(models.Image{}, false)
+	screenshots.EXPECT().Take(gomock.Any(), gomock.Any()).Return(&screenshot.Screenshot{Path: path}, nil)
+	cache.EXPECT().Set(gomock.Any(), "M2DGZaRLXtg=", gomock.Any()).Return(nil)
+
+	image, err := s.NewImage(ctx, &models.AlertRule{
+		OrgID:        1,
+		UID:          "foo",
+		DashboardUID: pointer.String("foo"),
+		PanelID:      pointer.Int64(1)})
+	require.NoError(t, err)
+
+	// assert that the image is stored in file storage and saved with its signed URL
+	assert.Equal(t, storage.signer.Sign(image.Token, image.ExpiresAt), image.URL)
+	saved, err := images.GetImage(ctx, image.Token)
+	require.NoError(t, err)
+	assert.Equal(t, image.URL, saved.URL)
+	_, ok, err := files.Get(ctx, filePath(image.Token), nil)
+	require.NoError(t, err)
+	assert.True(t, ok)
+}
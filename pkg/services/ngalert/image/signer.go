@@ -0,0 +1,80 @@
+package image
+
+import (
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"errors"
+	"net/url"
+	"strconv"
+	"strings"
+	"time"
+)
+
+var (
+	// ErrInvalidSignature is returned when the signature of an image URL does not match its token and expiration.
+	ErrInvalidSignature = errors.New("invalid signature")
+	// ErrURLExpired is returned when an image URL has expired.
+	ErrURLExpired = errors.New("url has expired")
+)
+
+// signingKeyPurpose is used to derive the key that signs URLs from the secret, so that signatures of image URLs
+// can't be used for anything else that is signed with the same secret.
+const signingKeyPurpose = "ngalert-image-url"
+
+// URLSigner signs and verifies URLs of images that are served by Grafana. A signed URL
+// contains the token of the image, the time at which the URL expires, and a signature of both
+// so the image can be fetched by a contact point without a Grafana session.
+type URLSigner struct {
+	baseURL string
+	secret  []byte
+	now     func() time.Time
+}
+
+// NewURLSigner returns a new URLSigner. The baseURL is the root URL of the Grafana server and
+// the secret is used to derive the key that signs URLs, which means all replicas of Grafana must
+// use the same secret.
+func NewURLSigner(baseURL, secret string) *URLSigner {
+	return &URLSigner{
+		baseURL: strings.TrimSuffix(baseURL, "/"),
+		secret:  deriveSigningKey(secret),
+		now:     time.Now,
+	}
+}
+
+// Sign returns the URL of the image with the token. The URL expires at expiresAt.
+func (s *URLSigner) Sign(token string, expiresAt time.Time) string {
+	expires := strconv.FormatInt(expiresAt.Unix(), 10)
+	v := url.Values{}
+	v.Set("expires", expires)
+	v.Set("signature", s.signature(token, expires))
+	return s.baseURL + "/api/alerting/images/" + url.PathEscape(token) + "?" + v.Encode()
+}
+
+// Verify returns ErrInvalidSignature if the signature does not match the token and expiration,
+// or ErrURLExpired if the URL has expired.
+func (s *URLSigner) Verify(token, expires, signature string) error {
+	if !hmac.Equal([]byte(s.signature(token, expires)), []byte(signature)) {
+		return ErrInvalidSignature
+	}
+	unix, err := strconv.ParseInt(expires, 10, 64)
+	if err != nil {
+		return ErrInvalidSignature
+	}
+	if !s.now().Before(time.Unix(unix, 0)) {
+		return ErrURLExpired
+	}
+	return nil
+}
+
+func deriveSigningKey(secret string) []byte {
+	mac := hmac.New(sha256.New, []byte(secret))
+	_, _ = mac.Write([]byte(signingKeyPurpose))
+	return mac.Sum(nil)
+}
+
+func (s *URLSigner) signature(token, expires string) string {
+	mac := hmac.New(sha256.New, s.secret)
+	_, _ = mac.Write([]byte(token + ":" + expires))
+	return hex.EncodeToString(mac.Sum(nil))
+}
@@ -0,0 +1,50 @@
+package image
+
+import (
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"net/url"
+	"path"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestURLSigner(t *testing.T) {
+	now := time.Unix(1000, 0)
+	s := NewURLSigner("https://grafana.example.com/", "secret")
+	s.now = func() time.Time { return now }
+
+	signed := s.Sign("foo", now.Add(time.Hour))
+	u, err := url.Parse(signed)
+	require.NoError(t, err)
+	assert.Equal(t, "grafana.example.com", u.Host)
+	assert.Equal(t, "/api/alerting/images/foo", u.Path)
+	assert.Equal(t, "4600", u.Query().Get("expires"))
+
+	token, expires, signature := path.Base(u.Path), u.Query().Get("expires"), u.Query().Get("signature")
+
+	// a valid URL is verified
+	assert.NoError(t, s.Verify(token, expires, signature))
+
+	// a URL for another token or expiration is not verified
+	assert.ErrorIs(t, s.Verify("bar", expires, signature), ErrInvalidSignature)
+	assert.ErrorIs(t, s.Verify(token, "4601", signature), ErrInvalidSignature)
+	assert.ErrorIs(t, s.Verify(token, expires, ""), ErrInvalidSignature)
+
+	// a URL signed with another secret is not verified
+	other := NewURLSigner("https://grafana.example.com/", "other")
+	assert.ErrorIs(t, other.Verify(token, expires, signature), ErrInvalidSignature)
+
+	// the secret is not used as the key of the signature
+	mac := hmac.New(sha256.New, []byte("secret"))
+	_, _ = mac.Write([]byte(token + ":" + expires))
+	assert.NotEqual(t, hex.EncodeToString(mac.Sum(nil)), signature)
+
+	// an expired URL is not verified
+	now = now.Add(time.Hour)
+	assert.ErrorIs(t, s.Verify(token, expires, signature), ErrURLExpired)
+}
@@ -0,0 +1,179 @@
+package image
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"os"
+	"strconv"
+	"time"
+
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/promauto"
+	"gocloud.dev/blob"
+
+	"github.com/grafana/grafana/pkg/infra/db"
+	"github.com/grafana/grafana/pkg/infra/filestorage"
+	"github.com/grafana/grafana/pkg/infra/log"
+	ngmodels "github.com/grafana/grafana/pkg/services/ngalert/models"
+	"github.com/grafana/grafana/pkg/setting"
+)
+
+const (
+	// imagesFolder is the root folder of images in file storage.
+	imagesFolder = "/alerting/images/"
+	// expiresAtProperty is the property of a stored image that contains the Unix time at which it expires.
+	expiresAtProperty = "expires_at"
+)
+
+// ErrImageNotFound is returned when an image does not exist in file storage.
+var ErrImageNotFound = errors.New("image not found")
+
+// StoringService stores images in Grafana file storage, either the database or a blob storage
+// bucket, so images can be served by any Grafana server using signed URLs.
+type StoringService struct {
+	files     filestorage.FileStorage
+	signer    *URLSigner
+	failures  prometheus.Counter
+	successes prometheus.Counter
+}
+
+func NewStoringService(files filestorage.FileStorage, signer *URLSigner, r prometheus.Registerer) *StoringService {
+	return &StoringService{
+		files:  files,
+		signer: signer,
+		failures: promauto.With(r).NewCounter(prometheus.CounterOpts{
+			Name:      "image_store_failures_total",
+			Namespace: "grafana",
+			Subsystem: "alerting",
+		}),
+		successes: promauto.With(r).NewCounter(prometheus.CounterOpts{
+			Name:      "image_store_successes_total",
+			Namespace: "grafana",
+			Subsystem: "alerting",
+		}),
+	}
+}
+
+// NewStoringServiceFromCfg returns a new StoringService from the configuration. It returns nil
+// if screenshots are not stored in file storage.
+func NewStoringServiceFromCfg(cfg *setting.Cfg, db db.DB, r prometheus.Registerer) (*StoringService, error) {
+	files, err := newFileStorage(cfg.UnifiedAlerting.Screenshots, db)
+	if err != nil {
+		return nil, err
+	}
+	if files == nil {
+		return nil, nil
+	}
+	return NewStoringService(files, NewURLSigner(cfg.AppURL, cfg.SecretKey), r), nil
+}
+
+// newFileStorage returns the file storage for images, or nil if screenshots are not stored in file storage.
+func newFileStorage(cfg setting.UnifiedAlertingScreenshotSettings, db db.DB) (filestorage.FileStorage, error) {
+	if !cfg.StoreInFileStorage {
+		return nil, nil
+	}
+
+	logger := log.New("ngalert.image.storage")
+	switch cfg.FileStorageType {
+	case setting.ScreenshotsFileStorageBlob:
+		bucket, err := blob.OpenBucket(context.Background(), cfg.FileStorageBlobURL)
+		if err != nil {
+			return nil, fmt.Errorf("failed to open bucket for images: %w", err)
+		}
+		return filestorage.NewCdkBlobStorage(logger, bucket, imagesFolder, nil), nil
+	default:
+		return filestorage.NewDbStorage(logger, db, nil, imagesFolder), nil
+	}
+}
+
+// Store stores the image in file storage and returns a new image with the unmodified path and
+// a signed URL that expires at the same time as the image. The image must have a token.
+// It returns the unmodified image on error.
+func (s *StoringService) Store(ctx context.Context, image ngmodels.Image) (ngmodels.Image, error) {
+	if err := s.store(ctx, image); err != nil {
+		defer s.failures.Inc()
+		return image, fmt.Errorf("failed to store screenshot: %w", err)
+	}
+	image.URL = s.signer.Sign(image.Token, image.ExpiresAt)
+	defer s.successes.Inc()
+	return image, nil
+}
+
+func (s *StoringService) store(ctx context.Context, image ngmodels.Image) error {
+	if image.Token == "" {
+		return errors.New("image has no token")
+	}
+	if !image.HasPath() {
+		return errors.New("image has no path")
+	}
+	b, err := os.ReadFile(image.Path)
+	if err != nil {
+		return err
+	}
+	return s.files.Upsert(ctx, &filestorage.UpsertFileCommand{
+		Path:     filePath(image.Token),
+		MimeType: "image/png",
+		Contents: b,
+		Properties: map[string]string{
+			expiresAtProperty: strconv.FormatInt(image.ExpiresAt.Unix(), 10),
+		},
+	})
+}
+
+// Get returns the stored image with the token if the signature of its URL is valid. It returns
+// ErrInvalidSignature or ErrURLExpired if the URL cannot be verified, and ErrImageNotFound if
+// the image does not exist.
+func (s *StoringService) Get(ctx context.Context, token, expires, signature string) (*filestorage.File, error) {
+	if err := s.signer.Verify(token, expires, signature); err != nil {
+		return nil, err
+	}
+	path := filePath(token)
+	if err := filestorage.ValidatePath(path); err != nil {
+		return nil, ErrImageNotFound
+	}
+	file, ok, err := s.files.Get(ctx, path, nil)
+	if err != nil {
+		return nil, err
+	}
+	if !ok {
+		return nil, ErrImageNotFound
+	}
+	return file, nil
+}
+
+// deleteExpiredFiles deletes all images that have expired from file storage and returns the
+// number of deleted images.
+func deleteExpiredFiles(ctx context.Context, files filestorage.FileStorage, now time.Time) (int64, error) {
+	var (
+		deleted int64
+		paging  = &filestorage.Paging{}
+	)
+	for {
+		resp, err := files.List(ctx, filestorage.Delimiter, paging, &filestorage.ListOptions{WithFiles: true})
+		if err != nil {
+			return deleted, fmt.Errorf("failed to list images: %w", err)
+		}
+		if resp == nil {
+			return deleted, nil
+		}
+		for _, f := range resp.Files {
+			expiresAt, err := strconv.ParseInt(f.Properties[expiresAtProperty], 10, 64)
+			if err != nil || now.Before(time.Unix(expiresAt, 0)) {
+				continue
+			}
+			if err := files.Delete(ctx, f.FullPath); err != nil {
+				return deleted, fmt.Errorf("failed to delete image: %w", err)
+			}
+			deleted++
+		}
+		if !resp.HasMore {
+			return deleted, nil
+		}
+		paging = &filestorage.Paging{After: resp.LastPath}
+	}
+}
+
+func filePath(token string) string {
+	return filestorage.Join(token + ".png")
+}
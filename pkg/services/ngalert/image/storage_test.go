@@ -0,0 +1,72 @@
+package image
+
+import (
+	"context"
+	"net/url"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"gocloud.dev/blob/memblob"
+
+	"github.com/grafana/grafana/pkg/infra/filestorage"
+	"github.com/grafana/grafana/pkg/infra/log"
+	"github.com/grafana/grafana/pkg/services/ngalert/models"
+)
+
+func TestStoringService(t *testing.T) {
+	files := filestorage.NewCdkBlobStorage(log.NewNopLogger(), memblob.OpenBucket(nil), imagesFolder, nil)
+	s := NewStoringService(files, NewURLSigner("https://grafana.example.com", "secret"), prometheus.NewRegistry())
+
+	ctx := context.Background()
+	path := filepath.Join(t.TempDir(), "foo.png")
+	// the PNG signature, as blob storage detects the content type from the contents
+	contents := []byte("\x89PNG\r\n\x1a\n")
+	require.NoError(t, os.WriteFile(path, contents, 0600))
+
+	expiresAt := time.Now().Add(time.Hour)
+	image, err := s.Store(ctx, models.Image{Token: "foo", Path: path, ExpiresAt: expiresAt})
+	require.NoError(t, err)
+	assert.Equal(t, path, image.Path)
+	require.True(t, image.HasURL())
+
+	u, err := url.Parse(image.URL)
+	require.NoError(t, err)
+	assert.Equal(t, "/api/alerting/images/foo", u.Path)
+
+	// the image can be fetched with the signed URL
+	file, err := s.Get(ctx, "foo", u.Query().Get("expires"), u.Query().Get("signature"))
+	require.NoError(t, err)
+	assert.Equal(t, contents, file.Contents)
+	assert.Equal(t, "image/png", file.MimeType)
+
+	// but not with an invalid signature
+	_, err = s.Get(ctx, "foo", u.Query().Get("expires"), "invalid")
+	assert.ErrorIs(t, err, ErrInvalidSignature)
+
+	// an image that was not stored is not found
+	signed, err := url.Parse(s.signer.Sign("bar", expiresAt))
+	require.NoError(t, err)
+	_, err = s.Get(ctx, "bar", signed.Query().Get("expires"), signed.Query().Get("signature"))
+	assert.ErrorIs(t, err, ErrImageNotFound)
+
+	// an image without a path is not stored
+	image, err = s.Store(ctx, models.Image{Token: "bar", ExpiresAt: expiresAt})
+	assert.EqualError(t, err, "failed to store screenshot: image has no path")
+	assert.Equal(t, models.Image{Token: "bar", ExpiresAt: expiresAt}, image)
+
+	// expired images are deleted
+	deleted, err := deleteExpiredFiles(ctx, files, expiresAt.Add(-time.Minute))
+	require.NoError(t, err)
+	assert.Equal(t, int64(0), deleted)
+	deleted, err = deleteExpiredFiles(ctx, files, expiresAt.Add(time.Minute))
+	require.NoError(t, err)
+	assert.Equal(t, int64(1), deleted)
+	_, ok, err := files.Get(ctx, "/foo.png", nil)
+	require.NoError(t, err)
+	assert.False(t, ok)
+}
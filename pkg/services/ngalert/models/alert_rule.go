@@ -11,6 +11,7 @@ import (
 
 	"github.com/google/go-cmp/cmp"
 	"github.com/google/go-cmp/cmp/cmpopts"
+	prometheusModel "github.com/prometheus/common/model"
 
 	"github.com/grafana/grafana/pkg/services/quota"
 	"github.com/grafana/grafana/pkg/util/cmputil"
@@ -27,6 +28,8 @@ var (
 	ErrAlertRuleFailedValidation          = errors.New("invalid alert rule")
 	ErrAlertRuleUniqueConstraintViolation = errors.New("a conflicting alert rule is found: rule title under the same organisation and folder should be unique")
 	ErrQuotaReached                       = errors.New("quota has been exceeded")
+	// ErrRecordingRulesDisabled is returned when a recording rule is saved while recording rules are disabled.
+	ErrRecordingRulesDisabled = fmt.Errorf("%w: recording rules are disabled", ErrAlertRuleFailedValidation)
 	// ErrNoDashboard is returned when the alert rule does not have a Dashboard UID
 	// in its annotations or the dashboard does not exist.
 	ErrNoDashboard = errors.New("no dashboard")
@@ -163,9 +166,41 @@ type AlertRule struct {
 	ExecErrState    ExecutionErrorState
 	// ideally this field should have been apimodels.ApiDuration
 	// but this is currently not possible because of circular dependencies
-	For         time.Duration
-	Annotations map[string]string
-	Labels      map[string]string
+	For time.Duration
+	// KeepFiringFor is how long an alerting instance keeps firing after its condition is no longer met.
+	KeepFiringFor time.Duration
+	Annotations   map[string]string
+	Labels        map[string]string
+	IsPaused      bool
+	// Record is set if the rule is a recording rule. Recording rules do not produce alerts, instead the result of the
+	// query or expression referenced by Record.From is written as a metric.
+	Record *Record `xorm:"json record"`
+}
+
+// Record contains the definition of a recording rule.
+type Record struct {
+	// Metric is the name of the metric the result of the rule is written as.
+	Metric string `json:"metric"`
+	// From is the reference ID of the query or expression whose result is written.
+	From string `json:"from"`
+}
+
+// Validate checks that the metric name is a valid Prometheus metric name and that From references one of the queries or expressions.
+func (r *Record) Validate(data []AlertQuery) error {
+	if !prometheusModel.IsValidMetricName(prometheusModel.LabelValue(r.Metric)) {
+		return fmt.Errorf("%w: metric name '%s' of the recording rule is not a valid Prometheus metric name", ErrAlertRuleFailedValidation, r.Metric)
+	}
+	for _, q := range data {
+		if q.RefID == r.From {
+			return nil
+		}
+	}
+	return fmt.Errorf("%w: recording rule must write the result of one of its queries or expressions, but '%s' is not found", ErrAlertRuleFailedValidation, r.From)
+}
+
+// IsRecordingRule returns true if the rule is a recording rule.
+func (alertRule *AlertRule) IsRecordingRule() bool {
+	return alertRule.Record != nil
 }
 
 // GetDashboardUID returns the DashboardUID or "".
@@ -343,9 +378,12 @@ type AlertRuleVersion struct {
 	ExecErrState    ExecutionErrorState
 	// ideally this field should have been apimodels.ApiDuration
 	// but this is currently not possible because of circular dependencies
-	For         time.Duration
-	Annotations map[string]string
-	Labels      map[string]string
+	For           time.Duration
+	KeepFiringFor time.Duration
+	Annotations   map[string]string
+	Labels        map[string]string
+	IsPaused      bool
+	Record        *Record `xorm:"json record"`
 }
 
 // GetAlertRuleByUIDQuery is the query for retrieving/deleting an alert rule by UID and organisation ID.
@@ -437,6 +475,14 @@ func (c Condition) IsValid() bool {
 	return len(c.Data) != 0
 }
 
+// AlertRuleWithOptionals is an alert rule submitted to the API, together with whether its optional fields that
+// have no empty value, such as IsPaused, were set.
+type AlertRuleWithOptionals struct {
+	AlertRule
+	// HasPause is false when the API model had no is_paused field, in which case the existing rule keeps its state.
+	HasPause bool
+}
+
 // PatchPartialAlertRule patches `ruleToPatch` by `existingRule` following the rule that if a field of `ruleToPatch` is empty or has the default value, it is populated by the value of the corresponding field from `existingRule`.
 // There are several exceptions:
 // 1. Following fields are not patched and therefore will be ignored: AlertRule.ID, AlertRule.OrgID, AlertRule.Updated, AlertRule.Version, AlertRule.UID, AlertRule.DashboardUID, AlertRule.PanelID, AlertRule.Annotations and AlertRule.Labels
@@ -444,7 +490,8 @@ func (c Condition) IsValid() bool {
 //   - AlertRule.Condition and AlertRule.Data
 //
 // If either of the pair is specified, neither is patched.
-func PatchPartialAlertRule(existingRule *AlertRule, ruleToPatch *AlertRule) {
+// 3. AlertRule.IsPaused is patched when the rule has no pause, see AlertRuleWithOptionals.HasPause.
+func PatchPartialAlertRule(existingRule *AlertRule, ruleToPatch *AlertRuleWithOptionals) {
 	if ruleToPatch.Title == "" {
 		ruleToPatch.Title = existingRule.Title
 	}
@@ -470,6 +517,12 @@ func PatchPartialAlertRule(existingRule *AlertRule, ruleToPatch *AlertRule) {
 	if ruleToPatch.For == -1 {
 		ruleToPatch.For = existingRule.For
 	}
+	if ruleToPatch.KeepFiringFor == -1 {
+		ruleToPatch.KeepFiringFor = existingRule.KeepFiringFor
+	}
+	if !ruleToPatch.HasPause {
+		ruleToPatch.IsPaused = existingRule.IsPaused
+	}
 }
 
 func ValidateRuleGroupInterval(intervalSeconds, baseIntervalSeconds int64) error {
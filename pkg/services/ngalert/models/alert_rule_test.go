@@ -119,6 +119,12 @@ func TestPatchPartialAlertRule(t *testing.T) {
 					r.For = -1
 				},
 			},
+			{
+				name: "KeepFiringFor is -1",
+				mutator: func(r *AlertRule) {
+					r.KeepFiringFor = -1
+				},
+			},
 		}
 
 		for _, testCase := range testCases {
@@ -136,16 +142,31 @@ func TestPatchPartialAlertRule(t *testing.T) {
 						break
 					}
 				}
-				patch := *existing
-				testCase.mutator(&patch)
+				patch := AlertRuleWithOptionals{AlertRule: *existing, HasPause: true}
+				testCase.mutator(&patch.AlertRule)
 
-				require.NotEqual(t, *existing, patch)
+				require.NotEqual(t, *existing, patch.AlertRule)
 				PatchPartialAlertRule(existing, &patch)
-				require.Equal(t, *existing, patch)
+				require.Equal(t, *existing, patch.AlertRule)
 			})
 		}
 	})
 
+	t.Run("patches IsPaused when the rule has no pause", func(t *testing.T) {
+		existing := AlertRuleGen(WithIsPaused(true))()
+		patch := AlertRuleWithOptionals{AlertRule: *existing}
+		patch.IsPaused = false
+
+		PatchPartialAlertRule(existing, &patch)
+		require.True(t, patch.IsPaused)
+
+		patch = AlertRuleWithOptionals{AlertRule: *existing, HasPause: true}
+		patch.IsPaused = false
+
+		PatchPartialAlertRule(existing, &patch)
+		require.False(t, patch.IsPaused)
+	})
+
 	t.Run("does not patch", func(t *testing.T) {
 		testCases := []struct {
 			name    string
@@ -221,10 +242,10 @@ func TestPatchPartialAlertRule(t *testing.T) {
 						break
 					}
 				}
-				patch := *existing
-				testCase.mutator(&patch)
+				patch := AlertRuleWithOptionals{AlertRule: *existing, HasPause: true}
+				testCase.mutator(&patch.AlertRule)
 				PatchPartialAlertRule(existing, &patch)
-				require.NotEqual(t, *existing, patch)
+				require.NotEqual(t, *existing, patch.AlertRule)
 			})
 		}
 	})
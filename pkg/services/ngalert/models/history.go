@@ -0,0 +1,24 @@
+package models
+
+import (
+	"errors"
+	"time"
+
+	"github.com/grafana/grafana/pkg/services/user"
+)
+
+// ErrHistoryQueryUnsupported is returned by state history backends that cannot run a query, such as the annotation
+// backend for queries that are not for a single rule.
+var ErrHistoryQueryUnsupported = errors.New("state history query is not supported by the backend")
+
+// HistoryQuery represents a query for alert state history.
+type HistoryQuery struct {
+	RuleUID      string
+	RuleUIDs     []string // Restricts the history to the given rules when RuleUID is not set. Empty for all rules.
+	OrgID        int64
+	Labels       map[string]string
+	From         time.Time
+	To           time.Time
+	Limit        int
+	SignedInUser *user.SignedInUser
+}
@@ -0,0 +1,39 @@
+package models
+
+import (
+	"errors"
+	"time"
+)
+
+// ErrNotificationDeadLetterNotFound is returned when a dead letter does not exist.
+var ErrNotificationDeadLetterNotFound = errors.New("notification dead letter not found")
+
+// NotificationDeadLetter is a notification that an integration of a receiver failed to send after all retries.
+// It contains everything that is needed to replay the notification.
+type NotificationDeadLetter struct {
+	ID               int64  `xorm:"pk autoincr 'id'"`
+	OrgID            int64  `xorm:"org_id"`
+	Receiver         string `xorm:"receiver"`
+	Integration      string `xorm:"integration"`
+	IntegrationIndex int    `xorm:"integration_idx"`
+	GroupKey         string `xorm:"group_key"`
+	// GroupLabels are the JSON encoded labels of the alert group.
+	GroupLabels string `xorm:"group_labels"`
+	// Alerts are the JSON encoded alerts of the notification.
+	Alerts    string    `xorm:"alerts"`
+	Error     string    `xorm:"error"`
+	CreatedAt time.Time `xorm:"created_at"`
+}
+
+// A XORM interface that defines the used table for this struct.
+func (d *NotificationDeadLetter) TableName() string {
+	return "alert_notification_dead_letter"
+}
+
+// NotificationDeadLetterQuery represents a query for the dead letters of an organization.
+// Dead letters are returned from the most recent.
+type NotificationDeadLetterQuery struct {
+	OrgID    int64
+	Receiver string
+	Limit    int
+}
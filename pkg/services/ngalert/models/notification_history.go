@@ -0,0 +1,47 @@
+package models
+
+import (
+	"time"
+)
+
+const (
+	NotificationHistoryStatusSuccess = "success"
+	NotificationHistoryStatusFailed  = "failed"
+)
+
+// NotificationHistoryEntry is an attempt of an integration of a receiver to send a notification
+// for an alert group. The attempt includes all retries of the integration.
+type NotificationHistoryEntry struct {
+	ID               int64  `xorm:"pk autoincr 'id'"`
+	OrgID            int64  `xorm:"org_id"`
+	Receiver         string `xorm:"receiver"`
+	Integration      string `xorm:"integration"`
+	IntegrationIndex int    `xorm:"integration_idx"`
+	GroupKey         string `xorm:"group_key"`
+	Status           string `xorm:"status"`
+	Error            string `xorm:"error"`
+	// Duration is the duration of the attempt, including retries, in milliseconds.
+	Duration int64 `xorm:"duration"`
+	// Retries is the number of times the integration was retried after its first attempt failed.
+	Retries        int       `xorm:"retries"`
+	FiringAlerts   int       `xorm:"firing_alerts"`
+	ResolvedAlerts int       `xorm:"resolved_alerts"`
+	CreatedAt      time.Time `xorm:"created_at"`
+}
+
+// A XORM interface that defines the used table for this struct.
+func (e *NotificationHistoryEntry) TableName() string {
+	return "alert_notification_history"
+}
+
+// NotificationHistoryQuery represents a query for the notification history of an organization.
+// Entries are returned from the most recent.
+type NotificationHistoryQuery struct {
+	OrgID       int64
+	Receiver    string
+	Integration string
+	Status      string
+	From        time.Time
+	To          time.Time
+	Limit       int
+}
@@ -134,6 +134,12 @@ func WithSequentialGroupIndex() AlertRuleMutator {
 	}
 }
 
+func WithIsPaused(paused bool) AlertRuleMutator {
+	return func(rule *AlertRule) {
+		rule.IsPaused = paused
+	}
+}
+
 func WithOrgID(orgId int64) AlertRuleMutator {
 	return func(rule *AlertRule) {
 		rule.OrgID = orgId
@@ -164,6 +170,23 @@ func WithFor(duration time.Duration) AlertRuleMutator {
 	}
 }
 
+func WithKeepFiringFor(duration time.Duration) AlertRuleMutator {
+	return func(rule *AlertRule) {
+		rule.KeepFiringFor = duration
+	}
+}
+
+// WithRecord makes the rule a recording rule that writes the result of its first query as the given metric.
+func WithRecord(metric string) AlertRuleMutator {
+	return func(rule *AlertRule) {
+		rule.Condition = rule.Data[0].RefID
+		rule.Record = &Record{
+			Metric: metric,
+			From:   rule.Condition,
+		}
+	}
+}
+
 func GenerateAlertLabels(count int, prefix string) data.Labels {
 	labels := make(data.Labels, count)
 	for i := 0; i < count; i++ {
@@ -256,6 +279,8 @@ func CopyRule(r *AlertRule) *AlertRule {
 		NoDataState:     r.NoDataState,
 		ExecErrState:    r.ExecErrState,
 		For:             r.For,
+		KeepFiringFor:   r.KeepFiringFor,
+		IsPaused:        r.IsPaused,
 	}
 
 	if r.DashboardUID != nil {
@@ -266,6 +291,10 @@ func CopyRule(r *AlertRule) *AlertRule {
 		p := *r.PanelID
 		result.PanelID = &p
 	}
+	if r.Record != nil {
+		record := *r.Record
+		result.Record = &record
+	}
 
 	for _, d := range r.Data {
 		q := AlertQuery{
@@ -4,6 +4,7 @@ import (
 	"context"
 	"fmt"
 	"net/url"
+	"time"
 
 	"github.com/benbjohnson/clock"
 	"golang.org/x/sync/errgroup"
@@ -35,6 +36,7 @@ import (
 	"github.com/grafana/grafana/pkg/services/ngalert/state"
 	"github.com/grafana/grafana/pkg/services/ngalert/state/historian"
 	"github.com/grafana/grafana/pkg/services/ngalert/store"
+	"github.com/grafana/grafana/pkg/services/ngalert/writer"
 	"github.com/grafana/grafana/pkg/services/notifications"
 	"github.com/grafana/grafana/pkg/services/quota"
 	"github.com/grafana/grafana/pkg/services/rendering"
@@ -157,7 +159,12 @@ func (ng *AlertNG) init() error {
 		return err
 	}
 
-	imageService, err := image.NewScreenshotImageServiceFromCfg(ng.Cfg, store, ng.dashboardService, ng.renderService, ng.Metrics.Registerer)
+	imageStorage, err := image.NewStoringServiceFromCfg(ng.Cfg, ng.SQLStore, ng.Metrics.Registerer)
+	if err != nil {
+		return err
+	}
+
+	imageService, err := image.NewScreenshotImageServiceFromCfg(ng.Cfg, store, ng.dashboardService, ng.renderService, imageStorage, ng.Metrics.Registerer)
 	if err != nil {
 		return err
 	}
@@ -187,21 +194,30 @@ func (ng *AlertNG) init() error {
 	ng.AlertsRouter = alertsRouter
 
 	evalFactory := eval.NewEvaluatorFactory(ng.Cfg.UnifiedAlerting, ng.DataSourceCache, ng.ExpressionService, ng.pluginsStore)
+	recordingWriter, err := configureRecordingWriter(ng.Cfg.UnifiedAlerting.RecordingRules)
+	if err != nil {
+		return err
+	}
 	schedCfg := schedule.SchedulerCfg{
-		MaxAttempts:          ng.Cfg.UnifiedAlerting.MaxAttempts,
-		C:                    clk,
-		BaseInterval:         ng.Cfg.UnifiedAlerting.BaseInterval,
-		MinRuleInterval:      ng.Cfg.UnifiedAlerting.MinInterval,
-		DisableGrafanaFolder: ng.Cfg.UnifiedAlerting.ReservedLabels.IsReservedLabelDisabled(models.FolderTitleLabel),
-		AppURL:               appUrl,
-		EvaluatorFactory:     evalFactory,
-		RuleStore:            store,
-		Metrics:              ng.Metrics.GetSchedulerMetrics(),
-		AlertSender:          alertsRouter,
+		MaxAttempts:           ng.Cfg.UnifiedAlerting.MaxAttempts,
+		C:                     clk,
+		BaseInterval:          ng.Cfg.UnifiedAlerting.BaseInterval,
+		MinRuleInterval:       ng.Cfg.UnifiedAlerting.MinInterval,
+		DisableGrafanaFolder:  ng.Cfg.UnifiedAlerting.ReservedLabels.IsReservedLabelDisabled(models.FolderTitleLabel),
+		DisableRecordingRules: !ng.Cfg.UnifiedAlerting.RecordingRules.Enabled,
+		AppURL:                appUrl,
+		EvaluatorFactory:      evalFactory,
+		RuleStore:             store,
+		Metrics:               ng.Metrics.GetSchedulerMetrics(),
+		AlertSender:           alertsRouter,
+		RecordingWriter:       recordingWriter,
 	}
 
-	historian := historian.NewAnnotationHistorian(ng.annotationsRepo, ng.dashboardService)
-	stateManager := state.NewManager(ng.Metrics.GetStateMetrics(), appUrl, store, ng.imageService, clk, historian)
+	history, err := configureHistorianBackend(ng.Cfg.UnifiedAlerting.StateHistory, ng.annotationsRepo, ng.dashboardService, store)
+	if err != nil {
+		return err
+	}
+	stateManager := state.NewManager(ng.Metrics.GetStateMetrics(), appUrl, store, ng.imageService, clk, history)
 	scheduler := schedule.NewScheduler(schedCfg, stateManager)
 
 	// if it is required to include folder title to the alerts, we need to subscribe to changes of alert title
@@ -219,7 +235,8 @@ func (ng *AlertNG) init() error {
 	muteTimingService := provisioning.NewMuteTimingService(store, store, store, ng.Log)
 	alertRuleService := provisioning.NewAlertRuleService(store, store, ng.QuotaService, store,
 		int64(ng.Cfg.UnifiedAlerting.DefaultRuleEvaluationInterval.Seconds()),
-		int64(ng.Cfg.UnifiedAlerting.BaseInterval.Seconds()), ng.Log)
+		int64(ng.Cfg.UnifiedAlerting.BaseInterval.Seconds()),
+		ng.Cfg.UnifiedAlerting.RecordingRules.Enabled, ng.Log)
 
 	api := api.API{
 		Cfg:                  ng.Cfg,
@@ -244,6 +261,8 @@ func (ng *AlertNG) init() error {
 		AlertRules:           alertRuleService,
 		AlertsRouter:         alertsRouter,
 		EvaluatorFactory:     evalFactory,
+		Historian:            history,
+		ImageStorage:         imageStorage,
 	}
 	api.RegisterAPIEndpoints(ng.Metrics.GetAPIMetrics())
 
@@ -330,6 +349,53 @@ func (ng *AlertNG) IsDisabled() bool {
 	return !ng.Cfg.UnifiedAlerting.IsEnabled()
 }
 
+// historianBackend is a state history backend that can both record and query state transitions.
+type historianBackend interface {
+	state.Historian
+	api.Historian
+}
+
+func configureHistorianBackend(cfg setting.UnifiedAlertingStateHistorySettings, ar annotations.Repository, ds dashboards.DashboardService, rs historian.RuleStore) (historianBackend, error) {
+	if !cfg.Enabled {
+		return historian.NewNopHistorian(), nil
+	}
+
+	backend, ok := historian.ParseBackendType(cfg.Backend)
+	if !ok {
+		return nil, fmt.Errorf("unrecognized state history backend: %s", cfg.Backend)
+	}
+
+	switch backend {
+	case historian.BackendTypeLoki:
+		lcfg, err := historian.NewLokiConfig(cfg)
+		if err != nil {
+			return nil, fmt.Errorf("invalid remote loki configuration: %w", err)
+		}
+		backend := historian.NewRemoteLokiBackend(lcfg)
+
+		testConnCtx, cancelFunc := context.WithTimeout(context.Background(), 10*time.Second)
+		defer cancelFunc()
+		if err := backend.TestConnection(testConnCtx); err != nil {
+			// Loki might come up after Grafana, so do not fail the startup and keep pushing to it.
+			log.New("ngalert.state.historian").Error("Failed to communicate with configured remote Loki backend, state history may not be persisted", "error", err)
+		}
+		return backend, nil
+	default:
+		return historian.NewAnnotationHistorian(ar, ds, rs), nil
+	}
+}
+
+func configureRecordingWriter(cfg setting.UnifiedAlertingRecordingRuleSettings) (schedule.RecordingWriter, error) {
+	if !cfg.Enabled {
+		return writer.NoopWriter{}, nil
+	}
+	w, err := writer.NewPrometheusWriter(cfg, log.New("ngalert.writer"))
+	if err != nil {
+		return nil, fmt.Errorf("invalid recording rules configuration: %w", err)
+	}
+	return w, nil
+}
+
 func readQuotaConfig(cfg *setting.Cfg) (*quota.Map, error) {
 	limits := &quota.Map{}
 
@@ -89,6 +89,8 @@ type ClusterPeer interface {
 type AlertingStore interface {
 	store.AlertingStore
 	store.ImageStore
+	store.NotificationHistoryStore
+	store.NotificationDeadLetterStore
 }
 
 type Alertmanager struct {
@@ -125,6 +127,7 @@ type Alertmanager struct {
 	muteTimes map[string][]timeinterval.TimeInterval
 
 	stageMetrics      *notify.Metrics
+	retryMetrics      *retryMetrics
 	dispatcherMetrics *dispatch.DispatcherMetrics
 
 	reloadConfigMtx sync.RWMutex
@@ -153,6 +156,12 @@ func newAlertmanager(ctx context.Context, orgID int64, cfg *setting.Cfg, store A
 		decryptFn:           decryptFn,
 	}
 
+	var err error
+	am.retryMetrics, err = newRetryMetrics(m.Registerer)
+	if err != nil {
+		am.logger.Warn("Failed to register the metrics of notification retries, some of them are not exported", "error", err)
+	}
+
 	am.fileStore = NewFileStore(am.orgID, kvStore, am.WorkingDirPath())
 
 	nflogFilepath, err := am.fileStore.FilepathFor(ctx, notificationLogFilename)
@@ -207,6 +216,20 @@ func newAlertmanager(ctx context.Context, orgID int64, cfg *setting.Cfg, store A
 		am.wg.Done()
 	}()
 
+	// Delete the notification history older than the retention period.
+	am.wg.Add(1)
+	go func() {
+		defer am.wg.Done()
+		am.notificationHistoryMaintenance(maintenanceNotificationAndSilences, cfg.UnifiedAlerting.NotificationHistory.Retention)
+	}()
+
+	// Delete the dead letters older than the retention period.
+	am.wg.Add(1)
+	go func() {
+		defer am.wg.Done()
+		am.deadLetterMaintenance(maintenanceNotificationAndSilences, cfg.UnifiedAlerting.NotificationRetry.DeadLetterRetention)
+	}()
+
 	// Initialize in-memory alerts
 	am.alerts, err = mem.NewAlerts(context.Background(), am.marker, memoryAlertsGCInterval, nil, am.logger, m.Registerer)
 	if err != nil {
@@ -405,6 +428,9 @@ func (am *Alertmanager) applyConfig(cfg *apimodels.PostableUserConfig, rawConfig
 		return fmt.Errorf("failed to build integration map: %w", err)
 	}
 
+	// Build the retry policies of the integrations, in the same order as the integrations.
+	retryPolicies := am.buildRetryPoliciesMap(cfg.AlertmanagerConfig.Receivers)
+
 	// Now, let's put together our notification pipeline
 	routingStage := make(notify.RoutingStage, len(integrationsMap))
 
@@ -431,7 +457,7 @@ func (am *Alertmanager) applyConfig(cfg *apimodels.PostableUserConfig, rawConfig
 	var receivers []*notify.Receiver
 	activeReceivers := am.getActiveReceiversMap(am.route)
 	for name := range integrationsMap {
-		stage := am.createReceiverStage(name, integrationsMap[name], am.waitFunc, am.notificationLog)
+		stage := am.createReceiverStage(name, integrationsMap[name], retryPolicies[name], am.waitFunc, am.notificationLog)
 		routingStage[name] = notify.MultiStage{meshStage, silencingStage, timeMuteStage, inhibitionStage, stage}
 		_, isActive := activeReceivers[name]
 
@@ -483,12 +509,36 @@ func (am *Alertmanager) buildReceiverIntegrations(receiver *apimodels.PostableAp
 		if err != nil {
 			return nil, err
 		}
-		integrations = append(integrations, notify.NewIntegration(n, n, r.Type, i))
+		// The attempts of the integration are counted for the notification history.
+		integrations = append(integrations, notify.NewIntegration(attemptsCountingNotifier{n}, n, r.Type, i))
 	}
 	return integrations, nil
 }
 
+// buildRetryPoliciesMap builds a map of name to the list of retry policies of the Grafana integrations off of a list of receiver config.
+// The retry policy of an integration is nil if failed notifications are retried until the notification times out.
+func (am *Alertmanager) buildRetryPoliciesMap(receivers []*apimodels.PostableApiReceiver) map[string][]*retryPolicy {
+	policiesMap := make(map[string][]*retryPolicy, len(receivers))
+	for _, receiver := range receivers {
+		policies := make([]*retryPolicy, 0, len(receiver.GrafanaManagedReceivers))
+		for _, r := range receiver.GrafanaManagedReceivers {
+			policies = append(policies, newRetryPolicy(r.RetryPolicy, am.Settings.UnifiedAlerting.NotificationRetry))
+		}
+		policiesMap[receiver.Name] = policies
+	}
+	return policiesMap
+}
+
 func (am *Alertmanager) buildReceiverIntegration(r *apimodels.PostableGrafanaReceiver, tmpl *template.Template) (channels.NotificationChannel, error) {
+	if r.RetryPolicy != nil {
+		if err := r.RetryPolicy.Validate(); err != nil {
+			return nil, InvalidReceiverError{
+				Receiver: r,
+				Err:      fmt.Errorf("invalid retry policy: %w", err),
+			}
+		}
+	}
+
 	// secure settings are already encrypted at this point
 	secureSettings := make(map[string][]byte, len(r.SecureSettings))
 
@@ -677,18 +727,31 @@ func (e AlertValidationError) Error() string {
 }
 
 // createReceiverStage creates a pipeline of stages for a receiver.
-func (am *Alertmanager) createReceiverStage(name string, integrations []*notify.Integration, wait func() time.Duration, notificationLog notify.NotificationLog) notify.Stage {
+func (am *Alertmanager) createReceiverStage(name string, integrations []*notify.Integration, retryPolicies []*retryPolicy, wait func() time.Duration, notificationLog notify.NotificationLog) notify.Stage {
 	var fs notify.FanoutStage
-	for _, integration := range integrations {
+	for i, integration := range integrations {
 		recv := &nflogpb.Receiver{
 			GroupName:   name,
 			Integration: integration.Name(),
 			Idx:         uint32(integration.Index()),
 		}
+		var retry notify.Stage
+		if i < len(retryPolicies) && retryPolicies[i] != nil {
+			retry = newRetryStage(integration, name, retryPolicies[i], am.retryMetrics)
+		} else {
+			retry = notify.NewRetryStage(integration, name, am.stageMetrics)
+		}
+		if am.Settings.UnifiedAlerting.NotificationHistory.Enabled {
+			retry = newNotificationHistoryStage(retry, am.Store, am.orgID, name, integration, am.logger)
+		}
+		if am.Settings.UnifiedAlerting.NotificationRetry.DeadLetterEnabled {
+			retry = newDeadLetterStage(retry, am.Store, am.orgID, name, integration, am.logger)
+		}
+
 		var s notify.MultiStage
 		s = append(s, notify.NewWaitStage(wait))
 		s = append(s, notify.NewDedupStage(integration, notificationLog, recv))
-		s = append(s, notify.NewRetryStage(integration, name, am.stageMetrics))
+		s = append(s, retry)
 		s = append(s, notify.NewSetNotifiesStage(notificationLog, recv))
 
 		fs = append(fs, s)
@@ -69,6 +69,7 @@ func (moa *MultiOrgAlertmanager) GetAlertmanagerConfiguration(ctx context.Contex
 				DisableResolveMessage: pr.DisableResolveMessage,
 				Settings:              pr.Settings,
 				SecureFields:          secureFields,
+				RetryPolicy:           pr.RetryPolicy,
 			}
 			receivers = append(receivers, &gr)
 		}
@@ -55,6 +55,9 @@ var receiverFactories = map[string]func(FactoryConfig) (NotificationChannel, err
 	"googlechat":              GoogleChatFactory,
 	"kafka":                   KafkaFactory,
 	"line":                    LineFactory,
+	"matrix":                  MatrixFactory,
+	"mattermost":              MattermostFactory,
+	"mqtt":                    MQTTFactory,
 	"opsgenie":                OpsgenieFactory,
 	"pagerduty":               PagerdutyFactory,
 	"pushover":                PushoverFactory,
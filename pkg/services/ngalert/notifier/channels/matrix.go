@@ -0,0 +1,181 @@
+package channels
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"html"
+	"net/http"
+	"net/url"
+	"strconv"
+	"strings"
+
+	"github.com/prometheus/alertmanager/template"
+	"github.com/prometheus/alertmanager/types"
+
+	"github.com/grafana/grafana/pkg/infra/log"
+	"github.com/grafana/grafana/pkg/models"
+	ngmodels "github.com/grafana/grafana/pkg/services/ngalert/models"
+	"github.com/grafana/grafana/pkg/services/notifications"
+)
+
+// MatrixNotifier is responsible for sending alert notifications as messages to a Matrix room.
+type MatrixNotifier struct {
+	*Base
+	ns       notifications.WebhookSender
+	log      log.Logger
+	images   ImageStore
+	tmpl     *template.Template
+	settings *matrixSettings
+}
+
+type matrixSettings struct {
+	HomeserverURL string `json:"homeserver_url,omitempty" yaml:"homeserver_url,omitempty"`
+	AccessToken   string `json:"access_token,omitempty" yaml:"access_token,omitempty"`
+	RoomID        string `json:"room_id,omitempty" yaml:"room_id,omitempty"`
+	Title         string `json:"title,omitempty" yaml:"title,omitempty"`
+	Message       string `json:"message,omitempty" yaml:"message,omitempty"`
+}
+
+func buildMatrixSettings(fc FactoryConfig) (*matrixSettings, error) {
+	settings := &matrixSettings{}
+	err := fc.Config.unmarshalSettings(&settings)
+	if err != nil {
+		return nil, fmt.Errorf("failed to unmarshal settings: %w", err)
+	}
+
+	if settings.HomeserverURL == "" {
+		return nil, errors.New("could not find homeserver url property in settings")
+	}
+	u, err := url.Parse(settings.HomeserverURL)
+	if err != nil {
+		return nil, fmt.Errorf("invalid homeserver URL %q", settings.HomeserverURL)
+	}
+	settings.HomeserverURL = strings.TrimSuffix(u.String(), "/")
+
+	if settings.RoomID == "" {
+		return nil, errors.New("could not find room id property in settings")
+	}
+
+	settings.AccessToken = fc.DecryptFunc(context.Background(), fc.Config.SecureSettings, "access_token", settings.AccessToken)
+	if settings.AccessToken == "" {
+		return nil, errors.New("could not find access token in settings")
+	}
+
+	if settings.Title == "" {
+		settings.Title = DefaultMessageTitleEmbed
+	}
+	if settings.Message == "" {
+		settings.Message = DefaultMessageEmbed
+	}
+	return settings, nil
+}
+
+func MatrixFactory(fc FactoryConfig) (NotificationChannel, error) {
+	notifier, err := buildMatrixNotifier(fc)
+	if err != nil {
+		return nil, receiverInitError{
+			Reason: err.Error(),
+			Cfg:    *fc.Config,
+		}
+	}
+	return notifier, nil
+}
+
+// buildMatrixNotifier is the constructor for the Matrix notifier.
+func buildMatrixNotifier(fc FactoryConfig) (*MatrixNotifier, error) {
+	settings, err := buildMatrixSettings(fc)
+	if err != nil {
+		return nil, err
+	}
+
+	return &MatrixNotifier{
+		Base: NewBase(&models.AlertNotification{
+			Uid:                   fc.Config.UID,
+			Name:                  fc.Config.Name,
+			Type:                  fc.Config.Type,
+			DisableResolveMessage: fc.Config.DisableResolveMessage,
+			Settings:              fc.Config.Settings,
+		}),
+		log:      log.New("alerting.notifier.matrix"),
+		ns:       fc.NotificationService,
+		images:   fc.ImageStore,
+		tmpl:     fc.Template,
+		settings: settings,
+	}, nil
+}
+
+// MatrixMessage defines the JSON object of a m.room.message event sent to the Matrix client-server API.
+type MatrixMessage struct {
+	MsgType       string `json:"msgtype"`
+	Body          string `json:"body"`
+	Format        string `json:"format,omitempty"`
+	FormattedBody string `json:"formatted_body,omitempty"`
+}
+
+// Notify sends the alert notification as a message to the Matrix room.
+func (mn *MatrixNotifier) Notify(ctx context.Context, as ...*types.Alert) (bool, error) {
+	var tmplErr error
+	tmpl, _ := TmplText(ctx, mn.tmpl, as, mn.log, &tmplErr)
+
+	title := tmpl(mn.settings.Title)
+	message := tmpl(mn.settings.Message)
+	if tmplErr != nil {
+		mn.log.Warn("Failed to template Matrix message", "error", tmplErr.Error())
+		tmplErr = nil
+	}
+
+	var imageURLs []string
+	_ = withStoredImages(ctx, mn.log, mn.images, func(_ int, image ngmodels.Image) error {
+		if image.HasURL() {
+			imageURLs = append(imageURLs, image.URL)
+		}
+		return nil
+	}, as...)
+
+	alerts := types.Alerts(as...)
+	msg := MatrixMessage{
+		MsgType:       "m.text",
+		Body:          strings.Join(append([]string{title, message}, imageURLs...), "\n"),
+		Format:        "org.matrix.custom.html",
+		FormattedBody: matrixFormattedBody(title, message, getAlertStatusColor(alerts.Status()), imageURLs),
+	}
+	body, err := json.Marshal(msg)
+	if err != nil {
+		return false, err
+	}
+
+	// The transaction ID makes the request idempotent, so it must be unique for every notification.
+	txnID := "grafana-" + strconv.FormatInt(timeNow().UnixNano(), 10)
+	cmd := &models.SendWebhookSync{
+		Url: fmt.Sprintf("%s/_matrix/client/v3/rooms/%s/send/m.room.message/%s",
+			mn.settings.HomeserverURL, url.PathEscape(mn.settings.RoomID), txnID),
+		Body:       string(body),
+		HttpMethod: http.MethodPut,
+		HttpHeader: map[string]string{
+			"Authorization": "Bearer " + mn.settings.AccessToken,
+		},
+		ContentType: "application/json",
+	}
+	if err := mn.ns.SendWebhookSync(ctx, cmd); err != nil {
+		mn.log.Error("Failed to send Matrix message", "error", err)
+		return false, err
+	}
+	return true, nil
+}
+
+// matrixFormattedBody renders the title and message as HTML. The message is escaped, and line breaks are kept.
+func matrixFormattedBody(title, message, color string, imageURLs []string) string {
+	var b strings.Builder
+	fmt.Fprintf(&b, `<h4><font color="%s">%s</font></h4>`, color, html.EscapeString(title))
+	b.WriteString(strings.ReplaceAll(html.EscapeString(message), "\n", "<br>"))
+	for _, u := range imageURLs {
+		fmt.Fprintf(&b, `<br><a href="%s">%s</a>`, html.EscapeString(u), html.EscapeString(u))
+	}
+	return b.String()
+}
+
+func (mn *MatrixNotifier) SendResolved() bool {
+	return !mn.GetDisableResolveMessage()
+}
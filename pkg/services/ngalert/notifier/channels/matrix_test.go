@@ -0,0 +1,183 @@
+package channels
+
+import (
+	"context"
+	"encoding/json"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/prometheus/alertmanager/notify"
+	"github.com/prometheus/alertmanager/types"
+	"github.com/prometheus/common/model"
+	"github.com/stretchr/testify/require"
+
+	"github.com/grafana/grafana/pkg/components/simplejson"
+	"github.com/grafana/grafana/pkg/services/secrets/fakes"
+	secretsManager "github.com/grafana/grafana/pkg/services/secrets/manager"
+)
+
+func TestMatrixNotifier(t *testing.T) {
+	tmpl := templateForTests(t)
+
+	externalURL, err := url.Parse("http://localhost")
+	require.NoError(t, err)
+	tmpl.ExternalURL = externalURL
+
+	defer mockTimeNow(time.Unix(1, 0))()
+
+	type request struct {
+		method string
+		path   string
+		auth   string
+		body   string
+	}
+
+	cases := []struct {
+		name         string
+		settings     string
+		imageStore   ImageStore
+		statusCode   int
+		alerts       []*types.Alert
+		expMsg       map[string]interface{}
+		expInitError string
+		expMsgError  string
+	}{
+		{
+			name:     "Default config with one alert",
+			settings: `{"homeserver_url": "{{server}}", "room_id": "!room:example.com", "access_token": "token"}`,
+			alerts: []*types.Alert{
+				{
+					Alert: model.Alert{
+						Labels:      model.LabelSet{"alertname": "alert1", "lbl1": "val1"},
+						Annotations: model.LabelSet{"ann1": "annv1", "__dashboardUid__": "abcd", "__panelId__": "efgh"},
+					},
+				},
+			},
+			expMsg: map[string]interface{}{
+				"msgtype":        "m.text",
+				"body":           "[FIRING:1]  (val1)\n**Firing**\n\nValue: [no value]\nLabels:\n - alertname = alert1\n - lbl1 = val1\nAnnotations:\n - ann1 = annv1\nSilence: http://localhost/alerting/silence/new?alertmanager=grafana&matcher=alertname%3Dalert1&matcher=lbl1%3Dval1\nDashboard: http://localhost/d/abcd\nPanel: http://localhost/d/abcd?viewPanel=efgh\n",
+				"format":         "org.matrix.custom.html",
+				"formatted_body": `<h4><font color="#D63232">[FIRING:1]  (val1)</font></h4>**Firing**<br><br>Value: [no value]<br>Labels:<br> - alertname = alert1<br> - lbl1 = val1<br>Annotations:<br> - ann1 = annv1<br>Silence: http://localhost/alerting/silence/new?alertmanager=grafana&amp;matcher=alertname%3Dalert1&amp;matcher=lbl1%3Dval1<br>Dashboard: http://localhost/d/abcd<br>Panel: http://localhost/d/abcd?viewPanel=efgh<br>`,
+			},
+		},
+		{
+			name:       "Custom title and message with an image",
+			settings:   `{"homeserver_url": "{{server}}/", "room_id": "!room:example.com", "access_token": "token", "title": "{{ .Status }} <b>", "message": "{{ len .Alerts.Firing }} firing\n{{ len .Alerts.Resolved }} resolved"}`,
+			imageStore: newFakeImageStore(1),
+			alerts: []*types.Alert{
+				{
+					Alert: model.Alert{
+						Labels:      model.LabelSet{"alertname": "alert1", "lbl1": "val1"},
+						Annotations: model.LabelSet{"__alertImageToken__": "test-image-1"},
+						EndsAt:      time.Unix(0, 0),
+					},
+				},
+			},
+			expMsg: map[string]interface{}{
+				"msgtype":        "m.text",
+				"body":           "resolved <b>\n0 firing\n1 resolved\nhttps://www.example.com/test-image-1.jpg",
+				"format":         "org.matrix.custom.html",
+				"formatted_body": `<h4><font color="#36a64f">resolved &lt;b&gt;</font></h4>0 firing<br>1 resolved<br><a href="https://www.example.com/test-image-1.jpg">https://www.example.com/test-image-1.jpg</a>`,
+			},
+		},
+		{
+			name:       "Error response from the homeserver",
+			settings:   `{"homeserver_url": "{{server}}", "room_id": "!room:example.com", "access_token": "token"}`,
+			statusCode: http.StatusForbidden,
+			alerts: []*types.Alert{
+				{
+					Alert: model.Alert{
+						Labels: model.LabelSet{"alertname": "alert1"},
+					},
+				},
+			},
+			expMsgError: "webhook response status 403 Forbidden",
+		},
+		{
+			name:         "Error if the homeserver url is missing",
+			settings:     `{"room_id": "!room:example.com", "access_token": "token"}`,
+			expInitError: "could not find homeserver url property in settings",
+		},
+		{
+			name:         "Error if the room id is missing",
+			settings:     `{"homeserver_url": "{{server}}", "access_token": "token"}`,
+			expInitError: "could not find room id property in settings",
+		},
+		{
+			name:         "Error if the access token is missing",
+			settings:     `{"homeserver_url": "{{server}}", "room_id": "!room:example.com"}`,
+			expInitError: "could not find access token in settings",
+		},
+	}
+
+	for _, c := range cases {
+		t.Run(c.name, func(t *testing.T) {
+			var requests []request
+			server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				b, err := io.ReadAll(r.Body)
+				require.NoError(t, err)
+				requests = append(requests, request{method: r.Method, path: r.URL.EscapedPath(), auth: r.Header.Get("Authorization"), body: string(b)})
+				if c.statusCode != 0 {
+					w.WriteHeader(c.statusCode)
+				}
+				_, _ = w.Write([]byte(`{"event_id": "$event"}`))
+			}))
+			defer server.Close()
+
+			settingsJSON, err := simplejson.NewJson([]byte(strings.ReplaceAll(c.settings, "{{server}}", server.URL)))
+			require.NoError(t, err)
+
+			secretsService := secretsManager.SetupTestService(t, fakes.NewFakeSecretsStore())
+
+			imageStore := c.imageStore
+			if imageStore == nil {
+				imageStore = &UnavailableImageStore{}
+			}
+
+			fc := FactoryConfig{
+				Config: &NotificationChannelConfig{
+					Name:     "matrix_testing",
+					Type:     "matrix",
+					Settings: settingsJSON,
+				},
+				NotificationService: CreateNotificationService(t),
+				DecryptFunc:         secretsService.GetDecryptedValue,
+				ImageStore:          imageStore,
+				Template:            tmpl,
+			}
+
+			pn, err := buildMatrixNotifier(fc)
+			if c.expInitError != "" {
+				require.EqualError(t, err, c.expInitError)
+				return
+			}
+			require.NoError(t, err)
+
+			ctx := notify.WithGroupKey(context.Background(), "alertname")
+			ctx = notify.WithGroupLabels(ctx, model.LabelSet{"alertname": ""})
+
+			ok, err := pn.Notify(ctx, c.alerts...)
+			if c.expMsgError != "" {
+				require.False(t, ok)
+				require.EqualError(t, err, c.expMsgError)
+				return
+			}
+			require.NoError(t, err)
+			require.True(t, ok)
+
+			require.Len(t, requests, 1)
+			require.Equal(t, http.MethodPut, requests[0].method)
+			require.Equal(t, "/_matrix/client/v3/rooms/%21room:example.com/send/m.room.message/grafana-1000000000", requests[0].path)
+			require.Equal(t, "Bearer token", requests[0].auth)
+
+			expBody, err := json.Marshal(c.expMsg)
+			require.NoError(t, err)
+			require.JSONEq(t, string(expBody), requests[0].body)
+		})
+	}
+}
@@ -0,0 +1,159 @@
+package channels
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+
+	"github.com/prometheus/alertmanager/template"
+	"github.com/prometheus/alertmanager/types"
+
+	"github.com/grafana/grafana/pkg/infra/log"
+	"github.com/grafana/grafana/pkg/models"
+	ngmodels "github.com/grafana/grafana/pkg/services/ngalert/models"
+	"github.com/grafana/grafana/pkg/services/notifications"
+	"github.com/grafana/grafana/pkg/setting"
+)
+
+// MattermostNotifier is responsible for sending alert notifications to a Mattermost incoming webhook.
+type MattermostNotifier struct {
+	*Base
+	ns       notifications.WebhookSender
+	log      log.Logger
+	images   ImageStore
+	tmpl     *template.Template
+	settings *mattermostSettings
+}
+
+type mattermostSettings struct {
+	URL      string `json:"url,omitempty" yaml:"url,omitempty"`
+	Channel  string `json:"channel,omitempty" yaml:"channel,omitempty"`
+	Username string `json:"username,omitempty" yaml:"username,omitempty"`
+	IconURL  string `json:"icon_url,omitempty" yaml:"icon_url,omitempty"`
+	Title    string `json:"title,omitempty" yaml:"title,omitempty"`
+	Text     string `json:"text,omitempty" yaml:"text,omitempty"`
+}
+
+func buildMattermostSettings(fc FactoryConfig) (*mattermostSettings, error) {
+	settings := &mattermostSettings{}
+	err := fc.Config.unmarshalSettings(&settings)
+	if err != nil {
+		return nil, fmt.Errorf("failed to unmarshal settings: %w", err)
+	}
+
+	settings.URL = fc.DecryptFunc(context.Background(), fc.Config.SecureSettings, "url", settings.URL)
+	if settings.URL == "" {
+		return nil, errors.New("could not find webhook url property in settings")
+	}
+
+	if settings.Title == "" {
+		settings.Title = DefaultMessageTitleEmbed
+	}
+	if settings.Text == "" {
+		settings.Text = DefaultMessageEmbed
+	}
+	return settings, nil
+}
+
+func MattermostFactory(fc FactoryConfig) (NotificationChannel, error) {
+	notifier, err := buildMattermostNotifier(fc)
+	if err != nil {
+		return nil, receiverInitError{
+			Reason: err.Error(),
+			Cfg:    *fc.Config,
+		}
+	}
+	return notifier, nil
+}
+
+// buildMattermostNotifier is the constructor for the Mattermost notifier.
+func buildMattermostNotifier(fc FactoryConfig) (*MattermostNotifier, error) {
+	settings, err := buildMattermostSettings(fc)
+	if err != nil {
+		return nil, err
+	}
+
+	return &MattermostNotifier{
+		Base: NewBase(&models.AlertNotification{
+			Uid:                   fc.Config.UID,
+			Name:                  fc.Config.Name,
+			Type:                  fc.Config.Type,
+			DisableResolveMessage: fc.Config.DisableResolveMessage,
+			Settings:              fc.Config.Settings,
+		}),
+		log:      log.New("alerting.notifier.mattermost"),
+		ns:       fc.NotificationService,
+		images:   fc.ImageStore,
+		tmpl:     fc.Template,
+		settings: settings,
+	}, nil
+}
+
+// mattermostMessage is the payload of a Mattermost incoming webhook. Mattermost accepts Slack compatible
+// attachments, see https://developers.mattermost.com/integrate/reference/message-attachments/.
+type mattermostMessage struct {
+	Channel     string       `json:"channel,omitempty"`
+	Username    string       `json:"username,omitempty"`
+	IconURL     string       `json:"icon_url,omitempty"`
+	Attachments []attachment `json:"attachments"`
+}
+
+// Notify sends an alert notification to Mattermost.
+func (mn *MattermostNotifier) Notify(ctx context.Context, as ...*types.Alert) (bool, error) {
+	var tmplErr error
+	tmpl, _ := TmplText(ctx, mn.tmpl, as, mn.log, &tmplErr)
+
+	ruleURL := joinUrlPath(mn.tmpl.ExternalURL.String(), "/alerting/list", mn.log)
+
+	msg := mattermostMessage{
+		Channel:  tmpl(mn.settings.Channel),
+		Username: tmpl(mn.settings.Username),
+		IconURL:  tmpl(mn.settings.IconURL),
+		Attachments: []attachment{
+			{
+				Color:      getAlertStatusColor(types.Alerts(as...).Status()),
+				Title:      tmpl(mn.settings.Title),
+				TitleLink:  ruleURL,
+				Fallback:   tmpl(mn.settings.Title),
+				Text:       tmpl(mn.settings.Text),
+				Footer:     "Grafana v" + setting.BuildVersion,
+				FooterIcon: FooterIconURL,
+			},
+		},
+	}
+	if tmplErr != nil {
+		mn.log.Warn("Failed to template Mattermost message", "error", tmplErr.Error())
+	}
+
+	// Incoming webhooks cannot upload files, instead share images via their URL.
+	_ = withStoredImages(ctx, mn.log, mn.images, func(index int, image ngmodels.Image) error {
+		if image.HasURL() {
+			msg.Attachments[0].ImageURL = image.URL
+			return ErrImagesDone
+		}
+		return nil
+	}, as...)
+
+	body, err := json.Marshal(msg)
+	if err != nil {
+		return false, err
+	}
+
+	cmd := &models.SendWebhookSync{
+		Url:         mn.settings.URL,
+		Body:        string(body),
+		HttpMethod:  http.MethodPost,
+		ContentType: "application/json",
+	}
+	if err := mn.ns.SendWebhookSync(ctx, cmd); err != nil {
+		mn.log.Error("Failed to send Mattermost message", "error", err)
+		return false, err
+	}
+	return true, nil
+}
+
+func (mn *MattermostNotifier) SendResolved() bool {
+	return !mn.GetDisableResolveMessage()
+}
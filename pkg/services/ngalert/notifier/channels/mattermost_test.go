@@ -0,0 +1,184 @@
+package channels
+
+import (
+	"context"
+	"encoding/json"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"testing"
+
+	"github.com/prometheus/alertmanager/notify"
+	"github.com/prometheus/alertmanager/types"
+	"github.com/prometheus/common/model"
+	"github.com/stretchr/testify/require"
+
+	"github.com/grafana/grafana/pkg/components/simplejson"
+	"github.com/grafana/grafana/pkg/services/secrets/fakes"
+	secretsManager "github.com/grafana/grafana/pkg/services/secrets/manager"
+	"github.com/grafana/grafana/pkg/setting"
+)
+
+func TestMattermostNotifier(t *testing.T) {
+	tmpl := templateForTests(t)
+
+	externalURL, err := url.Parse("http://localhost")
+	require.NoError(t, err)
+	tmpl.ExternalURL = externalURL
+
+	cases := []struct {
+		name         string
+		settings     string
+		imageStore   ImageStore
+		statusCode   int
+		alerts       []*types.Alert
+		expMsg       *mattermostMessage
+		expInitError string
+		expMsgError  string
+	}{
+		{
+			name:     "Default config with one alert",
+			settings: `{}`,
+			alerts: []*types.Alert{
+				{
+					Alert: model.Alert{
+						Labels:      model.LabelSet{"alertname": "alert1", "lbl1": "val1"},
+						Annotations: model.LabelSet{"ann1": "annv1", "__dashboardUid__": "abcd", "__panelId__": "efgh"},
+					},
+				},
+			},
+			expMsg: &mattermostMessage{
+				Attachments: []attachment{
+					{
+						Title:      "[FIRING:1]  (val1)",
+						TitleLink:  "http://localhost/alerting/list",
+						Text:       "**Firing**\n\nValue: [no value]\nLabels:\n - alertname = alert1\n - lbl1 = val1\nAnnotations:\n - ann1 = annv1\nSilence: http://localhost/alerting/silence/new?alertmanager=grafana&matcher=alertname%3Dalert1&matcher=lbl1%3Dval1\nDashboard: http://localhost/d/abcd\nPanel: http://localhost/d/abcd?viewPanel=efgh\n",
+						Fallback:   "[FIRING:1]  (val1)",
+						Footer:     "Grafana v" + setting.BuildVersion,
+						FooterIcon: FooterIconURL,
+						Color:      ColorAlertFiring,
+					},
+				},
+			},
+		},
+		{
+			name:       "Custom config with an image",
+			settings:   `{"channel": "alerts", "username": "Grafana", "icon_url": "https://example.com/icon.png", "title": "{{ .Status }}", "text": "{{ len .Alerts.Firing }} alerts are firing"}`,
+			imageStore: newFakeImageStore(2),
+			alerts: []*types.Alert{
+				{
+					Alert: model.Alert{
+						Labels:      model.LabelSet{"alertname": "alert1"},
+						Annotations: model.LabelSet{"__alertImageToken__": "test-image-1"},
+					},
+				}, {
+					Alert: model.Alert{
+						Labels:      model.LabelSet{"alertname": "alert2"},
+						Annotations: model.LabelSet{"__alertImageToken__": "test-image-2"},
+					},
+				},
+			},
+			expMsg: &mattermostMessage{
+				Channel:  "alerts",
+				Username: "Grafana",
+				IconURL:  "https://example.com/icon.png",
+				Attachments: []attachment{
+					{
+						Title:      "firing",
+						TitleLink:  "http://localhost/alerting/list",
+						Text:       "2 alerts are firing",
+						ImageURL:   "https://www.example.com/test-image-1.jpg",
+						Fallback:   "firing",
+						Footer:     "Grafana v" + setting.BuildVersion,
+						FooterIcon: FooterIconURL,
+						Color:      ColorAlertFiring,
+					},
+				},
+			},
+		},
+		{
+			name:       "Error response from Mattermost",
+			settings:   `{}`,
+			statusCode: http.StatusBadRequest,
+			alerts: []*types.Alert{
+				{
+					Alert: model.Alert{
+						Labels: model.LabelSet{"alertname": "alert1"},
+					},
+				},
+			},
+			expMsgError: "webhook response status 400 Bad Request",
+		},
+		{
+			name:         "Error if the url is missing",
+			settings:     `{"url": ""}`,
+			expInitError: "could not find webhook url property in settings",
+		},
+	}
+
+	for _, c := range cases {
+		t.Run(c.name, func(t *testing.T) {
+			var body []byte
+			server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				require.Equal(t, http.MethodPost, r.Method)
+				require.Equal(t, "application/json", r.Header.Get("Content-Type"))
+				b, err := io.ReadAll(r.Body)
+				require.NoError(t, err)
+				body = b
+				if c.statusCode != 0 {
+					w.WriteHeader(c.statusCode)
+				}
+			}))
+			defer server.Close()
+
+			settingsJSON, err := simplejson.NewJson([]byte(c.settings))
+			require.NoError(t, err)
+			if _, ok := settingsJSON.CheckGet("url"); !ok {
+				settingsJSON.Set("url", server.URL)
+			}
+
+			secretsService := secretsManager.SetupTestService(t, fakes.NewFakeSecretsStore())
+
+			imageStore := c.imageStore
+			if imageStore == nil {
+				imageStore = &UnavailableImageStore{}
+			}
+
+			fc := FactoryConfig{
+				Config: &NotificationChannelConfig{
+					Name:     "mattermost_testing",
+					Type:     "mattermost",
+					Settings: settingsJSON,
+				},
+				NotificationService: CreateNotificationService(t),
+				DecryptFunc:         secretsService.GetDecryptedValue,
+				ImageStore:          imageStore,
+				Template:            tmpl,
+			}
+
+			pn, err := buildMattermostNotifier(fc)
+			if c.expInitError != "" {
+				require.EqualError(t, err, c.expInitError)
+				return
+			}
+			require.NoError(t, err)
+
+			ctx := notify.WithGroupKey(context.Background(), "alertname")
+			ctx = notify.WithGroupLabels(ctx, model.LabelSet{"alertname": ""})
+
+			ok, err := pn.Notify(ctx, c.alerts...)
+			if c.expMsgError != "" {
+				require.False(t, ok)
+				require.EqualError(t, err, c.expMsgError)
+				return
+			}
+			require.NoError(t, err)
+			require.True(t, ok)
+
+			expBody, err := json.Marshal(c.expMsg)
+			require.NoError(t, err)
+			require.JSONEq(t, string(expBody), string(body))
+		})
+	}
+}
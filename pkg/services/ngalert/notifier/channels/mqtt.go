@@ -0,0 +1,327 @@
+package channels
+
+import (
+	"context"
+	"crypto/tls"
+	"crypto/x509"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/url"
+	"strconv"
+	"time"
+	"unicode/utf8"
+
+	mqtt "github.com/eclipse/paho.mqtt.golang"
+	"github.com/prometheus/alertmanager/notify"
+	"github.com/prometheus/alertmanager/template"
+	"github.com/prometheus/alertmanager/types"
+	"github.com/prometheus/common/model"
+
+	"github.com/grafana/grafana/pkg/infra/log"
+	"github.com/grafana/grafana/pkg/models"
+	ngmodels "github.com/grafana/grafana/pkg/services/ngalert/models"
+	"github.com/grafana/grafana/pkg/util"
+)
+
+const (
+	MQTTMessageFormatJSON = "json"
+	MQTTMessageFormatText = "text"
+
+	mqttConnectTimeout    = 10 * time.Second
+	mqttDisconnectQuiesce = 250 // milliseconds
+
+	// mqttMaxClientIDLength is the longest client ID that MQTT 3.1 brokers must accept.
+	mqttMaxClientIDLength = 23
+)
+
+// MQTTNotifier is responsible for publishing alert notifications to a topic of an MQTT broker.
+type MQTTNotifier struct {
+	*Base
+	log      log.Logger
+	images   ImageStore
+	tmpl     *template.Template
+	orgID    int64
+	settings mqttSettings
+}
+
+type mqttSettings struct {
+	BrokerURL          string
+	ClientID           string
+	Topic              string
+	MessageFormat      string
+	Message            string
+	Username           string
+	Password           string
+	QoS                byte
+	Retain             bool
+	InsecureSkipVerify bool
+	CACertificate      string
+	ClientCertificate  string
+	ClientKey          string
+}
+
+func buildMQTTSettings(fc FactoryConfig) (mqttSettings, error) {
+	settings := mqttSettings{}
+	rawSettings := struct {
+		BrokerURL          string      `json:"broker_url,omitempty" yaml:"broker_url,omitempty"`
+		ClientID           string      `json:"client_id,omitempty" yaml:"client_id,omitempty"`
+		Topic              string      `json:"topic,omitempty" yaml:"topic,omitempty"`
+		MessageFormat      string      `json:"message_format,omitempty" yaml:"message_format,omitempty"`
+		Message            string      `json:"message,omitempty" yaml:"message,omitempty"`
+		Username           string      `json:"username,omitempty" yaml:"username,omitempty"`
+		Password           string      `json:"password,omitempty" yaml:"password,omitempty"`
+		QoS                json.Number `json:"qos,omitempty" yaml:"qos,omitempty"`
+		Retain             bool        `json:"retain,omitempty" yaml:"retain,omitempty"`
+		InsecureSkipVerify bool        `json:"insecure_skip_verify,omitempty" yaml:"insecure_skip_verify,omitempty"`
+		CACertificate      string      `json:"tls_ca_certificate,omitempty" yaml:"tls_ca_certificate,omitempty"`
+		ClientCertificate  string      `json:"tls_client_certificate,omitempty" yaml:"tls_client_certificate,omitempty"`
+		ClientKey          string      `json:"tls_client_key,omitempty" yaml:"tls_client_key,omitempty"`
+	}{}
+
+	err := fc.Config.unmarshalSettings(&rawSettings)
+	if err != nil {
+		return settings, fmt.Errorf("failed to unmarshal settings: %w", err)
+	}
+
+	if rawSettings.BrokerURL == "" {
+		return settings, errors.New("required field 'broker_url' is not specified")
+	}
+	if _, err := url.Parse(rawSettings.BrokerURL); err != nil {
+		return settings, fmt.Errorf("invalid broker URL %q", rawSettings.BrokerURL)
+	}
+	settings.BrokerURL = rawSettings.BrokerURL
+
+	if rawSettings.Topic == "" {
+		return settings, errors.New("required field 'topic' is not specified")
+	}
+	settings.Topic = rawSettings.Topic
+
+	settings.ClientID = rawSettings.ClientID
+	if settings.ClientID == "" {
+		settings.ClientID = "grafana"
+	}
+
+	switch rawSettings.MessageFormat {
+	case "":
+		settings.MessageFormat = MQTTMessageFormatJSON
+	case MQTTMessageFormatJSON, MQTTMessageFormatText:
+		settings.MessageFormat = rawSettings.MessageFormat
+	default:
+		return settings, fmt.Errorf("invalid message format %q, must be %q or %q", rawSettings.MessageFormat, MQTTMessageFormatJSON, MQTTMessageFormatText)
+	}
+
+	settings.Message = rawSettings.Message
+	if settings.Message == "" {
+		settings.Message = DefaultMessageEmbed
+	}
+
+	if rawSettings.QoS != "" {
+		qos, err := strconv.Atoi(rawSettings.QoS.String())
+		if err != nil || qos < 0 || qos > 2 {
+			return settings, fmt.Errorf("invalid QoS %q, must be 0, 1 or 2", rawSettings.QoS)
+		}
+		settings.QoS = byte(qos)
+	}
+	settings.Retain = rawSettings.Retain
+
+	settings.Username = rawSettings.Username
+	settings.Password = fc.DecryptFunc(context.Background(), fc.Config.SecureSettings, "password", rawSettings.Password)
+
+	settings.InsecureSkipVerify = rawSettings.InsecureSkipVerify
+	settings.CACertificate = rawSettings.CACertificate
+	settings.ClientCertificate = rawSettings.ClientCertificate
+	settings.ClientKey = fc.DecryptFunc(context.Background(), fc.Config.SecureSettings, "tls_client_key", rawSettings.ClientKey)
+	if (settings.ClientCertificate == "") != (settings.ClientKey == "") {
+		return settings, errors.New("both the TLS client certificate and key must be specified")
+	}
+	if _, err := settings.tlsConfig(); err != nil {
+		return settings, err
+	}
+
+	return settings, nil
+}
+
+// tlsConfig returns the TLS configuration of the connection to the broker. It is only used by brokers with a TLS
+// scheme, such as ssl:// or wss://.
+func (s mqttSettings) tlsConfig() (*tls.Config, error) {
+	cfg := &tls.Config{
+		InsecureSkipVerify: s.InsecureSkipVerify,
+	}
+	if s.CACertificate != "" {
+		pool := x509.NewCertPool()
+		if !pool.AppendCertsFromPEM([]byte(s.CACertificate)) {
+			return nil, errors.New("failed to parse the TLS CA certificate")
+		}
+		cfg.RootCAs = pool
+	}
+	if s.ClientCertificate != "" {
+		cert, err := tls.X509KeyPair([]byte(s.ClientCertificate), []byte(s.ClientKey))
+		if err != nil {
+			return nil, fmt.Errorf("failed to parse the TLS client certificate: %w", err)
+		}
+		cfg.Certificates = []tls.Certificate{cert}
+	}
+	return cfg, nil
+}
+
+func MQTTFactory(fc FactoryConfig) (NotificationChannel, error) {
+	notifier, err := buildMQTTNotifier(fc)
+	if err != nil {
+		return nil, receiverInitError{
+			Reason: err.Error(),
+			Cfg:    *fc.Config,
+		}
+	}
+	return notifier, nil
+}
+
+// buildMQTTNotifier is the constructor for the MQTT notifier.
+func buildMQTTNotifier(fc FactoryConfig) (*MQTTNotifier, error) {
+	settings, err := buildMQTTSettings(fc)
+	if err != nil {
+		return nil, err
+	}
+	return &MQTTNotifier{
+		Base: NewBase(&models.AlertNotification{
+			Uid:                   fc.Config.UID,
+			Name:                  fc.Config.Name,
+			Type:                  fc.Config.Type,
+			DisableResolveMessage: fc.Config.DisableResolveMessage,
+			Settings:              fc.Config.Settings,
+		}),
+		orgID:    fc.Config.OrgID,
+		log:      log.New("alerting.notifier.mqtt"),
+		images:   fc.ImageStore,
+		tmpl:     fc.Template,
+		settings: settings,
+	}, nil
+}
+
+// Notify publishes the alert notification to the topic. Depending on the message format, the payload is either the
+// templated message or a JSON object with the same shape as the payload of the webhook notifier.
+func (n *MQTTNotifier) Notify(ctx context.Context, as ...*types.Alert) (bool, error) {
+	payload, err := n.buildPayload(ctx, as...)
+	if err != nil {
+		return false, err
+	}
+
+	if err := n.publish(ctx, payload); err != nil {
+		n.log.Error("Failed to publish MQTT message", "topic", n.settings.Topic, "error", err)
+		return false, err
+	}
+	return true, nil
+}
+
+func (n *MQTTNotifier) buildPayload(ctx context.Context, as ...*types.Alert) ([]byte, error) {
+	var tmplErr error
+	tmpl, data := TmplText(ctx, n.tmpl, as, n.log, &tmplErr)
+
+	if n.settings.MessageFormat == MQTTMessageFormatText {
+		message := tmpl(n.settings.Message)
+		if tmplErr != nil {
+			n.log.Warn("Failed to template MQTT message", "error", tmplErr.Error())
+		}
+		return []byte(message), nil
+	}
+
+	groupKey, err := notify.ExtractGroupKey(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	// Augment our Alert data with ImageURLs if available.
+	_ = withStoredImages(ctx, n.log, n.images,
+		func(index int, image ngmodels.Image) error {
+			if len(image.URL) != 0 {
+				data.Alerts[index].ImageURL = image.URL
+			}
+			return nil
+		},
+		as...)
+
+	msg := &WebhookMessage{
+		Version:      "1",
+		ExtendedData: data,
+		GroupKey:     groupKey.String(),
+		OrgID:        n.orgID,
+		Title:        tmpl(DefaultMessageTitleEmbed),
+		Message:      tmpl(n.settings.Message),
+	}
+	if types.Alerts(as...).Status() == model.AlertFiring {
+		msg.State = string(models.AlertStateAlerting)
+	} else {
+		msg.State = string(models.AlertStateOK)
+	}
+
+	if tmplErr != nil {
+		n.log.Warn("Failed to template MQTT message", "error", tmplErr.Error())
+	}
+
+	return json.Marshal(msg)
+}
+
+// publish connects to the broker, publishes the payload and disconnects. A connection is not kept between
+// notifications as they are infrequent.
+func (n *MQTTNotifier) publish(ctx context.Context, payload []byte) error {
+	tlsCfg, err := n.settings.tlsConfig()
+	if err != nil {
+		return err
+	}
+
+	// Brokers disconnect a client when another one connects with the same ID, and notifications of different alert
+	// groups are sent concurrently, so every connection gets a unique ID.
+	clientID := mqttClientID(n.settings.ClientID, util.GenerateShortUID())
+
+	opts := mqtt.NewClientOptions().
+		AddBroker(n.settings.BrokerURL).
+		SetClientID(clientID).
+		SetUsername(n.settings.Username).
+		SetPassword(n.settings.Password).
+		SetTLSConfig(tlsCfg).
+		SetConnectTimeout(mqttConnectTimeout).
+		SetAutoReconnect(false).
+		SetCleanSession(true)
+
+	client := mqtt.NewClient(opts)
+	if err := waitMQTTToken(ctx, client.Connect()); err != nil {
+		return fmt.Errorf("failed to connect to the MQTT broker: %w", err)
+	}
+	defer client.Disconnect(mqttDisconnectQuiesce)
+
+	if err := waitMQTTToken(ctx, client.Publish(n.settings.Topic, n.settings.QoS, n.settings.Retain, payload)); err != nil {
+		return fmt.Errorf("failed to publish the MQTT message: %w", err)
+	}
+	return nil
+}
+
+// mqttClientID returns the client ID of a connection from the configured prefix and a unique suffix. The prefix is
+// truncated so that the ID isn't longer than MQTT 3.1 brokers accept.
+func mqttClientID(prefix, suffix string) string {
+	suffix = "_" + suffix
+	maxPrefix := mqttMaxClientIDLength - len(suffix)
+	if maxPrefix < 0 {
+		maxPrefix = 0
+	}
+	if len(prefix) > maxPrefix {
+		// Truncate at a rune boundary so the ID stays valid UTF-8.
+		for maxPrefix > 0 && !utf8.RuneStart(prefix[maxPrefix]) {
+			maxPrefix--
+		}
+		prefix = prefix[:maxPrefix]
+	}
+	return prefix + suffix
+}
+
+func waitMQTTToken(ctx context.Context, token mqtt.Token) error {
+	select {
+	case <-token.Done():
+		return token.Error()
+	case <-ctx.Done():
+		return ctx.Err()
+	}
+}
+
+func (n *MQTTNotifier) SendResolved() bool {
+	return !n.GetDisableResolveMessage()
+}
@@ -0,0 +1,397 @@
+package channels
+
+import (
+	"context"
+	"crypto/tls"
+	"encoding/json"
+	"encoding/pem"
+	"fmt"
+	"net"
+	"net/http/httptest"
+	"net/url"
+	"strings"
+	"sync"
+	"testing"
+
+	"github.com/eclipse/paho.mqtt.golang/packets"
+	"github.com/prometheus/alertmanager/notify"
+	"github.com/prometheus/alertmanager/types"
+	"github.com/prometheus/common/model"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/grafana/grafana/pkg/components/simplejson"
+	"github.com/grafana/grafana/pkg/services/secrets/fakes"
+	secretsManager "github.com/grafana/grafana/pkg/services/secrets/manager"
+)
+
+func TestMQTTNotifier(t *testing.T) {
+	tmpl := templateForTests(t)
+
+	externalURL, err := url.Parse("http://localhost")
+	require.NoError(t, err)
+	tmpl.ExternalURL = externalURL
+
+	alerts := []*types.Alert{
+		{
+			Alert: model.Alert{
+				Labels:      model.LabelSet{"alertname": "alert1", "lbl1": "val1"},
+				Annotations: model.LabelSet{"ann1": "annv1", "__alertImageToken__": "test-image-1"},
+			},
+		},
+	}
+
+	cases := []struct {
+		name         string
+		settings     string
+		tls          bool
+		expPublish   *packets.PublishPacket
+		expConnect   *packets.ConnectPacket
+		expMsg       map[string]interface{}
+		expText      string
+		expInitError string
+	}{
+		{
+			name:     "Default config publishes the JSON payload",
+			settings: `{"broker_url": "tcp://{{broker}}", "topic": "grafana/alerts", "client_id": "grafana"}`,
+			expConnect: &packets.ConnectPacket{
+				ClientIdentifier: "grafana",
+			},
+			expPublish: &packets.PublishPacket{
+				TopicName: "grafana/alerts",
+			},
+			expMsg: map[string]interface{}{
+				"alerts": []interface{}{
+					map[string]interface{}{
+						"status":       "firing",
+						"labels":       map[string]interface{}{"alertname": "alert1", "lbl1": "val1"},
+						"annotations":  map[string]interface{}{"ann1": "annv1"},
+						"startsAt":     "0001-01-01T00:00:00Z",
+						"endsAt":       "0001-01-01T00:00:00Z",
+						"generatorURL": "",
+						"fingerprint":  "fac0861a85de433a",
+						"silenceURL":   "http://localhost/alerting/silence/new?alertmanager=grafana&matcher=alertname%3Dalert1&matcher=lbl1%3Dval1",
+						"dashboardURL": "",
+						"panelURL":     "",
+						"imageURL":     "https://www.example.com/test-image-1.jpg",
+						"values":       nil,
+						"valueString":  "",
+					},
+				},
+				"status":            "firing",
+				"receiver":          "",
+				"groupLabels":       map[string]interface{}{"alertname": ""},
+				"commonLabels":      map[string]interface{}{"alertname": "alert1", "lbl1": "val1"},
+				"commonAnnotations": map[string]interface{}{"ann1": "annv1"},
+				"externalURL":       "http://localhost",
+				"version":           "1",
+				"groupKey":          "alertname",
+				"truncatedAlerts":   float64(0),
+				"orgId":             float64(0),
+				"title":             "[FIRING:1]  (val1)",
+				"state":             "alerting",
+				"message":           "**Firing**\n\nValue: [no value]\nLabels:\n - alertname = alert1\n - lbl1 = val1\nAnnotations:\n - ann1 = annv1\nSilence: http://localhost/alerting/silence/new?alertmanager=grafana&matcher=alertname%3Dalert1&matcher=lbl1%3Dval1\n",
+			},
+		},
+		{
+			name: "Text format with QoS 1, retain and credentials",
+			settings: `{
+				"broker_url": "tcp://{{broker}}",
+				"topic": "grafana/alerts",
+				"client_id": "grafana",
+				"message_format": "text",
+				"message": "{{ len .Alerts.Firing }} alerts are firing",
+				"qos": 1,
+				"retain": true,
+				"username": "user",
+				"password": "pass"
+			}`,
+			expConnect: &packets.ConnectPacket{
+				ClientIdentifier: "grafana",
+				UsernameFlag:     true,
+				Username:         "user",
+				PasswordFlag:     true,
+				Password:         []byte("pass"),
+			},
+			expPublish: &packets.PublishPacket{
+				FixedHeader: packets.FixedHeader{Qos: 1, Retain: true},
+				TopicName:   "grafana/alerts",
+			},
+			expText: "1 alerts are firing",
+		},
+		{
+			name:     "Text format with QoS 2 over TLS",
+			settings: `{"broker_url": "ssl://{{broker}}", "topic": "grafana/alerts", "client_id": "grafana", "message_format": "text", "message": "{{ .Status }}", "qos": "2", "tls_ca_certificate": "{{ca}}"}`,
+			tls:      true,
+			expConnect: &packets.ConnectPacket{
+				ClientIdentifier: "grafana",
+			},
+			expPublish: &packets.PublishPacket{
+				FixedHeader: packets.FixedHeader{Qos: 2},
+				TopicName:   "grafana/alerts",
+			},
+			expText: "firing",
+		},
+		{
+			name:         "Error if the broker url is missing",
+			settings:     `{"topic": "grafana/alerts"}`,
+			expInitError: "required field 'broker_url' is not specified",
+		},
+		{
+			name:         "Error if the topic is missing",
+			settings:     `{"broker_url": "tcp://{{broker}}"}`,
+			expInitError: "required field 'topic' is not specified",
+		},
+		{
+			name:         "Error if the message format is invalid",
+			settings:     `{"broker_url": "tcp://{{broker}}", "topic": "grafana/alerts", "message_format": "xml"}`,
+			expInitError: `invalid message format "xml", must be "json" or "text"`,
+		},
+		{
+			name:         "Error if the QoS is invalid",
+			settings:     `{"broker_url": "tcp://{{broker}}", "topic": "grafana/alerts", "qos": 3}`,
+			expInitError: `invalid QoS "3", must be 0, 1 or 2`,
+		},
+		{
+			name:         "Error if the TLS client key is missing",
+			settings:     `{"broker_url": "tcp://{{broker}}", "topic": "grafana/alerts", "tls_client_certificate": "cert"}`,
+			expInitError: "both the TLS client certificate and key must be specified",
+		},
+	}
+
+	for _, c := range cases {
+		t.Run(c.name, func(t *testing.T) {
+			broker := newFakeMQTTBroker(t, c.tls)
+
+			settings := strings.ReplaceAll(c.settings, "{{broker}}", broker.addr)
+			settings = strings.ReplaceAll(settings, "{{ca}}", strings.ReplaceAll(broker.caPEM, "\n", `\n`))
+			settingsJSON, err := simplejson.NewJson([]byte(settings))
+			require.NoError(t, err)
+
+			secretsService := secretsManager.SetupTestService(t, fakes.NewFakeSecretsStore())
+
+			fc := FactoryConfig{
+				Config: &NotificationChannelConfig{
+					Name:     "mqtt_testing",
+					Type:     "mqtt",
+					Settings: settingsJSON,
+				},
+				DecryptFunc: secretsService.GetDecryptedValue,
+				ImageStore:  newFakeImageStore(1),
+				Template:    tmpl,
+			}
+
+			pn, err := buildMQTTNotifier(fc)
+			if c.expInitError != "" {
+				require.EqualError(t, err, c.expInitError)
+				return
+			}
+			require.NoError(t, err)
+
+			ctx := notify.WithGroupKey(context.Background(), "alertname")
+			ctx = notify.WithGroupLabels(ctx, model.LabelSet{"alertname": ""})
+
+			ok, err := pn.Notify(ctx, alerts...)
+			require.NoError(t, err)
+			require.True(t, ok)
+
+			connect, publish := broker.received()
+			require.True(t, strings.HasPrefix(connect.ClientIdentifier, c.expConnect.ClientIdentifier+"_"), "client id %q must have the prefix %q", connect.ClientIdentifier, c.expConnect.ClientIdentifier)
+			require.Equal(t, c.expConnect.Username, connect.Username)
+			require.Equal(t, c.expConnect.Password, connect.Password)
+			require.Equal(t, c.expPublish.TopicName, publish.TopicName)
+			require.Equal(t, c.expPublish.Qos, publish.Qos)
+			require.Equal(t, c.expPublish.Retain, publish.Retain)
+
+			if c.expMsg != nil {
+				expBody, err := json.Marshal(c.expMsg)
+				require.NoError(t, err)
+				require.JSONEq(t, string(expBody), string(publish.Payload))
+			} else {
+				require.Equal(t, c.expText, string(publish.Payload))
+			}
+		})
+	}
+}
+
+func TestMQTTNotifier_ConnectionRefused(t *testing.T) {
+	tmpl := templateForTests(t)
+
+	externalURL, err := url.Parse("http://localhost")
+	require.NoError(t, err)
+	tmpl.ExternalURL = externalURL
+
+	// Take a free port and close it, so that nothing is listening on it.
+	l, err := net.Listen("tcp", "127.0.0.1:0")
+	require.NoError(t, err)
+	addr := l.Addr().String()
+	require.NoError(t, l.Close())
+
+	settingsJSON, err := simplejson.NewJson([]byte(`{"broker_url": "tcp://` + addr + `", "topic": "grafana/alerts"}`))
+	require.NoError(t, err)
+	secretsService := secretsManager.SetupTestService(t, fakes.NewFakeSecretsStore())
+
+	pn, err := buildMQTTNotifier(FactoryConfig{
+		Config:      &NotificationChannelConfig{Name: "mqtt_testing", Type: "mqtt", Settings: settingsJSON},
+		DecryptFunc: secretsService.GetDecryptedValue,
+		ImageStore:  &UnavailableImageStore{},
+		Template:    tmpl,
+	})
+	require.NoError(t, err)
+
+	ctx := notify.WithGroupKey(context.Background(), "alertname")
+	ok, err := pn.Notify(ctx, &types.Alert{Alert: model.Alert{Labels: model.LabelSet{"alertname": "alert1"}}})
+	require.False(t, ok)
+	require.ErrorContains(t, err, "failed to connect to the MQTT broker")
+}
+
+func TestMQTTNotifier_ConcurrentNotify(t *testing.T) {
+	tmpl := templateForTests(t)
+
+	externalURL, err := url.Parse("http://localhost")
+	require.NoError(t, err)
+	tmpl.ExternalURL = externalURL
+
+	broker := newFakeMQTTBroker(t, false)
+	settingsJSON, err := simplejson.NewJson([]byte(`{"broker_url": "tcp://` + broker.addr + `", "topic": "grafana/alerts", "client_id": "grafana"}`))
+	require.NoError(t, err)
+	secretsService := secretsManager.SetupTestService(t, fakes.NewFakeSecretsStore())
+
+	pn, err := buildMQTTNotifier(FactoryConfig{
+		Config:      &NotificationChannelConfig{Name: "mqtt_testing", Type: "mqtt", Settings: settingsJSON},
+		DecryptFunc: secretsService.GetDecryptedValue,
+		ImageStore:  &UnavailableImageStore{},
+		Template:    tmpl,
+	})
+	require.NoError(t, err)
+
+	// Notifications of different alert groups are sent concurrently by the same notifier, so they must not share
+	// a client ID, otherwise the broker disconnects one of them.
+	const notifications = 5
+	var wg sync.WaitGroup
+	for i := 0; i < notifications; i++ {
+		wg.Add(1)
+		go func(i int) {
+			defer wg.Done()
+			ctx := notify.WithGroupKey(context.Background(), fmt.Sprintf("alertname-%d", i))
+			ok, err := pn.Notify(ctx, &types.Alert{Alert: model.Alert{Labels: model.LabelSet{"alertname": model.LabelValue(fmt.Sprintf("alert%d", i))}}})
+			assert.NoError(t, err)
+			assert.True(t, ok)
+		}(i)
+	}
+	wg.Wait()
+
+	clientIDs := map[string]struct{}{}
+	for i := 0; i < notifications; i++ {
+		connect, _ := broker.received()
+		assert.LessOrEqual(t, len(connect.ClientIdentifier), mqttMaxClientIDLength)
+		clientIDs[connect.ClientIdentifier] = struct{}{}
+	}
+	require.Len(t, clientIDs, notifications)
+}
+
+func TestMQTTClientID(t *testing.T) {
+	for _, tc := range []struct {
+		name, prefix, suffix, expected string
+	}{
+		{name: "short prefix is kept", prefix: "grafana", suffix: "abcdefghi", expected: "grafana_abcdefghi"},
+		{name: "long prefix is truncated", prefix: "grafana-production-instance", suffix: "abcdefghi", expected: "grafana-produ_abcdefghi"},
+		{name: "prefix is truncated at a rune boundary", prefix: "grafana-aleré", suffix: "abcdefghi", expected: "grafana-aler_abcdefghi"},
+	} {
+		t.Run(tc.name, func(t *testing.T) {
+			assert.Equal(t, tc.expected, mqttClientID(tc.prefix, tc.suffix))
+		})
+	}
+}
+
+// fakeMQTTBroker is a minimal MQTT broker that accepts any number of clients and records the messages they publish.
+type fakeMQTTBroker struct {
+	addr     string
+	caPEM    string
+	connects chan *packets.ConnectPacket
+	messages chan *packets.PublishPacket
+}
+
+func newFakeMQTTBroker(t *testing.T, useTLS bool) *fakeMQTTBroker {
+	t.Helper()
+
+	b := &fakeMQTTBroker{
+		connects: make(chan *packets.ConnectPacket, 10),
+		messages: make(chan *packets.PublishPacket, 10),
+	}
+
+	var l net.Listener
+	var err error
+	if useTLS {
+		// Reuse the self-signed certificate of the test HTTP server.
+		srv := httptest.NewUnstartedServer(nil)
+		srv.StartTLS()
+		certs := srv.TLS.Certificates
+		b.caPEM = string(pem.EncodeToMemory(&pem.Block{Type: "CERTIFICATE", Bytes: srv.Certificate().Raw}))
+		srv.Close()
+		l, err = tls.Listen("tcp", "127.0.0.1:0", &tls.Config{Certificates: certs})
+	} else {
+		l, err = net.Listen("tcp", "127.0.0.1:0")
+	}
+	require.NoError(t, err)
+	t.Cleanup(func() { _ = l.Close() })
+	b.addr = l.Addr().String()
+
+	go func() {
+		for {
+			conn, err := l.Accept()
+			if err != nil {
+				return
+			}
+			go func() {
+				defer func() { _ = conn.Close() }()
+				b.serve(conn)
+			}()
+		}
+	}()
+	return b
+}
+
+func (b *fakeMQTTBroker) serve(conn net.Conn) {
+	for {
+		p, err := packets.ReadPacket(conn)
+		if err != nil {
+			return
+		}
+		var resp packets.ControlPacket
+		switch p := p.(type) {
+		case *packets.ConnectPacket:
+			b.connects <- p
+			resp = packets.NewControlPacket(packets.Connack)
+		case *packets.PublishPacket:
+			b.messages <- p
+			switch p.Qos {
+			case 1:
+				ack := packets.NewControlPacket(packets.Puback).(*packets.PubackPacket)
+				ack.MessageID = p.MessageID
+				resp = ack
+			case 2:
+				rec := packets.NewControlPacket(packets.Pubrec).(*packets.PubrecPacket)
+				rec.MessageID = p.MessageID
+				resp = rec
+			}
+		case *packets.PubrelPacket:
+			comp := packets.NewControlPacket(packets.Pubcomp).(*packets.PubcompPacket)
+			comp.MessageID = p.MessageID
+			resp = comp
+		case *packets.PingreqPacket:
+			resp = packets.NewControlPacket(packets.Pingresp)
+		case *packets.DisconnectPacket:
+			return
+		}
+		if resp != nil {
+			if err := resp.Write(conn); err != nil {
+				return
+			}
+		}
+	}
+}
+
+func (b *fakeMQTTBroker) received() (*packets.ConnectPacket, *packets.PublishPacket) {
+	return <-b.connects, <-b.messages
+}
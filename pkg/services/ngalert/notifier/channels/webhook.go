@@ -1,17 +1,24 @@
 package channels
 
 import (
+	"bufio"
 	"context"
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
 	"encoding/json"
 	"errors"
 	"fmt"
 	"net/http"
+	"net/textproto"
 	"strconv"
+	"strings"
 
 	"github.com/prometheus/alertmanager/notify"
 	"github.com/prometheus/alertmanager/template"
 	"github.com/prometheus/alertmanager/types"
 	"github.com/prometheus/common/model"
+	"golang.org/x/net/http/httpguts"
 
 	"github.com/grafana/grafana/pkg/infra/log"
 	"github.com/grafana/grafana/pkg/models"
@@ -44,8 +51,22 @@ type webhookSettings struct {
 
 	Title   string
 	Message string
+
+	// Extra headers of the request.
+	HTTPHeaders map[string]string
+	// Templated body of the request, which replaces the WebhookMessage when set.
+	PayloadTemplate string
+	// HMAC-SHA256 signature of the request.
+	HMACSecret          string
+	HMACHeader          string
+	HMACTimestampHeader string
 }
 
+const (
+	defaultWebhookHMACHeader          = "X-Grafana-Alerting-Signature"
+	defaultWebhookHMACTimestampHeader = "X-Grafana-Alerting-Timestamp"
+)
+
 func buildWebhookSettings(factoryConfig FactoryConfig) (webhookSettings, error) {
 	settings := webhookSettings{}
 	rawSettings := struct {
@@ -58,6 +79,11 @@ func buildWebhookSettings(factoryConfig FactoryConfig) (webhookSettings, error)
 		Password                 string      `json:"password,omitempty" yaml:"password,omitempty"`
 		Title                    string      `json:"title,omitempty" yaml:"title,omitempty"`
 		Message                  string      `json:"message,omitempty" yaml:"message,omitempty"`
+		HTTPHeaders              string      `json:"http_headers,omitempty" yaml:"http_headers,omitempty"`
+		PayloadTemplate          string      `json:"payload_template,omitempty" yaml:"payload_template,omitempty"`
+		HMACSecret               string      `json:"hmac_secret,omitempty" yaml:"hmac_secret,omitempty"`
+		HMACHeader               string      `json:"hmac_header,omitempty" yaml:"hmac_header,omitempty"`
+		HMACTimestampHeader      string      `json:"hmac_timestamp_header,omitempty" yaml:"hmac_timestamp_header,omitempty"`
 	}{}
 
 	err := factoryConfig.Config.unmarshalSettings(&rawSettings)
@@ -96,9 +122,74 @@ func buildWebhookSettings(factoryConfig FactoryConfig) (webhookSettings, error)
 	if settings.Message == "" {
 		settings.Message = DefaultMessageEmbed
 	}
+
+	headers := factoryConfig.DecryptFunc(context.Background(), factoryConfig.Config.SecureSettings, "http_headers", rawSettings.HTTPHeaders)
+	settings.HTTPHeaders, err = parseWebhookHeaders(headers)
+	if err != nil {
+		return settings, err
+	}
+	settings.PayloadTemplate = rawSettings.PayloadTemplate
+
+	settings.HMACSecret = factoryConfig.DecryptFunc(context.Background(), factoryConfig.Config.SecureSettings, "hmac_secret", rawSettings.HMACSecret)
+	settings.HMACHeader = rawSettings.HMACHeader
+	if settings.HMACHeader == "" {
+		settings.HMACHeader = defaultWebhookHMACHeader
+	}
+	settings.HMACTimestampHeader = rawSettings.HMACTimestampHeader
+	if settings.HMACTimestampHeader == "" {
+		settings.HMACTimestampHeader = defaultWebhookHMACTimestampHeader
+	}
+	for _, name := range []string{settings.HMACHeader, settings.HMACTimestampHeader} {
+		if !httpguts.ValidHeaderFieldName(name) {
+			return settings, fmt.Errorf("invalid HMAC header name %q", name)
+		}
+	}
+	settings.HMACHeader = textproto.CanonicalMIMEHeaderKey(settings.HMACHeader)
+	settings.HMACTimestampHeader = textproto.CanonicalMIMEHeaderKey(settings.HMACTimestampHeader)
+
+	// The extra headers must not replace the headers of the authentication and the signature.
+	var reserved []string
+	if (settings.User != "" && settings.Password != "") || settings.AuthorizationCredentials != "" {
+		reserved = append(reserved, "Authorization")
+	}
+	if settings.HMACSecret != "" {
+		reserved = append(reserved, settings.HMACHeader, settings.HMACTimestampHeader)
+	}
+	for _, name := range reserved {
+		if _, ok := settings.HTTPHeaders[name]; ok {
+			return settings, fmt.Errorf("the HTTP header %q cannot be set because it is set by the authentication or the signature of the webhook", name)
+		}
+	}
 	return settings, err
 }
 
+// parseWebhookHeaders parses the extra headers of the request, which are given one per line as "Name: Value".
+// The names are canonicalized, so a header can only be given once whatever its case.
+func parseWebhookHeaders(s string) (map[string]string, error) {
+	headers := map[string]string{}
+	scanner := bufio.NewScanner(strings.NewReader(s))
+	for scanner.Scan() {
+		line := strings.TrimSpace(scanner.Text())
+		if line == "" {
+			continue
+		}
+		name, value, ok := strings.Cut(line, ":")
+		name, value = strings.TrimSpace(name), strings.TrimSpace(value)
+		if !ok || !httpguts.ValidHeaderFieldName(name) {
+			return nil, fmt.Errorf("invalid HTTP header %q, headers must be given one per line as 'Name: Value'", line)
+		}
+		if !httpguts.ValidHeaderFieldValue(value) {
+			return nil, fmt.Errorf("invalid value of the HTTP header %q", name)
+		}
+		name = textproto.CanonicalMIMEHeaderKey(name)
+		if _, ok := headers[name]; ok {
+			return nil, fmt.Errorf("the HTTP header %q is given more than once", name)
+		}
+		headers[name] = value
+	}
+	return headers, scanner.Err()
+}
+
 func WebHookFactory(fc FactoryConfig) (NotificationChannel, error) {
 	notifier, err := buildWebhookNotifier(fc)
 	if err != nil {
@@ -189,15 +280,32 @@ func (wn *WebhookNotifier) Notify(ctx context.Context, as ...*types.Alert) (bool
 		tmplErr = nil
 	}
 
-	body, err := json.Marshal(msg)
-	if err != nil {
-		return false, err
+	var body []byte
+	if wn.settings.PayloadTemplate != "" {
+		body = []byte(tmpl(wn.settings.PayloadTemplate))
+		if tmplErr != nil {
+			return false, fmt.Errorf("failed to template webhook payload: %w", tmplErr)
+		}
+	} else {
+		body, err = json.Marshal(msg)
+		if err != nil {
+			return false, err
+		}
 	}
 
-	headers := make(map[string]string)
+	// The extra headers never contain the authentication and signature headers, as they are rejected by the settings.
+	headers := make(map[string]string, len(wn.settings.HTTPHeaders))
+	for k, v := range wn.settings.HTTPHeaders {
+		headers[k] = v
+	}
 	if wn.settings.AuthorizationScheme != "" && wn.settings.AuthorizationCredentials != "" {
 		headers["Authorization"] = fmt.Sprintf("%s %s", wn.settings.AuthorizationScheme, wn.settings.AuthorizationCredentials)
 	}
+	if wn.settings.HMACSecret != "" {
+		timestamp := strconv.FormatInt(timeNow().Unix(), 10)
+		headers[wn.settings.HMACTimestampHeader] = timestamp
+		headers[wn.settings.HMACHeader] = webhookSignature(wn.settings.HMACSecret, timestamp, body)
+	}
 
 	parsedURL := tmpl(wn.settings.URL)
 	if tmplErr != nil {
@@ -220,6 +328,15 @@ func (wn *WebhookNotifier) Notify(ctx context.Context, as ...*types.Alert) (bool
 	return true, nil
 }
 
+// webhookSignature returns the hex encoded HMAC-SHA256 of the timestamp and the body, separated by a colon. The
+// timestamp is part of the signature so that receivers can reject replayed requests.
+func webhookSignature(secret, timestamp string, body []byte) string {
+	mac := hmac.New(sha256.New, []byte(secret))
+	_, _ = mac.Write([]byte(timestamp + ":"))
+	_, _ = mac.Write(body)
+	return hex.EncodeToString(mac.Sum(nil))
+}
+
 func truncateAlerts(maxAlerts int, alerts []*types.Alert) ([]*types.Alert, int) {
 	if maxAlerts > 0 && len(alerts) > maxAlerts {
 		return alerts[:maxAlerts], len(alerts) - maxAlerts
@@ -6,8 +6,10 @@ import (
 	"fmt"
 	"net/url"
 	"testing"
+	"time"
 
 	"github.com/grafana/grafana/pkg/components/simplejson"
+	"github.com/grafana/grafana/pkg/services/secrets"
 	"github.com/grafana/grafana/pkg/services/secrets/fakes"
 	secretsManager "github.com/grafana/grafana/pkg/services/secrets/manager"
 
@@ -27,12 +29,16 @@ func TestWebhookNotifier(t *testing.T) {
 
 	orgID := int64(1)
 
+	defer mockTimeNow(time.Unix(1, 0))()
+
 	cases := []struct {
-		name     string
-		settings string
-		alerts   []*types.Alert
+		name           string
+		settings       string
+		secureSettings map[string]string
+		alerts         []*types.Alert
 
 		expMsg        *WebhookMessage
+		expBody       string
 		expUrl        string
 		expUsername   string
 		expPassword   string
@@ -303,6 +309,128 @@ func TestWebhookNotifier(t *testing.T) {
 			expHttpMethod: "POST",
 			expHeaders:    map[string]string{"Authorization": "Bearer mysecret"},
 		},
+		{
+			name: "with extra headers in the secure settings",
+			settings: `{
+				"url": "http://localhost/test1",
+				"http_headers": "X-Env: prod\n\nContent-Type: text/plain ",
+				"payload_template": "{{ len .Alerts.Firing }} alerts are firing"
+			}`,
+			secureSettings: map[string]string{
+				"http_headers": "x-api-key: abc\nX-Env: prod",
+			},
+			alerts: []*types.Alert{
+				{
+					Alert: model.Alert{
+						Labels: model.LabelSet{"alertname": "alert1", "lbl1": "val1"},
+					},
+				},
+			},
+			expBody:       "1 alerts are firing",
+			expUrl:        "http://localhost/test1",
+			expHttpMethod: "POST",
+			expHeaders:    map[string]string{"X-Api-Key": "abc", "X-Env": "prod"},
+		},
+		{
+			name: "with payload template and HMAC signature",
+			settings: `{
+				"url": "http://localhost/test1",
+				"httpMethod": "PUT",
+				"http_headers": "X-Env: prod",
+				"payload_template": "{\"status\": \"{{ .Status }}\"}",
+				"authorization_credentials": "mysecret"
+			}`,
+			secureSettings: map[string]string{
+				"hmac_secret": "secret",
+			},
+			alerts: []*types.Alert{
+				{
+					Alert: model.Alert{
+						Labels: model.LabelSet{"alertname": "alert1", "lbl1": "val1"},
+					},
+				},
+			},
+			expBody:       `{"status": "firing"}`,
+			expUrl:        "http://localhost/test1",
+			expHttpMethod: "PUT",
+			expHeaders: map[string]string{
+				"X-Env":                        "prod",
+				"Authorization":                "Bearer mysecret",
+				"X-Grafana-Alerting-Timestamp": "1",
+				"X-Grafana-Alerting-Signature": "f0555f1a2ef34aaa40366d0143d72714c239bf140b74c524563a691f500632f8",
+			},
+		},
+		{
+			name: "with HMAC signature in custom headers",
+			settings: `{
+				"url": "http://localhost/test1",
+				"payload_template": "{\"status\": \"{{ .Status }}\"}",
+				"hmac_secret": "secret",
+				"hmac_header": "X-Signature",
+				"hmac_timestamp_header": "X-Timestamp"
+			}`,
+			alerts: []*types.Alert{
+				{
+					Alert: model.Alert{
+						Labels: model.LabelSet{"alertname": "alert1", "lbl1": "val1"},
+					},
+				},
+			},
+			expBody:       `{"status": "firing"}`,
+			expUrl:        "http://localhost/test1",
+			expHttpMethod: "POST",
+			expHeaders: map[string]string{
+				"X-Timestamp": "1",
+				"X-Signature": "f0555f1a2ef34aaa40366d0143d72714c239bf140b74c524563a691f500632f8",
+			},
+		},
+		{
+			name:     "bad template in payload",
+			settings: `{"url": "http://localhost/test1", "payload_template": "{{ len Alerts }}"}`,
+			alerts: []*types.Alert{
+				{
+					Alert: model.Alert{
+						Labels: model.LabelSet{"alertname": "alert1", "lbl1": "val1"},
+					},
+				},
+			},
+			expMsgError: fmt.Errorf("failed to template webhook payload: template: :1: function \"Alerts\" not defined"),
+		},
+		{
+			name:         "with invalid extra headers",
+			settings:     `{"url": "http://localhost/test1", "http_headers": "X-Env prod"}`,
+			expInitError: "invalid HTTP header \"X-Env prod\", headers must be given one per line as 'Name: Value'",
+		},
+		{
+			name:         "with the same extra header given twice",
+			settings:     `{"url": "http://localhost/test1", "http_headers": "X-Env: prod\nx-env: dev"}`,
+			expInitError: "the HTTP header \"X-Env\" is given more than once",
+		},
+		{
+			name:         "with the authorization header in the extra headers and basic auth",
+			settings:     `{"url": "http://localhost/test1", "username": "user", "password": "pass", "http_headers": "authorization: Bearer other"}`,
+			expInitError: "the HTTP header \"Authorization\" cannot be set because it is set by the authentication or the signature of the webhook",
+		},
+		{
+			name:         "with the authorization header in the extra headers and authorization credentials",
+			settings:     `{"url": "http://localhost/test1", "authorization_credentials": "mysecret", "http_headers": "Authorization: Bearer other"}`,
+			expInitError: "the HTTP header \"Authorization\" cannot be set because it is set by the authentication or the signature of the webhook",
+		},
+		{
+			name:         "with the HMAC signature header in the extra headers",
+			settings:     `{"url": "http://localhost/test1", "hmac_secret": "secret", "http_headers": "x-grafana-alerting-signature: overridden"}`,
+			expInitError: "the HTTP header \"X-Grafana-Alerting-Signature\" cannot be set because it is set by the authentication or the signature of the webhook",
+		},
+		{
+			name:         "with the HMAC timestamp header in the extra headers",
+			settings:     `{"url": "http://localhost/test1", "hmac_secret": "secret", "hmac_timestamp_header": "x-timestamp", "http_headers": "X-Timestamp: 1"}`,
+			expInitError: "the HTTP header \"X-Timestamp\" cannot be set because it is set by the authentication or the signature of the webhook",
+		},
+		{
+			name:         "with invalid HMAC header",
+			settings:     `{"url": "http://localhost/test1", "hmac_secret": "secret", "hmac_header": "X Signature"}`,
+			expInitError: "invalid HMAC header name \"X Signature\"",
+		},
 		{
 			name:     "bad template in url",
 			settings: `{"url": "http://localhost/test1?numAlerts={{len Alerts}}"}`,
@@ -339,7 +467,15 @@ func TestWebhookNotifier(t *testing.T) {
 		t.Run(c.name, func(t *testing.T) {
 			settingsJSON, err := simplejson.NewJson([]byte(c.settings))
 			require.NoError(t, err)
+			webhookSender := mockNotificationService()
+			secretsService := secretsManager.SetupTestService(t, fakes.NewFakeSecretsStore())
+
 			secureSettings := make(map[string][]byte)
+			for k, v := range c.secureSettings {
+				encrypted, err := secretsService.Encrypt(context.Background(), []byte(v), secrets.WithoutScope())
+				require.NoError(t, err)
+				secureSettings[k] = encrypted
+			}
 
 			m := &NotificationChannelConfig{
 				OrgID:          orgID,
@@ -349,9 +485,6 @@ func TestWebhookNotifier(t *testing.T) {
 				SecureSettings: secureSettings,
 			}
 
-			webhookSender := mockNotificationService()
-			secretsService := secretsManager.SetupTestService(t, fakes.NewFakeSecretsStore())
-
 			fc := FactoryConfig{
 				Config:              m,
 				NotificationService: webhookSender,
@@ -381,10 +514,13 @@ func TestWebhookNotifier(t *testing.T) {
 			require.NoError(t, err)
 			require.True(t, ok)
 
-			expBody, err := json.Marshal(c.expMsg)
-			require.NoError(t, err)
-
-			require.JSONEq(t, string(expBody), webhookSender.Webhook.Body)
+			if c.expBody != "" {
+				require.Equal(t, c.expBody, webhookSender.Webhook.Body)
+			} else {
+				expBody, err := json.Marshal(c.expMsg)
+				require.NoError(t, err)
+				require.JSONEq(t, string(expBody), webhookSender.Webhook.Body)
+			}
 			require.Equal(t, c.expUrl, webhookSender.Webhook.Url)
 			require.Equal(t, c.expUsername, webhookSender.Webhook.User)
 			require.Equal(t, c.expPassword, webhookSender.Webhook.Password)
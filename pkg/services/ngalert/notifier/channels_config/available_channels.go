@@ -763,6 +763,44 @@ func GetAvailableNotifiers() []*NotifierPlugin {
 					PropertyName: "message",
 					Placeholder:  channels.DefaultMessageEmbed,
 				},
+				{ // New in 9.3.
+					Label:        "Extra Headers",
+					Description:  "Extra headers of the request, one per line as 'Name: Value'. Each header can only be given once, and the Authorization and signature headers cannot be set when the authentication or the signature is configured.",
+					Element:      ElementTypeTextArea,
+					PropertyName: "http_headers",
+					Placeholder:  "X-Api-Key: my-key",
+					Secure:       true,
+				},
+				{ // New in 9.3.
+					Label:        "Payload Template",
+					Description:  "Templated body of the request. If it is empty, the default JSON payload is sent. Set the Content-Type in the extra headers if the body is not JSON.",
+					Element:      ElementTypeTextArea,
+					PropertyName: "payload_template",
+				},
+				{ // New in 9.3.
+					Label:        "HMAC Signature - Secret",
+					Description:  "If set, the request is signed with HMAC-SHA256 of the timestamp and the body, separated by a colon.",
+					Element:      ElementTypeInput,
+					InputType:    InputTypePassword,
+					PropertyName: "hmac_secret",
+					Secure:       true,
+				},
+				{ // New in 9.3.
+					Label:        "HMAC Signature - Header",
+					Description:  "Header that contains the hex encoded signature.",
+					Element:      ElementTypeInput,
+					InputType:    InputTypeText,
+					PropertyName: "hmac_header",
+					Placeholder:  "X-Grafana-Alerting-Signature",
+				},
+				{ // New in 9.3.
+					Label:        "HMAC Signature - Timestamp Header",
+					Description:  "Header that contains the Unix timestamp of the request.",
+					Element:      ElementTypeInput,
+					InputType:    InputTypeText,
+					PropertyName: "hmac_timestamp_header",
+					Placeholder:  "X-Grafana-Alerting-Timestamp",
+				},
 			},
 		},
 		{
@@ -1158,5 +1196,229 @@ func GetAvailableNotifiers() []*NotifierPlugin {
 				},
 			},
 		},
+		{
+			Type:        "matrix",
+			Name:        "Matrix",
+			Description: "Sends notifications to a Matrix room",
+			Heading:     "Matrix settings",
+			Info:        "The user of the access token must have joined the room",
+			Options: []NotifierOption{
+				{
+					Label:        "Homeserver URL",
+					Description:  "URL of the homeserver of the user that sends the messages.",
+					Element:      ElementTypeInput,
+					InputType:    InputTypeText,
+					Placeholder:  "https://matrix.example.com",
+					PropertyName: "homeserver_url",
+					Required:     true,
+				},
+				{
+					Label:        "Access Token",
+					Description:  "Access token of the user that sends the messages.",
+					Element:      ElementTypeInput,
+					InputType:    InputTypePassword,
+					PropertyName: "access_token",
+					Secure:       true,
+					Required:     true,
+				},
+				{
+					Label:        "Room ID",
+					Description:  "The ID of the room to send messages to.",
+					Element:      ElementTypeInput,
+					InputType:    InputTypeText,
+					Placeholder:  "!abcdefghijklmnop:example.com",
+					PropertyName: "room_id",
+					Required:     true,
+				},
+				{
+					Label:        "Title",
+					Description:  "Templated title of the message",
+					Element:      ElementTypeInput,
+					InputType:    InputTypeText,
+					Placeholder:  channels.DefaultMessageTitleEmbed,
+					PropertyName: "title",
+				},
+				{
+					Label:        "Message",
+					Description:  "Templated message, formatted as HTML in clients that support it",
+					Element:      ElementTypeTextArea,
+					Placeholder:  channels.DefaultMessageEmbed,
+					PropertyName: "message",
+				},
+			},
+		},
+		{
+			Type:        "mattermost",
+			Name:        "Mattermost",
+			Description: "Sends notifications to a Mattermost incoming webhook",
+			Heading:     "Mattermost settings",
+			Options: []NotifierOption{
+				{
+					Label:        "Webhook URL",
+					Element:      ElementTypeInput,
+					InputType:    InputTypeText,
+					Placeholder:  "https://mattermost.example.com/hooks/xxx-generatedkey-xxx",
+					PropertyName: "url",
+					Secure:       true,
+					Required:     true,
+				},
+				{
+					Label:        "Channel",
+					Description:  "Overrides the channel of the webhook.",
+					Element:      ElementTypeInput,
+					InputType:    InputTypeText,
+					PropertyName: "channel",
+				},
+				{
+					Label:        "Username",
+					Description:  "Overrides the username of the webhook.",
+					Element:      ElementTypeInput,
+					InputType:    InputTypeText,
+					PropertyName: "username",
+				},
+				{
+					Label:        "Icon URL",
+					Description:  "Overrides the profile picture of the webhook.",
+					Element:      ElementTypeInput,
+					InputType:    InputTypeText,
+					PropertyName: "icon_url",
+				},
+				{
+					Label:        "Title",
+					Description:  "Templated title of the message",
+					Element:      ElementTypeInput,
+					InputType:    InputTypeText,
+					Placeholder:  channels.DefaultMessageTitleEmbed,
+					PropertyName: "title",
+				},
+				{
+					Label:        "Text Body",
+					Description:  "Templated text of the message, Markdown is supported",
+					Element:      ElementTypeTextArea,
+					Placeholder:  channels.DefaultMessageEmbed,
+					PropertyName: "text",
+				},
+			},
+		},
+		{
+			Type:        "mqtt",
+			Name:        "MQTT",
+			Description: "Publishes notifications to a topic of an MQTT broker",
+			Heading:     "MQTT settings",
+			Options: []NotifierOption{
+				{
+					Label:        "Broker URL",
+					Description:  "The URL of the MQTT broker. Use the ssl:// or wss:// scheme for TLS.",
+					Element:      ElementTypeInput,
+					InputType:    InputTypeText,
+					Placeholder:  "tcp://localhost:1883",
+					PropertyName: "broker_url",
+					Required:     true,
+				},
+				{
+					Label:        "Topic",
+					Description:  "The topic to which the messages are published.",
+					Element:      ElementTypeInput,
+					InputType:    InputTypeText,
+					Placeholder:  "grafana/alerts",
+					PropertyName: "topic",
+					Required:     true,
+				},
+				{
+					Label:        "Client ID",
+					Description:  "The prefix of the client ID to use when connecting to the broker. A unique suffix is added to it for every connection. Defaults to grafana.",
+					Element:      ElementTypeInput,
+					InputType:    InputTypeText,
+					PropertyName: "client_id",
+				},
+				{
+					Label:        "Message format",
+					Description:  "JSON publishes the full alert payload, in the same format as the webhook contact point. Text publishes the message only.",
+					Element:      ElementTypeSelect,
+					PropertyName: "message_format",
+					SelectOptions: []SelectOption{
+						{
+							Value: channels.MQTTMessageFormatJSON,
+							Label: "JSON",
+						},
+						{
+							Value: channels.MQTTMessageFormatText,
+							Label: "Text",
+						},
+					},
+				},
+				{
+					Label:        "Message",
+					Description:  "Templated message",
+					Element:      ElementTypeTextArea,
+					Placeholder:  channels.DefaultMessageEmbed,
+					PropertyName: "message",
+				},
+				{
+					Label:        "QoS",
+					Description:  "The quality of service of the messages.",
+					Element:      ElementTypeSelect,
+					PropertyName: "qos",
+					SelectOptions: []SelectOption{
+						{
+							Value: "0",
+							Label: "At most once (0)",
+						},
+						{
+							Value: "1",
+							Label: "At least once (1)",
+						},
+						{
+							Value: "2",
+							Label: "Exactly once (2)",
+						},
+					},
+				},
+				{
+					Label:        "Retain",
+					Description:  "Whether the broker retains the last message of the topic.",
+					Element:      ElementTypeCheckbox,
+					PropertyName: "retain",
+				},
+				{
+					Label:        "Username",
+					Element:      ElementTypeInput,
+					InputType:    InputTypeText,
+					PropertyName: "username",
+				},
+				{
+					Label:        "Password",
+					Element:      ElementTypeInput,
+					InputType:    InputTypePassword,
+					PropertyName: "password",
+					Secure:       true,
+				},
+				{
+					Label:        "Disable certificate verification",
+					Description:  "Do not verify the certificate of the broker. This is insecure.",
+					Element:      ElementTypeCheckbox,
+					PropertyName: "insecure_skip_verify",
+				},
+				{
+					Label:        "TLS CA certificate",
+					Description:  "The PEM encoded certificate of the CA that signed the certificate of the broker.",
+					Element:      ElementTypeTextArea,
+					PropertyName: "tls_ca_certificate",
+				},
+				{
+					Label:        "TLS client certificate",
+					Description:  "The PEM encoded certificate to authenticate to the broker.",
+					Element:      ElementTypeTextArea,
+					PropertyName: "tls_client_certificate",
+				},
+				{
+					Label:        "TLS client key",
+					Description:  "The PEM encoded key of the client certificate.",
+					Element:      ElementTypeTextArea,
+					PropertyName: "tls_client_key",
+					Secure:       true,
+				},
+			},
+		},
 	}
 }
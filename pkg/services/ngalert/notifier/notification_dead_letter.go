@@ -0,0 +1,195 @@
+package notifier
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"time"
+
+	gokitlog "github.com/go-kit/log"
+	"github.com/prometheus/alertmanager/notify"
+	"github.com/prometheus/alertmanager/types"
+	"github.com/prometheus/common/model"
+
+	"github.com/grafana/grafana/pkg/infra/log"
+	"github.com/grafana/grafana/pkg/services/ngalert/models"
+	"github.com/grafana/grafana/pkg/services/ngalert/store"
+)
+
+var (
+	// ErrDeadLetterIntegrationNotFound is returned when a dead letter is replayed but its integration
+	// no longer exists in the configuration of the Alertmanager.
+	ErrDeadLetterIntegrationNotFound = errors.New("the integration of the notification no longer exists")
+)
+
+// DeadLetterReplayError is returned when the integration fails to send the notification of a replayed dead letter.
+type DeadLetterReplayError struct {
+	Err error
+}
+
+func (e DeadLetterReplayError) Error() string {
+	return fmt.Sprintf("failed to replay notification: %s", e.Err)
+}
+
+func (e DeadLetterReplayError) Unwrap() error {
+	return e.Err
+}
+
+// deadLetterStage saves the notifications that the integration failed to send after all retries
+// to the dead letters of the organization, so they can be inspected and replayed.
+type deadLetterStage struct {
+	next        notify.Stage
+	store       store.NotificationDeadLetterStore
+	orgID       int64
+	receiver    string
+	integration *notify.Integration
+	logger      log.Logger
+}
+
+func newDeadLetterStage(next notify.Stage, store store.NotificationDeadLetterStore, orgID int64, receiver string, integration *notify.Integration, logger log.Logger) *deadLetterStage {
+	return &deadLetterStage{
+		next:        next,
+		store:       store,
+		orgID:       orgID,
+		receiver:    receiver,
+		integration: integration,
+		logger:      logger,
+	}
+}
+
+func (s *deadLetterStage) Exec(ctx context.Context, l gokitlog.Logger, alerts ...*types.Alert) (context.Context, []*types.Alert, error) {
+	ctx, sent, err := s.next.Exec(ctx, l, alerts...)
+	if err == nil {
+		return ctx, sent, err
+	}
+
+	deadLetter, encodeErr := s.newDeadLetter(ctx, err, alerts)
+	if encodeErr != nil {
+		s.logger.Error("Failed to encode notification dead letter", "receiver", s.receiver, "integration", s.integration.Name(), "error", encodeErr)
+		return ctx, sent, err
+	}
+
+	saveCtx, cancel := context.WithTimeout(context.Background(), notificationHistorySaveTimeout)
+	defer cancel()
+	if saveErr := s.store.SaveNotificationDeadLetter(saveCtx, deadLetter); saveErr != nil {
+		s.logger.Error("Failed to save notification dead letter", "receiver", s.receiver, "integration", s.integration.Name(), "error", saveErr)
+	}
+
+	return ctx, sent, err
+}
+
+func (s *deadLetterStage) newDeadLetter(ctx context.Context, err error, alerts []*types.Alert) (*models.NotificationDeadLetter, error) {
+	groupKey, _ := notify.GroupKey(ctx)
+	groupLabels, _ := notify.GroupLabels(ctx)
+	b, encodeErr := json.Marshal(groupLabels)
+	if encodeErr != nil {
+		return nil, encodeErr
+	}
+	a, encodeErr := json.Marshal(alerts)
+	if encodeErr != nil {
+		return nil, encodeErr
+	}
+	return &models.NotificationDeadLetter{
+		OrgID:            s.orgID,
+		Receiver:         s.receiver,
+		Integration:      s.integration.Name(),
+		IntegrationIndex: s.integration.Index(),
+		GroupKey:         groupKey,
+		GroupLabels:      string(b),
+		Alerts:           string(a),
+		Error:            err.Error(),
+	}, nil
+}
+
+// GetDeadLetters returns the dead letters of the organization that match the query.
+func (am *Alertmanager) GetDeadLetters(ctx context.Context, query *models.NotificationDeadLetterQuery) ([]models.NotificationDeadLetter, error) {
+	q := *query
+	q.OrgID = am.orgID
+	return am.Store.GetNotificationDeadLetters(ctx, &q)
+}
+
+// DeleteDeadLetter deletes the dead letter with the ID.
+func (am *Alertmanager) DeleteDeadLetter(ctx context.Context, id int64) error {
+	return am.Store.DeleteNotificationDeadLetter(ctx, am.orgID, id)
+}
+
+// ReplayDeadLetter sends the notification of the dead letter with the ID once more with the integration
+// in the current configuration, and deletes the dead letter if the notification is sent. If the
+// integration fails to send the notification, the error of the dead letter is updated and a
+// DeadLetterReplayError is returned.
+func (am *Alertmanager) ReplayDeadLetter(ctx context.Context, id int64) error {
+	deadLetter, err := am.Store.GetNotificationDeadLetter(ctx, am.orgID, id)
+	if err != nil {
+		return err
+	}
+
+	integration := am.getIntegration(deadLetter.Receiver, deadLetter.Integration, deadLetter.IntegrationIndex)
+	if integration == nil {
+		return ErrDeadLetterIntegrationNotFound
+	}
+
+	var (
+		alerts      []*types.Alert
+		groupLabels model.LabelSet
+	)
+	if err := json.Unmarshal([]byte(deadLetter.Alerts), &alerts); err != nil {
+		return fmt.Errorf("failed to decode alerts of notification dead letter: %w", err)
+	}
+	if err := json.Unmarshal([]byte(deadLetter.GroupLabels), &groupLabels); err != nil {
+		return fmt.Errorf("failed to decode group labels of notification dead letter: %w", err)
+	}
+
+	ctx = notify.WithGroupKey(ctx, deadLetter.GroupKey)
+	ctx = notify.WithGroupLabels(ctx, groupLabels)
+	ctx = notify.WithReceiverName(ctx, deadLetter.Receiver)
+	ctx = notify.WithNow(ctx, time.Now())
+
+	if _, err := integration.Notify(ctx, alerts...); err != nil {
+		deadLetter.Error = err.Error()
+		if saveErr := am.Store.SaveNotificationDeadLetter(ctx, deadLetter); saveErr != nil {
+			am.logger.Error("Failed to update notification dead letter", "id", deadLetter.ID, "error", saveErr)
+		}
+		return DeadLetterReplayError{Err: err}
+	}
+
+	return am.Store.DeleteNotificationDeadLetter(ctx, am.orgID, id)
+}
+
+// getIntegration returns the integration of the receiver at the index, or nil if it does not exist.
+func (am *Alertmanager) getIntegration(receiver, name string, index int) *notify.Integration {
+	am.reloadConfigMtx.RLock()
+	defer am.reloadConfigMtx.RUnlock()
+
+	for _, rcv := range am.receivers {
+		if rcv.Name() != receiver {
+			continue
+		}
+		for _, integration := range rcv.Integrations() {
+			if integration.Index() == index && integration.Name() == name {
+				return integration
+			}
+		}
+	}
+	return nil
+}
+
+// deadLetterMaintenance deletes the dead letters of the organization that are older than the retention
+// at each interval, until the Alertmanager is stopped.
+func (am *Alertmanager) deadLetterMaintenance(interval, retention time.Duration) {
+	t := time.NewTicker(interval)
+	defer t.Stop()
+	for {
+		select {
+		case <-am.stopc:
+			return
+		case <-t.C:
+			n, err := am.Store.DeleteNotificationDeadLettersBefore(context.Background(), am.orgID, time.Now().Add(-retention))
+			if err != nil {
+				am.logger.Error("Failed to delete old notification dead letters", "error", err)
+				continue
+			}
+			am.logger.Debug("Deleted old notification dead letters", "entries", n)
+		}
+	}
+}
@@ -0,0 +1,193 @@
+package notifier
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"testing"
+	"time"
+
+	gokitlog "github.com/go-kit/log"
+	"github.com/prometheus/alertmanager/notify"
+	"github.com/prometheus/alertmanager/types"
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/common/model"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/grafana/grafana/pkg/infra/log"
+	"github.com/grafana/grafana/pkg/services/ngalert/models"
+)
+
+type fakeDeadLetterStore struct {
+	FakeConfigStore
+	deadLetters map[int64]*models.NotificationDeadLetter
+	nextID      int64
+}
+
+func newFakeDeadLetterStore() *fakeDeadLetterStore {
+	return &fakeDeadLetterStore{deadLetters: make(map[int64]*models.NotificationDeadLetter)}
+}
+
+func (f *fakeDeadLetterStore) SaveNotificationDeadLetter(_ context.Context, deadLetter *models.NotificationDeadLetter) error {
+	if deadLetter.ID == 0 {
+		for _, d := range f.deadLetters {
+			if d.OrgID == deadLetter.OrgID && d.Receiver == deadLetter.Receiver && d.Integration == deadLetter.Integration &&
+				d.IntegrationIndex == deadLetter.IntegrationIndex && d.GroupKey == deadLetter.GroupKey {
+				deadLetter.ID = d.ID
+			}
+		}
+	}
+	if deadLetter.ID == 0 {
+		f.nextID++
+		deadLetter.ID = f.nextID
+	}
+	d := *deadLetter
+	f.deadLetters[d.ID] = &d
+	return nil
+}
+
+func (f *fakeDeadLetterStore) GetNotificationDeadLetter(_ context.Context, orgID, id int64) (*models.NotificationDeadLetter, error) {
+	d, ok := f.deadLetters[id]
+	if !ok || d.OrgID != orgID {
+		return nil, models.ErrNotificationDeadLetterNotFound
+	}
+	result := *d
+	return &result, nil
+}
+
+func (f *fakeDeadLetterStore) DeleteNotificationDeadLetter(_ context.Context, orgID, id int64) error {
+	d, ok := f.deadLetters[id]
+	if !ok || d.OrgID != orgID {
+		return models.ErrNotificationDeadLetterNotFound
+	}
+	delete(f.deadLetters, id)
+	return nil
+}
+
+// fakeReplayNotifier records the alerts and the group labels of the notifications it sends.
+type fakeReplayNotifier struct {
+	err         error
+	alerts      []*types.Alert
+	groupLabels model.LabelSet
+}
+
+func (n *fakeReplayNotifier) Notify(ctx context.Context, as ...*types.Alert) (bool, error) {
+	n.alerts = as
+	n.groupLabels, _ = notify.GroupLabels(ctx)
+	return false, n.err
+}
+
+func (n *fakeReplayNotifier) SendResolved() bool {
+	return true
+}
+
+func TestDeadLetterStage(t *testing.T) {
+	alert := &types.Alert{Alert: model.Alert{
+		Labels:   model.LabelSet{"alertname": "test"},
+		StartsAt: time.Now().Add(-time.Minute),
+	}}
+
+	exec := func(t *testing.T, n *fakeHistoryNotifier) (*fakeDeadLetterStore, error) {
+		t.Helper()
+		store := newFakeDeadLetterStore()
+		integration := notify.NewIntegration(n, n, "slack", 1)
+		metrics, err := newRetryMetrics(prometheus.NewRegistry())
+		require.NoError(t, err)
+		retry := newRetryStage(integration, "team-a", &retryPolicy{maxAttempts: 1}, metrics)
+		stage := newDeadLetterStage(retry, store, 1, "team-a", integration, log.NewNopLogger())
+
+		ctx := notify.WithGroupKey(context.Background(), "{}:{alertname=\"test\"}")
+		ctx = notify.WithGroupLabels(ctx, model.LabelSet{"alertname": "test"})
+		_, _, err = stage.Exec(ctx, gokitlog.NewNopLogger(), alert)
+		return store, err
+	}
+
+	t.Run("should not save notification that is sent", func(t *testing.T) {
+		store, err := exec(t, &fakeHistoryNotifier{sendResolved: true})
+		require.NoError(t, err)
+		require.Empty(t, store.deadLetters)
+	})
+
+	t.Run("should save notification that failed", func(t *testing.T) {
+		store, err := exec(t, &fakeHistoryNotifier{errs: []error{errors.New("unavailable")}, retry: true, sendResolved: true})
+		require.Error(t, err)
+		require.Len(t, store.deadLetters, 1)
+
+		deadLetter := store.deadLetters[1]
+		assert.Equal(t, int64(1), deadLetter.OrgID)
+		assert.Equal(t, "team-a", deadLetter.Receiver)
+		assert.Equal(t, "slack", deadLetter.Integration)
+		assert.Equal(t, 1, deadLetter.IntegrationIndex)
+		assert.Equal(t, "{}:{alertname=\"test\"}", deadLetter.GroupKey)
+		assert.JSONEq(t, `{"alertname":"test"}`, deadLetter.GroupLabels)
+		assert.Contains(t, deadLetter.Error, "unavailable")
+
+		var alerts []*types.Alert
+		require.NoError(t, json.Unmarshal([]byte(deadLetter.Alerts), &alerts))
+		require.Len(t, alerts, 1)
+		assert.Equal(t, alert.Labels, alerts[0].Labels)
+	})
+}
+
+func TestReplayDeadLetter(t *testing.T) {
+	alerts, err := json.Marshal([]*types.Alert{{Alert: model.Alert{
+		Labels:   model.LabelSet{"alertname": "test"},
+		StartsAt: time.Now().Add(-time.Minute),
+	}}})
+	require.NoError(t, err)
+
+	setup := func(t *testing.T, n *fakeReplayNotifier) (*Alertmanager, *fakeDeadLetterStore) {
+		t.Helper()
+		store := newFakeDeadLetterStore()
+		require.NoError(t, store.SaveNotificationDeadLetter(context.Background(), &models.NotificationDeadLetter{
+			OrgID:            1,
+			Receiver:         "team-a",
+			Integration:      "slack",
+			IntegrationIndex: 0,
+			GroupKey:         "{}:{alertname=\"test\"}",
+			GroupLabels:      `{"alertname":"test"}`,
+			Alerts:           string(alerts),
+			Error:            "unavailable",
+		}))
+		am := &Alertmanager{
+			Store:  store,
+			orgID:  1,
+			logger: log.NewNopLogger(),
+			receivers: []*notify.Receiver{
+				notify.NewReceiver("team-a", true, []*notify.Integration{notify.NewIntegration(n, n, "slack", 0)}),
+			},
+		}
+		return am, store
+	}
+
+	t.Run("should send notification and delete dead letter", func(t *testing.T) {
+		n := &fakeReplayNotifier{}
+		am, store := setup(t, n)
+		require.NoError(t, am.ReplayDeadLetter(context.Background(), 1))
+		require.Len(t, n.alerts, 1)
+		assert.Equal(t, model.LabelSet{"alertname": "test"}, n.alerts[0].Labels)
+		assert.Equal(t, model.LabelSet{"alertname": "test"}, n.groupLabels)
+		require.Empty(t, store.deadLetters)
+	})
+
+	t.Run("should update error of dead letter if notification fails", func(t *testing.T) {
+		n := &fakeReplayNotifier{err: errors.New("bad gateway")}
+		am, store := setup(t, n)
+		err := am.ReplayDeadLetter(context.Background(), 1)
+		require.ErrorAs(t, err, &DeadLetterReplayError{})
+		require.Len(t, store.deadLetters, 1)
+		assert.Equal(t, "bad gateway", store.deadLetters[1].Error)
+	})
+
+	t.Run("should return error if dead letter does not exist", func(t *testing.T) {
+		am, _ := setup(t, &fakeReplayNotifier{})
+		require.ErrorIs(t, am.ReplayDeadLetter(context.Background(), 2), models.ErrNotificationDeadLetterNotFound)
+	})
+
+	t.Run("should return error if integration does not exist", func(t *testing.T) {
+		am, _ := setup(t, &fakeReplayNotifier{})
+		am.receivers = nil
+		require.ErrorIs(t, am.ReplayDeadLetter(context.Background(), 1), ErrDeadLetterIntegrationNotFound)
+	})
+}
@@ -0,0 +1,132 @@
+package notifier
+
+import (
+	"context"
+	"sync/atomic"
+	"time"
+
+	gokitlog "github.com/go-kit/log"
+	"github.com/prometheus/alertmanager/notify"
+	"github.com/prometheus/alertmanager/types"
+	"github.com/prometheus/common/model"
+
+	"github.com/grafana/grafana/pkg/infra/log"
+	"github.com/grafana/grafana/pkg/services/ngalert/models"
+	"github.com/grafana/grafana/pkg/services/ngalert/store"
+)
+
+// notificationHistorySaveTimeout is the timeout to save an entry of the notification history. The entry is
+// saved with its own context as the context of the notification can be canceled by then.
+const notificationHistorySaveTimeout = 10 * time.Second
+
+type attemptsKey struct{}
+
+// withAttempts returns a context that counts the attempts of the integration to send the notification.
+func withAttempts(ctx context.Context, attempts *int64) context.Context {
+	return context.WithValue(ctx, attemptsKey{}, attempts)
+}
+
+// attemptsCountingNotifier counts the calls to the notifier in the counter of the context, if any. The calls
+// include the retries of the retry stage.
+type attemptsCountingNotifier struct {
+	notify.Notifier
+}
+
+func (n attemptsCountingNotifier) Notify(ctx context.Context, as ...*types.Alert) (bool, error) {
+	if attempts, ok := ctx.Value(attemptsKey{}).(*int64); ok {
+		atomic.AddInt64(attempts, 1)
+	}
+	return n.Notifier.Notify(ctx, as...)
+}
+
+// notificationHistoryStage records each attempt of an integration to send a notification for an alert
+// group in the notification history of the organization.
+type notificationHistoryStage struct {
+	next        notify.Stage
+	store       store.NotificationHistoryStore
+	orgID       int64
+	receiver    string
+	integration *notify.Integration
+	logger      log.Logger
+}
+
+func newNotificationHistoryStage(next notify.Stage, store store.NotificationHistoryStore, orgID int64, receiver string, integration *notify.Integration, logger log.Logger) *notificationHistoryStage {
+	return &notificationHistoryStage{
+		next:        next,
+		store:       store,
+		orgID:       orgID,
+		receiver:    receiver,
+		integration: integration,
+		logger:      logger,
+	}
+}
+
+func (s *notificationHistoryStage) Exec(ctx context.Context, l gokitlog.Logger, alerts ...*types.Alert) (context.Context, []*types.Alert, error) {
+	var attempts int64
+	start := time.Now()
+	ctx, sent, err := s.next.Exec(withAttempts(ctx, &attempts), l, alerts...)
+
+	// The integration was not called, for example because all alerts are resolved and it does not send resolved notifications.
+	if atomic.LoadInt64(&attempts) == 0 {
+		return ctx, sent, err
+	}
+
+	entry := models.NotificationHistoryEntry{
+		OrgID:            s.orgID,
+		Receiver:         s.receiver,
+		Integration:      s.integration.Name(),
+		IntegrationIndex: s.integration.Index(),
+		Status:           models.NotificationHistoryStatusSuccess,
+		Duration:         time.Since(start).Milliseconds(),
+		Retries:          int(atomic.LoadInt64(&attempts)) - 1,
+	}
+	if groupKey, ok := notify.GroupKey(ctx); ok {
+		entry.GroupKey = groupKey
+	}
+	for _, a := range alerts {
+		if a.Status() == model.AlertFiring {
+			entry.FiringAlerts++
+		} else {
+			entry.ResolvedAlerts++
+		}
+	}
+	if err != nil {
+		entry.Status = models.NotificationHistoryStatusFailed
+		entry.Error = err.Error()
+	}
+
+	saveCtx, cancel := context.WithTimeout(context.Background(), notificationHistorySaveTimeout)
+	defer cancel()
+	if saveErr := s.store.SaveNotificationHistoryEntry(saveCtx, &entry); saveErr != nil {
+		s.logger.Error("Failed to save notification history", "receiver", s.receiver, "integration", entry.Integration, "error", saveErr)
+	}
+
+	return ctx, sent, err
+}
+
+// notificationHistoryMaintenance deletes the entries of the notification history of the organization that are
+// older than the retention at each interval, until the Alertmanager is stopped.
+func (am *Alertmanager) notificationHistoryMaintenance(interval, retention time.Duration) {
+	t := time.NewTicker(interval)
+	defer t.Stop()
+	for {
+		select {
+		case <-am.stopc:
+			return
+		case <-t.C:
+			n, err := am.Store.DeleteNotificationHistoryBefore(context.Background(), am.orgID, time.Now().Add(-retention))
+			if err != nil {
+				am.logger.Error("Failed to delete old notification history", "error", err)
+				continue
+			}
+			am.logger.Debug("Deleted old notification history", "entries", n)
+		}
+	}
+}
+
+// GetNotificationHistory returns the entries of the notification history of the organization that match the query.
+func (am *Alertmanager) GetNotificationHistory(ctx context.Context, query *models.NotificationHistoryQuery) ([]models.NotificationHistoryEntry, error) {
+	q := *query
+	q.OrgID = am.orgID
+	return am.Store.GetNotificationHistory(ctx, &q)
+}
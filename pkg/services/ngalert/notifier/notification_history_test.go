@@ -0,0 +1,131 @@
+package notifier
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	gokitlog "github.com/go-kit/log"
+	"github.com/prometheus/alertmanager/notify"
+	"github.com/prometheus/alertmanager/types"
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/common/model"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/grafana/grafana/pkg/infra/log"
+	"github.com/grafana/grafana/pkg/services/ngalert/models"
+)
+
+type fakeNotificationHistoryStore struct {
+	entries []models.NotificationHistoryEntry
+}
+
+func (f *fakeNotificationHistoryStore) SaveNotificationHistoryEntry(_ context.Context, entry *models.NotificationHistoryEntry) error {
+	f.entries = append(f.entries, *entry)
+	return nil
+}
+
+func (f *fakeNotificationHistoryStore) GetNotificationHistory(_ context.Context, _ *models.NotificationHistoryQuery) ([]models.NotificationHistoryEntry, error) {
+	return f.entries, nil
+}
+
+func (f *fakeNotificationHistoryStore) DeleteNotificationHistoryBefore(_ context.Context, _ int64, _ time.Time) (int64, error) {
+	return 0, nil
+}
+
+// fakeHistoryNotifier returns the errors in order, one for each call, and then succeeds.
+type fakeHistoryNotifier struct {
+	errs         []error
+	retry        bool
+	sendResolved bool
+}
+
+func (n *fakeHistoryNotifier) Notify(_ context.Context, _ ...*types.Alert) (bool, error) {
+	if len(n.errs) == 0 {
+		return false, nil
+	}
+	err := n.errs[0]
+	n.errs = n.errs[1:]
+	return n.retry, err
+}
+
+func (n *fakeHistoryNotifier) SendResolved() bool {
+	return n.sendResolved
+}
+
+func TestNotificationHistoryStage(t *testing.T) {
+	firing := &types.Alert{Alert: model.Alert{
+		Labels:   model.LabelSet{"alertname": "firing"},
+		StartsAt: time.Now().Add(-time.Minute),
+	}}
+	resolved := &types.Alert{Alert: model.Alert{
+		Labels:   model.LabelSet{"alertname": "resolved"},
+		StartsAt: time.Now().Add(-time.Hour),
+		EndsAt:   time.Now().Add(-time.Minute),
+	}}
+
+	exec := func(t *testing.T, n *fakeHistoryNotifier, alerts ...*types.Alert) (*fakeNotificationHistoryStore, error) {
+		t.Helper()
+		store := &fakeNotificationHistoryStore{}
+		integration := notify.NewIntegration(attemptsCountingNotifier{n}, n, "slack", 1)
+		retry := notify.NewRetryStage(integration, "team-a", notify.NewMetrics(prometheus.NewRegistry()))
+		stage := newNotificationHistoryStage(retry, store, 1, "team-a", integration, log.NewNopLogger())
+
+		var firingAlerts []uint64
+		for _, a := range alerts {
+			if a.Status() == model.AlertFiring {
+				firingAlerts = append(firingAlerts, uint64(a.Fingerprint()))
+			}
+		}
+		ctx := notify.WithGroupKey(context.Background(), "{}:{alertname=\"test\"}")
+		ctx = notify.WithFiringAlerts(ctx, firingAlerts)
+		ctx, cancel := context.WithTimeout(ctx, 5*time.Second)
+		defer cancel()
+
+		_, _, err := stage.Exec(ctx, gokitlog.NewNopLogger(), alerts...)
+		return store, err
+	}
+
+	t.Run("should record successful notification", func(t *testing.T) {
+		store, err := exec(t, &fakeHistoryNotifier{sendResolved: true}, firing, resolved)
+		require.NoError(t, err)
+		require.Len(t, store.entries, 1)
+
+		entry := store.entries[0]
+		assert.Equal(t, int64(1), entry.OrgID)
+		assert.Equal(t, "team-a", entry.Receiver)
+		assert.Equal(t, "slack", entry.Integration)
+		assert.Equal(t, 1, entry.IntegrationIndex)
+		assert.Equal(t, "{}:{alertname=\"test\"}", entry.GroupKey)
+		assert.Equal(t, models.NotificationHistoryStatusSuccess, entry.Status)
+		assert.Empty(t, entry.Error)
+		assert.Equal(t, 0, entry.Retries)
+		assert.Equal(t, 1, entry.FiringAlerts)
+		assert.Equal(t, 1, entry.ResolvedAlerts)
+	})
+
+	t.Run("should record retries of notification", func(t *testing.T) {
+		store, err := exec(t, &fakeHistoryNotifier{errs: []error{errors.New("unavailable")}, retry: true, sendResolved: true}, firing)
+		require.NoError(t, err)
+		require.Len(t, store.entries, 1)
+		assert.Equal(t, models.NotificationHistoryStatusSuccess, store.entries[0].Status)
+		assert.Equal(t, 1, store.entries[0].Retries)
+	})
+
+	t.Run("should record failed notification", func(t *testing.T) {
+		store, err := exec(t, &fakeHistoryNotifier{errs: []error{errors.New("bad request")}, sendResolved: true}, firing)
+		require.Error(t, err)
+		require.Len(t, store.entries, 1)
+		assert.Equal(t, models.NotificationHistoryStatusFailed, store.entries[0].Status)
+		assert.Contains(t, store.entries[0].Error, "bad request")
+		assert.Equal(t, 0, store.entries[0].Retries)
+	})
+
+	t.Run("should not record notification when integration is not called", func(t *testing.T) {
+		store, err := exec(t, &fakeHistoryNotifier{}, resolved)
+		require.NoError(t, err)
+		require.Empty(t, store.entries)
+	})
+}
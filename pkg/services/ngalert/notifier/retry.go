@@ -0,0 +1,220 @@
+package notifier
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+
+	gokitlog "github.com/go-kit/log"
+	"github.com/go-kit/log/level"
+	"github.com/prometheus/alertmanager/notify"
+	"github.com/prometheus/alertmanager/types"
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/common/model"
+
+	apimodels "github.com/grafana/grafana/pkg/services/ngalert/api/tooling/definitions"
+	"github.com/grafana/grafana/pkg/setting"
+)
+
+// retryPolicy is the policy to retry the failed notifications of an integration.
+type retryPolicy struct {
+	maxAttempts     int
+	initialInterval time.Duration
+	maxInterval     time.Duration
+}
+
+// newRetryPolicy returns the retry policy of the integration. The intervals that are not set in the policy of
+// the integration are taken from the configuration. It returns nil if neither the integration nor the configuration
+// set a maximum number of attempts, in which case failed notifications are retried until the notification times out.
+func newRetryPolicy(p *apimodels.RetryPolicy, cfg setting.UnifiedAlertingNotificationRetrySettings) *retryPolicy {
+	policy := &retryPolicy{
+		maxAttempts:     cfg.MaxAttempts,
+		initialInterval: cfg.InitialInterval,
+		maxInterval:     cfg.MaxInterval,
+	}
+	if p != nil {
+		if p.MaxAttempts > 0 {
+			policy.maxAttempts = p.MaxAttempts
+		}
+		if p.InitialInterval > 0 {
+			policy.initialInterval = time.Duration(p.InitialInterval)
+		}
+		if p.MaxInterval > 0 {
+			policy.maxInterval = time.Duration(p.MaxInterval)
+		}
+	}
+	if policy.maxAttempts <= 0 {
+		return nil
+	}
+	if policy.maxInterval < policy.initialInterval {
+		policy.maxInterval = policy.initialInterval
+	}
+	return policy
+}
+
+// backoff returns the time to wait after the attempt before the next attempt.
+func (p *retryPolicy) backoff(attempt int) time.Duration {
+	d := p.initialInterval
+	for i := 1; i < attempt; i++ {
+		d *= 2
+		if d >= p.maxInterval {
+			return p.maxInterval
+		}
+	}
+	return d
+}
+
+// retryMetrics are the metrics that notify.RetryStage records in notify.Metrics. The fields of notify.Metrics
+// are not exported, so the collectors that notify.NewMetrics registered are taken from the registry, and
+// both stages record the same series.
+type retryMetrics struct {
+	numNotifications                   *prometheus.CounterVec
+	numTotalFailedNotifications        *prometheus.CounterVec
+	numNotificationRequestsTotal       *prometheus.CounterVec
+	numNotificationRequestsFailedTotal *prometheus.CounterVec
+	notificationLatencySeconds         *prometheus.HistogramVec
+}
+
+// newRetryMetrics returns the metrics of the notifications in the registry. The collectors are registered
+// if notify.NewMetrics has not registered them yet. If a collector can't be registered, for example because
+// notify.NewMetrics registered it with different options, an unregistered collector is used instead and the
+// error is returned with the metrics, so that the retry stage still works but doesn't export the metric.
+func newRetryMetrics(r prometheus.Registerer) (*retryMetrics, error) {
+	var errs []error
+	counter := func(name, help string) *prometheus.CounterVec {
+		c, err := registerOrGet(r, prometheus.NewCounterVec(prometheus.CounterOpts{
+			Namespace: "alertmanager",
+			Name:      name,
+			Help:      help,
+		}, []string{"integration"}))
+		if err != nil {
+			errs = append(errs, err)
+		}
+		return c
+	}
+
+	m := &retryMetrics{
+		numNotifications:                   counter("notifications_total", "The total number of attempted notifications."),
+		numTotalFailedNotifications:        counter("notifications_failed_total", "The total number of failed notifications."),
+		numNotificationRequestsTotal:       counter("notification_requests_total", "The total number of attempted notification requests."),
+		numNotificationRequestsFailedTotal: counter("notification_requests_failed_total", "The total number of failed notification requests."),
+	}
+	latency, err := registerOrGet(r, prometheus.NewHistogramVec(prometheus.HistogramOpts{
+		Namespace: "alertmanager",
+		Name:      "notification_latency_seconds",
+		Help:      "The latency of notifications in seconds.",
+		Buckets:   []float64{1, 5, 10, 15, 20},
+	}, []string{"integration"}))
+	if err != nil {
+		errs = append(errs, err)
+	}
+	m.notificationLatencySeconds = latency
+
+	if len(errs) > 0 {
+		return m, fmt.Errorf("failed to register %d notification metrics, the first error: %w", len(errs), errs[0])
+	}
+	return m, nil
+}
+
+// registerOrGet registers the collector, or returns the collector of the same type that is already registered.
+// It returns the given collector together with the error if it can't be registered.
+func registerOrGet[T prometheus.Collector](r prometheus.Registerer, c T) (T, error) {
+	if err := r.Register(c); err != nil {
+		var are prometheus.AlreadyRegisteredError
+		if errors.As(err, &are) {
+			if existing, ok := are.ExistingCollector.(T); ok {
+				return existing, nil
+			}
+		}
+		return c, err
+	}
+	return c, nil
+}
+
+// retryStage notifies the integration and retries failed notifications with exponential backoff until
+// the maximum number of attempts of the policy. Unlike notify.RetryStage, it gives up before the
+// notification times out. It records the same metrics as notify.RetryStage.
+type retryStage struct {
+	integration *notify.Integration
+	groupName   string
+	policy      *retryPolicy
+	metrics     *retryMetrics
+}
+
+func newRetryStage(integration *notify.Integration, groupName string, policy *retryPolicy, metrics *retryMetrics) *retryStage {
+	return &retryStage{
+		integration: integration,
+		groupName:   groupName,
+		policy:      policy,
+		metrics:     metrics,
+	}
+}
+
+func (r *retryStage) Exec(ctx context.Context, l gokitlog.Logger, alerts ...*types.Alert) (context.Context, []*types.Alert, error) {
+	r.metrics.numNotifications.WithLabelValues(r.integration.Name()).Inc()
+	ctx, alerts, err := r.exec(ctx, l, alerts...)
+	if err != nil {
+		r.metrics.numTotalFailedNotifications.WithLabelValues(r.integration.Name()).Inc()
+	}
+	return ctx, alerts, err
+}
+
+func (r *retryStage) exec(ctx context.Context, l gokitlog.Logger, alerts ...*types.Alert) (context.Context, []*types.Alert, error) {
+	var sent []*types.Alert
+
+	// If we shouldn't send notifications for resolved alerts, but there are only
+	// resolved alerts, report them all as successfully notified.
+	if !r.integration.SendResolved() {
+		firing, ok := notify.FiringAlerts(ctx)
+		if !ok {
+			return ctx, nil, errors.New("firing alerts missing")
+		}
+		if len(firing) == 0 {
+			return ctx, alerts, nil
+		}
+		for _, a := range alerts {
+			if a.Status() != model.AlertResolved {
+				sent = append(sent, a)
+			}
+		}
+	} else {
+		sent = alerts
+	}
+
+	l = gokitlog.With(l, "receiver", r.groupName, "integration", r.integration.String())
+
+	for attempt := 1; ; attempt++ {
+		now := time.Now()
+		retry, err := r.integration.Notify(ctx, sent...)
+		duration := time.Since(now)
+
+		r.metrics.notificationLatencySeconds.WithLabelValues(r.integration.Name()).Observe(duration.Seconds())
+		r.metrics.numNotificationRequestsTotal.WithLabelValues(r.integration.Name()).Inc()
+		r.integration.Report(now, model.Duration(duration), err)
+		if err == nil {
+			lvl := level.Debug(l)
+			if attempt > 1 {
+				lvl = level.Info(l)
+			}
+			lvl.Log("msg", "Notify success", "attempts", attempt)
+			return ctx, alerts, nil
+		}
+		r.metrics.numNotificationRequestsFailedTotal.WithLabelValues(r.integration.Name()).Inc()
+		if !retry {
+			return ctx, alerts, fmt.Errorf("%s/%s: notify retry canceled due to unrecoverable error after %d attempts: %w", r.groupName, r.integration.String(), attempt, err)
+		}
+		if attempt >= r.policy.maxAttempts {
+			return ctx, nil, fmt.Errorf("%s/%s: notify retry canceled after %d attempts: %w", r.groupName, r.integration.String(), attempt, err)
+		}
+		level.Warn(l).Log("msg", "Notify attempt failed, will retry later", "attempts", attempt, "err", err)
+
+		t := time.NewTimer(r.policy.backoff(attempt))
+		select {
+		case <-ctx.Done():
+			t.Stop()
+			return ctx, nil, fmt.Errorf("%s/%s: notify retry canceled after %d attempts: %w", r.groupName, r.integration.String(), attempt, err)
+		case <-t.C:
+		}
+	}
+}
@@ -0,0 +1,187 @@
+package notifier
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	gokitlog "github.com/go-kit/log"
+	"github.com/prometheus/alertmanager/notify"
+	"github.com/prometheus/alertmanager/types"
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/testutil"
+	"github.com/prometheus/common/model"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	apimodels "github.com/grafana/grafana/pkg/services/ngalert/api/tooling/definitions"
+	"github.com/grafana/grafana/pkg/setting"
+)
+
+func TestNewRetryPolicy(t *testing.T) {
+	cfg := setting.UnifiedAlertingNotificationRetrySettings{
+		InitialInterval: time.Second,
+		MaxInterval:     time.Minute,
+	}
+
+	t.Run("should return nil without max attempts", func(t *testing.T) {
+		require.Nil(t, newRetryPolicy(nil, cfg))
+	})
+
+	t.Run("should use configuration without policy of integration", func(t *testing.T) {
+		cfg := cfg
+		cfg.MaxAttempts = 3
+		require.Equal(t, &retryPolicy{
+			maxAttempts:     3,
+			initialInterval: time.Second,
+			maxInterval:     time.Minute,
+		}, newRetryPolicy(nil, cfg))
+	})
+
+	t.Run("should use policy of integration", func(t *testing.T) {
+		require.Equal(t, &retryPolicy{
+			maxAttempts:     5,
+			initialInterval: 10 * time.Second,
+			maxInterval:     time.Minute,
+		}, newRetryPolicy(&apimodels.RetryPolicy{
+			MaxAttempts:     5,
+			InitialInterval: model.Duration(10 * time.Second),
+		}, cfg))
+	})
+
+	t.Run("should use max attempts of configuration if policy of integration does not set it", func(t *testing.T) {
+		cfg := cfg
+		cfg.MaxAttempts = 3
+		require.Equal(t, &retryPolicy{
+			maxAttempts:     3,
+			initialInterval: 10 * time.Second,
+			maxInterval:     time.Minute,
+		}, newRetryPolicy(&apimodels.RetryPolicy{
+			InitialInterval: model.Duration(10 * time.Second),
+		}, cfg))
+	})
+
+	t.Run("should not have max interval less than initial interval", func(t *testing.T) {
+		require.Equal(t, &retryPolicy{
+			maxAttempts:     5,
+			initialInterval: 2 * time.Minute,
+			maxInterval:     2 * time.Minute,
+		}, newRetryPolicy(&apimodels.RetryPolicy{
+			MaxAttempts:     5,
+			InitialInterval: model.Duration(2 * time.Minute),
+		}, cfg))
+	})
+}
+
+func TestRetryPolicy_Backoff(t *testing.T) {
+	p := &retryPolicy{
+		maxAttempts:     10,
+		initialInterval: time.Second,
+		maxInterval:     5 * time.Second,
+	}
+	assert.Equal(t, time.Second, p.backoff(1))
+	assert.Equal(t, 2*time.Second, p.backoff(2))
+	assert.Equal(t, 4*time.Second, p.backoff(3))
+	assert.Equal(t, 5*time.Second, p.backoff(4))
+	assert.Equal(t, 5*time.Second, p.backoff(9))
+}
+
+func TestRetryStage(t *testing.T) {
+	alert := &types.Alert{Alert: model.Alert{
+		Labels:   model.LabelSet{"alertname": "test"},
+		StartsAt: time.Now().Add(-time.Minute),
+	}}
+	policy := &retryPolicy{
+		maxAttempts:     3,
+		initialInterval: time.Millisecond,
+		maxInterval:     time.Millisecond,
+	}
+
+	exec := func(t *testing.T, n *fakeHistoryNotifier) (int64, []*types.Alert, error) {
+		t.Helper()
+		integration := notify.NewIntegration(attemptsCountingNotifier{n}, n, "slack", 0)
+		metrics, err := newRetryMetrics(prometheus.NewRegistry())
+		require.NoError(t, err)
+		stage := newRetryStage(integration, "team-a", policy, metrics)
+
+		var attempts int64
+		ctx := notify.WithFiringAlerts(withAttempts(context.Background(), &attempts), []uint64{uint64(alert.Fingerprint())})
+		ctx, cancel := context.WithTimeout(ctx, 5*time.Second)
+		defer cancel()
+		_, sent, err := stage.Exec(ctx, gokitlog.NewNopLogger(), alert)
+		return attempts, sent, err
+	}
+
+	t.Run("should send notification", func(t *testing.T) {
+		attempts, sent, err := exec(t, &fakeHistoryNotifier{sendResolved: true})
+		require.NoError(t, err)
+		require.Len(t, sent, 1)
+		require.Equal(t, int64(1), attempts)
+	})
+
+	t.Run("should retry failed notification", func(t *testing.T) {
+		attempts, sent, err := exec(t, &fakeHistoryNotifier{errs: []error{errors.New("unavailable"), errors.New("unavailable")}, retry: true, sendResolved: true})
+		require.NoError(t, err)
+		require.Len(t, sent, 1)
+		require.Equal(t, int64(3), attempts)
+	})
+
+	t.Run("should give up after max attempts", func(t *testing.T) {
+		errs := []error{errors.New("unavailable"), errors.New("unavailable"), errors.New("unavailable"), errors.New("unavailable")}
+		attempts, _, err := exec(t, &fakeHistoryNotifier{errs: errs, retry: true, sendResolved: true})
+		require.ErrorContains(t, err, "notify retry canceled after 3 attempts: unavailable")
+		require.Equal(t, int64(3), attempts)
+	})
+
+	t.Run("should not retry unrecoverable error", func(t *testing.T) {
+		attempts, _, err := exec(t, &fakeHistoryNotifier{errs: []error{errors.New("bad request")}, sendResolved: true})
+		require.ErrorContains(t, err, "notify retry canceled due to unrecoverable error after 1 attempts: bad request")
+		require.Equal(t, int64(1), attempts)
+	})
+
+	t.Run("should record the metrics of notify.RetryStage", func(t *testing.T) {
+		reg := prometheus.NewPedanticRegistry()
+		// The collectors that notify.NewMetrics registers are shared with the retry stage.
+		_ = notify.NewMetrics(reg)
+		metrics, err := newRetryMetrics(reg)
+		require.NoError(t, err)
+
+		n := &fakeHistoryNotifier{errs: []error{errors.New("unavailable"), errors.New("unavailable"), errors.New("unavailable")}, retry: true, sendResolved: true}
+		integration := notify.NewIntegration(attemptsCountingNotifier{n}, n, "slack", 0)
+		stage := newRetryStage(integration, "team-a", policy, metrics)
+
+		var attempts int64
+		ctx := notify.WithFiringAlerts(withAttempts(context.Background(), &attempts), []uint64{uint64(alert.Fingerprint())})
+		_, _, err = stage.Exec(ctx, gokitlog.NewNopLogger(), alert)
+		require.Error(t, err)
+
+		assert.Equal(t, 1.0, testutil.ToFloat64(metrics.numNotifications.WithLabelValues("slack")))
+		assert.Equal(t, 1.0, testutil.ToFloat64(metrics.numTotalFailedNotifications.WithLabelValues("slack")))
+		assert.Equal(t, 3.0, testutil.ToFloat64(metrics.numNotificationRequestsTotal.WithLabelValues("slack")))
+		assert.Equal(t, 3.0, testutil.ToFloat64(metrics.numNotificationRequestsFailedTotal.WithLabelValues("slack")))
+	})
+
+	t.Run("should use unregistered metrics if they can't be registered", func(t *testing.T) {
+		reg := prometheus.NewPedanticRegistry()
+		reg.MustRegister(prometheus.NewCounterVec(prometheus.CounterOpts{
+			Namespace: "alertmanager",
+			Name:      "notifications_total",
+			Help:      "A different help text.",
+		}, []string{"integration"}))
+
+		metrics, err := newRetryMetrics(reg)
+		require.Error(t, err)
+		require.NotNil(t, metrics.numNotifications)
+		require.NotNil(t, metrics.notificationLatencySeconds)
+
+		n := &fakeHistoryNotifier{sendResolved: true}
+		integration := notify.NewIntegration(attemptsCountingNotifier{n}, n, "slack", 0)
+		stage := newRetryStage(integration, "team-a", policy, metrics)
+		var attempts int64
+		ctx := notify.WithFiringAlerts(withAttempts(context.Background(), &attempts), []uint64{uint64(alert.Fingerprint())})
+		_, _, err = stage.Exec(ctx, gokitlog.NewNopLogger(), alert)
+		require.NoError(t, err)
+		assert.Equal(t, 1.0, testutil.ToFloat64(metrics.numNotifications.WithLabelValues("slack")))
+	})
+}
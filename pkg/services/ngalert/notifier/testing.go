@@ -8,6 +8,7 @@ import (
 	"strings"
 	"sync"
 	"testing"
+	"time"
 
 	"github.com/grafana/grafana/pkg/infra/kvstore"
 	"github.com/grafana/grafana/pkg/services/ngalert/models"
@@ -31,6 +32,38 @@ func (f *FakeConfigStore) GetImages(ctx context.Context, tokens []string) ([]mod
 	return nil, nil, models.ErrImageNotFound
 }
 
+func (f *FakeConfigStore) SaveNotificationHistoryEntry(_ context.Context, _ *models.NotificationHistoryEntry) error {
+	return nil
+}
+
+func (f *FakeConfigStore) GetNotificationHistory(_ context.Context, _ *models.NotificationHistoryQuery) ([]models.NotificationHistoryEntry, error) {
+	return nil, nil
+}
+
+func (f *FakeConfigStore) DeleteNotificationHistoryBefore(_ context.Context, _ int64, _ time.Time) (int64, error) {
+	return 0, nil
+}
+
+func (f *FakeConfigStore) SaveNotificationDeadLetter(_ context.Context, _ *models.NotificationDeadLetter) error {
+	return nil
+}
+
+func (f *FakeConfigStore) GetNotificationDeadLetters(_ context.Context, _ *models.NotificationDeadLetterQuery) ([]models.NotificationDeadLetter, error) {
+	return nil, nil
+}
+
+func (f *FakeConfigStore) GetNotificationDeadLetter(_ context.Context, _, _ int64) (*models.NotificationDeadLetter, error) {
+	return nil, models.ErrNotificationDeadLetterNotFound
+}
+
+func (f *FakeConfigStore) DeleteNotificationDeadLetter(_ context.Context, _, _ int64) error {
+	return models.ErrNotificationDeadLetterNotFound
+}
+
+func (f *FakeConfigStore) DeleteNotificationDeadLettersBefore(_ context.Context, _ int64, _ time.Time) (int64, error) {
+	return 0, nil
+}
+
 func NewFakeConfigStore(t *testing.T, configs map[int64]*models.AlertConfiguration) FakeConfigStore {
 	t.Helper()
 
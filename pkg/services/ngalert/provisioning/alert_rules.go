@@ -16,6 +16,7 @@ import (
 type AlertRuleService struct {
 	defaultIntervalSeconds int64
 	baseIntervalSeconds    int64
+	recordingRulesEnabled  bool
 	ruleStore              RuleStore
 	provenanceStore        ProvisioningStore
 	quotas                 QuotaChecker
@@ -29,10 +30,12 @@ func NewAlertRuleService(ruleStore RuleStore,
 	xact TransactionManager,
 	defaultIntervalSeconds int64,
 	baseIntervalSeconds int64,
+	recordingRulesEnabled bool,
 	log log.Logger) *AlertRuleService {
 	return &AlertRuleService{
 		defaultIntervalSeconds: defaultIntervalSeconds,
 		baseIntervalSeconds:    baseIntervalSeconds,
+		recordingRulesEnabled:  recordingRulesEnabled,
 		ruleStore:              ruleStore,
 		provenanceStore:        provenanceStore,
 		quotas:                 quotas,
@@ -61,6 +64,9 @@ func (service *AlertRuleService) GetAlertRule(ctx context.Context, orgID int64,
 // interval that is set in the rule struct and use the already existing group
 // interval or the default one.
 func (service *AlertRuleService) CreateAlertRule(ctx context.Context, rule models.AlertRule, provenance models.Provenance, userID int64) (models.AlertRule, error) {
+	if err := service.validateRecordingRules(rule); err != nil {
+		return models.AlertRule{}, err
+	}
 	if rule.UID == "" {
 		rule.UID = util.GenerateShortUID()
 	}
@@ -163,6 +169,9 @@ func (service *AlertRuleService) ReplaceRuleGroup(ctx context.Context, orgID int
 	if err := models.ValidateRuleGroupInterval(group.Interval, service.baseIntervalSeconds); err != nil {
 		return err
 	}
+	if err := service.validateRecordingRules(group.Rules...); err != nil {
+		return err
+	}
 
 	// If the provided request did not provide the rules list at all, treat it as though it does not wish to change rules.
 	// This is done for backwards compatibility. Requests which specify only the interval must update only the interval.
@@ -188,10 +197,11 @@ func (service *AlertRuleService) ReplaceRuleGroup(ctx context.Context, orgID int
 		NamespaceUID: group.FolderUID,
 		RuleGroup:    group.Title,
 	}
-	rules := make([]*models.AlertRule, len(group.Rules))
+	rules := make([]*models.AlertRuleWithOptionals, 0, len(group.Rules))
 	group = *syncGroupRuleFields(&group, orgID)
 	for i := range group.Rules {
-		rules = append(rules, &group.Rules[i])
+		// Provisioned rules always have a pause, as the provisioning API model has no optional pause
+		rules = append(rules, &models.AlertRuleWithOptionals{AlertRule: group.Rules[i], HasPause: true})
 	}
 	delta, err := store.CalculateChanges(ctx, service.ruleStore, key, rules)
 	if err != nil {
@@ -266,6 +276,9 @@ func (service *AlertRuleService) ReplaceRuleGroup(ctx context.Context, orgID int
 // interval that is set in the rule struct and fetch the current group interval
 // from database.
 func (service *AlertRuleService) UpdateAlertRule(ctx context.Context, rule models.AlertRule, provenance models.Provenance) (models.AlertRule, error) {
+	if err := service.validateRecordingRules(rule); err != nil {
+		return models.AlertRule{}, err
+	}
 	storedRule, storedProvenance, err := service.GetAlertRule(ctx, rule.OrgID, rule.UID)
 	if err != nil {
 		return models.AlertRule{}, err
@@ -317,6 +330,20 @@ func (service *AlertRuleService) DeleteAlertRule(ctx context.Context, orgID int6
 }
 
 // checkLimitsTransactionCtx checks whether the current transaction (as identified by the ctx) breaches configured alert rule limits.
+// validateRecordingRules returns ErrRecordingRulesDisabled if one of the rules is a recording rule and recording rules
+// are disabled.
+func (service *AlertRuleService) validateRecordingRules(rules ...models.AlertRule) error {
+	if service.recordingRulesEnabled {
+		return nil
+	}
+	for _, rule := range rules {
+		if rule.IsRecordingRule() {
+			return models.ErrRecordingRulesDisabled
+		}
+	}
+	return nil
+}
+
 func (service *AlertRuleService) checkLimitsTransactionCtx(ctx context.Context, orgID, userID int64) error {
 	limitReached, err := service.quotas.CheckQuotaReached(ctx, models.QuotaTargetSrv, &quota.ScopeParameters{
 		OrgID:  orgID,
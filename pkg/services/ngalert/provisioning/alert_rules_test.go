@@ -309,6 +309,28 @@ func TestAlertRuleService(t *testing.T) {
 
 		require.ErrorIs(t, err, models.ErrQuotaReached)
 	})
+
+	t.Run("recording rules are rejected when they are disabled", func(t *testing.T) {
+		ruleService := createAlertRuleService(t)
+		rule := dummyRule("recording-disabled", 1)
+		rule.Record = &models.Record{Metric: "my_metric", From: rule.Condition}
+
+		_, err := ruleService.CreateAlertRule(context.Background(), rule, models.ProvenanceNone, 0)
+		require.ErrorIs(t, err, models.ErrRecordingRulesDisabled)
+		require.ErrorIs(t, err, models.ErrAlertRuleFailedValidation)
+
+		_, err = ruleService.UpdateAlertRule(context.Background(), rule, models.ProvenanceNone)
+		require.ErrorIs(t, err, models.ErrRecordingRulesDisabled)
+
+		group := createDummyGroup("recording-disabled", 1)
+		group.Rules = append(group.Rules, rule)
+		err = ruleService.ReplaceRuleGroup(context.Background(), 1, group, 0, models.ProvenanceAPI)
+		require.ErrorIs(t, err, models.ErrRecordingRulesDisabled)
+
+		ruleService.recordingRulesEnabled = true
+		_, err = ruleService.CreateAlertRule(context.Background(), rule, models.ProvenanceNone, 0)
+		require.NoError(t, err)
+	})
 }
 
 func createAlertRuleService(t *testing.T) AlertRuleService {
@@ -38,6 +38,8 @@ type ContactPointQuery struct {
 	// Optionally filter by name.
 	Name  string
 	OrgID int64
+	// Decrypt returns the decrypted values of the secure settings instead of redacting them.
+	Decrypt bool
 }
 
 func (ecp *ContactPointService) GetContactPoints(ctx context.Context, q ContactPointQuery) ([]apimodels.EmbeddedContactPoint, error) {
@@ -61,6 +63,7 @@ func (ecp *ContactPointService) GetContactPoints(ctx context.Context, q ContactP
 			Name:                  contactPoint.Name,
 			DisableResolveMessage: contactPoint.DisableResolveMessage,
 			Settings:              contactPoint.Settings,
+			RetryPolicy:           contactPoint.RetryPolicy,
 		}
 		if val, exists := provenances[embeddedContactPoint.UID]; exists && val != "" {
 			embeddedContactPoint.Provenance = string(val)
@@ -74,6 +77,10 @@ func (ecp *ContactPointService) GetContactPoints(ctx context.Context, q ContactP
 			if decryptedValue == "" {
 				continue
 			}
+			if q.Decrypt {
+				embeddedContactPoint.Settings.Set(k, decryptedValue)
+				continue
+			}
 			embeddedContactPoint.Settings.Set(k, apimodels.RedactedValue)
 		}
 
@@ -102,6 +109,7 @@ func (ecp *ContactPointService) getContactPointDecrypted(ctx context.Context, or
 			Name:                  receiver.Name,
 			DisableResolveMessage: receiver.DisableResolveMessage,
 			Settings:              receiver.Settings,
+			RetryPolicy:           receiver.RetryPolicy,
 		}
 		for k, v := range receiver.SecureSettings {
 			decryptedValue, err := ecp.decryptValue(v)
@@ -153,6 +161,7 @@ func (ecp *ContactPointService) CreateContactPoint(ctx context.Context, orgID in
 		DisableResolveMessage: contactPoint.DisableResolveMessage,
 		Settings:              contactPoint.Settings,
 		SecureSettings:        extractedSecrets,
+		RetryPolicy:           contactPoint.RetryPolicy,
 	}
 
 	receiverFound := false
@@ -267,6 +276,7 @@ func (ecp *ContactPointService) UpdateContactPoint(ctx context.Context, orgID in
 		DisableResolveMessage: contactPoint.DisableResolveMessage,
 		Settings:              contactPoint.Settings,
 		SecureSettings:        extractedSecrets,
+		RetryPolicy:           contactPoint.RetryPolicy,
 	}
 	// save to store
 	revision, err := getLastConfiguration(ctx, orgID, ecp.amStore)
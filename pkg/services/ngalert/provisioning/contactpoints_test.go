@@ -61,6 +61,26 @@ func TestContactPointService(t *testing.T) {
 		require.Equal(t, "slack", cps[1].Type)
 	})
 
+	t.Run("service redacts secure settings unless they're decrypted", func(t *testing.T) {
+		sut := createContactPointServiceSut(secretsService)
+		newCp := createTestContactPoint()
+
+		_, err := sut.CreateContactPoint(context.Background(), 1, newCp, models.ProvenanceAPI)
+		require.NoError(t, err)
+
+		cps, err := sut.GetContactPoints(context.Background(), cpsQuery(1))
+		require.NoError(t, err)
+		require.Len(t, cps, 2)
+		require.Equal(t, definitions.RedactedValue, cps[1].Settings.Get("token").MustString())
+
+		q := cpsQuery(1)
+		q.Decrypt = true
+		cps, err = sut.GetContactPoints(context.Background(), q)
+		require.NoError(t, err)
+		require.Len(t, cps, 2)
+		require.Equal(t, "value_token", cps[1].Settings.Get("token").MustString())
+	})
+
 	t.Run("it's possible to use a custom uid", func(t *testing.T) {
 		customUID := "1337"
 		sut := createContactPointServiceSut(secretsService)
@@ -0,0 +1,40 @@
+package schedule
+
+import (
+	"github.com/grafana/grafana-plugin-sdk-go/data"
+
+	"github.com/grafana/grafana/pkg/services/ngalert/eval"
+	ngmodels "github.com/grafana/grafana/pkg/services/ngalert/models"
+	"github.com/grafana/grafana/pkg/services/ngalert/state"
+)
+
+var _ eval.AlertingResultsReader = AlertingResultsFromRuleState{}
+
+// AlertingResultsFromRuleState reads the results that are currently alerting from the state of the rule.
+type AlertingResultsFromRuleState struct {
+	Manager RuleStatesReader
+	Rule    *ngmodels.AlertRule
+}
+
+// RuleStatesReader provides the current states of a rule, such as the state manager of the scheduler or the one of
+// a backtesting run.
+type RuleStatesReader interface {
+	GetStatesForRuleUID(orgID int64, alertRuleUID string) []*state.State
+}
+
+// Read returns the labels of the results of the rule that are either Alerting or Pending.
+func (n AlertingResultsFromRuleState) Read() []data.Labels {
+	states := n.Manager.GetStatesForRuleUID(n.Rule.OrgID, n.Rule.UID)
+
+	result := make([]data.Labels, 0, len(states))
+	for _, st := range states {
+		if st.State != eval.Alerting && st.State != eval.Pending {
+			continue
+		}
+		if st.ResultLabels == nil {
+			continue
+		}
+		result = append(result, st.ResultLabels)
+	}
+	return result
+}
@@ -0,0 +1,130 @@
+package schedule
+
+import (
+	"context"
+	"encoding/json"
+	"testing"
+	"time"
+
+	"github.com/benbjohnson/clock"
+	"github.com/grafana/grafana-plugin-sdk-go/data"
+	"github.com/prometheus/client_golang/prometheus"
+	prometheusModel "github.com/prometheus/common/model"
+	"github.com/stretchr/testify/require"
+
+	"github.com/grafana/grafana/pkg/expr"
+	"github.com/grafana/grafana/pkg/plugins"
+	"github.com/grafana/grafana/pkg/services/ngalert/eval"
+	"github.com/grafana/grafana/pkg/services/ngalert/metrics"
+	"github.com/grafana/grafana/pkg/services/ngalert/models"
+	"github.com/grafana/grafana/pkg/services/ngalert/state"
+	"github.com/grafana/grafana/pkg/setting"
+)
+
+func TestAlertingResultsFromRuleState(t *testing.T) {
+	m := metrics.NewNGAlert(prometheus.NewPedanticRegistry())
+	st := state.NewManager(m.GetStateMetrics(), nil, nil, &state.NoopImageService{}, clock.NewMock(), &state.FakeHistorian{})
+
+	rule := models.AlertRuleGen(models.WithFor(time.Minute), func(rule *models.AlertRule) {
+		rule.NoDataState = models.NoData
+	})()
+	reader := AlertingResultsFromRuleState{Manager: st, Rule: rule}
+
+	require.Empty(t, reader.Read())
+
+	now := time.Now()
+	st.ProcessEvalResults(context.Background(), now, rule, eval.Results{
+		{Instance: data.Labels{"test": "pending"}, State: eval.Alerting, EvaluatedAt: now},
+		{Instance: data.Labels{"test": "normal"}, State: eval.Normal, EvaluatedAt: now},
+		{Instance: data.Labels{"test": "nodata"}, State: eval.NoData, EvaluatedAt: now},
+	}, data.Labels{"extra": "label"})
+
+	require.Equal(t, []data.Labels{{"test": "pending"}}, reader.Read())
+
+	other := models.AlertRuleGen(models.WithOrgID(rule.OrgID))()
+	require.Empty(t, AlertingResultsFromRuleState{Manager: st, Rule: other}.Read())
+}
+
+func TestAlertingResultsFromRuleStateAfterWarm(t *testing.T) {
+	// the value of A is between the recovery threshold and the threshold of B
+	rule := models.AlertRuleGen(func(rule *models.AlertRule) {
+		rule.Labels = map[string]string{"team": "infra"}
+		rule.Condition = "B"
+		rule.Data = []models.AlertQuery{
+			{
+				RefID:         "A",
+				DatasourceUID: expr.DatasourceUID,
+				Model:         json.RawMessage(`{"datasourceUid": "-100", "type": "math", "expression": "75"}`),
+			},
+			{
+				RefID:         "B",
+				DatasourceUID: expr.DatasourceUID,
+				Model:         json.RawMessage(`{"datasourceUid": "-100", "type": "threshold", "expression": "A", "conditions": [{"evaluator": {"type": "gt", "params": [80]}, "unloadEvaluator": {"type": "lt", "params": [70]}}]}`),
+			},
+		}
+	})()
+	extraLabels := data.Labels{
+		prometheusModel.AlertNameLabel: rule.Title,
+		models.RuleUIDLabel:            rule.UID,
+		models.NamespaceUIDLabel:       rule.NamespaceUID,
+	}
+
+	instanceLabels := models.InstanceLabels{"team": "infra"}
+	for k, v := range extraLabels {
+		instanceLabels[k] = v
+	}
+	_, hash, err := instanceLabels.StringAndHash()
+	require.NoError(t, err)
+	now := time.Now()
+	instanceStore := &fakeWarmInstanceStore{instances: []models.AlertInstance{{
+		AlertInstanceKey:  models.AlertInstanceKey{RuleOrgID: rule.OrgID, RuleUID: rule.UID, LabelsHash: hash},
+		Labels:            instanceLabels,
+		CurrentState:      models.InstanceStateFiring,
+		CurrentStateSince: now.Add(-time.Hour),
+		CurrentStateEnd:   now.Add(time.Minute),
+		LastEvalTime:      now.Add(-time.Minute),
+	}}}
+	ruleStore := newFakeRulesStore()
+	ruleStore.PutRule(context.Background(), rule)
+
+	m := metrics.NewNGAlert(prometheus.NewPedanticRegistry())
+	st := state.NewManager(m.GetStateMetrics(), nil, instanceStore, &state.NoopImageService{}, clock.NewMock(), &state.FakeHistorian{})
+	st.Warm(context.Background(), ruleStore)
+
+	reader := AlertingResultsFromRuleState{Manager: st, Rule: rule}
+	require.Equal(t, []data.Labels{{}}, reader.Read())
+
+	factory := eval.NewEvaluatorFactory(setting.UnifiedAlertingSettings{}, nil, expr.ProvideService(&setting.Cfg{ExpressionsEnabled: true}, nil, nil, nil), &plugins.FakePluginStore{})
+	ruleEval, err := factory.Create(eval.NewContextWithPreviousResults(context.Background(), schedulerUser(rule.OrgID), reader), rule.GetEvalCondition())
+	require.NoError(t, err)
+	results, err := ruleEval.Evaluate(context.Background(), now)
+	require.NoError(t, err)
+	transitions := st.ProcessEvalResults(context.Background(), now, rule, results, extraLabels)
+
+	require.Len(t, transitions, 1)
+	require.Equal(t, eval.Alerting, transitions[0].State.State)
+	require.Equal(t, now.Add(-time.Hour), transitions[0].State.StartsAt)
+}
+
+// fakeWarmInstanceStore is an instance store that returns the given instances to warm the state cache with.
+type fakeWarmInstanceStore struct {
+	state.FakeInstanceStore
+	instances []models.AlertInstance
+}
+
+func (f *fakeWarmInstanceStore) FetchOrgIds(context.Context) ([]int64, error) {
+	orgIDs := make([]int64, 0, len(f.instances))
+	for _, instance := range f.instances {
+		orgIDs = append(orgIDs, instance.RuleOrgID)
+	}
+	return orgIDs, nil
+}
+
+func (f *fakeWarmInstanceStore) ListAlertInstances(_ context.Context, q *models.ListAlertInstancesQuery) error {
+	for i := range f.instances {
+		if f.instances[i].RuleOrgID == q.RuleOrgID {
+			q.Result = append(q.Result, &f.instances[i])
+		}
+	}
+	return nil
+}
@@ -7,6 +7,7 @@ import (
 	"net/url"
 	"time"
 
+	"github.com/grafana/grafana-plugin-sdk-go/data"
 	prometheusModel "github.com/prometheus/common/model"
 
 	"github.com/grafana/grafana/pkg/infra/log"
@@ -45,6 +46,11 @@ type AlertsSender interface {
 	Send(key ngmodels.AlertRuleKey, alerts definitions.PostableAlerts)
 }
 
+// RecordingWriter is an interface for a service that writes the results of recording rules as metrics.
+type RecordingWriter interface {
+	Write(ctx context.Context, name string, t time.Time, frames data.Frames, extraLabels map[string]string) error
+}
+
 // RulesStore is a store that provides alert rules for scheduling
 type RulesStore interface {
 	GetAlertRulesKeysForScheduling(ctx context.Context) ([]ngmodels.AlertRuleKeyWithVersion, error)
@@ -80,12 +86,14 @@ type schedule struct {
 
 	stateManager *state.Manager
 
-	appURL               *url.URL
-	disableGrafanaFolder bool
+	appURL                *url.URL
+	disableGrafanaFolder  bool
+	disableRecordingRules bool
 
 	metrics *metrics.Scheduler
 
 	alertsSender    AlertsSender
+	recordingWriter RecordingWriter
 	minRuleInterval time.Duration
 
 	// schedulableAlertRules contains the alert rules that are considered for
@@ -107,6 +115,9 @@ type SchedulerCfg struct {
 	RuleStore            RulesStore
 	Metrics              *metrics.Scheduler
 	AlertSender          AlertsSender
+	RecordingWriter      RecordingWriter
+	// DisableRecordingRules stops the evaluation of recording rules that were saved before they were disabled.
+	DisableRecordingRules bool
 }
 
 // NewScheduler returns a new schedule.
@@ -122,10 +133,12 @@ func NewScheduler(cfg SchedulerCfg, stateManager *state.Manager) *schedule {
 		metrics:               cfg.Metrics,
 		appURL:                cfg.AppURL,
 		disableGrafanaFolder:  cfg.DisableGrafanaFolder,
+		disableRecordingRules: cfg.DisableRecordingRules,
 		stateManager:          stateManager,
 		minRuleInterval:       cfg.MinRuleInterval,
 		schedulableAlertRules: alertRulesRegistry{rules: make(map[ngmodels.AlertRuleKey]*ngmodels.AlertRule)},
 		alertsSender:          cfg.AlertSender,
+		recordingWriter:       cfg.RecordingWriter,
 	}
 
 	return &sch
@@ -248,6 +261,25 @@ func (sch *schedule) processTick(ctx context.Context, dispatcherGroup *errgroup.
 			continue
 		}
 
+		if item.IsPaused {
+			// The routine of a paused rule keeps running, but the rule is not evaluated until it is resumed.
+			// Rules can be paused without notifying the scheduler, for example by provisioning, so the routine
+			// is sent the version of the paused rule here. It clears the state after an evaluation that may
+			// still be running, which would otherwise write its results back after the state was cleared.
+			if len(sch.stateManager.GetStatesForRuleUID(key.OrgID, key.UID)) > 0 {
+				sch.log.Info("Clearing the state of the rule because it is paused", key.LogContext()...)
+				go ruleInfo.update(ruleVersion(item.Version))
+			}
+			delete(registeredDefinitions, key)
+			continue
+		}
+
+		if item.IsRecordingRule() && sch.disableRecordingRules {
+			// The results of recording rules would be discarded, so their data sources are not queried.
+			delete(registeredDefinitions, key)
+			continue
+		}
+
 		itemFrequency := item.IntervalSeconds / int64(sch.baseInterval.Seconds())
 		if item.IntervalSeconds != 0 && tickNum%itemFrequency == 0 {
 			var folderTitle string
@@ -316,32 +348,17 @@ func (sch *schedule) ruleRoutine(grafanaCtx context.Context, key ngmodels.AlertR
 	evalTotalFailures := sch.metrics.EvalFailures.WithLabelValues(orgID)
 
 	clearState := func() {
-		states := sch.stateManager.ResetStateByRuleUID(grafanaCtx, key)
-		expiredAlerts := FromAlertsStateToStoppedAlert(states, sch.appURL, sch.clock)
-		if len(expiredAlerts.PostableAlerts) > 0 {
-			sch.alertsSender.Send(key, expiredAlerts)
-		}
+		sch.clearState(grafanaCtx, key)
 	}
 
 	evaluate := func(ctx context.Context, attempt int64, e *evaluation) {
 		logger := logger.New("version", e.rule.Version, "attempt", attempt, "now", e.scheduledAt)
 		start := sch.clock.Now()
 
-		schedulerUser := &user.SignedInUser{
-			UserID:           -1,
-			IsServiceAccount: true,
-			Login:            "grafana_scheduler",
-			OrgID:            e.rule.OrgID,
-			OrgRole:          org.RoleAdmin,
-			Permissions: map[int64]map[string][]string{
-				e.rule.OrgID: {
-					datasources.ActionQuery: []string{
-						datasources.ScopeAll,
-					},
-				},
-			},
-		}
-		evalCtx := eval.Context(ctx, schedulerUser)
+		evalCtx := eval.NewContextWithPreviousResults(ctx, schedulerUser(e.rule.OrgID), AlertingResultsFromRuleState{
+			Manager: sch.stateManager,
+			Rule:    e.rule,
+		})
 		ruleEval, err := sch.evaluatorFactory.Create(evalCtx, e.rule.GetEvalCondition())
 		var results eval.Results
 		var dur time.Duration
@@ -377,6 +394,36 @@ func (sch *schedule) ruleRoutine(grafanaCtx context.Context, key ngmodels.AlertR
 		}
 	}
 
+	record := func(ctx context.Context, attempt int64, e *evaluation) {
+		logger := logger.New("version", e.rule.Version, "attempt", attempt, "now", e.scheduledAt)
+		start := sch.clock.Now()
+
+		var frames data.Frames
+		ruleEval, err := sch.evaluatorFactory.Create(eval.Context(ctx, schedulerUser(e.rule.OrgID)), e.rule.GetEvalCondition())
+		if err == nil {
+			frames, err = evaluateRecord(ctx, ruleEval, e.rule.Record, e.scheduledAt)
+		}
+		dur := sch.clock.Now().Sub(start)
+
+		evalTotal.Inc()
+		evalDuration.Observe(dur.Seconds())
+
+		if err != nil {
+			evalTotalFailures.Inc()
+			logger.Error("Failed to evaluate recording rule", "error", err, "duration", dur)
+			return
+		}
+		if ctx.Err() != nil { // check if the context is not cancelled. The evaluation can be a long-running task.
+			logger.Debug("Skip writing the result because the context has been cancelled")
+			return
+		}
+		if err := sch.recordingWriter.Write(ctx, e.rule.Record.Metric, e.scheduledAt, frames, e.rule.Labels); err != nil {
+			logger.Error("Failed to write the result of recording rule", "error", err)
+			return
+		}
+		logger.Debug("Recording rule evaluated", "duration", dur)
+	}
+
 	retryIfError := func(f func(attempt int64) error) error {
 		var attempt int64
 		var err error
@@ -434,6 +481,10 @@ func (sch *schedule) ruleRoutine(grafanaCtx context.Context, key ngmodels.AlertR
 						}
 						currentRuleVersion = newVersion
 					}
+					if ctx.rule.IsRecordingRule() {
+						record(grafanaCtx, attempt, ctx)
+						return nil
+					}
 					evaluate(grafanaCtx, attempt, ctx)
 					return nil
 				})
@@ -452,6 +503,49 @@ func (sch *schedule) ruleRoutine(grafanaCtx context.Context, key ngmodels.AlertR
 	}
 }
 
+// clearState resets the state of the rule and expires its firing alerts.
+func (sch *schedule) clearState(ctx context.Context, key ngmodels.AlertRuleKey) {
+	states := sch.stateManager.ResetStateByRuleUID(ctx, key)
+	expiredAlerts := FromAlertsStateToStoppedAlert(states, sch.appURL, sch.clock)
+	if len(expiredAlerts.PostableAlerts) > 0 {
+		sch.alertsSender.Send(key, expiredAlerts)
+	}
+}
+
+// schedulerUser returns the user the scheduler evaluates the rules of the organization with.
+func schedulerUser(orgID int64) *user.SignedInUser {
+	return &user.SignedInUser{
+		UserID:           -1,
+		IsServiceAccount: true,
+		Login:            "grafana_scheduler",
+		OrgID:            orgID,
+		OrgRole:          org.RoleAdmin,
+		Permissions: map[int64]map[string][]string{
+			orgID: {
+				datasources.ActionQuery: []string{
+					datasources.ScopeAll,
+				},
+			},
+		},
+	}
+}
+
+// evaluateRecord evaluates the queries and expressions of a recording rule and returns the frames of the one the rule records.
+func evaluateRecord(ctx context.Context, ruleEval eval.ConditionEvaluator, record *ngmodels.Record, now time.Time) (data.Frames, error) {
+	resp, err := ruleEval.EvaluateRaw(ctx, now)
+	if err != nil {
+		return nil, err
+	}
+	res, ok := resp.Responses[record.From]
+	if !ok {
+		return nil, fmt.Errorf("no result for the query or expression %s", record.From)
+	}
+	if res.Error != nil {
+		return nil, fmt.Errorf("failed to evaluate the query or expression %s: %w", record.From, res.Error)
+	}
+	return res.Frames, nil
+}
+
 // evalApplied is only used on tests.
 func (sch *schedule) evalApplied(alertDefKey ngmodels.AlertRuleKey, now time.Time) {
 	if sch.evalAppliedFunc == nil {
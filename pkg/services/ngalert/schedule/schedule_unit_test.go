@@ -188,6 +188,25 @@ func TestProcessTicks(t *testing.T) {
 
 		assertEvalRun(t, evalAppliedCh, tick, alertRule3.GetKey())
 	})
+
+	t.Run("on 8th tick a paused alert rule should not be evaluated nor stopped", func(t *testing.T) {
+		// create a paused alert rule with one base interval
+		alertRule4 := models.AlertRuleGen(models.WithOrgID(mainOrgID), models.WithInterval(cfg.BaseInterval), models.WithTitle("rule-4"), models.WithIsPaused(true))()
+		ruleStore.PutRule(ctx, alertRule4)
+		tick = tick.Add(cfg.BaseInterval)
+
+		scheduled, stopped := sched.processTick(ctx, dispatcherGroup, tick)
+
+		var keys []models.AlertRuleKey
+		for _, item := range scheduled {
+			keys = append(keys, item.rule.GetKey())
+		}
+		require.NotContains(t, keys, alertRule4.GetKey())
+		require.Emptyf(t, stopped, "None rules are expected to be stopped")
+		require.True(t, sched.registry.exists(alertRule4.GetKey()))
+
+		assertEvalRun(t, evalAppliedCh, tick, keys...)
+	})
 }
 
 func TestSchedule_ruleRoutine(t *testing.T) {
@@ -579,6 +598,139 @@ func TestSchedule_ruleRoutine(t *testing.T) {
 
 		require.NotEmpty(t, sch.stateManager.GetStatesForRuleUID(rule.OrgID, rule.UID))
 	})
+
+	t.Run("when the rule is a recording rule", func(t *testing.T) {
+		rule := models.AlertRuleGen(withQueryForState(t, eval.Alerting), models.WithRecord("test_metric"))()
+
+		evalChan := make(chan *evaluation)
+		evalAppliedChan := make(chan time.Time)
+
+		sender := AlertsSenderMock{}
+
+		sch, ruleStore, _, reg := createSchedule(evalAppliedChan, &sender)
+		recordingWriter := &fakeRecordingWriter{}
+		sch.recordingWriter = recordingWriter
+		ruleStore.PutRule(context.Background(), rule)
+
+		go func() {
+			ctx, cancel := context.WithCancel(context.Background())
+			t.Cleanup(cancel)
+			_ = sch.ruleRoutine(ctx, rule.GetKey(), evalChan, make(chan ruleVersion))
+		}()
+
+		scheduledAt := sch.clock.Now()
+		evalChan <- &evaluation{
+			scheduledAt: scheduledAt,
+			rule:        rule,
+		}
+
+		waitForTimeChannel(t, evalAppliedChan)
+
+		t.Run("it should write the result of the rule", func(t *testing.T) {
+			writes := recordingWriter.Writes()
+			require.Len(t, writes, 1)
+			require.Equal(t, "test_metric", writes[0].Name)
+			require.Equal(t, scheduledAt, writes[0].T)
+			require.Equal(t, rule.Labels, writes[0].ExtraLabels)
+			require.Len(t, writes[0].Frames, 1)
+			value, err := writes[0].Frames[0].Fields[0].NullableFloatAt(0)
+			require.NoError(t, err)
+			require.Equal(t, 1.0, *value)
+		})
+
+		t.Run("it should not create alerts", func(t *testing.T) {
+			sender.AssertNotCalled(t, "Send", mock.Anything, mock.Anything)
+			require.Empty(t, sch.stateManager.GetStatesForRuleUID(rule.OrgID, rule.UID))
+		})
+
+		t.Run("it reports metrics", func(t *testing.T) {
+			expectedMetric := fmt.Sprintf(
+				`# HELP grafana_alerting_rule_evaluations_total The total number of rule evaluations.
+				# TYPE grafana_alerting_rule_evaluations_total counter
+				grafana_alerting_rule_evaluations_total{org="%[1]d"} 1
+				# HELP grafana_alerting_rule_evaluation_failures_total The total number of rule evaluation failures.
+				# TYPE grafana_alerting_rule_evaluation_failures_total counter
+				grafana_alerting_rule_evaluation_failures_total{org="%[1]d"} 0
+				`, rule.OrgID)
+
+			err := testutil.GatherAndCompare(reg, bytes.NewBufferString(expectedMetric), "grafana_alerting_rule_evaluations_total", "grafana_alerting_rule_evaluation_failures_total")
+			require.NoError(t, err)
+		})
+	})
+}
+
+func TestSchedule_PauseAlertRule(t *testing.T) {
+	t.Run("should clear the state when the rule is paused without an update", func(t *testing.T) {
+		rule := models.AlertRuleGen(withQueryForState(t, eval.Alerting), models.WithIsPaused(false))()
+
+		sent := make(chan struct{})
+		sender := AlertsSenderMock{}
+		sender.EXPECT().Send(rule.GetKey(), mock.Anything).Run(func(models.AlertRuleKey, definitions.PostableAlerts) {
+			close(sent)
+		}).Return()
+
+		ruleStore := newFakeRulesStore()
+		ruleStore.PutRule(context.Background(), rule)
+		sch := setupScheduler(t, ruleStore, nil, nil, &sender, nil)
+
+		sch.stateManager.Put([]*state.State{{
+			AlertRuleUID: rule.UID,
+			CacheID:      util.GenerateShortUID(),
+			OrgID:        rule.OrgID,
+			State:        eval.Alerting,
+			StartsAt:     sch.clock.Now(),
+			EndsAt:       sch.clock.Now().Add(time.Minute),
+			Labels:       rule.Labels,
+		}})
+
+		// pause the rule in the store, as provisioning does, without sending its version to the scheduler
+		paused := *rule
+		paused.IsPaused = true
+		paused.Version++
+		ruleStore.PutRule(context.Background(), &paused)
+
+		ctx, cancel := context.WithCancel(context.Background())
+		t.Cleanup(cancel)
+		dispatcherGroup, ctx := errgroup.WithContext(ctx)
+		scheduled, stopped := sch.processTick(ctx, dispatcherGroup, sch.clock.Now())
+
+		require.Empty(t, scheduled)
+		require.Empty(t, stopped)
+		// the state is cleared by the routine of the rule
+		select {
+		case <-sent:
+		case <-time.After(5 * time.Second):
+			t.Fatal("The state of the paused rule was not cleared")
+		}
+		require.Empty(t, sch.stateManager.GetStatesForRuleUID(rule.OrgID, rule.UID))
+		sender.AssertNumberOfCalls(t, "Send", 1)
+		args, ok := sender.Calls[0].Arguments[1].(definitions.PostableAlerts)
+		require.Truef(t, ok, fmt.Sprintf("expected argument of function was supposed to be 'definitions.PostableAlerts' but got %T", sender.Calls[0].Arguments[1]))
+		require.Len(t, args.PostableAlerts, 1)
+	})
+}
+
+func TestSchedule_RecordingRulesDisabled(t *testing.T) {
+	rule := models.AlertRuleGen(withQueryForState(t, eval.Alerting), models.WithRecord("test_metric"), models.WithIsPaused(false))()
+
+	ruleStore := newFakeRulesStore()
+	ruleStore.PutRule(context.Background(), rule)
+	sch := setupScheduler(t, ruleStore, nil, nil, nil, nil)
+	sch.disableRecordingRules = true
+
+	ctx, cancel := context.WithCancel(context.Background())
+	t.Cleanup(cancel)
+	dispatcherGroup, ctx := errgroup.WithContext(ctx)
+	// every rule is evaluated on the first tick
+	scheduled, stopped := sch.processTick(ctx, dispatcherGroup, time.Unix(0, 0))
+
+	require.Empty(t, scheduled)
+	require.Empty(t, stopped)
+	require.True(t, sch.registry.exists(rule.GetKey()))
+
+	sch.disableRecordingRules = false
+	scheduled, _ = sch.processTick(ctx, dispatcherGroup, time.Unix(0, 0))
+	require.Len(t, scheduled, 1)
 }
 
 func TestSchedule_UpdateAlertRule(t *testing.T) {
@@ -652,7 +804,7 @@ func setupScheduler(t *testing.T, rs *fakeRulesStore, is *state.FakeInstanceStor
 
 	var evaluator = evalMock
 	if evalMock == nil {
-		evaluator = eval.NewEvaluatorFactory(setting.UnifiedAlertingSettings{}, nil, expr.ProvideService(&setting.Cfg{ExpressionsEnabled: true}, nil, nil), &plugins.FakePluginStore{})
+		evaluator = eval.NewEvaluatorFactory(setting.UnifiedAlertingSettings{}, nil, expr.ProvideService(&setting.Cfg{ExpressionsEnabled: true}, nil, nil, nil), &plugins.FakePluginStore{})
 	}
 
 	if registry == nil {
@@ -2,9 +2,12 @@ package schedule
 
 import (
 	"context"
+	"sync"
 	"testing"
 	"time"
 
+	"github.com/grafana/grafana-plugin-sdk-go/data"
+
 	"github.com/grafana/grafana/pkg/services/ngalert/models"
 )
 
@@ -65,6 +68,15 @@ func (f *fakeRulesStore) GetAlertRulesForScheduling(ctx context.Context, query *
 	return nil
 }
 
+func (f *fakeRulesStore) ListAlertRules(_ context.Context, q *models.ListAlertRulesQuery) error {
+	for _, rule := range f.rules {
+		if rule.OrgID == q.OrgID {
+			q.Result = append(q.Result, rule)
+		}
+	}
+	return nil
+}
+
 func (f *fakeRulesStore) PutRule(_ context.Context, rules ...*models.AlertRule) {
 	for _, r := range rules {
 		f.rules[r.UID] = r
@@ -80,3 +92,28 @@ func (f *fakeRulesStore) DeleteRule(rules ...*models.AlertRule) {
 func (f *fakeRulesStore) getNamespaceTitle(uid string) string {
 	return "TEST-FOLDER-" + uid
 }
+
+type fakeRecordingWriter struct {
+	mtx    sync.Mutex
+	writes []fakeRecordingWrite
+}
+
+type fakeRecordingWrite struct {
+	Name        string
+	T           time.Time
+	Frames      data.Frames
+	ExtraLabels map[string]string
+}
+
+func (f *fakeRecordingWriter) Write(_ context.Context, name string, t time.Time, frames data.Frames, extraLabels map[string]string) error {
+	f.mtx.Lock()
+	defer f.mtx.Unlock()
+	f.writes = append(f.writes, fakeRecordingWrite{Name: name, T: t, Frames: frames, ExtraLabels: extraLabels})
+	return nil
+}
+
+func (f *fakeRecordingWriter) Writes() []fakeRecordingWrite {
+	f.mtx.Lock()
+	defer f.mtx.Unlock()
+	return append([]fakeRecordingWrite(nil), f.writes...)
+}
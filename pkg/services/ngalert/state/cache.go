@@ -9,6 +9,7 @@ import (
 	"sync"
 
 	"github.com/grafana/grafana-plugin-sdk-go/data"
+	"github.com/prometheus/common/model"
 
 	"github.com/grafana/grafana/pkg/infra/log"
 	"github.com/grafana/grafana/pkg/services/ngalert/eval"
@@ -113,6 +114,7 @@ func (rs *ruleStates) getOrCreate(ctx context.Context, log log.Logger, alertRule
 		}
 		state.Annotations = annotations
 		state.Values = values
+		state.ResultLabels = result.Instance
 		rs.states[id] = state
 		return state
 	}
@@ -124,6 +126,7 @@ func (rs *ruleStates) getOrCreate(ctx context.Context, log log.Logger, alertRule
 		OrgID:              alertRule.OrgID,
 		CacheID:            id,
 		Labels:             lbs,
+		ResultLabels:       result.Instance,
 		Annotations:        annotations,
 		EvaluationDuration: result.EvaluationDuration,
 		Values:             values,
@@ -291,6 +294,24 @@ func (c *cache) recordMetrics(metrics *metrics.State) {
 	}
 }
 
+// resultLabelsFromLabels returns the labels of the evaluation result a state with the given labels was created
+// from. These are the labels of the state without the labels of the rule and the labels added by the scheduler,
+// which both take precedence over the labels of the result.
+func resultLabelsFromLabels(alertRule *ngModels.AlertRule, lbs data.Labels) data.Labels {
+	result := make(data.Labels, len(lbs))
+	for k, v := range lbs {
+		if _, ok := alertRule.Labels[k]; ok {
+			continue
+		}
+		switch k {
+		case model.AlertNameLabel, ngModels.RuleUIDLabel, ngModels.NamespaceUIDLabel, ngModels.FolderTitleLabel:
+			continue
+		}
+		result[k] = v
+	}
+	return result
+}
+
 // if duplicate labels exist, keep the value from the first set
 func mergeLabels(a, b data.Labels) data.Labels {
 	newLbs := make(data.Labels, len(a)+len(b))
@@ -2,6 +2,7 @@ package historian
 
 import (
 	"context"
+	"encoding/json"
 	"fmt"
 	"sort"
 	"strconv"
@@ -23,14 +24,21 @@ import (
 type AnnotationStateHistorian struct {
 	annotations annotations.Repository
 	dashboards  *dashboardResolver
+	rules       RuleStore
 	log         log.Logger
 }
 
-func NewAnnotationHistorian(annotations annotations.Repository, dashboards dashboards.DashboardService) *AnnotationStateHistorian {
+// RuleStore is the subset of the rule store used to resolve rules when querying state history.
+type RuleStore interface {
+	GetAlertRuleByUID(ctx context.Context, query *ngmodels.GetAlertRuleByUIDQuery) error
+}
+
+func NewAnnotationHistorian(annotations annotations.Repository, dashboards dashboards.DashboardService, rules RuleStore) *AnnotationStateHistorian {
 	return &AnnotationStateHistorian{
 		annotations: annotations,
 		dashboards:  newDashboardResolver(dashboards, defaultDashboardCacheExpiry),
-		log:         log.New("ngalert.state.historian"),
+		rules:       rules,
+		log:         log.New("ngalert.state.historian", "backend", "annotations"),
 	}
 }
 
@@ -43,10 +51,65 @@ func (h *AnnotationStateHistorian) RecordStatesAsync(ctx context.Context, rule *
 	go h.recordAnnotationsSync(ctx, panel, annotations, logger)
 }
 
+// QueryStates reads the state transitions of a single rule from the annotations of that rule.
+func (h *AnnotationStateHistorian) QueryStates(ctx context.Context, query ngmodels.HistoryQuery) (*data.Frame, error) {
+	if query.RuleUID == "" {
+		return nil, fmt.Errorf("%w: ruleUID is required to query annotations", ngmodels.ErrHistoryQueryUnsupported)
+	}
+
+	q := ngmodels.GetAlertRuleByUIDQuery{UID: query.RuleUID, OrgID: query.OrgID}
+	if err := h.rules.GetAlertRuleByUID(ctx, &q); err != nil {
+		return nil, fmt.Errorf("failed to look up the requested rule: %w", err)
+	}
+	if q.Result == nil {
+		return nil, fmt.Errorf("no such rule exists")
+	}
+
+	items, err := h.annotations.Find(ctx, &annotations.ItemQuery{
+		OrgId:        query.OrgID,
+		AlertId:      q.Result.ID,
+		From:         query.From.UnixMilli(),
+		To:           query.To.UnixMilli(),
+		Limit:        int64(query.Limit),
+		SignedInUser: query.SignedInUser,
+	})
+	if err != nil {
+		return nil, fmt.Errorf("failed to query annotations for state history: %w", err)
+	}
+
+	// Annotations are returned newest first, the frame is ordered oldest first.
+	sort.SliceStable(items, func(i, j int) bool {
+		return items[i].Time < items[j].Time
+	})
+
+	frame := NewStatesFrame(len(items))
+	for _, item := range items {
+		line, err := json.Marshal(annotationLine{
+			Previous: item.PrevState,
+			Current:  item.NewState,
+			Text:     item.Text,
+			Data:     item.Data,
+		})
+		if err != nil {
+			return nil, fmt.Errorf("failed to marshal annotation: %w", err)
+		}
+		frame.AppendRow(time.UnixMilli(item.Time), json.RawMessage(line), json.RawMessage("{}"))
+	}
+	return frame, nil
+}
+
+// annotationLine is the representation of a state annotation in the frame returned by QueryStates.
+type annotationLine struct {
+	Previous string           `json:"previous"`
+	Current  string           `json:"current"`
+	Text     string           `json:"text"`
+	Data     *simplejson.Json `json:"data"`
+}
+
 func (h *AnnotationStateHistorian) buildAnnotations(rule *ngmodels.AlertRule, states []state.StateTransition, logger log.Logger) []annotations.Item {
 	items := make([]annotations.Item, 0, len(states))
 	for _, state := range states {
-		if !shouldAnnotate(state) {
+		if !shouldRecord(state) {
 			continue
 		}
 		logger.Debug("Alert state changed creating annotation", "newState", state.Formatted(), "oldState", state.PreviousFormatted())
@@ -148,21 +211,3 @@ func buildAnnotationTextAndData(rule *ngmodels.AlertRule, currentState *state.St
 	labels := removePrivateLabels(currentState.Labels)
 	return fmt.Sprintf("%s {%s} - %s", rule.Title, labels.String(), value), jsonData
 }
-
-func removePrivateLabels(labels data.Labels) data.Labels {
-	result := make(data.Labels)
-	for k, v := range labels {
-		if !strings.HasPrefix(k, "__") && !strings.HasSuffix(k, "__") {
-			result[k] = v
-		}
-	}
-	return result
-}
-
-func shouldAnnotate(transition state.StateTransition) bool {
-	// Do not log not transitioned states normal states if it was marked as stale
-	if !transition.Changed() || transition.StateReason == ngmodels.StateReasonMissingSeries && transition.PreviousState == eval.Normal && transition.State.State == eval.Normal {
-		return false
-	}
-	return true
-}
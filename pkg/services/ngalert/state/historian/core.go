@@ -0,0 +1,70 @@
+package historian
+
+import (
+	"encoding/json"
+	"strings"
+	"time"
+
+	"github.com/grafana/grafana-plugin-sdk-go/data"
+
+	"github.com/grafana/grafana/pkg/services/ngalert/eval"
+	ngmodels "github.com/grafana/grafana/pkg/services/ngalert/models"
+	"github.com/grafana/grafana/pkg/services/ngalert/state"
+)
+
+// BackendType identifies a state history backend.
+type BackendType string
+
+const (
+	BackendTypeAnnotations BackendType = "annotations"
+	BackendTypeLoki        BackendType = "loki"
+)
+
+// ParseBackendType parses the state history backend setting.
+func ParseBackendType(s string) (BackendType, bool) {
+	switch BackendType(strings.ToLower(strings.TrimSpace(s))) {
+	case BackendTypeAnnotations:
+		return BackendTypeAnnotations, true
+	case BackendTypeLoki:
+		return BackendTypeLoki, true
+	}
+	return "", false
+}
+
+const (
+	frameName   = "states"
+	fieldTime   = "time"
+	fieldLine   = "line"
+	fieldLabels = "labels"
+)
+
+// NewStatesFrame creates an empty data frame in the shape returned by all historian backends.
+// Every row is a state transition: its timestamp, the JSON encoded transition and the JSON encoded instance labels.
+func NewStatesFrame(capacity int) *data.Frame {
+	times := make([]time.Time, 0, capacity)
+	lines := make([]json.RawMessage, 0, capacity)
+	labels := make([]json.RawMessage, 0, capacity)
+	return data.NewFrame(frameName,
+		data.NewField(fieldTime, nil, times),
+		data.NewField(fieldLine, nil, lines),
+		data.NewField(fieldLabels, nil, labels),
+	)
+}
+
+func shouldRecord(transition state.StateTransition) bool {
+	// Do not log not transitioned states normal states if it was marked as stale
+	if !transition.Changed() || transition.StateReason == ngmodels.StateReasonMissingSeries && transition.PreviousState == eval.Normal && transition.State.State == eval.Normal {
+		return false
+	}
+	return true
+}
+
+func removePrivateLabels(labels data.Labels) data.Labels {
+	result := make(data.Labels)
+	for k, v := range labels {
+		if !strings.HasPrefix(k, "__") && !strings.HasSuffix(k, "__") {
+			result[k] = v
+		}
+	}
+	return result
+}
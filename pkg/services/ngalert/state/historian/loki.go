@@ -0,0 +1,333 @@
+package historian
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"math"
+	"regexp"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/grafana/grafana-plugin-sdk-go/data"
+
+	"github.com/grafana/grafana/pkg/components/simplejson"
+	"github.com/grafana/grafana/pkg/infra/log"
+	"github.com/grafana/grafana/pkg/services/ngalert/eval"
+	ngmodels "github.com/grafana/grafana/pkg/services/ngalert/models"
+	"github.com/grafana/grafana/pkg/services/ngalert/state"
+)
+
+const (
+	OrgIDLabel     = "orgID"
+	RuleUIDLabel   = "ruleUID"
+	GroupLabel     = "group"
+	FolderUIDLabel = "folderUID"
+)
+
+const (
+	StateHistoryLabelKey   = "from"
+	StateHistoryLabelValue = "state-history"
+)
+
+// lokiEntrySchemaVersion is the version of the lokiEntry format. It should be incremented on breaking changes.
+const lokiEntrySchemaVersion = 1
+
+type remoteLokiClient interface {
+	ping(context.Context) error
+	push(context.Context, []stream) error
+	query(ctx context.Context, logQuery string, start, end int64, limit int) (queryRes, error)
+}
+
+// RemoteLokiBackend is an implementation of state.Historian that pushes state transitions to a remote Loki instance
+// as structured log lines, and reads them back from the same instance.
+type RemoteLokiBackend struct {
+	client         remoteLokiClient
+	externalLabels map[string]string
+	log            log.Logger
+}
+
+func NewRemoteLokiBackend(cfg LokiConfig) *RemoteLokiBackend {
+	logger := log.New("ngalert.state.historian", "backend", "loki")
+	return &RemoteLokiBackend{
+		client:         newLokiClient(cfg, logger),
+		externalLabels: cfg.ExternalLabels,
+		log:            logger,
+	}
+}
+
+// TestConnection checks that the remote Loki instance can be reached.
+func (h *RemoteLokiBackend) TestConnection(ctx context.Context) error {
+	return h.client.ping(ctx)
+}
+
+// RecordStatesAsync writes a number of state transitions for a given rule to the remote Loki instance.
+func (h *RemoteLokiBackend) RecordStatesAsync(ctx context.Context, rule *ngmodels.AlertRule, states []state.StateTransition) {
+	logger := h.log.FromContext(ctx)
+	// Build streams before starting goroutine, to make sure all data is copied and won't mutate underneath us.
+	streams := statesToStreams(rule, states, h.externalLabels, logger)
+	if len(streams) == 0 {
+		return
+	}
+	go h.recordStreamsSync(ctx, streams, logger)
+}
+
+// QueryStates reads the state transitions of a rule, or of the given rules of an organization, from the remote Loki instance.
+func (h *RemoteLokiBackend) QueryStates(ctx context.Context, query ngmodels.HistoryQuery) (*data.Frame, error) {
+	res := queryRes{}
+	for _, q := range splitByRuleUIDs(query) {
+		selectors, err := buildSelectors(q)
+		if err != nil {
+			return nil, fmt.Errorf("failed to build the provided selectors: %w", err)
+		}
+		r, err := h.client.query(ctx, buildLogQuery(selectors, q.Labels), q.From.UnixNano(), q.To.UnixNano(), q.Limit)
+		if err != nil {
+			return nil, err
+		}
+		res.Data.Result = append(res.Data.Result, r.Data.Result...)
+	}
+	return merge(res, query.Labels, query.Limit)
+}
+
+// maxRuleUIDsPerQuery is the maximum number of rules selected by a single Loki query, so that queries for the rules
+// of large organizations stay within the query length limits of Loki.
+const maxRuleUIDsPerQuery = 100
+
+// splitByRuleUIDs splits a query for many rules into queries for at most maxRuleUIDsPerQuery rules each.
+func splitByRuleUIDs(query ngmodels.HistoryQuery) []ngmodels.HistoryQuery {
+	if query.RuleUID != "" || len(query.RuleUIDs) <= maxRuleUIDsPerQuery {
+		return []ngmodels.HistoryQuery{query}
+	}
+	queries := make([]ngmodels.HistoryQuery, 0, len(query.RuleUIDs)/maxRuleUIDsPerQuery+1)
+	for start := 0; start < len(query.RuleUIDs); start += maxRuleUIDsPerQuery {
+		end := start + maxRuleUIDsPerQuery
+		if end > len(query.RuleUIDs) {
+			end = len(query.RuleUIDs)
+		}
+		q := query
+		q.RuleUIDs = query.RuleUIDs[start:end]
+		queries = append(queries, q)
+	}
+	return queries
+}
+
+func (h *RemoteLokiBackend) recordStreamsSync(ctx context.Context, streams []stream, logger log.Logger) {
+	if err := h.client.push(ctx, streams); err != nil {
+		logger.Error("Failed to save alert state history batch", "error", err)
+		return
+	}
+	logger.Debug("Done saving alert state history batch")
+}
+
+// lokiEntry is the JSON encoded log line written to Loki for every state transition.
+type lokiEntry struct {
+	SchemaVersion int               `json:"schemaVersion"`
+	Previous      string            `json:"previous"`
+	Current       string            `json:"current"`
+	Error         string            `json:"error,omitempty"`
+	Values        *simplejson.Json  `json:"values"`
+	Labels        map[string]string `json:"labels"`
+	RuleTitle     string            `json:"ruleTitle"`
+	DashboardUID  string            `json:"dashboardUID"`
+	PanelID       int64             `json:"panelID"`
+}
+
+func statesToStreams(rule *ngmodels.AlertRule, states []state.StateTransition, externalLabels map[string]string, logger log.Logger) []stream {
+	values := make([]sample, 0, len(states))
+	for _, state := range states {
+		if !shouldRecord(state) {
+			continue
+		}
+
+		entry := lokiEntry{
+			SchemaVersion: lokiEntrySchemaVersion,
+			Previous:      state.PreviousFormatted(),
+			Current:       state.Formatted(),
+			Values:        valuesAsDataBlob(state.State),
+			Labels:        removePrivateLabels(state.State.Labels),
+			RuleTitle:     rule.Title,
+		}
+		if state.State.State == eval.Error && state.Error != nil {
+			entry.Error = state.Error.Error()
+		}
+		if panel := parsePanelKey(rule, logger); panel != nil {
+			entry.DashboardUID = panel.dashUID
+			entry.PanelID = panel.panelID
+		}
+
+		line, err := json.Marshal(entry)
+		if err != nil {
+			logger.Error("Failed to construct history record for state, skipping", "error", err)
+			continue
+		}
+
+		values = append(values, sample{
+			T: state.State.LastEvaluationTime,
+			V: string(line),
+		})
+	}
+
+	if len(values) == 0 {
+		return nil
+	}
+
+	labels := make(map[string]string, len(externalLabels)+5)
+	for k, v := range externalLabels {
+		labels[k] = v
+	}
+	labels[StateHistoryLabelKey] = StateHistoryLabelValue
+	labels[OrgIDLabel] = strconv.FormatInt(rule.OrgID, 10)
+	labels[RuleUIDLabel] = rule.UID
+	labels[GroupLabel] = rule.RuleGroup
+	labels[FolderUIDLabel] = rule.NamespaceUID
+
+	return []stream{{Stream: labels, Values: values}}
+}
+
+func valuesAsDataBlob(state *state.State) *simplejson.Json {
+	if state.State == eval.Error || state.State == eval.NoData {
+		return simplejson.New()
+	}
+	return jsonifyValues(state.Values)
+}
+
+// jsonifyValues converts the values of a state to JSON. NaN and Inf cannot be represented as JSON numbers, so they are written as strings.
+func jsonifyValues(vs map[string]float64) *simplejson.Json {
+	j := simplejson.New()
+	for k, v := range vs {
+		switch {
+		case math.IsInf(v, 0), math.IsNaN(v):
+			j.Set(k, fmt.Sprintf("%f", v))
+		default:
+			j.Set(k, v)
+		}
+	}
+	return j
+}
+
+func buildSelectors(query ngmodels.HistoryQuery) ([]selector, error) {
+	// +2 as OrgID and the state history label will always be selectors at the API level.
+	selectors := make([]selector, 0, 3)
+
+	// Set the predefined selector orgID.
+	s, err := newSelector(OrgIDLabel, "=", strconv.FormatInt(query.OrgID, 10))
+	if err != nil {
+		return nil, err
+	}
+	selectors = append(selectors, s)
+
+	// Set the predefined selector for state history.
+	s, err = newSelector(StateHistoryLabelKey, "=", StateHistoryLabelValue)
+	if err != nil {
+		return nil, err
+	}
+	selectors = append(selectors, s)
+
+	// Set the optional special selector rule_id, or restrict the streams to the given rules.
+	switch {
+	case query.RuleUID != "":
+		s, err = newSelector(RuleUIDLabel, "=", query.RuleUID)
+		if err != nil {
+			return nil, err
+		}
+		selectors = append(selectors, s)
+	case len(query.RuleUIDs) > 0:
+		uids := make([]string, 0, len(query.RuleUIDs))
+		for _, uid := range query.RuleUIDs {
+			uids = append(uids, regexp.QuoteMeta(uid))
+		}
+		s, err = newSelector(RuleUIDLabel, "=~", strings.Join(uids, "|"))
+		if err != nil {
+			return nil, err
+		}
+		selectors = append(selectors, s)
+	}
+
+	return selectors, nil
+}
+
+// buildLogQuery renders the stream selectors and the instance label filters as a LogQL query. Instance labels are
+// stored in the log line, so they are extracted with the json parser and filtered by Loki before it applies the limit.
+func buildLogQuery(selectors []selector, labelFilter map[string]string) string {
+	query := selectorString(selectors)
+	if len(labelFilter) == 0 {
+		return query
+	}
+
+	keys := make([]string, 0, len(labelFilter))
+	for k := range labelFilter {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+
+	filters := make([]string, 0, len(keys))
+	for _, k := range keys {
+		filters = append(filters, fmt.Sprintf("%s=%q", jsonLabelName("labels_"+k), labelFilter[k]))
+	}
+	return query + " | json | " + strings.Join(filters, " | ")
+}
+
+var invalidLabelChars = regexp.MustCompile(`[^a-zA-Z0-9_]`)
+
+// jsonLabelName returns the name of the label extracted by the Loki json parser for a JSON field, which replaces
+// characters that aren't valid in label names with underscores.
+func jsonLabelName(field string) string {
+	return invalidLabelChars.ReplaceAllString(field, "_")
+}
+
+type frameRow struct {
+	t      time.Time
+	line   json.RawMessage
+	labels json.RawMessage
+}
+
+// merge flattens the streams of a Loki response into a single frame sorted by time. Loki already filters by instance
+// labels, but the json parser sanitizes label names, so the exact labels are checked again here. If limit is set,
+// only the latest rows are kept, as the streams can come from several queries that each applied the limit.
+func merge(res queryRes, labelFilter map[string]string, limit int) (*data.Frame, error) {
+	rows := make([]frameRow, 0)
+	for _, s := range res.Data.Result {
+		for _, sample := range s.Values {
+			entry := lokiEntry{}
+			if err := json.Unmarshal([]byte(sample.V), &entry); err != nil {
+				return nil, fmt.Errorf("failed to unmarshal entry: %w", err)
+			}
+			if !labelsMatch(entry.Labels, labelFilter) {
+				continue
+			}
+			labels, err := json.Marshal(entry.Labels)
+			if err != nil {
+				return nil, fmt.Errorf("failed to marshal labels: %w", err)
+			}
+			rows = append(rows, frameRow{
+				t:      sample.T,
+				line:   json.RawMessage(sample.V),
+				labels: labels,
+			})
+		}
+	}
+
+	sort.SliceStable(rows, func(i, j int) bool {
+		return rows[i].t.Before(rows[j].t)
+	})
+	if limit > 0 && len(rows) > limit {
+		rows = rows[len(rows)-limit:]
+	}
+
+	frame := NewStatesFrame(len(rows))
+	for _, r := range rows {
+		frame.AppendRow(r.t, r.line, r.labels)
+	}
+	return frame, nil
+}
+
+func labelsMatch(labels map[string]string, filter map[string]string) bool {
+	for k, v := range filter {
+		if labels[k] != v {
+			return false
+		}
+	}
+	return true
+}
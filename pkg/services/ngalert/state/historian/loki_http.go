@@ -0,0 +1,295 @@
+package historian
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/grafana/grafana/pkg/infra/log"
+	"github.com/grafana/grafana/pkg/setting"
+)
+
+const defaultLokiClientTimeout = 30 * time.Second
+
+// LokiConfig contains the settings required to talk to a remote Loki instance.
+type LokiConfig struct {
+	Url               *url.URL
+	BasicAuthUser     string
+	BasicAuthPassword string
+	TenantID          string
+	// ExternalLabels are labels added to every stream pushed to Loki.
+	ExternalLabels map[string]string
+}
+
+// NewLokiConfig builds a LokiConfig from the state history settings.
+func NewLokiConfig(cfg setting.UnifiedAlertingStateHistorySettings) (LokiConfig, error) {
+	if cfg.LokiRemoteURL == "" {
+		return LokiConfig{}, fmt.Errorf("setting 'loki_remote_url' is required when the state history backend is '%s'", BackendTypeLoki)
+	}
+	u, err := url.Parse(cfg.LokiRemoteURL)
+	if err != nil {
+		return LokiConfig{}, fmt.Errorf("failed to parse setting 'loki_remote_url': %w", err)
+	}
+	return LokiConfig{
+		Url:               u,
+		BasicAuthUser:     cfg.LokiBasicAuthUsername,
+		BasicAuthPassword: cfg.LokiBasicAuthPassword,
+		TenantID:          cfg.LokiTenantID,
+		ExternalLabels:    cfg.ExternalLabels,
+	}, nil
+}
+
+// httpLokiClient is a minimal client for the Loki push and query HTTP APIs.
+type httpLokiClient struct {
+	client http.Client
+	cfg    LokiConfig
+	log    log.Logger
+}
+
+func newLokiClient(cfg LokiConfig, logger log.Logger) *httpLokiClient {
+	return &httpLokiClient{
+		client: http.Client{
+			Timeout: defaultLokiClientTimeout,
+		},
+		cfg: cfg,
+		log: logger.New("protocol", "http"),
+	}
+}
+
+// ping checks that the Loki instance is reachable and accepts our credentials.
+func (c *httpLokiClient) ping(ctx context.Context) error {
+	uri := c.cfg.Url.JoinPath("/loki/api/v1/labels")
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, uri.String(), nil)
+	if err != nil {
+		return fmt.Errorf("error creating request: %w", err)
+	}
+	c.setAuthAndTenantHeaders(req)
+
+	res, err := c.client.Do(req)
+	if res != nil {
+		defer func() {
+			if err := res.Body.Close(); err != nil {
+				c.log.Warn("Failed to close response body", "err", err)
+			}
+		}()
+	}
+	if err != nil {
+		return fmt.Errorf("error sending request: %w", err)
+	}
+
+	if res.StatusCode < 200 || res.StatusCode >= 300 {
+		return fmt.Errorf("ping request to loki endpoint returned a non-200 status code: %d", res.StatusCode)
+	}
+	c.log.Debug("Ping request to Loki endpoint succeeded", "status", res.StatusCode)
+	return nil
+}
+
+// stream is a set of log lines sharing the same set of labels.
+type stream struct {
+	Stream map[string]string `json:"stream"`
+	Values []sample          `json:"values"`
+}
+
+// sample is a single log line with its timestamp.
+type sample struct {
+	T time.Time
+	V string
+}
+
+func (r *sample) MarshalJSON() ([]byte, error) {
+	return json.Marshal([2]string{
+		fmt.Sprintf("%d", r.T.UnixNano()), r.V,
+	})
+}
+
+func (r *sample) UnmarshalJSON(b []byte) error {
+	// A Loki stream sample is formatted like a list with two elements, [At, Val]
+	// At is a string wrapping a timestamp, in nanosecond unix epoch.
+	// Val is a string containing the log line.
+	var tuple [2]string
+	if err := json.Unmarshal(b, &tuple); err != nil {
+		return fmt.Errorf("failed to deserialize sample in Loki response: %w", err)
+	}
+	nano, err := strconv.ParseInt(tuple[0], 10, 64)
+	if err != nil {
+		return fmt.Errorf("timestamp in Loki sample not convertible to nanosecond epoch: %v", tuple[0])
+	}
+	r.T = time.Unix(0, nano)
+	r.V = tuple[1]
+	return nil
+}
+
+// push sends a batch of streams to the Loki push API.
+func (c *httpLokiClient) push(ctx context.Context, s []stream) error {
+	body := struct {
+		Streams []stream `json:"streams"`
+	}{Streams: s}
+	enc, err := json.Marshal(body)
+	if err != nil {
+		return fmt.Errorf("failed to serialize Loki payload: %w", err)
+	}
+
+	uri := c.cfg.Url.JoinPath("/loki/api/v1/push")
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, uri.String(), bytes.NewBuffer(enc))
+	if err != nil {
+		return fmt.Errorf("failed to create Loki request: %w", err)
+	}
+	c.setAuthAndTenantHeaders(req)
+	req.Header.Add("content-type", "application/json")
+
+	resp, err := c.client.Do(req)
+	if resp != nil {
+		defer func() {
+			if err := resp.Body.Close(); err != nil {
+				c.log.Warn("Failed to close response body", "err", err)
+			}
+		}()
+	}
+	if err != nil {
+		return fmt.Errorf("error sending request: %w", err)
+	}
+
+	if resp.StatusCode < 200 || resp.StatusCode >= 300 {
+		byt, _ := io.ReadAll(resp.Body)
+		if len(byt) > 0 {
+			c.log.Error("Error response from Loki", "response", string(byt), "status", resp.StatusCode)
+		} else {
+			c.log.Error("Error response from Loki with an empty body", "status", resp.StatusCode)
+		}
+		return fmt.Errorf("received a non-200 response from loki, status: %d", resp.StatusCode)
+	}
+	return nil
+}
+
+// queryRes is the response of the Loki query_range API for a log query.
+type queryRes struct {
+	Status string       `json:"status"`
+	Data   queryResData `json:"data"`
+}
+
+type queryResData struct {
+	Result []stream `json:"result"`
+}
+
+// query runs a log query against the Loki query_range API. Start and end are in nanoseconds since the epoch.
+func (c *httpLokiClient) query(ctx context.Context, logQuery string, start, end int64, limit int) (queryRes, error) {
+	// Run the pre-flight checks for the query.
+	if !strings.HasPrefix(logQuery, "{") || strings.HasPrefix(logQuery, "{}") {
+		return queryRes{}, fmt.Errorf("at least one selector is required to query")
+	}
+	if start > end {
+		return queryRes{}, fmt.Errorf("start time cannot be after end time")
+	}
+
+	queryURL := c.cfg.Url.JoinPath("/loki/api/v1/query_range")
+
+	values := url.Values{}
+	values.Set("query", logQuery)
+	values.Set("start", fmt.Sprintf("%d", start))
+	values.Set("end", fmt.Sprintf("%d", end))
+	if limit > 0 {
+		values.Set("limit", fmt.Sprintf("%d", limit))
+	}
+
+	queryURL.RawQuery = values.Encode()
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, queryURL.String(), nil)
+	if err != nil {
+		return queryRes{}, fmt.Errorf("error creating request: %w", err)
+	}
+	c.setAuthAndTenantHeaders(req)
+
+	res, err := c.client.Do(req)
+	if res != nil {
+		defer func() {
+			if err := res.Body.Close(); err != nil {
+				c.log.Warn("Failed to close response body", "err", err)
+			}
+		}()
+	}
+	if err != nil {
+		return queryRes{}, fmt.Errorf("error executing request: %w", err)
+	}
+
+	data, err := io.ReadAll(res.Body)
+	if err != nil {
+		return queryRes{}, fmt.Errorf("error reading request response: %w", err)
+	}
+
+	if res.StatusCode < 200 || res.StatusCode >= 300 {
+		if len(data) > 0 {
+			c.log.Error("Error response from Loki", "response", string(data), "status", res.StatusCode)
+		} else {
+			c.log.Error("Error response from Loki with an empty body", "status", res.StatusCode)
+		}
+		return queryRes{}, fmt.Errorf("received a non-200 response from loki, status: %d", res.StatusCode)
+	}
+
+	result := queryRes{}
+	if err := json.Unmarshal(data, &result); err != nil {
+		return queryRes{}, fmt.Errorf("error parsing request response: %w", err)
+	}
+
+	return result, nil
+}
+
+func (c *httpLokiClient) setAuthAndTenantHeaders(req *http.Request) {
+	if c.cfg.BasicAuthUser != "" || c.cfg.BasicAuthPassword != "" {
+		req.SetBasicAuth(c.cfg.BasicAuthUser, c.cfg.BasicAuthPassword)
+	}
+
+	if c.cfg.TenantID != "" {
+		req.Header.Add("X-Scope-OrgID", c.cfg.TenantID)
+	}
+}
+
+// selector is a single label matcher of a Loki stream selector.
+type selector struct {
+	Label string
+	Op    operator
+	Value string
+}
+
+type operator string
+
+const (
+	eq  operator = "="
+	neq operator = "!="
+	eqR operator = "=~"
+	nqR operator = "!~"
+)
+
+// newSelector creates a new selector, validating the operator.
+func newSelector(label, op, value string) (selector, error) {
+	if !isValidOperator(op) {
+		return selector{}, fmt.Errorf("'%s' is not a valid query operator", op)
+	}
+	return selector{Label: label, Op: operator(op), Value: value}, nil
+}
+
+func isValidOperator(op string) bool {
+	switch operator(op) {
+	case eq, neq, eqR, nqR:
+		return true
+	}
+	return false
+}
+
+// selectorString renders the selectors as a LogQL stream selector, e.g. {a="b",c!="d"}.
+func selectorString(selectors []selector) string {
+	if len(selectors) == 0 {
+		return "{}"
+	}
+	query := make([]string, 0, len(selectors))
+	for _, s := range selectors {
+		query = append(query, fmt.Sprintf("%s%s%q", s.Label, s.Op, s.Value))
+	}
+	return "{" + strings.Join(query, ",") + "}"
+}
@@ -0,0 +1,157 @@
+package historian
+
+import (
+	"context"
+	"encoding/json"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/grafana/grafana/pkg/infra/log"
+	"github.com/grafana/grafana/pkg/setting"
+)
+
+func TestLokiConfig(t *testing.T) {
+	t.Run("requires a remote URL", func(t *testing.T) {
+		_, err := NewLokiConfig(setting.UnifiedAlertingStateHistorySettings{Backend: "loki"})
+
+		require.Error(t, err)
+	})
+
+	t.Run("copies credentials and tenant", func(t *testing.T) {
+		cfg, err := NewLokiConfig(setting.UnifiedAlertingStateHistorySettings{
+			LokiRemoteURL:         "http://localhost:3100",
+			LokiTenantID:          "tenant",
+			LokiBasicAuthUsername: "user",
+			LokiBasicAuthPassword: "pass",
+			ExternalLabels:        map[string]string{"cluster": "prod"},
+		})
+
+		require.NoError(t, err)
+		require.Equal(t, "http://localhost:3100", cfg.Url.String())
+		require.Equal(t, "tenant", cfg.TenantID)
+		require.Equal(t, "user", cfg.BasicAuthUser)
+		require.Equal(t, "pass", cfg.BasicAuthPassword)
+		require.Equal(t, map[string]string{"cluster": "prod"}, cfg.ExternalLabels)
+	})
+}
+
+func TestLokiHTTPClient(t *testing.T) {
+	t.Run("push sends streams with auth and tenant headers", func(t *testing.T) {
+		var got struct {
+			Streams []struct {
+				Stream map[string]string `json:"stream"`
+				Values [][2]string       `json:"values"`
+			} `json:"streams"`
+		}
+		var req *http.Request
+		srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			req = r
+			b, err := io.ReadAll(r.Body)
+			require.NoError(t, err)
+			require.NoError(t, json.Unmarshal(b, &got))
+			w.WriteHeader(http.StatusNoContent)
+		}))
+		t.Cleanup(srv.Close)
+
+		client := newLokiClient(testLokiConfig(t, srv.URL), log.NewNopLogger())
+		ts := time.Unix(0, 1234)
+		err := client.push(context.Background(), []stream{{
+			Stream: map[string]string{"a": "b"},
+			Values: []sample{{T: ts, V: "line"}},
+		}})
+
+		require.NoError(t, err)
+		require.Equal(t, "/loki/api/v1/push", req.URL.Path)
+		require.Equal(t, "tenant", req.Header.Get("X-Scope-OrgID"))
+		user, pass, ok := req.BasicAuth()
+		require.True(t, ok)
+		require.Equal(t, "user", user)
+		require.Equal(t, "pass", pass)
+		require.Len(t, got.Streams, 1)
+		require.Equal(t, map[string]string{"a": "b"}, got.Streams[0].Stream)
+		require.Equal(t, [][2]string{{"1234", "line"}}, got.Streams[0].Values)
+	})
+
+	t.Run("push fails on non-2xx responses", func(t *testing.T) {
+		srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			w.WriteHeader(http.StatusBadRequest)
+			_, _ = w.Write([]byte("bad"))
+		}))
+		t.Cleanup(srv.Close)
+
+		client := newLokiClient(testLokiConfig(t, srv.URL), log.NewNopLogger())
+		err := client.push(context.Background(), []stream{})
+
+		require.Error(t, err)
+	})
+
+	t.Run("query parses the query_range response", func(t *testing.T) {
+		var req *http.Request
+		srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			req = r
+			_, _ = w.Write([]byte(`{"status":"success","data":{"resultType":"streams","result":[{"stream":{"ruleUID":"abc"},"values":[["1000","{\"current\":\"Alerting\"}"]]}]}}`))
+		}))
+		t.Cleanup(srv.Close)
+
+		client := newLokiClient(testLokiConfig(t, srv.URL), log.NewNopLogger())
+		res, err := client.query(context.Background(), `{ruleUID="abc"}`, 1, 2, 5)
+
+		require.NoError(t, err)
+		require.Equal(t, "/loki/api/v1/query_range", req.URL.Path)
+		require.Equal(t, `{ruleUID="abc"}`, req.URL.Query().Get("query"))
+		require.Equal(t, "1", req.URL.Query().Get("start"))
+		require.Equal(t, "2", req.URL.Query().Get("end"))
+		require.Equal(t, "5", req.URL.Query().Get("limit"))
+		require.Len(t, res.Data.Result, 1)
+		require.Equal(t, time.Unix(0, 1000), res.Data.Result[0].Values[0].T)
+		require.Equal(t, `{"current":"Alerting"}`, res.Data.Result[0].Values[0].V)
+	})
+
+	t.Run("query rejects invalid ranges", func(t *testing.T) {
+		client := newLokiClient(testLokiConfig(t, "http://localhost"), log.NewNopLogger())
+
+		_, err := client.query(context.Background(), `{a="b"}`, 2, 1, 0)
+
+		require.Error(t, err)
+	})
+
+	t.Run("ping checks the labels endpoint", func(t *testing.T) {
+		var path string
+		srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			path = r.URL.Path
+		}))
+		t.Cleanup(srv.Close)
+
+		client := newLokiClient(testLokiConfig(t, srv.URL), log.NewNopLogger())
+
+		require.NoError(t, client.ping(context.Background()))
+		require.Equal(t, "/loki/api/v1/labels", path)
+	})
+}
+
+func TestNewSelector(t *testing.T) {
+	s, err := newSelector("label", "=~", "value")
+	require.NoError(t, err)
+	require.Equal(t, selector{Label: "label", Op: eqR, Value: "value"}, s)
+
+	_, err = newSelector("label", "==", "value")
+	require.Error(t, err)
+}
+
+func testLokiConfig(t *testing.T, rawURL string) LokiConfig {
+	t.Helper()
+	u, err := url.Parse(rawURL)
+	require.NoError(t, err)
+	return LokiConfig{
+		Url:               u,
+		BasicAuthUser:     "user",
+		BasicAuthPassword: "pass",
+		TenantID:          "tenant",
+	}
+}
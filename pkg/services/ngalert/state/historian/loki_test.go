@@ -0,0 +1,332 @@
+package historian
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"math"
+	"testing"
+	"time"
+
+	"github.com/grafana/grafana-plugin-sdk-go/data"
+	"github.com/stretchr/testify/require"
+
+	"github.com/grafana/grafana/pkg/infra/log"
+	"github.com/grafana/grafana/pkg/services/ngalert/eval"
+	ngmodels "github.com/grafana/grafana/pkg/services/ngalert/models"
+	"github.com/grafana/grafana/pkg/services/ngalert/state"
+)
+
+func TestRemoteLokiBackend(t *testing.T) {
+	t.Run("statesToStreams", func(t *testing.T) {
+		t.Run("skips non-transitory states", func(t *testing.T) {
+			rule := createTestRule()
+			l := log.NewNopLogger()
+			states := singleFromNormal(&state.State{State: eval.Normal})
+
+			res := statesToStreams(rule, states, nil, l)
+
+			require.Empty(t, res)
+		})
+
+		t.Run("maps evaluation errors", func(t *testing.T) {
+			rule := createTestRule()
+			l := log.NewNopLogger()
+			states := singleFromNormal(&state.State{State: eval.Error, Error: fmt.Errorf("oh no")})
+
+			res := statesToStreams(rule, states, nil, l)
+
+			entry := requireSingleEntry(t, res)
+			require.Contains(t, entry.Error, "oh no")
+		})
+
+		t.Run("maps NoData results", func(t *testing.T) {
+			rule := createTestRule()
+			l := log.NewNopLogger()
+			states := singleFromNormal(&state.State{State: eval.NoData})
+
+			res := statesToStreams(rule, states, nil, l)
+
+			_ = requireSingleEntry(t, res)
+		})
+
+		t.Run("produces expected stream identifier", func(t *testing.T) {
+			rule := createTestRule()
+			l := log.NewNopLogger()
+			states := singleFromNormal(&state.State{
+				State:  eval.Alerting,
+				Labels: data.Labels{"a": "b"},
+			})
+
+			res := statesToStreams(rule, states, nil, l)
+
+			exp := map[string]string{
+				StateHistoryLabelKey: StateHistoryLabelValue,
+				OrgIDLabel:           fmt.Sprint(rule.OrgID),
+				RuleUIDLabel:         rule.UID,
+				GroupLabel:           rule.RuleGroup,
+				FolderUIDLabel:       rule.NamespaceUID,
+			}
+			require.Len(t, res, 1)
+			require.Equal(t, exp, res[0].Stream)
+		})
+
+		t.Run("includes external labels", func(t *testing.T) {
+			rule := createTestRule()
+			l := log.NewNopLogger()
+			states := singleFromNormal(&state.State{State: eval.Alerting})
+
+			res := statesToStreams(rule, states, map[string]string{"externalLabelKey": "externalLabelValue"}, l)
+
+			require.Len(t, res, 1)
+			require.Contains(t, res[0].Stream, "externalLabelKey")
+		})
+
+		t.Run("stores instance labels without private labels in the line", func(t *testing.T) {
+			rule := createTestRule()
+			l := log.NewNopLogger()
+			states := singleFromNormal(&state.State{
+				State:  eval.Alerting,
+				Labels: data.Labels{"a": "b", "__private__": "c"},
+			})
+
+			res := statesToStreams(rule, states, nil, l)
+
+			entry := requireSingleEntry(t, res)
+			require.Equal(t, map[string]string{"a": "b"}, entry.Labels)
+		})
+
+		t.Run("serializes values when regular", func(t *testing.T) {
+			rule := createTestRule()
+			l := log.NewNopLogger()
+			states := singleFromNormal(&state.State{
+				State:  eval.Alerting,
+				Values: map[string]float64{"A": 2.0, "B": 5.5},
+			})
+
+			res := statesToStreams(rule, states, nil, l)
+
+			entry := requireSingleEntry(t, res)
+			require.NotNil(t, entry.Values)
+			require.NotNil(t, entry.Values.Get("A"))
+			require.NotNil(t, entry.Values.Get("B"))
+			require.InDelta(t, 2.0, entry.Values.Get("A").MustFloat64(), 1e-4)
+			require.InDelta(t, 5.5, entry.Values.Get("B").MustFloat64(), 1e-4)
+		})
+
+		t.Run("serializes special float values as strings", func(t *testing.T) {
+			rule := createTestRule()
+			l := log.NewNopLogger()
+			states := singleFromNormal(&state.State{
+				State:  eval.Alerting,
+				Values: map[string]float64{"A": math.NaN(), "B": math.Inf(1)},
+			})
+
+			res := statesToStreams(rule, states, nil, l)
+
+			entry := requireSingleEntry(t, res)
+			require.Equal(t, "NaN", entry.Values.Get("A").MustString())
+			require.Equal(t, "+Inf", entry.Values.Get("B").MustString())
+		})
+	})
+
+	t.Run("buildSelectors", func(t *testing.T) {
+		t.Run("always selects the org and state history streams", func(t *testing.T) {
+			selectors, err := buildSelectors(ngmodels.HistoryQuery{OrgID: 123})
+
+			require.NoError(t, err)
+			require.Equal(t, `{orgID="123",from="state-history"}`, selectorString(selectors))
+		})
+
+		t.Run("selects the rule when given", func(t *testing.T) {
+			selectors, err := buildSelectors(ngmodels.HistoryQuery{OrgID: 123, RuleUID: "rule-uid"})
+
+			require.NoError(t, err)
+			require.Equal(t, `{orgID="123",from="state-history",ruleUID="rule-uid"}`, selectorString(selectors))
+		})
+
+		t.Run("restricts the streams to the given rules", func(t *testing.T) {
+			selectors, err := buildSelectors(ngmodels.HistoryQuery{OrgID: 123, RuleUIDs: []string{"a.b", "c"}})
+
+			require.NoError(t, err)
+			require.Equal(t, `{orgID="123",from="state-history",ruleUID=~"a\\.b|c"}`, selectorString(selectors))
+		})
+	})
+
+	t.Run("buildLogQuery", func(t *testing.T) {
+		selectors := []selector{{Label: OrgIDLabel, Op: eq, Value: "1"}}
+
+		t.Run("only selects streams without label filters", func(t *testing.T) {
+			require.Equal(t, `{orgID="1"}`, buildLogQuery(selectors, nil))
+		})
+
+		t.Run("filters by instance labels in Loki", func(t *testing.T) {
+			query := buildLogQuery(selectors, map[string]string{"severity": "critical", "team.name": "infra"})
+
+			require.Equal(t, `{orgID="1"} | json | labels_severity="critical" | labels_team_name="infra"`, query)
+		})
+	})
+
+	t.Run("merge", func(t *testing.T) {
+		now := time.Now()
+		res := queryRes{
+			Data: queryResData{
+				Result: []stream{
+					{
+						Stream: map[string]string{RuleUIDLabel: "a"},
+						Values: []sample{
+							{T: now.Add(2 * time.Second), V: `{"current":"Alerting","labels":{"x":"1"}}`},
+						},
+					},
+					{
+						Stream: map[string]string{RuleUIDLabel: "b"},
+						Values: []sample{
+							{T: now, V: `{"current":"Pending","labels":{"x":"2"}}`},
+							{T: now.Add(time.Second), V: `{"current":"Normal","labels":{"x":"1"}}`},
+						},
+					},
+				},
+			},
+		}
+
+		t.Run("sorts the rows of all streams by time", func(t *testing.T) {
+			frame, err := merge(res, nil, 0)
+
+			require.NoError(t, err)
+			require.Equal(t, 3, frame.Rows())
+			for i := 1; i < frame.Rows(); i++ {
+				prev := frame.At(0, i-1).(time.Time)
+				cur := frame.At(0, i).(time.Time)
+				require.False(t, cur.Before(prev))
+			}
+		})
+
+		t.Run("filters by instance labels", func(t *testing.T) {
+			frame, err := merge(res, map[string]string{"x": "1"}, 0)
+
+			require.NoError(t, err)
+			require.Equal(t, 2, frame.Rows())
+			require.JSONEq(t, `{"x":"1"}`, string(frame.At(2, 0).(json.RawMessage)))
+		})
+
+		t.Run("keeps the latest rows up to the limit", func(t *testing.T) {
+			frame, err := merge(res, nil, 2)
+
+			require.NoError(t, err)
+			require.Equal(t, 2, frame.Rows())
+			require.Equal(t, now.Add(time.Second), frame.At(0, 0).(time.Time))
+			require.Equal(t, now.Add(2*time.Second), frame.At(0, 1).(time.Time))
+		})
+
+		t.Run("fails on malformed lines", func(t *testing.T) {
+			bad := queryRes{Data: queryResData{Result: []stream{{Values: []sample{{T: now, V: "not json"}}}}}}
+
+			_, err := merge(bad, nil, 0)
+
+			require.Error(t, err)
+		})
+	})
+
+	t.Run("QueryStates", func(t *testing.T) {
+		t.Run("passes the query to the client", func(t *testing.T) {
+			client := &fakeLokiClient{}
+			backend := &RemoteLokiBackend{client: client, log: log.NewNopLogger()}
+			from := time.Unix(100, 0)
+			to := time.Unix(200, 0)
+
+			frame, err := backend.QueryStates(context.Background(), ngmodels.HistoryQuery{OrgID: 1, RuleUID: "abc", From: from, To: to, Limit: 10})
+
+			require.NoError(t, err)
+			require.Equal(t, 0, frame.Rows())
+			require.Equal(t, from.UnixNano(), client.lastStart)
+			require.Equal(t, to.UnixNano(), client.lastEnd)
+			require.Equal(t, 10, client.lastLimit)
+			require.Equal(t, `{orgID="1",from="state-history",ruleUID="abc"}`, client.lastQuery)
+		})
+
+		t.Run("splits queries for many rules", func(t *testing.T) {
+			client := &fakeLokiClient{}
+			backend := &RemoteLokiBackend{client: client, log: log.NewNopLogger()}
+			uids := make([]string, 0, 2*maxRuleUIDsPerQuery+1)
+			for i := 0; i < cap(uids); i++ {
+				uids = append(uids, fmt.Sprintf("uid-%d", i))
+			}
+
+			_, err := backend.QueryStates(context.Background(), ngmodels.HistoryQuery{OrgID: 1, RuleUIDs: uids, From: time.Unix(100, 0), To: time.Unix(200, 0)})
+
+			require.NoError(t, err)
+			require.Len(t, client.queries, 3)
+			require.Equal(t, `{orgID="1",from="state-history",ruleUID=~"uid-200"}`, client.queries[2])
+		})
+
+		t.Run("returns client errors", func(t *testing.T) {
+			client := &fakeLokiClient{err: errors.New("boom")}
+			backend := &RemoteLokiBackend{client: client, log: log.NewNopLogger()}
+
+			_, err := backend.QueryStates(context.Background(), ngmodels.HistoryQuery{OrgID: 1})
+
+			require.ErrorContains(t, err, "boom")
+		})
+	})
+}
+
+type fakeLokiClient struct {
+	queries   []string
+	lastQuery string
+	lastStart int64
+	lastEnd   int64
+	lastLimit int
+	err       error
+}
+
+func (c *fakeLokiClient) ping(context.Context) error {
+	return c.err
+}
+
+func (c *fakeLokiClient) push(context.Context, []stream) error {
+	return c.err
+}
+
+func (c *fakeLokiClient) query(_ context.Context, logQuery string, start, end int64, limit int) (queryRes, error) {
+	c.queries = append(c.queries, logQuery)
+	c.lastQuery = logQuery
+	c.lastStart = start
+	c.lastEnd = end
+	c.lastLimit = limit
+	return queryRes{}, c.err
+}
+
+func singleFromNormal(st *state.State) []state.StateTransition {
+	return []state.StateTransition{
+		{
+			PreviousState: eval.Normal,
+			State:         st,
+		},
+	}
+}
+
+func createTestRule() *ngmodels.AlertRule {
+	return &ngmodels.AlertRule{
+		OrgID:        1,
+		UID:          "rule-uid",
+		Title:        "rule title",
+		NamespaceUID: "my-folder",
+		RuleGroup:    "my-group",
+	}
+}
+
+func requireSingleEntry(t *testing.T, res []stream) lokiEntry {
+	require.Len(t, res, 1)
+	require.Len(t, res[0].Values, 1)
+	return requireEntry(t, res[0].Values[0])
+}
+
+func requireEntry(t *testing.T, row sample) lokiEntry {
+	t.Helper()
+
+	var entry lokiEntry
+	err := json.Unmarshal([]byte(row.V), &entry)
+	require.NoError(t, err)
+	return entry
+}
@@ -0,0 +1,25 @@
+package historian
+
+import (
+	"context"
+
+	"github.com/grafana/grafana-plugin-sdk-go/data"
+
+	ngmodels "github.com/grafana/grafana/pkg/services/ngalert/models"
+	"github.com/grafana/grafana/pkg/services/ngalert/state"
+)
+
+// NoOpHistorian is a state.Historian that does nothing with the resulting data, to be used in contexts where history is not needed.
+type NoOpHistorian struct{}
+
+func NewNopHistorian() *NoOpHistorian {
+	return &NoOpHistorian{}
+}
+
+func (f *NoOpHistorian) RecordStatesAsync(ctx context.Context, _ *ngmodels.AlertRule, _ []state.StateTransition) {
+}
+
+// QueryStates always returns an empty frame.
+func (f *NoOpHistorian) QueryStates(ctx context.Context, _ ngmodels.HistoryQuery) (*data.Frame, error) {
+	return NewStatesFrame(0), nil
+}
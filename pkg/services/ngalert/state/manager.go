@@ -94,6 +94,10 @@ func (st *Manager) Warm(ctx context.Context, rulesReader RuleReader) {
 
 		ruleByUID := make(map[string]*ngModels.AlertRule, len(ruleCmd.Result))
 		for _, rule := range ruleCmd.Result {
+			// Paused rules are not evaluated, so their previous state must not be restored.
+			if rule.IsPaused {
+				continue
+			}
 			ruleByUID[rule.UID] = rule
 		}
 
@@ -131,6 +135,7 @@ func (st *Manager) Warm(ctx context.Context, rulesReader RuleReader) {
 				OrgID:                entry.RuleOrgID,
 				CacheID:              cacheID,
 				Labels:               lbs,
+				ResultLabels:         resultLabelsFromLabels(ruleForEntry, lbs),
 				State:                translateInstanceState(entry.CurrentState),
 				StateReason:          entry.CurrentReason,
 				LastEvaluationString: "",
@@ -54,10 +54,18 @@ type State struct {
 	// If a label is templated then the template is first evaluated to derive the final label.
 	Labels data.Labels
 
+	// ResultLabels contain the labels of the evaluation result the state was created from. It is not
+	// persisted, states restored from the database rebuild it from their labels, see resultLabelsFromLabels.
+	ResultLabels data.Labels
+
 	// Values contains the values of any instant vectors, reduce and math expressions, or classic
 	// conditions.
 	Values map[string]float64
 
+	// KeptFiringSince is the time of the first Normal evaluation of an Alerting state that is kept firing
+	// because of the keep firing for duration of the alert rule. It is zero if the state is not kept firing.
+	KeptFiringSince time.Time
+
 	StartsAt             time.Time
 	EndsAt               time.Time
 	LastSentAt           time.Time
@@ -89,6 +97,7 @@ func (a *State) SetAlerting(reason string, startsAt, endsAt time.Time) {
 	a.StartsAt = startsAt
 	a.EndsAt = endsAt
 	a.Error = nil
+	a.KeptFiringSince = time.Time{}
 }
 
 // SetPending the state to Pending. It changes both the start and end time.
@@ -98,6 +107,7 @@ func (a *State) SetPending(reason string, startsAt, endsAt time.Time) {
 	a.StartsAt = startsAt
 	a.EndsAt = endsAt
 	a.Error = nil
+	a.KeptFiringSince = time.Time{}
 }
 
 // SetNoData sets the state to NoData. It changes both the start and end time.
@@ -107,6 +117,7 @@ func (a *State) SetNoData(reason string, startsAt, endsAt time.Time) {
 	a.StartsAt = startsAt
 	a.EndsAt = endsAt
 	a.Error = nil
+	a.KeptFiringSince = time.Time{}
 }
 
 // SetError sets the state to Error. It changes both the start and end time.
@@ -116,6 +127,7 @@ func (a *State) SetError(err error, startsAt, endsAt time.Time) {
 	a.StartsAt = startsAt
 	a.EndsAt = endsAt
 	a.Error = err
+	a.KeptFiringSince = time.Time{}
 }
 
 // SetNormal sets the state to Normal. It changes both the start and end time.
@@ -125,6 +137,7 @@ func (a *State) SetNormal(reason string, startsAt, endsAt time.Time) {
 	a.StartsAt = startsAt
 	a.EndsAt = endsAt
 	a.Error = nil
+	a.KeptFiringSince = time.Time{}
 }
 
 // Resolve sets the State to Normal. It updates the StateReason, the end time, and sets Resolved to true.
@@ -179,10 +192,24 @@ func NewEvaluationValues(m map[string]eval.NumberValueCapture) map[string]*float
 	return result
 }
 
-func resultNormal(state *State, _ *models.AlertRule, result eval.Result, logger log.Logger) {
-	if state.State == eval.Normal {
+func resultNormal(state *State, rule *models.AlertRule, result eval.Result, logger log.Logger) {
+	switch state.State {
+	case eval.Normal:
 		logger.Debug("Keeping state", "state", state.State)
-	} else {
+	case eval.Alerting:
+		if rule.KeepFiringFor > 0 {
+			if state.KeptFiringSince.IsZero() {
+				state.KeptFiringSince = result.EvaluatedAt
+			}
+			// If the alert rule has a KeepFiringFor duration then the state should keep firing until it has been observed
+			if result.EvaluatedAt.Sub(state.KeptFiringSince) < rule.KeepFiringFor {
+				logger.Debug("Keeping state", "state", state.State, "kept_firing_since", state.KeptFiringSince)
+				state.Maintain(rule.IntervalSeconds, result.EvaluatedAt)
+				return
+			}
+		}
+		fallthrough
+	default:
 		logger.Debug("Changing state", "previous_state", state.State, "next_state", eval.Normal)
 		// Normal states have the same start and end timestamps
 		state.SetNormal("", result.EvaluatedAt, result.EvaluatedAt)
@@ -194,6 +221,7 @@ func resultAlerting(state *State, rule *models.AlertRule, result eval.Result, lo
 	case eval.Alerting:
 		logger.Debug("Keeping state", "state", state.State)
 		state.Maintain(rule.IntervalSeconds, result.EvaluatedAt)
+		state.KeptFiringSince = time.Time{}
 	case eval.Pending:
 		// If the previous state is Pending then check if the For duration has been observed
 		if result.EvaluatedAt.Sub(state.StartsAt) >= rule.For {
@@ -256,6 +284,7 @@ func resultError(state *State, rule *models.AlertRule, result eval.Result, logge
 
 func resultNoData(state *State, rule *models.AlertRule, result eval.Result, _ log.Logger) {
 	state.Error = result.Error
+	state.KeptFiringSince = time.Time{}
 
 	if state.StartsAt.IsZero() {
 		state.StartsAt = result.EvaluatedAt
@@ -14,6 +14,7 @@ import (
 	"github.com/stretchr/testify/require"
 	ptr "github.com/xorcare/pointer"
 
+	"github.com/grafana/grafana/pkg/infra/log"
 	"github.com/grafana/grafana/pkg/services/ngalert/eval"
 	ngmodels "github.com/grafana/grafana/pkg/services/ngalert/models"
 	"github.com/grafana/grafana/pkg/services/screenshot"
@@ -246,6 +247,55 @@ func TestSetError(t *testing.T) {
 	}
 }
 
+func TestResultNormalKeepFiringFor(t *testing.T) {
+	mock := clock.NewMock()
+	logger := log.NewNopLogger()
+	rule := &ngmodels.AlertRule{IntervalSeconds: 10, KeepFiringFor: 30 * time.Second}
+
+	t.Run("alerting state keeps firing until keep firing for is observed", func(t *testing.T) {
+		s := State{State: eval.Alerting, StartsAt: mock.Now()}
+
+		resultNormal(&s, rule, eval.Result{EvaluatedAt: mock.Now().Add(10 * time.Second)}, logger)
+		require.Equal(t, eval.Alerting, s.State)
+		require.Equal(t, mock.Now().Add(10*time.Second), s.KeptFiringSince)
+		require.Equal(t, mock.Now(), s.StartsAt)
+
+		resultNormal(&s, rule, eval.Result{EvaluatedAt: mock.Now().Add(30 * time.Second)}, logger)
+		require.Equal(t, eval.Alerting, s.State)
+		require.Equal(t, mock.Now().Add(10*time.Second), s.KeptFiringSince)
+
+		resultNormal(&s, rule, eval.Result{EvaluatedAt: mock.Now().Add(40 * time.Second)}, logger)
+		require.Equal(t, eval.Normal, s.State)
+		require.True(t, s.KeptFiringSince.IsZero())
+	})
+
+	t.Run("alerting result resets keep firing for", func(t *testing.T) {
+		s := State{State: eval.Alerting, StartsAt: mock.Now()}
+
+		resultNormal(&s, rule, eval.Result{EvaluatedAt: mock.Now().Add(10 * time.Second)}, logger)
+		resultAlerting(&s, rule, eval.Result{EvaluatedAt: mock.Now().Add(20 * time.Second)}, logger)
+		require.True(t, s.KeptFiringSince.IsZero())
+
+		resultNormal(&s, rule, eval.Result{EvaluatedAt: mock.Now().Add(40 * time.Second)}, logger)
+		require.Equal(t, eval.Alerting, s.State)
+		require.Equal(t, mock.Now().Add(40*time.Second), s.KeptFiringSince)
+	})
+
+	t.Run("pending state is not kept firing", func(t *testing.T) {
+		s := State{State: eval.Pending, StartsAt: mock.Now()}
+
+		resultNormal(&s, rule, eval.Result{EvaluatedAt: mock.Now().Add(10 * time.Second)}, logger)
+		require.Equal(t, eval.Normal, s.State)
+	})
+
+	t.Run("alerting state resolves immediately without keep firing for", func(t *testing.T) {
+		s := State{State: eval.Alerting, StartsAt: mock.Now()}
+
+		resultNormal(&s, &ngmodels.AlertRule{IntervalSeconds: 10}, eval.Result{EvaluatedAt: mock.Now().Add(10 * time.Second)}, logger)
+		require.Equal(t, eval.Normal, s.State)
+	})
+}
+
 func TestMaintain(t *testing.T) {
 	mock := clock.NewMock()
 	now := mock.Now()
@@ -147,8 +147,11 @@ func (st DBstore) InsertAlertRules(ctx context.Context, rules []ngmodels.AlertRu
 				NoDataState:      r.NoDataState,
 				ExecErrState:     r.ExecErrState,
 				For:              r.For,
+				KeepFiringFor:    r.KeepFiringFor,
+				Record:           r.Record,
 				Annotations:      r.Annotations,
 				Labels:           r.Labels,
+				IsPaused:         r.IsPaused,
 			})
 		}
 		if len(newRules) > 0 {
@@ -215,8 +218,11 @@ func (st DBstore) UpdateAlertRules(ctx context.Context, rules []ngmodels.UpdateR
 				NoDataState:      r.New.NoDataState,
 				ExecErrState:     r.New.ExecErrState,
 				For:              r.New.For,
+				KeepFiringFor:    r.New.KeepFiringFor,
+				Record:           r.New.Record,
 				Annotations:      r.New.Annotations,
 				Labels:           r.New.Labels,
+				IsPaused:         r.New.IsPaused,
 			})
 		}
 		if len(ruleVersions) > 0 {
@@ -530,5 +536,15 @@ func (st DBstore) validateAlertRule(alertRule ngmodels.AlertRule) error {
 	if alertRule.For < 0 {
 		return fmt.Errorf("%w: field `for` cannot be negative", ngmodels.ErrAlertRuleFailedValidation)
 	}
+
+	if alertRule.KeepFiringFor < 0 {
+		return fmt.Errorf("%w: field `keep_firing_for` cannot be negative", ngmodels.ErrAlertRuleFailedValidation)
+	}
+
+	if alertRule.Record != nil {
+		if err := alertRule.Record.Validate(alertRule.Data); err != nil {
+			return err
+		}
+	}
 	return nil
 }
@@ -0,0 +1,130 @@
+package store
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/grafana/grafana/pkg/infra/db"
+	"github.com/grafana/grafana/pkg/services/ngalert/models"
+)
+
+type NotificationDeadLetterStore interface {
+	// SaveNotificationDeadLetter saves the dead letter or returns an error. A new dead letter replaces the
+	// existing dead letter of the same alert group, receiver and integration, so a notification that keeps
+	// failing has a single dead letter with its latest alerts and error.
+	SaveNotificationDeadLetter(ctx context.Context, deadLetter *models.NotificationDeadLetter) error
+
+	// GetNotificationDeadLetters returns the dead letters that match the query, from the most recent.
+	GetNotificationDeadLetters(ctx context.Context, query *models.NotificationDeadLetterQuery) ([]models.NotificationDeadLetter, error)
+
+	// GetNotificationDeadLetter returns the dead letter of the organization with the ID. It returns
+	// models.ErrNotificationDeadLetterNotFound if the dead letter does not exist.
+	GetNotificationDeadLetter(ctx context.Context, orgID, id int64) (*models.NotificationDeadLetter, error)
+
+	// DeleteNotificationDeadLetter deletes the dead letter of the organization with the ID. It returns
+	// models.ErrNotificationDeadLetterNotFound if the dead letter does not exist.
+	DeleteNotificationDeadLetter(ctx context.Context, orgID, id int64) error
+
+	// DeleteNotificationDeadLettersBefore deletes the dead letters of the organization created before t.
+	// It returns the number of deleted dead letters or an error.
+	DeleteNotificationDeadLettersBefore(ctx context.Context, orgID int64, t time.Time) (int64, error)
+}
+
+func (st DBstore) SaveNotificationDeadLetter(ctx context.Context, deadLetter *models.NotificationDeadLetter) error {
+	return st.SQLStore.WithDbSession(ctx, func(sess *db.Session) error {
+		if deadLetter.CreatedAt.IsZero() {
+			deadLetter.CreatedAt = TimeNow().UTC()
+		}
+		if deadLetter.ID == 0 {
+			existing := models.NotificationDeadLetter{}
+			has, err := sess.Where("org_id = ? AND receiver = ? AND integration = ? AND integration_idx = ? AND group_key = ?",
+				deadLetter.OrgID, deadLetter.Receiver, deadLetter.Integration, deadLetter.IntegrationIndex, deadLetter.GroupKey).Get(&existing)
+			if err != nil {
+				return fmt.Errorf("failed to get notification dead letter: %w", err)
+			}
+			if has {
+				deadLetter.ID = existing.ID
+			}
+		}
+		if deadLetter.ID == 0 {
+			if _, err := sess.Insert(deadLetter); err != nil {
+				return fmt.Errorf("failed to insert notification dead letter: %w", err)
+			}
+			return nil
+		}
+		if _, err := sess.ID(deadLetter.ID).AllCols().Update(deadLetter); err != nil {
+			return fmt.Errorf("failed to update notification dead letter: %w", err)
+		}
+		return nil
+	})
+}
+
+func (st DBstore) GetNotificationDeadLetters(ctx context.Context, query *models.NotificationDeadLetterQuery) ([]models.NotificationDeadLetter, error) {
+	deadLetters := make([]models.NotificationDeadLetter, 0)
+	err := st.SQLStore.WithDbSession(ctx, func(sess *db.Session) error {
+		q := sess.Where("org_id = ?", query.OrgID)
+		if query.Receiver != "" {
+			q = q.And("receiver = ?", query.Receiver)
+		}
+
+		limit := query.Limit
+		if limit <= 0 {
+			limit = defaultNotificationHistoryLimit
+		}
+		if limit > maxNotificationHistoryLimit {
+			limit = maxNotificationHistoryLimit
+		}
+		return q.Desc("created_at", "id").Limit(limit).Find(&deadLetters)
+	})
+	if err != nil {
+		return nil, fmt.Errorf("failed to get notification dead letters: %w", err)
+	}
+	return deadLetters, nil
+}
+
+func (st DBstore) GetNotificationDeadLetter(ctx context.Context, orgID, id int64) (*models.NotificationDeadLetter, error) {
+	var deadLetter models.NotificationDeadLetter
+	err := st.SQLStore.WithDbSession(ctx, func(sess *db.Session) error {
+		exists, err := sess.Where("org_id = ? AND id = ?", orgID, id).Get(&deadLetter)
+		if err != nil {
+			return fmt.Errorf("failed to get notification dead letter: %w", err)
+		}
+		if !exists {
+			return models.ErrNotificationDeadLetterNotFound
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+	return &deadLetter, nil
+}
+
+func (st DBstore) DeleteNotificationDeadLetter(ctx context.Context, orgID, id int64) error {
+	return st.SQLStore.WithDbSession(ctx, func(sess *db.Session) error {
+		rows, err := sess.Where("org_id = ? AND id = ?", orgID, id).Delete(&models.NotificationDeadLetter{})
+		if err != nil {
+			return fmt.Errorf("failed to delete notification dead letter: %w", err)
+		}
+		if rows == 0 {
+			return models.ErrNotificationDeadLetterNotFound
+		}
+		return nil
+	})
+}
+
+func (st DBstore) DeleteNotificationDeadLettersBefore(ctx context.Context, orgID int64, t time.Time) (int64, error) {
+	var n int64
+	if err := st.SQLStore.WithDbSession(ctx, func(sess *db.Session) error {
+		rows, err := sess.Where("org_id = ? AND created_at < ?", orgID, t.UTC()).Delete(&models.NotificationDeadLetter{})
+		if err != nil {
+			return fmt.Errorf("failed to delete notification dead letters: %w", err)
+		}
+		n = rows
+		return nil
+	}); err != nil {
+		return -1, err
+	}
+	return n, nil
+}
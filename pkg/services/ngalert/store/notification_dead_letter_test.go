@@ -0,0 +1,120 @@
+package store_test
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/grafana/grafana/pkg/services/ngalert/models"
+	"github.com/grafana/grafana/pkg/services/ngalert/tests"
+)
+
+func TestIntegrationNotificationDeadLetters(t *testing.T) {
+	if testing.Short() {
+		t.Skip("skipping integration test")
+	}
+
+	ctx := context.Background()
+	_, dbstore := tests.SetupTestEnv(t, baseIntervalSeconds)
+
+	// our database schema uses second precision for timestamps
+	now := time.Now().UTC().Truncate(time.Second)
+	deadLetters := []models.NotificationDeadLetter{{
+		OrgID:       1,
+		Receiver:    "team-a",
+		Integration: "slack",
+		GroupKey:    "{}:{alertname=\"a\"}",
+		GroupLabels: `{"alertname":"a"}`,
+		Alerts:      `[{"labels":{"alertname":"a"}}]`,
+		Error:       "unavailable",
+		CreatedAt:   now.Add(-time.Hour),
+	}, {
+		OrgID:       1,
+		Receiver:    "team-b",
+		Integration: "email",
+		GroupLabels: `{}`,
+		Alerts:      `[]`,
+		CreatedAt:   now,
+	}, {
+		OrgID:       2,
+		Receiver:    "team-a",
+		Integration: "slack",
+		GroupLabels: `{}`,
+		Alerts:      `[]`,
+		CreatedAt:   now,
+	}}
+	for i := range deadLetters {
+		require.NoError(t, dbstore.SaveNotificationDeadLetter(ctx, &deadLetters[i]))
+		require.NotZero(t, deadLetters[i].ID)
+	}
+
+	t.Run("should return dead letters of the organization from the most recent", func(t *testing.T) {
+		result, err := dbstore.GetNotificationDeadLetters(ctx, &models.NotificationDeadLetterQuery{OrgID: 1})
+		require.NoError(t, err)
+		require.Len(t, result, 2)
+		assert.Equal(t, deadLetters[1].ID, result[0].ID)
+		assert.Equal(t, deadLetters[0], result[1])
+
+		result, err = dbstore.GetNotificationDeadLetters(ctx, &models.NotificationDeadLetterQuery{OrgID: 1, Receiver: "team-a"})
+		require.NoError(t, err)
+		require.Len(t, result, 1)
+		assert.Equal(t, deadLetters[0].ID, result[0].ID)
+	})
+
+	t.Run("should get and update dead letter", func(t *testing.T) {
+		_, err := dbstore.GetNotificationDeadLetter(ctx, 2, deadLetters[0].ID)
+		require.ErrorIs(t, err, models.ErrNotificationDeadLetterNotFound)
+
+		deadLetter, err := dbstore.GetNotificationDeadLetter(ctx, 1, deadLetters[0].ID)
+		require.NoError(t, err)
+		deadLetter.Error = "bad gateway"
+		require.NoError(t, dbstore.SaveNotificationDeadLetter(ctx, deadLetter))
+
+		deadLetter, err = dbstore.GetNotificationDeadLetter(ctx, 1, deadLetters[0].ID)
+		require.NoError(t, err)
+		assert.Equal(t, "bad gateway", deadLetter.Error)
+	})
+
+	t.Run("should replace the dead letter of the same notification", func(t *testing.T) {
+		deadLetter := deadLetters[0]
+		deadLetter.ID = 0
+		deadLetter.Alerts = `[{"labels":{"alertname":"a"}},{"labels":{"alertname":"b"}}]`
+		deadLetter.Error = "timeout"
+		deadLetter.CreatedAt = now.Add(-45 * time.Minute)
+		require.NoError(t, dbstore.SaveNotificationDeadLetter(ctx, &deadLetter))
+		assert.Equal(t, deadLetters[0].ID, deadLetter.ID)
+
+		result, err := dbstore.GetNotificationDeadLetters(ctx, &models.NotificationDeadLetterQuery{OrgID: 1, Receiver: "team-a"})
+		require.NoError(t, err)
+		require.Len(t, result, 1)
+		assert.Equal(t, deadLetter, result[0])
+
+		other := deadLetters[0]
+		other.ID = 0
+		other.IntegrationIndex = 1
+		other.CreatedAt = now.Add(-45 * time.Minute)
+		require.NoError(t, dbstore.SaveNotificationDeadLetter(ctx, &other))
+		assert.NotEqual(t, deadLetters[0].ID, other.ID)
+		require.NoError(t, dbstore.DeleteNotificationDeadLetter(ctx, 1, other.ID))
+	})
+
+	t.Run("should delete dead letters", func(t *testing.T) {
+		require.ErrorIs(t, dbstore.DeleteNotificationDeadLetter(ctx, 2, deadLetters[1].ID), models.ErrNotificationDeadLetterNotFound)
+		require.NoError(t, dbstore.DeleteNotificationDeadLetter(ctx, 1, deadLetters[1].ID))
+
+		n, err := dbstore.DeleteNotificationDeadLettersBefore(ctx, 1, now.Add(-30*time.Minute))
+		require.NoError(t, err)
+		assert.Equal(t, int64(1), n)
+
+		result, err := dbstore.GetNotificationDeadLetters(ctx, &models.NotificationDeadLetterQuery{OrgID: 1})
+		require.NoError(t, err)
+		require.Empty(t, result)
+
+		result, err = dbstore.GetNotificationDeadLetters(ctx, &models.NotificationDeadLetterQuery{OrgID: 2})
+		require.NoError(t, err)
+		require.Len(t, result, 1)
+	})
+}
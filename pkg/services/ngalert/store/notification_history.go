@@ -0,0 +1,91 @@
+package store
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/grafana/grafana/pkg/infra/db"
+	"github.com/grafana/grafana/pkg/services/ngalert/models"
+)
+
+const (
+	// defaultNotificationHistoryLimit is the number of entries returned when the query does not specify a limit.
+	defaultNotificationHistoryLimit = 100
+	// maxNotificationHistoryLimit is the maximum number of entries returned by a query.
+	maxNotificationHistoryLimit = 1000
+)
+
+type NotificationHistoryStore interface {
+	// SaveNotificationHistoryEntry saves the entry or returns an error.
+	SaveNotificationHistoryEntry(ctx context.Context, entry *models.NotificationHistoryEntry) error
+
+	// GetNotificationHistory returns the entries that match the query, from the most recent.
+	GetNotificationHistory(ctx context.Context, query *models.NotificationHistoryQuery) ([]models.NotificationHistoryEntry, error)
+
+	// DeleteNotificationHistoryBefore deletes the entries of the organization created before t.
+	// It returns the number of deleted entries or an error.
+	DeleteNotificationHistoryBefore(ctx context.Context, orgID int64, t time.Time) (int64, error)
+}
+
+func (st DBstore) SaveNotificationHistoryEntry(ctx context.Context, entry *models.NotificationHistoryEntry) error {
+	return st.SQLStore.WithDbSession(ctx, func(sess *db.Session) error {
+		if entry.CreatedAt.IsZero() {
+			entry.CreatedAt = TimeNow().UTC()
+		}
+		if _, err := sess.Insert(entry); err != nil {
+			return fmt.Errorf("failed to insert notification history entry: %w", err)
+		}
+		return nil
+	})
+}
+
+func (st DBstore) GetNotificationHistory(ctx context.Context, query *models.NotificationHistoryQuery) ([]models.NotificationHistoryEntry, error) {
+	entries := make([]models.NotificationHistoryEntry, 0)
+	err := st.SQLStore.WithDbSession(ctx, func(sess *db.Session) error {
+		q := sess.Where("org_id = ?", query.OrgID)
+		if query.Receiver != "" {
+			q = q.And("receiver = ?", query.Receiver)
+		}
+		if query.Integration != "" {
+			q = q.And("integration = ?", query.Integration)
+		}
+		if query.Status != "" {
+			q = q.And("status = ?", query.Status)
+		}
+		if !query.From.IsZero() {
+			q = q.And("created_at >= ?", query.From.UTC())
+		}
+		if !query.To.IsZero() {
+			q = q.And("created_at <= ?", query.To.UTC())
+		}
+
+		limit := query.Limit
+		if limit <= 0 {
+			limit = defaultNotificationHistoryLimit
+		}
+		if limit > maxNotificationHistoryLimit {
+			limit = maxNotificationHistoryLimit
+		}
+		return q.Desc("created_at", "id").Limit(limit).Find(&entries)
+	})
+	if err != nil {
+		return nil, fmt.Errorf("failed to get notification history: %w", err)
+	}
+	return entries, nil
+}
+
+func (st DBstore) DeleteNotificationHistoryBefore(ctx context.Context, orgID int64, t time.Time) (int64, error) {
+	var n int64
+	if err := st.SQLStore.WithDbSession(ctx, func(sess *db.Session) error {
+		rows, err := sess.Where("org_id = ? AND created_at < ?", orgID, t.UTC()).Delete(&models.NotificationHistoryEntry{})
+		if err != nil {
+			return fmt.Errorf("failed to delete notification history: %w", err)
+		}
+		n = rows
+		return nil
+	}); err != nil {
+		return -1, err
+	}
+	return n, nil
+}
@@ -0,0 +1,112 @@
+package store_test
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/grafana/grafana/pkg/services/ngalert/models"
+	"github.com/grafana/grafana/pkg/services/ngalert/tests"
+)
+
+func TestIntegrationNotificationHistory(t *testing.T) {
+	if testing.Short() {
+		t.Skip("skipping integration test")
+	}
+
+	ctx := context.Background()
+	_, dbstore := tests.SetupTestEnv(t, baseIntervalSeconds)
+
+	// our database schema uses second precision for timestamps
+	now := time.Now().UTC().Truncate(time.Second)
+	entries := []models.NotificationHistoryEntry{{
+		OrgID:        1,
+		Receiver:     "team-a",
+		Integration:  "slack",
+		Status:       models.NotificationHistoryStatusSuccess,
+		FiringAlerts: 1,
+		CreatedAt:    now.Add(-2 * time.Hour),
+	}, {
+		OrgID:       1,
+		Receiver:    "team-a",
+		Integration: "email",
+		Status:      models.NotificationHistoryStatusFailed,
+		Error:       "failed to send email",
+		Retries:     2,
+		CreatedAt:   now.Add(-time.Hour),
+	}, {
+		OrgID:       1,
+		Receiver:    "team-b",
+		Integration: "slack",
+		Status:      models.NotificationHistoryStatusSuccess,
+		CreatedAt:   now,
+	}, {
+		OrgID:       2,
+		Receiver:    "team-a",
+		Integration: "slack",
+		Status:      models.NotificationHistoryStatusSuccess,
+		CreatedAt:   now,
+	}}
+	for i := range entries {
+		require.NoError(t, dbstore.SaveNotificationHistoryEntry(ctx, &entries[i]))
+		require.NotZero(t, entries[i].ID)
+	}
+
+	t.Run("should return entries of the organization from the most recent", func(t *testing.T) {
+		result, err := dbstore.GetNotificationHistory(ctx, &models.NotificationHistoryQuery{OrgID: 1})
+		require.NoError(t, err)
+		require.Len(t, result, 3)
+		assert.Equal(t, entries[2].ID, result[0].ID)
+		assert.Equal(t, entries[1].ID, result[1].ID)
+		assert.Equal(t, entries[0].ID, result[2].ID)
+		assert.Equal(t, "failed to send email", result[1].Error)
+		assert.Equal(t, 2, result[1].Retries)
+	})
+
+	t.Run("should filter entries", func(t *testing.T) {
+		result, err := dbstore.GetNotificationHistory(ctx, &models.NotificationHistoryQuery{OrgID: 1, Receiver: "team-a"})
+		require.NoError(t, err)
+		require.Len(t, result, 2)
+
+		result, err = dbstore.GetNotificationHistory(ctx, &models.NotificationHistoryQuery{OrgID: 1, Integration: "slack"})
+		require.NoError(t, err)
+		require.Len(t, result, 2)
+
+		result, err = dbstore.GetNotificationHistory(ctx, &models.NotificationHistoryQuery{OrgID: 1, Status: models.NotificationHistoryStatusFailed})
+		require.NoError(t, err)
+		require.Len(t, result, 1)
+		assert.Equal(t, entries[1].ID, result[0].ID)
+
+		result, err = dbstore.GetNotificationHistory(ctx, &models.NotificationHistoryQuery{
+			OrgID: 1,
+			From:  now.Add(-90 * time.Minute),
+			To:    now.Add(-30 * time.Minute),
+		})
+		require.NoError(t, err)
+		require.Len(t, result, 1)
+		assert.Equal(t, entries[1].ID, result[0].ID)
+
+		result, err = dbstore.GetNotificationHistory(ctx, &models.NotificationHistoryQuery{OrgID: 1, Limit: 1})
+		require.NoError(t, err)
+		require.Len(t, result, 1)
+		assert.Equal(t, entries[2].ID, result[0].ID)
+	})
+
+	t.Run("should delete entries of the organization before time", func(t *testing.T) {
+		n, err := dbstore.DeleteNotificationHistoryBefore(ctx, 1, now.Add(-30*time.Minute))
+		require.NoError(t, err)
+		assert.Equal(t, int64(2), n)
+
+		result, err := dbstore.GetNotificationHistory(ctx, &models.NotificationHistoryQuery{OrgID: 1})
+		require.NoError(t, err)
+		require.Len(t, result, 1)
+		assert.Equal(t, entries[2].ID, result[0].ID)
+
+		result, err = dbstore.GetNotificationHistory(ctx, &models.NotificationHistoryQuery{OrgID: 2})
+		require.NoError(t, err)
+		require.Len(t, result, 1)
+	})
+}
@@ -0,0 +1,154 @@
+package writer
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"io"
+	"math"
+	"net/http"
+	"net/url"
+	"sort"
+	"time"
+
+	"github.com/grafana/grafana-plugin-sdk-go/data"
+	"github.com/prometheus/prometheus/prompb"
+
+	"github.com/grafana/grafana/pkg/infra/log"
+	"github.com/grafana/grafana/pkg/services/live/remotewrite"
+	"github.com/grafana/grafana/pkg/setting"
+)
+
+// PrometheusWriter writes the results of recording rules to a Prometheus remote write endpoint.
+type PrometheusWriter struct {
+	client            http.Client
+	url               *url.URL
+	basicAuthUsername string
+	basicAuthPassword string
+	log               log.Logger
+}
+
+func NewPrometheusWriter(cfg setting.UnifiedAlertingRecordingRuleSettings, logger log.Logger) (*PrometheusWriter, error) {
+	if cfg.URL == "" {
+		return nil, fmt.Errorf("setting 'url' is required when recording rules are enabled")
+	}
+	u, err := url.Parse(cfg.URL)
+	if err != nil {
+		return nil, fmt.Errorf("failed to parse setting 'url': %w", err)
+	}
+	return &PrometheusWriter{
+		client: http.Client{
+			Timeout: cfg.Timeout,
+		},
+		url:               u,
+		basicAuthUsername: cfg.BasicAuthUsername,
+		basicAuthPassword: cfg.BasicAuthPassword,
+		log:               logger,
+	}, nil
+}
+
+// Write converts every numeric field of the frames to a sample of the metric with the given name, and sends them to
+// the remote write endpoint. The labels of a sample are the labels of its field and the extra labels.
+func (w *PrometheusWriter) Write(ctx context.Context, name string, t time.Time, frames data.Frames, extraLabels map[string]string) error {
+	series := TimeSeriesFromFrames(name, t, frames, extraLabels)
+	if len(series) == 0 {
+		w.log.Debug("No samples to write", "metric", name)
+		return nil
+	}
+
+	body, err := remotewrite.TimeSeriesToBytes(series)
+	if err != nil {
+		return fmt.Errorf("failed to serialize time series: %w", err)
+	}
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, w.url.String(), bytes.NewReader(body))
+	if err != nil {
+		return fmt.Errorf("failed to create remote write request: %w", err)
+	}
+	req.Header.Set("Content-Type", "application/x-protobuf")
+	req.Header.Set("Content-Encoding", "snappy")
+	req.Header.Set("X-Prometheus-Remote-Write-Version", "0.1.0")
+	if w.basicAuthUsername != "" || w.basicAuthPassword != "" {
+		req.SetBasicAuth(w.basicAuthUsername, w.basicAuthPassword)
+	}
+
+	resp, err := w.client.Do(req)
+	if resp != nil {
+		defer func() {
+			if err := resp.Body.Close(); err != nil {
+				w.log.Warn("Failed to close response body", "err", err)
+			}
+		}()
+	}
+	if err != nil {
+		return fmt.Errorf("error sending remote write request: %w", err)
+	}
+
+	if resp.StatusCode < 200 || resp.StatusCode >= 300 {
+		byt, _ := io.ReadAll(resp.Body)
+		w.log.Error("Error response from remote write endpoint", "response", string(byt), "status", resp.StatusCode)
+		return fmt.Errorf("received a non-200 response from the remote write endpoint, status: %d", resp.StatusCode)
+	}
+	w.log.Debug("Wrote samples to the remote write endpoint", "metric", name, "series", len(series))
+	return nil
+}
+
+// TimeSeriesFromFrames converts the numeric fields of the frames to time series with a single sample at time t. The
+// sample is the last non-null value of the field, which is the only value of the fields that expressions return for
+// numbers. Fields without a value are skipped.
+func TimeSeriesFromFrames(name string, t time.Time, frames data.Frames, extraLabels map[string]string) []prompb.TimeSeries {
+	var result []prompb.TimeSeries
+	for _, frame := range frames {
+		for _, field := range frame.Fields {
+			if !field.Type().Numeric() {
+				continue
+			}
+			value, ok := lastValue(field)
+			if !ok {
+				continue
+			}
+			result = append(result, prompb.TimeSeries{
+				Labels: seriesLabels(name, field.Labels, extraLabels),
+				Samples: []prompb.Sample{{
+					// Timestamp is int milliseconds for remote write.
+					Timestamp: t.UnixMilli(),
+					Value:     value,
+				}},
+			})
+		}
+	}
+	return result
+}
+
+func lastValue(field *data.Field) (float64, bool) {
+	for i := field.Len() - 1; i >= 0; i-- {
+		value, err := field.NullableFloatAt(i)
+		if err != nil || value == nil || math.IsNaN(*value) {
+			continue
+		}
+		return *value, true
+	}
+	return 0, false
+}
+
+// seriesLabels merges the labels of the field with the extra labels, which take precedence, and sorts them by name
+// as required by the remote write protocol.
+func seriesLabels(name string, fieldLabels data.Labels, extraLabels map[string]string) []prompb.Label {
+	merged := make(map[string]string, len(fieldLabels)+len(extraLabels)+1)
+	for k, v := range fieldLabels {
+		merged[k] = v
+	}
+	for k, v := range extraLabels {
+		merged[k] = v
+	}
+	merged["__name__"] = name
+
+	labels := make([]prompb.Label, 0, len(merged))
+	for k, v := range merged {
+		labels = append(labels, prompb.Label{Name: k, Value: v})
+	}
+	sort.Slice(labels, func(i, j int) bool {
+		return labels[i].Name < labels[j].Name
+	})
+	return labels
+}
@@ -0,0 +1,115 @@
+package writer
+
+import (
+	"context"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/gogo/protobuf/proto"
+	"github.com/golang/snappy"
+	"github.com/grafana/grafana-plugin-sdk-go/data"
+	"github.com/prometheus/prometheus/prompb"
+	"github.com/stretchr/testify/require"
+
+	"github.com/grafana/grafana/pkg/infra/log"
+	"github.com/grafana/grafana/pkg/setting"
+)
+
+func TestTimeSeriesFromFrames(t *testing.T) {
+	now := time.Unix(100, 0)
+
+	t.Run("should convert numeric fields to series with the last value", func(t *testing.T) {
+		frames := data.Frames{
+			data.NewFrame("",
+				data.NewField("", data.Labels{"instance": "a"}, []*float64{fp(1), fp(2), nil}),
+			),
+			data.NewFrame("",
+				data.NewField("", data.Labels{"instance": "b", "team": "x"}, []float64{3}),
+			),
+		}
+
+		series := TimeSeriesFromFrames("test_metric", now, frames, map[string]string{"team": "y"})
+
+		require.Equal(t, []prompb.TimeSeries{
+			{
+				Labels:  []prompb.Label{{Name: "__name__", Value: "test_metric"}, {Name: "instance", Value: "a"}, {Name: "team", Value: "y"}},
+				Samples: []prompb.Sample{{Timestamp: 100000, Value: 2}},
+			},
+			{
+				Labels:  []prompb.Label{{Name: "__name__", Value: "test_metric"}, {Name: "instance", Value: "b"}, {Name: "team", Value: "y"}},
+				Samples: []prompb.Sample{{Timestamp: 100000, Value: 3}},
+			},
+		}, series)
+	})
+
+	t.Run("should skip fields that are not numeric or have no value", func(t *testing.T) {
+		frames := data.Frames{
+			data.NewFrame("",
+				data.NewField("time", nil, []time.Time{now}),
+				data.NewField("name", nil, []string{"a"}),
+				data.NewField("value", nil, []*float64{nil}),
+			),
+		}
+
+		require.Empty(t, TimeSeriesFromFrames("test_metric", now, frames, nil))
+	})
+}
+
+func TestPrometheusWriter_Write(t *testing.T) {
+	now := time.Unix(100, 0)
+	frames := data.Frames{data.NewFrame("", data.NewField("", data.Labels{"instance": "a"}, []float64{1}))}
+
+	t.Run("should send the series to the remote write endpoint", func(t *testing.T) {
+		var received prompb.WriteRequest
+		server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			user, password, ok := r.BasicAuth()
+			require.True(t, ok)
+			require.Equal(t, "user", user)
+			require.Equal(t, "password", password)
+			require.Equal(t, "snappy", r.Header.Get("Content-Encoding"))
+
+			compressed, err := io.ReadAll(r.Body)
+			require.NoError(t, err)
+			body, err := snappy.Decode(nil, compressed)
+			require.NoError(t, err)
+			require.NoError(t, proto.Unmarshal(body, &received))
+			w.WriteHeader(http.StatusNoContent)
+		}))
+		defer server.Close()
+
+		writer, err := NewPrometheusWriter(setting.UnifiedAlertingRecordingRuleSettings{
+			URL:               server.URL,
+			BasicAuthUsername: "user",
+			BasicAuthPassword: "password",
+			Timeout:           time.Second,
+		}, log.NewNopLogger())
+		require.NoError(t, err)
+
+		require.NoError(t, writer.Write(context.Background(), "test_metric", now, frames, nil))
+		require.Equal(t, TimeSeriesFromFrames("test_metric", now, frames, nil), received.Timeseries)
+	})
+
+	t.Run("should fail if the remote write endpoint returns an error", func(t *testing.T) {
+		server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			w.WriteHeader(http.StatusBadRequest)
+		}))
+		defer server.Close()
+
+		writer, err := NewPrometheusWriter(setting.UnifiedAlertingRecordingRuleSettings{URL: server.URL, Timeout: time.Second}, log.NewNopLogger())
+		require.NoError(t, err)
+
+		require.ErrorContains(t, writer.Write(context.Background(), "test_metric", now, frames, nil), "status: 400")
+	})
+
+	t.Run("should require the url", func(t *testing.T) {
+		_, err := NewPrometheusWriter(setting.UnifiedAlertingRecordingRuleSettings{}, log.NewNopLogger())
+		require.Error(t, err)
+	})
+}
+
+func fp(f float64) *float64 {
+	return &f
+}
@@ -0,0 +1,489 @@
+package access
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strconv"
+
+	"github.com/grafana/grafana/pkg/infra/log"
+	"github.com/grafana/grafana/pkg/models"
+	"github.com/grafana/grafana/pkg/services/accesscontrol"
+	"github.com/grafana/grafana/pkg/services/dashboards"
+	"github.com/grafana/grafana/pkg/services/folder"
+	"github.com/grafana/grafana/pkg/services/org"
+	"github.com/grafana/grafana/pkg/services/user"
+)
+
+type FolderStore interface {
+	Get(ctx context.Context, cmd *folder.GetFolderQuery) (*folder.Folder, error)
+	Create(ctx context.Context, cmd *folder.CreateFolderCommand) (*folder.Folder, error)
+	Update(ctx context.Context, user *user.SignedInUser, orgID int64, existingUid string, cmd *models.UpdateFolderCommand) (*folder.Folder, error)
+	Move(ctx context.Context, cmd *folder.MoveFolderCommand) (*folder.Folder, error)
+	DeleteFolder(ctx context.Context, cmd *folder.DeleteFolderCommand) error
+}
+
+type TeamStore interface {
+	CreateTeam(name, email string, orgID int64) (models.Team, error)
+	UpdateTeam(ctx context.Context, cmd *models.UpdateTeamCommand) error
+	DeleteTeam(ctx context.Context, cmd *models.DeleteTeamCommand) error
+	SearchTeams(ctx context.Context, query *models.SearchTeamsQuery) error
+	GetTeamMembers(ctx context.Context, query *models.GetTeamMembersQuery) error
+}
+
+type UserStore interface {
+	GetByLogin(ctx context.Context, query *user.GetUserByLoginQuery) (*user.User, error)
+	GetByEmail(ctx context.Context, query *user.GetUserByEmailQuery) (*user.User, error)
+}
+
+type ProvisionerConfig struct {
+	Path                        string
+	FolderService               FolderStore
+	TeamService                 TeamStore
+	UserService                 UserStore
+	TeamPermissionsService      accesscontrol.TeamPermissionsService
+	FolderPermissionsService    accesscontrol.FolderPermissionsService
+	DashboardPermissionsService accesscontrol.DashboardPermissionsService
+	OrgService                  org.Service
+	// NestedFolders is whether the nestedFolders feature toggle is enabled, that is required to provision the
+	// parent of folders.
+	NestedFolders bool
+}
+
+// provisionerPermissions are the permissions of the user that the provisioner acts as.
+var provisionerPermissions = []accesscontrol.Permission{
+	{Action: dashboards.ActionFoldersCreate},
+	{Action: dashboards.ActionFoldersRead, Scope: dashboards.ScopeFoldersAll},
+	{Action: dashboards.ActionFoldersWrite, Scope: dashboards.ScopeFoldersAll},
+	{Action: dashboards.ActionFoldersDelete, Scope: dashboards.ScopeFoldersAll},
+	{Action: accesscontrol.ActionTeamsRead, Scope: accesscontrol.ScopeTeamsAll},
+	{Action: accesscontrol.ActionOrgUsersRead, Scope: accesscontrol.ScopeUsersAll},
+}
+
+// Provision scans a directory for provisioning config files and provisions the folders, teams and
+// permissions in those files.
+func Provision(ctx context.Context, cfg ProvisionerConfig) error {
+	logger := log.New("provisioning.access")
+	ap := AccessProvisioner{
+		log:         logger,
+		cfgProvider: &configReader{log: logger, orgService: cfg.OrgService},
+		cfg:         cfg,
+	}
+	return ap.applyChanges(ctx, cfg.Path)
+}
+
+// AccessProvisioner is responsible for provisioning folders, teams and folder and dashboard permissions based
+// on configuration read by the `configReader`
+type AccessProvisioner struct {
+	log         log.Logger
+	cfgProvider *configReader
+	cfg         ProvisionerConfig
+}
+
+func (ap *AccessProvisioner) applyChanges(ctx context.Context, configPath string) error {
+	configs, err := ap.cfgProvider.readConfig(ctx, configPath)
+	if err != nil {
+		return err
+	}
+
+	// Deletions are applied first and permissions last, so that permissions can be granted on folders and to
+	// teams of any of the files
+	for _, cfg := range configs {
+		if err := ap.deleteFolders(ctx, cfg.DeleteFolders); err != nil {
+			return err
+		}
+		if err := ap.deleteTeams(ctx, cfg.DeleteTeams); err != nil {
+			return err
+		}
+	}
+
+	var folders []*folderFromConfig
+	for _, cfg := range configs {
+		folders = append(folders, cfg.Folders...)
+	}
+	folders, err = sortFolders(folders)
+	if err != nil {
+		return err
+	}
+	for _, f := range folders {
+		if err := ap.applyFolder(ctx, f); err != nil {
+			return fmt.Errorf("failed to provision %q folder: %w", f.UID, err)
+		}
+	}
+
+	for _, cfg := range configs {
+		for _, t := range cfg.Teams {
+			if err := ap.applyTeam(ctx, t); err != nil {
+				return fmt.Errorf("failed to provision %q team: %w", t.Name, err)
+			}
+		}
+	}
+
+	// Permissions are deleted before they are granted, so that a grant in a file wins over its deletion
+	for _, cfg := range configs {
+		for _, p := range cfg.DeletePermissions {
+			if err := ap.deletePermissions(ctx, p); err != nil {
+				return fmt.Errorf("failed to delete permissions of %s: %w", p.resource(), err)
+			}
+		}
+	}
+
+	for _, cfg := range configs {
+		for _, p := range cfg.Permissions {
+			if err := ap.applyPermissions(ctx, p); err != nil {
+				return fmt.Errorf("failed to provision permissions of %s: %w", p.resource(), err)
+			}
+		}
+	}
+
+	return nil
+}
+
+func (ap *AccessProvisioner) applyFolder(ctx context.Context, f *folderFromConfig) error {
+	signedInUser := provisionerUser(f.OrgID)
+	parentUID := f.ParentUID
+	if parentUID != "" && !ap.cfg.NestedFolders {
+		ap.log.Warn("ignoring parent of folder, that requires the nestedFolders feature toggle", "uid", f.UID, "parentUid", parentUID)
+		parentUID = ""
+	}
+
+	existing, err := ap.cfg.FolderService.Get(ctx, &folder.GetFolderQuery{UID: &f.UID, OrgID: f.OrgID, SignedInUser: signedInUser})
+	if err != nil && !isFolderNotFound(err) {
+		return err
+	}
+
+	if existing == nil {
+		ap.log.Info("inserting folder from configuration", "uid", f.UID, "title", f.Title)
+		_, err := ap.cfg.FolderService.Create(ctx, &folder.CreateFolderCommand{
+			UID:          f.UID,
+			OrgID:        f.OrgID,
+			Title:        f.Title,
+			Description:  f.Description,
+			ParentUID:    parentUID,
+			SignedInUser: signedInUser,
+		})
+		return err
+	}
+
+	// Descriptions of folders are only stored with the nestedFolders feature toggle
+	if existing.Title != f.Title || (ap.cfg.NestedFolders && existing.Description != f.Description) {
+		ap.log.Debug("updating folder from configuration", "uid", f.UID, "title", f.Title)
+		if _, err := ap.cfg.FolderService.Update(ctx, signedInUser, f.OrgID, f.UID, &models.UpdateFolderCommand{
+			Uid:         f.UID,
+			Title:       f.Title,
+			Description: f.Description,
+			Overwrite:   true,
+		}); err != nil {
+			return err
+		}
+	}
+
+	if ap.cfg.NestedFolders && existing.ParentUID != parentUID {
+		ap.log.Debug("moving folder from configuration", "uid", f.UID, "parentUid", parentUID)
+		if _, err := ap.cfg.FolderService.Move(ctx, &folder.MoveFolderCommand{
+			UID:          f.UID,
+			NewParentUID: parentUID,
+			OrgID:        f.OrgID,
+			SignedInUser: signedInUser,
+		}); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+func (ap *AccessProvisioner) applyTeam(ctx context.Context, t *teamFromConfig) error {
+	existing, err := ap.getTeam(ctx, t.OrgID, t.Name)
+	if err != nil {
+		return err
+	}
+
+	var teamID int64
+	if existing == nil {
+		ap.log.Info("inserting team from configuration", "name", t.Name)
+		team, err := ap.cfg.TeamService.CreateTeam(t.Name, t.Email, t.OrgID)
+		if err != nil {
+			return err
+		}
+		teamID = team.Id
+	} else {
+		teamID = existing.Id
+		if existing.Email != t.Email {
+			ap.log.Debug("updating team from configuration", "name", t.Name)
+			if err := ap.cfg.TeamService.UpdateTeam(ctx, &models.UpdateTeamCommand{
+				Id:    teamID,
+				Name:  t.Name,
+				Email: t.Email,
+				OrgId: t.OrgID,
+			}); err != nil {
+				return err
+			}
+		}
+	}
+
+	return ap.applyTeamMembers(ctx, t, teamID)
+}
+
+// applyTeamMembers adds the members of the team in the config and updates their permission, and removes the
+// other members of the team, unless they were added by team sync.
+func (ap *AccessProvisioner) applyTeamMembers(ctx context.Context, t *teamFromConfig, teamID int64) error {
+	query := &models.GetTeamMembersQuery{OrgId: t.OrgID, TeamId: teamID, SignedInUser: provisionerUser(t.OrgID)}
+	if err := ap.cfg.TeamService.GetTeamMembers(ctx, query); err != nil {
+		return err
+	}
+	current := map[int64]*models.TeamMemberDTO{}
+	for _, m := range query.Result {
+		current[m.UserId] = m
+	}
+
+	teamIDString := strconv.FormatInt(teamID, 10)
+	members := map[int64]bool{}
+	for _, m := range t.Members {
+		u, err := ap.getUser(ctx, m.Login, m.Email)
+		if err != nil {
+			return err
+		}
+		if u == nil {
+			ap.log.Warn("skipping team member that doesn't exist", "team", t.Name, "login", m.Login, "email", m.Email)
+			continue
+		}
+		members[u.ID] = true
+
+		// The permission of team members is 0, which the team permission service names Member
+		var permission models.PermissionType
+		permissionName := "Member"
+		if m.Admin {
+			permission = models.PERMISSION_ADMIN
+			permissionName = permission.String()
+		}
+		if c, ok := current[u.ID]; ok && c.Permission == permission {
+			continue
+		}
+
+		ap.log.Debug("adding team member from configuration", "team", t.Name, "login", u.Login, "permission", permissionName)
+		if _, err := ap.cfg.TeamPermissionsService.SetUserPermission(ctx, t.OrgID, accesscontrol.User{ID: u.ID}, teamIDString, permissionName); err != nil {
+			return fmt.Errorf("failed to add team member %q: %w", u.Login, err)
+		}
+	}
+
+	for userID, m := range current {
+		if members[userID] || m.External {
+			continue
+		}
+		ap.log.Debug("removing team member missing in configuration", "team", t.Name, "login", m.Login)
+		if _, err := ap.cfg.TeamPermissionsService.SetUserPermission(ctx, t.OrgID, accesscontrol.User{ID: userID}, teamIDString, ""); err != nil {
+			return fmt.Errorf("failed to remove team member %q: %w", m.Login, err)
+		}
+	}
+
+	return nil
+}
+
+// applyPermissions sets the permissions of the grants of the folder or dashboard. Permissions of other teams,
+// users and roles are left unchanged, and are removed with deletePermissions.
+func (ap *AccessProvisioner) applyPermissions(ctx context.Context, p *permissionsFromConfig) error {
+	commands, err := ap.permissionCommands(ctx, p, false)
+	if err != nil {
+		return err
+	}
+
+	if p.FolderUID != "" {
+		_, err := ap.cfg.FolderPermissionsService.SetPermissions(ctx, p.OrgID, p.FolderUID, commands...)
+		return err
+	}
+
+	_, err = ap.cfg.DashboardPermissionsService.SetPermissions(ctx, p.OrgID, p.DashboardUID, commands...)
+	if errors.Is(err, dashboards.ErrDashboardNotFound) {
+		// The dashboard may be provisioned later on by the dashboard provisioner
+		ap.log.Warn("skipping permissions of dashboard that doesn't exist", "uid", p.DashboardUID)
+		return nil
+	}
+	return err
+}
+
+// deletePermissions removes the permissions of the grants of the folder or dashboard. Grants of teams, users,
+// folders and dashboards that don't exist are already removed.
+func (ap *AccessProvisioner) deletePermissions(ctx context.Context, p *permissionsFromConfig) error {
+	commands, err := ap.permissionCommands(ctx, p, true)
+	if err != nil {
+		return err
+	}
+	if len(commands) == 0 {
+		return nil
+	}
+
+	if p.FolderUID != "" {
+		_, err = ap.cfg.FolderPermissionsService.SetPermissions(ctx, p.OrgID, p.FolderUID, commands...)
+	} else {
+		_, err = ap.cfg.DashboardPermissionsService.SetPermissions(ctx, p.OrgID, p.DashboardUID, commands...)
+	}
+	if isFolderNotFound(err) || errors.Is(err, dashboards.ErrDashboardNotFound) {
+		return nil
+	}
+	if err != nil {
+		return err
+	}
+	ap.log.Info("deleted permissions based on configuration", "resource", p.resource())
+	return nil
+}
+
+// permissionCommands returns the commands setting the permissions of the grants, or removing them if remove is
+// true.
+func (ap *AccessProvisioner) permissionCommands(ctx context.Context, p *permissionsFromConfig, remove bool) ([]accesscontrol.SetResourcePermissionCommand, error) {
+	commands := make([]accesscontrol.SetResourcePermissionCommand, 0, len(p.Grants))
+	for _, g := range p.Grants {
+		cmd := accesscontrol.SetResourcePermissionCommand{BuiltinRole: g.Role, Permission: g.Permission}
+		if remove {
+			cmd.Permission = ""
+		}
+		switch {
+		case g.Team != "":
+			team, err := ap.getTeam(ctx, p.OrgID, g.Team)
+			if err != nil {
+				return nil, err
+			}
+			if team == nil && remove {
+				continue
+			}
+			if team == nil {
+				return nil, fmt.Errorf("team %q not found", g.Team)
+			}
+			cmd.TeamID = team.Id
+		case g.User != "":
+			u, err := ap.getUser(ctx, g.User, "")
+			if err != nil {
+				return nil, err
+			}
+			if u == nil {
+				ap.log.Warn("skipping permission of user that doesn't exist", "resource", p.resource(), "user", g.User)
+				continue
+			}
+			cmd.UserID = u.ID
+		}
+		commands = append(commands, cmd)
+	}
+	return commands, nil
+}
+
+func (ap *AccessProvisioner) deleteFolders(ctx context.Context, foldersToDelete []*deleteFolderConfig) error {
+	for _, f := range foldersToDelete {
+		err := ap.cfg.FolderService.DeleteFolder(ctx, &folder.DeleteFolderCommand{
+			UID:          f.UID,
+			OrgID:        f.OrgID,
+			SignedInUser: provisionerUser(f.OrgID),
+		})
+		if isFolderNotFound(err) {
+			continue
+		}
+		if err != nil {
+			return fmt.Errorf("failed to delete %q folder: %w", f.UID, err)
+		}
+		ap.log.Info("deleted folder based on configuration", "uid", f.UID)
+	}
+
+	return nil
+}
+
+func (ap *AccessProvisioner) deleteTeams(ctx context.Context, teamsToDelete []*deleteTeamConfig) error {
+	for _, t := range teamsToDelete {
+		team, err := ap.getTeam(ctx, t.OrgID, t.Name)
+		if err != nil {
+			return err
+		}
+		if team == nil {
+			continue
+		}
+
+		if err := ap.cfg.TeamService.DeleteTeam(ctx, &models.DeleteTeamCommand{OrgId: t.OrgID, Id: team.Id}); err != nil {
+			return fmt.Errorf("failed to delete %q team: %w", t.Name, err)
+		}
+		ap.log.Info("deleted team based on configuration", "name", t.Name)
+	}
+
+	return nil
+}
+
+func (ap *AccessProvisioner) getTeam(ctx context.Context, orgID int64, name string) (*models.TeamDTO, error) {
+	return getTeam(ctx, ap.cfg.TeamService, orgID, name)
+}
+
+func (ap *AccessProvisioner) getUser(ctx context.Context, login string, email string) (*user.User, error) {
+	return getUser(ctx, ap.cfg.UserService, login, email)
+}
+
+// getTeam returns the team with the name in the organization, or nil if there is none.
+func getTeam(ctx context.Context, teamService TeamReader, orgID int64, name string) (*models.TeamDTO, error) {
+	query := &models.SearchTeamsQuery{
+		OrgId:        orgID,
+		Name:         name,
+		Limit:        1,
+		Page:         1,
+		UserIdFilter: models.FilterIgnoreUser,
+		SignedInUser: provisionerUser(orgID),
+	}
+	if err := teamService.SearchTeams(ctx, query); err != nil {
+		return nil, err
+	}
+	if len(query.Result.Teams) == 0 {
+		return nil, nil
+	}
+	return query.Result.Teams[0], nil
+}
+
+// getUser returns the user with the login, or else the email, or nil if there is none.
+func getUser(ctx context.Context, userService UserStore, login string, email string) (*user.User, error) {
+	var (
+		u   *user.User
+		err error
+	)
+	if login != "" {
+		u, err = userService.GetByLogin(ctx, &user.GetUserByLoginQuery{LoginOrEmail: login})
+	} else {
+		u, err = userService.GetByEmail(ctx, &user.GetUserByEmailQuery{Email: email})
+	}
+	if errors.Is(err, user.ErrUserNotFound) {
+		return nil, nil
+	}
+	return u, err
+}
+
+// sortFolders returns the folders sorted so that parent folders come before their children.
+func sortFolders(folders []*folderFromConfig) ([]*folderFromConfig, error) {
+	type folderKey struct {
+		orgID int64
+		uid   string
+	}
+
+	pending := map[folderKey]bool{}
+	for _, f := range folders {
+		pending[folderKey{f.OrgID, f.UID}] = true
+	}
+
+	sorted := make([]*folderFromConfig, 0, len(folders))
+	for len(sorted) < len(folders) {
+		progress := false
+		for _, f := range folders {
+			key := folderKey{f.OrgID, f.UID}
+			if !pending[key] || pending[folderKey{f.OrgID, f.ParentUID}] {
+				continue
+			}
+			delete(pending, key)
+			sorted = append(sorted, f)
+			progress = true
+		}
+		if !progress {
+			return nil, fmt.Errorf("folders in configuration have circular parents")
+		}
+	}
+
+	return sorted, nil
+}
+
+func isFolderNotFound(err error) bool {
+	return errors.Is(err, dashboards.ErrFolderNotFound) || errors.Is(err, folder.ErrFolderNotFound)
+}
+
+func provisionerUser(orgID int64) *user.SignedInUser {
+	return accesscontrol.BackgroundUser("access_provisioning", orgID, org.RoleAdmin, provisionerPermissions)
+}
@@ -0,0 +1,301 @@
+package access
+
+import (
+	"context"
+	"strconv"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/grafana/grafana/pkg/models"
+	"github.com/grafana/grafana/pkg/services/accesscontrol"
+	"github.com/grafana/grafana/pkg/services/dashboards"
+	"github.com/grafana/grafana/pkg/services/folder"
+	"github.com/grafana/grafana/pkg/services/org"
+	"github.com/grafana/grafana/pkg/services/org/orgtest"
+	"github.com/grafana/grafana/pkg/services/user"
+)
+
+func TestAccessProvisioner(t *testing.T) {
+	setup := func() (ProvisionerConfig, *fakeFolderStore, *fakeTeamStore, *fakePermissionsService, *fakePermissionsService) {
+		folders := &fakeFolderStore{folders: map[string]*folder.Folder{}}
+		teams := &fakeTeamStore{members: map[int64][]*models.TeamMemberDTO{}}
+		users := &fakeUserStore{users: []*user.User{
+			{ID: 1, Login: "alice", Email: "alice@example.com"},
+			{ID: 2, Login: "bob", Email: "bob@example.com"},
+			{ID: 3, Login: "carol", Email: "carol@example.com"},
+		}}
+		folderPermissions := &fakePermissionsService{resources: map[string]bool{}}
+		dashboardPermissions := &fakePermissionsService{resources: map[string]bool{}}
+		cfg := ProvisionerConfig{
+			FolderService:               folders,
+			TeamService:                 teams,
+			UserService:                 users,
+			TeamPermissionsService:      &fakeTeamPermissionsService{teams: teams, users: users},
+			FolderPermissionsService:    folderPermissions,
+			DashboardPermissionsService: dashboardPermissions,
+			OrgService:                  &orgtest.FakeOrgService{ExpectedOrg: &org.Org{ID: 1}},
+		}
+		folderPermissions.folders = folders
+		return cfg, folders, teams, folderPermissions, dashboardPermissions
+	}
+
+	t.Run("should provision folders, teams and permissions", func(t *testing.T) {
+		cfg, folders, teams, folderPermissions, dashboardPermissions := setup()
+		cfg.Path = allProperties
+		require.NoError(t, Provision(context.Background(), cfg))
+
+		require.Len(t, folders.folders, 2)
+		assert.Equal(t, "Platform", folders.folders["platform"].Title)
+		assert.Equal(t, "SRE", folders.folders["platform-sre"].Title)
+		assert.Empty(t, folders.folders["platform-sre"].ParentUID, "parent requires nested folders")
+
+		require.Len(t, teams.teams, 1)
+		assert.Equal(t, "sre@example.com", teams.teams[0].Email)
+		assert.ElementsMatch(t, []*models.TeamMemberDTO{
+			{OrgId: 1, TeamId: 1, UserId: 1, Login: "alice", Permission: models.PERMISSION_ADMIN},
+			{OrgId: 1, TeamId: 1, UserId: 2, Login: "bob"},
+		}, teams.members[1])
+
+		assert.Equal(t, map[string][]accesscontrol.SetResourcePermissionCommand{
+			"platform-sre": {
+				{TeamID: 1, Permission: "Edit"},
+				{UserID: 3, Permission: "Admin"},
+				{BuiltinRole: "Viewer", Permission: "View"},
+			},
+		}, folderPermissions.set)
+		assert.Empty(t, dashboardPermissions.set, "dashboard doesn't exist")
+
+		t.Run("should not change anything when provisioning again", func(t *testing.T) {
+			folders.calls, teams.calls = nil, nil
+			dashboardPermissions.resources["overview"] = true
+			require.NoError(t, Provision(context.Background(), cfg))
+
+			assert.Empty(t, folders.calls)
+			assert.Empty(t, teams.calls)
+			assert.Equal(t, map[string][]accesscontrol.SetResourcePermissionCommand{
+				"overview": {{TeamID: 1, Permission: "View"}},
+			}, dashboardPermissions.set)
+		})
+	})
+
+	t.Run("should update folders and teams", func(t *testing.T) {
+		cfg, folders, teams, _, _ := setup()
+		cfg.Path = allProperties
+		cfg.NestedFolders = true
+		folders.folders["platform-sre"] = &folder.Folder{OrgID: 1, UID: "platform-sre", Title: "Old"}
+		teams.teams = []*models.TeamDTO{{Id: 1, OrgId: 1, Name: "SRE", Email: "old@example.com"}}
+		teams.nextID = 1
+		teams.members[1] = []*models.TeamMemberDTO{
+			{OrgId: 1, TeamId: 1, UserId: 1, Login: "alice"},
+			{OrgId: 1, TeamId: 1, UserId: 3, Login: "carol"},
+			{OrgId: 1, TeamId: 1, UserId: 4, Login: "dave", External: true},
+		}
+		require.NoError(t, Provision(context.Background(), cfg))
+
+		assert.Equal(t, "SRE", folders.folders["platform-sre"].Title)
+		assert.Equal(t, "platform", folders.folders["platform-sre"].ParentUID)
+		assert.Equal(t, "sre@example.com", teams.teams[0].Email)
+		assert.ElementsMatch(t, []*models.TeamMemberDTO{
+			{OrgId: 1, TeamId: 1, UserId: 1, Login: "alice", Permission: models.PERMISSION_ADMIN},
+			{OrgId: 1, TeamId: 1, UserId: 2, Login: "bob"},
+			{OrgId: 1, TeamId: 1, UserId: 4, Login: "dave", External: true},
+		}, teams.members[1])
+	})
+
+	t.Run("should delete folders, teams and permissions", func(t *testing.T) {
+		cfg, folders, teams, folderPermissions, dashboardPermissions := setup()
+		cfg.Path = deleteAccess
+		folders.folders["platform"] = &folder.Folder{OrgID: 1, UID: "platform", Title: "Platform"}
+		folders.folders["platform-sre"] = &folder.Folder{OrgID: 1, UID: "platform-sre", Title: "SRE"}
+		teams.teams = []*models.TeamDTO{{Id: 1, OrgId: 1, Name: "SRE"}}
+		require.NoError(t, Provision(context.Background(), cfg))
+
+		require.Len(t, folders.folders, 1)
+		assert.Contains(t, folders.folders, "platform-sre")
+		assert.Empty(t, teams.teams)
+		assert.Equal(t, map[string][]accesscontrol.SetResourcePermissionCommand{
+			"platform-sre": {
+				{UserID: 3},
+				{BuiltinRole: "Viewer"},
+			},
+		}, folderPermissions.set, "permissions of the deleted team are already removed")
+		assert.Empty(t, dashboardPermissions.set, "dashboard doesn't exist")
+	})
+
+	t.Run("circular parents should return error", func(t *testing.T) {
+		cfg, _, _, _, _ := setup()
+		cfg.Path = circularFolders
+		require.EqualError(t, Provision(context.Background(), cfg), "folders in configuration have circular parents")
+	})
+}
+
+type fakeFolderStore struct {
+	folders map[string]*folder.Folder
+	calls   []string
+}
+
+func (s *fakeFolderStore) Get(ctx context.Context, cmd *folder.GetFolderQuery) (*folder.Folder, error) {
+	if f, ok := s.folders[*cmd.UID]; ok {
+		return f, nil
+	}
+	return nil, dashboards.ErrFolderNotFound
+}
+
+func (s *fakeFolderStore) Create(ctx context.Context, cmd *folder.CreateFolderCommand) (*folder.Folder, error) {
+	s.calls = append(s.calls, "create "+cmd.UID)
+	f := &folder.Folder{OrgID: cmd.OrgID, UID: cmd.UID, Title: cmd.Title, ParentUID: cmd.ParentUID}
+	s.folders[cmd.UID] = f
+	return f, nil
+}
+
+func (s *fakeFolderStore) Update(ctx context.Context, user *user.SignedInUser, orgID int64, existingUid string, cmd *models.UpdateFolderCommand) (*folder.Folder, error) {
+	s.calls = append(s.calls, "update "+existingUid)
+	s.folders[existingUid].Title = cmd.Title
+	return s.folders[existingUid], nil
+}
+
+func (s *fakeFolderStore) Move(ctx context.Context, cmd *folder.MoveFolderCommand) (*folder.Folder, error) {
+	s.calls = append(s.calls, "move "+cmd.UID)
+	s.folders[cmd.UID].ParentUID = cmd.NewParentUID
+	return s.folders[cmd.UID], nil
+}
+
+func (s *fakeFolderStore) DeleteFolder(ctx context.Context, cmd *folder.DeleteFolderCommand) error {
+	if _, ok := s.folders[cmd.UID]; !ok {
+		return dashboards.ErrFolderNotFound
+	}
+	s.calls = append(s.calls, "delete "+cmd.UID)
+	delete(s.folders, cmd.UID)
+	return nil
+}
+
+type fakeTeamStore struct {
+	teams   []*models.TeamDTO
+	members map[int64][]*models.TeamMemberDTO
+	nextID  int64
+	calls   []string
+}
+
+func (s *fakeTeamStore) CreateTeam(name, email string, orgID int64) (models.Team, error) {
+	s.calls = append(s.calls, "create "+name)
+	s.nextID++
+	s.teams = append(s.teams, &models.TeamDTO{Id: s.nextID, OrgId: orgID, Name: name, Email: email})
+	return models.Team{Id: s.nextID, OrgId: orgID, Name: name, Email: email}, nil
+}
+
+func (s *fakeTeamStore) UpdateTeam(ctx context.Context, cmd *models.UpdateTeamCommand) error {
+	s.calls = append(s.calls, "update "+cmd.Name)
+	for _, t := range s.teams {
+		if t.Id == cmd.Id {
+			t.Name, t.Email = cmd.Name, cmd.Email
+		}
+	}
+	return nil
+}
+
+func (s *fakeTeamStore) DeleteTeam(ctx context.Context, cmd *models.DeleteTeamCommand) error {
+	s.calls = append(s.calls, "delete "+strconv.FormatInt(cmd.Id, 10))
+	for i, t := range s.teams {
+		if t.Id == cmd.Id {
+			s.teams = append(s.teams[:i], s.teams[i+1:]...)
+			return nil
+		}
+	}
+	return models.ErrTeamNotFound
+}
+
+func (s *fakeTeamStore) SearchTeams(ctx context.Context, query *models.SearchTeamsQuery) error {
+	query.Result = models.SearchTeamQueryResult{Teams: []*models.TeamDTO{}}
+	for _, t := range s.teams {
+		if t.OrgId == query.OrgId && t.Name == query.Name {
+			query.Result.Teams = append(query.Result.Teams, t)
+		}
+	}
+	return nil
+}
+
+func (s *fakeTeamStore) GetTeamMembers(ctx context.Context, query *models.GetTeamMembersQuery) error {
+	query.Result = s.members[query.TeamId]
+	return nil
+}
+
+type fakeUserStore struct {
+	users []*user.User
+}
+
+func (s *fakeUserStore) GetByLogin(ctx context.Context, query *user.GetUserByLoginQuery) (*user.User, error) {
+	for _, u := range s.users {
+		if u.Login == query.LoginOrEmail || u.Email == query.LoginOrEmail {
+			return u, nil
+		}
+	}
+	return nil, user.ErrUserNotFound
+}
+
+func (s *fakeUserStore) GetByEmail(ctx context.Context, query *user.GetUserByEmailQuery) (*user.User, error) {
+	for _, u := range s.users {
+		if u.Email == query.Email {
+			return u, nil
+		}
+	}
+	return nil, user.ErrUserNotFound
+}
+
+// fakeTeamPermissionsService updates the members of the teams of the team store, like the hooks of the team
+// permission service do.
+type fakeTeamPermissionsService struct {
+	teams *fakeTeamStore
+	users *fakeUserStore
+}
+
+func (s *fakeTeamPermissionsService) GetPermissions(ctx context.Context, user *user.SignedInUser, resourceID string) ([]accesscontrol.ResourcePermission, error) {
+	return nil, nil
+}
+
+func (s *fakeTeamPermissionsService) SetUserPermission(ctx context.Context, orgID int64, u accesscontrol.User, resourceID, permission string) (*accesscontrol.ResourcePermission, error) {
+	teamID, _ := strconv.ParseInt(resourceID, 10, 64)
+	var members []*models.TeamMemberDTO
+	for _, m := range s.teams.members[teamID] {
+		if m.UserId != u.ID {
+			members = append(members, m)
+		}
+	}
+	if permission != "" {
+		member := &models.TeamMemberDTO{OrgId: orgID, TeamId: teamID, UserId: u.ID}
+		for _, usr := range s.users.users {
+			if usr.ID == u.ID {
+				member.Login = usr.Login
+			}
+		}
+		if permission == models.PERMISSION_ADMIN.String() {
+			member.Permission = models.PERMISSION_ADMIN
+		}
+		members = append(members, member)
+	}
+	s.teams.members[teamID] = members
+	return &accesscontrol.ResourcePermission{}, nil
+}
+
+type fakePermissionsService struct {
+	accesscontrol.PermissionsService
+	folders   *fakeFolderStore
+	resources map[string]bool
+	set       map[string][]accesscontrol.SetResourcePermissionCommand
+}
+
+func (s *fakePermissionsService) SetPermissions(ctx context.Context, orgID int64, resourceID string, commands ...accesscontrol.SetResourcePermissionCommand) ([]accesscontrol.ResourcePermission, error) {
+	if s.folders != nil {
+		if _, ok := s.folders.folders[resourceID]; !ok {
+			return nil, dashboards.ErrFolderNotFound
+		}
+	} else if !s.resources[resourceID] {
+		return nil, dashboards.ErrDashboardNotFound
+	}
+	if s.set == nil {
+		s.set = map[string][]accesscontrol.SetResourcePermissionCommand{}
+	}
+	s.set[resourceID] = commands
+	return nil, nil
+}
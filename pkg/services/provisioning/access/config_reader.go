@@ -0,0 +1,269 @@
+package access
+
+import (
+	"context"
+	"fmt"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"gopkg.in/yaml.v3"
+
+	"github.com/grafana/grafana/pkg/infra/log"
+	"github.com/grafana/grafana/pkg/models"
+	"github.com/grafana/grafana/pkg/services/accesscontrol"
+	"github.com/grafana/grafana/pkg/services/org"
+	"github.com/grafana/grafana/pkg/services/provisioning/utils"
+)
+
+var grantPermissions = []string{
+	models.PERMISSION_VIEW.String(),
+	models.PERMISSION_EDIT.String(),
+	models.PERMISSION_ADMIN.String(),
+}
+
+type configReader struct {
+	log        log.Logger
+	orgService org.Service
+}
+
+func (cr *configReader) readConfig(ctx context.Context, path string) ([]*configs, error) {
+	var accessConfigs []*configs
+
+	files, err := os.ReadDir(path)
+	if err != nil {
+		cr.log.Error("can't read access provisioning files from directory", "path", path, "error", err)
+		return accessConfigs, nil
+	}
+
+	for _, file := range files {
+		if strings.HasSuffix(file.Name(), ".yaml") || strings.HasSuffix(file.Name(), ".yml") {
+			cfg, err := cr.parseAccessConfig(path, file)
+			if err != nil {
+				return nil, err
+			}
+
+			if cfg != nil {
+				accessConfigs = append(accessConfigs, cfg)
+			}
+		}
+	}
+
+	if err := cr.validate(ctx, accessConfigs); err != nil {
+		return nil, err
+	}
+
+	return accessConfigs, nil
+}
+
+func (cr *configReader) parseAccessConfig(path string, file fs.DirEntry) (*configs, error) {
+	filename, _ := filepath.Abs(filepath.Join(path, file.Name()))
+
+	// nolint:gosec
+	// We can ignore the gosec G304 warning on this one because `filename` comes from ps.Cfg.ProvisioningPath
+	yamlFile, err := os.ReadFile(filename)
+	if err != nil {
+		return nil, err
+	}
+
+	var apiVersion *configVersion
+	err = yaml.Unmarshal(yamlFile, &apiVersion)
+	if err != nil {
+		return nil, err
+	}
+
+	if apiVersion == nil {
+		// The file is empty or only contains comments
+		return nil, nil
+	}
+
+	if apiVersion.APIVersion != 1 {
+		return nil, fmt.Errorf("%s: unsupported apiVersion %d", file.Name(), apiVersion.APIVersion)
+	}
+
+	v1 := &configsV1{}
+	err = yaml.Unmarshal(yamlFile, v1)
+	if err != nil {
+		return nil, err
+	}
+
+	return v1.mapToAccessFromConfig(apiVersion.APIVersion), nil
+}
+
+// validate checks the required fields of the configs and applies the default organization, and that folders
+// and teams are only declared once per organization.
+func (cr *configReader) validate(ctx context.Context, accessConfigs []*configs) error {
+	folderUIDs := map[int64]map[string]bool{}
+	teamNames := map[int64]map[string]bool{}
+
+	for _, cfg := range accessConfigs {
+		for _, f := range cfg.Folders {
+			if f == nil {
+				continue
+			}
+			if err := cr.validateOrgID(ctx, &f.OrgID); err != nil {
+				return fmt.Errorf("failed to provision %q folder: %w", f.Title, err)
+			}
+			if f.UID == "" || f.Title == "" {
+				return fmt.Errorf("folder %q in configuration doesn't contain required fields uid and title", f.Title)
+			}
+			if f.UID == accesscontrol.GeneralFolderUID || f.UID == f.ParentUID {
+				return fmt.Errorf("folder %q in configuration has an invalid uid", f.UID)
+			}
+			if folderUIDs[f.OrgID] == nil {
+				folderUIDs[f.OrgID] = map[string]bool{}
+			}
+			if folderUIDs[f.OrgID][f.UID] {
+				return fmt.Errorf("folder %q is provisioned more than once in organization %d", f.UID, f.OrgID)
+			}
+			folderUIDs[f.OrgID][f.UID] = true
+		}
+
+		for _, t := range cfg.Teams {
+			if t == nil {
+				continue
+			}
+			if err := cr.validateOrgID(ctx, &t.OrgID); err != nil {
+				return fmt.Errorf("failed to provision %q team: %w", t.Name, err)
+			}
+			if t.Name == "" {
+				return fmt.Errorf("team in configuration doesn't contain required field name")
+			}
+			if teamNames[t.OrgID] == nil {
+				teamNames[t.OrgID] = map[string]bool{}
+			}
+			if teamNames[t.OrgID][t.Name] {
+				return fmt.Errorf("team %q is provisioned more than once in organization %d", t.Name, t.OrgID)
+			}
+			teamNames[t.OrgID][t.Name] = true
+
+			for _, m := range t.Members {
+				if (m.Login == "") == (m.Email == "") {
+					return fmt.Errorf("member of team %q in configuration should contain either field login or email", t.Name)
+				}
+			}
+		}
+
+		for _, p := range cfg.Permissions {
+			if p == nil {
+				continue
+			}
+			if err := cr.validateOrgID(ctx, &p.OrgID); err != nil {
+				return fmt.Errorf("failed to provision permissions: %w", err)
+			}
+			if err := validatePermissions(p); err != nil {
+				return err
+			}
+		}
+
+		for _, f := range cfg.DeleteFolders {
+			if f.OrgID == 0 {
+				f.OrgID = 1
+			}
+		}
+
+		for _, t := range cfg.DeleteTeams {
+			if t.OrgID == 0 {
+				t.OrgID = 1
+			}
+		}
+
+		for _, p := range cfg.DeletePermissions {
+			if p == nil {
+				continue
+			}
+			if p.OrgID == 0 {
+				p.OrgID = 1
+			}
+			if err := validateDeletePermissions(p); err != nil {
+				return err
+			}
+		}
+	}
+
+	return nil
+}
+
+func (cr *configReader) validateOrgID(ctx context.Context, orgID *int64) error {
+	if *orgID == 0 {
+		*orgID = 1
+	}
+	return utils.CheckOrgExists(ctx, cr.orgService, *orgID)
+}
+
+func validatePermissions(p *permissionsFromConfig) error {
+	if err := validatePermissionsResource(p); err != nil {
+		return err
+	}
+
+	for _, g := range p.Grants {
+		if err := validateGrantAssignee(p, g); err != nil {
+			return err
+		}
+		if !isGrantPermission(g.Permission) {
+			return fmt.Errorf("grant of %s in configuration has an invalid permission %q, expected one of %s", p.resource(), g.Permission, strings.Join(grantPermissions, ", "))
+		}
+	}
+
+	return nil
+}
+
+// validateDeletePermissions checks the grants to remove, that are identified by their team, user or role only.
+func validateDeletePermissions(p *permissionsFromConfig) error {
+	if err := validatePermissionsResource(p); err != nil {
+		return err
+	}
+
+	for _, g := range p.Grants {
+		if err := validateGrantAssignee(p, g); err != nil {
+			return err
+		}
+		if g.Permission != "" {
+			return fmt.Errorf("grant of %s to delete in configuration shouldn't contain field permission", p.resource())
+		}
+	}
+
+	return nil
+}
+
+func validatePermissionsResource(p *permissionsFromConfig) error {
+	if (p.FolderUID == "") == (p.DashboardUID == "") {
+		return fmt.Errorf("permissions in configuration should contain either field folderUid or dashboardUid")
+	}
+	return nil
+}
+
+func validateGrantAssignee(p *permissionsFromConfig, g *grantFromConfig) error {
+	assignees := 0
+	for _, assignee := range []string{g.Team, g.User, g.Role} {
+		if assignee != "" {
+			assignees++
+		}
+	}
+	if assignees != 1 {
+		return fmt.Errorf("grant of %s in configuration should contain one of fields team, user or role", p.resource())
+	}
+
+	if g.Role != "" && !org.RoleType(g.Role).IsValid() {
+		return fmt.Errorf("grant of %s in configuration has an invalid role %q", p.resource(), g.Role)
+	}
+	return nil
+}
+
+func isGrantPermission(permission string) bool {
+	for _, p := range grantPermissions {
+		if p == permission {
+			return true
+		}
+	}
+	return false
+}
+
+// resource returns a description of the folder or dashboard of the permissions for errors and logs.
+func (p *permissionsFromConfig) resource() string {
+	if p.FolderUID != "" {
+		return fmt.Sprintf("folder %q", p.FolderUID)
+	}
+	return fmt.Sprintf("dashboard %q", p.DashboardUID)
+}
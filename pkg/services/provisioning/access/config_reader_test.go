@@ -0,0 +1,96 @@
+package access
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/grafana/grafana/pkg/infra/log"
+	"github.com/grafana/grafana/pkg/services/org"
+	"github.com/grafana/grafana/pkg/services/org/orgtest"
+)
+
+const (
+	allProperties      = "testdata/all-properties"
+	invalidGrant       = "testdata/invalid-grant"
+	invalidDeleteGrant = "testdata/invalid-delete-grant"
+	duplicateFolder    = "testdata/duplicate-folder"
+	circularFolders    = "testdata/circular-folders"
+	deleteAccess       = "testdata/delete"
+)
+
+func TestConfigReader(t *testing.T) {
+	newReader := func() *configReader {
+		return &configReader{log: log.New("test logger"), orgService: &orgtest.FakeOrgService{ExpectedOrg: &org.Org{ID: 1}}}
+	}
+
+	t.Run("Can read all properties", func(t *testing.T) {
+		cfgs, err := newReader().readConfig(context.Background(), allProperties)
+		require.NoError(t, err)
+		require.Len(t, cfgs, 1)
+
+		cfg := cfgs[0]
+		require.Equal(t, []*folderFromConfig{
+			{OrgID: 1, UID: "platform", Title: "Platform"},
+			{OrgID: 1, UID: "platform-sre", Title: "SRE", ParentUID: "platform"},
+		}, cfg.Folders)
+
+		require.Equal(t, []*teamFromConfig{
+			{OrgID: 1, Name: "SRE", Email: "sre@example.com", Members: []*teamMemberFromConfig{
+				{Login: "alice", Admin: true},
+				{Email: "bob@example.com"},
+			}},
+		}, cfg.Teams)
+
+		require.Equal(t, []*permissionsFromConfig{
+			{OrgID: 1, FolderUID: "platform-sre", Grants: []*grantFromConfig{
+				{Team: "SRE", Permission: "Edit"},
+				{User: "carol", Permission: "Admin"},
+				{Role: "Viewer", Permission: "View"},
+			}},
+			{OrgID: 1, DashboardUID: "overview", Grants: []*grantFromConfig{
+				{Team: "SRE", Permission: "View"},
+			}},
+		}, cfg.Permissions)
+	})
+
+	t.Run("Can read deletions", func(t *testing.T) {
+		cfgs, err := newReader().readConfig(context.Background(), deleteAccess)
+		require.NoError(t, err)
+		require.Len(t, cfgs, 1)
+		require.Equal(t, []*deleteFolderConfig{{OrgID: 1, UID: "platform"}, {OrgID: 1, UID: "missing"}}, cfgs[0].DeleteFolders)
+		require.Equal(t, []*deleteTeamConfig{{OrgID: 1, Name: "SRE"}}, cfgs[0].DeleteTeams)
+		require.Equal(t, []*permissionsFromConfig{
+			{OrgID: 1, FolderUID: "platform-sre", Grants: []*grantFromConfig{{User: "carol"}, {Role: "Viewer"}, {Team: "SRE"}}},
+			{OrgID: 1, DashboardUID: "missing", Grants: []*grantFromConfig{{Role: "Editor"}}},
+		}, cfgs[0].DeletePermissions)
+	})
+
+	t.Run("Grant with several assignees should return error", func(t *testing.T) {
+		_, err := newReader().readConfig(context.Background(), invalidGrant)
+		require.EqualError(t, err, `grant of folder "platform" in configuration should contain one of fields team, user or role`)
+	})
+
+	t.Run("Grant to delete with permission should return error", func(t *testing.T) {
+		_, err := newReader().readConfig(context.Background(), invalidDeleteGrant)
+		require.EqualError(t, err, `grant of folder "platform" to delete in configuration shouldn't contain field permission`)
+	})
+
+	t.Run("Folder provisioned twice should return error", func(t *testing.T) {
+		_, err := newReader().readConfig(context.Background(), duplicateFolder)
+		require.EqualError(t, err, `folder "platform" is provisioned more than once in organization 1`)
+	})
+
+	t.Run("Missing organization should return error", func(t *testing.T) {
+		reader := &configReader{log: log.New("test logger"), orgService: &orgtest.FakeOrgService{ExpectedError: org.ErrOrgNotFound}}
+		_, err := reader.readConfig(context.Background(), allProperties)
+		require.Error(t, err)
+	})
+
+	t.Run("Skip invalid directory", func(t *testing.T) {
+		cfgs, err := newReader().readConfig(context.Background(), "testdata/missing")
+		require.NoError(t, err)
+		require.Len(t, cfgs, 0)
+	})
+}
@@ -0,0 +1,199 @@
+package access
+
+import (
+	"github.com/grafana/grafana/pkg/services/provisioning/values"
+)
+
+// configVersion is used to figure out which API version a config uses.
+type configVersion struct {
+	APIVersion int64 `json:"apiVersion" yaml:"apiVersion"`
+}
+
+type configs struct {
+	APIVersion int64
+
+	Folders           []*folderFromConfig
+	Teams             []*teamFromConfig
+	Permissions       []*permissionsFromConfig
+	DeleteFolders     []*deleteFolderConfig
+	DeleteTeams       []*deleteTeamConfig
+	DeletePermissions []*permissionsFromConfig
+}
+
+type folderFromConfig struct {
+	OrgID       int64
+	UID         string
+	Title       string
+	Description string
+	ParentUID   string
+}
+
+type teamFromConfig struct {
+	OrgID   int64
+	Name    string
+	Email   string
+	Members []*teamMemberFromConfig
+}
+
+type teamMemberFromConfig struct {
+	Login string
+	Email string
+	Admin bool
+}
+
+type permissionsFromConfig struct {
+	OrgID        int64
+	FolderUID    string
+	DashboardUID string
+	Grants       []*grantFromConfig
+}
+
+type grantFromConfig struct {
+	Team       string
+	User       string
+	Role       string
+	Permission string
+}
+
+type deleteFolderConfig struct {
+	OrgID int64
+	UID   string
+}
+
+type deleteTeamConfig struct {
+	OrgID int64
+	Name  string
+}
+
+type configsV1 struct {
+	configVersion
+
+	Folders           []*folderFromConfigV1      `json:"folders" yaml:"folders"`
+	Teams             []*teamFromConfigV1        `json:"teams" yaml:"teams"`
+	Permissions       []*permissionsFromConfigV1 `json:"permissions" yaml:"permissions"`
+	DeleteFolders     []*deleteFolderConfigV1    `json:"deleteFolders" yaml:"deleteFolders"`
+	DeleteTeams       []*deleteTeamConfigV1      `json:"deleteTeams" yaml:"deleteTeams"`
+	DeletePermissions []*permissionsFromConfigV1 `json:"deletePermissions" yaml:"deletePermissions"`
+}
+
+type folderFromConfigV1 struct {
+	OrgID       values.Int64Value  `json:"orgId" yaml:"orgId"`
+	UID         values.StringValue `json:"uid" yaml:"uid"`
+	Title       values.StringValue `json:"title" yaml:"title"`
+	Description values.StringValue `json:"description" yaml:"description"`
+	ParentUID   values.StringValue `json:"parentUid" yaml:"parentUid"`
+}
+
+type teamFromConfigV1 struct {
+	OrgID   values.Int64Value         `json:"orgId" yaml:"orgId"`
+	Name    values.StringValue        `json:"name" yaml:"name"`
+	Email   values.StringValue        `json:"email" yaml:"email"`
+	Members []*teamMemberFromConfigV1 `json:"members" yaml:"members"`
+}
+
+type teamMemberFromConfigV1 struct {
+	Login values.StringValue `json:"login" yaml:"login"`
+	Email values.StringValue `json:"email" yaml:"email"`
+	Admin values.BoolValue   `json:"admin" yaml:"admin"`
+}
+
+type permissionsFromConfigV1 struct {
+	OrgID        values.Int64Value    `json:"orgId" yaml:"orgId"`
+	FolderUID    values.StringValue   `json:"folderUid" yaml:"folderUid"`
+	DashboardUID values.StringValue   `json:"dashboardUid" yaml:"dashboardUid"`
+	Grants       []*grantFromConfigV1 `json:"grants" yaml:"grants"`
+}
+
+type grantFromConfigV1 struct {
+	Team       values.StringValue `json:"team" yaml:"team"`
+	User       values.StringValue `json:"user" yaml:"user"`
+	Role       values.StringValue `json:"role" yaml:"role"`
+	Permission values.StringValue `json:"permission" yaml:"permission"`
+}
+
+type deleteFolderConfigV1 struct {
+	OrgID values.Int64Value  `json:"orgId" yaml:"orgId"`
+	UID   values.StringValue `json:"uid" yaml:"uid"`
+}
+
+type deleteTeamConfigV1 struct {
+	OrgID values.Int64Value  `json:"orgId" yaml:"orgId"`
+	Name  values.StringValue `json:"name" yaml:"name"`
+}
+
+func (cfg *configsV1) mapToAccessFromConfig(apiVersion int64) *configs {
+	r := &configs{}
+
+	r.APIVersion = apiVersion
+
+	if cfg == nil {
+		return r
+	}
+
+	for _, f := range cfg.Folders {
+		r.Folders = append(r.Folders, &folderFromConfig{
+			OrgID:       f.OrgID.Value(),
+			UID:         f.UID.Value(),
+			Title:       f.Title.Value(),
+			Description: f.Description.Value(),
+			ParentUID:   f.ParentUID.Value(),
+		})
+	}
+
+	for _, t := range cfg.Teams {
+		team := &teamFromConfig{
+			OrgID: t.OrgID.Value(),
+			Name:  t.Name.Value(),
+			Email: t.Email.Value(),
+		}
+		for _, m := range t.Members {
+			team.Members = append(team.Members, &teamMemberFromConfig{
+				Login: m.Login.Value(),
+				Email: m.Email.Value(),
+				Admin: m.Admin.Value(),
+			})
+		}
+		r.Teams = append(r.Teams, team)
+	}
+
+	for _, p := range cfg.Permissions {
+		r.Permissions = append(r.Permissions, p.mapToPermissionsFromConfig())
+	}
+
+	for _, f := range cfg.DeleteFolders {
+		r.DeleteFolders = append(r.DeleteFolders, &deleteFolderConfig{
+			OrgID: f.OrgID.Value(),
+			UID:   f.UID.Value(),
+		})
+	}
+
+	for _, t := range cfg.DeleteTeams {
+		r.DeleteTeams = append(r.DeleteTeams, &deleteTeamConfig{
+			OrgID: t.OrgID.Value(),
+			Name:  t.Name.Value(),
+		})
+	}
+
+	for _, p := range cfg.DeletePermissions {
+		r.DeletePermissions = append(r.DeletePermissions, p.mapToPermissionsFromConfig())
+	}
+
+	return r
+}
+
+func (p *permissionsFromConfigV1) mapToPermissionsFromConfig() *permissionsFromConfig {
+	permissions := &permissionsFromConfig{
+		OrgID:        p.OrgID.Value(),
+		FolderUID:    p.FolderUID.Value(),
+		DashboardUID: p.DashboardUID.Value(),
+	}
+	for _, g := range p.Grants {
+		permissions.Grants = append(permissions.Grants, &grantFromConfig{
+			Team:       g.Team.Value(),
+			User:       g.User.Value(),
+			Role:       g.Role.Value(),
+			Permission: g.Permission.Value(),
+		})
+	}
+	return permissions
+}
@@ -0,0 +1,343 @@
+package access
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"github.com/grafana/grafana/pkg/infra/log"
+	"github.com/grafana/grafana/pkg/models"
+	"github.com/grafana/grafana/pkg/services/folder"
+	"github.com/grafana/grafana/pkg/services/org"
+	"github.com/grafana/grafana/pkg/services/provisioning/validation"
+)
+
+type FolderReader interface {
+	Get(ctx context.Context, cmd *folder.GetFolderQuery) (*folder.Folder, error)
+}
+
+type TeamReader interface {
+	SearchTeams(ctx context.Context, query *models.SearchTeamsQuery) error
+	GetTeamMembers(ctx context.Context, query *models.GetTeamMembersQuery) error
+}
+
+type ValidatorConfig struct {
+	Path          string
+	FolderService FolderReader
+	TeamService   TeamReader
+	UserService   UserStore
+	OrgService    org.Service
+	NestedFolders bool
+}
+
+// Validate reads the access provisioning files in the directory without applying them, and returns the problems
+// of the files and the folders and teams that provisioning would create, update or delete.
+func Validate(ctx context.Context, cfg ValidatorConfig) (*validation.Report, error) {
+	cr := &configReader{log: log.New("provisioning.access"), orgService: cfg.OrgService}
+	v := &accessValidator{
+		cfg:            cfg,
+		report:         validation.NewReport("access"),
+		deletedFolders: orgNames{},
+		deletedTeams:   orgNames{},
+		folders:        orgNames{},
+		teams:          orgNames{},
+	}
+
+	files, err := os.ReadDir(cfg.Path)
+	if err != nil {
+		// Access isn't provisioned without the directory
+		return v.report, nil
+	}
+
+	var accessFiles []*accessFile
+	var accessConfigs []*configs
+	for _, file := range files {
+		if !strings.HasSuffix(file.Name(), ".yaml") && !strings.HasSuffix(file.Name(), ".yml") {
+			continue
+		}
+		filename := filepath.Join(cfg.Path, file.Name())
+
+		c, err := cr.parseAccessConfig(cfg.Path, file)
+		if err != nil {
+			v.report.AddProblem(filename, 0, err)
+			continue
+		}
+		if c == nil {
+			continue
+		}
+		// Each file is validated with the valid files before it, to find folders and teams declared twice
+		if err := cr.validate(ctx, append(accessConfigs, c)); err != nil {
+			v.report.AddProblem(filename, 0, err)
+			continue
+		}
+
+		// nolint:gosec
+		// We can ignore the gosec G304 warning on this one because `filename` was already read by the config reader
+		data, err := os.ReadFile(filename)
+		if err != nil {
+			return nil, err
+		}
+		accessConfigs = append(accessConfigs, c)
+		accessFiles = append(accessFiles, &accessFile{name: filename, data: data, cfg: c})
+	}
+
+	// The files are checked in the order provisioning applies them
+	var folders []*folderFromConfig
+	for _, f := range accessFiles {
+		if err := v.validateDeletions(ctx, f); err != nil {
+			return nil, err
+		}
+		folders = append(folders, f.cfg.Folders...)
+	}
+	if _, err := sortFolders(folders); err != nil {
+		v.report.AddProblem(cfg.Path, 0, err)
+	}
+	for _, f := range accessFiles {
+		for _, folder := range f.cfg.Folders {
+			v.folders.add(folder.OrgID, folder.UID)
+		}
+		for _, t := range f.cfg.Teams {
+			v.teams.add(t.OrgID, t.Name)
+		}
+	}
+	for _, f := range accessFiles {
+		if err := v.validateFolders(ctx, f); err != nil {
+			return nil, err
+		}
+		if err := v.validateTeams(ctx, f); err != nil {
+			return nil, err
+		}
+	}
+	for _, f := range accessFiles {
+		for _, p := range f.cfg.DeletePermissions {
+			if err := v.validatePermissions(ctx, f, p, true); err != nil {
+				return nil, err
+			}
+		}
+		for _, p := range f.cfg.Permissions {
+			if err := v.validatePermissions(ctx, f, p, false); err != nil {
+				return nil, err
+			}
+		}
+	}
+
+	return v.report, nil
+}
+
+type accessFile struct {
+	name string
+	data []byte
+	cfg  *configs
+}
+
+// orgNames are folder UIDs or team names by organization.
+type orgNames map[int64]map[string]bool
+
+func (n orgNames) add(orgID int64, name string) {
+	if n[orgID] == nil {
+		n[orgID] = map[string]bool{}
+	}
+	n[orgID][name] = true
+}
+
+func (n orgNames) has(orgID int64, name string) bool {
+	return n[orgID][name]
+}
+
+type accessValidator struct {
+	cfg    ValidatorConfig
+	report *validation.Report
+
+	// deletedFolders and deletedTeams are deleted by provisioning, and folders and teams are created or updated.
+	deletedFolders orgNames
+	deletedTeams   orgNames
+	folders        orgNames
+	teams          orgNames
+}
+
+func (v *accessValidator) validateDeletions(ctx context.Context, f *accessFile) error {
+	for _, d := range f.cfg.DeleteFolders {
+		existing, err := v.getFolder(ctx, d.OrgID, d.UID)
+		if err != nil {
+			return err
+		}
+		if existing != nil {
+			v.report.AddChange(validation.ActionDelete, "folder", d.OrgID, d.UID, f.name)
+			v.deletedFolders.add(d.OrgID, d.UID)
+		}
+	}
+
+	for _, d := range f.cfg.DeleteTeams {
+		existing, err := getTeam(ctx, v.cfg.TeamService, d.OrgID, d.Name)
+		if err != nil {
+			return err
+		}
+		if existing != nil {
+			v.report.AddChange(validation.ActionDelete, "team", d.OrgID, d.Name, f.name)
+			v.deletedTeams.add(d.OrgID, d.Name)
+		}
+	}
+
+	return nil
+}
+
+func (v *accessValidator) validateFolders(ctx context.Context, f *accessFile) error {
+	for _, folder := range f.cfg.Folders {
+		parentUID := folder.ParentUID
+		if !v.cfg.NestedFolders {
+			parentUID = ""
+		}
+		if parentUID != "" {
+			parent, err := v.getFolder(ctx, folder.OrgID, parentUID)
+			if err != nil {
+				return err
+			}
+			if parent == nil && !v.folders.has(folder.OrgID, parentUID) {
+				v.report.AddProblem(f.name, validation.Line(f.data, "uid", folder.UID), fmt.Errorf("parent folder %q of folder %q not found", parentUID, folder.UID))
+				continue
+			}
+		}
+
+		existing, err := v.getFolder(ctx, folder.OrgID, folder.UID)
+		if err != nil {
+			return err
+		}
+		if existing == nil {
+			v.report.AddChange(validation.ActionCreate, "folder", folder.OrgID, folder.UID, f.name)
+			continue
+		}
+		var fields validation.Fields
+		fields.Add("title", existing.Title != folder.Title)
+		if v.cfg.NestedFolders {
+			fields.Add("description", existing.Description != folder.Description)
+			fields.Add("parentUid", existing.ParentUID != parentUID)
+		}
+		v.report.AddUpdate("folder", folder.OrgID, folder.UID, f.name, fields)
+	}
+
+	return nil
+}
+
+func (v *accessValidator) validateTeams(ctx context.Context, f *accessFile) error {
+	for _, t := range f.cfg.Teams {
+		line := validation.Line(f.data, "name", t.Name)
+		members := map[int64]models.PermissionType{}
+		for _, m := range t.Members {
+			u, err := getUser(ctx, v.cfg.UserService, m.Login, m.Email)
+			if err != nil {
+				return err
+			}
+			if u == nil {
+				name := m.Login
+				if name == "" {
+					name = m.Email
+				}
+				v.report.AddProblem(f.name, line, fmt.Errorf("member %q of team %q not found, the member is skipped", name, t.Name))
+				continue
+			}
+			members[u.ID] = 0
+			if m.Admin {
+				members[u.ID] = models.PERMISSION_ADMIN
+			}
+		}
+
+		existing, err := getTeam(ctx, v.cfg.TeamService, t.OrgID, t.Name)
+		if err != nil {
+			return err
+		}
+		if existing == nil || v.deletedTeams.has(t.OrgID, t.Name) {
+			v.report.AddChange(validation.ActionCreate, "team", t.OrgID, t.Name, f.name)
+			continue
+		}
+
+		var fields validation.Fields
+		fields.Add("email", existing.Email != t.Email)
+		changed, err := v.teamMembersChanged(ctx, t.OrgID, existing.Id, members)
+		if err != nil {
+			return err
+		}
+		fields.Add("members", changed)
+		v.report.AddUpdate("team", t.OrgID, t.Name, f.name, fields)
+	}
+
+	return nil
+}
+
+// teamMembersChanged returns whether the members of the team differ from the members in the config, except for
+// the members added by team sync, that provisioning leaves unchanged.
+func (v *accessValidator) teamMembersChanged(ctx context.Context, orgID, teamID int64, members map[int64]models.PermissionType) (bool, error) {
+	query := &models.GetTeamMembersQuery{OrgId: orgID, TeamId: teamID, SignedInUser: provisionerUser(orgID)}
+	if err := v.cfg.TeamService.GetTeamMembers(ctx, query); err != nil {
+		return false, err
+	}
+
+	current := 0
+	for _, m := range query.Result {
+		if m.External {
+			continue
+		}
+		if permission, ok := members[m.UserId]; !ok || permission != m.Permission {
+			return true, nil
+		}
+		current++
+	}
+	return current != len(members), nil
+}
+
+// validatePermissions checks that the folder and the teams of the grants exist or are provisioned, and that
+// the users of the grants exist. Grants to delete only need to exist if they're still there.
+func (v *accessValidator) validatePermissions(ctx context.Context, f *accessFile, p *permissionsFromConfig, remove bool) error {
+	line := validation.Line(f.data, "dashboardUid", p.DashboardUID)
+	if p.FolderUID != "" {
+		line = validation.Line(f.data, "folderUid", p.FolderUID)
+
+		existing, err := v.getFolder(ctx, p.OrgID, p.FolderUID)
+		if err != nil {
+			return err
+		}
+		if existing == nil && !v.folders.has(p.OrgID, p.FolderUID) {
+			if !remove {
+				v.report.AddProblem(f.name, line, fmt.Errorf("failed to provision permissions of %s: folder not found", p.resource()))
+			}
+			return nil
+		}
+	}
+
+	for _, g := range p.Grants {
+		switch {
+		case g.Team != "":
+			existing, err := getTeam(ctx, v.cfg.TeamService, p.OrgID, g.Team)
+			if err != nil {
+				return err
+			}
+			if (existing == nil || v.deletedTeams.has(p.OrgID, g.Team)) && !v.teams.has(p.OrgID, g.Team) && !remove {
+				v.report.AddProblem(f.name, line, fmt.Errorf("failed to provision permissions of %s: team %q not found", p.resource(), g.Team))
+			}
+		case g.User != "":
+			u, err := getUser(ctx, v.cfg.UserService, g.User, "")
+			if err != nil {
+				return err
+			}
+			if u == nil && !remove {
+				v.report.AddProblem(f.name, line, fmt.Errorf("user %q of permissions of %s not found, the permission is skipped", g.User, p.resource()))
+			}
+		}
+	}
+
+	return nil
+}
+
+// getFolder returns the folder with the UID in the organization, or nil if there is none or provisioning
+// deletes it.
+func (v *accessValidator) getFolder(ctx context.Context, orgID int64, uid string) (*folder.Folder, error) {
+	if v.deletedFolders.has(orgID, uid) {
+		return nil, nil
+	}
+	existing, err := v.cfg.FolderService.Get(ctx, &folder.GetFolderQuery{UID: &uid, OrgID: orgID, SignedInUser: provisionerUser(orgID)})
+	if isFolderNotFound(err) {
+		return nil, nil
+	}
+	return existing, err
+}
@@ -0,0 +1,127 @@
+package access
+
+import (
+	"context"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/grafana/grafana/pkg/models"
+	"github.com/grafana/grafana/pkg/services/folder"
+	"github.com/grafana/grafana/pkg/services/org"
+	"github.com/grafana/grafana/pkg/services/org/orgtest"
+	"github.com/grafana/grafana/pkg/services/provisioning/validation"
+	"github.com/grafana/grafana/pkg/services/user"
+)
+
+func TestValidate(t *testing.T) {
+	setup := func(path string) (ValidatorConfig, *fakeFolderStore, *fakeTeamStore) {
+		folders := &fakeFolderStore{folders: map[string]*folder.Folder{}}
+		teams := &fakeTeamStore{members: map[int64][]*models.TeamMemberDTO{}}
+		cfg := ValidatorConfig{
+			Path:          path,
+			FolderService: folders,
+			TeamService:   teams,
+			UserService: &fakeUserStore{users: []*user.User{
+				{ID: 1, Login: "alice", Email: "alice@example.com"},
+				{ID: 2, Login: "bob", Email: "bob@example.com"},
+				{ID: 3, Login: "carol", Email: "carol@example.com"},
+			}},
+			OrgService: &orgtest.FakeOrgService{ExpectedOrg: &org.Org{ID: 1}},
+		}
+		return cfg, folders, teams
+	}
+
+	t.Run("should return the changes without applying them", func(t *testing.T) {
+		cfg, folders, teams := setup(allProperties)
+		folders.folders["platform-sre"] = &folder.Folder{OrgID: 1, UID: "platform-sre", Title: "Old"}
+		report, err := Validate(context.Background(), cfg)
+		require.NoError(t, err)
+
+		file := filepath.Join(allProperties, "access.yaml")
+		assert.True(t, report.Valid())
+		assert.ElementsMatch(t, []validation.Change{
+			{Action: validation.ActionCreate, Kind: "folder", OrgID: 1, Name: "platform", File: file},
+			{Action: validation.ActionUpdate, Kind: "folder", OrgID: 1, Name: "platform-sre", File: file, Fields: []string{"title"}},
+			{Action: validation.ActionCreate, Kind: "team", OrgID: 1, Name: "SRE", File: file},
+		}, report.Changes)
+
+		assert.Empty(t, folders.calls)
+		assert.Empty(t, teams.calls)
+	})
+
+	t.Run("should not return changes for provisioned folders and teams", func(t *testing.T) {
+		cfg, folders, teams := setup(allProperties)
+		folders.folders["platform"] = &folder.Folder{OrgID: 1, UID: "platform", Title: "Platform"}
+		folders.folders["platform-sre"] = &folder.Folder{OrgID: 1, UID: "platform-sre", Title: "SRE"}
+		teams.teams = []*models.TeamDTO{{Id: 1, OrgId: 1, Name: "SRE", Email: "sre@example.com"}}
+		teams.members[1] = []*models.TeamMemberDTO{
+			{OrgId: 1, TeamId: 1, UserId: 1, Login: "alice", Permission: models.PERMISSION_ADMIN},
+			{OrgId: 1, TeamId: 1, UserId: 2, Login: "bob"},
+			{OrgId: 1, TeamId: 1, UserId: 4, Login: "dave", External: true},
+		}
+		report, err := Validate(context.Background(), cfg)
+		require.NoError(t, err)
+
+		assert.True(t, report.Valid())
+		assert.Empty(t, report.Changes)
+
+		t.Run("should return an update when the members differ", func(t *testing.T) {
+			teams.members[1] = teams.members[1][:1]
+			report, err := Validate(context.Background(), cfg)
+			require.NoError(t, err)
+
+			assert.Equal(t, []validation.Change{
+				{Action: validation.ActionUpdate, Kind: "team", OrgID: 1, Name: "SRE", File: filepath.Join(allProperties, "access.yaml"), Fields: []string{"members"}},
+			}, report.Changes)
+		})
+	})
+
+	t.Run("should report missing users", func(t *testing.T) {
+		cfg, _, _ := setup(allProperties)
+		cfg.UserService = &fakeUserStore{}
+		report, err := Validate(context.Background(), cfg)
+		require.NoError(t, err)
+
+		require.Len(t, report.Problems, 3)
+		assert.Equal(t, 12, report.Problems[0].Line)
+		assert.Equal(t, 20, report.Problems[2].Line)
+	})
+
+	t.Run("should return deletions", func(t *testing.T) {
+		cfg, folders, teams := setup(deleteAccess)
+		folders.folders["platform"] = &folder.Folder{OrgID: 1, UID: "platform", Title: "Platform"}
+		folders.folders["platform-sre"] = &folder.Folder{OrgID: 1, UID: "platform-sre", Title: "SRE"}
+		teams.teams = []*models.TeamDTO{{Id: 1, OrgId: 1, Name: "SRE"}}
+		report, err := Validate(context.Background(), cfg)
+		require.NoError(t, err)
+
+		file := filepath.Join(deleteAccess, "access.yaml")
+		assert.True(t, report.Valid(), "permissions of deleted teams and dashboards can be deleted")
+		assert.ElementsMatch(t, []validation.Change{
+			{Action: validation.ActionDelete, Kind: "folder", OrgID: 1, Name: "platform", File: file},
+			{Action: validation.ActionDelete, Kind: "team", OrgID: 1, Name: "SRE", File: file},
+		}, report.Changes)
+		assert.Empty(t, folders.calls)
+		assert.Empty(t, teams.calls)
+	})
+
+	t.Run("should report invalid files", func(t *testing.T) {
+		for _, path := range []string{invalidGrant, invalidDeleteGrant, duplicateFolder, circularFolders} {
+			cfg, _, _ := setup(path)
+			report, err := Validate(context.Background(), cfg)
+			require.NoError(t, err)
+			assert.Len(t, report.Problems, 1, path)
+		}
+	})
+
+	t.Run("should return an empty report without the directory", func(t *testing.T) {
+		cfg, _, _ := setup("testdata/missing")
+		report, err := Validate(context.Background(), cfg)
+		require.NoError(t, err)
+		assert.True(t, report.Valid())
+		assert.Empty(t, report.Changes)
+	})
+}
@@ -5,6 +5,8 @@ import (
 	"fmt"
 	"strings"
 
+	"github.com/prometheus/common/model"
+
 	"github.com/grafana/grafana/pkg/components/simplejson"
 	"github.com/grafana/grafana/pkg/services/ngalert/api/tooling/definitions"
 	"github.com/grafana/grafana/pkg/services/ngalert/models"
@@ -69,6 +71,34 @@ type ReceiverV1 struct {
 	Type                  values.StringValue `json:"type" yaml:"type"`
 	Settings              values.JSONValue   `json:"settings" yaml:"settings"`
 	DisableResolveMessage values.BoolValue   `json:"disableResolveMessage" yaml:"disableResolveMessage"`
+	RetryPolicy           *RetryPolicyV1     `json:"retryPolicy" yaml:"retryPolicy"`
+}
+
+type RetryPolicyV1 struct {
+	MaxAttempts     values.IntValue    `json:"maxAttempts" yaml:"maxAttempts"`
+	InitialInterval values.StringValue `json:"initialInterval" yaml:"initialInterval"`
+	MaxInterval     values.StringValue `json:"maxInterval" yaml:"maxInterval"`
+}
+
+func (config *RetryPolicyV1) mapToModel() (*definitions.RetryPolicy, error) {
+	policy := &definitions.RetryPolicy{
+		MaxAttempts: config.MaxAttempts.Value(),
+	}
+	if v := strings.TrimSpace(config.InitialInterval.Value()); v != "" {
+		d, err := model.ParseDuration(v)
+		if err != nil {
+			return nil, fmt.Errorf("invalid initialInterval: %w", err)
+		}
+		policy.InitialInterval = d
+	}
+	if v := strings.TrimSpace(config.MaxInterval.Value()); v != "" {
+		d, err := model.ParseDuration(v)
+		if err != nil {
+			return nil, fmt.Errorf("invalid maxInterval: %w", err)
+		}
+		policy.MaxInterval = d
+	}
+	return policy, nil
 }
 
 func (config *ReceiverV1) mapToModel(name string) (definitions.EmbeddedContactPoint, error) {
@@ -92,6 +122,13 @@ func (config *ReceiverV1) mapToModel(name string) (definitions.EmbeddedContactPo
 		Provenance:            string(models.ProvenanceFile),
 		Settings:              settings,
 	}
+	if config.RetryPolicy != nil {
+		retryPolicy, err := config.RetryPolicy.mapToModel()
+		if err != nil {
+			return definitions.EmbeddedContactPoint{}, err
+		}
+		cp.RetryPolicy = retryPolicy
+	}
 	// As the values are not encrypted when coming from disk files,
 	// we can simply return the fallback for validation.
 	err := cp.Valid(func(_ context.Context, _ map[string][]byte, _, fallback string) string {
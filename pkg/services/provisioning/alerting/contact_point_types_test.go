@@ -2,7 +2,11 @@ package alerting
 
 import (
 	"testing"
+	"time"
 
+	"github.com/prometheus/common/model"
+
+	"github.com/grafana/grafana/pkg/services/ngalert/api/tooling/definitions"
 	"github.com/grafana/grafana/pkg/services/provisioning/values"
 	"github.com/stretchr/testify/require"
 	"gopkg.in/yaml.v3"
@@ -14,6 +18,26 @@ func TestReceivers(t *testing.T) {
 		_, err := cp.mapToModel("test")
 		require.NoError(t, err)
 	})
+	t.Run("Retry policy should be mapped", func(t *testing.T) {
+		cp := validReceiverV1(t)
+		cp.RetryPolicy = &RetryPolicyV1{}
+		err := yaml.Unmarshal([]byte("5"), &cp.RetryPolicy.MaxAttempts)
+		require.NoError(t, err)
+		err = yaml.Unmarshal([]byte("10s"), &cp.RetryPolicy.InitialInterval)
+		require.NoError(t, err)
+		m, err := cp.mapToModel("test")
+		require.NoError(t, err)
+		require.Equal(t, &definitions.RetryPolicy{
+			MaxAttempts:     5,
+			InitialInterval: model.Duration(10 * time.Second),
+		}, m.RetryPolicy)
+	})
+	t.Run("Invalid retry policy should error on mapping", func(t *testing.T) {
+		cp := validReceiverV1(t)
+		cp.RetryPolicy = &RetryPolicyV1{}
+		_, err := cp.mapToModel("test")
+		require.Error(t, err)
+	})
 	t.Run("Invalid config should error on mapping", func(t *testing.T) {
 		cp := validReceiverV1(t)
 		var settings values.JSONValue
@@ -161,6 +161,88 @@ func TestRules(t *testing.T) {
 		require.NoError(t, err)
 		require.Equal(t, ruleMapped.ExecErrState, models.OkErrState)
 	})
+	t.Run("a rule with out isPaused should not be paused", func(t *testing.T) {
+		rule := validRuleV1(t)
+		ruleMapped, err := rule.mapToModel(1)
+		require.NoError(t, err)
+		require.False(t, ruleMapped.IsPaused)
+	})
+	t.Run("a rule with isPaused should map it correctly", func(t *testing.T) {
+		rule := validRuleV1(t)
+		isPaused := values.BoolValue{}
+		err := yaml.Unmarshal([]byte("true"), &isPaused)
+		require.NoError(t, err)
+		rule.IsPaused = isPaused
+		ruleMapped, err := rule.mapToModel(1)
+		require.NoError(t, err)
+		require.True(t, ruleMapped.IsPaused)
+	})
+	t.Run("a rule with out keepFiringFor should not keep firing", func(t *testing.T) {
+		rule := validRuleV1(t)
+		ruleMapped, err := rule.mapToModel(1)
+		require.NoError(t, err)
+		require.Equal(t, time.Duration(0), ruleMapped.KeepFiringFor)
+	})
+	t.Run("a rule with keepFiringFor should map it correctly", func(t *testing.T) {
+		rule := validRuleV1(t)
+		keepFiringFor := values.StringValue{}
+		err := yaml.Unmarshal([]byte("10m"), &keepFiringFor)
+		require.NoError(t, err)
+		rule.KeepFiringFor = keepFiringFor
+		ruleMapped, err := rule.mapToModel(1)
+		require.NoError(t, err)
+		require.Equal(t, 10*time.Minute, ruleMapped.KeepFiringFor)
+	})
+	t.Run("a rule with invalid keepFiringFor should error", func(t *testing.T) {
+		rule := validRuleV1(t)
+		keepFiringFor := values.StringValue{}
+		err := yaml.Unmarshal([]byte("abc"), &keepFiringFor)
+		require.NoError(t, err)
+		rule.KeepFiringFor = keepFiringFor
+		_, err = rule.mapToModel(1)
+		require.Error(t, err)
+	})
+	t.Run("a rule with out record should not be a recording rule", func(t *testing.T) {
+		rule := validRuleV1(t)
+		ruleMapped, err := rule.mapToModel(1)
+		require.NoError(t, err)
+		require.Nil(t, ruleMapped.Record)
+	})
+	t.Run("a rule with record should map it correctly and use the recorded query as condition", func(t *testing.T) {
+		rule := validRuleV1(t)
+		rule.Condition = values.StringValue{}
+		record := RecordV1{}
+		err := yaml.Unmarshal([]byte("metric: test_metric\nfrom: A"), &record)
+		require.NoError(t, err)
+		rule.Record = &record
+		err = yaml.Unmarshal([]byte("A"), &rule.Data[0].RefID)
+		require.NoError(t, err)
+		ruleMapped, err := rule.mapToModel(1)
+		require.NoError(t, err)
+		require.Equal(t, &models.Record{Metric: "test_metric", From: "A"}, ruleMapped.Record)
+		require.Equal(t, "A", ruleMapped.Condition)
+	})
+	t.Run("a recording rule with a condition different from the recorded query should error", func(t *testing.T) {
+		rule := validRuleV1(t)
+		record := RecordV1{}
+		err := yaml.Unmarshal([]byte("metric: test_metric\nfrom: B"), &record)
+		require.NoError(t, err)
+		rule.Record = &record
+		_, err = rule.mapToModel(1)
+		require.ErrorContains(t, err, "must be the same as the query or expression it records")
+	})
+	t.Run("a recording rule with an invalid metric name should error", func(t *testing.T) {
+		rule := validRuleV1(t)
+		rule.Condition = values.StringValue{}
+		record := RecordV1{}
+		err := yaml.Unmarshal([]byte("metric: test-metric\nfrom: A"), &record)
+		require.NoError(t, err)
+		rule.Record = &record
+		err = yaml.Unmarshal([]byte("A"), &rule.Data[0].RefID)
+		require.NoError(t, err)
+		_, err = rule.mapToModel(1)
+		require.ErrorContains(t, err, "not a valid Prometheus metric name")
+	})
 	t.Run("a rule with out noDataState should have sane defaults", func(t *testing.T) {
 		rule := validRuleV1(t)
 		ruleMapped, err := rule.mapToModel(1)
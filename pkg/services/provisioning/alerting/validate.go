@@ -0,0 +1,400 @@
+package alerting
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+
+	"github.com/grafana/grafana/pkg/infra/log"
+	"github.com/grafana/grafana/pkg/infra/slugify"
+	legacymodels "github.com/grafana/grafana/pkg/models"
+	"github.com/grafana/grafana/pkg/services/dashboards"
+	"github.com/grafana/grafana/pkg/services/ngalert/api/tooling/definitions"
+	"github.com/grafana/grafana/pkg/services/ngalert/models"
+	"github.com/grafana/grafana/pkg/services/ngalert/provisioning"
+	"github.com/grafana/grafana/pkg/services/provisioning/validation"
+)
+
+type RuleReader interface {
+	GetAlertRule(ctx context.Context, orgID int64, ruleUID string) (models.AlertRule, models.Provenance, error)
+}
+
+type ContactPointReader interface {
+	GetContactPoints(ctx context.Context, q provisioning.ContactPointQuery) ([]definitions.EmbeddedContactPoint, error)
+}
+
+type MuteTimingReader interface {
+	GetMuteTimings(ctx context.Context, orgID int64) ([]definitions.MuteTimeInterval, error)
+}
+
+type TemplateReader interface {
+	GetTemplates(ctx context.Context, orgID int64) (map[string]string, error)
+}
+
+type PolicyReader interface {
+	GetPolicyTree(ctx context.Context, orgID int64) (definitions.Route, error)
+}
+
+type FolderReader interface {
+	GetDashboard(ctx context.Context, query *legacymodels.GetDashboardQuery) error
+}
+
+type ValidatorConfig struct {
+	Path                string
+	RuleService         RuleReader
+	FolderService       FolderReader
+	ContactPointService ContactPointReader
+	MuteTimingService   MuteTimingReader
+	TemplateService     TemplateReader
+	PolicyService       PolicyReader
+	// RecordingRulesEnabled is whether recording rules can be provisioned.
+	RecordingRulesEnabled bool
+	// DefaultConfiguration is the default Alertmanager configuration, whose policy tree resets the policies.
+	DefaultConfiguration string
+}
+
+// Validate reads the alerting provisioning files in the directory without applying them, and returns the problems
+// of the files and the alerting resources that provisioning would create, update or delete.
+func Validate(ctx context.Context, cfg ValidatorConfig) (*validation.Report, error) {
+	cr := newRulesConfigReader(log.New("provisioning.alerting"))
+	v := &alertingValidator{
+		cfg:           cfg,
+		report:        validation.NewReport("alerting"),
+		ruleFiles:     map[string]string{},
+		folders:       map[int64]map[string]string{},
+		contactPoints: map[int64]map[string]definitions.EmbeddedContactPoint{},
+		muteTimings:   map[int64]map[string]definitions.MuteTimeInterval{},
+		templates:     map[int64]map[string]string{},
+	}
+
+	files, err := os.ReadDir(cfg.Path)
+	if err != nil {
+		// Alerting isn't provisioned without the directory
+		return v.report, nil
+	}
+
+	for _, file := range files {
+		if !cr.isYAML(file.Name()) && !cr.isJSON(file.Name()) {
+			continue
+		}
+		filename := filepath.Join(cfg.Path, file.Name())
+
+		alertFileV1, err := cr.parseConfig(cfg.Path, file)
+		if err != nil {
+			v.report.AddProblem(filename, 0, err)
+			continue
+		}
+		if alertFileV1 == nil {
+			continue
+		}
+		alertFile, err := alertFileV1.MapToModel()
+		if err != nil {
+			v.report.AddProblem(filename, 0, err)
+			continue
+		}
+
+		if err := v.validateFile(ctx, filename, &alertFile); err != nil {
+			return nil, err
+		}
+	}
+
+	return v.report, nil
+}
+
+type alertingValidator struct {
+	cfg    ValidatorConfig
+	report *validation.Report
+
+	// ruleFiles are the files of the rule UIDs, folders are the UIDs of the folders of each organization by title,
+	// and the other maps are the existing resources of each organization by name or UID.
+	ruleFiles     map[string]string
+	folders       map[int64]map[string]string
+	contactPoints map[int64]map[string]definitions.EmbeddedContactPoint
+	muteTimings   map[int64]map[string]definitions.MuteTimeInterval
+	templates     map[int64]map[string]string
+}
+
+func (v *alertingValidator) validateFile(ctx context.Context, filename string, file *AlertingFile) error {
+	for _, group := range file.Groups {
+		for _, rule := range group.Rules {
+			if other, ok := v.ruleFiles[rule.UID]; ok {
+				v.report.AddProblem(filename, 0, fmt.Errorf("alert rule uid %q is already provisioned by %s", rule.UID, other))
+				continue
+			}
+			v.ruleFiles[rule.UID] = filename
+			if rule.IsRecordingRule() && !v.cfg.RecordingRulesEnabled {
+				v.report.AddProblem(filename, 0, fmt.Errorf("alert rule %q: %w", rule.Title, models.ErrRecordingRulesDisabled))
+				continue
+			}
+
+			existing, err := v.getRule(ctx, group.OrgID, rule.UID)
+			if err != nil {
+				return err
+			}
+			if existing == nil {
+				v.report.AddChange(validation.ActionCreate, "alert rule", group.OrgID, rule.Title, filename)
+				continue
+			}
+			folderUID, err := v.folderUID(ctx, group.OrgID, group.Folder)
+			if err != nil {
+				return err
+			}
+			v.report.AddUpdate("alert rule", group.OrgID, rule.Title, filename, ruleFields(group, folderUID, rule, existing))
+		}
+	}
+	for _, deleteRule := range file.DeleteRules {
+		existing, err := v.getRule(ctx, deleteRule.OrgID, deleteRule.UID)
+		if err != nil {
+			return err
+		}
+		if existing != nil {
+			v.report.AddChange(validation.ActionDelete, "alert rule", deleteRule.OrgID, deleteRule.UID, filename)
+		}
+	}
+
+	for _, contactPointsConfig := range file.ContactPoints {
+		existing, err := v.existingContactPoints(ctx, contactPointsConfig.OrgID)
+		if err != nil {
+			return err
+		}
+		for _, contactPoint := range contactPointsConfig.ContactPoints {
+			stored, ok := existing[contactPoint.UID]
+			if !ok {
+				v.report.AddChange(validation.ActionCreate, "contact point", contactPointsConfig.OrgID, contactPoint.Name, filename)
+				continue
+			}
+			var fields validation.Fields
+			fields.Add("name", stored.Name != contactPoint.Name)
+			fields.Add("type", stored.Type != contactPoint.Type)
+			fields.Add("disableResolveMessage", stored.DisableResolveMessage != contactPoint.DisableResolveMessage)
+			fields.Compare("settings", contactPoint.Settings, stored.Settings)
+			fields.Compare("retryPolicy", contactPoint.RetryPolicy, stored.RetryPolicy)
+			v.report.AddUpdate("contact point", contactPointsConfig.OrgID, contactPoint.Name, filename, fields)
+		}
+	}
+	for _, cp := range file.DeleteContactPoints {
+		existing, err := v.existingContactPoints(ctx, cp.OrgID)
+		if err != nil {
+			return err
+		}
+		if _, ok := existing[cp.UID]; ok {
+			v.report.AddChange(validation.ActionDelete, "contact point", cp.OrgID, cp.UID, filename)
+		}
+	}
+
+	for _, muteTime := range file.MuteTimes {
+		existing, err := v.existingMuteTimings(ctx, muteTime.OrgID)
+		if err != nil {
+			return err
+		}
+		stored, ok := existing[muteTime.MuteTime.Name]
+		if !ok {
+			v.report.AddChange(validation.ActionCreate, "mute timing", muteTime.OrgID, muteTime.MuteTime.Name, filename)
+			continue
+		}
+		var fields validation.Fields
+		fields.Compare("time_intervals", muteTime.MuteTime.TimeIntervals, stored.TimeIntervals)
+		v.report.AddUpdate("mute timing", muteTime.OrgID, muteTime.MuteTime.Name, filename, fields)
+	}
+	for _, muteTime := range file.DeleteMuteTimes {
+		existing, err := v.existingMuteTimings(ctx, muteTime.OrgID)
+		if err != nil {
+			return err
+		}
+		if _, ok := existing[muteTime.Name]; ok {
+			v.report.AddChange(validation.ActionDelete, "mute timing", muteTime.OrgID, muteTime.Name, filename)
+		}
+	}
+
+	for _, template := range file.Templates {
+		existing, err := v.existingTemplates(ctx, template.OrgID)
+		if err != nil {
+			return err
+		}
+		stored, ok := existing[template.Data.Name]
+		if !ok {
+			v.report.AddChange(validation.ActionCreate, "template", template.OrgID, template.Data.Name, filename)
+			continue
+		}
+		var fields validation.Fields
+		fields.Add("template", stored != template.Data.Template)
+		v.report.AddUpdate("template", template.OrgID, template.Data.Name, filename, fields)
+	}
+	for _, template := range file.DeleteTemplates {
+		existing, err := v.existingTemplates(ctx, template.OrgID)
+		if err != nil {
+			return err
+		}
+		if _, ok := existing[template.Name]; ok {
+			v.report.AddChange(validation.ActionDelete, "template", template.OrgID, template.Name, filename)
+		}
+	}
+
+	// The notification policy tree of an organization always exists, so it's only ever updated
+	for _, policy := range file.Policies {
+		if err := v.validatePolicyTree(ctx, filename, policy.OrgID, policy.Policy); err != nil {
+			return err
+		}
+	}
+	if len(file.ResetPolicies) > 0 {
+		defaultPolicy, err := v.defaultPolicyTree()
+		if err != nil {
+			return err
+		}
+		for _, orgID := range file.ResetPolicies {
+			if err := v.validatePolicyTree(ctx, filename, int64(orgID), defaultPolicy); err != nil {
+				return err
+			}
+		}
+	}
+
+	return nil
+}
+
+// ruleFields returns the fields of the stored rule that differ from the provisioned rule of the group.
+func ruleFields(group AlertRuleGroup, folderUID string, rule models.AlertRule, existing *models.AlertRule) validation.Fields {
+	// The queries are stored with the defaults that are set in their model before they're saved. The queries that
+	// can't be saved are compared as they are, as provisioning fails to save them anyway.
+	data := make([]models.AlertQuery, len(rule.Data))
+	for i, q := range rule.Data {
+		if err := q.PreSave(); err != nil {
+			q = rule.Data[i]
+		}
+		data[i] = q
+	}
+	provisioned := rule
+	provisioned.Data = data
+
+	var fields validation.Fields
+	fields.Add("folder", existing.NamespaceUID != folderUID)
+	fields.Add("group", existing.RuleGroup != group.Name)
+	fields.Add("interval", existing.IntervalSeconds != int64(group.Interval.Seconds()))
+	fields.Add("title", existing.Title != provisioned.Title)
+	fields.Add("condition", existing.Condition != provisioned.Condition)
+	fields.Compare("data", provisioned.Data, existing.Data)
+	fields.Compare("dashboardUid", provisioned.DashboardUID, existing.DashboardUID)
+	fields.Add("panelId", panelID(existing.PanelID) != panelID(provisioned.PanelID))
+	fields.Add("noDataState", existing.NoDataState != provisioned.NoDataState)
+	fields.Add("execErrState", existing.ExecErrState != provisioned.ExecErrState)
+	fields.Add("for", existing.For != provisioned.For)
+	fields.Add("keepFiringFor", existing.KeepFiringFor != provisioned.KeepFiringFor)
+	fields.Compare("annotations", provisioned.Annotations, existing.Annotations)
+	fields.Compare("labels", provisioned.Labels, existing.Labels)
+	fields.Add("isPaused", existing.IsPaused != provisioned.IsPaused)
+	fields.Compare("record", provisioned.Record, existing.Record)
+	return fields
+}
+
+func panelID(id *int64) int64 {
+	if id == nil {
+		return 0
+	}
+	return *id
+}
+
+func (v *alertingValidator) validatePolicyTree(ctx context.Context, filename string, orgID int64, policy definitions.Route) error {
+	stored, err := v.cfg.PolicyService.GetPolicyTree(ctx, orgID)
+	if err != nil {
+		return err
+	}
+	// The provenance isn't part of the policy tree in the config files
+	stored.Provenance = ""
+	policy.Provenance = ""
+
+	var fields validation.Fields
+	fields.Compare("policy", policy, stored)
+	v.report.AddUpdate("notification policy", orgID, "notification policy tree", filename, fields)
+	return nil
+}
+
+func (v *alertingValidator) defaultPolicyTree() (definitions.Route, error) {
+	var defaultCfg definitions.PostableUserConfig
+	if err := json.Unmarshal([]byte(v.cfg.DefaultConfiguration), &defaultCfg); err != nil {
+		return definitions.Route{}, fmt.Errorf("failed to parse default alertmanager config: %w", err)
+	}
+	if defaultCfg.AlertmanagerConfig.Route == nil {
+		return definitions.Route{}, fmt.Errorf("no route present in default alertmanager config")
+	}
+	return *defaultCfg.AlertmanagerConfig.Route, nil
+}
+
+// getRule returns the stored rule, or nil when it doesn't exist.
+func (v *alertingValidator) getRule(ctx context.Context, orgID int64, uid string) (*models.AlertRule, error) {
+	rule, _, err := v.cfg.RuleService.GetAlertRule(ctx, orgID, uid)
+	if errors.Is(err, models.ErrAlertRuleNotFound) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	return &rule, nil
+}
+
+// folderUID returns the UID of the folder with the title, or an empty UID when provisioning would create the folder.
+func (v *alertingValidator) folderUID(ctx context.Context, orgID int64, title string) (string, error) {
+	if uid, ok := v.folders[orgID][title]; ok {
+		return uid, nil
+	}
+	query := &legacymodels.GetDashboardQuery{Slug: slugify.Slugify(title), OrgId: orgID}
+	err := v.cfg.FolderService.GetDashboard(ctx, query)
+	if err != nil && !errors.Is(err, dashboards.ErrDashboardNotFound) {
+		return "", err
+	}
+	uid := ""
+	if err == nil {
+		uid = query.Result.Uid
+	}
+	if v.folders[orgID] == nil {
+		v.folders[orgID] = map[string]string{}
+	}
+	v.folders[orgID][title] = uid
+	return uid, nil
+}
+
+func (v *alertingValidator) existingContactPoints(ctx context.Context, orgID int64) (map[string]definitions.EmbeddedContactPoint, error) {
+	if existing, ok := v.contactPoints[orgID]; ok {
+		return existing, nil
+	}
+	// The secure settings are decrypted to compare them with the provisioned ones
+	cps, err := v.cfg.ContactPointService.GetContactPoints(ctx, provisioning.ContactPointQuery{OrgID: orgID, Decrypt: true})
+	if err != nil {
+		return nil, err
+	}
+	existing := make(map[string]definitions.EmbeddedContactPoint, len(cps))
+	for _, cp := range cps {
+		existing[cp.UID] = cp
+	}
+	v.contactPoints[orgID] = existing
+	return existing, nil
+}
+
+func (v *alertingValidator) existingMuteTimings(ctx context.Context, orgID int64) (map[string]definitions.MuteTimeInterval, error) {
+	if existing, ok := v.muteTimings[orgID]; ok {
+		return existing, nil
+	}
+	muteTimings, err := v.cfg.MuteTimingService.GetMuteTimings(ctx, orgID)
+	if err != nil {
+		return nil, err
+	}
+	existing := make(map[string]definitions.MuteTimeInterval, len(muteTimings))
+	for _, muteTiming := range muteTimings {
+		existing[muteTiming.Name] = muteTiming
+	}
+	v.muteTimings[orgID] = existing
+	return existing, nil
+}
+
+func (v *alertingValidator) existingTemplates(ctx context.Context, orgID int64) (map[string]string, error) {
+	if existing, ok := v.templates[orgID]; ok {
+		return existing, nil
+	}
+	templates, err := v.cfg.TemplateService.GetTemplates(ctx, orgID)
+	if err != nil {
+		return nil, err
+	}
+	v.templates[orgID] = templates
+	return templates, nil
+}
@@ -0,0 +1,259 @@
+package alerting
+
+import (
+	"context"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/grafana/grafana/pkg/components/simplejson"
+	"github.com/grafana/grafana/pkg/infra/log"
+	legacymodels "github.com/grafana/grafana/pkg/models"
+	"github.com/grafana/grafana/pkg/services/dashboards"
+	"github.com/grafana/grafana/pkg/services/ngalert/api/tooling/definitions"
+	"github.com/grafana/grafana/pkg/services/ngalert/models"
+	"github.com/grafana/grafana/pkg/services/ngalert/provisioning"
+	"github.com/grafana/grafana/pkg/services/provisioning/validation"
+	"github.com/grafana/grafana/pkg/setting"
+)
+
+func TestValidate(t *testing.T) {
+	ctx := context.Background()
+	setup := func(path string) ValidatorConfig {
+		return ValidatorConfig{
+			Path:                 path,
+			RuleService:          &fakeRuleReader{rules: map[string]models.AlertRule{}},
+			FolderService:        &fakeFolderReader{uids: map[string]string{"my-folder": "my-folder-uid"}},
+			ContactPointService:  &fakeContactPointReader{},
+			MuteTimingService:    &fakeMuteTimingReader{},
+			TemplateService:      &fakeTemplateReader{templates: map[string]string{"my_first_template": "test"}},
+			PolicyService:        &fakePolicyReader{},
+			DefaultConfiguration: setting.GetAlertmanagerDefaultConfiguration(),
+		}
+	}
+	rulesFile := filepath.Join(testFileCorrectProperties, "rules.yml")
+
+	t.Run("should return the changes of rules without applying them", func(t *testing.T) {
+		report, err := Validate(ctx, setup(testFileCorrectProperties))
+		require.NoError(t, err)
+
+		assert.True(t, report.Valid())
+		assert.Equal(t, []validation.Change{
+			{Action: validation.ActionCreate, Kind: "alert rule", OrgID: 1, Name: "my_first_rule", File: rulesFile},
+		}, report.Changes)
+	})
+
+	t.Run("should only return the changed fields of rules", func(t *testing.T) {
+		cfg := setup(testFileCorrectProperties)
+		rules := cfg.RuleService.(*fakeRuleReader)
+		rules.rules["my_first_rule"] = storedRule(t, "my-folder-uid")
+
+		report, err := Validate(ctx, cfg)
+		require.NoError(t, err)
+		assert.Empty(t, report.Changes)
+
+		rule := rules.rules["my_first_rule"]
+		rule.Title = "old title"
+		rule.IntervalSeconds = 60
+		rules.rules["my_first_rule"] = rule
+		report, err = Validate(ctx, cfg)
+		require.NoError(t, err)
+		assert.Equal(t, []validation.Change{
+			{Action: validation.ActionUpdate, Kind: "alert rule", OrgID: 1, Name: "my_first_rule", File: rulesFile, Fields: []string{"interval", "title"}},
+		}, report.Changes)
+	})
+
+	t.Run("should return a changed folder of rules when the folder doesn't exist", func(t *testing.T) {
+		cfg := setup(testFileCorrectProperties)
+		cfg.FolderService = &fakeFolderReader{}
+		cfg.RuleService.(*fakeRuleReader).rules["my_first_rule"] = storedRule(t, "my-folder-uid")
+
+		report, err := Validate(ctx, cfg)
+		require.NoError(t, err)
+		assert.Equal(t, []validation.Change{
+			{Action: validation.ActionUpdate, Kind: "alert rule", OrgID: 1, Name: "my_first_rule", File: rulesFile, Fields: []string{"folder"}},
+		}, report.Changes)
+	})
+
+	t.Run("should return the changes of contact points, mute timings and templates", func(t *testing.T) {
+		for _, tc := range []struct {
+			path   string
+			change validation.Change
+		}{
+			{
+				path:   testFileCorrectProperties_cp,
+				change: validation.Change{Action: validation.ActionCreate, Kind: "contact point", OrgID: 1, Name: "cp_1", File: filepath.Join(testFileCorrectProperties_cp, "contact_points.yml")},
+			},
+			{
+				path:   testFileCorrectProperties_mt,
+				change: validation.Change{Action: validation.ActionCreate, Kind: "mute timing", OrgID: 1, Name: "test", File: filepath.Join(testFileCorrectProperties_mt, "mute_times.yml")},
+			},
+		} {
+			report, err := Validate(ctx, setup(tc.path))
+			require.NoError(t, err)
+
+			assert.True(t, report.Valid())
+			assert.Equal(t, []validation.Change{tc.change}, report.Changes)
+		}
+	})
+
+	t.Run("should only return the changed fields of contact points", func(t *testing.T) {
+		cfg := setup(testFileCorrectProperties_cp)
+		cfg.ContactPointService = &fakeContactPointReader{contactPoints: []definitions.EmbeddedContactPoint{
+			{UID: "first_uid", Name: "cp_1", Type: "prometheus-alertmanager", Settings: simplejson.NewFromAny(map[string]interface{}{"url": "http://test:9000"})},
+		}}
+		report, err := Validate(ctx, cfg)
+		require.NoError(t, err)
+		assert.Empty(t, report.Changes)
+
+		cfg.ContactPointService = &fakeContactPointReader{contactPoints: []definitions.EmbeddedContactPoint{
+			{UID: "first_uid", Name: "cp_1", Type: "prometheus-alertmanager", Settings: simplejson.NewFromAny(map[string]interface{}{"url": "http://old:9000"})},
+		}}
+		report, err = Validate(ctx, cfg)
+		require.NoError(t, err)
+		assert.Equal(t, []validation.Change{
+			{Action: validation.ActionUpdate, Kind: "contact point", OrgID: 1, Name: "cp_1", File: filepath.Join(testFileCorrectProperties_cp, "contact_points.yml"), Fields: []string{"settings"}},
+		}, report.Changes)
+	})
+
+	t.Run("should only return changed templates", func(t *testing.T) {
+		cfg := setup(testFileCorrectProperties_t)
+		report, err := Validate(ctx, cfg)
+		require.NoError(t, err)
+		assert.Empty(t, report.Changes)
+
+		cfg.TemplateService = &fakeTemplateReader{templates: map[string]string{"my_first_template": "old"}}
+		report, err = Validate(ctx, cfg)
+		require.NoError(t, err)
+		assert.Equal(t, []validation.Change{
+			{Action: validation.ActionUpdate, Kind: "template", OrgID: 1, Name: "my_first_template", File: filepath.Join(testFileCorrectProperties_t, "mute_times.yml"), Fields: []string{"template"}},
+		}, report.Changes)
+	})
+
+	t.Run("should only return a changed notification policy tree", func(t *testing.T) {
+		cfg := setup(testFileCorrectProperties_np)
+		report, err := Validate(ctx, cfg)
+		require.NoError(t, err)
+		assert.Equal(t, []validation.Change{
+			{Action: validation.ActionUpdate, Kind: "notification policy", OrgID: 1, Name: "notification policy tree", File: filepath.Join(testFileCorrectProperties_np, "policies.yml"), Fields: []string{"policy"}},
+		}, report.Changes)
+
+		cfg.PolicyService = &fakePolicyReader{tree: definitions.Route{
+			Receiver:   "grafana-default-email",
+			GroupByStr: []string{"grafana_folder", "alertname"},
+			Provenance: models.ProvenanceFile,
+		}}
+		report, err = Validate(ctx, cfg)
+		require.NoError(t, err)
+		assert.Empty(t, report.Changes)
+	})
+
+	t.Run("should report broken files with their line", func(t *testing.T) {
+		report, err := Validate(ctx, setup(testFileBrokenYAML))
+		require.NoError(t, err)
+
+		require.Len(t, report.Problems, 1)
+		assert.Equal(t, filepath.Join(testFileBrokenYAML, "rules.yml"), report.Problems[0].File)
+		assert.NotZero(t, report.Problems[0].Line)
+		assert.Empty(t, report.Changes)
+	})
+
+	t.Run("should report recording rules when they are disabled", func(t *testing.T) {
+		report, err := Validate(ctx, setup(testFileRecordingRule))
+		require.NoError(t, err)
+
+		require.Len(t, report.Problems, 1)
+		assert.Contains(t, report.Problems[0].Message, models.ErrRecordingRulesDisabled.Error())
+		assert.Empty(t, report.Changes)
+
+		cfg := setup(testFileRecordingRule)
+		cfg.RecordingRulesEnabled = true
+		report, err = Validate(ctx, cfg)
+		require.NoError(t, err)
+		assert.True(t, report.Valid())
+		assert.Equal(t, []validation.Change{
+			{Action: validation.ActionCreate, Kind: "alert rule", OrgID: 1, Name: "my_recording_rule", File: filepath.Join(testFileRecordingRule, "rules.yml")},
+		}, report.Changes)
+	})
+
+	t.Run("should report contact points without uid", func(t *testing.T) {
+		report, err := Validate(ctx, setup(testFileMissingUID))
+		require.NoError(t, err)
+
+		require.Len(t, report.Problems, 1)
+		assert.Empty(t, report.Changes)
+	})
+}
+
+// storedRule returns the rule of the rules test file as provisioning stores it in the folder.
+func storedRule(t *testing.T, folderUID string) models.AlertRule {
+	t.Helper()
+	cr := newRulesConfigReader(log.NewNopLogger())
+	files, err := cr.readConfig(context.Background(), testFileCorrectProperties)
+	require.NoError(t, err)
+	group := files[0].Groups[0]
+	rule := group.Rules[0]
+	rule.NamespaceUID = folderUID
+	rule.RuleGroup = group.Name
+	rule.IntervalSeconds = int64(group.Interval.Seconds())
+	return rule
+}
+
+type fakeRuleReader struct {
+	rules map[string]models.AlertRule
+}
+
+func (f *fakeRuleReader) GetAlertRule(_ context.Context, _ int64, ruleUID string) (models.AlertRule, models.Provenance, error) {
+	rule, ok := f.rules[ruleUID]
+	if !ok {
+		return models.AlertRule{}, models.ProvenanceNone, models.ErrAlertRuleNotFound
+	}
+	return rule, models.ProvenanceFile, nil
+}
+
+type fakeFolderReader struct {
+	uids map[string]string
+}
+
+func (f *fakeFolderReader) GetDashboard(_ context.Context, query *legacymodels.GetDashboardQuery) error {
+	uid, ok := f.uids[query.Slug]
+	if !ok {
+		return dashboards.ErrDashboardNotFound
+	}
+	query.Result = &legacymodels.Dashboard{Uid: uid, Slug: query.Slug, IsFolder: true}
+	return nil
+}
+
+type fakeContactPointReader struct {
+	contactPoints []definitions.EmbeddedContactPoint
+}
+
+func (f *fakeContactPointReader) GetContactPoints(_ context.Context, _ provisioning.ContactPointQuery) ([]definitions.EmbeddedContactPoint, error) {
+	return f.contactPoints, nil
+}
+
+type fakeMuteTimingReader struct {
+	muteTimings []definitions.MuteTimeInterval
+}
+
+func (f *fakeMuteTimingReader) GetMuteTimings(_ context.Context, _ int64) ([]definitions.MuteTimeInterval, error) {
+	return f.muteTimings, nil
+}
+
+type fakeTemplateReader struct {
+	templates map[string]string
+}
+
+func (f *fakeTemplateReader) GetTemplates(_ context.Context, _ int64) (map[string]string, error) {
+	return f.templates, nil
+}
+
+type fakePolicyReader struct {
+	tree definitions.Route
+}
+
+func (f *fakePolicyReader) GetPolicyTree(_ context.Context, _ int64) (definitions.Route, error) {
+	return f.tree, nil
+}
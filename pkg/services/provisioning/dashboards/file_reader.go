@@ -37,11 +37,20 @@ type FileReader struct {
 	dashboardStore               utils.DashboardStore
 	FoldersFromFilesStructure    bool
 
+	// source syncs the dashboards of providers that don't read them from the file system, such as git
+	// repositories, to the path of the reader.
+	source dashboardSource
+
 	mux                     sync.RWMutex
 	usageTracker            *usageTracker
 	dbWriteAccessRestricted bool
 }
 
+// dashboardSource syncs dashboards from a remote location to the path of a FileReader.
+type dashboardSource interface {
+	sync(ctx context.Context) error
+}
+
 // NewDashboardFileReader returns a new filereader based on `config`
 func NewDashboardFileReader(cfg *config, log log.Logger, service dashboards.DashboardProvisioningService, dashboardStore utils.DashboardStore) (*FileReader, error) {
 	var path string
@@ -55,9 +64,15 @@ func NewDashboardFileReader(cfg *config, log log.Logger, service dashboards.Dash
 		log.Warn("[Deprecated] The folder property is deprecated. Please use path instead.")
 	}
 
-	foldersFromFilesStructure, _ := cfg.Options["foldersFromFilesStructure"].(bool)
-	if foldersFromFilesStructure && cfg.Folder != "" && cfg.FolderUID != "" {
-		return nil, fmt.Errorf("'folder' and 'folderUID' should be empty using 'foldersFromFilesStructure' option")
+	return newFileReader(cfg, path, log, service, dashboardStore)
+}
+
+// newFileReader returns a reader of the dashboards in path, that is either the path of a file provider or
+// the work directory of a provider syncing the dashboards from a remote location.
+func newFileReader(cfg *config, path string, log log.Logger, service dashboards.DashboardProvisioningService, dashboardStore utils.DashboardStore) (*FileReader, error) {
+	foldersFromFilesStructure, err := foldersFromFilesStructureOption(cfg)
+	if err != nil {
+		return nil, err
 	}
 
 	return &FileReader{
@@ -71,6 +86,14 @@ func NewDashboardFileReader(cfg *config, log log.Logger, service dashboards.Dash
 	}, nil
 }
 
+func foldersFromFilesStructureOption(cfg *config) (bool, error) {
+	foldersFromFilesStructure, _ := cfg.Options["foldersFromFilesStructure"].(bool)
+	if foldersFromFilesStructure && cfg.Folder != "" && cfg.FolderUID != "" {
+		return false, fmt.Errorf("'folder' and 'folderUID' should be empty using 'foldersFromFilesStructure' option")
+	}
+	return foldersFromFilesStructure, nil
+}
+
 // pollChanges periodically runs walkDisk based on interval specified in the config.
 func (fr *FileReader) pollChanges(ctx context.Context) {
 	ticker := time.NewTicker(time.Duration(int64(time.Second) * fr.Cfg.UpdateIntervalSeconds))
@@ -89,6 +112,13 @@ func (fr *FileReader) pollChanges(ctx context.Context) {
 // walkDisk traverses the file system for the defined path, reading dashboard definition files,
 // and applies any change to the database.
 func (fr *FileReader) walkDisk(ctx context.Context) error {
+	if fr.source != nil {
+		// Dashboards that failed to sync keep their last synced version
+		if err := fr.source.sync(ctx); err != nil {
+			fr.log.Error("Failed to sync dashboards", "error", err)
+		}
+	}
+
 	fr.log.Debug("Start walking disk", "path", fr.Path)
 	resolvedPath := fr.resolvedPath()
 	if _, err := os.Stat(resolvedPath); err != nil {
@@ -0,0 +1,297 @@
+package dashboards
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/url"
+	"os"
+	"path/filepath"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/go-git/go-git/v5"
+	"github.com/go-git/go-git/v5/plumbing"
+	"github.com/go-git/go-git/v5/plumbing/transport"
+	"github.com/go-git/go-git/v5/plumbing/transport/http"
+	"github.com/go-git/go-git/v5/plumbing/transport/ssh"
+
+	"github.com/grafana/grafana/pkg/infra/log"
+	"github.com/grafana/grafana/pkg/infra/slugify"
+	"github.com/grafana/grafana/pkg/services/dashboards"
+	"github.com/grafana/grafana/pkg/services/provisioning/utils"
+)
+
+const (
+	gitRemoteName = "origin"
+	// gitRepositoryDir is the directory of the work directory that the repository is cloned into.
+	gitRepositoryDir = "repository"
+	// workDirMarker is the file that marks the work directories created by git and http providers.
+	workDirMarker = ".grafana-provisioning"
+)
+
+// gitSource syncs the dashboards of a branch of a git repository to a directory, by cloning the repository
+// and then fetching the branch and resetting the directory to it. The directory is only removed by the source
+// if it has to clone the repository again.
+type gitSource struct {
+	url string
+	// redactedURL is the URL without its user info, that is logged and wrapped in errors instead of the URL
+	redactedURL string
+	branch      string
+	auth        transport.AuthMethod
+	dir         string
+	log         log.Logger
+
+	mu sync.Mutex
+}
+
+// NewDashboardGitReader returns a reader of the dashboards of a git repository, that clones the repository into
+// its work directory and reads the dashboards like a file reader of the path in the repository.
+func NewDashboardGitReader(cfg *config, dataPath string, log log.Logger, service dashboards.DashboardProvisioningService, dashboardStore utils.DashboardStore) (*FileReader, error) {
+	url, err := gitURL(cfg.Options)
+	if err != nil {
+		return nil, err
+	}
+	branch, _ := cfg.Options["branch"].(string)
+
+	subPath, _ := cfg.Options["path"].(string)
+	subPath = filepath.Clean("/" + subPath)
+
+	auth, err := gitAuth(cfg.Options)
+	if err != nil {
+		return nil, err
+	}
+
+	if err := setPollInterval(cfg); err != nil {
+		return nil, err
+	}
+
+	workDir, err := providerWorkDir(cfg, dataPath)
+	if err != nil {
+		return nil, err
+	}
+	dir := filepath.Join(workDir, gitRepositoryDir)
+
+	reader, err := newFileReader(cfg, filepath.Join(dir, subPath), log, service, dashboardStore)
+	if err != nil {
+		return nil, err
+	}
+	reader.source = &gitSource{
+		url:         url,
+		redactedURL: redactURL(url),
+		branch:      branch,
+		auth:        auth,
+		dir:         dir,
+		log:         log,
+	}
+	return reader, nil
+}
+
+func gitURL(options map[string]interface{}) (string, error) {
+	url, ok := options["url"].(string)
+	if !ok || url == "" {
+		return "", fmt.Errorf("failed to load dashboards, url param is not a string")
+	}
+	return url, nil
+}
+
+// redactURL returns the URL without its user info, as credentials are often embedded in HTTP URLs of
+// repositories. URLs that can't be parsed, such as SSH URLs like git@host:repo.git, are returned as they are.
+func redactURL(rawURL string) string {
+	u, err := url.Parse(rawURL)
+	if err != nil || u.User == nil {
+		return rawURL
+	}
+	u.User = nil
+	return u.String()
+}
+
+// gitAuth returns the authentication for the repository, with a username and password or token for
+// HTTP URLs, or with a private key for SSH URLs.
+func gitAuth(options map[string]interface{}) (transport.AuthMethod, error) {
+	username, _ := options["username"].(string)
+	password, _ := options["password"].(string)
+	privateKeyFile, _ := options["privateKeyFile"].(string)
+
+	if privateKeyFile != "" {
+		if username == "" {
+			username = "git"
+		}
+		auth, err := ssh.NewPublicKeysFromFile(username, privateKeyFile, password)
+		if err != nil {
+			return nil, fmt.Errorf("failed to read private key file: %w", err)
+		}
+		return auth, nil
+	}
+
+	if username != "" || password != "" {
+		if username == "" {
+			// Tokens of most git hosts are accepted with any username that isn't empty
+			username = "grafana"
+		}
+		return &http.BasicAuth{Username: username, Password: password}, nil
+	}
+	return nil, nil
+}
+
+func (s *gitSource) sync(ctx context.Context) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	repo, err := s.openRepository()
+	if err != nil {
+		return err
+	}
+	if repo == nil {
+		s.log.Info("Cloning git repository", "url", s.redactedURL, "branch", s.branch, "dir", s.dir)
+		opts := &git.CloneOptions{
+			URL:          s.url,
+			Auth:         s.auth,
+			RemoteName:   gitRemoteName,
+			SingleBranch: true,
+		}
+		if s.branch != "" {
+			opts.ReferenceName = plumbing.NewBranchReferenceName(s.branch)
+		}
+		if _, err := git.PlainCloneContext(ctx, s.dir, false, opts); err != nil {
+			// Don't leave a partial clone behind, that would be opened by the next sync
+			if err := os.RemoveAll(s.dir); err != nil {
+				s.log.Warn("Failed to remove partial clone", "dir", s.dir, "error", err)
+			}
+			return fmt.Errorf("failed to clone %s: %w", s.redactedURL, err)
+		}
+		return nil
+	}
+
+	head, err := repo.Head()
+	if err != nil {
+		return err
+	}
+
+	err = repo.FetchContext(ctx, &git.FetchOptions{RemoteName: gitRemoteName, Auth: s.auth, Force: true})
+	if err != nil && !errors.Is(err, git.NoErrAlreadyUpToDate) {
+		return fmt.Errorf("failed to fetch %s: %w", s.redactedURL, err)
+	}
+
+	remoteRef, err := repo.Reference(plumbing.NewRemoteReferenceName(gitRemoteName, head.Name().Short()), true)
+	if err != nil {
+		return fmt.Errorf("failed to find branch %s: %w", head.Name().Short(), err)
+	}
+	if remoteRef.Hash() == head.Hash() {
+		return nil
+	}
+
+	worktree, err := repo.Worktree()
+	if err != nil {
+		return err
+	}
+	s.log.Debug("Updating git repository", "url", s.redactedURL, "from", head.Hash(), "to", remoteRef.Hash())
+	return worktree.Reset(&git.ResetOptions{Commit: remoteRef.Hash(), Mode: git.HardReset})
+}
+
+// openRepository returns the repository in the directory, or nil if there is none, or if it was cloned from
+// another URL or branch than the ones of the config, in which case it is removed.
+func (s *gitSource) openRepository() (*git.Repository, error) {
+	repo, err := git.PlainOpen(s.dir)
+	if errors.Is(err, git.ErrRepositoryNotExists) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	if s.isClonedFromConfig(repo) {
+		return repo, nil
+	}
+
+	s.log.Info("Removing git repository cloned with other settings", "dir", s.dir)
+	if err := os.RemoveAll(s.dir); err != nil {
+		return nil, fmt.Errorf("failed to remove git repository: %w", err)
+	}
+	return nil, nil
+}
+
+func (s *gitSource) isClonedFromConfig(repo *git.Repository) bool {
+	remote, err := repo.Remote(gitRemoteName)
+	if err != nil || len(remote.Config().URLs) == 0 || remote.Config().URLs[0] != s.url {
+		return false
+	}
+	if s.branch == "" {
+		return true
+	}
+	head, err := repo.Head()
+	return err == nil && head.Name().Short() == s.branch
+}
+
+// providerWorkDir returns the work directory of the provider from its workDir option, that is relative to the
+// data path, or else a directory named after the provider in the data path.
+func providerWorkDir(cfg *config, dataPath string) (string, error) {
+	workDir, _ := cfg.Options["workDir"].(string)
+	if workDir == "" {
+		workDir = filepath.Join("provisioning", "dashboards", fmt.Sprintf("%s-%s", cfg.Type, slugify.Slugify(cfg.Name)))
+	}
+	if !filepath.IsAbs(workDir) {
+		workDir = filepath.Join(dataPath, workDir)
+	}
+
+	workDir, err := filepath.Abs(workDir)
+	if err != nil {
+		return "", err
+	}
+	if err := claimWorkDir(workDir); err != nil {
+		return "", err
+	}
+	return workDir, nil
+}
+
+// claimWorkDir creates the work directory with a marker file, or adds the marker file to the directory if it
+// is empty. Directories that aren't empty are only used if they have the marker file, so that providers never
+// overwrite or remove files they didn't create.
+func claimWorkDir(workDir string) error {
+	marker := filepath.Join(workDir, workDirMarker)
+	entries, err := os.ReadDir(workDir)
+	switch {
+	case errors.Is(err, os.ErrNotExist):
+		if err := os.MkdirAll(workDir, 0750); err != nil {
+			return fmt.Errorf("failed to create work directory: %w", err)
+		}
+	case err != nil:
+		return fmt.Errorf("failed to read work directory: %w", err)
+	case len(entries) > 0:
+		if _, err := os.Stat(marker); err != nil {
+			return fmt.Errorf("work directory %s is not empty and was not created by a provider", workDir)
+		}
+		return nil
+	}
+	return os.WriteFile(marker, nil, 0640)
+}
+
+// setPollInterval sets the update interval of the config from its pollInterval option.
+func setPollInterval(cfg *config) error {
+	interval, err := pollInterval(cfg.Options)
+	if err != nil {
+		return err
+	}
+	if interval > 0 {
+		cfg.UpdateIntervalSeconds = interval
+	}
+	return nil
+}
+
+// pollInterval returns the seconds of the pollInterval option, such as 5m, or 0 when it isn't set.
+func pollInterval(options map[string]interface{}) (int64, error) {
+	v, ok := options["pollInterval"].(string)
+	if !ok || strings.TrimSpace(v) == "" {
+		return 0, nil
+	}
+
+	interval, err := time.ParseDuration(v)
+	if err != nil {
+		return 0, fmt.Errorf("invalid poll interval %q: %w", v, err)
+	}
+	if interval < time.Second {
+		return 0, fmt.Errorf("poll interval %q must be at least 1s", v)
+	}
+	return int64(interval / time.Second), nil
+}
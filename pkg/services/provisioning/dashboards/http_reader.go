@@ -0,0 +1,303 @@
+package dashboards
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"os"
+	"path"
+	"path/filepath"
+	"regexp"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/grafana/grafana/pkg/infra/log"
+	"github.com/grafana/grafana/pkg/infra/slugify"
+	"github.com/grafana/grafana/pkg/services/dashboards"
+	"github.com/grafana/grafana/pkg/services/provisioning/utils"
+)
+
+const (
+	httpSourceTimeout = 30 * time.Second
+	// httpSourceMaxSize is the maximum size of the manifest and of each dashboard.
+	httpSourceMaxSize = 20 << 20
+)
+
+// httpDashboardFile matches the names of the dashboard files, and of their temporary files, that the http
+// source writes to its work directory.
+var httpDashboardFile = regexp.MustCompile(`-[0-9a-f]{8}\.json(\.tmp)?$`)
+
+// httpManifest lists the URLs of the dashboards of an http provider.
+type httpManifest struct {
+	Dashboards []httpManifestEntry `json:"dashboards"`
+}
+
+type httpManifestEntry struct {
+	// URL of the dashboard JSON, that can be relative to the URL of the manifest.
+	URL string `json:"url"`
+	// Folder of the dashboard, used with the foldersFromFilesStructure option.
+	Folder string `json:"folder"`
+}
+
+// httpSource syncs the dashboards listed in a manifest to files in a work directory. The manifest and the
+// dashboards are only downloaded again when their ETag changed.
+type httpSource struct {
+	url      string
+	username string
+	password string
+	workDir  string
+	client   *http.Client
+	log      log.Logger
+
+	mu           sync.Mutex
+	manifestETag string
+	manifest     *httpManifest
+	etags        map[string]string
+}
+
+// NewDashboardHTTPReader returns a reader of the dashboards listed in the manifest at the URL of the config,
+// that downloads the dashboards into its work directory and reads them like a file reader.
+func NewDashboardHTTPReader(cfg *config, dataPath string, log log.Logger, service dashboards.DashboardProvisioningService, dashboardStore utils.DashboardStore) (*FileReader, error) {
+	manifestURL, err := httpManifestURL(cfg.Options)
+	if err != nil {
+		return nil, err
+	}
+	username, _ := cfg.Options["username"].(string)
+	password, _ := cfg.Options["password"].(string)
+
+	if err := setPollInterval(cfg); err != nil {
+		return nil, err
+	}
+
+	workDir, err := providerWorkDir(cfg, dataPath)
+	if err != nil {
+		return nil, err
+	}
+
+	reader, err := newFileReader(cfg, workDir, log, service, dashboardStore)
+	if err != nil {
+		return nil, err
+	}
+	reader.source = &httpSource{
+		url:      manifestURL,
+		username: username,
+		password: password,
+		workDir:  workDir,
+		client:   &http.Client{Timeout: httpSourceTimeout},
+		log:      log,
+		etags:    map[string]string{},
+	}
+	return reader, nil
+}
+
+func httpManifestURL(options map[string]interface{}) (string, error) {
+	manifestURL, ok := options["url"].(string)
+	if !ok || manifestURL == "" {
+		return "", fmt.Errorf("failed to load dashboards, url param is not a string")
+	}
+	if u, err := url.Parse(manifestURL); err != nil || (u.Scheme != "http" && u.Scheme != "https") {
+		return "", fmt.Errorf("failed to load dashboards, url param is not an http URL")
+	}
+	return manifestURL, nil
+}
+
+func (s *httpSource) sync(ctx context.Context) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	manifest, err := s.getManifest(ctx)
+	if err != nil {
+		return err
+	}
+
+	if err := os.MkdirAll(s.workDir, 0750); err != nil {
+		return fmt.Errorf("failed to create work directory: %w", err)
+	}
+
+	files := map[string]bool{}
+	for _, entry := range manifest.Dashboards {
+		dashboardURL, file, err := s.resolveEntry(entry)
+		if err != nil {
+			s.log.Error("Invalid dashboard in manifest", "url", entry.URL, "error", err)
+			continue
+		}
+		files[file] = true
+
+		if err := s.syncDashboard(ctx, dashboardURL, file); err != nil {
+			// Keep the last downloaded version of the dashboard
+			s.log.Error("Failed to download dashboard", "url", dashboardURL, "error", err)
+		}
+	}
+
+	return s.removeOtherFiles(files)
+}
+
+// getManifest returns the manifest, that is only downloaded again if it changed since the last sync.
+func (s *httpSource) getManifest(ctx context.Context) (*httpManifest, error) {
+	etag := ""
+	if s.manifest != nil {
+		etag = s.manifestETag
+	}
+
+	body, newETag, err := s.get(ctx, s.url, etag)
+	if err != nil {
+		return nil, fmt.Errorf("failed to download manifest: %w", err)
+	}
+	if body == nil {
+		return s.manifest, nil
+	}
+
+	manifest := &httpManifest{}
+	if err := json.Unmarshal(body, manifest); err != nil {
+		return nil, fmt.Errorf("failed to parse manifest: %w", err)
+	}
+	s.manifest = manifest
+	s.manifestETag = newETag
+	return manifest, nil
+}
+
+// resolveEntry returns the URL of the dashboard of the manifest entry, and the path of its file in the work
+// directory, that is in a directory named after the folder of the entry.
+func (s *httpSource) resolveEntry(entry httpManifestEntry) (string, string, error) {
+	base, err := url.Parse(s.url)
+	if err != nil {
+		return "", "", err
+	}
+	ref, err := url.Parse(entry.URL)
+	if err != nil {
+		return "", "", err
+	}
+	u := base.ResolveReference(ref)
+	if u.Scheme != "http" && u.Scheme != "https" {
+		return "", "", fmt.Errorf("dashboard URL is not an http URL")
+	}
+
+	folder := strings.TrimSpace(entry.Folder)
+	if folder == "." || folder == ".." || strings.ContainsAny(folder, `/\`) {
+		return "", "", fmt.Errorf("invalid folder %q", entry.Folder)
+	}
+
+	sum := sha256.Sum256([]byte(u.String()))
+	name := slugify.Slugify(strings.TrimSuffix(path.Base(u.Path), path.Ext(u.Path)))
+	file := fmt.Sprintf("%s-%s.json", name, hex.EncodeToString(sum[:4]))
+	return u.String(), filepath.Join(s.workDir, folder, file), nil
+}
+
+// syncDashboard downloads the dashboard to its file, unless its ETag didn't change since the last download.
+func (s *httpSource) syncDashboard(ctx context.Context, dashboardURL string, file string) error {
+	etag := ""
+	if _, err := os.Stat(file); err == nil {
+		etag = s.etags[dashboardURL]
+	}
+
+	body, newETag, err := s.get(ctx, dashboardURL, etag)
+	if err != nil {
+		return err
+	}
+	if body == nil {
+		return nil
+	}
+	if !json.Valid(body) {
+		return fmt.Errorf("dashboard is not valid JSON")
+	}
+
+	if err := os.MkdirAll(filepath.Dir(file), 0750); err != nil {
+		return err
+	}
+	// Write to a temporary file first, so that the file reader never reads a partial dashboard
+	tmp := file + ".tmp"
+	if err := os.WriteFile(tmp, body, 0640); err != nil {
+		return err
+	}
+	if err := os.Rename(tmp, file); err != nil {
+		return err
+	}
+	s.etags[dashboardURL] = newETag
+	return nil
+}
+
+// removeOtherFiles removes the dashboard files of the work directory that are no longer in the manifest, and
+// the folders left empty. Other files of the work directory are left unchanged.
+func (s *httpSource) removeOtherFiles(files map[string]bool) error {
+	var dirs []string
+	err := filepath.Walk(s.workDir, func(p string, info os.FileInfo, err error) error {
+		if err != nil {
+			return err
+		}
+		if info.IsDir() {
+			if p != s.workDir {
+				dirs = append(dirs, p)
+			}
+			return nil
+		}
+		if !files[p] && httpDashboardFile.MatchString(info.Name()) {
+			s.log.Debug("Removing dashboard missing in manifest", "file", p)
+			return os.Remove(p)
+		}
+		return nil
+	})
+	if err != nil {
+		return err
+	}
+
+	for i := len(dirs) - 1; i >= 0; i-- {
+		if entries, err := os.ReadDir(dirs[i]); err == nil && len(entries) == 0 {
+			if err := os.Remove(dirs[i]); err != nil {
+				return err
+			}
+		}
+	}
+	return nil
+}
+
+// get downloads the URL, and returns a nil body if it has the ETag. The credentials of the provider are only
+// sent to the scheme and host of the manifest, since the manifest can list dashboards of other hosts.
+func (s *httpSource) get(ctx context.Context, u string, etag string) ([]byte, string, error) {
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, u, nil)
+	if err != nil {
+		return nil, "", err
+	}
+	if (s.username != "" || s.password != "") && s.isManifestHost(req.URL) {
+		req.SetBasicAuth(s.username, s.password)
+	}
+	if etag != "" {
+		req.Header.Set("If-None-Match", etag)
+	}
+
+	res, err := s.client.Do(req)
+	if err != nil {
+		return nil, "", err
+	}
+	defer func() {
+		if err := res.Body.Close(); err != nil {
+			s.log.Warn("Failed to close response body", "err", err)
+		}
+	}()
+
+	if res.StatusCode == http.StatusNotModified {
+		return nil, etag, nil
+	}
+	if res.StatusCode/100 != 2 {
+		return nil, "", fmt.Errorf("request failed, status: %s", res.Status)
+	}
+
+	body, err := io.ReadAll(io.LimitReader(res.Body, httpSourceMaxSize+1))
+	if err != nil {
+		return nil, "", err
+	}
+	if len(body) > httpSourceMaxSize {
+		return nil, "", fmt.Errorf("response is larger than %d bytes", httpSourceMaxSize)
+	}
+	return body, res.Header.Get("ETag"), nil
+}
+
+func (s *httpSource) isManifestHost(u *url.URL) bool {
+	manifestURL, err := url.Parse(s.url)
+	return err == nil && manifestURL.Scheme == u.Scheme && strings.EqualFold(manifestURL.Host, u.Host)
+}
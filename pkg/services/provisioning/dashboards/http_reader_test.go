@@ -0,0 +1,210 @@
+package dashboards
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"sync"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/grafana/grafana/pkg/infra/log"
+)
+
+func TestCreatingNewDashboardHTTPReader(t *testing.T) {
+	setup := func() *config {
+		return &config{
+			Name:    "Default",
+			Type:    "http",
+			OrgID:   1,
+			Options: map[string]interface{}{"url": "https://example.com/manifest.json", "workDir": t.TempDir()},
+		}
+	}
+
+	t.Run("using url parameter", func(t *testing.T) {
+		cfg := setup()
+		reader, err := NewDashboardHTTPReader(cfg, "", log.New("test-logger"), nil, nil)
+		require.NoError(t, err)
+		require.Equal(t, cfg.Options["workDir"], reader.Path)
+	})
+
+	t.Run("work directory that is not empty should return error", func(t *testing.T) {
+		cfg := setup()
+		file := filepath.Join(cfg.Options["workDir"].(string), "notes.json")
+		require.NoError(t, os.WriteFile(file, []byte("{}"), 0600))
+		_, err := NewDashboardHTTPReader(cfg, "", log.New("test-logger"), nil, nil)
+		require.Error(t, err)
+		require.FileExists(t, file)
+	})
+
+	t.Run("url parameter that is not http should return error", func(t *testing.T) {
+		cfg := setup()
+		cfg.Options["url"] = "file:///etc/manifest.json"
+		_, err := NewDashboardHTTPReader(cfg, "", log.New("test-logger"), nil, nil)
+		require.Error(t, err)
+	})
+
+	t.Run("missing url parameter should return error", func(t *testing.T) {
+		cfg := setup()
+		delete(cfg.Options, "url")
+		_, err := NewDashboardHTTPReader(cfg, "", log.New("test-logger"), nil, nil)
+		require.Error(t, err)
+	})
+}
+
+func TestHTTPSource(t *testing.T) {
+	var mu sync.Mutex
+	files := map[string]string{}
+	var downloaded []string
+
+	server := httptest.NewServer(http.HandlerFunc(func(rw http.ResponseWriter, req *http.Request) {
+		mu.Lock()
+		defer mu.Unlock()
+
+		if user, password, ok := req.BasicAuth(); !ok || user != "user" || password != "password" {
+			rw.WriteHeader(http.StatusUnauthorized)
+			return
+		}
+		content, ok := files[req.URL.Path]
+		if !ok {
+			rw.WriteHeader(http.StatusNotFound)
+			return
+		}
+		etag := fmt.Sprintf(`"%x"`, len(content))
+		if req.Header.Get("If-None-Match") == etag {
+			rw.WriteHeader(http.StatusNotModified)
+			return
+		}
+		downloaded = append(downloaded, req.URL.Path)
+		rw.Header().Set("ETag", etag)
+		_, _ = rw.Write([]byte(content))
+	}))
+	t.Cleanup(server.Close)
+
+	var otherAuthorization []string
+	otherServer := httptest.NewServer(http.HandlerFunc(func(rw http.ResponseWriter, req *http.Request) {
+		mu.Lock()
+		defer mu.Unlock()
+		otherAuthorization = append(otherAuthorization, req.Header.Get("Authorization"))
+		_, _ = rw.Write([]byte(`{"title": "three"}`))
+	}))
+	t.Cleanup(otherServer.Close)
+
+	setFiles := func(f map[string]string) {
+		mu.Lock()
+		defer mu.Unlock()
+		files = f
+		downloaded = nil
+	}
+	getDownloaded := func() []string {
+		mu.Lock()
+		defer mu.Unlock()
+		return downloaded
+	}
+	readDir := func(t *testing.T, dir string) []string {
+		t.Helper()
+		var names []string
+		err := filepath.Walk(dir, func(p string, info os.FileInfo, err error) error {
+			if err == nil && !info.IsDir() {
+				rel, _ := filepath.Rel(dir, p)
+				names = append(names, filepath.ToSlash(rel))
+			}
+			return err
+		})
+		require.NoError(t, err)
+		return names
+	}
+
+	workDir := filepath.Join(t.TempDir(), "work")
+	source := &httpSource{
+		url:      server.URL + "/provisioning/manifest.json",
+		username: "user",
+		password: "password",
+		workDir:  workDir,
+		client:   server.Client(),
+		log:      log.New("test-logger"),
+		etags:    map[string]string{},
+	}
+
+	t.Run("should download the dashboards of the manifest", func(t *testing.T) {
+		setFiles(map[string]string{
+			"/provisioning/manifest.json": `{"dashboards": [
+				{"url": "one.json"},
+				{"url": "/other/two.json", "folder": "Team"}
+			]}`,
+			"/provisioning/one.json": `{"title": "one"}`,
+			"/other/two.json":        `{"title": "two"}`,
+		})
+		require.NoError(t, source.sync(context.Background()))
+
+		names := readDir(t, workDir)
+		require.Len(t, names, 2)
+		assert.Regexp(t, `^one-[0-9a-f]{8}\.json$`, names[1])
+		assert.Regexp(t, `^Team/two-[0-9a-f]{8}\.json$`, names[0])
+		assert.ElementsMatch(t, []string{"/provisioning/manifest.json", "/provisioning/one.json", "/other/two.json"}, getDownloaded())
+	})
+
+	t.Run("should only download changed dashboards", func(t *testing.T) {
+		setFiles(map[string]string{
+			"/provisioning/manifest.json": `{"dashboards": [
+				{"url": "one.json"},
+				{"url": "/other/two.json", "folder": "Team"}
+			]}`,
+			"/provisioning/one.json": `{"title": "one updated"}`,
+			"/other/two.json":        `{"title": "two"}`,
+		})
+		require.NoError(t, source.sync(context.Background()))
+		assert.Equal(t, []string{"/provisioning/one.json"}, getDownloaded())
+		assert.Len(t, readDir(t, workDir), 2)
+	})
+
+	t.Run("should keep dashboards that failed to download", func(t *testing.T) {
+		setFiles(map[string]string{
+			"/provisioning/manifest.json": `{"dashboards": [
+				{"url": "one.json"},
+				{"url": "/other/two.json", "folder": "Team"}
+			]}`,
+			"/other/two.json": `{"title": "two"}`,
+		})
+		require.NoError(t, source.sync(context.Background()))
+		assert.Len(t, readDir(t, workDir), 2)
+	})
+
+	t.Run("should remove dashboards missing in the manifest", func(t *testing.T) {
+		setFiles(map[string]string{
+			"/provisioning/manifest.json": `{"dashboards": [{"url": "one.json"}, {"url": "bad.json", "folder": "../x"}]}`,
+			"/provisioning/one.json":      `{"title": "one"}`,
+		})
+		require.NoError(t, os.WriteFile(filepath.Join(workDir, "Team", "notes.json"), []byte("{}"), 0600))
+		require.NoError(t, source.sync(context.Background()))
+
+		names := readDir(t, workDir)
+		require.Len(t, names, 2)
+		assert.Equal(t, "Team/notes.json", names[0])
+		assert.Regexp(t, `^one-[0-9a-f]{8}\.json$`, names[1])
+	})
+
+	t.Run("should keep dashboards when the manifest failed to download", func(t *testing.T) {
+		setFiles(map[string]string{})
+		require.Error(t, source.sync(context.Background()))
+		assert.Len(t, readDir(t, workDir), 2)
+	})
+
+	t.Run("should only send credentials to the host of the manifest", func(t *testing.T) {
+		setFiles(map[string]string{
+			"/provisioning/manifest.json": fmt.Sprintf(`{"dashboards": [{"url": "one.json"}, {"url": "%s/three.json"}]}`, otherServer.URL),
+			"/provisioning/one.json":      `{"title": "one"}`,
+		})
+		require.NoError(t, source.sync(context.Background()))
+		assert.Len(t, readDir(t, workDir), 3)
+
+		mu.Lock()
+		defer mu.Unlock()
+		assert.Equal(t, []string{""}, otherAuthorization)
+	})
+}
@@ -0,0 +1,256 @@
+package dashboards
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+
+	"github.com/grafana/grafana/pkg/infra/log"
+	"github.com/grafana/grafana/pkg/infra/slugify"
+	"github.com/grafana/grafana/pkg/models"
+	"github.com/grafana/grafana/pkg/services/dashboards"
+	"github.com/grafana/grafana/pkg/services/org"
+	"github.com/grafana/grafana/pkg/services/provisioning/utils"
+	"github.com/grafana/grafana/pkg/services/provisioning/validation"
+)
+
+// Validate reads the provisioning config files in the directory and the dashboards of their file providers
+// without applying them, and returns the problems of the files and the dashboards and folders that provisioning
+// would create, update or delete. The dashboards of git and http providers are only fetched when they're
+// provisioned, so only the config of those providers is validated.
+func Validate(ctx context.Context, configDirectory string, service dashboards.DashboardProvisioningService, orgService org.Service, dashboardStore utils.DashboardStore) (*validation.Report, error) {
+	logger := log.New("provisioning.dashboard")
+	cr := &configReader{path: configDirectory, log: logger, orgService: orgService}
+	v := &dashboardValidator{
+		log:            logger,
+		service:        service,
+		dashboardStore: dashboardStore,
+		report:         validation.NewReport("dashboards"),
+		uids:           map[int64]map[string]string{},
+		folders:        map[int64]map[string]bool{},
+	}
+
+	files, err := os.ReadDir(configDirectory)
+	if err != nil {
+		// The dashboards aren't provisioned without the directory
+		return v.report, nil
+	}
+
+	names := map[string]string{}
+	for _, file := range files {
+		if !strings.HasSuffix(file.Name(), ".yaml") && !strings.HasSuffix(file.Name(), ".yml") {
+			continue
+		}
+		filename := filepath.Join(configDirectory, file.Name())
+
+		configs, err := cr.parseConfigs(file)
+		if err != nil {
+			v.report.AddProblem(filename, 0, err)
+			continue
+		}
+
+		// nolint:gosec
+		// We can ignore the gosec G304 warning on this one because `filename` was already read by the config reader
+		data, err := os.ReadFile(filename)
+		if err != nil {
+			return nil, err
+		}
+
+		for _, cfg := range configs {
+			line := validation.Line(data, "name", cfg.Name)
+			if other, ok := names[cfg.Name]; ok {
+				v.report.AddProblem(filename, line, fmt.Errorf("dashboard provider %q is already declared in %s", cfg.Name, other))
+				continue
+			}
+			names[cfg.Name] = filename
+
+			if err := v.validateProvider(ctx, cr, cfg); err != nil {
+				var problem *providerProblem
+				if !errors.As(err, &problem) {
+					return nil, err
+				}
+				v.report.AddProblem(filename, line, problem.err)
+			}
+		}
+	}
+
+	return v.report, nil
+}
+
+// providerProblem is a problem of the config of a provider, as opposed to an error reading the database.
+type providerProblem struct {
+	err error
+}
+
+func (p *providerProblem) Error() string {
+	return p.err.Error()
+}
+
+type dashboardValidator struct {
+	log            log.Logger
+	service        dashboards.DashboardProvisioningService
+	dashboardStore utils.DashboardStore
+	report         *validation.Report
+
+	// uids are the files of the dashboard UIDs of each organization, and folders are the folders of each
+	// organization that were already checked.
+	uids    map[int64]map[string]string
+	folders map[int64]map[string]bool
+}
+
+func (v *dashboardValidator) validateProvider(ctx context.Context, cr *configReader, cfg *config) error {
+	if cfg.OrgID == 0 {
+		cfg.OrgID = 1
+	}
+	if err := utils.CheckOrgExists(ctx, cr.orgService, cfg.OrgID); err != nil {
+		return &providerProblem{fmt.Errorf("failed to provision dashboards with %q reader: %w", cfg.Name, err)}
+	}
+	if cfg.Type == "" {
+		cfg.Type = "file"
+	}
+
+	if cfg.Type != "file" {
+		// Readers of git and http providers create their work directory, so only their options are checked
+		if err := validateOptions(cfg); err != nil {
+			return &providerProblem{fmt.Errorf("failed to create %s reader for config %v: %w", cfg.Type, cfg.Name, err)}
+		}
+		return nil
+	}
+
+	fr, err := NewDashboardFileReader(cfg, v.log.New("type", cfg.Type, "name", cfg.Name), v.service, v.dashboardStore)
+	if err != nil {
+		return &providerProblem{fmt.Errorf("failed to create file reader for config %v: %w", cfg.Name, err)}
+	}
+	return v.validateFileReader(ctx, fr)
+}
+
+// validateOptions checks the options of a git or http provider like its reader does, without changing the config.
+func validateOptions(cfg *config) error {
+	var err error
+	switch cfg.Type {
+	case "git":
+		if _, err = gitURL(cfg.Options); err == nil {
+			_, err = gitAuth(cfg.Options)
+		}
+	case "http":
+		_, err = httpManifestURL(cfg.Options)
+	default:
+		return fmt.Errorf("type %s is not supported", cfg.Type)
+	}
+	if err != nil {
+		return err
+	}
+
+	if _, err := pollInterval(cfg.Options); err != nil {
+		return err
+	}
+	_, err = foldersFromFilesStructureOption(cfg)
+	return err
+}
+
+func (v *dashboardValidator) validateFileReader(ctx context.Context, fr *FileReader) error {
+	resolvedPath := fr.resolvedPath()
+	if _, err := os.Stat(resolvedPath); err != nil {
+		return &providerProblem{fmt.Errorf("failed to read dashboards of %q provider: %w", fr.Cfg.Name, err)}
+	}
+
+	provisionedDashboardRefs, err := getProvisionedDashboardsByPath(ctx, v.service, fr.Cfg.Name)
+	if err != nil {
+		return err
+	}
+
+	filesFoundOnDisk := map[string]os.FileInfo{}
+	if err := filepath.Walk(resolvedPath, createWalkFn(filesFoundOnDisk)); err != nil {
+		return &providerProblem{err}
+	}
+
+	// Files are sorted to report the changes in the same order as the files
+	paths := make([]string, 0, len(filesFoundOnDisk))
+	for path := range filesFoundOnDisk {
+		paths = append(paths, path)
+	}
+	sort.Strings(paths)
+
+	for _, path := range paths {
+		folderName := fr.Cfg.Folder
+		if fr.FoldersFromFilesStructure {
+			folderName = ""
+			if dashboardsFolder := filepath.Dir(path); dashboardsFolder != resolvedPath {
+				folderName = filepath.Base(dashboardsFolder)
+			}
+		}
+		if err := v.validateFolder(ctx, fr.Cfg.OrgID, folderName, path); err != nil {
+			return err
+		}
+
+		v.validateDashboard(fr, path, filesFoundOnDisk[path], provisionedDashboardRefs[path])
+	}
+
+	if !fr.Cfg.DisableDeletion {
+		for path, provisioningData := range provisionedDashboardRefs {
+			if _, existsOnDisk := filesFoundOnDisk[path]; !existsOnDisk {
+				v.report.AddChange(validation.ActionDelete, "dashboard", fr.Cfg.OrgID, fmt.Sprintf("dashboard with id %d", provisioningData.DashboardId), path)
+			}
+		}
+	}
+
+	return nil
+}
+
+func (v *dashboardValidator) validateFolder(ctx context.Context, orgID int64, folderName string, path string) error {
+	if folderName == "" || v.folders[orgID][folderName] {
+		return nil
+	}
+	if v.folders[orgID] == nil {
+		v.folders[orgID] = map[string]bool{}
+	}
+	v.folders[orgID][folderName] = true
+
+	err := v.dashboardStore.GetDashboard(ctx, &models.GetDashboardQuery{Slug: slugify.Slugify(folderName), OrgId: orgID})
+	if errors.Is(err, dashboards.ErrDashboardNotFound) {
+		v.report.AddChange(validation.ActionCreate, "folder", orgID, folderName, path)
+		return nil
+	}
+	return err
+}
+
+func (v *dashboardValidator) validateDashboard(fr *FileReader, path string, fileInfo os.FileInfo, provisionedData *models.DashboardProvisioning) {
+	resolvedFileInfo, err := resolveSymlink(fileInfo, path)
+	if err != nil {
+		v.report.AddProblem(path, 0, err)
+		return
+	}
+
+	jsonFile, err := fr.readDashboardFromFile(path, resolvedFileInfo.ModTime(), 0)
+	if err != nil {
+		// nolint:gosec
+		// We can ignore the gosec G304 warning on this one because `path` comes from the provisioning configuration file.
+		data, _ := os.ReadFile(path)
+		v.report.AddProblem(path, validation.JSONLine(data, err), err)
+		return
+	}
+
+	dash := jsonFile.dashboard.Dashboard
+	if dash.Uid != "" {
+		if v.uids[fr.Cfg.OrgID] == nil {
+			v.uids[fr.Cfg.OrgID] = map[string]string{}
+		}
+		if other, ok := v.uids[fr.Cfg.OrgID][dash.Uid]; ok {
+			v.report.AddProblem(path, 0, fmt.Errorf("dashboard uid %q is already used by %s", dash.Uid, other))
+			return
+		}
+		v.uids[fr.Cfg.OrgID][dash.Uid] = path
+	}
+
+	switch {
+	case provisionedData == nil:
+		v.report.AddChange(validation.ActionCreate, "dashboard", fr.Cfg.OrgID, dash.Title, path)
+	case provisionedData.CheckSum != jsonFile.checkSum:
+		// Provisioning saves the whole dashboard when the checksum of its file changes
+		v.report.AddUpdate("dashboard", fr.Cfg.OrgID, dash.Title, path, validation.Fields{"dashboard"})
+	}
+}
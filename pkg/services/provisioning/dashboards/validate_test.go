@@ -0,0 +1,160 @@
+package dashboards
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+
+	"github.com/grafana/grafana/pkg/models"
+	"github.com/grafana/grafana/pkg/services/dashboards"
+	"github.com/grafana/grafana/pkg/services/org"
+	"github.com/grafana/grafana/pkg/services/org/orgtest"
+	"github.com/grafana/grafana/pkg/services/provisioning/validation"
+)
+
+func TestValidate(t *testing.T) {
+	orgFake := &orgtest.FakeOrgService{ExpectedOrg: &org.Org{ID: 1}}
+
+	writeConfig := func(t *testing.T, content string) string {
+		t.Helper()
+		dir := t.TempDir()
+		require.NoError(t, os.WriteFile(filepath.Join(dir, "dashboards.yaml"), []byte(content), 0600))
+		return dir
+	}
+	absPath := func(t *testing.T, path string) string {
+		t.Helper()
+		abs, err := filepath.Abs(path)
+		require.NoError(t, err)
+		return abs
+	}
+
+	t.Run("should return the changes without applying them", func(t *testing.T) {
+		dir := absPath(t, defaultDashboards)
+		configDir := writeConfig(t, `
+apiVersion: 1
+providers:
+- name: default
+  folder: developers
+  options:
+    path: `+dir+`
+`)
+
+		fakeService := &dashboards.FakeDashboardProvisioning{}
+		defer fakeService.AssertExpectations(t)
+		fakeService.On("GetProvisionedDashboardData", mock.Anything, "default").Return([]*models.DashboardProvisioning{
+			{DashboardId: 1, ExternalId: filepath.Join(dir, "dashboard1.json"), CheckSum: "fakechecksum"},
+			{DashboardId: 3, ExternalId: filepath.Join(dir, "dashboard3.json")},
+		}, nil).Once()
+
+		report, err := Validate(context.Background(), configDir, fakeService, orgFake, &fakeDashboardStore{})
+		require.NoError(t, err)
+
+		assert.True(t, report.Valid())
+		assert.ElementsMatch(t, []validation.Change{
+			{Action: validation.ActionCreate, Kind: "folder", OrgID: 1, Name: "developers", File: filepath.Join(dir, "dashboard1.json")},
+			{Action: validation.ActionUpdate, Kind: "dashboard", OrgID: 1, Name: "Grafana1", File: filepath.Join(dir, "dashboard1.json"), Fields: []string{"dashboard"}},
+			{Action: validation.ActionCreate, Kind: "dashboard", OrgID: 1, Name: "Grafana2", File: filepath.Join(dir, "dashboard2.json")},
+			{Action: validation.ActionDelete, Kind: "dashboard", OrgID: 1, Name: "dashboard with id 3", File: filepath.Join(dir, "dashboard3.json")},
+		}, report.Changes)
+	})
+
+	t.Run("should report dashboards with the same uid", func(t *testing.T) {
+		configDir := writeConfig(t, `
+apiVersion: 1
+providers:
+- name: default
+  options:
+    path: `+absPath(t, twoDashboardsWithUID)+`
+`)
+
+		fakeService := &dashboards.FakeDashboardProvisioning{}
+		fakeService.On("GetProvisionedDashboardData", mock.Anything, "default").Return(nil, nil).Once()
+
+		report, err := Validate(context.Background(), configDir, fakeService, orgFake, &fakeDashboardStore{})
+		require.NoError(t, err)
+
+		require.Len(t, report.Problems, 1)
+		assert.Contains(t, report.Problems[0].Message, "Z-phNqGmz")
+		assert.Len(t, report.Changes, 1)
+	})
+
+	t.Run("should report broken dashboards with their line", func(t *testing.T) {
+		dir := t.TempDir()
+		require.NoError(t, os.WriteFile(filepath.Join(dir, "broken.json"), []byte("{\n  \"title\": \"Grafana\",\n}\n"), 0600))
+		configDir := writeConfig(t, `
+apiVersion: 1
+providers:
+- name: default
+  options:
+    path: `+dir+`
+`)
+
+		fakeService := &dashboards.FakeDashboardProvisioning{}
+		fakeService.On("GetProvisionedDashboardData", mock.Anything, "default").Return(nil, nil).Once()
+
+		report, err := Validate(context.Background(), configDir, fakeService, orgFake, &fakeDashboardStore{})
+		require.NoError(t, err)
+
+		require.Len(t, report.Problems, 1)
+		assert.Equal(t, filepath.Join(dir, "broken.json"), report.Problems[0].File)
+		assert.Equal(t, 3, report.Problems[0].Line)
+		assert.Empty(t, report.Changes)
+	})
+
+	t.Run("should report providers with missing organizations and unsupported types", func(t *testing.T) {
+		configDir := writeConfig(t, `
+apiVersion: 1
+providers:
+- name: default
+  options:
+    path: `+absPath(t, defaultDashboards)+`
+- name: other
+  type: ftp
+`)
+
+		report, err := Validate(context.Background(), configDir, &dashboards.FakeDashboardProvisioning{}, &orgtest.FakeOrgService{ExpectedError: org.ErrOrgNotFound}, &fakeDashboardStore{})
+		require.NoError(t, err)
+
+		require.Len(t, report.Problems, 2)
+		assert.Equal(t, 4, report.Problems[0].Line)
+		assert.Equal(t, 7, report.Problems[1].Line)
+		assert.Empty(t, report.Changes)
+	})
+
+	t.Run("should validate git and http providers without creating their work directories", func(t *testing.T) {
+		workDir := filepath.Join(t.TempDir(), "work")
+		configDir := writeConfig(t, `
+apiVersion: 1
+providers:
+- name: git
+  type: git
+  options:
+    url: https://example.com/dashboards.git
+    workDir: `+workDir+`
+- name: http
+  type: http
+  options:
+    url: https://example.com/manifest.json
+    pollInterval: 1ms
+- name: broken
+  type: http
+  options:
+    url: ftp://example.com/manifest.json
+`)
+
+		report, err := Validate(context.Background(), configDir, &dashboards.FakeDashboardProvisioning{}, orgFake, &fakeDashboardStore{})
+		require.NoError(t, err)
+
+		require.Len(t, report.Problems, 2)
+		assert.Equal(t, 9, report.Problems[0].Line)
+		assert.Equal(t, 14, report.Problems[1].Line)
+		assert.Empty(t, report.Changes)
+		assert.NoDirExists(t, workDir)
+		assert.NoDirExists(t, "provisioning")
+	})
+}
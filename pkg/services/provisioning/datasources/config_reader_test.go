@@ -20,6 +20,7 @@ var (
 
 	twoDatasourcesConfig            = "testdata/two-datasources"
 	twoDatasourcesConfigPurgeOthers = "testdata/insert-two-delete-two"
+	secureJSONDataConfig            = "testdata/secure-json-data"
 	deleteOneDatasource             = "testdata/delete-one"
 	doubleDatasourcesConfig         = "testdata/double-default"
 	allProperties                   = "testdata/all-properties"
@@ -396,6 +397,15 @@ func (s *spyStore) AddDataSource(ctx context.Context, cmd *datasources.AddDataSo
 	return nil
 }
 
+// DecryptedValues returns the secure JSON data of the data source, that isn't encrypted by the spy store.
+func (s *spyStore) DecryptedValues(ctx context.Context, ds *datasources.DataSource) (map[string]string, error) {
+	values := make(map[string]string, len(ds.SecureJsonData))
+	for k, v := range ds.SecureJsonData {
+		values[k] = string(v)
+	}
+	return values, nil
+}
+
 func (s *spyStore) UpdateDataSource(ctx context.Context, cmd *datasources.UpdateDataSourceCommand) error {
 	s.updated = append(s.updated, cmd)
 	return nil
@@ -0,0 +1,170 @@
+package datasources
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"github.com/grafana/grafana/pkg/infra/log"
+	"github.com/grafana/grafana/pkg/services/datasources"
+	"github.com/grafana/grafana/pkg/services/org"
+	"github.com/grafana/grafana/pkg/services/provisioning/validation"
+)
+
+// DataSourceReader reads the stored data sources and their secrets, to compare them with the provisioned ones.
+type DataSourceReader interface {
+	GetDataSource(ctx context.Context, query *datasources.GetDataSourceQuery) error
+	DecryptedValues(ctx context.Context, ds *datasources.DataSource) (map[string]string, error)
+}
+
+// Validate reads the provisioning config files in the directory without applying them, and returns the problems
+// of the files and the data sources that provisioning would create, update or delete.
+func Validate(ctx context.Context, configDirectory string, store DataSourceReader, orgService org.Service) (*validation.Report, error) {
+	cr := &configReader{log: log.New("provisioning.datasources"), orgService: orgService}
+	report := validation.NewReport("datasources")
+
+	files, err := os.ReadDir(configDirectory)
+	if err != nil {
+		// The data sources aren't provisioned without the directory
+		return report, nil
+	}
+
+	defaults := map[int64]string{}
+	for _, file := range files {
+		if !strings.HasSuffix(file.Name(), ".yaml") && !strings.HasSuffix(file.Name(), ".yml") {
+			continue
+		}
+		filename := filepath.Join(configDirectory, file.Name())
+
+		cfg, err := cr.parseDatasourceConfig(configDirectory, file)
+		if err != nil {
+			report.AddProblem(filename, 0, err)
+			continue
+		}
+		if cfg == nil {
+			continue
+		}
+
+		// nolint:gosec
+		// We can ignore the gosec G304 warning on this one because `filename` was already read by the config reader
+		data, err := os.ReadFile(filename)
+		if err != nil {
+			return nil, err
+		}
+
+		deleted := map[int64]map[string]bool{}
+		for _, ds := range cfg.DeleteDatasources {
+			if ds == nil {
+				continue
+			}
+			if ds.OrgID == 0 {
+				ds.OrgID = 1
+			}
+
+			existing, err := getDataSource(ctx, store, ds.OrgID, ds.Name)
+			if err != nil {
+				return nil, err
+			}
+			if existing != nil {
+				report.AddChange(validation.ActionDelete, "data source", ds.OrgID, ds.Name, filename)
+				if deleted[ds.OrgID] == nil {
+					deleted[ds.OrgID] = map[string]bool{}
+				}
+				deleted[ds.OrgID][ds.Name] = true
+			}
+		}
+
+		for _, ds := range cfg.Datasources {
+			if ds == nil {
+				continue
+			}
+			if ds.OrgID == 0 {
+				ds.OrgID = 1
+			}
+			line := validation.Line(data, "name", ds.Name)
+
+			if err := cr.validateAccessAndOrgID(ctx, ds); err != nil {
+				report.AddProblem(filename, line, fmt.Errorf("failed to provision %q data source: %w", ds.Name, err))
+				continue
+			}
+			if ds.IsDefault {
+				if other, ok := defaults[ds.OrgID]; ok {
+					report.AddProblem(filename, line, fmt.Errorf("data source %q is marked as default, but %q is already the default data source of organization %d", ds.Name, other, ds.OrgID))
+					continue
+				}
+				defaults[ds.OrgID] = ds.Name
+			}
+
+			existing, err := getDataSource(ctx, store, ds.OrgID, ds.Name)
+			if err != nil {
+				return nil, err
+			}
+			if existing == nil || deleted[ds.OrgID][ds.Name] {
+				report.AddChange(validation.ActionCreate, "data source", ds.OrgID, ds.Name, filename)
+				continue
+			}
+
+			fields, err := changedFields(ctx, store, ds, existing)
+			if err != nil {
+				return nil, err
+			}
+			report.AddUpdate("data source", ds.OrgID, ds.Name, filename, fields)
+		}
+	}
+
+	return report, nil
+}
+
+// getDataSource returns the data source with the name in the organization, or nil if there is none.
+func getDataSource(ctx context.Context, store DataSourceReader, orgID int64, name string) (*datasources.DataSource, error) {
+	query := &datasources.GetDataSourceQuery{OrgId: orgID, Name: name}
+	err := store.GetDataSource(ctx, query)
+	if errors.Is(err, datasources.ErrDataSourceNotFound) {
+		return nil, nil
+	}
+	return query.Result, err
+}
+
+// changedFields returns the fields of the existing data source that provisioning would change. The UID is kept
+// when the config doesn't set it, and the secrets that the config doesn't set are kept too. Correlations are
+// recreated on every update, so they aren't compared.
+func changedFields(ctx context.Context, store DataSourceReader, ds *upsertDataSourceFromConfig, existing *datasources.DataSource) (validation.Fields, error) {
+	cmd := createUpdateCommand(ds, existing.Id)
+
+	var fields validation.Fields
+	fields.Add("uid", cmd.Uid != "" && cmd.Uid != existing.Uid)
+	fields.Add("type", cmd.Type != existing.Type)
+	fields.Add("access", cmd.Access != existing.Access)
+	fields.Add("url", cmd.Url != existing.Url)
+	fields.Add("user", cmd.User != existing.User)
+	fields.Add("database", cmd.Database != existing.Database)
+	fields.Add("basicAuth", cmd.BasicAuth != existing.BasicAuth)
+	fields.Add("basicAuthUser", cmd.BasicAuthUser != existing.BasicAuthUser)
+	fields.Add("withCredentials", cmd.WithCredentials != existing.WithCredentials)
+	fields.Add("isDefault", cmd.IsDefault != existing.IsDefault)
+	fields.Add("editable", cmd.ReadOnly != existing.ReadOnly)
+	var jsonData interface{}
+	if existing.JsonData != nil {
+		jsonData = existing.JsonData.Interface()
+	}
+	fields.Compare("jsonData", cmd.JsonData.Interface(), jsonData)
+
+	if len(cmd.SecureJsonData) > 0 {
+		secrets, err := store.DecryptedValues(ctx, existing)
+		if err != nil {
+			return nil, err
+		}
+		changed := false
+		for k, v := range cmd.SecureJsonData {
+			if secret, ok := secrets[k]; !ok || secret != v {
+				changed = true
+			}
+		}
+		fields.Add("secureJsonData", changed)
+	}
+
+	return fields, nil
+}
@@ -0,0 +1,102 @@
+package datasources
+
+import (
+	"context"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/grafana/grafana/pkg/components/simplejson"
+	"github.com/grafana/grafana/pkg/services/datasources"
+	"github.com/grafana/grafana/pkg/services/org"
+	"github.com/grafana/grafana/pkg/services/org/orgtest"
+	"github.com/grafana/grafana/pkg/services/provisioning/validation"
+)
+
+func TestValidate(t *testing.T) {
+	orgFake := &orgtest.FakeOrgService{ExpectedOrg: &org.Org{ID: 1}}
+
+	t.Run("should return the changes without applying them", func(t *testing.T) {
+		store := &spyStore{items: []*datasources.DataSource{
+			{Name: "old-graphite", OrgId: 1, Id: 1},
+			{Name: "Graphite", OrgId: 1, Id: 2},
+		}}
+		report, err := Validate(context.Background(), twoDatasourcesConfigPurgeOthers, store, orgFake)
+		require.NoError(t, err)
+
+		assert.True(t, report.Valid())
+		assert.ElementsMatch(t, []validation.Change{
+			{Action: validation.ActionCreate, Kind: "data source", OrgID: 1, Name: "Prometheus", File: filepath.Join(twoDatasourcesConfigPurgeOthers, "one-datasources.yaml")},
+			{Action: validation.ActionDelete, Kind: "data source", OrgID: 1, Name: "old-graphite", File: filepath.Join(twoDatasourcesConfigPurgeOthers, "one-datasources.yaml")},
+			{Action: validation.ActionUpdate, Kind: "data source", OrgID: 1, Name: "Graphite", File: filepath.Join(twoDatasourcesConfigPurgeOthers, "two-datasources.yml"), Fields: []string{"type", "access", "url", "editable"}},
+		}, report.Changes)
+
+		assert.Empty(t, store.inserted)
+		assert.Empty(t, store.updated)
+		assert.Empty(t, store.deleted)
+	})
+
+	t.Run("should only report the data sources that differ from the config", func(t *testing.T) {
+		graphite := &datasources.DataSource{Name: "Graphite", OrgId: 1, Id: 2, Type: "graphite", Access: "proxy", Url: "http://localhost:8080", ReadOnly: true, JsonData: simplejson.New()}
+		store := &spyStore{items: []*datasources.DataSource{
+			graphite,
+			{Name: "Prometheus", OrgId: 1, Id: 3, Type: "prometheus", Access: "proxy", Url: "http://localhost:9090", ReadOnly: true},
+		}}
+		report, err := Validate(context.Background(), twoDatasourcesConfigPurgeOthers, store, orgFake)
+		require.NoError(t, err)
+		assert.Empty(t, report.Changes)
+
+		graphite.Url = "http://graphite:8080"
+		graphite.JsonData = simplejson.NewFromAny(map[string]interface{}{"graphiteVersion": "1.1"})
+		report, err = Validate(context.Background(), twoDatasourcesConfigPurgeOthers, store, orgFake)
+		require.NoError(t, err)
+		assert.Equal(t, []validation.Change{
+			{Action: validation.ActionUpdate, Kind: "data source", OrgID: 1, Name: "Graphite", File: filepath.Join(twoDatasourcesConfigPurgeOthers, "two-datasources.yml"), Fields: []string{"url", "jsonData"}},
+		}, report.Changes)
+	})
+
+	t.Run("should compare the secrets set by the config", func(t *testing.T) {
+		elasticsearch := &datasources.DataSource{Name: "Elasticsearch", OrgId: 1, Id: 1, Type: "elasticsearch", Access: "proxy", Url: "http://localhost:9200",
+			BasicAuth: true, BasicAuthUser: "grafana", SecureJsonData: map[string][]byte{"basicAuthPassword": []byte("grafana"), "tlsCACert": []byte("kept")}}
+		store := &spyStore{items: []*datasources.DataSource{elasticsearch}}
+		report, err := Validate(context.Background(), secureJSONDataConfig, store, orgFake)
+		require.NoError(t, err)
+		assert.Empty(t, report.Changes)
+
+		elasticsearch.SecureJsonData["basicAuthPassword"] = []byte("other")
+		report, err = Validate(context.Background(), secureJSONDataConfig, store, orgFake)
+		require.NoError(t, err)
+		assert.Equal(t, []validation.Change{
+			{Action: validation.ActionUpdate, Kind: "data source", OrgID: 1, Name: "Elasticsearch", File: filepath.Join(secureJSONDataConfig, "secure-json-data.yaml"), Fields: []string{"secureJsonData"}},
+		}, report.Changes)
+	})
+
+	t.Run("should report more than one default data source with its line", func(t *testing.T) {
+		report, err := Validate(context.Background(), doubleDatasourcesConfig, &spyStore{}, orgFake)
+		require.NoError(t, err)
+
+		require.Len(t, report.Problems, 1)
+		assert.Equal(t, filepath.Join(doubleDatasourcesConfig, "default-2.yaml"), report.Problems[0].File)
+		assert.Equal(t, 2, report.Problems[0].Line)
+		assert.Len(t, report.Changes, 1)
+	})
+
+	t.Run("should report broken yaml with its line", func(t *testing.T) {
+		report, err := Validate(context.Background(), brokenYaml, &spyStore{}, orgFake)
+		require.NoError(t, err)
+
+		require.Len(t, report.Problems, 1)
+		assert.NotZero(t, report.Problems[0].Line)
+		assert.Empty(t, report.Changes)
+	})
+
+	t.Run("should report missing organizations", func(t *testing.T) {
+		report, err := Validate(context.Background(), twoDatasourcesConfig, &spyStore{}, &orgtest.FakeOrgService{ExpectedError: org.ErrOrgNotFound})
+		require.NoError(t, err)
+
+		assert.Len(t, report.Problems, 2)
+		assert.Empty(t, report.Changes)
+	})
+}
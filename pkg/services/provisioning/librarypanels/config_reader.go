@@ -0,0 +1,114 @@
+package librarypanels
+
+import (
+	"context"
+	"fmt"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"gopkg.in/yaml.v3"
+
+	"github.com/grafana/grafana/pkg/infra/log"
+	"github.com/grafana/grafana/pkg/services/org"
+	"github.com/grafana/grafana/pkg/services/provisioning/utils"
+)
+
+type configReader struct {
+	log        log.Logger
+	orgService org.Service
+}
+
+func (cr *configReader) readConfig(ctx context.Context, path string) ([]*configs, error) {
+	var panelConfigs []*configs
+
+	files, err := os.ReadDir(path)
+	if err != nil {
+		cr.log.Error("can't read library panel provisioning files from directory", "path", path, "error", err)
+		return panelConfigs, nil
+	}
+
+	for _, file := range files {
+		if strings.HasSuffix(file.Name(), ".yaml") || strings.HasSuffix(file.Name(), ".yml") {
+			cfg, err := cr.parseLibraryPanelConfig(path, file)
+			if err != nil {
+				return nil, err
+			}
+
+			if cfg != nil {
+				panelConfigs = append(panelConfigs, cfg)
+			}
+		}
+	}
+
+	if err := cr.validate(ctx, panelConfigs); err != nil {
+		return nil, err
+	}
+
+	return panelConfigs, nil
+}
+
+func (cr *configReader) parseLibraryPanelConfig(path string, file fs.DirEntry) (*configs, error) {
+	filename, _ := filepath.Abs(filepath.Join(path, file.Name()))
+
+	// nolint:gosec
+	// We can ignore the gosec G304 warning on this one because `filename` comes from ps.Cfg.ProvisioningPath
+	yamlFile, err := os.ReadFile(filename)
+	if err != nil {
+		return nil, err
+	}
+
+	var apiVersion *configVersion
+	err = yaml.Unmarshal(yamlFile, &apiVersion)
+	if err != nil {
+		return nil, err
+	}
+
+	if apiVersion == nil {
+		// The file is empty or only contains comments
+		return nil, nil
+	}
+
+	if apiVersion.APIVersion != 1 {
+		return nil, fmt.Errorf("%s: unsupported apiVersion %d", file.Name(), apiVersion.APIVersion)
+	}
+
+	v1 := &configsV1{}
+	err = yaml.Unmarshal(yamlFile, v1)
+	if err != nil {
+		return nil, err
+	}
+
+	return v1.mapToLibraryPanelsFromConfig(apiVersion.APIVersion), nil
+}
+
+// validate checks the required fields of the providers and applies the default organization, and that providers
+// are only declared once.
+func (cr *configReader) validate(ctx context.Context, panelConfigs []*configs) error {
+	names := map[string]bool{}
+
+	for _, cfg := range panelConfigs {
+		for _, p := range cfg.Providers {
+			if p == nil {
+				continue
+			}
+			if p.Name == "" || p.Path == "" {
+				return fmt.Errorf("library panel provider %q in configuration doesn't contain required fields name and path", p.Name)
+			}
+			if names[p.Name] {
+				return fmt.Errorf("library panel provider %q is provisioned more than once", p.Name)
+			}
+			names[p.Name] = true
+
+			if p.OrgID == 0 {
+				p.OrgID = 1
+			}
+			if err := utils.CheckOrgExists(ctx, cr.orgService, p.OrgID); err != nil {
+				return fmt.Errorf("failed to provision library panels with %q provider: %w", p.Name, err)
+			}
+		}
+	}
+
+	return nil
+}
@@ -0,0 +1,57 @@
+package librarypanels
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/grafana/grafana/pkg/infra/log"
+	"github.com/grafana/grafana/pkg/services/org"
+	"github.com/grafana/grafana/pkg/services/org/orgtest"
+)
+
+const (
+	allProperties     = "testdata/all-properties"
+	duplicateProvider = "testdata/duplicate-provider"
+	missingPath       = "testdata/missing-path"
+)
+
+func TestConfigReader(t *testing.T) {
+	newReader := func() *configReader {
+		return &configReader{log: log.New("test logger"), orgService: &orgtest.FakeOrgService{ExpectedOrg: &org.Org{ID: 1}}}
+	}
+
+	t.Run("Can read all properties", func(t *testing.T) {
+		cfgs, err := newReader().readConfig(context.Background(), allProperties)
+		require.NoError(t, err)
+		require.Len(t, cfgs, 1)
+
+		require.Equal(t, []*providerConfig{
+			{Name: "team", OrgID: 1, Folder: "Library panels", FolderUID: "library-panels", Path: "testdata/panels"},
+			{Name: "general", OrgID: 1, Path: "testdata/general"},
+		}, cfgs[0].Providers)
+	})
+
+	t.Run("Provider without a path should return error", func(t *testing.T) {
+		_, err := newReader().readConfig(context.Background(), missingPath)
+		require.EqualError(t, err, `library panel provider "team" in configuration doesn't contain required fields name and path`)
+	})
+
+	t.Run("Provider provisioned twice should return error", func(t *testing.T) {
+		_, err := newReader().readConfig(context.Background(), duplicateProvider)
+		require.EqualError(t, err, `library panel provider "team" is provisioned more than once`)
+	})
+
+	t.Run("Missing organization should return error", func(t *testing.T) {
+		reader := &configReader{log: log.New("test logger"), orgService: &orgtest.FakeOrgService{ExpectedError: org.ErrOrgNotFound}}
+		_, err := reader.readConfig(context.Background(), allProperties)
+		require.Error(t, err)
+	})
+
+	t.Run("Missing directory should not return error", func(t *testing.T) {
+		cfgs, err := newReader().readConfig(context.Background(), "testdata/missing")
+		require.NoError(t, err)
+		require.Empty(t, cfgs)
+	})
+}
@@ -0,0 +1,132 @@
+package librarypanels
+
+import (
+	"encoding/json"
+	"fmt"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"github.com/grafana/grafana/pkg/models"
+	"github.com/grafana/grafana/pkg/services/libraryelements"
+	"github.com/grafana/grafana/pkg/util"
+)
+
+// readLibraryPanels reads the library panels of the JSON files in the directory and its subdirectories, and
+// returns the errors of the files that aren't valid library panels.
+func readLibraryPanels(path string) ([]*libraryPanelFromFile, map[string]error, error) {
+	var panels []*libraryPanelFromFile
+	fileErrors := map[string]error{}
+
+	resolvedPath, err := filepath.EvalSymlinks(path)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	err = filepath.WalkDir(resolvedPath, func(p string, d fs.DirEntry, err error) error {
+		if err != nil {
+			return err
+		}
+		if d.IsDir() || !strings.HasSuffix(d.Name(), ".json") {
+			return nil
+		}
+
+		// nolint:gosec
+		// We can ignore the gosec G304 warning on this one because `p` comes from the provider path
+		data, err := os.ReadFile(p)
+		if err != nil {
+			return err
+		}
+
+		panel, err := parseLibraryPanel(data)
+		if err != nil {
+			fileErrors[p] = err
+			return nil
+		}
+		panel.File = p
+		panels = append(panels, panel)
+		return nil
+	})
+	if err != nil {
+		return nil, nil, err
+	}
+
+	return panels, fileErrors, nil
+}
+
+// parseLibraryPanel parses a library panel exported by the library elements API, or the model of a library panel
+// of a dashboard.
+func parseLibraryPanel(data []byte) (*libraryPanelFromFile, error) {
+	var fields map[string]json.RawMessage
+	if err := json.Unmarshal(data, &fields); err != nil {
+		return nil, err
+	}
+
+	var panel *libraryPanelFromFile
+	var err error
+	if _, ok := fields["model"]; ok {
+		panel, err = parseLibraryElementExport(data)
+	} else {
+		panel, err = parseLibraryPanelModel(data)
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	if panel.UID == "" || panel.Name == "" {
+		return nil, fmt.Errorf("library panel doesn't contain required fields uid and name")
+	}
+	if !util.IsValidShortUID(panel.UID) || util.IsShortUIDTooLong(panel.UID) {
+		return nil, fmt.Errorf("library panel has an invalid uid %q", panel.UID)
+	}
+
+	return panel, nil
+}
+
+func parseLibraryElementExport(data []byte) (*libraryPanelFromFile, error) {
+	var export libraryelements.LibraryElementExport
+	if err := json.Unmarshal(data, &export); err != nil {
+		return nil, err
+	}
+	if export.Kind != 0 && export.Kind != int64(models.PanelElement) {
+		return nil, fmt.Errorf("library element %q is not a library panel", export.UID)
+	}
+
+	return &libraryPanelFromFile{
+		UID:   export.UID,
+		Name:  export.Name,
+		Model: export.Model,
+	}, nil
+}
+
+// parseLibraryPanelModel parses the model of a panel of a dashboard, that refers to its library panel with the
+// libraryPanel field.
+func parseLibraryPanelModel(data []byte) (*libraryPanelFromFile, error) {
+	var model map[string]interface{}
+	if err := json.Unmarshal(data, &model); err != nil {
+		return nil, err
+	}
+
+	panel := &libraryPanelFromFile{}
+	if libraryPanel, ok := model["libraryPanel"].(map[string]interface{}); ok {
+		panel.UID, _ = libraryPanel["uid"].(string)
+		panel.Name, _ = libraryPanel["name"].(string)
+	}
+	if panel.Name == "" {
+		panel.Name, _ = model["title"].(string)
+	}
+
+	// The position and ID of the panel are specific to the dashboard
+	delete(model, "libraryPanel")
+	delete(model, "gridPos")
+	delete(model, "id")
+
+	var err error
+	panel.Model, err = json.Marshal(model)
+	if err != nil {
+		return nil, err
+	}
+
+	return panel, nil
+}
@@ -0,0 +1,219 @@
+package librarypanels
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"reflect"
+
+	"github.com/grafana/grafana/pkg/infra/log"
+	"github.com/grafana/grafana/pkg/models"
+	"github.com/grafana/grafana/pkg/services/accesscontrol"
+	"github.com/grafana/grafana/pkg/services/dashboards"
+	"github.com/grafana/grafana/pkg/services/folder"
+	"github.com/grafana/grafana/pkg/services/libraryelements"
+	"github.com/grafana/grafana/pkg/services/org"
+	"github.com/grafana/grafana/pkg/services/user"
+)
+
+type LibraryElementStore interface {
+	GetElement(c context.Context, signedInUser *user.SignedInUser, UID string) (libraryelements.LibraryElementDTO, error)
+	CreateElement(c context.Context, signedInUser *user.SignedInUser, cmd libraryelements.CreateLibraryElementCommand) (libraryelements.LibraryElementDTO, error)
+	PatchElement(c context.Context, signedInUser *user.SignedInUser, cmd libraryelements.PatchLibraryElementCommand, UID string) (libraryelements.LibraryElementDTO, error)
+}
+
+type FolderStore interface {
+	Get(ctx context.Context, cmd *folder.GetFolderQuery) (*folder.Folder, error)
+	Create(ctx context.Context, cmd *folder.CreateFolderCommand) (*folder.Folder, error)
+}
+
+type ProvisionerConfig struct {
+	Path                  string
+	LibraryElementService LibraryElementStore
+	FolderService         FolderStore
+	OrgService            org.Service
+}
+
+var provisionerPermissions = []accesscontrol.Permission{
+	{Action: dashboards.ActionFoldersCreate},
+	{Action: dashboards.ActionFoldersRead, Scope: dashboards.ScopeFoldersAll},
+	{Action: dashboards.ActionFoldersWrite, Scope: dashboards.ScopeFoldersAll},
+}
+
+// Provision scans a directory for provisioning config files and provisions the library panels of the providers
+// in those files.
+func Provision(ctx context.Context, cfg ProvisionerConfig) error {
+	logger := log.New("provisioning.librarypanels")
+	lpp := LibraryPanelProvisioner{
+		log:         logger,
+		cfgProvider: &configReader{log: logger, orgService: cfg.OrgService},
+		cfg:         cfg,
+	}
+	return lpp.applyChanges(ctx, cfg.Path)
+}
+
+// LibraryPanelProvisioner is responsible for provisioning library panels based on configuration read by the
+// `configReader`
+type LibraryPanelProvisioner struct {
+	log         log.Logger
+	cfgProvider *configReader
+	cfg         ProvisionerConfig
+}
+
+func (lpp *LibraryPanelProvisioner) applyChanges(ctx context.Context, configPath string) error {
+	configs, err := lpp.cfgProvider.readConfig(ctx, configPath)
+	if err != nil {
+		return err
+	}
+
+	for _, cfg := range configs {
+		for _, p := range cfg.Providers {
+			if err := lpp.applyProvider(ctx, p); err != nil {
+				return fmt.Errorf("failed to provision library panels with %q provider: %w", p.Name, err)
+			}
+		}
+	}
+
+	return nil
+}
+
+func (lpp *LibraryPanelProvisioner) applyProvider(ctx context.Context, p *providerConfig) error {
+	panels, fileErrors, err := readLibraryPanels(p.Path)
+	if err != nil {
+		return err
+	}
+	for file, err := range fileErrors {
+		lpp.log.Error("failed to load library panel from file", "provider", p.Name, "file", file, "error", err)
+	}
+
+	folderID, err := lpp.getOrCreateFolderID(ctx, p)
+	if err != nil {
+		return err
+	}
+
+	files := map[string]string{}
+	for _, panel := range panels {
+		if file, ok := files[panel.UID]; ok {
+			lpp.log.Warn("library panel with the same uid is provisioned more than once, skipping file", "provider", p.Name, "uid", panel.UID, "file", panel.File, "provisionedFile", file)
+			continue
+		}
+		files[panel.UID] = panel.File
+
+		if err := lpp.applyPanel(ctx, p.OrgID, folderID, panel); err != nil {
+			return fmt.Errorf("%s: %w", panel.File, err)
+		}
+	}
+
+	return nil
+}
+
+// getOrCreateFolderID returns the ID of the folder of the provider, and creates the folder when it doesn't exist.
+// Library panels of providers without a folder are provisioned in the General folder.
+func (lpp *LibraryPanelProvisioner) getOrCreateFolderID(ctx context.Context, p *providerConfig) (int64, error) {
+	if p.Folder == "" && p.FolderUID == "" {
+		return 0, nil
+	}
+
+	signedInUser := provisionerUser(p.OrgID)
+	query := &folder.GetFolderQuery{OrgID: p.OrgID, SignedInUser: signedInUser}
+	if p.FolderUID != "" {
+		query.UID = &p.FolderUID
+	} else {
+		query.Title = &p.Folder
+	}
+
+	existing, err := lpp.cfg.FolderService.Get(ctx, query)
+	if err == nil {
+		return existing.ID, nil
+	}
+	if !isFolderNotFound(err) {
+		return 0, err
+	}
+	if p.Folder == "" {
+		return 0, fmt.Errorf("folder with uid %q doesn't exist", p.FolderUID)
+	}
+
+	lpp.log.Info("inserting folder of library panels from configuration", "uid", p.FolderUID, "title", p.Folder)
+	created, err := lpp.cfg.FolderService.Create(ctx, &folder.CreateFolderCommand{
+		UID:          p.FolderUID,
+		OrgID:        p.OrgID,
+		Title:        p.Folder,
+		SignedInUser: signedInUser,
+	})
+	if err != nil {
+		return 0, err
+	}
+	return created.ID, nil
+}
+
+func (lpp *LibraryPanelProvisioner) applyPanel(ctx context.Context, orgID, folderID int64, panel *libraryPanelFromFile) error {
+	signedInUser := provisionerUser(orgID)
+
+	existing, err := lpp.cfg.LibraryElementService.GetElement(ctx, signedInUser, panel.UID)
+	if errors.Is(err, libraryelements.ErrLibraryElementNotFound) {
+		lpp.log.Debug("inserting library panel from configuration", "uid", panel.UID, "name", panel.Name)
+		_, err := lpp.cfg.LibraryElementService.CreateElement(ctx, signedInUser, libraryelements.CreateLibraryElementCommand{
+			FolderID: folderID,
+			Name:     panel.Name,
+			Model:    panel.Model,
+			Kind:     int64(models.PanelElement),
+			UID:      panel.UID,
+		})
+		return err
+	}
+	if err != nil {
+		return err
+	}
+
+	if existing.Kind != int64(models.PanelElement) {
+		return fmt.Errorf("library element with uid %q is not a library panel", panel.UID)
+	}
+
+	changed, err := modelChanged(existing, panel.Model)
+	if err != nil {
+		return err
+	}
+	if !changed && existing.Name == panel.Name && existing.FolderID == folderID {
+		return nil
+	}
+
+	lpp.log.Debug("updating library panel from configuration", "uid", panel.UID, "name", panel.Name)
+	_, err = lpp.cfg.LibraryElementService.PatchElement(ctx, signedInUser, libraryelements.PatchLibraryElementCommand{
+		FolderID: folderID,
+		Name:     panel.Name,
+		Model:    panel.Model,
+		Kind:     int64(models.PanelElement),
+		Version:  existing.Version,
+	}, panel.UID)
+	return err
+}
+
+// modelChanged compares the model of the library panel with the model in the file. The type and description of
+// the stored model are filled in when missing, so they're only compared when the file sets them.
+func modelChanged(existing libraryelements.LibraryElementDTO, model json.RawMessage) (bool, error) {
+	var existingModel, fileModel map[string]interface{}
+	if err := json.Unmarshal(existing.Model, &existingModel); err != nil {
+		return false, err
+	}
+	if err := json.Unmarshal(model, &fileModel); err != nil {
+		return false, err
+	}
+
+	if _, ok := fileModel["type"]; !ok {
+		fileModel["type"] = existing.Type
+	}
+	if _, ok := fileModel["description"]; !ok {
+		fileModel["description"] = existing.Description
+	}
+
+	return !reflect.DeepEqual(existingModel, fileModel), nil
+}
+
+func isFolderNotFound(err error) bool {
+	return errors.Is(err, dashboards.ErrFolderNotFound) || errors.Is(err, folder.ErrFolderNotFound)
+}
+
+func provisionerUser(orgID int64) *user.SignedInUser {
+	return accesscontrol.BackgroundUser("library_panel_provisioning", orgID, org.RoleAdmin, provisionerPermissions)
+}
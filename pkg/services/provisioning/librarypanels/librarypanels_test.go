@@ -0,0 +1,227 @@
+package librarypanels
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/grafana/grafana/pkg/models"
+	"github.com/grafana/grafana/pkg/services/folder"
+	"github.com/grafana/grafana/pkg/services/libraryelements"
+	"github.com/grafana/grafana/pkg/services/org"
+	"github.com/grafana/grafana/pkg/services/org/orgtest"
+	"github.com/grafana/grafana/pkg/services/user"
+)
+
+func TestLibraryPanelProvisioner(t *testing.T) {
+	setup := func() (ProvisionerConfig, *fakeLibraryElements, *fakeFolders) {
+		elements := &fakeLibraryElements{elements: map[string]*libraryelements.LibraryElementDTO{}}
+		folders := &fakeFolders{}
+		cfg := ProvisionerConfig{
+			Path:                  allProperties,
+			LibraryElementService: elements,
+			FolderService:         folders,
+			OrgService:            &orgtest.FakeOrgService{ExpectedOrg: &org.Org{ID: 1}},
+		}
+		return cfg, elements, folders
+	}
+
+	t.Run("should provision library panels and their folder", func(t *testing.T) {
+		cfg, elements, folders := setup()
+		require.NoError(t, Provision(context.Background(), cfg))
+
+		require.Len(t, folders.folders, 1)
+		assert.Equal(t, "library-panels", folders.folders[0].UID)
+		assert.Equal(t, "Library panels", folders.folders[0].Title)
+
+		// The file without an uid is skipped
+		require.Len(t, elements.elements, 3)
+
+		cpu := elements.elements["cpu-usage"]
+		require.NotNil(t, cpu)
+		assert.Equal(t, "CPU usage", cpu.Name)
+		assert.Equal(t, int64(models.PanelElement), cpu.Kind)
+		assert.Equal(t, folders.folders[0].ID, cpu.FolderID)
+
+		memory := elements.elements["memory-usage"]
+		require.NotNil(t, memory)
+		assert.Equal(t, "Memory usage", memory.Name)
+		assert.JSONEq(t, `{"description": "", "title": "Memory", "type": "timeseries"}`, string(memory.Model))
+
+		logs := elements.elements["logs"]
+		require.NotNil(t, logs)
+		assert.Equal(t, "Logs", logs.Name)
+		assert.Equal(t, int64(0), logs.FolderID)
+	})
+
+	t.Run("should not update unchanged library panels", func(t *testing.T) {
+		cfg, elements, _ := setup()
+		require.NoError(t, Provision(context.Background(), cfg))
+		require.NoError(t, Provision(context.Background(), cfg))
+
+		for uid, element := range elements.elements {
+			assert.Equal(t, int64(1), element.Version, uid)
+		}
+	})
+
+	t.Run("should revert changes made outside of provisioning", func(t *testing.T) {
+		cfg, elements, _ := setup()
+		require.NoError(t, Provision(context.Background(), cfg))
+
+		cpu := elements.elements["cpu-usage"]
+		cpu.Name = "Renamed"
+		cpu.Model = json.RawMessage(`{"title": "Changed", "type": "timeseries", "description": ""}`)
+		elements.elements["logs"].FolderID = 100
+
+		require.NoError(t, Provision(context.Background(), cfg))
+
+		cpu = elements.elements["cpu-usage"]
+		assert.Equal(t, int64(2), cpu.Version)
+		assert.Equal(t, "CPU usage", cpu.Name)
+		assert.JSONEq(t, `{
+			"datasource": "${DS_PROMETHEUS}",
+			"description": "CPU usage of the hosts",
+			"title": "CPU usage",
+			"type": "timeseries"
+		}`, string(cpu.Model))
+
+		logs := elements.elements["logs"]
+		assert.Equal(t, int64(2), logs.Version)
+		assert.Equal(t, int64(0), logs.FolderID)
+		assert.Equal(t, int64(1), elements.elements["memory-usage"].Version)
+	})
+
+	t.Run("should use the existing folder", func(t *testing.T) {
+		cfg, elements, folders := setup()
+		folders.folders = append(folders.folders, &folder.Folder{ID: 10, UID: "library-panels", OrgID: 1, Title: "Existing"})
+		require.NoError(t, Provision(context.Background(), cfg))
+
+		require.Len(t, folders.folders, 1)
+		assert.Equal(t, int64(10), elements.elements["cpu-usage"].FolderID)
+	})
+
+	t.Run("should return error when a library element with the same uid is not a library panel", func(t *testing.T) {
+		cfg, elements, _ := setup()
+		elements.elements["logs"] = &libraryelements.LibraryElementDTO{UID: "logs", Kind: int64(models.VariableElement), Model: json.RawMessage(`{}`)}
+		require.Error(t, Provision(context.Background(), cfg))
+	})
+}
+
+func TestParseLibraryPanel(t *testing.T) {
+	t.Run("should parse exported library panels", func(t *testing.T) {
+		panel, err := parseLibraryPanel([]byte(`{"uid": "cpu", "name": "CPU", "kind": 1, "model": {"type": "stat"}}`))
+		require.NoError(t, err)
+		assert.Equal(t, "cpu", panel.UID)
+		assert.Equal(t, "CPU", panel.Name)
+		assert.JSONEq(t, `{"type": "stat"}`, string(panel.Model))
+	})
+
+	t.Run("should return error for exported library variables", func(t *testing.T) {
+		_, err := parseLibraryPanel([]byte(`{"uid": "host", "name": "host", "kind": 2, "model": {}}`))
+		require.EqualError(t, err, `library element "host" is not a library panel`)
+	})
+
+	t.Run("should return error for invalid uids", func(t *testing.T) {
+		_, err := parseLibraryPanel([]byte(`{"libraryPanel": {"uid": "cpu usage"}, "title": "CPU"}`))
+		require.EqualError(t, err, `library panel has an invalid uid "cpu usage"`)
+	})
+
+	t.Run("should return error for panels without an uid", func(t *testing.T) {
+		_, err := parseLibraryPanel([]byte(`{"title": "CPU"}`))
+		require.EqualError(t, err, "library panel doesn't contain required fields uid and name")
+	})
+}
+
+type fakeLibraryElements struct {
+	elements map[string]*libraryelements.LibraryElementDTO
+	lastID   int64
+}
+
+func (f *fakeLibraryElements) GetElement(c context.Context, signedInUser *user.SignedInUser, UID string) (libraryelements.LibraryElementDTO, error) {
+	element, ok := f.elements[UID]
+	if !ok {
+		return libraryelements.LibraryElementDTO{}, libraryelements.ErrLibraryElementNotFound
+	}
+	return *element, nil
+}
+
+func (f *fakeLibraryElements) CreateElement(c context.Context, signedInUser *user.SignedInUser, cmd libraryelements.CreateLibraryElementCommand) (libraryelements.LibraryElementDTO, error) {
+	f.lastID++
+	element := &libraryelements.LibraryElementDTO{
+		ID:       f.lastID,
+		OrgID:    signedInUser.OrgID,
+		FolderID: cmd.FolderID,
+		UID:      cmd.UID,
+		Name:     cmd.Name,
+		Kind:     cmd.Kind,
+		Version:  1,
+	}
+	if err := syncModel(element, cmd.Model); err != nil {
+		return libraryelements.LibraryElementDTO{}, err
+	}
+	f.elements[cmd.UID] = element
+	return *element, nil
+}
+
+func (f *fakeLibraryElements) PatchElement(c context.Context, signedInUser *user.SignedInUser, cmd libraryelements.PatchLibraryElementCommand, UID string) (libraryelements.LibraryElementDTO, error) {
+	element, ok := f.elements[UID]
+	if !ok {
+		return libraryelements.LibraryElementDTO{}, libraryelements.ErrLibraryElementNotFound
+	}
+	if element.Version != cmd.Version {
+		return libraryelements.LibraryElementDTO{}, errors.New("the library element has been changed by someone else")
+	}
+	element.FolderID = cmd.FolderID
+	element.Name = cmd.Name
+	element.Version++
+	if err := syncModel(element, cmd.Model); err != nil {
+		return libraryelements.LibraryElementDTO{}, err
+	}
+	return *element, nil
+}
+
+// syncModel stores the type and description of the model like the library elements service.
+func syncModel(element *libraryelements.LibraryElementDTO, model json.RawMessage) error {
+	var m map[string]interface{}
+	if err := json.Unmarshal(model, &m); err != nil {
+		return err
+	}
+	if t, ok := m["type"].(string); ok {
+		element.Type = t
+	}
+	m["type"] = element.Type
+	if d, ok := m["description"].(string); ok {
+		element.Description = d
+	}
+	m["description"] = element.Description
+
+	var err error
+	element.Model, err = json.Marshal(m)
+	return err
+}
+
+type fakeFolders struct {
+	folders []*folder.Folder
+}
+
+func (f *fakeFolders) Get(ctx context.Context, cmd *folder.GetFolderQuery) (*folder.Folder, error) {
+	for _, existing := range f.folders {
+		if existing.OrgID != cmd.OrgID {
+			continue
+		}
+		if (cmd.UID != nil && existing.UID == *cmd.UID) || (cmd.Title != nil && existing.Title == *cmd.Title) {
+			return existing, nil
+		}
+	}
+	return nil, folder.ErrFolderNotFound
+}
+
+func (f *fakeFolders) Create(ctx context.Context, cmd *folder.CreateFolderCommand) (*folder.Folder, error) {
+	created := &folder.Folder{ID: int64(len(f.folders) + 1), UID: cmd.UID, OrgID: cmd.OrgID, Title: cmd.Title}
+	f.folders = append(f.folders, created)
+	return created, nil
+}
@@ -0,0 +1,71 @@
+package librarypanels
+
+import (
+	"encoding/json"
+
+	"github.com/grafana/grafana/pkg/services/provisioning/values"
+)
+
+// configVersion is used to figure out which API version a config uses.
+type configVersion struct {
+	APIVersion int64 `json:"apiVersion" yaml:"apiVersion"`
+}
+
+type configs struct {
+	APIVersion int64
+
+	Providers []*providerConfig
+}
+
+// providerConfig is a directory of library panel files that are provisioned in a folder.
+type providerConfig struct {
+	Name      string
+	OrgID     int64
+	Folder    string
+	FolderUID string
+	Path      string
+}
+
+// libraryPanelFromFile is a library panel read from a file of a provider.
+type libraryPanelFromFile struct {
+	File  string
+	UID   string
+	Name  string
+	Model json.RawMessage
+}
+
+type configsV1 struct {
+	configVersion
+
+	Providers []*providerConfigV1 `json:"providers" yaml:"providers"`
+}
+
+type providerConfigV1 struct {
+	Name      values.StringValue `json:"name" yaml:"name"`
+	OrgID     values.Int64Value  `json:"orgId" yaml:"orgId"`
+	Folder    values.StringValue `json:"folder" yaml:"folder"`
+	FolderUID values.StringValue `json:"folderUid" yaml:"folderUid"`
+	Path      values.StringValue `json:"path" yaml:"path"`
+}
+
+func (cfg *configsV1) mapToLibraryPanelsFromConfig(apiVersion int64) *configs {
+	r := &configs{}
+
+	r.APIVersion = apiVersion
+
+	if cfg == nil {
+		return r
+	}
+
+	for _, p := range cfg.Providers {
+		r.Providers = append(r.Providers, &providerConfig{
+			Name:      p.Name.Value(),
+			OrgID:     p.OrgID.Value(),
+			Folder:    p.Folder.Value(),
+			FolderUID: p.FolderUID.Value(),
+			Path:      p.Path.Value(),
+		})
+	}
+
+	return r
+}
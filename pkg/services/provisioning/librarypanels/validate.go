@@ -0,0 +1,180 @@
+package librarypanels
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+
+	"github.com/grafana/grafana/pkg/infra/log"
+	"github.com/grafana/grafana/pkg/models"
+	"github.com/grafana/grafana/pkg/services/folder"
+	"github.com/grafana/grafana/pkg/services/libraryelements"
+	"github.com/grafana/grafana/pkg/services/org"
+	"github.com/grafana/grafana/pkg/services/provisioning/validation"
+	"github.com/grafana/grafana/pkg/services/user"
+)
+
+type LibraryElementReader interface {
+	GetElement(c context.Context, signedInUser *user.SignedInUser, UID string) (libraryelements.LibraryElementDTO, error)
+}
+
+type FolderReader interface {
+	Get(ctx context.Context, cmd *folder.GetFolderQuery) (*folder.Folder, error)
+}
+
+type ValidatorConfig struct {
+	Path                  string
+	LibraryElementService LibraryElementReader
+	FolderService         FolderReader
+	OrgService            org.Service
+}
+
+// Validate reads the library panel provisioning files in the directory and the library panels of their providers
+// without applying them, and returns the problems of the files and the library panels and folders that
+// provisioning would create or update.
+func Validate(ctx context.Context, cfg ValidatorConfig) (*validation.Report, error) {
+	report := validation.NewReport("library panels")
+	cr := &configReader{log: log.New("provisioning.librarypanels"), orgService: cfg.OrgService}
+
+	files, err := os.ReadDir(cfg.Path)
+	if err != nil {
+		// Library panels aren't provisioned without the directory
+		return report, nil
+	}
+
+	var panelConfigs []*configs
+	for _, file := range files {
+		if !strings.HasSuffix(file.Name(), ".yaml") && !strings.HasSuffix(file.Name(), ".yml") {
+			continue
+		}
+		filename := filepath.Join(cfg.Path, file.Name())
+
+		c, err := cr.parseLibraryPanelConfig(cfg.Path, file)
+		if err != nil {
+			report.AddProblem(filename, 0, err)
+			continue
+		}
+		if c == nil {
+			continue
+		}
+		// Each file is validated with the valid files before it, to find providers declared twice
+		if err := cr.validate(ctx, append(panelConfigs, c)); err != nil {
+			report.AddProblem(filename, 0, err)
+			continue
+		}
+		panelConfigs = append(panelConfigs, c)
+
+		// nolint:gosec
+		// We can ignore the gosec G304 warning on this one because `filename` was already read by the config reader
+		data, err := os.ReadFile(filename)
+		if err != nil {
+			return nil, err
+		}
+		for _, p := range c.Providers {
+			if err := validateProvider(ctx, cfg, report, filename, data, p); err != nil {
+				return nil, err
+			}
+		}
+	}
+
+	return report, nil
+}
+
+func validateProvider(ctx context.Context, cfg ValidatorConfig, report *validation.Report, filename string, data []byte, p *providerConfig) error {
+	line := validation.Line(data, "name", p.Name)
+	panels, fileErrors, err := readLibraryPanels(p.Path)
+	if err != nil {
+		report.AddProblem(filename, line, fmt.Errorf("failed to read library panels of %q provider: %w", p.Name, err))
+		return nil
+	}
+
+	// Files are sorted to report the problems in the same order as the files
+	paths := make([]string, 0, len(fileErrors))
+	for path := range fileErrors {
+		paths = append(paths, path)
+	}
+	sort.Strings(paths)
+	for _, path := range paths {
+		// nolint:gosec
+		// We can ignore the gosec G304 warning on this one because `path` comes from the provider path
+		panelData, _ := os.ReadFile(path)
+		report.AddProblem(path, validation.JSONLine(panelData, fileErrors[path]), fileErrors[path])
+	}
+
+	folderID, ok, err := validateFolder(ctx, cfg, report, filename, line, p)
+	if err != nil || !ok {
+		return err
+	}
+
+	signedInUser := provisionerUser(p.OrgID)
+	files := map[string]string{}
+	for _, panel := range panels {
+		if file, ok := files[panel.UID]; ok {
+			report.AddProblem(panel.File, 0, fmt.Errorf("library panel uid %q is already used by %s, the file is skipped", panel.UID, file))
+			continue
+		}
+		files[panel.UID] = panel.File
+
+		existing, err := cfg.LibraryElementService.GetElement(ctx, signedInUser, panel.UID)
+		if errors.Is(err, libraryelements.ErrLibraryElementNotFound) {
+			report.AddChange(validation.ActionCreate, "library panel", p.OrgID, panel.Name, panel.File)
+			continue
+		}
+		if err != nil {
+			return err
+		}
+		if existing.Kind != int64(models.PanelElement) {
+			report.AddProblem(panel.File, 0, fmt.Errorf("library element with uid %q is not a library panel", panel.UID))
+			continue
+		}
+
+		changed, err := modelChanged(existing, panel.Model)
+		if err != nil {
+			return err
+		}
+		var fields validation.Fields
+		fields.Add("name", existing.Name != panel.Name)
+		fields.Add("folder", existing.FolderID != folderID)
+		fields.Add("model", changed)
+		report.AddUpdate("library panel", p.OrgID, panel.Name, panel.File, fields)
+	}
+
+	return nil
+}
+
+// newFolderID is the folder ID of the library panels of a folder that provisioning would create, that differs
+// from the folder ID of any existing library panel.
+const newFolderID int64 = -1
+
+// validateFolder returns the ID of the folder of the provider, or false when the folder can't be provisioned.
+func validateFolder(ctx context.Context, cfg ValidatorConfig, report *validation.Report, filename string, line int, p *providerConfig) (int64, bool, error) {
+	if p.Folder == "" && p.FolderUID == "" {
+		return 0, true, nil
+	}
+
+	query := &folder.GetFolderQuery{OrgID: p.OrgID, SignedInUser: provisionerUser(p.OrgID)}
+	if p.FolderUID != "" {
+		query.UID = &p.FolderUID
+	} else {
+		query.Title = &p.Folder
+	}
+
+	existing, err := cfg.FolderService.Get(ctx, query)
+	if err == nil {
+		return existing.ID, true, nil
+	}
+	if !isFolderNotFound(err) {
+		return 0, false, err
+	}
+	if p.Folder == "" {
+		report.AddProblem(filename, line, fmt.Errorf("failed to provision library panels with %q provider: folder with uid %q doesn't exist", p.Name, p.FolderUID))
+		return 0, false, nil
+	}
+
+	report.AddChange(validation.ActionCreate, "folder", p.OrgID, p.Folder, filename)
+	return newFolderID, true, nil
+}
@@ -0,0 +1,101 @@
+package librarypanels
+
+import (
+	"context"
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/grafana/grafana/pkg/models"
+	"github.com/grafana/grafana/pkg/services/libraryelements"
+	"github.com/grafana/grafana/pkg/services/org"
+	"github.com/grafana/grafana/pkg/services/org/orgtest"
+	"github.com/grafana/grafana/pkg/services/provisioning/validation"
+)
+
+func TestValidate(t *testing.T) {
+	setup := func(path string) (ValidatorConfig, *fakeLibraryElements, *fakeFolders) {
+		elements := &fakeLibraryElements{elements: map[string]*libraryelements.LibraryElementDTO{}}
+		folders := &fakeFolders{}
+		cfg := ValidatorConfig{
+			Path:                  path,
+			LibraryElementService: elements,
+			FolderService:         folders,
+			OrgService:            &orgtest.FakeOrgService{ExpectedOrg: &org.Org{ID: 1}},
+		}
+		return cfg, elements, folders
+	}
+	file := filepath.Join(allProperties, "librarypanels.yaml")
+
+	t.Run("should return the changes without applying them", func(t *testing.T) {
+		cfg, elements, folders := setup(allProperties)
+		report, err := Validate(context.Background(), cfg)
+		require.NoError(t, err)
+
+		require.Len(t, report.Problems, 1)
+		assert.Equal(t, "testdata/panels/missing-uid.json", report.Problems[0].File)
+		assert.ElementsMatch(t, []validation.Change{
+			{Action: validation.ActionCreate, Kind: "folder", OrgID: 1, Name: "Library panels", File: file},
+			{Action: validation.ActionCreate, Kind: "library panel", OrgID: 1, Name: "CPU usage", File: "testdata/panels/cpu.json"},
+			{Action: validation.ActionCreate, Kind: "library panel", OrgID: 1, Name: "Memory usage", File: "testdata/panels/nested/memory.json"},
+			{Action: validation.ActionCreate, Kind: "library panel", OrgID: 1, Name: "Logs", File: "testdata/general/logs.json"},
+		}, report.Changes)
+
+		assert.Empty(t, elements.elements)
+		assert.Empty(t, folders.folders)
+	})
+
+	t.Run("should only return changed library panels", func(t *testing.T) {
+		cfg, elements, folders := setup(allProperties)
+		require.NoError(t, Provision(context.Background(), ProvisionerConfig{
+			Path:                  allProperties,
+			LibraryElementService: elements,
+			FolderService:         folders,
+			OrgService:            cfg.OrgService,
+		}))
+		elements.elements["cpu-usage"].Model = json.RawMessage(`{"title": "Changed", "type": "timeseries", "description": ""}`)
+
+		report, err := Validate(context.Background(), cfg)
+		require.NoError(t, err)
+		assert.Equal(t, []validation.Change{
+			{Action: validation.ActionUpdate, Kind: "library panel", OrgID: 1, Name: "CPU usage", File: "testdata/panels/cpu.json", Fields: []string{"model"}},
+		}, report.Changes)
+	})
+
+	t.Run("should report library elements that aren't library panels", func(t *testing.T) {
+		cfg, elements, _ := setup(allProperties)
+		elements.elements["logs"] = &libraryelements.LibraryElementDTO{UID: "logs", Kind: int64(models.VariableElement), Model: json.RawMessage(`{}`)}
+		report, err := Validate(context.Background(), cfg)
+		require.NoError(t, err)
+
+		require.Len(t, report.Problems, 2)
+		assert.Equal(t, "testdata/general/logs.json", report.Problems[1].File)
+	})
+
+	t.Run("should report a missing folder without a title", func(t *testing.T) {
+		dir := t.TempDir()
+		config := "apiVersion: 1\nproviders:\n  - name: team\n    folderUid: missing\n    path: " + t.TempDir() + "\n"
+		require.NoError(t, os.WriteFile(filepath.Join(dir, "librarypanels.yaml"), []byte(config), 0600))
+
+		cfg, _, _ := setup(dir)
+		report, err := Validate(context.Background(), cfg)
+		require.NoError(t, err)
+
+		require.Len(t, report.Problems, 1)
+		assert.Equal(t, 3, report.Problems[0].Line)
+		assert.Empty(t, report.Changes)
+	})
+
+	t.Run("should report invalid files", func(t *testing.T) {
+		for _, path := range []string{duplicateProvider, missingPath} {
+			cfg, _, _ := setup(path)
+			report, err := Validate(context.Background(), cfg)
+			require.NoError(t, err)
+			assert.Len(t, report.Problems, 1, path)
+		}
+	})
+}
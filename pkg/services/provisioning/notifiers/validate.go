@@ -0,0 +1,172 @@
+package notifiers
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+	"time"
+
+	"github.com/grafana/grafana/pkg/infra/log"
+	"github.com/grafana/grafana/pkg/models"
+	"github.com/grafana/grafana/pkg/services/encryption"
+	"github.com/grafana/grafana/pkg/services/notifications"
+	"github.com/grafana/grafana/pkg/services/org"
+	"github.com/grafana/grafana/pkg/services/provisioning/validation"
+	"github.com/grafana/grafana/pkg/setting"
+)
+
+type NotificationReader interface {
+	GetAlertNotificationsWithUid(ctx context.Context, query *models.GetAlertNotificationsWithUidQuery) error
+}
+
+// Validate reads the alert notification provisioning files in the directory without applying them, and returns
+// the problems of the files and the alert notifications that provisioning would create, update or delete.
+func Validate(ctx context.Context, configDirectory string, alertingService NotificationReader, orgService org.Service, encryptionService encryption.Internal, notificationService *notifications.NotificationService) (*validation.Report, error) {
+	cr := &configReader{
+		encryptionService:   encryptionService,
+		notificationService: notificationService,
+		orgService:          orgService,
+		log:                 log.New("provisioning.notifiers"),
+	}
+	report := validation.NewReport("notifiers")
+
+	files, err := os.ReadDir(configDirectory)
+	if err != nil {
+		// The alert notifications aren't provisioned without the directory
+		return report, nil
+	}
+
+	for _, file := range files {
+		if !strings.HasSuffix(file.Name(), ".yaml") && !strings.HasSuffix(file.Name(), ".yml") {
+			continue
+		}
+		filename := filepath.Join(configDirectory, file.Name())
+
+		cfg, err := cr.parseNotificationConfig(configDirectory, file)
+		if err != nil {
+			report.AddProblem(filename, 0, err)
+			continue
+		}
+		if cfg == nil {
+			continue
+		}
+
+		cfgs := []*notificationsAsConfig{cfg}
+		if err := cr.validateRequiredField(cfgs); err != nil {
+			report.AddProblem(filename, 0, err)
+			continue
+		}
+		if err := cr.checkOrgIDAndOrgName(ctx, cfgs); err != nil {
+			report.AddProblem(filename, 0, err)
+			continue
+		}
+		if err := cr.validateNotifications(cfgs); err != nil {
+			report.AddProblem(filename, 0, err)
+			continue
+		}
+
+		// nolint:gosec
+		// We can ignore the gosec G304 warning on this one because `filename` was already read by the config reader
+		data, err := os.ReadFile(filename)
+		if err != nil {
+			return nil, err
+		}
+
+		deleted := map[int64]map[string]bool{}
+		for _, n := range cfg.DeleteNotifications {
+			line := validation.Line(data, "uid", n.UID)
+			orgID, err := notificationOrgID(ctx, orgService, n.OrgID, n.OrgName)
+			if err != nil {
+				report.AddProblem(filename, line, fmt.Errorf("failed to delete %q notification: %w", n.Name, err))
+				continue
+			}
+			existing, err := getNotification(ctx, alertingService, orgID, n.UID)
+			if err != nil {
+				return nil, err
+			}
+			if existing != nil {
+				report.AddChange(validation.ActionDelete, "alert notification", orgID, n.Name, filename)
+				if deleted[orgID] == nil {
+					deleted[orgID] = map[string]bool{}
+				}
+				deleted[orgID][n.UID] = true
+			}
+		}
+
+		for _, n := range cfg.Notifications {
+			line := validation.Line(data, "uid", n.UID)
+			orgID, err := notificationOrgID(ctx, orgService, n.OrgID, n.OrgName)
+			if err != nil {
+				report.AddProblem(filename, line, fmt.Errorf("failed to provision %q notification: %w", n.Name, err))
+				continue
+			}
+			existing, err := getNotification(ctx, alertingService, orgID, n.UID)
+			if err != nil {
+				return nil, err
+			}
+			if existing == nil || deleted[orgID][n.UID] {
+				report.AddChange(validation.ActionCreate, "alert notification", orgID, n.Name, filename)
+				continue
+			}
+
+			fields, err := changedFields(ctx, encryptionService, n, existing)
+			if err != nil {
+				return nil, err
+			}
+			report.AddUpdate("alert notification", orgID, n.Name, filename, fields)
+		}
+	}
+
+	return report, nil
+}
+
+// notificationOrgID returns the organization ID of a notification, that is looked up by name when the config
+// only has the name of the organization.
+func notificationOrgID(ctx context.Context, orgService org.Service, orgID int64, orgName string) (int64, error) {
+	if orgID != 0 || orgName == "" {
+		return orgID, nil
+	}
+	res, err := orgService.GetByName(ctx, &org.GetOrgByNameQuery{Name: orgName})
+	if err != nil {
+		return 0, err
+	}
+	return res.ID, nil
+}
+
+// getNotification returns the notification with the UID in the organization, or nil if there is none.
+func getNotification(ctx context.Context, alertingService NotificationReader, orgID int64, uid string) (*models.AlertNotification, error) {
+	query := &models.GetAlertNotificationsWithUidQuery{OrgId: orgID, Uid: uid}
+	if err := alertingService.GetAlertNotificationsWithUid(ctx, query); err != nil {
+		return nil, err
+	}
+	return query.Result, nil
+}
+
+// changedFields returns the fields of the existing notification that provisioning would change. The frequency is
+// only compared when the notification sends reminders.
+func changedFields(ctx context.Context, encryptionService encryption.Internal, n *notificationFromConfig, existing *models.AlertNotification) (validation.Fields, error) {
+	var fields validation.Fields
+	fields.Add("name", n.Name != existing.Name)
+	fields.Add("type", n.Type != existing.Type)
+	fields.Add("is_default", n.IsDefault != existing.IsDefault)
+	fields.Add("send_reminder", n.SendReminder != existing.SendReminder)
+	fields.Add("disable_resolve_message", n.DisableResolveMessage != existing.DisableResolveMessage)
+	if n.SendReminder {
+		frequency, err := time.ParseDuration(n.Frequency)
+		fields.Add("frequency", err != nil || frequency != existing.Frequency)
+	}
+	var settings interface{}
+	if existing.Settings != nil {
+		settings = existing.Settings.Interface()
+	}
+	fields.Compare("settings", n.Settings, settings)
+
+	secureSettings, err := encryptionService.DecryptJsonData(ctx, existing.SecureSettings, setting.SecretKey)
+	if err != nil {
+		return nil, err
+	}
+	fields.Compare("secure_settings", n.SecureSettings, secureSettings)
+	return fields, nil
+}
@@ -0,0 +1,96 @@
+package notifiers
+
+import (
+	"context"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/grafana/grafana/pkg/components/simplejson"
+	"github.com/grafana/grafana/pkg/infra/db"
+	"github.com/grafana/grafana/pkg/models"
+	"github.com/grafana/grafana/pkg/services/alerting"
+	"github.com/grafana/grafana/pkg/services/alerting/notifiers"
+	encryptionservice "github.com/grafana/grafana/pkg/services/encryption/service"
+	"github.com/grafana/grafana/pkg/services/notifications"
+	"github.com/grafana/grafana/pkg/services/org"
+	"github.com/grafana/grafana/pkg/services/org/orgimpl"
+	"github.com/grafana/grafana/pkg/services/provisioning/validation"
+	"github.com/grafana/grafana/pkg/services/quota/quotatest"
+)
+
+func TestValidate(t *testing.T) {
+	encryptionService := encryptionservice.SetupTestService(t)
+	alerting.RegisterNotifier(&alerting.NotifierPlugin{Type: "slack", Name: "slack", Factory: notifiers.NewSlackNotifier})
+	alerting.RegisterNotifier(&alerting.NotifierPlugin{Type: "email", Name: "email", Factory: notifiers.NewEmailNotifier})
+
+	sqlStore := db.InitTestDB(t)
+	orgService, err := orgimpl.ProvideService(sqlStore, sqlStore.Cfg, quotatest.New(false, nil))
+	require.NoError(t, err)
+	ns := alerting.ProvideService(sqlStore, encryptionService, &notifications.NotificationService{})
+	_, err = orgService.CreateWithMember(context.Background(), &org.CreateOrgCommand{Name: "Main Org. 1"})
+	require.NoError(t, err)
+
+	t.Run("should return the changes without applying them", func(t *testing.T) {
+		existing := models.CreateAlertNotificationCommand{Name: "channel1", OrgId: 1, Uid: "notifier1", Type: "slack"}
+		require.NoError(t, ns.SQLStore.CreateAlertNotificationCommand(context.Background(), &existing))
+
+		report, err := Validate(context.Background(), twoNotificationsConfig, ns, orgService, encryptionService, nil)
+		require.NoError(t, err)
+
+		file := filepath.Join(twoNotificationsConfig, "two-notifications.yaml")
+		assert.True(t, report.Valid())
+		assert.Equal(t, []validation.Change{
+			{Action: validation.ActionUpdate, Kind: "alert notification", OrgID: 1, Name: "channel1", File: file, Fields: []string{"type", "settings"}},
+			{Action: validation.ActionCreate, Kind: "alert notification", OrgID: 1, Name: "channel2", File: file},
+		}, report.Changes)
+
+		query := &models.GetAllAlertNotificationsQuery{OrgId: 1}
+		require.NoError(t, ns.GetAllAlertNotifications(context.Background(), query))
+		assert.Len(t, query.Result, 1)
+	})
+
+	t.Run("should not report notifications that don't change", func(t *testing.T) {
+		cmd := models.UpdateAlertNotificationWithUidCommand{
+			Uid: "notifier1", Name: "channel1", OrgId: 1, Type: "email",
+			Settings: simplejson.NewFromAny(map[string]interface{}{"addresses": "example@example.com"}),
+		}
+		require.NoError(t, ns.SQLStore.UpdateAlertNotificationWithUid(context.Background(), &cmd))
+
+		report, err := Validate(context.Background(), twoNotificationsConfig, ns, orgService, encryptionService, nil)
+		require.NoError(t, err)
+
+		assert.Equal(t, []validation.Change{
+			{Action: validation.ActionCreate, Kind: "alert notification", OrgID: 1, Name: "channel2", File: filepath.Join(twoNotificationsConfig, "two-notifications.yaml")},
+		}, report.Changes)
+	})
+
+	t.Run("should report missing organizations", func(t *testing.T) {
+		report, err := Validate(context.Background(), correctPropertiesWithOrgName, ns, orgService, encryptionService, nil)
+		require.NoError(t, err)
+
+		require.Len(t, report.Problems, 2)
+		assert.Equal(t, 4, report.Problems[0].Line)
+		assert.Empty(t, report.Changes)
+	})
+
+	t.Run("should report invalid files", func(t *testing.T) {
+		for _, path := range []string{brokenYaml, noRequiredFields, unknownNotifier, incorrectSettings} {
+			report, err := Validate(context.Background(), path, ns, orgService, encryptionService, nil)
+			require.NoError(t, err)
+			assert.Len(t, report.Problems, 1, path)
+			assert.Empty(t, report.Changes, path)
+		}
+	})
+
+	t.Run("should return an empty report without files", func(t *testing.T) {
+		for _, path := range []string{emptyFolder, emptyFile, "./testdata/test-configs/missing"} {
+			report, err := Validate(context.Background(), path, ns, orgService, encryptionService, nil)
+			require.NoError(t, err)
+			assert.True(t, report.Valid(), path)
+			assert.Empty(t, report.Changes, path)
+		}
+	})
+}
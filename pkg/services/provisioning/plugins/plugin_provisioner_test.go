@@ -82,6 +82,8 @@ func (tcr *testConfigReader) readConfig(_ context.Context, _ string) ([]*plugins
 
 type mockStore struct {
 	updateRequests []*pluginsettings.UpdateArgs
+	// existing are the settings of test-plugin in organization 2, that has version 2.0.1 when they're nil.
+	existing *pluginsettings.DTO
 }
 
 func (m *mockStore) GetOrgByNameHandler(_ context.Context, query *models.GetOrgByNameQuery) error {
@@ -93,6 +95,9 @@ func (m *mockStore) GetOrgByNameHandler(_ context.Context, query *models.GetOrgB
 
 func (m *mockStore) GetPluginSettingByPluginID(_ context.Context, args *pluginsettings.GetByPluginIDArgs) (*pluginsettings.DTO, error) {
 	if args.PluginID == "test-plugin" && args.OrgID == 2 {
+		if m.existing != nil {
+			return m.existing, nil
+		}
 		return &pluginsettings.DTO{
 			PluginVersion: "2.0.1",
 		}, nil
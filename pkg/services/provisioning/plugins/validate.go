@@ -0,0 +1,110 @@
+package plugins
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"github.com/grafana/grafana/pkg/infra/log"
+	"github.com/grafana/grafana/pkg/models"
+	"github.com/grafana/grafana/pkg/plugins"
+	"github.com/grafana/grafana/pkg/services/org"
+	"github.com/grafana/grafana/pkg/services/pluginsettings"
+	"github.com/grafana/grafana/pkg/services/provisioning/validation"
+)
+
+// Validate reads the provisioning config files in the directory without applying them, and returns the problems
+// of the files and the apps that provisioning would create or update.
+func Validate(ctx context.Context, configDirectory string, pluginStore plugins.Store, pluginSettings pluginsettings.Service, orgService org.Service) (*validation.Report, error) {
+	cr := &configReaderImpl{log: log.New("provisioning.plugins"), pluginStore: pluginStore}
+	report := validation.NewReport("plugins")
+
+	files, err := os.ReadDir(configDirectory)
+	if err != nil {
+		// The apps aren't provisioned without the directory
+		return report, nil
+	}
+
+	for _, file := range files {
+		if !strings.HasSuffix(file.Name(), ".yaml") && !strings.HasSuffix(file.Name(), ".yml") {
+			continue
+		}
+		filename := filepath.Join(configDirectory, file.Name())
+
+		cfg, err := cr.parsePluginConfig(configDirectory, file)
+		if err != nil {
+			report.AddProblem(filename, 0, err)
+			continue
+		}
+
+		// nolint:gosec
+		// We can ignore the gosec G304 warning on this one because `filename` was already read by the config reader
+		data, err := os.ReadFile(filename)
+		if err != nil {
+			return nil, err
+		}
+
+		checkOrgIDAndOrgName([]*pluginsAsConfig{cfg})
+		for index, app := range cfg.Apps {
+			if app.PluginID == "" {
+				report.AddProblem(filename, 0, fmt.Errorf("app item %d in configuration doesn't contain required field type", index+1))
+				continue
+			}
+			line := validation.Line(data, "type", app.PluginID)
+
+			if _, exists := cr.pluginStore.Plugin(ctx, app.PluginID); !exists {
+				report.AddProblem(filename, line, fmt.Errorf("plugin not installed: %q", app.PluginID))
+				continue
+			}
+
+			orgID := app.OrgID
+			if orgID == 0 && app.OrgName != "" {
+				res, err := orgService.GetByName(ctx, &org.GetOrgByNameQuery{Name: app.OrgName})
+				if err != nil {
+					report.AddProblem(filename, line, fmt.Errorf("failed to provision %q app: %w", app.PluginID, err))
+					continue
+				}
+				orgID = res.ID
+			}
+
+			existing, err := pluginSettings.GetPluginSettingByPluginID(ctx, &pluginsettings.GetByPluginIDArgs{
+				OrgID:    orgID,
+				PluginID: app.PluginID,
+			})
+			switch {
+			case errors.Is(err, models.ErrPluginSettingNotFound):
+				report.AddChange(validation.ActionCreate, "app", orgID, app.PluginID, filename)
+			case err != nil:
+				return nil, err
+			default:
+				report.AddUpdate("app", orgID, app.PluginID, filename, changedFields(pluginSettings, app, existing))
+			}
+		}
+	}
+
+	return report, nil
+}
+
+// changedFields returns the fields of the existing plugin settings that provisioning would change. The secrets that
+// the config doesn't set are kept.
+func changedFields(pluginSettings pluginsettings.Service, app *appFromConfig, existing *pluginsettings.DTO) validation.Fields {
+	var fields validation.Fields
+	fields.Add("disabled", app.Enabled != existing.Enabled)
+	fields.Add("pinned", app.Pinned != existing.Pinned)
+	fields.Compare("jsonData", app.JSONData, existing.JSONData)
+
+	if len(app.SecureJSONData) > 0 {
+		secrets := pluginSettings.DecryptedValues(existing)
+		changed := false
+		for k, v := range app.SecureJSONData {
+			if secret, ok := secrets[k]; !ok || secret != v {
+				changed = true
+			}
+		}
+		fields.Add("secureJsonData", changed)
+	}
+	return fields
+}
@@ -0,0 +1,78 @@
+package plugins
+
+import (
+	"context"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/grafana/grafana/pkg/plugins"
+	"github.com/grafana/grafana/pkg/services/org"
+	"github.com/grafana/grafana/pkg/services/org/orgtest"
+	"github.com/grafana/grafana/pkg/services/pluginsettings"
+	"github.com/grafana/grafana/pkg/services/provisioning/validation"
+)
+
+func TestValidate(t *testing.T) {
+	pm := plugins.FakePluginStore{
+		PluginList: []plugins.PluginDTO{
+			{JSONData: plugins.JSONData{ID: "test-plugin"}},
+			{JSONData: plugins.JSONData{ID: "test-plugin-2"}},
+		},
+	}
+	orgFake := &orgtest.FakeOrgService{ExpectedOrg: &org.Org{ID: 3}}
+
+	t.Run("should return the changes without applying them", func(t *testing.T) {
+		t.Setenv("ENABLE_PLUGIN_VAR", "test-plugin")
+		store := &mockStore{}
+		report, err := Validate(context.Background(), correctProperties, pm, store, orgFake)
+		require.NoError(t, err)
+
+		file := filepath.Join(correctProperties, "correct-properties.yaml")
+		assert.True(t, report.Valid())
+		assert.Equal(t, []validation.Change{
+			{Action: validation.ActionUpdate, Kind: "app", OrgID: 2, Name: "test-plugin", File: file, Fields: []string{"disabled", "pinned"}},
+			{Action: validation.ActionCreate, Kind: "app", OrgID: 3, Name: "test-plugin-2", File: file},
+			{Action: validation.ActionCreate, Kind: "app", OrgID: 3, Name: "test-plugin", File: file},
+			{Action: validation.ActionCreate, Kind: "app", OrgID: 1, Name: "test-plugin-2", File: file},
+		}, report.Changes)
+		assert.Empty(t, store.updateRequests)
+	})
+
+	t.Run("should not report apps that don't change", func(t *testing.T) {
+		t.Setenv("ENABLE_PLUGIN_VAR", "test-plugin")
+		store := &mockStore{existing: &pluginsettings.DTO{PluginVersion: "2.0.1", Enabled: true, Pinned: true}}
+		report, err := Validate(context.Background(), correctProperties, pm, store, orgFake)
+		require.NoError(t, err)
+
+		require.Len(t, report.Changes, 3)
+		for _, change := range report.Changes {
+			assert.Equal(t, validation.ActionCreate, change.Action)
+		}
+	})
+
+	t.Run("should report plugins that aren't installed with their line", func(t *testing.T) {
+		report, err := Validate(context.Background(), unknownApp, pm, &mockStore{}, orgFake)
+		require.NoError(t, err)
+
+		require.Len(t, report.Problems, 1)
+		assert.Equal(t, 2, report.Problems[0].Line)
+		assert.Equal(t, `plugin not installed: "nonexisting"`, report.Problems[0].Message)
+	})
+
+	t.Run("should report apps without type", func(t *testing.T) {
+		report, err := Validate(context.Background(), incorrectSettings, pm, &mockStore{}, orgFake)
+		require.NoError(t, err)
+
+		require.Len(t, report.Problems, 1)
+		assert.Equal(t, "app item 1 in configuration doesn't contain required field type", report.Problems[0].Message)
+	})
+
+	t.Run("should report broken yaml", func(t *testing.T) {
+		report, err := Validate(context.Background(), brokenYaml, pm, &mockStore{}, orgFake)
+		require.NoError(t, err)
+		assert.Len(t, report.Problems, 1)
+	})
+}
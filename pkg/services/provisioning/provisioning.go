@@ -16,20 +16,29 @@ import (
 	dashboardservice "github.com/grafana/grafana/pkg/services/dashboards"
 	datasourceservice "github.com/grafana/grafana/pkg/services/datasources"
 	"github.com/grafana/grafana/pkg/services/encryption"
+	"github.com/grafana/grafana/pkg/services/featuremgmt"
 	"github.com/grafana/grafana/pkg/services/folder"
+	"github.com/grafana/grafana/pkg/services/libraryelements"
 	"github.com/grafana/grafana/pkg/services/ngalert/provisioning"
 	"github.com/grafana/grafana/pkg/services/ngalert/store"
 	"github.com/grafana/grafana/pkg/services/notifications"
 	"github.com/grafana/grafana/pkg/services/org"
 	"github.com/grafana/grafana/pkg/services/pluginsettings"
+	"github.com/grafana/grafana/pkg/services/provisioning/access"
 	prov_alerting "github.com/grafana/grafana/pkg/services/provisioning/alerting"
 	"github.com/grafana/grafana/pkg/services/provisioning/dashboards"
 	"github.com/grafana/grafana/pkg/services/provisioning/datasources"
+	"github.com/grafana/grafana/pkg/services/provisioning/librarypanels"
 	"github.com/grafana/grafana/pkg/services/provisioning/notifiers"
 	"github.com/grafana/grafana/pkg/services/provisioning/plugins"
+	prov_serviceaccounts "github.com/grafana/grafana/pkg/services/provisioning/serviceaccounts"
+	"github.com/grafana/grafana/pkg/services/provisioning/validation"
 	"github.com/grafana/grafana/pkg/services/quota"
 	"github.com/grafana/grafana/pkg/services/searchV2"
 	"github.com/grafana/grafana/pkg/services/secrets"
+	"github.com/grafana/grafana/pkg/services/serviceaccounts"
+	"github.com/grafana/grafana/pkg/services/team"
+	"github.com/grafana/grafana/pkg/services/user"
 	"github.com/grafana/grafana/pkg/setting"
 )
 
@@ -51,6 +60,14 @@ func ProvideService(
 	quotaService quota.Service,
 	secrectService secrets.Service,
 	orgService org.Service,
+	teamService team.Service,
+	userService user.Service,
+	teamPermissionsService accesscontrol.TeamPermissionsService,
+	folderPermissionsService accesscontrol.FolderPermissionsService,
+	dashboardPermissionsService accesscontrol.DashboardPermissionsService,
+	serviceAccountsService serviceaccounts.Service,
+	serviceAccountsStore serviceaccounts.Store,
+	libraryElementService libraryelements.Service,
 ) (*ProvisioningServiceImpl, error) {
 	s := &ProvisioningServiceImpl{
 		Cfg:                          cfg,
@@ -64,8 +81,12 @@ func ProvideService(
 		provisionDatasources:         datasources.Provision,
 		provisionPlugins:             plugins.Provision,
 		provisionAlerting:            prov_alerting.Provision,
+		provisionAccess:              access.Provision,
+		provisionServiceAccounts:     prov_serviceaccounts.Provision,
+		provisionLibraryPanels:       librarypanels.Provision,
 		dashboardProvisioningService: dashboardProvisioningService,
 		dashboardService:             dashboardService,
+		folderService:                folderService,
 		datasourceService:            datasourceService,
 		correlationsService:          correlationsService,
 		alertingService:              alertingService,
@@ -75,6 +96,14 @@ func ProvideService(
 		secretService:                secrectService,
 		log:                          log.New("provisioning"),
 		orgService:                   orgService,
+		teamService:                  teamService,
+		userService:                  userService,
+		teamPermissionsService:       teamPermissionsService,
+		folderPermissionsService:     folderPermissionsService,
+		dashboardPermissionsService:  dashboardPermissionsService,
+		serviceAccountsService:       serviceAccountsService,
+		serviceAccountsStore:         serviceAccountsStore,
+		libraryElementService:        libraryElementService,
 	}
 	return s, nil
 }
@@ -87,6 +116,10 @@ type ProvisioningService interface {
 	ProvisionNotifications(ctx context.Context) error
 	ProvisionDashboards(ctx context.Context) error
 	ProvisionAlerting(ctx context.Context) error
+	ProvisionAccess(ctx context.Context) error
+	ProvisionServiceAccounts(ctx context.Context) error
+	ProvisionLibraryPanels(ctx context.Context) error
+	ValidateProvisioning(ctx context.Context) ([]*validation.Report, error)
 	GetDashboardProvisionerResolvedPath(name string) string
 	GetAllowUIUpdatesFromConfig(name string) bool
 }
@@ -135,9 +168,13 @@ type ProvisioningServiceImpl struct {
 	provisionDatasources         func(context.Context, string, datasources.Store, datasources.CorrelationsStore, org.Service) error
 	provisionPlugins             func(context.Context, string, plugifaces.Store, pluginsettings.Service, org.Service) error
 	provisionAlerting            func(context.Context, prov_alerting.ProvisionerConfig) error
+	provisionAccess              func(context.Context, access.ProvisionerConfig) error
+	provisionServiceAccounts     func(context.Context, prov_serviceaccounts.ProvisionerConfig) error
+	provisionLibraryPanels       func(context.Context, librarypanels.ProvisionerConfig) error
 	mutex                        sync.Mutex
 	dashboardProvisioningService dashboardservice.DashboardProvisioningService
 	dashboardService             dashboardservice.DashboardService
+	folderService                folder.Service
 	datasourceService            datasourceservice.DataSourceService
 	correlationsService          correlations.Service
 	alertingService              *alerting.AlertNotificationService
@@ -145,6 +182,14 @@ type ProvisioningServiceImpl struct {
 	searchService                searchV2.SearchService
 	quotaService                 quota.Service
 	secretService                secrets.Service
+	teamService                  team.Service
+	userService                  user.Service
+	teamPermissionsService       accesscontrol.TeamPermissionsService
+	folderPermissionsService     accesscontrol.FolderPermissionsService
+	dashboardPermissionsService  accesscontrol.DashboardPermissionsService
+	serviceAccountsService       serviceaccounts.Service
+	serviceAccountsStore         serviceaccounts.Store
+	libraryElementService        libraryelements.Service
 }
 
 func (ps *ProvisioningServiceImpl) RunInitProvisioners(ctx context.Context) error {
@@ -168,6 +213,22 @@ func (ps *ProvisioningServiceImpl) RunInitProvisioners(ctx context.Context) erro
 		return err
 	}
 
+	err = ps.ProvisionAccess(ctx)
+	if err != nil {
+		return err
+	}
+
+	err = ps.ProvisionServiceAccounts(ctx)
+	if err != nil {
+		return err
+	}
+
+	// Library panels are provisioned before the dashboards that use them
+	err = ps.ProvisionLibraryPanels(ctx)
+	if err != nil {
+		return err
+	}
+
 	return nil
 }
 
@@ -179,6 +240,10 @@ func (ps *ProvisioningServiceImpl) Run(ctx context.Context) error {
 	}
 	if ps.dashboardProvisioner.HasDashboardSources() {
 		ps.searchService.TriggerReIndex()
+
+		// Provision access again, as the permissions of dashboards that didn't exist before dashboard
+		// provisioning were skipped. Errors are logged by ProvisionAccess.
+		_ = ps.ProvisionAccess(ctx)
 	}
 
 	for {
@@ -235,7 +300,7 @@ func (ps *ProvisioningServiceImpl) ProvisionNotifications(ctx context.Context) e
 
 func (ps *ProvisioningServiceImpl) ProvisionDashboards(ctx context.Context) error {
 	dashboardPath := filepath.Join(ps.Cfg.ProvisioningPath, "dashboards")
-	dashProvisioner, err := ps.newDashboardProvisioner(ctx, dashboardPath, ps.dashboardProvisioningService, ps.orgService, ps.dashboardService)
+	dashProvisioner, err := ps.newDashboardProvisioner(ctx, dashboardPath, ps.Cfg.DataPath, ps.dashboardProvisioningService, ps.orgService, ps.dashboardService)
 	if err != nil {
 		return fmt.Errorf("%v: %w", "Failed to create provisioner", err)
 	}
@@ -273,6 +338,7 @@ func (ps *ProvisioningServiceImpl) ProvisionAlerting(ctx context.Context) error
 		ps.SQLStore,
 		int64(ps.Cfg.UnifiedAlerting.DefaultRuleEvaluationInterval.Seconds()),
 		int64(ps.Cfg.UnifiedAlerting.BaseInterval.Seconds()),
+		ps.Cfg.UnifiedAlerting.RecordingRules.Enabled,
 		ps.log)
 	contactPointService := provisioning.NewContactPointService(&st, ps.secretService,
 		st, ps.SQLStore, ps.log)
@@ -293,6 +359,59 @@ func (ps *ProvisioningServiceImpl) ProvisionAlerting(ctx context.Context) error
 	return ps.provisionAlerting(ctx, cfg)
 }
 
+func (ps *ProvisioningServiceImpl) ProvisionAccess(ctx context.Context) error {
+	accessPath := filepath.Join(ps.Cfg.ProvisioningPath, "access")
+	cfg := access.ProvisionerConfig{
+		Path:                        accessPath,
+		FolderService:               ps.folderService,
+		TeamService:                 ps.teamService,
+		UserService:                 ps.userService,
+		TeamPermissionsService:      ps.teamPermissionsService,
+		FolderPermissionsService:    ps.folderPermissionsService,
+		DashboardPermissionsService: ps.dashboardPermissionsService,
+		OrgService:                  ps.orgService,
+		NestedFolders:               ps.Cfg.IsFeatureToggleEnabled(featuremgmt.FlagNestedFolders),
+	}
+	if err := ps.provisionAccess(ctx, cfg); err != nil {
+		err = fmt.Errorf("%v: %w", "Access provisioning error", err)
+		ps.log.Error("Failed to provision access", "error", err)
+		return err
+	}
+	return nil
+}
+
+func (ps *ProvisioningServiceImpl) ProvisionServiceAccounts(ctx context.Context) error {
+	serviceAccountsPath := filepath.Join(ps.Cfg.ProvisioningPath, "serviceaccounts")
+	cfg := prov_serviceaccounts.ProvisionerConfig{
+		Path:                  serviceAccountsPath,
+		ServiceAccountService: ps.serviceAccountsService,
+		ServiceAccountStore:   ps.serviceAccountsStore,
+		OrgService:            ps.orgService,
+	}
+	if err := ps.provisionServiceAccounts(ctx, cfg); err != nil {
+		err = fmt.Errorf("%v: %w", "Service accounts provisioning error", err)
+		ps.log.Error("Failed to provision service accounts", "error", err)
+		return err
+	}
+	return nil
+}
+
+func (ps *ProvisioningServiceImpl) ProvisionLibraryPanels(ctx context.Context) error {
+	libraryPanelsPath := filepath.Join(ps.Cfg.ProvisioningPath, "librarypanels")
+	cfg := librarypanels.ProvisionerConfig{
+		Path:                  libraryPanelsPath,
+		LibraryElementService: ps.libraryElementService,
+		FolderService:         ps.folderService,
+		OrgService:            ps.orgService,
+	}
+	if err := ps.provisionLibraryPanels(ctx, cfg); err != nil {
+		err = fmt.Errorf("%v: %w", "Library panels provisioning error", err)
+		ps.log.Error("Failed to provision library panels", "error", err)
+		return err
+	}
+	return nil
+}
+
 func (ps *ProvisioningServiceImpl) GetDashboardProvisionerResolvedPath(name string) string {
 	return ps.dashboardProvisioner.GetProvisionerResolvedPath(name)
 }
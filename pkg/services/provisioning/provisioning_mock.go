@@ -1,6 +1,10 @@
 package provisioning
 
-import "context"
+import (
+	"context"
+
+	"github.com/grafana/grafana/pkg/services/provisioning/validation"
+)
 
 type Calls struct {
 	RunInitProvisioners                 []interface{}
@@ -9,6 +13,10 @@ type Calls struct {
 	ProvisionNotifications              []interface{}
 	ProvisionDashboards                 []interface{}
 	ProvisionAlerting                   []interface{}
+	ProvisionAccess                     []interface{}
+	ProvisionServiceAccounts            []interface{}
+	ProvisionLibraryPanels              []interface{}
+	ValidateProvisioning                []interface{}
 	GetDashboardProvisionerResolvedPath []interface{}
 	GetAllowUIUpdatesFromConfig         []interface{}
 	Run                                 []interface{}
@@ -21,6 +29,10 @@ type ProvisioningServiceMock struct {
 	ProvisionPluginsFunc                    func() error
 	ProvisionNotificationsFunc              func() error
 	ProvisionDashboardsFunc                 func() error
+	ProvisionAccessFunc                     func() error
+	ProvisionServiceAccountsFunc            func() error
+	ProvisionLibraryPanelsFunc              func() error
+	ValidateProvisioningFunc                func() ([]*validation.Report, error)
 	GetDashboardProvisionerResolvedPathFunc func(name string) string
 	GetAllowUIUpdatesFromConfigFunc         func(name string) bool
 	RunFunc                                 func(ctx context.Context) error
@@ -77,6 +89,38 @@ func (mock *ProvisioningServiceMock) ProvisionAlerting(ctx context.Context) erro
 	return nil
 }
 
+func (mock *ProvisioningServiceMock) ProvisionAccess(ctx context.Context) error {
+	mock.Calls.ProvisionAccess = append(mock.Calls.ProvisionAccess, nil)
+	if mock.ProvisionAccessFunc != nil {
+		return mock.ProvisionAccessFunc()
+	}
+	return nil
+}
+
+func (mock *ProvisioningServiceMock) ProvisionServiceAccounts(ctx context.Context) error {
+	mock.Calls.ProvisionServiceAccounts = append(mock.Calls.ProvisionServiceAccounts, nil)
+	if mock.ProvisionServiceAccountsFunc != nil {
+		return mock.ProvisionServiceAccountsFunc()
+	}
+	return nil
+}
+
+func (mock *ProvisioningServiceMock) ProvisionLibraryPanels(ctx context.Context) error {
+	mock.Calls.ProvisionLibraryPanels = append(mock.Calls.ProvisionLibraryPanels, nil)
+	if mock.ProvisionLibraryPanelsFunc != nil {
+		return mock.ProvisionLibraryPanelsFunc()
+	}
+	return nil
+}
+
+func (mock *ProvisioningServiceMock) ValidateProvisioning(ctx context.Context) ([]*validation.Report, error) {
+	mock.Calls.ValidateProvisioning = append(mock.Calls.ValidateProvisioning, nil)
+	if mock.ValidateProvisioningFunc != nil {
+		return mock.ValidateProvisioningFunc()
+	}
+	return nil, nil
+}
+
 func (mock *ProvisioningServiceMock) GetDashboardProvisionerResolvedPath(name string) string {
 	mock.Calls.GetDashboardProvisionerResolvedPath = append(mock.Calls.GetDashboardProvisionerResolvedPath, name)
 	if mock.GetDashboardProvisionerResolvedPathFunc != nil {
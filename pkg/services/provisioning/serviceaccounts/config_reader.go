@@ -0,0 +1,152 @@
+package serviceaccounts
+
+import (
+	"context"
+	"fmt"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"gopkg.in/yaml.v3"
+
+	apikeygenprefix "github.com/grafana/grafana/pkg/components/apikeygenprefixed"
+	"github.com/grafana/grafana/pkg/infra/log"
+	"github.com/grafana/grafana/pkg/services/org"
+	"github.com/grafana/grafana/pkg/services/provisioning/utils"
+	saAPI "github.com/grafana/grafana/pkg/services/serviceaccounts/api"
+)
+
+type configReader struct {
+	log        log.Logger
+	orgService org.Service
+}
+
+func (cr *configReader) readConfig(ctx context.Context, path string) ([]*configs, error) {
+	var saConfigs []*configs
+
+	files, err := os.ReadDir(path)
+	if err != nil {
+		cr.log.Error("can't read service account provisioning files from directory", "path", path, "error", err)
+		return saConfigs, nil
+	}
+
+	for _, file := range files {
+		if strings.HasSuffix(file.Name(), ".yaml") || strings.HasSuffix(file.Name(), ".yml") {
+			cfg, err := cr.parseServiceAccountConfig(path, file)
+			if err != nil {
+				return nil, err
+			}
+
+			if cfg != nil {
+				saConfigs = append(saConfigs, cfg)
+			}
+		}
+	}
+
+	if err := cr.validate(ctx, saConfigs); err != nil {
+		return nil, err
+	}
+
+	return saConfigs, nil
+}
+
+func (cr *configReader) parseServiceAccountConfig(path string, file fs.DirEntry) (*configs, error) {
+	filename, _ := filepath.Abs(filepath.Join(path, file.Name()))
+
+	// nolint:gosec
+	// We can ignore the gosec G304 warning on this one because `filename` comes from ps.Cfg.ProvisioningPath
+	yamlFile, err := os.ReadFile(filename)
+	if err != nil {
+		return nil, err
+	}
+
+	var apiVersion *configVersion
+	err = yaml.Unmarshal(yamlFile, &apiVersion)
+	if err != nil {
+		return nil, err
+	}
+
+	if apiVersion == nil {
+		// The file is empty or only contains comments
+		return nil, nil
+	}
+
+	if apiVersion.APIVersion != 1 {
+		return nil, fmt.Errorf("%s: unsupported apiVersion %d", file.Name(), apiVersion.APIVersion)
+	}
+
+	v1 := &configsV1{}
+	err = yaml.Unmarshal(yamlFile, v1)
+	if err != nil {
+		return nil, err
+	}
+
+	return v1.mapToServiceAccountsFromConfig(apiVersion.APIVersion), nil
+}
+
+// validate checks the required fields of the configs and applies the default organization and role, and that
+// service accounts are only declared once per organization and tokens only once.
+func (cr *configReader) validate(ctx context.Context, saConfigs []*configs) error {
+	names := map[int64]map[string]bool{}
+	keys := map[string]bool{}
+
+	for _, cfg := range saConfigs {
+		for _, sa := range cfg.ServiceAccounts {
+			if sa == nil {
+				continue
+			}
+			if sa.OrgID == 0 {
+				sa.OrgID = 1
+			}
+			if err := utils.CheckOrgExists(ctx, cr.orgService, sa.OrgID); err != nil {
+				return fmt.Errorf("failed to provision %q service account: %w", sa.Name, err)
+			}
+			if sa.Name == "" {
+				return fmt.Errorf("service account in configuration doesn't contain required field name")
+			}
+			if sa.Role == "" {
+				sa.Role = string(org.RoleViewer)
+			}
+			if !org.RoleType(sa.Role).IsValid() {
+				return fmt.Errorf("service account %q in configuration has an invalid role %q", sa.Name, sa.Role)
+			}
+			if names[sa.OrgID] == nil {
+				names[sa.OrgID] = map[string]bool{}
+			}
+			if names[sa.OrgID][sa.Name] {
+				return fmt.Errorf("service account %q is provisioned more than once in organization %d", sa.Name, sa.OrgID)
+			}
+			names[sa.OrgID][sa.Name] = true
+
+			tokenNames := map[string]bool{}
+			for _, t := range sa.Tokens {
+				if t.Name == "" || t.Key == "" {
+					return fmt.Errorf("token of service account %q in configuration doesn't contain required fields name and key", sa.Name)
+				}
+				if tokenNames[t.Name] {
+					return fmt.Errorf("token %q of service account %q is provisioned more than once", t.Name, sa.Name)
+				}
+				tokenNames[t.Name] = true
+
+				// The key isn't part of the errors, as it is a secret
+				key, err := apikeygenprefix.Decode(t.Key)
+				if err != nil || key.ServiceID != saAPI.ServiceID {
+					return fmt.Errorf("token %q of service account %q in configuration has an invalid key, expected a service account token", t.Name, sa.Name)
+				}
+				if keys[t.Key] {
+					return fmt.Errorf("token %q of service account %q in configuration has the key of another token", t.Name, sa.Name)
+				}
+				keys[t.Key] = true
+			}
+		}
+
+		for _, sa := range cfg.DeleteServiceAccounts {
+			if sa.OrgID == 0 {
+				sa.OrgID = 1
+			}
+		}
+	}
+
+	return nil
+}
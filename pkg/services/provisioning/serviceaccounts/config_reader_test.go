@@ -0,0 +1,81 @@
+package serviceaccounts
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/grafana/grafana/pkg/infra/log"
+	"github.com/grafana/grafana/pkg/services/org"
+	"github.com/grafana/grafana/pkg/services/org/orgtest"
+)
+
+const (
+	allProperties           = "testdata/all-properties"
+	invalidKey              = "testdata/invalid-key"
+	duplicateServiceAccount = "testdata/duplicate-service-account"
+	deleteServiceAccounts   = "testdata/delete"
+
+	deployToken     = "glsa_GH5eTWZsmJbJ8MgO3Ih8hGBTbrC3uNT8_2ae4254b"
+	dashboardsToken = "glsa_yY4dAxm0FM1e3x7sIBeSKVvsVCpyG4Aw_b865e459"
+	rotatedToken    = "glsa_D4kk9j0lTRs0pHhSKWMSt5Ns4j9ZFNhS_b67a4923"
+)
+
+func TestConfigReader(t *testing.T) {
+	newReader := func() *configReader {
+		return &configReader{log: log.New("test logger"), orgService: &orgtest.FakeOrgService{ExpectedOrg: &org.Org{ID: 1}}}
+	}
+
+	t.Run("Can read all properties", func(t *testing.T) {
+		t.Setenv("SA_DEPLOY_TOKEN", deployToken)
+
+		cfgs, err := newReader().readConfig(context.Background(), allProperties)
+		require.NoError(t, err)
+		require.Len(t, cfgs, 1)
+
+		require.Equal(t, []*serviceAccountFromConfig{
+			{OrgID: 1, Name: "ci", Role: "Editor", Tokens: []*tokenFromConfig{
+				{Name: "deploy", Key: deployToken},
+				{Name: "dashboards", Key: dashboardsToken},
+			}},
+			{OrgID: 1, Name: "monitoring", Role: "Viewer", IsDisabled: true},
+		}, cfgs[0].ServiceAccounts)
+	})
+
+	t.Run("Can read deletions", func(t *testing.T) {
+		cfgs, err := newReader().readConfig(context.Background(), deleteServiceAccounts)
+		require.NoError(t, err)
+		require.Len(t, cfgs, 1)
+		require.Equal(t, []*deleteServiceAccountConfig{{OrgID: 1, Name: "ci"}, {OrgID: 1, Name: "missing"}}, cfgs[0].DeleteServiceAccounts)
+	})
+
+	t.Run("Token with an invalid key should return error", func(t *testing.T) {
+		_, err := newReader().readConfig(context.Background(), invalidKey)
+		require.EqualError(t, err, `token "deploy" of service account "ci" in configuration has an invalid key, expected a service account token`)
+	})
+
+	t.Run("Token with a missing key should return error", func(t *testing.T) {
+		t.Setenv("SA_DEPLOY_TOKEN", "")
+
+		_, err := newReader().readConfig(context.Background(), allProperties)
+		require.EqualError(t, err, `token of service account "ci" in configuration doesn't contain required fields name and key`)
+	})
+
+	t.Run("Service account provisioned twice should return error", func(t *testing.T) {
+		_, err := newReader().readConfig(context.Background(), duplicateServiceAccount)
+		require.EqualError(t, err, `service account "ci" is provisioned more than once in organization 1`)
+	})
+
+	t.Run("Missing organization should return error", func(t *testing.T) {
+		reader := &configReader{log: log.New("test logger"), orgService: &orgtest.FakeOrgService{ExpectedError: org.ErrOrgNotFound}}
+		_, err := reader.readConfig(context.Background(), duplicateServiceAccount)
+		require.Error(t, err)
+	})
+
+	t.Run("Missing directory should not return error", func(t *testing.T) {
+		cfgs, err := newReader().readConfig(context.Background(), "testdata/missing")
+		require.NoError(t, err)
+		require.Empty(t, cfgs)
+	})
+}
@@ -0,0 +1,193 @@
+package serviceaccounts
+
+import (
+	"context"
+	"errors"
+	"fmt"
+
+	apikeygenprefix "github.com/grafana/grafana/pkg/components/apikeygenprefixed"
+	"github.com/grafana/grafana/pkg/infra/log"
+	"github.com/grafana/grafana/pkg/services/apikey"
+	"github.com/grafana/grafana/pkg/services/org"
+	"github.com/grafana/grafana/pkg/services/serviceaccounts"
+)
+
+type ServiceAccountStore interface {
+	RetrieveServiceAccount(ctx context.Context, orgID, serviceAccountID int64) (*serviceaccounts.ServiceAccountProfileDTO, error)
+	UpdateServiceAccount(ctx context.Context, orgID, serviceAccountID int64, saForm *serviceaccounts.UpdateServiceAccountForm) (*serviceaccounts.ServiceAccountProfileDTO, error)
+	ListTokens(ctx context.Context, query *serviceaccounts.GetSATokensQuery) ([]apikey.APIKey, error)
+	AddServiceAccountToken(ctx context.Context, serviceAccountID int64, cmd *serviceaccounts.AddServiceAccountTokenCommand) error
+	DeleteServiceAccountToken(ctx context.Context, orgID, serviceAccountID, tokenID int64) error
+}
+
+type ProvisionerConfig struct {
+	Path                  string
+	ServiceAccountService serviceaccounts.Service
+	ServiceAccountStore   ServiceAccountStore
+	OrgService            org.Service
+}
+
+// Provision scans a directory for provisioning config files and provisions the service accounts and tokens in
+// those files.
+func Provision(ctx context.Context, cfg ProvisionerConfig) error {
+	logger := log.New("provisioning.serviceaccounts")
+	sap := ServiceAccountProvisioner{
+		log:         logger,
+		cfgProvider: &configReader{log: logger, orgService: cfg.OrgService},
+		cfg:         cfg,
+	}
+	return sap.applyChanges(ctx, cfg.Path)
+}
+
+// ServiceAccountProvisioner is responsible for provisioning service accounts and their tokens based on
+// configuration read by the `configReader`
+type ServiceAccountProvisioner struct {
+	log         log.Logger
+	cfgProvider *configReader
+	cfg         ProvisionerConfig
+}
+
+func (sap *ServiceAccountProvisioner) applyChanges(ctx context.Context, configPath string) error {
+	configs, err := sap.cfgProvider.readConfig(ctx, configPath)
+	if err != nil {
+		return err
+	}
+
+	for _, cfg := range configs {
+		for _, sa := range cfg.DeleteServiceAccounts {
+			if err := sap.deleteServiceAccount(ctx, sa); err != nil {
+				return fmt.Errorf("failed to delete %q service account: %w", sa.Name, err)
+			}
+		}
+	}
+
+	var provisioned []int64
+	for _, cfg := range configs {
+		for _, sa := range cfg.ServiceAccounts {
+			id, err := sap.applyServiceAccount(ctx, sa)
+			if err != nil {
+				return fmt.Errorf("failed to provision %q service account: %w", sa.Name, err)
+			}
+			provisioned = append(provisioned, id)
+		}
+	}
+
+	// Service accounts that were removed from the configs can be modified through the API again
+	sap.cfg.ServiceAccountService.SetProvisionedServiceAccounts(provisioned)
+	return nil
+}
+
+func (sap *ServiceAccountProvisioner) deleteServiceAccount(ctx context.Context, sa *deleteServiceAccountConfig) error {
+	id, err := sap.cfg.ServiceAccountService.RetrieveServiceAccountIdByName(ctx, sa.OrgID, sa.Name)
+	if err != nil {
+		if errors.Is(err, serviceaccounts.ErrServiceAccountNotFound) {
+			return nil
+		}
+		return err
+	}
+
+	sap.log.Debug("deleting service account from configuration", "name", sa.Name, "orgId", sa.OrgID)
+	return sap.cfg.ServiceAccountService.DeleteServiceAccount(ctx, sa.OrgID, id)
+}
+
+// applyServiceAccount creates or updates the service account, reconciles its tokens and returns its ID.
+func (sap *ServiceAccountProvisioner) applyServiceAccount(ctx context.Context, sa *serviceAccountFromConfig) (int64, error) {
+	role := org.RoleType(sa.Role)
+	isDisabled := sa.IsDisabled
+
+	id, err := sap.cfg.ServiceAccountService.RetrieveServiceAccountIdByName(ctx, sa.OrgID, sa.Name)
+	switch {
+	case errors.Is(err, serviceaccounts.ErrServiceAccountNotFound):
+		sap.log.Debug("creating service account from configuration", "name", sa.Name, "orgId", sa.OrgID)
+		created, err := sap.cfg.ServiceAccountService.CreateServiceAccount(ctx, sa.OrgID, &serviceaccounts.CreateServiceAccountForm{
+			Name:       sa.Name,
+			Role:       &role,
+			IsDisabled: &isDisabled,
+		})
+		if err != nil {
+			return 0, err
+		}
+		id = created.Id
+	case err != nil:
+		return 0, err
+	default:
+		existing, err := sap.cfg.ServiceAccountStore.RetrieveServiceAccount(ctx, sa.OrgID, id)
+		if err != nil {
+			return 0, err
+		}
+		if existing.Role != sa.Role || existing.IsDisabled != sa.IsDisabled {
+			sap.log.Debug("updating service account from configuration", "name", sa.Name, "orgId", sa.OrgID)
+			if _, err := sap.cfg.ServiceAccountStore.UpdateServiceAccount(ctx, sa.OrgID, id, &serviceaccounts.UpdateServiceAccountForm{
+				Role:       &role,
+				IsDisabled: &isDisabled,
+			}); err != nil {
+				return 0, err
+			}
+		}
+	}
+
+	if err := sap.applyTokens(ctx, sa, id); err != nil {
+		return 0, err
+	}
+	return id, nil
+}
+
+// applyTokens adds the tokens of the service account that don't exist or whose key changed, and deletes the
+// tokens that aren't in the configuration.
+func (sap *ServiceAccountProvisioner) applyTokens(ctx context.Context, sa *serviceAccountFromConfig, serviceAccountID int64) error {
+	existing, err := sap.cfg.ServiceAccountStore.ListTokens(ctx, &serviceaccounts.GetSATokensQuery{
+		OrgID:            &sa.OrgID,
+		ServiceAccountID: &serviceAccountID,
+	})
+	if err != nil {
+		return err
+	}
+
+	existingByName := make(map[string]apikey.APIKey, len(existing))
+	for _, t := range existing {
+		existingByName[t.Name] = t
+	}
+
+	var toAdd []*serviceaccounts.AddServiceAccountTokenCommand
+	for _, t := range sa.Tokens {
+		key, err := apikeygenprefix.Decode(t.Key)
+		if err != nil {
+			return fmt.Errorf("invalid key of token %q: %w", t.Name, err)
+		}
+		hashedKey, err := key.Hash()
+		if err != nil {
+			return err
+		}
+
+		if e, ok := existingByName[t.Name]; ok && e.Key == hashedKey {
+			if e.IsRevoked != nil && *e.IsRevoked {
+				sap.log.Warn("provisioned service account token is revoked, its key should be replaced", "serviceAccount", sa.Name, "token", t.Name)
+			}
+			delete(existingByName, t.Name)
+			continue
+		}
+
+		toAdd = append(toAdd, &serviceaccounts.AddServiceAccountTokenCommand{
+			Name:  t.Name,
+			OrgId: sa.OrgID,
+			Key:   hashedKey,
+		})
+	}
+
+	// Tokens are deleted first, so that the name and key of a deleted token can be used by an added token
+	for _, t := range existingByName {
+		sap.log.Debug("deleting service account token missing in configuration", "serviceAccount", sa.Name, "token", t.Name)
+		if err := sap.cfg.ServiceAccountStore.DeleteServiceAccountToken(ctx, sa.OrgID, serviceAccountID, t.Id); err != nil {
+			return fmt.Errorf("failed to delete token %q: %w", t.Name, err)
+		}
+	}
+
+	for _, cmd := range toAdd {
+		sap.log.Debug("adding service account token from configuration", "serviceAccount", sa.Name, "token", cmd.Name)
+		if err := sap.cfg.ServiceAccountStore.AddServiceAccountToken(ctx, serviceAccountID, cmd); err != nil {
+			return fmt.Errorf("failed to add token %q: %w", cmd.Name, err)
+		}
+	}
+
+	return nil
+}
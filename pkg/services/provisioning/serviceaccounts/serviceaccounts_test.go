@@ -0,0 +1,265 @@
+package serviceaccounts
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	apikeygenprefix "github.com/grafana/grafana/pkg/components/apikeygenprefixed"
+	"github.com/grafana/grafana/pkg/services/apikey"
+	"github.com/grafana/grafana/pkg/services/org"
+	"github.com/grafana/grafana/pkg/services/org/orgtest"
+	"github.com/grafana/grafana/pkg/services/serviceaccounts"
+)
+
+func TestServiceAccountProvisioner(t *testing.T) {
+	setup := func() (ProvisionerConfig, *fakeServiceAccounts) {
+		fake := &fakeServiceAccounts{accounts: map[int64]*serviceaccounts.ServiceAccountProfileDTO{}}
+		cfg := ProvisionerConfig{
+			Path:                  allProperties,
+			ServiceAccountService: fake,
+			ServiceAccountStore:   fake,
+			OrgService:            &orgtest.FakeOrgService{ExpectedOrg: &org.Org{ID: 1}},
+		}
+		return cfg, fake
+	}
+
+	t.Run("should provision service accounts and tokens", func(t *testing.T) {
+		t.Setenv("SA_DEPLOY_TOKEN", deployToken)
+		cfg, fake := setup()
+		require.NoError(t, Provision(context.Background(), cfg))
+
+		require.Len(t, fake.accounts, 2)
+		ci := fake.byName("ci")
+		require.NotNil(t, ci)
+		assert.Equal(t, "Editor", ci.Role)
+		assert.False(t, ci.IsDisabled)
+		monitoring := fake.byName("monitoring")
+		require.NotNil(t, monitoring)
+		assert.Equal(t, "Viewer", monitoring.Role)
+		assert.True(t, monitoring.IsDisabled)
+
+		require.Len(t, fake.tokens, 2)
+		assert.Equal(t, hashKey(t, deployToken), fake.token(ci.Id, "deploy").Key)
+		assert.Equal(t, hashKey(t, dashboardsToken), fake.token(ci.Id, "dashboards").Key)
+
+		assert.ElementsMatch(t, []int64{ci.Id, monitoring.Id}, fake.provisioned)
+	})
+
+	t.Run("should reconcile service accounts and tokens", func(t *testing.T) {
+		t.Setenv("SA_DEPLOY_TOKEN", deployToken)
+		cfg, fake := setup()
+		require.NoError(t, Provision(context.Background(), cfg))
+		ci := fake.byName("ci")
+		deploy := *fake.token(ci.Id, "deploy")
+
+		// Changes made outside of provisioning are reverted
+		ci.Role = "Admin"
+		ci.IsDisabled = true
+		fake.addToken(ci.Id, "manual", "hashed")
+		fake.byName("monitoring").IsDisabled = false
+
+		t.Setenv("SA_DEPLOY_TOKEN", rotatedToken)
+		require.NoError(t, Provision(context.Background(), cfg))
+
+		require.Len(t, fake.accounts, 2)
+		assert.Equal(t, "Editor", ci.Role)
+		assert.False(t, ci.IsDisabled)
+		assert.True(t, fake.byName("monitoring").IsDisabled)
+
+		require.Len(t, fake.tokens, 2)
+		assert.Nil(t, fake.token(ci.Id, "manual"))
+		rotated := fake.token(ci.Id, "deploy")
+		require.NotNil(t, rotated)
+		assert.NotEqual(t, deploy.Id, rotated.Id)
+		assert.Equal(t, hashKey(t, rotatedToken), rotated.Key)
+	})
+
+	t.Run("should not change unchanged tokens", func(t *testing.T) {
+		t.Setenv("SA_DEPLOY_TOKEN", deployToken)
+		cfg, fake := setup()
+		require.NoError(t, Provision(context.Background(), cfg))
+		ci := fake.byName("ci")
+		deploy := *fake.token(ci.Id, "deploy")
+
+		require.NoError(t, Provision(context.Background(), cfg))
+		assert.Equal(t, deploy.Id, fake.token(ci.Id, "deploy").Id)
+	})
+
+	t.Run("should delete service accounts and no longer protect removed service accounts", func(t *testing.T) {
+		t.Setenv("SA_DEPLOY_TOKEN", deployToken)
+		cfg, fake := setup()
+		require.NoError(t, Provision(context.Background(), cfg))
+		require.Len(t, fake.provisioned, 2)
+
+		cfg.Path = deleteServiceAccounts
+		require.NoError(t, Provision(context.Background(), cfg))
+
+		require.Len(t, fake.accounts, 1)
+		assert.Nil(t, fake.byName("ci"))
+		assert.Empty(t, fake.tokens)
+		assert.Empty(t, fake.provisioned)
+	})
+
+	t.Run("should not provision anything with an invalid config", func(t *testing.T) {
+		cfg, fake := setup()
+		cfg.Path = invalidKey
+		require.Error(t, Provision(context.Background(), cfg))
+		assert.Empty(t, fake.accounts)
+	})
+
+	t.Run("should return error when a token key is already used", func(t *testing.T) {
+		dir := t.TempDir()
+		config := "apiVersion: 1\nserviceAccounts:\n  - name: ci\n    tokens:\n      - name: deploy\n        key: " + deployToken + "\n"
+		require.NoError(t, os.WriteFile(filepath.Join(dir, "serviceaccounts.yaml"), []byte(config), 0600))
+
+		cfg, fake := setup()
+		cfg.Path = dir
+		fake.addToken(100, "other", hashKey(t, deployToken))
+		require.ErrorIs(t, Provision(context.Background(), cfg), apikey.ErrDuplicate)
+	})
+}
+
+func hashKey(t *testing.T, token string) string {
+	t.Helper()
+	key, err := apikeygenprefix.Decode(token)
+	require.NoError(t, err)
+	hashed, err := key.Hash()
+	require.NoError(t, err)
+	return hashed
+}
+
+type fakeServiceAccounts struct {
+	accounts    map[int64]*serviceaccounts.ServiceAccountProfileDTO
+	tokens      []apikey.APIKey
+	provisioned []int64
+	lastID      int64
+}
+
+func (f *fakeServiceAccounts) nextID() int64 {
+	f.lastID++
+	return f.lastID
+}
+
+func (f *fakeServiceAccounts) byName(name string) *serviceaccounts.ServiceAccountProfileDTO {
+	for _, sa := range f.accounts {
+		if sa.Name == name {
+			return sa
+		}
+	}
+	return nil
+}
+
+func (f *fakeServiceAccounts) token(serviceAccountID int64, name string) *apikey.APIKey {
+	for i := range f.tokens {
+		if *f.tokens[i].ServiceAccountId == serviceAccountID && f.tokens[i].Name == name {
+			return &f.tokens[i]
+		}
+	}
+	return nil
+}
+
+func (f *fakeServiceAccounts) addToken(serviceAccountID int64, name string, key string) {
+	f.tokens = append(f.tokens, apikey.APIKey{Id: f.nextID(), OrgId: 1, Name: name, Key: key, ServiceAccountId: &serviceAccountID})
+}
+
+func (f *fakeServiceAccounts) CreateServiceAccount(ctx context.Context, orgID int64, saForm *serviceaccounts.CreateServiceAccountForm) (*serviceaccounts.ServiceAccountDTO, error) {
+	sa := &serviceaccounts.ServiceAccountProfileDTO{
+		Id:         f.nextID(),
+		OrgId:      orgID,
+		Name:       saForm.Name,
+		Role:       string(*saForm.Role),
+		IsDisabled: *saForm.IsDisabled,
+	}
+	f.accounts[sa.Id] = sa
+	return &serviceaccounts.ServiceAccountDTO{Id: sa.Id, OrgId: orgID, Name: sa.Name, Role: sa.Role, IsDisabled: sa.IsDisabled}, nil
+}
+
+func (f *fakeServiceAccounts) DeleteServiceAccount(ctx context.Context, orgID, serviceAccountID int64) error {
+	delete(f.accounts, serviceAccountID)
+	var tokens []apikey.APIKey
+	for _, t := range f.tokens {
+		if *t.ServiceAccountId != serviceAccountID {
+			tokens = append(tokens, t)
+		}
+	}
+	f.tokens = tokens
+	return nil
+}
+
+func (f *fakeServiceAccounts) RetrieveServiceAccountIdByName(ctx context.Context, orgID int64, name string) (int64, error) {
+	if sa := f.byName(name); sa != nil && sa.OrgId == orgID {
+		return sa.Id, nil
+	}
+	return 0, serviceaccounts.ErrServiceAccountNotFound
+}
+
+func (f *fakeServiceAccounts) SetProvisionedServiceAccounts(serviceAccountIDs []int64) {
+	f.provisioned = serviceAccountIDs
+}
+
+func (f *fakeServiceAccounts) IsServiceAccountProvisioned(serviceAccountID int64) bool {
+	for _, id := range f.provisioned {
+		if id == serviceAccountID {
+			return true
+		}
+	}
+	return false
+}
+
+func (f *fakeServiceAccounts) RetrieveServiceAccount(ctx context.Context, orgID, serviceAccountID int64) (*serviceaccounts.ServiceAccountProfileDTO, error) {
+	sa, ok := f.accounts[serviceAccountID]
+	if !ok || sa.OrgId != orgID {
+		return nil, serviceaccounts.ErrServiceAccountNotFound
+	}
+	return sa, nil
+}
+
+func (f *fakeServiceAccounts) UpdateServiceAccount(ctx context.Context, orgID, serviceAccountID int64, saForm *serviceaccounts.UpdateServiceAccountForm) (*serviceaccounts.ServiceAccountProfileDTO, error) {
+	sa, err := f.RetrieveServiceAccount(ctx, orgID, serviceAccountID)
+	if err != nil {
+		return nil, err
+	}
+	if saForm.Role != nil {
+		sa.Role = string(*saForm.Role)
+	}
+	if saForm.IsDisabled != nil {
+		sa.IsDisabled = *saForm.IsDisabled
+	}
+	return sa, nil
+}
+
+func (f *fakeServiceAccounts) ListTokens(ctx context.Context, query *serviceaccounts.GetSATokensQuery) ([]apikey.APIKey, error) {
+	var tokens []apikey.APIKey
+	for _, t := range f.tokens {
+		if t.OrgId == *query.OrgID && *t.ServiceAccountId == *query.ServiceAccountID {
+			tokens = append(tokens, t)
+		}
+	}
+	return tokens, nil
+}
+
+func (f *fakeServiceAccounts) AddServiceAccountToken(ctx context.Context, serviceAccountID int64, cmd *serviceaccounts.AddServiceAccountTokenCommand) error {
+	for _, t := range f.tokens {
+		if t.Key == cmd.Key {
+			return apikey.ErrDuplicate
+		}
+	}
+	f.addToken(serviceAccountID, cmd.Name, cmd.Key)
+	cmd.Result = &f.tokens[len(f.tokens)-1]
+	return nil
+}
+
+func (f *fakeServiceAccounts) DeleteServiceAccountToken(ctx context.Context, orgID, serviceAccountID, tokenID int64) error {
+	for i, t := range f.tokens {
+		if t.Id == tokenID && *t.ServiceAccountId == serviceAccountID {
+			f.tokens = append(f.tokens[:i], f.tokens[i+1:]...)
+			return nil
+		}
+	}
+	return apikey.ErrNotFound
+}
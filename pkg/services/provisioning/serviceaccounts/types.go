@@ -0,0 +1,96 @@
+package serviceaccounts
+
+import (
+	"github.com/grafana/grafana/pkg/services/provisioning/values"
+)
+
+// configVersion is used to figure out which API version a config uses.
+type configVersion struct {
+	APIVersion int64 `json:"apiVersion" yaml:"apiVersion"`
+}
+
+type configs struct {
+	APIVersion int64
+
+	ServiceAccounts       []*serviceAccountFromConfig
+	DeleteServiceAccounts []*deleteServiceAccountConfig
+}
+
+type serviceAccountFromConfig struct {
+	OrgID      int64
+	Name       string
+	Role       string
+	IsDisabled bool
+	Tokens     []*tokenFromConfig
+}
+
+type tokenFromConfig struct {
+	Name string
+	// Key is the service account token, that is only kept in memory and stored hashed.
+	Key string
+}
+
+type deleteServiceAccountConfig struct {
+	OrgID int64
+	Name  string
+}
+
+type configsV1 struct {
+	configVersion
+
+	ServiceAccounts       []*serviceAccountFromConfigV1   `json:"serviceAccounts" yaml:"serviceAccounts"`
+	DeleteServiceAccounts []*deleteServiceAccountConfigV1 `json:"deleteServiceAccounts" yaml:"deleteServiceAccounts"`
+}
+
+type serviceAccountFromConfigV1 struct {
+	OrgID      values.Int64Value    `json:"orgId" yaml:"orgId"`
+	Name       values.StringValue   `json:"name" yaml:"name"`
+	Role       values.StringValue   `json:"role" yaml:"role"`
+	IsDisabled values.BoolValue     `json:"isDisabled" yaml:"isDisabled"`
+	Tokens     []*tokenFromConfigV1 `json:"tokens" yaml:"tokens"`
+}
+
+type tokenFromConfigV1 struct {
+	Name values.StringValue `json:"name" yaml:"name"`
+	Key  values.StringValue `json:"key" yaml:"key"`
+}
+
+type deleteServiceAccountConfigV1 struct {
+	OrgID values.Int64Value  `json:"orgId" yaml:"orgId"`
+	Name  values.StringValue `json:"name" yaml:"name"`
+}
+
+func (cfg *configsV1) mapToServiceAccountsFromConfig(apiVersion int64) *configs {
+	r := &configs{}
+
+	r.APIVersion = apiVersion
+
+	if cfg == nil {
+		return r
+	}
+
+	for _, sa := range cfg.ServiceAccounts {
+		serviceAccount := &serviceAccountFromConfig{
+			OrgID:      sa.OrgID.Value(),
+			Name:       sa.Name.Value(),
+			Role:       sa.Role.Value(),
+			IsDisabled: sa.IsDisabled.Value(),
+		}
+		for _, t := range sa.Tokens {
+			serviceAccount.Tokens = append(serviceAccount.Tokens, &tokenFromConfig{
+				Name: t.Name.Value(),
+				Key:  t.Key.Value(),
+			})
+		}
+		r.ServiceAccounts = append(r.ServiceAccounts, serviceAccount)
+	}
+
+	for _, sa := range cfg.DeleteServiceAccounts {
+		r.DeleteServiceAccounts = append(r.DeleteServiceAccounts, &deleteServiceAccountConfig{
+			OrgID: sa.OrgID.Value(),
+			Name:  sa.Name.Value(),
+		})
+	}
+
+	return r
+}
@@ -0,0 +1,160 @@
+package serviceaccounts
+
+import (
+	"context"
+	"errors"
+	"os"
+	"path/filepath"
+	"strings"
+
+	apikeygenprefix "github.com/grafana/grafana/pkg/components/apikeygenprefixed"
+	"github.com/grafana/grafana/pkg/infra/log"
+	"github.com/grafana/grafana/pkg/services/apikey"
+	"github.com/grafana/grafana/pkg/services/org"
+	"github.com/grafana/grafana/pkg/services/provisioning/validation"
+	"github.com/grafana/grafana/pkg/services/serviceaccounts"
+)
+
+type ServiceAccountRetriever interface {
+	RetrieveServiceAccountIdByName(ctx context.Context, orgID int64, name string) (int64, error)
+}
+
+type ServiceAccountReader interface {
+	RetrieveServiceAccount(ctx context.Context, orgID, serviceAccountID int64) (*serviceaccounts.ServiceAccountProfileDTO, error)
+	ListTokens(ctx context.Context, query *serviceaccounts.GetSATokensQuery) ([]apikey.APIKey, error)
+}
+
+type ValidatorConfig struct {
+	Path                  string
+	ServiceAccountService ServiceAccountRetriever
+	ServiceAccountStore   ServiceAccountReader
+	OrgService            org.Service
+}
+
+// Validate reads the service account provisioning files in the directory without applying them, and returns the
+// problems of the files and the service accounts that provisioning would create, update or delete.
+func Validate(ctx context.Context, cfg ValidatorConfig) (*validation.Report, error) {
+	report := validation.NewReport("service accounts")
+	cr := &configReader{log: log.New("provisioning.serviceaccounts"), orgService: cfg.OrgService}
+
+	files, err := os.ReadDir(cfg.Path)
+	if err != nil {
+		// Service accounts aren't provisioned without the directory
+		return report, nil
+	}
+
+	type saFile struct {
+		name string
+		cfg  *configs
+	}
+	var saFiles []*saFile
+	var saConfigs []*configs
+	for _, file := range files {
+		if !strings.HasSuffix(file.Name(), ".yaml") && !strings.HasSuffix(file.Name(), ".yml") {
+			continue
+		}
+		filename := filepath.Join(cfg.Path, file.Name())
+
+		c, err := cr.parseServiceAccountConfig(cfg.Path, file)
+		if err != nil {
+			report.AddProblem(filename, 0, err)
+			continue
+		}
+		if c == nil {
+			continue
+		}
+		// Each file is validated with the valid files before it, to find service accounts and keys declared twice
+		if err := cr.validate(ctx, append(saConfigs, c)); err != nil {
+			report.AddProblem(filename, 0, err)
+			continue
+		}
+		saConfigs = append(saConfigs, c)
+		saFiles = append(saFiles, &saFile{name: filename, cfg: c})
+	}
+
+	deleted := map[int64]map[string]bool{}
+	for _, f := range saFiles {
+		for _, sa := range f.cfg.DeleteServiceAccounts {
+			_, err := cfg.ServiceAccountService.RetrieveServiceAccountIdByName(ctx, sa.OrgID, sa.Name)
+			if errors.Is(err, serviceaccounts.ErrServiceAccountNotFound) {
+				continue
+			}
+			if err != nil {
+				return nil, err
+			}
+			report.AddChange(validation.ActionDelete, "service account", sa.OrgID, sa.Name, f.name)
+			if deleted[sa.OrgID] == nil {
+				deleted[sa.OrgID] = map[string]bool{}
+			}
+			deleted[sa.OrgID][sa.Name] = true
+		}
+	}
+
+	for _, f := range saFiles {
+		for _, sa := range f.cfg.ServiceAccounts {
+			id, err := cfg.ServiceAccountService.RetrieveServiceAccountIdByName(ctx, sa.OrgID, sa.Name)
+			if errors.Is(err, serviceaccounts.ErrServiceAccountNotFound) || deleted[sa.OrgID][sa.Name] {
+				report.AddChange(validation.ActionCreate, "service account", sa.OrgID, sa.Name, f.name)
+				continue
+			}
+			if err != nil {
+				return nil, err
+			}
+
+			fields, err := changedFields(ctx, cfg.ServiceAccountStore, sa, id)
+			if err != nil {
+				return nil, err
+			}
+			report.AddUpdate("service account", sa.OrgID, sa.Name, f.name, fields)
+		}
+	}
+
+	return report, nil
+}
+
+// changedFields returns the fields of the service account that differ from the config. The tokens change when
+// provisioning would add or delete any of them.
+func changedFields(ctx context.Context, store ServiceAccountReader, sa *serviceAccountFromConfig, id int64) (validation.Fields, error) {
+	existing, err := store.RetrieveServiceAccount(ctx, sa.OrgID, id)
+	if err != nil {
+		return nil, err
+	}
+	var fields validation.Fields
+	fields.Add("role", existing.Role != sa.Role)
+	fields.Add("isDisabled", existing.IsDisabled != sa.IsDisabled)
+
+	tokens, err := store.ListTokens(ctx, &serviceaccounts.GetSATokensQuery{OrgID: &sa.OrgID, ServiceAccountID: &id})
+	if err != nil {
+		return nil, err
+	}
+	changed, err := tokensChanged(tokens, sa.Tokens)
+	if err != nil {
+		return nil, err
+	}
+	fields.Add("tokens", changed)
+	return fields, nil
+}
+
+func tokensChanged(tokens []apikey.APIKey, provisioned []*tokenFromConfig) (bool, error) {
+	if len(tokens) != len(provisioned) {
+		return true, nil
+	}
+	keys := make(map[string]string, len(tokens))
+	for _, t := range tokens {
+		keys[t.Name] = t.Key
+	}
+	for _, t := range provisioned {
+		key, err := apikeygenprefix.Decode(t.Key)
+		if err != nil {
+			return false, err
+		}
+		hashedKey, err := key.Hash()
+		if err != nil {
+			return false, err
+		}
+		if keys[t.Name] != hashedKey {
+			return true, nil
+		}
+	}
+	return false, nil
+}
@@ -0,0 +1,90 @@
+package serviceaccounts
+
+import (
+	"context"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/grafana/grafana/pkg/services/org"
+	"github.com/grafana/grafana/pkg/services/org/orgtest"
+	"github.com/grafana/grafana/pkg/services/provisioning/validation"
+	"github.com/grafana/grafana/pkg/services/serviceaccounts"
+)
+
+func TestValidate(t *testing.T) {
+	setup := func(path string) (ValidatorConfig, *fakeServiceAccounts) {
+		fake := &fakeServiceAccounts{accounts: map[int64]*serviceaccounts.ServiceAccountProfileDTO{}}
+		cfg := ValidatorConfig{
+			Path:                  path,
+			ServiceAccountService: fake,
+			ServiceAccountStore:   fake,
+			OrgService:            &orgtest.FakeOrgService{ExpectedOrg: &org.Org{ID: 1}},
+		}
+		return cfg, fake
+	}
+	file := filepath.Join(allProperties, "serviceaccounts.yaml")
+
+	t.Run("should return the changes without applying them", func(t *testing.T) {
+		t.Setenv("SA_DEPLOY_TOKEN", deployToken)
+		cfg, fake := setup(allProperties)
+		fake.accounts[1] = &serviceaccounts.ServiceAccountProfileDTO{Id: 1, OrgId: 1, Name: "monitoring", Role: "Viewer"}
+		fake.lastID = 1
+		report, err := Validate(context.Background(), cfg)
+		require.NoError(t, err)
+
+		assert.True(t, report.Valid())
+		assert.ElementsMatch(t, []validation.Change{
+			{Action: validation.ActionCreate, Kind: "service account", OrgID: 1, Name: "ci", File: file},
+			{Action: validation.ActionUpdate, Kind: "service account", OrgID: 1, Name: "monitoring", File: file, Fields: []string{"isDisabled"}},
+		}, report.Changes)
+		assert.Len(t, fake.accounts, 1)
+		assert.False(t, fake.accounts[1].IsDisabled)
+	})
+
+	t.Run("should only return changed tokens", func(t *testing.T) {
+		t.Setenv("SA_DEPLOY_TOKEN", deployToken)
+		cfg, fake := setup(allProperties)
+		require.NoError(t, Provision(context.Background(), ProvisionerConfig{
+			Path:                  allProperties,
+			ServiceAccountService: fake,
+			ServiceAccountStore:   fake,
+			OrgService:            cfg.OrgService,
+		}))
+
+		report, err := Validate(context.Background(), cfg)
+		require.NoError(t, err)
+		assert.Empty(t, report.Changes)
+
+		t.Setenv("SA_DEPLOY_TOKEN", rotatedToken)
+		report, err = Validate(context.Background(), cfg)
+		require.NoError(t, err)
+		assert.Equal(t, []validation.Change{
+			{Action: validation.ActionUpdate, Kind: "service account", OrgID: 1, Name: "ci", File: file, Fields: []string{"tokens"}},
+		}, report.Changes)
+		assert.Equal(t, hashKey(t, deployToken), fake.token(fake.byName("ci").Id, "deploy").Key)
+	})
+
+	t.Run("should return deletions of existing service accounts", func(t *testing.T) {
+		cfg, fake := setup(deleteServiceAccounts)
+		fake.accounts[1] = &serviceaccounts.ServiceAccountProfileDTO{Id: 1, OrgId: 1, Name: "ci", Role: "Editor"}
+		report, err := Validate(context.Background(), cfg)
+		require.NoError(t, err)
+
+		assert.Equal(t, []validation.Change{
+			{Action: validation.ActionDelete, Kind: "service account", OrgID: 1, Name: "ci", File: filepath.Join(deleteServiceAccounts, "serviceaccounts.yaml")},
+		}, report.Changes)
+		assert.Len(t, fake.accounts, 1)
+	})
+
+	t.Run("should report invalid files", func(t *testing.T) {
+		for _, path := range []string{invalidKey, duplicateServiceAccount} {
+			cfg, _ := setup(path)
+			report, err := Validate(context.Background(), cfg)
+			require.NoError(t, err)
+			assert.Len(t, report.Problems, 1, path)
+		}
+	})
+}
@@ -0,0 +1,182 @@
+package provisioning
+
+import (
+	"context"
+	"path/filepath"
+
+	"github.com/grafana/grafana/pkg/infra/db"
+	"github.com/grafana/grafana/pkg/infra/log"
+	plugifaces "github.com/grafana/grafana/pkg/plugins"
+	"github.com/grafana/grafana/pkg/services/alerting"
+	dashboardservice "github.com/grafana/grafana/pkg/services/dashboards"
+	datasourceservice "github.com/grafana/grafana/pkg/services/datasources"
+	"github.com/grafana/grafana/pkg/services/encryption"
+	"github.com/grafana/grafana/pkg/services/featuremgmt"
+	"github.com/grafana/grafana/pkg/services/folder"
+	"github.com/grafana/grafana/pkg/services/libraryelements"
+	"github.com/grafana/grafana/pkg/services/ngalert/provisioning"
+	"github.com/grafana/grafana/pkg/services/ngalert/store"
+	"github.com/grafana/grafana/pkg/services/notifications"
+	"github.com/grafana/grafana/pkg/services/org"
+	"github.com/grafana/grafana/pkg/services/pluginsettings"
+	"github.com/grafana/grafana/pkg/services/provisioning/access"
+	prov_alerting "github.com/grafana/grafana/pkg/services/provisioning/alerting"
+	"github.com/grafana/grafana/pkg/services/provisioning/dashboards"
+	"github.com/grafana/grafana/pkg/services/provisioning/datasources"
+	"github.com/grafana/grafana/pkg/services/provisioning/librarypanels"
+	"github.com/grafana/grafana/pkg/services/provisioning/notifiers"
+	"github.com/grafana/grafana/pkg/services/provisioning/plugins"
+	prov_serviceaccounts "github.com/grafana/grafana/pkg/services/provisioning/serviceaccounts"
+	"github.com/grafana/grafana/pkg/services/provisioning/validation"
+	"github.com/grafana/grafana/pkg/services/quota"
+	"github.com/grafana/grafana/pkg/services/secrets"
+	"github.com/grafana/grafana/pkg/services/serviceaccounts"
+	"github.com/grafana/grafana/pkg/services/team"
+	"github.com/grafana/grafana/pkg/services/user"
+	"github.com/grafana/grafana/pkg/setting"
+)
+
+// ValidationConfig is the provisioning directory and the services that are used to read the current state of the
+// provisioned resources. The services are only read from, so validation never changes the database.
+type ValidationConfig struct {
+	Path                         string
+	Cfg                          *setting.Cfg
+	SQLStore                     db.DB
+	OrgService                   org.Service
+	DatasourceService            datasourceservice.DataSourceService
+	PluginStore                  plugifaces.Store
+	PluginSettings               pluginsettings.Service
+	DashboardProvisioningService dashboardservice.DashboardProvisioningService
+	DashboardService             dashboardservice.DashboardService
+	QuotaService                 quota.Service
+	SecretsService               secrets.Service
+	AlertingService              *alerting.AlertNotificationService
+	EncryptionService            encryption.Internal
+	NotificationService          *notifications.NotificationService
+	FolderService                folder.Service
+	TeamService                  team.Service
+	UserService                  user.Service
+	NestedFolders                bool
+	ServiceAccountsService       serviceaccounts.Service
+	ServiceAccountsStore         serviceaccounts.Store
+	LibraryElementService        libraryelements.Service
+}
+
+// Validate reads the provisioning files of each provisioner in the directory without applying them, and returns
+// a report of the problems and changes of each provisioner in the order they're provisioned.
+func Validate(ctx context.Context, cfg ValidationConfig) ([]*validation.Report, error) {
+	var reports []*validation.Report
+
+	report, err := datasources.Validate(ctx, filepath.Join(cfg.Path, "datasources"), cfg.DatasourceService, cfg.OrgService)
+	if err != nil {
+		return nil, err
+	}
+	reports = append(reports, report)
+
+	report, err = plugins.Validate(ctx, filepath.Join(cfg.Path, "plugins"), cfg.PluginStore, cfg.PluginSettings, cfg.OrgService)
+	if err != nil {
+		return nil, err
+	}
+	reports = append(reports, report)
+
+	report, err = notifiers.Validate(ctx, filepath.Join(cfg.Path, "notifiers"), cfg.AlertingService, cfg.OrgService, cfg.EncryptionService, cfg.NotificationService)
+	if err != nil {
+		return nil, err
+	}
+	reports = append(reports, report)
+
+	logger := log.New("provisioning.alerting")
+	st := store.DBstore{
+		Cfg:      cfg.Cfg.UnifiedAlerting,
+		SQLStore: cfg.SQLStore,
+		Logger:   logger,
+	}
+	report, err = prov_alerting.Validate(ctx, prov_alerting.ValidatorConfig{
+		Path: filepath.Join(cfg.Path, "alerting"),
+		RuleService: provisioning.NewAlertRuleService(st, st, cfg.QuotaService, cfg.SQLStore,
+			int64(cfg.Cfg.UnifiedAlerting.DefaultRuleEvaluationInterval.Seconds()),
+			int64(cfg.Cfg.UnifiedAlerting.BaseInterval.Seconds()),
+			cfg.Cfg.UnifiedAlerting.RecordingRules.Enabled,
+			logger),
+		FolderService:         cfg.DashboardService,
+		ContactPointService:   provisioning.NewContactPointService(&st, cfg.SecretsService, st, cfg.SQLStore, logger),
+		MuteTimingService:     provisioning.NewMuteTimingService(&st, st, &st, logger),
+		TemplateService:       provisioning.NewTemplateService(&st, st, &st, logger),
+		PolicyService:         provisioning.NewNotificationPolicyService(&st, st, cfg.SQLStore, cfg.Cfg.UnifiedAlerting, logger),
+		RecordingRulesEnabled: cfg.Cfg.UnifiedAlerting.RecordingRules.Enabled,
+		DefaultConfiguration:  cfg.Cfg.UnifiedAlerting.DefaultConfiguration,
+	})
+	if err != nil {
+		return nil, err
+	}
+	reports = append(reports, report)
+
+	report, err = access.Validate(ctx, access.ValidatorConfig{
+		Path:          filepath.Join(cfg.Path, "access"),
+		FolderService: cfg.FolderService,
+		TeamService:   cfg.TeamService,
+		UserService:   cfg.UserService,
+		OrgService:    cfg.OrgService,
+		NestedFolders: cfg.NestedFolders,
+	})
+	if err != nil {
+		return nil, err
+	}
+	reports = append(reports, report)
+
+	report, err = prov_serviceaccounts.Validate(ctx, prov_serviceaccounts.ValidatorConfig{
+		Path:                  filepath.Join(cfg.Path, "serviceaccounts"),
+		ServiceAccountService: cfg.ServiceAccountsService,
+		ServiceAccountStore:   cfg.ServiceAccountsStore,
+		OrgService:            cfg.OrgService,
+	})
+	if err != nil {
+		return nil, err
+	}
+	reports = append(reports, report)
+
+	report, err = librarypanels.Validate(ctx, librarypanels.ValidatorConfig{
+		Path:                  filepath.Join(cfg.Path, "librarypanels"),
+		LibraryElementService: cfg.LibraryElementService,
+		FolderService:         cfg.FolderService,
+		OrgService:            cfg.OrgService,
+	})
+	if err != nil {
+		return nil, err
+	}
+	reports = append(reports, report)
+
+	report, err = dashboards.Validate(ctx, filepath.Join(cfg.Path, "dashboards"), cfg.DashboardProvisioningService, cfg.OrgService, cfg.DashboardService)
+	if err != nil {
+		return nil, err
+	}
+	reports = append(reports, report)
+
+	return reports, nil
+}
+
+func (ps *ProvisioningServiceImpl) ValidateProvisioning(ctx context.Context) ([]*validation.Report, error) {
+	return Validate(ctx, ValidationConfig{
+		Path:                         ps.Cfg.ProvisioningPath,
+		Cfg:                          ps.Cfg,
+		SQLStore:                     ps.SQLStore,
+		OrgService:                   ps.orgService,
+		DatasourceService:            ps.datasourceService,
+		PluginStore:                  ps.pluginStore,
+		PluginSettings:               ps.pluginsSettings,
+		DashboardProvisioningService: ps.dashboardProvisioningService,
+		DashboardService:             ps.dashboardService,
+		QuotaService:                 ps.quotaService,
+		SecretsService:               ps.secretService,
+		AlertingService:              ps.alertingService,
+		EncryptionService:            ps.EncryptionService,
+		NotificationService:          ps.NotificationService,
+		FolderService:                ps.folderService,
+		TeamService:                  ps.teamService,
+		UserService:                  ps.userService,
+		NestedFolders:                ps.Cfg.IsFeatureToggleEnabled(featuremgmt.FlagNestedFolders),
+		ServiceAccountsService:       ps.serviceAccountsService,
+		ServiceAccountsStore:         ps.serviceAccountsStore,
+		LibraryElementService:        ps.libraryElementService,
+	})
+}
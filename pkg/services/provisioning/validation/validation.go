@@ -0,0 +1,187 @@
+package validation
+
+import (
+	"encoding/json"
+	"errors"
+	"reflect"
+	"regexp"
+	"strconv"
+
+	"gopkg.in/yaml.v3"
+)
+
+// Action is the change that provisioning makes to a resource.
+type Action string
+
+const (
+	ActionCreate Action = "create"
+	ActionUpdate Action = "update"
+	ActionDelete Action = "delete"
+)
+
+// Problem is a problem in a provisioning config file, that fails or is skipped by provisioning.
+type Problem struct {
+	File    string `json:"file"`
+	Line    int    `json:"line,omitempty"`
+	Message string `json:"message"`
+}
+
+// Change is a resource that provisioning would create, update or delete. Fields are the fields of the resource
+// that an update changes.
+type Change struct {
+	Action Action   `json:"action"`
+	Kind   string   `json:"kind"`
+	OrgID  int64    `json:"orgId"`
+	Name   string   `json:"name"`
+	File   string   `json:"file,omitempty"`
+	Fields []string `json:"fields,omitempty"`
+}
+
+// Report is the result of the validation of the provisioning config files of a provisioner.
+type Report struct {
+	Provisioner string    `json:"provisioner"`
+	Problems    []Problem `json:"problems"`
+	Changes     []Change  `json:"changes"`
+}
+
+func NewReport(provisioner string) *Report {
+	return &Report{Provisioner: provisioner, Problems: []Problem{}, Changes: []Change{}}
+}
+
+// AddProblem adds a problem of the file to the report. When line is 0, the line is read from YAML and JSON
+// syntax errors.
+func (r *Report) AddProblem(file string, line int, err error) {
+	if line == 0 {
+		line = errorLine(err)
+	}
+	r.Problems = append(r.Problems, Problem{File: file, Line: line, Message: err.Error()})
+}
+
+func (r *Report) AddChange(action Action, kind string, orgID int64, name string, file string) {
+	r.Changes = append(r.Changes, Change{Action: action, Kind: kind, OrgID: orgID, Name: name, File: file})
+}
+
+// AddUpdate adds the update of a resource to the report, with the fields that the update changes. Nothing is added
+// when no field changes, as provisioning would leave the resource as it is.
+func (r *Report) AddUpdate(kind string, orgID int64, name string, file string, fields Fields) {
+	if len(fields) == 0 {
+		return
+	}
+	r.Changes = append(r.Changes, Change{Action: ActionUpdate, Kind: kind, OrgID: orgID, Name: name, File: file, Fields: fields})
+}
+
+// Fields are the names of the fields of a resource that provisioning would change, as they're named in the
+// provisioning config files.
+type Fields []string
+
+// Add adds the field when it changes.
+func (f *Fields) Add(field string, changed bool) {
+	if changed {
+		*f = append(*f, field)
+	}
+}
+
+// Compare adds the field when the provisioned value differs from the stored one. The values are compared by their
+// JSON representation, so that the maps and numbers read from the config files equal the stored ones, and empty
+// values equal missing ones.
+func (f *Fields) Compare(field string, provisioned interface{}, stored interface{}) {
+	f.Add(field, !equalJSON(provisioned, stored))
+}
+
+func equalJSON(a, b interface{}) bool {
+	na, err := normalizeJSON(a)
+	if err != nil {
+		return reflect.DeepEqual(a, b)
+	}
+	nb, err := normalizeJSON(b)
+	if err != nil {
+		return reflect.DeepEqual(a, b)
+	}
+	return reflect.DeepEqual(na, nb)
+}
+
+func normalizeJSON(v interface{}) (interface{}, error) {
+	data, err := json.Marshal(v)
+	if err != nil {
+		return nil, err
+	}
+	var normalized interface{}
+	if err := json.Unmarshal(data, &normalized); err != nil {
+		return nil, err
+	}
+	switch n := normalized.(type) {
+	case map[string]interface{}:
+		if len(n) == 0 {
+			return nil, nil
+		}
+	case []interface{}:
+		if len(n) == 0 {
+			return nil, nil
+		}
+	case string:
+		if n == "" {
+			return nil, nil
+		}
+	}
+	return normalized, nil
+}
+
+// Valid returns whether provisioning would apply the config files without problems.
+func (r *Report) Valid() bool {
+	return len(r.Problems) == 0
+}
+
+var yamlLineRegexp = regexp.MustCompile(`line (\d+):`)
+
+// errorLine returns the line of the first YAML error in the error message, or 0 when there is none.
+func errorLine(err error) int {
+	if m := yamlLineRegexp.FindStringSubmatch(err.Error()); m != nil {
+		line, _ := strconv.Atoi(m[1])
+		return line
+	}
+	return 0
+}
+
+// JSONLine returns the line of the offset of a JSON syntax error in the data, or 0 for other errors.
+func JSONLine(data []byte, err error) int {
+	var syntaxErr *json.SyntaxError
+	if !errors.As(err, &syntaxErr) || syntaxErr.Offset > int64(len(data)) {
+		return 0
+	}
+
+	line := 1
+	for _, b := range data[:syntaxErr.Offset] {
+		if b == '\n' {
+			line++
+		}
+	}
+	return line
+}
+
+// Line returns the line of the first mapping in the YAML data with the key set to the value, such as the name
+// of a data source, or 0 when there is no such mapping.
+func Line(data []byte, key string, value string) int {
+	var node yaml.Node
+	if err := yaml.Unmarshal(data, &node); err != nil {
+		return 0
+	}
+	return findLine(&node, key, value)
+}
+
+func findLine(node *yaml.Node, key string, value string) int {
+	if node.Kind == yaml.MappingNode {
+		for i := 0; i+1 < len(node.Content); i += 2 {
+			k, v := node.Content[i], node.Content[i+1]
+			if k.Value == key && v.Kind == yaml.ScalarNode && v.Value == value {
+				return k.Line
+			}
+		}
+	}
+
+	for _, child := range node.Content {
+		if line := findLine(child, key, value); line > 0 {
+			return line
+		}
+	}
+	return 0
+}
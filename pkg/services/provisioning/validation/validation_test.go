@@ -0,0 +1,64 @@
+package validation
+
+import (
+	"encoding/json"
+	"errors"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"gopkg.in/yaml.v3"
+)
+
+func TestLine(t *testing.T) {
+	data := []byte(`apiVersion: 1
+datasources:
+  - name: Graphite
+    type: graphite
+  - name: Prometheus
+    type: prometheus
+`)
+
+	assert.Equal(t, 3, Line(data, "name", "Graphite"))
+	assert.Equal(t, 6, Line(data, "type", "prometheus"))
+	assert.Equal(t, 0, Line(data, "name", "Loki"))
+}
+
+func TestJSONLine(t *testing.T) {
+	data := []byte("{\n  \"title\": \"Grafana\",\n}\n")
+	var v interface{}
+	err := json.Unmarshal(data, &v)
+
+	assert.Equal(t, 3, JSONLine(data, err))
+	assert.Equal(t, 0, JSONLine(data, errors.New("dashboard title cannot be empty")))
+}
+
+func TestReport(t *testing.T) {
+	report := NewReport("datasources")
+	assert.True(t, report.Valid())
+
+	var v interface{}
+	report.AddProblem("default.yaml", 0, yaml.Unmarshal([]byte("a: b\nc"), &v))
+	report.AddProblem("default.yaml", 7, errors.New("organization not found"))
+
+	assert.False(t, report.Valid())
+	assert.Equal(t, 2, report.Problems[0].Line)
+	assert.Equal(t, 7, report.Problems[1].Line)
+}
+
+func TestReportAddUpdate(t *testing.T) {
+	report := NewReport("datasources")
+
+	var fields Fields
+	fields.Compare("url", "http://localhost:9090", "http://localhost:9090")
+	fields.Compare("jsonData", map[string]interface{}{"timeout": 30}, map[string]interface{}{"timeout": 30.0})
+	fields.Compare("secureJsonData", map[string]string{}, nil)
+	report.AddUpdate("data source", 1, "Prometheus", "default.yaml", fields)
+	assert.Empty(t, report.Changes)
+
+	fields.Compare("basicAuth", true, false)
+	fields.Add("editable", true)
+	report.AddUpdate("data source", 1, "Prometheus", "default.yaml", fields)
+	assert.Equal(t, []Change{
+		{Action: ActionUpdate, Kind: "data source", OrgID: 1, Name: "Prometheus", File: "default.yaml", Fields: []string{"basicAuth", "editable"}},
+	}, report.Changes)
+}
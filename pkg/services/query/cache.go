@@ -0,0 +1,229 @@
+package query
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"sort"
+	"time"
+
+	"github.com/grafana/grafana-plugin-sdk-go/backend"
+	"github.com/grafana/grafana-plugin-sdk-go/backend/gtime"
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/promauto"
+
+	"github.com/grafana/grafana/pkg/expr"
+	"github.com/grafana/grafana/pkg/infra/log"
+	"github.com/grafana/grafana/pkg/infra/remotecache"
+	"github.com/grafana/grafana/pkg/services/contexthandler"
+	"github.com/grafana/grafana/pkg/services/datasources"
+	"github.com/grafana/grafana/pkg/setting"
+)
+
+const (
+	cacheStatusHit    = "hit"
+	cacheStatusMiss   = "miss"
+	cacheStatusBypass = "bypass"
+	cacheStatusError  = "error"
+)
+
+var queryCachingRequestsCounter = promauto.NewCounterVec(
+	prometheus.CounterOpts{
+		Namespace: "grafana",
+		Subsystem: "query_caching",
+		Name:      "requests_total",
+		Help:      "A counter for the requests to data sources with query caching enabled, by cache status",
+	},
+	[]string{"datasource_type", "status"},
+)
+
+// volatileQueryFields are the fields of a query that don't change its result, and are left out of its cache key.
+var volatileQueryFields = []string{"requestId", "datasource", "datasourceId", "key"}
+
+// queryCache caches the responses of the data sources that enabled query caching in their settings,
+// with the queryCachingEnabled and queryCachingTTL fields of their JSON data.
+type queryCache struct {
+	storage        remotecache.CacheStorage
+	defaultTTL     time.Duration
+	sendUserHeader bool
+	log            log.Logger
+}
+
+// ProvideQueryCache returns the query cache shared by the query and expression services, or nil if query caching
+// is disabled.
+func ProvideQueryCache(cfg *setting.Cfg, remoteCache *remotecache.RemoteCache) expr.QueryCache {
+	if !cfg.QueryCachingEnabled || remoteCache == nil {
+		// A nil *queryCache would not be a nil expr.QueryCache.
+		return nil
+	}
+	return newQueryCache(remoteCache, cfg.QueryCachingTTL, cfg.SendUserHeader)
+}
+
+func newQueryCache(storage remotecache.CacheStorage, defaultTTL time.Duration, sendUserHeader bool) *queryCache {
+	return &queryCache{
+		storage:        storage,
+		defaultTTL:     defaultTTL,
+		sendUserHeader: sendUserHeader,
+		log:            log.New("query_data.cache"),
+	}
+}
+
+// QueryData returns the cached response of the request if there is one, or else queries the data source and
+// caches its response. The time ranges of the queries are aligned to their intervals in the cache key only, so
+// that requests for time ranges that move with the current time share the cached response for the duration of
+// an interval. The queries sent to the data source keep their time ranges.
+func (c *queryCache) QueryData(ctx context.Context, ds *datasources.DataSource, req *backend.QueryDataRequest, client backend.QueryDataHandler) (*backend.QueryDataResponse, error) {
+	ttl, ok := c.ttl(ds)
+	if !ok {
+		return client.QueryData(ctx, req)
+	}
+
+	key, err := cacheKey(ds, req.Queries)
+	if err != nil {
+		c.log.Warn("Failed to create cache key of queries", "datasource", ds.Uid, "error", err)
+		queryCachingRequestsCounter.WithLabelValues(ds.Type, cacheStatusError).Inc()
+		return client.QueryData(ctx, req)
+	}
+
+	status := cacheStatusBypass
+	if !skipQueryCache(ctx) {
+		var resp *backend.QueryDataResponse
+		resp, status = c.get(ctx, key)
+		if status == cacheStatusHit {
+			queryCachingRequestsCounter.WithLabelValues(ds.Type, status).Inc()
+			return resp, nil
+		}
+	}
+	queryCachingRequestsCounter.WithLabelValues(ds.Type, status).Inc()
+
+	resp, err := client.QueryData(ctx, req)
+	if err != nil {
+		return nil, err
+	}
+	c.set(ctx, key, resp, ttl)
+	return resp, nil
+}
+
+// ttl returns for how long the responses of the data source are cached, and false if they must not be cached.
+func (c *queryCache) ttl(ds *datasources.DataSource) (time.Duration, bool) {
+	if ds.JsonData == nil || !ds.JsonData.Get("queryCachingEnabled").MustBool(false) {
+		return 0, false
+	}
+	// The responses of data sources that forward the identity of the user can't be shared between users.
+	if ds.JsonData.Get("oauthPassThru").MustBool(false) || len(ds.AllowedCookies()) > 0 || c.sendUserHeader {
+		return 0, false
+	}
+
+	ttl := c.defaultTTL
+	if v := ds.JsonData.Get("queryCachingTTL").MustString(""); v != "" {
+		parsed, err := gtime.ParseDuration(v)
+		if err != nil {
+			c.log.Warn("Invalid query caching TTL of data source, using the default TTL", "datasource", ds.Uid, "ttl", v, "error", err)
+		} else {
+			ttl = parsed
+		}
+	}
+	return ttl, ttl > 0
+}
+
+func (c *queryCache) get(ctx context.Context, key string) (*backend.QueryDataResponse, string) {
+	v, err := c.storage.Get(ctx, key)
+	if err != nil {
+		if errors.Is(err, remotecache.ErrCacheItemNotFound) {
+			return nil, cacheStatusMiss
+		}
+		c.log.Warn("Failed to get query response from cache", "error", err)
+		return nil, cacheStatusError
+	}
+
+	b, ok := v.([]byte)
+	if !ok {
+		c.log.Warn("Unexpected type of cached query response", "type", fmt.Sprintf("%T", v))
+		return nil, cacheStatusError
+	}
+	resp := &backend.QueryDataResponse{}
+	if err := json.Unmarshal(b, resp); err != nil {
+		c.log.Warn("Failed to decode cached query response", "error", err)
+		return nil, cacheStatusError
+	}
+	return resp, cacheStatusHit
+}
+
+// set caches the response unless one of its queries failed.
+func (c *queryCache) set(ctx context.Context, key string, resp *backend.QueryDataResponse, ttl time.Duration) {
+	for _, r := range resp.Responses {
+		if r.Error != nil {
+			return
+		}
+	}
+
+	b, err := json.Marshal(resp)
+	if err != nil {
+		c.log.Warn("Failed to encode query response for cache", "error", err)
+		return
+	}
+	if err := c.storage.Set(ctx, key, b, ttl); err != nil {
+		c.log.Warn("Failed to set query response in cache", "error", err)
+	}
+}
+
+// alignTimeRange extends the time range of the query to the closest multiples of its interval.
+func alignTimeRange(q *backend.DataQuery) {
+	if q.Interval <= 0 {
+		return
+	}
+	q.TimeRange.From = q.TimeRange.From.Truncate(q.Interval)
+	if to := q.TimeRange.To.Truncate(q.Interval); to.Before(q.TimeRange.To) {
+		q.TimeRange.To = to.Add(q.Interval)
+	}
+}
+
+// cacheKey returns a key that is the same for the queries of any request to the data source that has the
+// same results. The version of the data source is part of the key, so that changes to its settings don't
+// return responses cached before. The queries are not changed.
+func cacheKey(ds *datasources.DataSource, queries []backend.DataQuery) (string, error) {
+	sorted := make([]backend.DataQuery, len(queries))
+	copy(sorted, queries)
+	for i := range sorted {
+		alignTimeRange(&sorted[i])
+	}
+	sort.Slice(sorted, func(i, j int) bool {
+		return sorted[i].RefID < sorted[j].RefID
+	})
+
+	h := sha256.New()
+	_, _ = fmt.Fprintf(h, "%d\n%s\n%d\n", ds.OrgId, ds.Uid, ds.Version)
+	for _, q := range sorted {
+		model, err := normalizeQuery(q.JSON)
+		if err != nil {
+			return "", err
+		}
+		_, _ = fmt.Fprintf(h, "%s\n%d\n%d\n%d\n%d\n%s\n", q.RefID, q.TimeRange.From.UnixMilli(), q.TimeRange.To.UnixMilli(), q.Interval.Milliseconds(), q.MaxDataPoints, q.QueryType)
+		_, _ = h.Write(model)
+		_, _ = h.Write([]byte("\n"))
+	}
+	return "query-cache:" + hex.EncodeToString(h.Sum(nil)), nil
+}
+
+// normalizeQuery returns the JSON of the query model without its volatile fields, and with its fields sorted.
+func normalizeQuery(model json.RawMessage) ([]byte, error) {
+	var m map[string]interface{}
+	if err := json.Unmarshal(model, &m); err != nil {
+		return nil, err
+	}
+	for _, f := range volatileQueryFields {
+		delete(m, f)
+	}
+	return json.Marshal(m)
+}
+
+func skipQueryCache(ctx context.Context) bool {
+	reqCtx := contexthandler.FromContext(ctx)
+	if reqCtx == nil || reqCtx.Req == nil {
+		return false
+	}
+	return reqCtx.Req.Header.Get(HeaderQueryCacheSkip) != ""
+}
@@ -0,0 +1,286 @@
+package query
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"testing"
+	"time"
+
+	"github.com/grafana/grafana-plugin-sdk-go/backend"
+	"github.com/grafana/grafana-plugin-sdk-go/data"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/grafana/grafana/pkg/components/simplejson"
+	"github.com/grafana/grafana/pkg/expr"
+	"github.com/grafana/grafana/pkg/infra/remotecache"
+	"github.com/grafana/grafana/pkg/models"
+	"github.com/grafana/grafana/pkg/plugins"
+	"github.com/grafana/grafana/pkg/services/contexthandler/ctxkey"
+	"github.com/grafana/grafana/pkg/services/datasources"
+	fakeDatasources "github.com/grafana/grafana/pkg/services/datasources/fakes"
+	"github.com/grafana/grafana/pkg/setting"
+	"github.com/grafana/grafana/pkg/web"
+)
+
+func TestQueryCache(t *testing.T) {
+	cachedDataSource := func(jsonData string) *datasources.DataSource {
+		json, err := simplejson.NewJson([]byte(jsonData))
+		require.NoError(t, err)
+		return &datasources.DataSource{OrgId: 1, Uid: "ds", Type: "prometheus", Version: 1, JsonData: json}
+	}
+	queryRequest := func() *backend.QueryDataRequest {
+		return &backend.QueryDataRequest{
+			Queries: []backend.DataQuery{{
+				RefID:    "A",
+				Interval: time.Minute,
+				TimeRange: backend.TimeRange{
+					From: time.Date(2022, 12, 1, 10, 0, 30, 0, time.UTC),
+					To:   time.Date(2022, 12, 1, 11, 0, 30, 0, time.UTC),
+				},
+				JSON: []byte(`{"refId": "A", "expr": "up", "requestId": "1"}`),
+			}},
+		}
+	}
+
+	t.Run("should not cache responses of data source without query caching", func(t *testing.T) {
+		c := newQueryCache(newFakeCacheStorage(), time.Minute, false)
+		client := &fakeCountingPluginClient{}
+
+		for i := 0; i < 2; i++ {
+			_, err := c.QueryData(context.Background(), cachedDataSource(`{}`), queryRequest(), client)
+			require.NoError(t, err)
+		}
+		require.Equal(t, 2, client.calls)
+	})
+
+	t.Run("should return cached response", func(t *testing.T) {
+		storage := newFakeCacheStorage()
+		c := newQueryCache(storage, time.Minute, false)
+		client := &fakeCountingPluginClient{}
+		ds := cachedDataSource(`{"queryCachingEnabled": true, "queryCachingTTL": "5m"}`)
+
+		first, err := c.QueryData(context.Background(), ds, queryRequest(), client)
+		require.NoError(t, err)
+		second, err := c.QueryData(context.Background(), ds, queryRequest(), client)
+		require.NoError(t, err)
+
+		require.Equal(t, 1, client.calls)
+		require.Len(t, storage.items, 1)
+		for _, item := range storage.items {
+			require.Equal(t, 5*time.Minute, item.ttl)
+		}
+		require.Len(t, second.Responses["A"].Frames, 1)
+		assert.Equal(t, first.Responses["A"].Frames[0].Fields[1].At(0), second.Responses["A"].Frames[0].Fields[1].At(0))
+		assert.Equal(t, "A", second.Responses["A"].Frames[0].RefID)
+
+		sent := client.req.Queries[0].TimeRange
+		assert.Equal(t, queryRequest().Queries[0].TimeRange, sent)
+	})
+
+	t.Run("should share cached response within an interval", func(t *testing.T) {
+		c := newQueryCache(newFakeCacheStorage(), time.Minute, false)
+		client := &fakeCountingPluginClient{}
+		ds := cachedDataSource(`{"queryCachingEnabled": true}`)
+
+		_, err := c.QueryData(context.Background(), ds, queryRequest(), client)
+		require.NoError(t, err)
+		later := queryRequest()
+		later.Queries[0].TimeRange.From = later.Queries[0].TimeRange.From.Add(10 * time.Second)
+		later.Queries[0].TimeRange.To = later.Queries[0].TimeRange.To.Add(10 * time.Second)
+		_, err = c.QueryData(context.Background(), ds, later, client)
+		require.NoError(t, err)
+
+		require.Equal(t, 1, client.calls)
+	})
+
+	t.Run("should query data source when bypass header is set", func(t *testing.T) {
+		c := newQueryCache(newFakeCacheStorage(), time.Minute, false)
+		client := &fakeCountingPluginClient{}
+		ds := cachedDataSource(`{"queryCachingEnabled": true}`)
+
+		httpReq, err := http.NewRequest(http.MethodPost, "/api/ds/query", nil)
+		require.NoError(t, err)
+		httpReq.Header.Set(HeaderQueryCacheSkip, "true")
+		ctx := ctxkey.Set(context.Background(), &models.ReqContext{Context: &web.Context{Req: httpReq}})
+
+		for i := 0; i < 2; i++ {
+			_, err := c.QueryData(ctx, ds, queryRequest(), client)
+			require.NoError(t, err)
+		}
+		require.Equal(t, 2, client.calls)
+
+		_, err = c.QueryData(context.Background(), ds, queryRequest(), client)
+		require.NoError(t, err)
+		require.Equal(t, 2, client.calls)
+	})
+
+	t.Run("should not cache failed queries", func(t *testing.T) {
+		storage := newFakeCacheStorage()
+		c := newQueryCache(storage, time.Minute, false)
+		client := &fakeCountingPluginClient{err: errors.New("query failed")}
+		ds := cachedDataSource(`{"queryCachingEnabled": true}`)
+
+		for i := 0; i < 2; i++ {
+			resp, err := c.QueryData(context.Background(), ds, queryRequest(), client)
+			require.NoError(t, err)
+			require.Error(t, resp.Responses["A"].Error)
+		}
+		require.Equal(t, 2, client.calls)
+		require.Empty(t, storage.items)
+	})
+
+	t.Run("ttl", func(t *testing.T) {
+		c := newQueryCache(newFakeCacheStorage(), time.Minute, false)
+		tcs := []struct {
+			jsonData string
+			ttl      time.Duration
+			ok       bool
+		}{
+			{jsonData: `{}`},
+			{jsonData: `{"queryCachingEnabled": false, "queryCachingTTL": "5m"}`},
+			{jsonData: `{"queryCachingEnabled": true}`, ttl: time.Minute, ok: true},
+			{jsonData: `{"queryCachingEnabled": true, "queryCachingTTL": "1h"}`, ttl: time.Hour, ok: true},
+			{jsonData: `{"queryCachingEnabled": true, "queryCachingTTL": "invalid"}`, ttl: time.Minute, ok: true},
+			{jsonData: `{"queryCachingEnabled": true, "queryCachingTTL": "0s"}`},
+			{jsonData: `{"queryCachingEnabled": true, "oauthPassThru": true}`},
+			{jsonData: `{"queryCachingEnabled": true, "keepCookies": ["session"]}`},
+			{jsonData: `{"queryCachingEnabled": true, "keepCookies": []}`, ttl: time.Minute, ok: true},
+		}
+		for _, tc := range tcs {
+			ttl, ok := c.ttl(cachedDataSource(tc.jsonData))
+			assert.Equal(t, tc.ok, ok, tc.jsonData)
+			assert.Equal(t, tc.ttl, ttl, tc.jsonData)
+		}
+
+		withUserHeader := newQueryCache(newFakeCacheStorage(), time.Minute, true)
+		_, ok := withUserHeader.ttl(cachedDataSource(`{"queryCachingEnabled": true}`))
+		assert.False(t, ok)
+	})
+
+	t.Run("should cache the queries of data sources in expressions", func(t *testing.T) {
+		client := &fakeCountingPluginClient{}
+		exprService := expr.ProvideService(&setting.Cfg{ExpressionsEnabled: true}, client, &fakeDatasources.FakeDataSourceService{}, newQueryCache(newFakeCacheStorage(), time.Minute, false))
+		// Alert rules query the data sources of their conditions with expressions, with a time range relative to the evaluation.
+		req := &expr.Request{Queries: []expr.Query{
+			{
+				RefID:      "A",
+				DataSource: cachedDataSource(`{"queryCachingEnabled": true}`),
+				JSON:       []byte(`{"refId": "A", "expr": "up", "intervalMs": 60000}`),
+				TimeRange:  expr.RelativeTimeRange{From: time.Hour},
+			},
+			{
+				RefID:      "B",
+				DataSource: expr.DataSourceModel(),
+				JSON:       []byte(`{"refId": "B", "type": "math", "expression": "$A * 2"}`),
+			},
+		}}
+
+		now := time.Date(2022, 12, 1, 11, 0, 10, 0, time.UTC)
+		for i := 0; i < 2; i++ {
+			resp, err := exprService.TransformData(context.Background(), now.Add(time.Duration(i)*10*time.Second), req)
+			require.NoError(t, err)
+			require.NoError(t, resp.Responses["B"].Error)
+		}
+		require.Equal(t, 1, client.calls)
+	})
+}
+
+func TestCacheKey(t *testing.T) {
+	ds := &datasources.DataSource{OrgId: 1, Uid: "ds", Version: 1}
+	timeRange := backend.TimeRange{From: time.Unix(0, 0), To: time.Unix(3600, 0)}
+	queryA := backend.DataQuery{RefID: "A", TimeRange: timeRange, JSON: []byte(`{"refId": "A", "expr": "up", "requestId": "1"}`)}
+	queryB := backend.DataQuery{RefID: "B", TimeRange: timeRange, JSON: []byte(`{"expr": "down", "refId": "B"}`)}
+
+	key, err := cacheKey(ds, []backend.DataQuery{queryA, queryB})
+	require.NoError(t, err)
+
+	t.Run("should ignore order of queries and volatile fields", func(t *testing.T) {
+		other := queryA
+		other.JSON = []byte(`{"expr": "up", "refId": "A", "requestId": "2", "datasource": {"uid": "ds"}}`)
+		otherKey, err := cacheKey(ds, []backend.DataQuery{queryB, other})
+		require.NoError(t, err)
+		require.Equal(t, key, otherKey)
+	})
+
+	t.Run("should depend on queries, time range and version of data source", func(t *testing.T) {
+		other := queryA
+		other.JSON = []byte(`{"refId": "A", "expr": "down"}`)
+		otherKey, err := cacheKey(ds, []backend.DataQuery{other, queryB})
+		require.NoError(t, err)
+		require.NotEqual(t, key, otherKey)
+
+		other = queryA
+		other.TimeRange.To = other.TimeRange.To.Add(time.Minute)
+		otherKey, err = cacheKey(ds, []backend.DataQuery{other, queryB})
+		require.NoError(t, err)
+		require.NotEqual(t, key, otherKey)
+
+		otherKey, err = cacheKey(&datasources.DataSource{OrgId: 1, Uid: "ds", Version: 2}, []backend.DataQuery{queryA, queryB})
+		require.NoError(t, err)
+		require.NotEqual(t, key, otherKey)
+	})
+
+	t.Run("should fail on invalid query", func(t *testing.T) {
+		_, err := cacheKey(ds, []backend.DataQuery{{RefID: "A", JSON: []byte(`{`)}})
+		require.Error(t, err)
+	})
+}
+
+type fakeCacheItem struct {
+	value interface{}
+	ttl   time.Duration
+}
+
+type fakeCacheStorage struct {
+	items map[string]fakeCacheItem
+}
+
+func newFakeCacheStorage() *fakeCacheStorage {
+	return &fakeCacheStorage{items: map[string]fakeCacheItem{}}
+}
+
+func (s *fakeCacheStorage) Get(_ context.Context, key string) (interface{}, error) {
+	item, ok := s.items[key]
+	if !ok {
+		return nil, remotecache.ErrCacheItemNotFound
+	}
+	return item.value, nil
+}
+
+func (s *fakeCacheStorage) Set(_ context.Context, key string, value interface{}, expire time.Duration) error {
+	s.items[key] = fakeCacheItem{value: value, ttl: expire}
+	return nil
+}
+
+func (s *fakeCacheStorage) Delete(_ context.Context, key string) error {
+	delete(s.items, key)
+	return nil
+}
+
+type fakeCountingPluginClient struct {
+	plugins.Client
+	calls int
+	req   *backend.QueryDataRequest
+	err   error
+}
+
+func (c *fakeCountingPluginClient) QueryData(_ context.Context, req *backend.QueryDataRequest) (*backend.QueryDataResponse, error) {
+	c.calls++
+	c.req = req
+	resp := backend.NewQueryDataResponse()
+	for _, q := range req.Queries {
+		if c.err != nil {
+			resp.Responses[q.RefID] = backend.DataResponse{Error: c.err}
+			continue
+		}
+		frame := data.NewFrame("",
+			data.NewField("time", nil, []time.Time{q.TimeRange.From}),
+			data.NewField("value", nil, []float64{float64(c.calls)}),
+		)
+		frame.RefID = q.RefID
+		resp.Responses[q.RefID] = backend.DataResponse{Frames: data.Frames{frame}}
+	}
+	return resp, nil
+}
@@ -23,10 +23,11 @@ import (
 )
 
 const (
-	HeaderPluginID      = "X-Plugin-Id"      // can be used for routing
-	HeaderDatasourceUID = "X-Datasource-Uid" // can be used for routing/ load balancing
-	HeaderDashboardUID  = "X-Dashboard-Uid"  // mainly useful for debuging slow queries
-	HeaderPanelID       = "X-Panel-Id"       // mainly useful for debuging slow queries
+	HeaderPluginID       = "X-Plugin-Id"      // can be used for routing
+	HeaderDatasourceUID  = "X-Datasource-Uid" // can be used for routing/ load balancing
+	HeaderDashboardUID   = "X-Dashboard-Uid"  // mainly useful for debuging slow queries
+	HeaderPanelID        = "X-Panel-Id"       // mainly useful for debuging slow queries
+	HeaderQueryCacheSkip = "X-Cache-Skip"     // bypasses the query cache and refreshes the cached response
 )
 
 func ProvideService(
@@ -36,6 +37,7 @@ func ProvideService(
 	pluginRequestValidator models.PluginRequestValidator,
 	dataSourceService datasources.DataSourceService,
 	pluginClient plugins.Client,
+	queryCache expr.QueryCache,
 ) *Service {
 	g := &Service{
 		cfg:                    cfg,
@@ -44,6 +46,7 @@ func ProvideService(
 		pluginRequestValidator: pluginRequestValidator,
 		dataSourceService:      dataSourceService,
 		pluginClient:           pluginClient,
+		queryCache:             queryCache,
 		log:                    log.New("query_data"),
 	}
 	g.log.Info("Query Service initialization")
@@ -57,6 +60,7 @@ type Service struct {
 	pluginRequestValidator models.PluginRequestValidator
 	dataSourceService      datasources.DataSourceService
 	pluginClient           plugins.Client
+	queryCache             expr.QueryCache
 	log                    log.Logger
 }
 
@@ -232,6 +236,9 @@ func (s *Service) handleQuerySingleDatasource(ctx context.Context, user *user.Si
 		req.Queries = append(req.Queries, q.query)
 	}
 
+	if s.queryCache != nil {
+		return s.queryCache.QueryData(ctx, ds, req, s.pluginClient)
+	}
 	return s.pluginClient.QueryData(ctx, req)
 }
 
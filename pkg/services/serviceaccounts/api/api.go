@@ -206,6 +206,10 @@ func (api *ServiceAccountsAPI) UpdateServiceAccount(c *models.ReqContext) respon
 		return response.Error(http.StatusBadRequest, "Service Account ID is invalid", err)
 	}
 
+	if api.service.IsServiceAccountProvisioned(scopeID) {
+		return response.Error(http.StatusBadRequest, "Cannot modify a provisioned service account", serviceaccounts.ErrServiceAccountProvisioned)
+	}
+
 	cmd := serviceaccounts.UpdateServiceAccountForm{}
 	if err := web.Bind(c.Req, &cmd); err != nil {
 		return response.Error(http.StatusBadRequest, "Bad request data", err)
@@ -273,6 +277,9 @@ func (api *ServiceAccountsAPI) DeleteServiceAccount(ctx *models.ReqContext) resp
 	if err != nil {
 		return response.Error(http.StatusBadRequest, "Service account ID is invalid", err)
 	}
+	if api.service.IsServiceAccountProvisioned(scopeID) {
+		return response.Error(http.StatusBadRequest, "Cannot modify a provisioned service account", serviceaccounts.ErrServiceAccountProvisioned)
+	}
 	err = api.service.DeleteServiceAccount(ctx.Req.Context(), ctx.OrgID, scopeID)
 	if err != nil {
 		return response.Error(http.StatusInternalServerError, "Service account deletion error", err)
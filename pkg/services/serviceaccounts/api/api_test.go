@@ -268,6 +268,31 @@ func TestServiceAccountsAPI_DeleteServiceAccount(t *testing.T) {
 			require.Equal(t, testcase.expectedCode, actual)
 		})
 	})
+
+	t.Run("should not be able to delete a provisioned serviceaccount", func(t *testing.T) {
+		testcase := struct {
+			user         tests.TestUser
+			acmock       *accesscontrolmock.Mock
+			expectedCode int
+		}{
+			user: tests.TestUser{Login: "servicetest3@admin", IsServiceAccount: true},
+			acmock: tests.SetupMockAccesscontrol(
+				t,
+				func(c context.Context, siu *user.SignedInUser, _ accesscontrol.Options) ([]accesscontrol.Permission, error) {
+					return []accesscontrol.Permission{{Action: serviceaccounts.ActionDelete, Scope: serviceaccounts.ScopeAll}}, nil
+				},
+				false,
+			),
+			expectedCode: http.StatusBadRequest,
+		}
+		serviceAccountRequestScenario(t, http.MethodDelete, serviceAccountIDPath, &testcase.user, func(httpmethod string, endpoint string, user *tests.TestUser) {
+			createduser := tests.SetupUserServiceAccount(t, store, testcase.user)
+			svc := &tests.ServiceAccountMock{ProvisionedIDs: []int64{createduser.ID}}
+			server, _ := setupTestServer(t, svc, routing.NewRouteRegister(), testcase.acmock, store, services.SAStore)
+			actual := requestResponse(server, httpmethod, fmt.Sprintf(endpoint, fmt.Sprint(createduser.ID))).Code
+			require.Equal(t, testcase.expectedCode, actual)
+		})
+	})
 }
 
 func serviceAccountRequestScenario(t *testing.T, httpMethod string, endpoint string, user *tests.TestUser, fn func(httpmethod string, endpoint string, user *tests.TestUser)) {
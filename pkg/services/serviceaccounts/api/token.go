@@ -143,6 +143,10 @@ func (api *ServiceAccountsAPI) CreateToken(c *models.ReqContext) response.Respon
 		}
 	}
 
+	if api.service.IsServiceAccountProvisioned(saID) {
+		return response.Error(http.StatusBadRequest, "Cannot modify a provisioned service account", serviceaccounts.ErrServiceAccountProvisioned)
+	}
+
 	cmd := serviceaccounts.AddServiceAccountTokenCommand{}
 	if err := web.Bind(c.Req, &cmd); err != nil {
 		return response.Error(http.StatusBadRequest, "Bad request data", err)
@@ -226,6 +230,10 @@ func (api *ServiceAccountsAPI) DeleteToken(c *models.ReqContext) response.Respon
 		}
 	}
 
+	if api.service.IsServiceAccountProvisioned(saID) {
+		return response.Error(http.StatusBadRequest, "Cannot modify a provisioned service account", serviceaccounts.ErrServiceAccountProvisioned)
+	}
+
 	tokenID, err := strconv.ParseInt(web.Params(c.Req)[":tokenId"], 10, 64)
 	if err != nil {
 		return response.Error(http.StatusBadRequest, "Token ID is invalid", err)
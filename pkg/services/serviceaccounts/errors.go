@@ -6,4 +6,5 @@ var (
 	ErrServiceAccountNotFound            = errors.New("service account not found")
 	ErrServiceAccountInvalidRole         = errors.New("invalid role specified")
 	ErrServiceAccountRolePrivilegeDenied = errors.New("can not assign a role higher than user's role")
+	ErrServiceAccountProvisioned         = errors.New("cannot modify a provisioned service account")
 )
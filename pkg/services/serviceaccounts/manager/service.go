@@ -3,6 +3,7 @@ package manager
 import (
 	"context"
 	"fmt"
+	"sync"
 	"time"
 
 	"github.com/grafana/grafana/pkg/api/routing"
@@ -28,6 +29,9 @@ type ServiceAccountsService struct {
 
 	secretScanEnabled  bool
 	secretScanInterval time.Duration
+
+	provisionedMu sync.RWMutex
+	provisioned   map[int64]bool
 }
 
 func ProvideServiceAccountsService(
@@ -43,6 +47,7 @@ func ProvideServiceAccountsService(
 		store:         serviceAccountsStore,
 		log:           log.New("serviceaccounts"),
 		backgroundLog: log.New("serviceaccounts.background"),
+		provisioned:   map[int64]bool{},
 	}
 
 	if err := RegisterRoles(accesscontrolService); err != nil {
@@ -132,3 +137,20 @@ func (sa *ServiceAccountsService) DeleteServiceAccount(ctx context.Context, orgI
 func (sa *ServiceAccountsService) RetrieveServiceAccountIdByName(ctx context.Context, orgID int64, name string) (int64, error) {
 	return sa.store.RetrieveServiceAccountIdByName(ctx, orgID, name)
 }
+
+func (sa *ServiceAccountsService) SetProvisionedServiceAccounts(serviceAccountIDs []int64) {
+	provisioned := make(map[int64]bool, len(serviceAccountIDs))
+	for _, id := range serviceAccountIDs {
+		provisioned[id] = true
+	}
+
+	sa.provisionedMu.Lock()
+	defer sa.provisionedMu.Unlock()
+	sa.provisioned = provisioned
+}
+
+func (sa *ServiceAccountsService) IsServiceAccountProvisioned(serviceAccountID int64) bool {
+	sa.provisionedMu.RLock()
+	defer sa.provisionedMu.RUnlock()
+	return sa.provisioned[serviceAccountID]
+}
@@ -17,6 +17,10 @@ type Service interface {
 	CreateServiceAccount(ctx context.Context, orgID int64, saForm *CreateServiceAccountForm) (*ServiceAccountDTO, error)
 	DeleteServiceAccount(ctx context.Context, orgID, serviceAccountID int64) error
 	RetrieveServiceAccountIdByName(ctx context.Context, orgID int64, name string) (int64, error)
+	// SetProvisionedServiceAccounts replaces the service accounts that are managed by provisioning, which can't be
+	// modified through the API.
+	SetProvisionedServiceAccounts(serviceAccountIDs []int64)
+	IsServiceAccountProvisioned(serviceAccountID int64) bool
 }
 
 /*
@@ -102,7 +102,9 @@ func SetupApiKey(t *testing.T, sqlStore *sqlstore.SQLStore, testKey TestApiKey)
 }
 
 // create mock for serviceaccountservice
-type ServiceAccountMock struct{}
+type ServiceAccountMock struct {
+	ProvisionedIDs []int64
+}
 
 func (s *ServiceAccountMock) RetrieveServiceAccountIdByName(ctx context.Context, orgID int64, name string) (int64, error) {
 	return 0, nil
@@ -116,6 +118,19 @@ func (s *ServiceAccountMock) DeleteServiceAccount(ctx context.Context, orgID, se
 	return nil
 }
 
+func (s *ServiceAccountMock) SetProvisionedServiceAccounts(serviceAccountIDs []int64) {
+	s.ProvisionedIDs = serviceAccountIDs
+}
+
+func (s *ServiceAccountMock) IsServiceAccountProvisioned(serviceAccountID int64) bool {
+	for _, id := range s.ProvisionedIDs {
+		if id == serviceAccountID {
+			return true
+		}
+	}
+	return false
+}
+
 func (s *ServiceAccountMock) Migrated(ctx context.Context, orgID int64) bool {
 	return false
 }
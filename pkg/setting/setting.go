@@ -403,6 +403,21 @@ type Cfg struct {
 
 	// Data sources
 	DataSourceLimit int
+	// SQLiteDataSourceAllowedPaths are the database files, or the directories containing them,
+	// that the SQLite data source is allowed to open.
+	SQLiteDataSourceAllowedPaths []string
+	// DuckDBDataSourceAllowedPaths are the database and data files, or the directories containing them,
+	// that the DuckDB data source is allowed to open.
+	DuckDBDataSourceAllowedPaths []string
+	// DuckDBDataSourceMaxDataFileSize is the maximum size in bytes of the CSV and Parquet files
+	// that the DuckDB data source loads in memory, 0 for no limit.
+	DuckDBDataSourceMaxDataFileSize int64
+
+	// Query caching
+	QueryCachingEnabled bool
+	// QueryCachingTTL is for how long query responses are cached, for the data sources
+	// that enable query caching without their own TTL.
+	QueryCachingTTL time.Duration
 
 	// Snapshots
 	SnapshotPublicMode bool
@@ -1085,6 +1100,7 @@ func (cfg *Cfg) Load(args CommandLineArgs) error {
 	}
 
 	cfg.readDataSourcesSettings()
+	cfg.readQueryCachingSettings()
 
 	cfg.DashboardPreviews = readDashboardPreviewsSettings(iniFile)
 	cfg.Storage = readStorageSettings(iniFile)
@@ -1736,6 +1752,28 @@ func (cfg *Cfg) GetContentDeliveryURL(prefix string) string {
 func (cfg *Cfg) readDataSourcesSettings() {
 	datasources := cfg.Raw.Section("datasources")
 	cfg.DataSourceLimit = datasources.Key("datasource_limit").MustInt(5000)
+
+	cfg.SQLiteDataSourceAllowedPaths = readAllowedPaths(cfg.Raw.Section("datasources.sqlite"))
+	duckdb := cfg.Raw.Section("datasources.duckdb")
+	cfg.DuckDBDataSourceAllowedPaths = readAllowedPaths(duckdb)
+	cfg.DuckDBDataSourceMaxDataFileSize = duckdb.Key("max_data_file_size_mb").MustInt64(100) * 1024 * 1024
+}
+
+// readAllowedPaths reads the comma-separated allowed_paths of a data source that opens local files.
+func readAllowedPaths(section *ini.Section) []string {
+	paths := []string{}
+	for _, p := range strings.Split(section.Key("allowed_paths").MustString(""), ",") {
+		if p = strings.TrimSpace(p); p != "" {
+			paths = append(paths, p)
+		}
+	}
+	return paths
+}
+
+func (cfg *Cfg) readQueryCachingSettings() {
+	queryCaching := cfg.Raw.Section("query_caching")
+	cfg.QueryCachingEnabled = queryCaching.Key("enabled").MustBool(true)
+	cfg.QueryCachingTTL = queryCaching.Key("ttl").MustDuration(time.Minute)
 }
 
 func GetAllowedOriginGlobs(originPatterns []string) ([]glob.Glob, error) {
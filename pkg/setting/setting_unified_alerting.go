@@ -52,6 +52,19 @@ const (
 	screenshotsDefaultCapture               = false
 	screenshotsDefaultMaxConcurrent         = 5
 	screenshotsDefaultUploadImageStorage    = false
+	screenshotsDefaultStoreInFileStorage    = false
+	screenshotsDefaultFileStorageType       = ScreenshotsFileStorageDatabase
+	stateHistoryDefaultEnabled              = true
+	stateHistoryDefaultBackend              = "annotations"
+	recordingRulesDefaultEnabled            = false
+	recordingRulesDefaultTimeout            = 10 * time.Second
+	notificationHistoryDefaultEnabled       = true
+	notificationHistoryDefaultRetention     = 7 * 24 * time.Hour
+	notificationRetryDefaultMaxAttempts     = 0
+	notificationRetryDefaultInitialInterval = 500 * time.Millisecond
+	notificationRetryDefaultMaxInterval     = time.Minute
+	deadLetterDefaultEnabled                = true
+	deadLetterDefaultRetention              = 7 * 24 * time.Hour
 	// SchedulerBaseInterval base interval of the scheduler. Controls how often the scheduler fetches database for new changes as well as schedules evaluation of a rule
 	// changing this value is discouraged because this could cause existing alert definition
 	// with intervals that are not exactly divided by this number not to be evaluated
@@ -83,18 +96,63 @@ type UnifiedAlertingSettings struct {
 	DefaultRuleEvaluationInterval time.Duration
 	Screenshots                   UnifiedAlertingScreenshotSettings
 	ReservedLabels                UnifiedAlertingReservedLabelSettings
+	StateHistory                  UnifiedAlertingStateHistorySettings
+	RecordingRules                UnifiedAlertingRecordingRuleSettings
+	NotificationHistory           UnifiedAlertingNotificationHistorySettings
+	NotificationRetry             UnifiedAlertingNotificationRetrySettings
 }
 
+const (
+	ScreenshotsFileStorageDatabase = "database"
+	ScreenshotsFileStorageBlob     = "blob"
+)
+
 type UnifiedAlertingScreenshotSettings struct {
 	Capture                    bool
 	MaxConcurrentScreenshots   int64
 	UploadExternalImageStorage bool
+	StoreInFileStorage         bool
+	FileStorageType            string
+	FileStorageBlobURL         string
 }
 
 type UnifiedAlertingReservedLabelSettings struct {
 	DisabledLabels map[string]struct{}
 }
 
+type UnifiedAlertingStateHistorySettings struct {
+	Enabled               bool
+	Backend               string
+	LokiRemoteURL         string
+	LokiTenantID          string
+	LokiBasicAuthUsername string
+	LokiBasicAuthPassword string
+	ExternalLabels        map[string]string
+}
+
+type UnifiedAlertingRecordingRuleSettings struct {
+	Enabled           bool
+	URL               string
+	BasicAuthUsername string
+	BasicAuthPassword string
+	Timeout           time.Duration
+}
+
+type UnifiedAlertingNotificationHistorySettings struct {
+	Enabled   bool
+	Retention time.Duration
+}
+
+type UnifiedAlertingNotificationRetrySettings struct {
+	// MaxAttempts is the default maximum number of attempts to send a notification. If 0, failed
+	// notifications are retried until the notification times out.
+	MaxAttempts         int
+	InitialInterval     time.Duration
+	MaxInterval         time.Duration
+	DeadLetterEnabled   bool
+	DeadLetterRetention time.Duration
+}
+
 // IsEnabled returns true if UnifiedAlertingSettings.Enabled is either nil or true.
 // It hides the implementation details of the Enabled and simplifies its usage.
 func (u *UnifiedAlertingSettings) IsEnabled() bool {
@@ -283,6 +341,23 @@ func (cfg *Cfg) ReadUnifiedAlertingSettings(iniFile *ini.File) error {
 	uaCfgScreenshots.Capture = screenshots.Key("capture").MustBool(screenshotsDefaultCapture)
 	uaCfgScreenshots.MaxConcurrentScreenshots = screenshots.Key("max_concurrent_screenshots").MustInt64(screenshotsDefaultMaxConcurrent)
 	uaCfgScreenshots.UploadExternalImageStorage = screenshots.Key("upload_external_image_storage").MustBool(screenshotsDefaultUploadImageStorage)
+	uaCfgScreenshots.StoreInFileStorage = screenshots.Key("store_in_file_storage").MustBool(screenshotsDefaultStoreInFileStorage)
+	uaCfgScreenshots.FileStorageType = screenshots.Key("file_storage_type").MustString(screenshotsDefaultFileStorageType)
+	uaCfgScreenshots.FileStorageBlobURL = screenshots.Key("file_storage_blob_url").MustString("")
+	if uaCfgScreenshots.StoreInFileStorage {
+		if uaCfgScreenshots.UploadExternalImageStorage {
+			return errors.New("screenshots cannot be both uploaded to external image storage and stored in file storage, please disable one of 'upload_external_image_storage' and 'store_in_file_storage'")
+		}
+		switch uaCfgScreenshots.FileStorageType {
+		case ScreenshotsFileStorageDatabase:
+		case ScreenshotsFileStorageBlob:
+			if uaCfgScreenshots.FileStorageBlobURL == "" {
+				return fmt.Errorf("setting 'file_storage_blob_url' is required when 'file_storage_type' is %q", ScreenshotsFileStorageBlob)
+			}
+		default:
+			return fmt.Errorf("invalid value of setting 'file_storage_type' %q, should be either %q or %q", uaCfgScreenshots.FileStorageType, ScreenshotsFileStorageDatabase, ScreenshotsFileStorageBlob)
+		}
+	}
 	uaCfg.Screenshots = uaCfgScreenshots
 
 	reservedLabels := iniFile.Section("unified_alerting.reserved_labels")
@@ -294,6 +369,60 @@ func (cfg *Cfg) ReadUnifiedAlertingSettings(iniFile *ini.File) error {
 	}
 	uaCfg.ReservedLabels = uaCfgReservedLabels
 
+	stateHistory := iniFile.Section("unified_alerting.state_history")
+	uaCfgStateHistory := UnifiedAlertingStateHistorySettings{
+		Enabled:               stateHistory.Key("enabled").MustBool(stateHistoryDefaultEnabled),
+		Backend:               stateHistory.Key("backend").MustString(stateHistoryDefaultBackend),
+		LokiRemoteURL:         stateHistory.Key("loki_remote_url").MustString(""),
+		LokiTenantID:          stateHistory.Key("loki_tenant_id").MustString(""),
+		LokiBasicAuthUsername: stateHistory.Key("loki_basic_auth_username").MustString(""),
+		LokiBasicAuthPassword: stateHistory.Key("loki_basic_auth_password").MustString(""),
+		ExternalLabels:        iniFile.Section("unified_alerting.state_history.external_labels").KeysHash(),
+	}
+	uaCfg.StateHistory = uaCfgStateHistory
+
+	recordingRules := iniFile.Section("unified_alerting.recording_rules")
+	uaCfgRecordingRules := UnifiedAlertingRecordingRuleSettings{
+		Enabled:           recordingRules.Key("enabled").MustBool(recordingRulesDefaultEnabled),
+		URL:               recordingRules.Key("url").MustString(""),
+		BasicAuthUsername: recordingRules.Key("basic_auth_username").MustString(""),
+		BasicAuthPassword: recordingRules.Key("basic_auth_password").MustString(""),
+		Timeout:           recordingRules.Key("timeout").MustDuration(recordingRulesDefaultTimeout),
+	}
+	uaCfg.RecordingRules = uaCfgRecordingRules
+
+	notificationHistory := iniFile.Section("unified_alerting.notification_history")
+	uaCfgNotificationHistory := UnifiedAlertingNotificationHistorySettings{
+		Enabled:   notificationHistory.Key("enabled").MustBool(notificationHistoryDefaultEnabled),
+		Retention: notificationHistory.Key("retention").MustDuration(notificationHistoryDefaultRetention),
+	}
+	if uaCfgNotificationHistory.Retention <= 0 {
+		return fmt.Errorf("value of setting 'retention' in section 'unified_alerting.notification_history' should be greater than 0")
+	}
+	uaCfg.NotificationHistory = uaCfgNotificationHistory
+
+	notificationRetry := iniFile.Section("unified_alerting.notification_retry")
+	uaCfgNotificationRetry := UnifiedAlertingNotificationRetrySettings{
+		MaxAttempts:         notificationRetry.Key("max_attempts").MustInt(notificationRetryDefaultMaxAttempts),
+		InitialInterval:     notificationRetry.Key("initial_interval").MustDuration(notificationRetryDefaultInitialInterval),
+		MaxInterval:         notificationRetry.Key("max_interval").MustDuration(notificationRetryDefaultMaxInterval),
+		DeadLetterEnabled:   notificationRetry.Key("dead_letter_enabled").MustBool(deadLetterDefaultEnabled),
+		DeadLetterRetention: notificationRetry.Key("dead_letter_retention").MustDuration(deadLetterDefaultRetention),
+	}
+	if uaCfgNotificationRetry.MaxAttempts < 0 {
+		return fmt.Errorf("value of setting 'max_attempts' in section 'unified_alerting.notification_retry' should not be negative")
+	}
+	if uaCfgNotificationRetry.InitialInterval <= 0 {
+		return fmt.Errorf("value of setting 'initial_interval' in section 'unified_alerting.notification_retry' should be greater than 0")
+	}
+	if uaCfgNotificationRetry.MaxInterval < uaCfgNotificationRetry.InitialInterval {
+		return fmt.Errorf("value of setting 'max_interval' in section 'unified_alerting.notification_retry' should be greater than or equal to 'initial_interval'")
+	}
+	if uaCfgNotificationRetry.DeadLetterRetention <= 0 {
+		return fmt.Errorf("value of setting 'dead_letter_retention' in section 'unified_alerting.notification_retry' should be greater than 0")
+	}
+	uaCfg.NotificationRetry = uaCfgNotificationRetry
+
 	cfg.UnifiedAlerting = uaCfg
 	return nil
 }
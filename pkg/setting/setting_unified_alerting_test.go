@@ -25,6 +25,17 @@ func TestCfg_ReadUnifiedAlertingSettings(t *testing.T) {
 		require.Len(t, cfg.UnifiedAlerting.HAPeers, 0)
 		require.Equal(t, 200*time.Millisecond, cfg.UnifiedAlerting.HAGossipInterval)
 		require.Equal(t, time.Minute, cfg.UnifiedAlerting.HAPushPullInterval)
+		require.True(t, cfg.UnifiedAlerting.StateHistory.Enabled)
+		require.Equal(t, "annotations", cfg.UnifiedAlerting.StateHistory.Backend)
+		require.False(t, cfg.UnifiedAlerting.RecordingRules.Enabled)
+		require.Equal(t, 10*time.Second, cfg.UnifiedAlerting.RecordingRules.Timeout)
+		require.True(t, cfg.UnifiedAlerting.NotificationHistory.Enabled)
+		require.Equal(t, 7*24*time.Hour, cfg.UnifiedAlerting.NotificationHistory.Retention)
+		require.Equal(t, 0, cfg.UnifiedAlerting.NotificationRetry.MaxAttempts)
+		require.Equal(t, 500*time.Millisecond, cfg.UnifiedAlerting.NotificationRetry.InitialInterval)
+		require.Equal(t, time.Minute, cfg.UnifiedAlerting.NotificationRetry.MaxInterval)
+		require.True(t, cfg.UnifiedAlerting.NotificationRetry.DeadLetterEnabled)
+		require.Equal(t, 7*24*time.Hour, cfg.UnifiedAlerting.NotificationRetry.DeadLetterRetention)
 	}
 
 	// With peers set, it correctly parses them.
@@ -39,6 +50,52 @@ func TestCfg_ReadUnifiedAlertingSettings(t *testing.T) {
 		require.Len(t, cfg.UnifiedAlerting.HAPeers, 3)
 		require.ElementsMatch(t, []string{"hostname1:9090", "hostname2:9090", "hostname3:9090"}, cfg.UnifiedAlerting.HAPeers)
 	}
+
+	// With a Loki state history backend, it reads the remote settings.
+	{
+		s, err := cfg.Raw.NewSection("unified_alerting.state_history")
+		require.NoError(t, err)
+		_, err = s.NewKey("backend", "loki")
+		require.NoError(t, err)
+		_, err = s.NewKey("loki_remote_url", "http://localhost:3100")
+		require.NoError(t, err)
+		_, err = s.NewKey("loki_tenant_id", "tenant")
+		require.NoError(t, err)
+		labels, err := cfg.Raw.NewSection("unified_alerting.state_history.external_labels")
+		require.NoError(t, err)
+		_, err = labels.NewKey("cluster", "prod")
+		require.NoError(t, err)
+
+		require.NoError(t, cfg.ReadUnifiedAlertingSettings(cfg.Raw))
+		require.Equal(t, "loki", cfg.UnifiedAlerting.StateHistory.Backend)
+		require.Equal(t, "http://localhost:3100", cfg.UnifiedAlerting.StateHistory.LokiRemoteURL)
+		require.Equal(t, "tenant", cfg.UnifiedAlerting.StateHistory.LokiTenantID)
+		require.Equal(t, map[string]string{"cluster": "prod"}, cfg.UnifiedAlerting.StateHistory.ExternalLabels)
+	}
+
+	// With screenshots stored in file storage, it validates the storage type.
+	{
+		require.False(t, cfg.UnifiedAlerting.Screenshots.StoreInFileStorage)
+		require.Equal(t, "database", cfg.UnifiedAlerting.Screenshots.FileStorageType)
+
+		s, err := cfg.Raw.NewSection("unified_alerting.screenshots")
+		require.NoError(t, err)
+		_, err = s.NewKey("store_in_file_storage", "true")
+		require.NoError(t, err)
+		_, err = s.NewKey("file_storage_type", "blob")
+		require.NoError(t, err)
+		require.EqualError(t, cfg.ReadUnifiedAlertingSettings(cfg.Raw), `setting 'file_storage_blob_url' is required when 'file_storage_type' is "blob"`)
+
+		_, err = s.NewKey("file_storage_blob_url", "file:///var/lib/grafana/images")
+		require.NoError(t, err)
+		require.NoError(t, cfg.ReadUnifiedAlertingSettings(cfg.Raw))
+		require.True(t, cfg.UnifiedAlerting.Screenshots.StoreInFileStorage)
+		require.Equal(t, "file:///var/lib/grafana/images", cfg.UnifiedAlerting.Screenshots.FileStorageBlobURL)
+
+		_, err = s.NewKey("upload_external_image_storage", "true")
+		require.NoError(t, err)
+		require.Error(t, cfg.ReadUnifiedAlertingSettings(cfg.Raw))
+	}
 }
 
 func TestUnifiedAlertingSettings(t *testing.T) {
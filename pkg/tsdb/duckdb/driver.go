@@ -0,0 +1,94 @@
+//go:build duckdb
+
+package duckdb
+
+import (
+	"context"
+	"database/sql"
+	"database/sql/driver"
+	"fmt"
+	"io"
+	"path/filepath"
+	"strings"
+
+	"github.com/marcboeker/go-duckdb"
+	"xorm.io/core"
+)
+
+func init() {
+	sql.Register(driverName, dataSourceDriver{})
+	// xorm only runs the raw SQL queries of the data source, so the DuckDB driver reuses the SQLite dialect.
+	core.RegisterDriver(driverName, core.QueryDriver("sqlite3"))
+}
+
+// checkDriver returns an error if Grafana was built without the DuckDB driver.
+func checkDriver() error {
+	return nil
+}
+
+// dataSourceDriver opens DuckDB database files in read-only mode, and loads data files into a table of an
+// in-memory database, before disabling the access to the file system.
+type dataSourceDriver struct{}
+
+func (d dataSourceDriver) Open(path string) (driver.Conn, error) {
+	connector, err := d.OpenConnector(path)
+	if err != nil {
+		return nil, err
+	}
+	return connector.Connect(context.Background())
+}
+
+func (dataSourceDriver) OpenConnector(path string) (driver.Connector, error) {
+	reader, ok := dataFileReaders[strings.ToLower(filepath.Ext(path))]
+	if !ok {
+		// The DuckDB driver reads the configuration options after the first "?", checkPath rejects paths that contain one.
+		return duckdb.NewConnector(path+"?access_mode=read_only&enable_external_access=false&lock_configuration=true", nil)
+	}
+
+	connector, err := duckdb.NewConnector("", nil)
+	if err != nil {
+		return nil, err
+	}
+	if err := loadDataFile(connector, reader, path); err != nil {
+		if closer, ok := connector.(io.Closer); ok {
+			_ = closer.Close()
+		}
+		return nil, err
+	}
+	return connector, nil
+}
+
+// loadDataFile creates a table named after the data file, with the rows of the file, and then disables the access to
+// the file system and locks the configuration, so that the queries of the data source can't access other files.
+// The file is read once, when the connector is opened; the table is not refreshed when the file changes.
+func loadDataFile(connector driver.Connector, reader, path string) error {
+	ctx := context.Background()
+	conn, err := connector.Connect(ctx)
+	if err != nil {
+		return err
+	}
+	defer func() {
+		_ = conn.Close()
+	}()
+
+	table := strings.TrimSuffix(filepath.Base(path), filepath.Ext(path))
+	execer := conn.(driver.ExecerContext)
+	for _, stmt := range []string{
+		fmt.Sprintf("CREATE TABLE %s AS SELECT * FROM %s(%s)", quoteIdentifier(table), reader, quoteLiteral(path)),
+		"SET enable_external_access = false",
+		"SET lock_configuration = true",
+	} {
+		if _, err := execer.ExecContext(ctx, stmt, nil); err != nil {
+			return fmt.Errorf("failed to load data file: %w", err)
+		}
+	}
+	return nil
+}
+
+func quoteIdentifier(name string) string {
+	return `"` + strings.ReplaceAll(name, `"`, `""`) + `"`
+}
+
+func quoteLiteral(value string) string {
+	return "'" + strings.ReplaceAll(value, "'", "''") + "'"
+}
@@ -0,0 +1,13 @@
+//go:build !duckdb
+
+package duckdb
+
+import "errors"
+
+var errDriverNotBuilt = errors.New("this build of Grafana does not support DuckDB, it must be built with the duckdb build tag")
+
+// checkDriver returns an error if Grafana was built without the DuckDB driver. The driver requires cgo and links
+// DuckDB statically, so it is only built with the duckdb build tag.
+func checkDriver() error {
+	return errDriverNotBuilt
+}
@@ -0,0 +1,35 @@
+//go:build !duckdb
+
+package duckdb
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/grafana/grafana-plugin-sdk-go/backend"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/grafana/grafana/pkg/setting"
+)
+
+func TestCheckHealthWithoutDriver(t *testing.T) {
+	dir := t.TempDir()
+	path := filepath.Join(dir, "metric.csv")
+	require.NoError(t, os.WriteFile(path, []byte("value\n1\n"), 0600))
+
+	cfg := setting.NewCfg()
+	cfg.DuckDBDataSourceAllowedPaths = []string{dir}
+	s := ProvideService(cfg)
+
+	res, err := s.CheckHealth(context.Background(), &backend.CheckHealthRequest{
+		PluginContext: backend.PluginContext{
+			DataSourceInstanceSettings: &backend.DataSourceInstanceSettings{ID: 1, Database: path, JSONData: []byte(`{}`)},
+		},
+	})
+	require.NoError(t, err)
+	assert.Equal(t, backend.HealthStatusError, res.Status)
+	assert.Equal(t, errDriverNotBuilt.Error(), res.Message)
+}
@@ -0,0 +1,157 @@
+//go:build duckdb
+
+package duckdb
+
+import (
+	"context"
+	"database/sql"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/grafana/grafana-plugin-sdk-go/backend"
+	"github.com/grafana/grafana-plugin-sdk-go/data"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/grafana/grafana/pkg/setting"
+)
+
+const testRows = `
+	('2018-03-15 13:00:00', 'server1', 1.5),
+	('2018-03-15 13:00:00', 'server2', 3.5),
+	('2018-03-15 13:01:00', 'server2', NULL),
+	('2018-03-15 13:01:00', 'server1', 2.5)`
+
+func TestDuckDB(t *testing.T) {
+	dir := t.TempDir()
+	dbPath := filepath.Join(dir, "metrics.duckdb")
+	csvPath := filepath.Join(dir, "metric.csv")
+	parquetPath := filepath.Join(dir, "metric.parquet")
+
+	db, err := sql.Open("duckdb", dbPath)
+	require.NoError(t, err)
+	for _, stmt := range []string{
+		"CREATE TABLE metric (time TIMESTAMP, host VARCHAR, value DOUBLE)",
+		"INSERT INTO metric VALUES " + testRows,
+		"COPY metric TO '" + csvPath + "' (HEADER)",
+		"COPY metric TO '" + parquetPath + "' (FORMAT PARQUET)",
+	} {
+		_, err = db.Exec(stmt)
+		require.NoError(t, err)
+	}
+	require.NoError(t, db.Close())
+
+	cfg := setting.NewCfg()
+	cfg.DataProxyRowLimit = 1000
+	cfg.DuckDBDataSourceAllowedPaths = []string{dir}
+	s := ProvideService(cfg)
+
+	timeRange := backend.TimeRange{
+		From: time.Date(2018, 3, 15, 12, 0, 0, 0, time.UTC),
+		To:   time.Date(2018, 3, 15, 14, 0, 0, 0, time.UTC),
+	}
+
+	for i, path := range []string{dbPath, csvPath, parquetPath} {
+		pluginCtx := backend.PluginContext{
+			DataSourceInstanceSettings: &backend.DataSourceInstanceSettings{
+				ID:       int64(i + 1),
+				Database: path,
+				JSONData: []byte(`{}`),
+			},
+		}
+		query := func(t *testing.T, rawSQL, format string) backend.DataResponse {
+			t.Helper()
+			resp, err := s.QueryData(context.Background(), &backend.QueryDataRequest{
+				PluginContext: pluginCtx,
+				Queries: []backend.DataQuery{{
+					RefID:     "A",
+					JSON:      []byte(`{"rawSql": "` + rawSQL + `", "format": "` + format + `"}`),
+					TimeRange: timeRange,
+				}},
+			})
+			require.NoError(t, err)
+			return resp.Responses["A"]
+		}
+
+		t.Run(filepath.Base(path), func(t *testing.T) {
+			t.Run("should return table", func(t *testing.T) {
+				resp := query(t, "SELECT host, count(*)::DOUBLE AS c, max(value) AS max FROM metric WHERE $__timeFilter(time) GROUP BY host ORDER BY host", "table")
+				require.NoError(t, resp.Error)
+				require.Len(t, resp.Frames, 1)
+
+				frame := resp.Frames[0]
+				require.Equal(t, 2, frame.Rows())
+				require.Len(t, frame.Fields, 3)
+				assert.Equal(t, data.FieldTypeNullableString, frame.Fields[0].Type())
+				assert.Equal(t, "server1", *frame.Fields[0].At(0).(*string))
+				assert.Equal(t, data.FieldTypeNullableFloat64, frame.Fields[1].Type())
+				assert.Equal(t, 2.0, *frame.Fields[1].At(0).(*float64))
+				assert.Equal(t, 3.5, *frame.Fields[2].At(1).(*float64))
+			})
+
+			t.Run("should filter by time", func(t *testing.T) {
+				resp := query(t, "SELECT count(*)::DOUBLE AS c FROM metric WHERE time >= $__timeFrom() AND time < $__timeTo()", "table")
+				require.NoError(t, resp.Error)
+				require.Len(t, resp.Frames, 1)
+				assert.Equal(t, 4.0, *resp.Frames[0].Fields[0].At(0).(*float64))
+			})
+
+			t.Run("should return time series", func(t *testing.T) {
+				resp := query(t, "SELECT $__timeGroupAlias(time, '1m'), host AS metric, avg(value) AS value FROM metric GROUP BY 1, 2 ORDER BY 1", "time_series")
+				require.NoError(t, resp.Error)
+				require.Len(t, resp.Frames, 1)
+
+				frame := resp.Frames[0]
+				require.Equal(t, 2, frame.Rows())
+				require.Len(t, frame.Fields, 3)
+				assert.Equal(t, time.Date(2018, 3, 15, 13, 0, 0, 0, time.UTC), frame.Fields[0].At(0).(time.Time).UTC())
+				assert.Equal(t, "server1", frame.Fields[1].Name)
+				assert.Equal(t, 2.5, *frame.Fields[1].At(1).(*float64))
+				assert.Equal(t, "server2", frame.Fields[2].Name)
+				assert.Nil(t, frame.Fields[2].At(1))
+			})
+
+			t.Run("should not read other files", func(t *testing.T) {
+				resp := query(t, "SELECT * FROM read_csv_auto('"+csvPath+"')", "table")
+				require.Error(t, resp.Error)
+				require.Contains(t, resp.Error.Error(), "disabled")
+			})
+
+			t.Run("should not write files", func(t *testing.T) {
+				resp := query(t, "COPY metric TO '"+filepath.Join(dir, "out.csv")+"'", "table")
+				require.Error(t, resp.Error)
+				require.NoFileExists(t, filepath.Join(dir, "out.csv"))
+			})
+
+			t.Run("should not attach other databases", func(t *testing.T) {
+				resp := query(t, "ATTACH '"+filepath.Join(dir, "other.duckdb")+"' AS other", "table")
+				require.Error(t, resp.Error)
+				require.NoFileExists(t, filepath.Join(dir, "other.duckdb"))
+			})
+
+			t.Run("should not enable the access to files", func(t *testing.T) {
+				resp := query(t, "SET enable_external_access = true", "table")
+				require.Error(t, resp.Error)
+			})
+
+			t.Run("should check health", func(t *testing.T) {
+				res, err := s.CheckHealth(context.Background(), &backend.CheckHealthRequest{PluginContext: pluginCtx})
+				require.NoError(t, err)
+				assert.Equal(t, backend.HealthStatusOk, res.Status)
+			})
+		})
+	}
+
+	t.Run("should not write to the database file", func(t *testing.T) {
+		resp, err := s.QueryData(context.Background(), &backend.QueryDataRequest{
+			PluginContext: backend.PluginContext{
+				DataSourceInstanceSettings: &backend.DataSourceInstanceSettings{ID: 1, Database: dbPath, JSONData: []byte(`{}`)},
+			},
+			Queries: []backend.DataQuery{{RefID: "A", JSON: []byte(`{"rawSql": "CREATE TABLE other (value DOUBLE)", "format": "table"}`)}},
+		})
+		require.NoError(t, err)
+		require.Error(t, resp.Responses["A"].Error)
+		require.Contains(t, resp.Responses["A"].Error.Error(), "read-only")
+	})
+}
@@ -0,0 +1,190 @@
+package duckdb
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"github.com/grafana/grafana-plugin-sdk-go/backend"
+	"github.com/grafana/grafana-plugin-sdk-go/backend/datasource"
+	"github.com/grafana/grafana-plugin-sdk-go/backend/instancemgmt"
+	"github.com/grafana/grafana-plugin-sdk-go/data/sqlutil"
+
+	"github.com/grafana/grafana/pkg/infra/log"
+	"github.com/grafana/grafana/pkg/setting"
+	"github.com/grafana/grafana/pkg/tsdb/sqleng"
+)
+
+// driverName is the name of the DuckDB driver used by the data source. Its connections can't read, write or attach
+// other files than the one of the data source, nor install extensions.
+const driverName = "duckdb_datasource"
+
+var logger = log.New("tsdb.duckdb")
+
+var (
+	errPathNotAllowed   = fmt.Errorf("%w, see allowed_paths in the [datasources.duckdb] section of the configuration", sqleng.ErrPathNotAllowed)
+	errPathUnsupported  = errors.New("path of the database file contains unsupported characters")
+	errDataFileTooLarge = errors.New("data file is too large, see max_data_file_size_mb in the [datasources.duckdb] section of the configuration")
+)
+
+// dataFileReaders are the table functions that read the data files, by file extension. The files with other
+// extensions are opened as DuckDB database files.
+// The table functions expand glob patterns, so the paths of data files can't contain *, ?, [ or {.
+var dataFileReaders = map[string]string{
+	".csv":     "read_csv_auto",
+	".tsv":     "read_csv_auto",
+	".parquet": "read_parquet",
+}
+
+type Service struct {
+	im instancemgmt.InstanceManager
+}
+
+func ProvideService(cfg *setting.Cfg) *Service {
+	return &Service{
+		im: datasource.NewInstanceManager(newInstanceSettings(cfg)),
+	}
+}
+
+func newInstanceSettings(cfg *setting.Cfg) datasource.InstanceFactoryFunc {
+	return func(settings backend.DataSourceInstanceSettings) (instancemgmt.Instance, error) {
+		jsonData := sqleng.JsonData{
+			MaxOpenConns:    0,
+			MaxIdleConns:    2,
+			ConnMaxLifetime: 14400,
+		}
+
+		err := json.Unmarshal(settings.JSONData, &jsonData)
+		if err != nil {
+			return nil, fmt.Errorf("error reading settings: %w", err)
+		}
+		dsInfo := sqleng.DataSourceInfo{
+			JsonData:                jsonData,
+			URL:                     settings.URL,
+			User:                    settings.User,
+			Database:                settings.Database,
+			ID:                      settings.ID,
+			Updated:                 settings.Updated,
+			UID:                     settings.UID,
+			DecryptedSecureJSONData: settings.DecryptedSecureJSONData,
+		}
+
+		path, err := resolvePath(dsInfo.Database, cfg.DuckDBDataSourceAllowedPaths)
+		if err != nil {
+			return nil, err
+		}
+		if err := checkPath(path, cfg.DuckDBDataSourceMaxDataFileSize); err != nil {
+			return nil, err
+		}
+		if err := checkDriver(); err != nil {
+			return nil, err
+		}
+
+		if cfg.Env == setting.Dev {
+			logger.Debug("GetEngine", "connection", path)
+		}
+
+		config := sqleng.DataPluginConfiguration{
+			DriverName:        driverName,
+			ConnectionString:  path,
+			DSInfo:            dsInfo,
+			TimeColumnNames:   []string{"time", "time_sec"},
+			MetricColumnTypes: []string{"VARCHAR", "ENUM"},
+			RowLimit:          cfg.DataProxyRowLimit,
+		}
+
+		queryResultTransformer := duckdbQueryResultTransformer{}
+
+		return sqleng.NewQueryDataHandler(config, &queryResultTransformer, newDuckDBMacroEngine(), logger)
+	}
+}
+
+// resolvePath returns the absolute path of the database or data file if it is allowed by allowed_paths.
+func resolvePath(path string, allowedPaths []string) (string, error) {
+	resolved, err := sqleng.ResolveAllowedPath(path, allowedPaths, logger)
+	if errors.Is(err, sqleng.ErrPathNotAllowed) {
+		return "", errPathNotAllowed
+	}
+	return resolved, err
+}
+
+// checkPath checks that the database or data file can be opened by the driver. Data files are loaded in memory
+// when the data source is first queried, so their size is bounded by maxDataFileSize, unless it is 0.
+func checkPath(path string, maxDataFileSize int64) error {
+	if _, ok := dataFileReaders[strings.ToLower(filepath.Ext(path))]; !ok {
+		// The DuckDB driver reads the configuration options of the database after the first "?" of its path.
+		if strings.Contains(path, "?") {
+			return fmt.Errorf("%w: %q", errPathUnsupported, "?")
+		}
+		return nil
+	}
+
+	if i := strings.IndexAny(path, "*?[{"); i >= 0 {
+		return fmt.Errorf("%w: %q", errPathUnsupported, path[i:i+1])
+	}
+	if maxDataFileSize <= 0 {
+		return nil
+	}
+	info, err := os.Stat(path)
+	if err != nil {
+		return fmt.Errorf("failed to read data file: %w", err)
+	}
+	if info.Size() > maxDataFileSize {
+		return errDataFileTooLarge
+	}
+	return nil
+}
+
+func (s *Service) getDataSourceHandler(pluginCtx backend.PluginContext) (*sqleng.DataSourceHandler, error) {
+	i, err := s.im.Get(pluginCtx)
+	if err != nil {
+		return nil, err
+	}
+	instance := i.(*sqleng.DataSourceHandler)
+	return instance, nil
+}
+
+func (s *Service) QueryData(ctx context.Context, req *backend.QueryDataRequest) (*backend.QueryDataResponse, error) {
+	dsHandler, err := s.getDataSourceHandler(req.PluginContext)
+	if err != nil {
+		return nil, err
+	}
+	return dsHandler.QueryData(ctx, req)
+}
+
+func (s *Service) CheckHealth(ctx context.Context, req *backend.CheckHealthRequest) (*backend.CheckHealthResult, error) {
+	dsHandler, err := s.getDataSourceHandler(req.PluginContext)
+	if err != nil {
+		return &backend.CheckHealthResult{
+			Status:  backend.HealthStatusError,
+			Message: err.Error(),
+		}, nil
+	}
+
+	if err := dsHandler.Ping(ctx); err != nil {
+		logger.Error("Failed to open DuckDB database", "error", err)
+		return &backend.CheckHealthResult{
+			Status:  backend.HealthStatusError,
+			Message: "failed to open the database file - please inspect Grafana server log for details",
+		}, nil
+	}
+
+	return &backend.CheckHealthResult{
+		Status:  backend.HealthStatusOk,
+		Message: "Database Connection OK",
+	}, nil
+}
+
+type duckdbQueryResultTransformer struct{}
+
+func (t *duckdbQueryResultTransformer) TransformQueryError(_ log.Logger, err error) error {
+	return err
+}
+
+func (t *duckdbQueryResultTransformer) GetConverterList() []sqlutil.StringConverter {
+	return nil
+}
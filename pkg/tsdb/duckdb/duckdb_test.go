@@ -0,0 +1,68 @@
+package duckdb
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/grafana/grafana-plugin-sdk-go/backend"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/grafana/grafana/pkg/setting"
+	"github.com/grafana/grafana/pkg/tsdb/sqleng"
+)
+
+func TestCheckHealth(t *testing.T) {
+	dir := t.TempDir()
+	cfg := setting.NewCfg()
+	cfg.DuckDBDataSourceAllowedPaths = []string{dir}
+	cfg.DuckDBDataSourceMaxDataFileSize = 8
+	s := ProvideService(cfg)
+
+	checkHealth := func(t *testing.T, id int64, path string) *backend.CheckHealthResult {
+		t.Helper()
+		res, err := s.CheckHealth(context.Background(), &backend.CheckHealthRequest{
+			PluginContext: backend.PluginContext{
+				DataSourceInstanceSettings: &backend.DataSourceInstanceSettings{
+					ID:       id,
+					Database: path,
+					JSONData: []byte(`{}`),
+				},
+			},
+		})
+		require.NoError(t, err)
+		return res
+	}
+
+	t.Run("should fail health check when path is not allowed", func(t *testing.T) {
+		other := filepath.Join(t.TempDir(), "other.csv")
+		require.NoError(t, os.WriteFile(other, []byte("value\n1\n"), 0600))
+
+		res := checkHealth(t, 1, other)
+		assert.Equal(t, backend.HealthStatusError, res.Status)
+		assert.Equal(t, errPathNotAllowed.Error(), res.Message)
+		assert.ErrorIs(t, errPathNotAllowed, sqleng.ErrPathNotAllowed)
+	})
+
+	t.Run("should fail health check when data file is too large", func(t *testing.T) {
+		path := filepath.Join(dir, "large.csv")
+		require.NoError(t, os.WriteFile(path, []byte("value\n1\n2\n3\n"), 0600))
+
+		res := checkHealth(t, 2, path)
+		assert.Equal(t, backend.HealthStatusError, res.Status)
+		assert.Equal(t, errDataFileTooLarge.Error(), res.Message)
+	})
+
+	t.Run("should fail health check when path contains unsupported characters", func(t *testing.T) {
+		for i, name := range []string{"metric?.duckdb", "metric[1].csv", "metric*.parquet"} {
+			path := filepath.Join(dir, name)
+			require.NoError(t, os.WriteFile(path, nil, 0600))
+
+			res := checkHealth(t, int64(i+3), path)
+			assert.Equal(t, backend.HealthStatusError, res.Status)
+			assert.Contains(t, res.Message, errPathUnsupported.Error())
+		}
+	})
+}
@@ -0,0 +1,116 @@
+package duckdb
+
+import (
+	"fmt"
+	"testing"
+	"time"
+
+	"github.com/grafana/grafana-plugin-sdk-go/backend"
+	"github.com/stretchr/testify/require"
+)
+
+func TestMacroEngine(t *testing.T) {
+	engine := newDuckDBMacroEngine()
+	query := &backend.DataQuery{}
+
+	t.Run("Given a time range between 2018-04-12 00:00 and 2018-04-12 00:05", func(t *testing.T) {
+		from := time.Date(2018, 4, 12, 18, 0, 0, 0, time.UTC)
+		to := from.Add(5 * time.Minute)
+		timeRange := backend.TimeRange{From: from, To: to}
+
+		t.Run("interpolate __time function", func(t *testing.T) {
+			sql, err := engine.Interpolate(query, timeRange, "select $__time(time_column)")
+			require.Nil(t, err)
+
+			require.Equal(t, "select time_column AS \"time\"", sql)
+		})
+
+		t.Run("interpolate __timeEpoch function", func(t *testing.T) {
+			sql, err := engine.Interpolate(query, timeRange, "select $__timeEpoch(time_column)")
+			require.Nil(t, err)
+
+			require.Equal(t, "select epoch(time_column) AS \"time\"", sql)
+		})
+
+		t.Run("interpolate __timeGroup function", func(t *testing.T) {
+			sql, err := engine.Interpolate(query, timeRange, "GROUP BY $__timeGroup(time_column,'5m')")
+			require.Nil(t, err)
+			sql2, err := engine.Interpolate(query, timeRange, "GROUP BY $__timeGroupAlias(time_column,'5m')")
+			require.Nil(t, err)
+
+			require.Equal(t, "GROUP BY floor(epoch(time_column)/300)*300", sql)
+			require.Equal(t, sql+" AS \"time\"", sql2)
+		})
+
+		t.Run("interpolate __timeGroup function with spaces around arguments", func(t *testing.T) {
+			sql, err := engine.Interpolate(query, timeRange, "GROUP BY $__timeGroup(time_column , '5m')")
+			require.Nil(t, err)
+
+			require.Equal(t, "GROUP BY floor(epoch(time_column)/300)*300", sql)
+		})
+
+		t.Run("interpolate __timeGroup function with fill", func(t *testing.T) {
+			query := &backend.DataQuery{JSON: []byte("{}")}
+			sql, err := engine.Interpolate(query, timeRange, "GROUP BY $__timeGroup(time_column,'5m', NULL)")
+			require.Nil(t, err)
+
+			require.Equal(t, "GROUP BY floor(epoch(time_column)/300)*300", sql)
+			require.JSONEq(t, `{"fill": true, "fillInterval": 300, "fillMode": "null"}`, string(query.JSON))
+		})
+
+		t.Run("interpolate __timeFilter function", func(t *testing.T) {
+			sql, err := engine.Interpolate(query, timeRange, "WHERE $__timeFilter(time_column)")
+			require.Nil(t, err)
+
+			require.Equal(t, fmt.Sprintf("WHERE time_column BETWEEN '%s'::TIMESTAMPTZ AND '%s'::TIMESTAMPTZ", from.Format(time.RFC3339), to.Format(time.RFC3339)), sql)
+		})
+
+		t.Run("interpolate __timeFrom function", func(t *testing.T) {
+			sql, err := engine.Interpolate(query, timeRange, "select $__timeFrom()")
+			require.Nil(t, err)
+
+			require.Equal(t, fmt.Sprintf("select '%s'::TIMESTAMPTZ", from.Format(time.RFC3339)), sql)
+		})
+
+		t.Run("interpolate __timeTo function", func(t *testing.T) {
+			sql, err := engine.Interpolate(query, timeRange, "select $__timeTo()")
+			require.Nil(t, err)
+
+			require.Equal(t, fmt.Sprintf("select '%s'::TIMESTAMPTZ", to.Format(time.RFC3339)), sql)
+		})
+
+		t.Run("interpolate __unixEpochFilter function", func(t *testing.T) {
+			sql, err := engine.Interpolate(query, timeRange, "select $__unixEpochFilter(time)")
+			require.Nil(t, err)
+
+			require.Equal(t, fmt.Sprintf("select time >= %d AND time <= %d", from.Unix(), to.Unix()), sql)
+		})
+
+		t.Run("interpolate __unixEpochNanoFilter function", func(t *testing.T) {
+			sql, err := engine.Interpolate(query, timeRange, "select $__unixEpochNanoFilter(time)")
+			require.Nil(t, err)
+
+			require.Equal(t, fmt.Sprintf("select time >= %d AND time <= %d", from.UnixNano(), to.UnixNano()), sql)
+		})
+
+		t.Run("interpolate __unixEpochGroup function", func(t *testing.T) {
+			sql, err := engine.Interpolate(query, timeRange, "SELECT $__unixEpochGroup(time_column,'5m')")
+			require.Nil(t, err)
+			sql2, err := engine.Interpolate(query, timeRange, "SELECT $__unixEpochGroupAlias(time_column,'5m')")
+			require.Nil(t, err)
+
+			require.Equal(t, "SELECT floor((time_column)/300)*300", sql)
+			require.Equal(t, sql+" AS \"time\"", sql2)
+		})
+	})
+
+	t.Run("Given a macro with missing arguments", func(t *testing.T) {
+		_, err := engine.Interpolate(query, backend.TimeRange{}, "select $__timeGroup(time_column)")
+		require.EqualError(t, err, "macro __timeGroup needs time column and interval and optional fill value")
+	})
+
+	t.Run("Given an unknown macro", func(t *testing.T) {
+		_, err := engine.Interpolate(query, backend.TimeRange{}, "select $__unknown(time_column)")
+		require.EqualError(t, err, "unknown macro __unknown")
+	})
+}
@@ -0,0 +1,303 @@
+package graphite
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"path"
+	"regexp"
+	"strings"
+
+	"github.com/grafana/grafana-plugin-sdk-go/backend/resource/httpadapter"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/codes"
+)
+
+// functionsInfinityDefault matches the default values of function parameters that Graphite 1.1.7 returns as
+// Infinity, which is not valid JSON. See https://github.com/graphite-project/graphite-web/issues/2609
+var functionsInfinityDefault = regexp.MustCompile(`"default": ?Infinity`)
+
+func (s *Service) newResourceMux() *http.ServeMux {
+	mux := http.NewServeMux()
+	mux.HandleFunc("/metrics/find", s.handleMetricsFind)
+	mux.HandleFunc("/metrics/expand", s.handleMetricsExpand)
+	mux.HandleFunc("/tags/autoComplete/tags", s.handleTagsAutoComplete)
+	mux.HandleFunc("/tags/autoComplete/values", s.handleTagValuesAutoComplete)
+	mux.HandleFunc("/functions", s.handleFunctions)
+	return mux
+}
+
+// metricFindResult is a node of the metrics tree returned by the metrics/find endpoint.
+type metricFindResult struct {
+	Text       string `json:"text"`
+	ID         string `json:"id"`
+	Expandable bool   `json:"expandable"`
+}
+
+// metricFindResponseDTO is a node of the metrics tree as returned by Graphite. graphite-web returns
+// the expandable and leaf flags as 0 or 1, other implementations as booleans.
+type metricFindResponseDTO struct {
+	Text       string      `json:"text"`
+	ID         string      `json:"id"`
+	Expandable interface{} `json:"expandable"`
+}
+
+type metricExpandResponseDTO struct {
+	Results []string `json:"results"`
+}
+
+func (s *Service) handleMetricsFind(rw http.ResponseWriter, req *http.Request) {
+	params, err := resourceParams(req, "query", "from", "until")
+	if err != nil {
+		writeErrorResponse(rw, http.StatusBadRequest, err)
+		return
+	}
+	if params.Get("query") == "" {
+		writeErrorResponse(rw, http.StatusBadRequest, fmt.Errorf("query is required"))
+		return
+	}
+
+	var nodes []metricFindResponseDTO
+	if code, err := s.doResourceRequest(req, http.MethodPost, "metrics/find", params, &nodes); err != nil {
+		writeErrorResponse(rw, code, err)
+		return
+	}
+
+	results := make([]metricFindResult, 0, len(nodes))
+	for _, node := range nodes {
+		results = append(results, metricFindResult{
+			Text:       node.Text,
+			ID:         node.ID,
+			Expandable: isTrue(node.Expandable),
+		})
+	}
+	writeJSONResponse(rw, results)
+}
+
+func (s *Service) handleMetricsExpand(rw http.ResponseWriter, req *http.Request) {
+	params, err := resourceParams(req, "query", "from", "until")
+	if err != nil {
+		writeErrorResponse(rw, http.StatusBadRequest, err)
+		return
+	}
+	if params.Get("query") == "" {
+		writeErrorResponse(rw, http.StatusBadRequest, fmt.Errorf("query is required"))
+		return
+	}
+
+	var expanded metricExpandResponseDTO
+	if code, err := s.doResourceRequest(req, http.MethodGet, "metrics/expand", params, &expanded); err != nil {
+		writeErrorResponse(rw, code, err)
+		return
+	}
+
+	results := make([]metricFindResult, 0, len(expanded.Results))
+	for _, metric := range expanded.Results {
+		results = append(results, metricFindResult{Text: metric, ID: metric})
+	}
+	writeJSONResponse(rw, results)
+}
+
+func (s *Service) handleTagsAutoComplete(rw http.ResponseWriter, req *http.Request) {
+	params, err := resourceParams(req, "expr", "tagPrefix", "limit", "from", "until")
+	if err != nil {
+		writeErrorResponse(rw, http.StatusBadRequest, err)
+		return
+	}
+
+	tags := []string{}
+	if code, err := s.doResourceRequest(req, http.MethodGet, "tags/autoComplete/tags", params, &tags); err != nil {
+		writeErrorResponse(rw, code, err)
+		return
+	}
+	writeJSONResponse(rw, tags)
+}
+
+func (s *Service) handleTagValuesAutoComplete(rw http.ResponseWriter, req *http.Request) {
+	params, err := resourceParams(req, "expr", "tag", "valuePrefix", "limit", "from", "until")
+	if err != nil {
+		writeErrorResponse(rw, http.StatusBadRequest, err)
+		return
+	}
+	if params.Get("tag") == "" {
+		writeErrorResponse(rw, http.StatusBadRequest, fmt.Errorf("tag is required"))
+		return
+	}
+
+	values := []string{}
+	if code, err := s.doResourceRequest(req, http.MethodGet, "tags/autoComplete/values", params, &values); err != nil {
+		writeErrorResponse(rw, code, err)
+		return
+	}
+	writeJSONResponse(rw, values)
+}
+
+// handleFunctions returns the function definitions of Graphite as they are, apart from default values of
+// Infinity, which are replaced by a number that JSON parsers read as Infinity.
+func (s *Service) handleFunctions(rw http.ResponseWriter, req *http.Request) {
+	body, code, err := s.resourceRequest(req, http.MethodGet, "functions", url.Values{})
+	if err != nil {
+		writeErrorResponse(rw, code, err)
+		return
+	}
+
+	body = functionsInfinityDefault.ReplaceAll(body, []byte(`"default": 1e9999`))
+	if !json.Valid(body) {
+		writeErrorResponse(rw, http.StatusBadGateway, fmt.Errorf("invalid function definitions returned by Graphite"))
+		return
+	}
+	rw.Header().Set("Content-Type", "application/json")
+	writeResponseBytes(rw, http.StatusOK, body)
+}
+
+// resourceParams returns the parameters of the resource request, from its URL or its form encoded body,
+// that are passed on to Graphite.
+func resourceParams(req *http.Request, names ...string) (url.Values, error) {
+	if err := req.ParseForm(); err != nil {
+		return nil, fmt.Errorf("failed to parse request parameters: %w", err)
+	}
+
+	params := url.Values{}
+	for _, name := range names {
+		for _, v := range req.Form[name] {
+			if v != "" {
+				params.Add(name, v)
+			}
+		}
+	}
+	return params, nil
+}
+
+// doResourceRequest sends the request to the endpoint of Graphite and decodes its JSON response into v. It
+// returns the status code of the response for the resource request when the request fails.
+func (s *Service) doResourceRequest(req *http.Request, method string, endpoint string, params url.Values, v interface{}) (int, error) {
+	body, code, err := s.resourceRequest(req, method, endpoint, params)
+	if err != nil {
+		return code, err
+	}
+	if err := json.Unmarshal(body, v); err != nil {
+		logger.Info("Failed to unmarshal graphite response", "endpoint", endpoint, "error", err, "body", string(body))
+		return http.StatusBadGateway, fmt.Errorf("invalid response returned by Graphite: %w", err)
+	}
+	return http.StatusOK, nil
+}
+
+func (s *Service) resourceRequest(req *http.Request, method string, endpoint string, params url.Values) ([]byte, int, error) {
+	ctx := req.Context()
+	logger := logger.FromContext(ctx)
+
+	dsInfo, err := s.getDSInfo(httpadapter.PluginConfigFromContext(ctx))
+	if err != nil {
+		return nil, http.StatusInternalServerError, err
+	}
+
+	ctx, span := s.tracer.Start(ctx, "graphite resource")
+	defer span.End()
+	span.SetAttributes("endpoint", endpoint, attribute.Key("endpoint").String(endpoint))
+	span.SetAttributes("datasource_id", dsInfo.Id, attribute.Key("datasource_id").Int64(dsInfo.Id))
+
+	graphiteReq, err := createResourceRequest(ctx, dsInfo, method, endpoint, params)
+	if err != nil {
+		return nil, http.StatusInternalServerError, err
+	}
+	s.tracer.Inject(ctx, graphiteReq.Header, span)
+
+	res, err := dsInfo.HTTPClient.Do(graphiteReq)
+	if res != nil {
+		span.SetAttributes("graphite.response.code", res.StatusCode, attribute.Key("graphite.response.code").Int(res.StatusCode))
+	}
+	if err != nil {
+		span.RecordError(err)
+		span.SetStatus(codes.Error, err.Error())
+		return nil, http.StatusBadGateway, err
+	}
+	defer func() {
+		if err := res.Body.Close(); err != nil {
+			logger.Warn("Failed to close response body", "err", err)
+		}
+	}()
+
+	body, err := io.ReadAll(res.Body)
+	if err != nil {
+		return nil, http.StatusBadGateway, err
+	}
+	if res.StatusCode/100 != 2 {
+		logger.Info("Request failed", "endpoint", endpoint, "status", res.Status, "body", string(body))
+		return nil, resourceErrorStatus(res.StatusCode), fmt.Errorf("request failed, status: %s", res.Status)
+	}
+	return body, http.StatusOK, nil
+}
+
+// resourceErrorStatus returns the status of the resource response for a failed request to Graphite. Authentication
+// errors of Graphite are returned as 502, as the frontend would otherwise take them for an expired Grafana session,
+// and so are server errors.
+func resourceErrorStatus(code int) int {
+	if code/100 == 4 && code != http.StatusUnauthorized && code != http.StatusForbidden {
+		return code
+	}
+	return http.StatusBadGateway
+}
+
+// createResourceRequest creates the request to the endpoint of Graphite. The parameters of POST requests are
+// sent in a form encoded body, so that long queries don't exceed the length limits of URLs.
+func createResourceRequest(ctx context.Context, dsInfo *datasourceInfo, method string, endpoint string, params url.Values) (*http.Request, error) {
+	u, err := url.Parse(dsInfo.URL)
+	if err != nil {
+		return nil, err
+	}
+	u.Path = path.Join(u.Path, endpoint)
+
+	if method != http.MethodPost {
+		u.RawQuery = params.Encode()
+		req, err := http.NewRequestWithContext(ctx, method, u.String(), nil)
+		if err != nil {
+			return nil, fmt.Errorf("failed to create request: %w", err)
+		}
+		return req, nil
+	}
+
+	req, err := http.NewRequestWithContext(ctx, method, u.String(), strings.NewReader(params.Encode()))
+	if err != nil {
+		return nil, fmt.Errorf("failed to create request: %w", err)
+	}
+	req.Header.Set("Content-Type", "application/x-www-form-urlencoded")
+	return req, nil
+}
+
+func isTrue(v interface{}) bool {
+	switch v := v.(type) {
+	case bool:
+		return v
+	case float64:
+		return v != 0
+	case string:
+		return v == "1" || v == "true"
+	}
+	return false
+}
+
+func writeJSONResponse(rw http.ResponseWriter, v interface{}) {
+	body, err := json.Marshal(v)
+	if err != nil {
+		writeErrorResponse(rw, http.StatusInternalServerError, err)
+		return
+	}
+	rw.Header().Set("Content-Type", "application/json")
+	writeResponseBytes(rw, http.StatusOK, body)
+}
+
+func writeErrorResponse(rw http.ResponseWriter, code int, err error) {
+	body, _ := json.Marshal(map[string]string{"message": err.Error()})
+	rw.Header().Set("Content-Type", "application/json")
+	writeResponseBytes(rw, code, body)
+}
+
+func writeResponseBytes(rw http.ResponseWriter, code int, body []byte) {
+	rw.WriteHeader(code)
+	if _, err := rw.Write(body); err != nil {
+		logger.Error("Unable to write HTTP response", "error", err)
+	}
+}
@@ -0,0 +1,187 @@
+package graphite
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"strings"
+	"testing"
+
+	"github.com/grafana/grafana-plugin-sdk-go/backend"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/grafana/grafana/pkg/infra/httpclient"
+	"github.com/grafana/grafana/pkg/infra/tracing"
+)
+
+func TestCallResource(t *testing.T) {
+	var requests []*http.Request
+	var forms []url.Values
+	graphite := httptest.NewServer(http.HandlerFunc(func(rw http.ResponseWriter, req *http.Request) {
+		require.NoError(t, req.ParseForm())
+		requests = append(requests, req)
+		forms = append(forms, req.Form)
+
+		if user, password, ok := req.BasicAuth(); !ok || user != "user" || password != "password" {
+			rw.WriteHeader(http.StatusUnauthorized)
+			return
+		}
+
+		switch req.URL.Path {
+		case "/graphite/metrics/find":
+			_, _ = rw.Write([]byte(`[
+				{"text": "cpu", "id": "servers.a.cpu", "leaf": 0, "expandable": 1, "allowChildren": 1},
+				{"text": "load", "id": "servers.a.load", "leaf": 1, "expandable": 0, "allowChildren": 0}
+			]`))
+		case "/graphite/metrics/expand":
+			_, _ = rw.Write([]byte(`{"results": ["servers.a.cpu", "servers.b.cpu"]}`))
+		case "/graphite/tags/autoComplete/tags":
+			_, _ = rw.Write([]byte(`["dc", "host"]`))
+		case "/graphite/tags/autoComplete/values":
+			_, _ = rw.Write([]byte(`["server-1", "server-2"]`))
+		case "/graphite/functions":
+			_, _ = rw.Write([]byte(`{"movingWindow": {"name": "movingWindow", "params": [{"name": "xFilesFactor", "type": "float", "default": Infinity}]}}`))
+		default:
+			rw.WriteHeader(http.StatusNotFound)
+			_, _ = rw.Write([]byte("not found"))
+		}
+	}))
+	t.Cleanup(graphite.Close)
+
+	service := ProvideService(httpclient.NewProvider(), tracing.InitializeTracerForTest())
+	pluginCtx := backend.PluginContext{
+		DataSourceInstanceSettings: &backend.DataSourceInstanceSettings{
+			ID:                      1,
+			URL:                     graphite.URL + "/graphite",
+			BasicAuthEnabled:        true,
+			BasicAuthUser:           "user",
+			DecryptedSecureJSONData: map[string]string{"basicAuthPassword": "password"},
+		},
+	}
+
+	callResource := func(t *testing.T, method string, path string, body string) *backend.CallResourceResponse {
+		t.Helper()
+		requests, forms = nil, nil
+
+		req := &backend.CallResourceRequest{
+			PluginContext: pluginCtx,
+			Method:        method,
+			Path:          strings.SplitN(path, "?", 2)[0],
+			URL:           path,
+			Body:          []byte(body),
+		}
+		if body != "" {
+			req.Headers = map[string][]string{"Content-Type": {"application/x-www-form-urlencoded"}}
+		}
+
+		sender := &fakeCallResourceResponseSender{}
+		require.NoError(t, service.CallResource(context.Background(), req, sender))
+		require.NotNil(t, sender.response)
+		return sender.response
+	}
+
+	t.Run("should find metrics", func(t *testing.T) {
+		resp := callResource(t, http.MethodPost, "metrics/find?from=1000&until=2000", "query=servers.a.*")
+		require.Equal(t, http.StatusOK, resp.Status, string(resp.Body))
+
+		var results []metricFindResult
+		require.NoError(t, json.Unmarshal(resp.Body, &results))
+		assert.Equal(t, []metricFindResult{
+			{Text: "cpu", ID: "servers.a.cpu", Expandable: true},
+			{Text: "load", ID: "servers.a.load", Expandable: false},
+		}, results)
+
+		require.Len(t, requests, 1)
+		assert.Equal(t, http.MethodPost, requests[0].Method)
+		assert.Equal(t, "servers.a.*", forms[0].Get("query"))
+		assert.Equal(t, "1000", forms[0].Get("from"))
+		assert.Equal(t, "2000", forms[0].Get("until"))
+	})
+
+	t.Run("should require query to find metrics", func(t *testing.T) {
+		resp := callResource(t, http.MethodGet, "metrics/find", "")
+		require.Equal(t, http.StatusBadRequest, resp.Status)
+		assert.JSONEq(t, `{"message": "query is required"}`, string(resp.Body))
+		assert.Empty(t, requests)
+	})
+
+	t.Run("should expand metrics", func(t *testing.T) {
+		resp := callResource(t, http.MethodGet, "metrics/expand?query=servers.*.cpu", "")
+		require.Equal(t, http.StatusOK, resp.Status, string(resp.Body))
+		assert.JSONEq(t, `[
+			{"text": "servers.a.cpu", "id": "servers.a.cpu", "expandable": false},
+			{"text": "servers.b.cpu", "id": "servers.b.cpu", "expandable": false}
+		]`, string(resp.Body))
+	})
+
+	t.Run("should auto complete tags", func(t *testing.T) {
+		resp := callResource(t, http.MethodGet, "tags/autoComplete/tags?expr=name%3Dcpu&expr=dc%3Deu&tagPrefix=h&limit=10", "")
+		require.Equal(t, http.StatusOK, resp.Status, string(resp.Body))
+		assert.JSONEq(t, `["dc", "host"]`, string(resp.Body))
+
+		require.Len(t, requests, 1)
+		assert.Equal(t, http.MethodGet, requests[0].Method)
+		assert.Equal(t, []string{"name=cpu", "dc=eu"}, forms[0]["expr"])
+		assert.Equal(t, "h", forms[0].Get("tagPrefix"))
+		assert.Equal(t, "10", forms[0].Get("limit"))
+	})
+
+	t.Run("should auto complete tag values", func(t *testing.T) {
+		resp := callResource(t, http.MethodGet, "tags/autoComplete/values?expr=name%3Dcpu&tag=host&valuePrefix=server", "")
+		require.Equal(t, http.StatusOK, resp.Status, string(resp.Body))
+		assert.JSONEq(t, `["server-1", "server-2"]`, string(resp.Body))
+		assert.Equal(t, "host", forms[0].Get("tag"))
+		assert.Equal(t, "server", forms[0].Get("valuePrefix"))
+	})
+
+	t.Run("should require tag to auto complete tag values", func(t *testing.T) {
+		resp := callResource(t, http.MethodGet, "tags/autoComplete/values?expr=name%3Dcpu", "")
+		require.Equal(t, http.StatusBadRequest, resp.Status)
+		assert.Empty(t, requests)
+	})
+
+	t.Run("should return functions with valid JSON", func(t *testing.T) {
+		resp := callResource(t, http.MethodGet, "functions", "")
+		require.Equal(t, http.StatusOK, resp.Status, string(resp.Body))
+		assert.Contains(t, string(resp.Body), `"default": 1e9999`)
+	})
+
+	t.Run("should return status of failed Graphite request", func(t *testing.T) {
+		pluginCtx.DataSourceInstanceSettings.URL = graphite.URL + "/other"
+		pluginCtx.DataSourceInstanceSettings.ID = 2
+		t.Cleanup(func() {
+			pluginCtx.DataSourceInstanceSettings.URL = graphite.URL + "/graphite"
+			pluginCtx.DataSourceInstanceSettings.ID = 1
+		})
+
+		resp := callResource(t, http.MethodGet, "tags/autoComplete/tags", "")
+		require.Equal(t, http.StatusNotFound, resp.Status)
+		assert.JSONEq(t, fmt.Sprintf(`{"message": %q}`, "request failed, status: 404 Not Found"), string(resp.Body))
+	})
+
+	t.Run("should not return authentication errors of Graphite", func(t *testing.T) {
+		pluginCtx.DataSourceInstanceSettings.DecryptedSecureJSONData = map[string]string{"basicAuthPassword": "wrong"}
+		pluginCtx.DataSourceInstanceSettings.ID = 3
+		t.Cleanup(func() {
+			pluginCtx.DataSourceInstanceSettings.DecryptedSecureJSONData = map[string]string{"basicAuthPassword": "password"}
+			pluginCtx.DataSourceInstanceSettings.ID = 1
+		})
+
+		resp := callResource(t, http.MethodGet, "tags/autoComplete/tags", "")
+		require.Equal(t, http.StatusBadGateway, resp.Status)
+		assert.JSONEq(t, fmt.Sprintf(`{"message": %q}`, "request failed, status: 401 Unauthorized"), string(resp.Body))
+	})
+}
+
+type fakeCallResourceResponseSender struct {
+	response *backend.CallResourceResponse
+}
+
+func (s *fakeCallResourceResponseSender) Send(resp *backend.CallResourceResponse) error {
+	s.response = resp
+	return nil
+}
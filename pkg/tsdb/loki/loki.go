@@ -49,8 +49,9 @@ var (
 )
 
 type datasourceInfo struct {
-	HTTPClient *http.Client
-	URL        string
+	HTTPClient     *http.Client
+	URL            string
+	querySplitting querySplitting
 
 	// open streams
 	streams   map[string]data.FrameJSONCache
@@ -87,10 +88,16 @@ func newInstanceSettings(httpClientProvider httpclient.Provider) datasource.Inst
 			return nil, err
 		}
 
+		splitting, err := parseQuerySplitting(settings.JSONData)
+		if err != nil {
+			return nil, err
+		}
+
 		model := &datasourceInfo{
-			HTTPClient: client,
-			URL:        settings.URL,
-			streams:    make(map[string]data.FrameJSONCache),
+			HTTPClient:     client,
+			URL:            settings.URL,
+			querySplitting: splitting,
+			streams:        make(map[string]data.FrameJSONCache),
 		}
 		return model, nil
 	}
@@ -207,7 +214,7 @@ func queryData(ctx context.Context, req *backend.QueryDataRequest, dsInfo *datas
 		logger := logger.FromContext(ctx) // get logger with trace-id and other contextual info
 		logger.Debug("Sending query", "start", query.Start, "end", query.End, "step", query.Step, "query", query.Expr)
 
-		frames, err := runQuery(ctx, api, query)
+		frames, err := runSplitQuery(ctx, api, query, dsInfo.querySplitting)
 
 		span.End()
 		queryRes := backend.DataResponse{}
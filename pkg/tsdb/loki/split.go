@@ -0,0 +1,263 @@
+package loki
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"sort"
+	"time"
+
+	"github.com/grafana/grafana-plugin-sdk-go/backend/gtime"
+	"github.com/grafana/grafana-plugin-sdk-go/data"
+	"golang.org/x/sync/errgroup"
+)
+
+const defaultQuerySplitConcurrency = 4
+
+// querySplitting are the settings of the data source for splitting long range queries
+// into queries of shorter time ranges.
+type querySplitting struct {
+	// Duration is the longest time range of a query sent to Loki. Queries are not split when it is zero.
+	Duration time.Duration
+	// Concurrency is the number of split queries that are sent to Loki at the same time.
+	Concurrency int
+}
+
+type querySplittingJSONModel struct {
+	QuerySplitDuration    string `json:"querySplitDuration"`
+	QuerySplitConcurrency int    `json:"querySplitConcurrency"`
+}
+
+func parseQuerySplitting(raw json.RawMessage) (querySplitting, error) {
+	splitting := querySplitting{Concurrency: defaultQuerySplitConcurrency}
+	if len(raw) == 0 {
+		return splitting, nil
+	}
+
+	model := querySplittingJSONModel{}
+	if err := json.Unmarshal(raw, &model); err != nil {
+		return splitting, fmt.Errorf("error reading settings: %w", err)
+	}
+	if model.QuerySplitDuration != "" {
+		duration, err := gtime.ParseDuration(model.QuerySplitDuration)
+		if err != nil {
+			return splitting, fmt.Errorf("invalid query split duration: %w", err)
+		}
+		splitting.Duration = duration
+	}
+	if model.QuerySplitConcurrency > 0 {
+		splitting.Concurrency = model.QuerySplitConcurrency
+	}
+	return splitting, nil
+}
+
+// splitQuery splits the time range of a range query into consecutive time ranges of at most the split
+// duration, rounded up to a multiple of the step, so that metric queries are evaluated at the same times
+// as the whole query. Each time range ends one nanosecond before the next one starts, so that no log line
+// is returned twice.
+func splitQuery(query *lokiQuery, duration time.Duration) []*lokiQuery {
+	if query.QueryType != QueryTypeRange || duration <= 0 || query.End.Sub(query.Start) <= duration {
+		return []*lokiQuery{query}
+	}
+	if query.Step > 0 && duration%query.Step != 0 {
+		duration = (duration/query.Step + 1) * query.Step
+	}
+
+	queries := []*lokiQuery{}
+	for start := query.Start; ; start = start.Add(duration) {
+		q := *query
+		q.Start = start
+		end := start.Add(duration)
+		if !end.Before(query.End) {
+			q.End = query.End
+			queries = append(queries, &q)
+			return queries
+		}
+		q.End = end.Add(-time.Nanosecond)
+		queries = append(queries, &q)
+	}
+}
+
+// runSplitQuery runs the split queries of a range query, with the concurrency of the data source, and
+// merges their frames. The split queries run in the direction of the query, and no more of them run once
+// the log lines they returned reach the line limit.
+func runSplitQuery(ctx context.Context, api *LokiAPI, query *lokiQuery, splitting querySplitting) (data.Frames, error) {
+	queries := splitQuery(query, splitting.Duration)
+	if len(queries) == 1 {
+		return runQuery(ctx, api, query)
+	}
+
+	if query.Direction == DirectionBackward {
+		for i, j := 0, len(queries)-1; i < j; i, j = i+1, j-1 {
+			queries[i], queries[j] = queries[j], queries[i]
+		}
+	}
+
+	concurrency := splitting.Concurrency
+	if concurrency <= 0 {
+		concurrency = defaultQuerySplitConcurrency
+	}
+
+	results := make([]data.Frames, 0, len(queries))
+	for start := 0; start < len(queries); start += concurrency {
+		end := start + concurrency
+		if end > len(queries) {
+			end = len(queries)
+		}
+
+		batch := make([]data.Frames, end-start)
+		g, gctx := errgroup.WithContext(ctx)
+		for i, q := range queries[start:end] {
+			i, q := i, q
+			g.Go(func() error {
+				frames, err := api.DataQuery(gctx, *q)
+				if err != nil {
+					return err
+				}
+				batch[i] = frames
+				return nil
+			})
+		}
+		if err := g.Wait(); err != nil {
+			return data.Frames{}, err
+		}
+		results = append(results, batch...)
+
+		if query.MaxLines > 0 && countLogLines(results) >= query.MaxLines {
+			break
+		}
+	}
+
+	frames := mergeFrames(results, query)
+	for _, frame := range frames {
+		if err := adjustFrame(frame, query); err != nil {
+			return data.Frames{}, err
+		}
+	}
+	return frames, nil
+}
+
+func isMetricFrame(frame *data.Frame) bool {
+	return len(frame.Fields) >= 2 && frame.Fields[1].Type() == data.FieldTypeFloat64
+}
+
+func countLogLines(results []data.Frames) int {
+	count := 0
+	for _, frames := range results {
+		for _, frame := range frames {
+			if !isMetricFrame(frame) {
+				count += frame.Rows()
+			}
+		}
+	}
+	return count
+}
+
+// mergeFrames merges the frames of the split queries, that are in the order the split queries ran.
+func mergeFrames(results []data.Frames, query *lokiQuery) data.Frames {
+	for _, frames := range results {
+		if len(frames) == 0 {
+			continue
+		}
+		if isMetricFrame(frames[0]) {
+			if query.Direction == DirectionBackward {
+				reversed := make([]data.Frames, len(results))
+				for i, frames := range results {
+					reversed[len(results)-1-i] = frames
+				}
+				results = reversed
+			}
+			return mergeMetricFrames(results)
+		}
+		return mergeLogsFrames(results, query)
+	}
+	return data.Frames{}
+}
+
+// mergeMetricFrames appends the values of the frames of the same series, that are in time order.
+// Values of a series at the same time are summed.
+func mergeMetricFrames(results []data.Frames) data.Frames {
+	merged := data.Frames{}
+	series := make(map[string]*data.Frame)
+
+	for _, frames := range results {
+		for _, frame := range frames {
+			if len(frame.Fields) != 2 {
+				merged = append(merged, frame)
+				continue
+			}
+			key := frame.Fields[1].Labels.String()
+			m, ok := series[key]
+			if !ok {
+				series[key] = frame
+				merged = append(merged, frame)
+				continue
+			}
+
+			timeField, valueField := m.Fields[0], m.Fields[1]
+			for i := 0; i < frame.Rows(); i++ {
+				t, ok := frame.Fields[0].At(i).(time.Time)
+				if !ok {
+					continue
+				}
+				v, ok := frame.Fields[1].At(i).(float64)
+				if !ok {
+					continue
+				}
+				last := timeField.Len() - 1
+				if last >= 0 && timeField.At(last).(time.Time).Equal(t) {
+					valueField.Set(last, valueField.At(last).(float64)+v)
+					continue
+				}
+				timeField.Append(t)
+				valueField.Append(v)
+			}
+		}
+	}
+	return merged
+}
+
+// mergeLogsFrames appends the log lines of the frames, in the direction of the query, up to the line limit.
+func mergeLogsFrames(results []data.Frames, query *lokiQuery) data.Frames {
+	var merged *data.Frame
+	for _, frames := range results {
+		for _, frame := range frames {
+			if merged == nil {
+				merged = frame.EmptyCopy()
+				merged.Meta = frame.Meta
+			}
+			for _, i := range sortedLogRows(frame, query.Direction) {
+				if query.MaxLines > 0 && merged.Rows() >= query.MaxLines {
+					return data.Frames{merged}
+				}
+				merged.AppendRow(frame.RowCopy(i)...)
+			}
+		}
+	}
+	if merged == nil {
+		return data.Frames{}
+	}
+	return data.Frames{merged}
+}
+
+// sortedLogRows returns the rows of the logs frame, sorted by time in the direction of the query.
+func sortedLogRows(frame *data.Frame, direction Direction) []int {
+	rows := make([]int, frame.Rows())
+	for i := range rows {
+		rows[i] = i
+	}
+	if len(frame.Fields) < 2 || frame.Fields[1].Type() != data.FieldTypeTime {
+		return rows
+	}
+
+	timeField := frame.Fields[1]
+	sort.SliceStable(rows, func(i, j int) bool {
+		ti := timeField.At(rows[i]).(time.Time)
+		tj := timeField.At(rows[j]).(time.Time)
+		if direction == DirectionBackward {
+			return ti.After(tj)
+		}
+		return ti.Before(tj)
+	})
+	return rows
+}
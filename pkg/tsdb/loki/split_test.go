@@ -0,0 +1,225 @@
+package loki
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"sort"
+	"strconv"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/grafana/grafana/pkg/infra/log"
+)
+
+func TestParseQuerySplitting(t *testing.T) {
+	splitting, err := parseQuerySplitting(nil)
+	require.NoError(t, err)
+	require.Equal(t, querySplitting{Concurrency: defaultQuerySplitConcurrency}, splitting)
+
+	splitting, err = parseQuerySplitting([]byte(`{"maxLines": "1000", "querySplitDuration": "1d", "querySplitConcurrency": 2}`))
+	require.NoError(t, err)
+	require.Equal(t, querySplitting{Duration: 24 * time.Hour, Concurrency: 2}, splitting)
+
+	_, err = parseQuerySplitting([]byte(`{"querySplitDuration": "one day"}`))
+	require.Error(t, err)
+}
+
+func TestSplitQuery(t *testing.T) {
+	start := time.Date(2022, 12, 1, 0, 0, 0, 0, time.UTC)
+	query := &lokiQuery{Expr: "{app=\"test\"}", QueryType: QueryTypeRange, Step: time.Minute, Start: start, End: start.Add(3 * time.Hour)}
+
+	t.Run("should not split short query", func(t *testing.T) {
+		queries := splitQuery(query, 3*time.Hour)
+		require.Equal(t, []*lokiQuery{query}, queries)
+	})
+
+	t.Run("should not split instant query", func(t *testing.T) {
+		instant := *query
+		instant.QueryType = QueryTypeInstant
+		queries := splitQuery(&instant, time.Hour)
+		require.Len(t, queries, 1)
+	})
+
+	t.Run("should split query into consecutive time ranges", func(t *testing.T) {
+		queries := splitQuery(query, time.Hour)
+		require.Len(t, queries, 3)
+		require.Equal(t, start, queries[0].Start)
+		require.Equal(t, start.Add(time.Hour-time.Nanosecond), queries[0].End)
+		require.Equal(t, start.Add(time.Hour), queries[1].Start)
+		require.Equal(t, start.Add(2*time.Hour-time.Nanosecond), queries[1].End)
+		require.Equal(t, start.Add(2*time.Hour), queries[2].Start)
+		require.Equal(t, start.Add(3*time.Hour), queries[2].End)
+		for _, q := range queries {
+			require.Equal(t, query.Expr, q.Expr)
+			require.Equal(t, query.Step, q.Step)
+		}
+	})
+
+	t.Run("should align split duration to step", func(t *testing.T) {
+		queries := splitQuery(query, 50*time.Minute+30*time.Second)
+		require.Len(t, queries, 4)
+		require.Equal(t, start.Add(51*time.Minute), queries[1].Start)
+	})
+}
+
+func TestRunSplitQuery(t *testing.T) {
+	start := time.Date(2022, 12, 1, 0, 0, 0, 0, time.UTC)
+	splitting := querySplitting{Duration: time.Hour, Concurrency: 2}
+
+	t.Run("should merge metric frames", func(t *testing.T) {
+		loki := &fakeLoki{metric: true}
+		api := newLokiAPI(&http.Client{Transport: loki}, "http://localhost:3100", log.New("test"), nil)
+		query := &lokiQuery{Expr: "count_over_time({app=\"test\"}[1m])", QueryType: QueryTypeRange, Direction: DirectionBackward, Step: 10 * time.Minute, Start: start, End: start.Add(5 * time.Hour), RefID: "A"}
+
+		frames, err := runSplitQuery(context.Background(), api, query, splitting)
+		require.NoError(t, err)
+		require.Len(t, loki.requests, 5)
+		require.Len(t, frames, 1)
+
+		frame := frames[0]
+		require.Equal(t, 31, frame.Rows())
+		for i := 0; i < frame.Rows(); i++ {
+			require.Equal(t, start.Add(time.Duration(i)*10*time.Minute), frame.Fields[0].At(i))
+			require.Equal(t, 1.0, frame.Fields[1].At(i))
+		}
+		require.Equal(t, `{app="test"}`, frame.Name)
+	})
+
+	t.Run("should merge log frames up to line limit", func(t *testing.T) {
+		loki := &fakeLoki{}
+		for i := 0; i < 50; i++ {
+			loki.lines = append(loki.lines, start.Add(time.Duration(i)*10*time.Minute))
+		}
+		api := newLokiAPI(&http.Client{Transport: loki}, "http://localhost:3100", log.New("test"), nil)
+		query := &lokiQuery{Expr: "{app=\"test\"}", QueryType: QueryTypeRange, Direction: DirectionBackward, Step: time.Minute, MaxLines: 10, Start: start, End: start.Add(8*time.Hour + 20*time.Minute), RefID: "A"}
+
+		frames, err := runSplitQuery(context.Background(), api, query, splitting)
+		require.NoError(t, err)
+		// the last two hours have 2 and 6 lines, so the line limit is reached with the second batch of queries
+		require.Len(t, loki.requests, 4)
+		require.Len(t, frames, 1)
+
+		frame := frames[0]
+		require.Equal(t, 10, frame.Rows())
+		require.Len(t, frame.Fields, 5)
+		for i := 0; i < frame.Rows(); i++ {
+			require.Equal(t, start.Add(time.Duration(49-i)*10*time.Minute), frame.Fields[1].At(i))
+		}
+	})
+
+	t.Run("should merge log frames in forward direction", func(t *testing.T) {
+		loki := &fakeLoki{}
+		for i := 0; i < 50; i++ {
+			loki.lines = append(loki.lines, start.Add(time.Duration(i)*10*time.Minute))
+		}
+		api := newLokiAPI(&http.Client{Transport: loki}, "http://localhost:3100", log.New("test"), nil)
+		query := &lokiQuery{Expr: "{app=\"test\"}", QueryType: QueryTypeRange, Direction: DirectionForward, Step: time.Minute, MaxLines: 100, Start: start, End: start.Add(8*time.Hour + 20*time.Minute), RefID: "A"}
+
+		frames, err := runSplitQuery(context.Background(), api, query, splitting)
+		require.NoError(t, err)
+		require.Len(t, loki.requests, 9)
+		require.Len(t, frames, 1)
+		require.Equal(t, 50, frames[0].Rows())
+		for i := 0; i < frames[0].Rows(); i++ {
+			require.Equal(t, start.Add(time.Duration(i)*10*time.Minute), frames[0].Fields[1].At(i))
+		}
+	})
+
+	t.Run("should fail when a split query fails", func(t *testing.T) {
+		loki := &fakeLoki{failAfter: start.Add(2 * time.Hour)}
+		api := newLokiAPI(&http.Client{Transport: loki}, "http://localhost:3100", log.New("test"), nil)
+		query := &lokiQuery{Expr: "{app=\"test\"}", QueryType: QueryTypeRange, Direction: DirectionForward, Step: time.Minute, Start: start, End: start.Add(5 * time.Hour), RefID: "A"}
+
+		_, err := runSplitQuery(context.Background(), api, query, splitting)
+		require.EqualError(t, err, "too many outstanding requests")
+	})
+}
+
+// fakeLoki answers range queries with a stream of log lines at the given times, or with a
+// series with the value 1 at every step.
+type fakeLoki struct {
+	metric    bool
+	lines     []time.Time
+	failAfter time.Time
+
+	mu       sync.Mutex
+	requests []*http.Request
+}
+
+func (l *fakeLoki) RoundTrip(req *http.Request) (*http.Response, error) {
+	l.mu.Lock()
+	l.requests = append(l.requests, req)
+	l.mu.Unlock()
+
+	qs := req.URL.Query()
+	start, err := strconv.ParseInt(qs.Get("start"), 10, 64)
+	if err != nil {
+		return nil, err
+	}
+	end, err := strconv.ParseInt(qs.Get("end"), 10, 64)
+	if err != nil {
+		return nil, err
+	}
+	if !l.failAfter.IsZero() && end > l.failAfter.UnixNano() {
+		return response(http.StatusTooManyRequests, `{"message": "too many outstanding requests"}`), nil
+	}
+
+	if l.metric {
+		step, err := time.ParseDuration(qs.Get("step"))
+		if err != nil {
+			return nil, err
+		}
+		values := [][]interface{}{}
+		for t := start; t <= end; t += step.Nanoseconds() {
+			values = append(values, []interface{}{float64(t) / 1e9, "1"})
+		}
+		return resultResponse("matrix", map[string]interface{}{"metric": map[string]string{"app": "test"}, "values": values})
+	}
+
+	var lines []time.Time
+	for _, t := range l.lines {
+		if t.UnixNano() >= start && t.UnixNano() <= end {
+			lines = append(lines, t)
+		}
+	}
+	sort.Slice(lines, func(i, j int) bool {
+		if qs.Get("direction") == string(DirectionBackward) {
+			return lines[i].After(lines[j])
+		}
+		return lines[i].Before(lines[j])
+	})
+	if limit, err := strconv.Atoi(qs.Get("limit")); err == nil && len(lines) > limit {
+		lines = lines[:limit]
+	}
+
+	values := [][]string{}
+	for _, t := range lines {
+		values = append(values, []string{strconv.FormatInt(t.UnixNano(), 10), fmt.Sprintf("line at %s", t)})
+	}
+	return resultResponse("streams", map[string]interface{}{"stream": map[string]string{"app": "test"}, "values": values})
+}
+
+// resultResponse returns a response with the result, the result type has to come before the result.
+func resultResponse(resultType string, result interface{}) (*http.Response, error) {
+	b, err := json.Marshal(result)
+	if err != nil {
+		return nil, err
+	}
+	body := fmt.Sprintf(`{"status": "success", "data": {"resultType": %q, "result": [%s]}}`, resultType, b)
+	return response(http.StatusOK, body), nil
+}
+
+func response(status int, body string) *http.Response {
+	return &http.Response{
+		StatusCode: status,
+		Header:     http.Header{"Content-Type": []string{"application/json"}},
+		Body:       io.NopCloser(bytes.NewReader([]byte(body))),
+	}
+}
@@ -1,6 +1,7 @@
 package opentsdb
 
 import (
+	"bytes"
 	"context"
 	"encoding/json"
 	"fmt"
@@ -8,6 +9,7 @@ import (
 	"net/http"
 	"net/url"
 	"path"
+	"sort"
 	"strconv"
 	"strings"
 	"time"
@@ -15,6 +17,7 @@ import (
 	"github.com/grafana/grafana-plugin-sdk-go/backend"
 	"github.com/grafana/grafana-plugin-sdk-go/backend/datasource"
 	"github.com/grafana/grafana-plugin-sdk-go/backend/instancemgmt"
+	"github.com/grafana/grafana-plugin-sdk-go/backend/resource/httpadapter"
 	"github.com/grafana/grafana-plugin-sdk-go/data"
 
 	"github.com/grafana/grafana/pkg/components/simplejson"
@@ -26,18 +29,35 @@ import (
 var logger = log.New("tsdb.opentsdb")
 
 type Service struct {
-	im instancemgmt.InstanceManager
+	im              instancemgmt.InstanceManager
+	resourceHandler backend.CallResourceHandler
 }
 
 func ProvideService(httpClientProvider httpclient.Provider) *Service {
-	return &Service{
+	s := &Service{
 		im: datasource.NewInstanceManager(newInstanceSettings(httpClientProvider)),
 	}
+	s.resourceHandler = httpadapter.New(s.newResourceMux())
+	return s
 }
 
+const (
+	// tsdbVersion3 is the version setting of OpenTSDB 2.3, that returns the queries with their results
+	tsdbVersion3 = 3
+	// tsdbResolutionMilliseconds is the resolution setting of OpenTSDB storing timestamps in milliseconds
+	tsdbResolutionMilliseconds = 2
+	defaultLookupLimit         = 1000
+)
+
+// fillPolicies are the downsampling fill policies of OpenTSDB, the policy none doesn't fill missing values.
+var fillPolicies = []string{"none", "nan", "null", "zero"}
+
 type datasourceInfo struct {
-	HTTPClient *http.Client
-	URL        string
+	HTTPClient     *http.Client
+	URL            string
+	TSDBVersion    int64
+	TSDBResolution int64
+	LookupLimit    int64
 }
 
 type DsAccess string
@@ -54,28 +74,77 @@ func newInstanceSettings(httpClientProvider httpclient.Provider) datasource.Inst
 			return nil, err
 		}
 
+		jsonData := simplejson.New()
+		if len(settings.JSONData) > 0 {
+			jsonData, err = simplejson.NewJson(settings.JSONData)
+			if err != nil {
+				return nil, fmt.Errorf("error reading settings: %w", err)
+			}
+		}
+
 		model := &datasourceInfo{
-			HTTPClient: client,
-			URL:        settings.URL,
+			HTTPClient:     client,
+			URL:            settings.URL,
+			TSDBVersion:    jsonData.Get("tsdbVersion").MustInt64(1),
+			TSDBResolution: jsonData.Get("tsdbResolution").MustInt64(1),
+			LookupLimit:    jsonData.Get("lookupLimit").MustInt64(defaultLookupLimit),
 		}
 
 		return model, nil
 	}
 }
 
+func (s *Service) CallResource(ctx context.Context, req *backend.CallResourceRequest, sender backend.CallResourceResponseSender) error {
+	return s.resourceHandler.CallResource(ctx, req, sender)
+}
+
+// QueryData runs the metric queries of the request in one request to OpenTSDB, and each annotation query
+// in a request of its own.
 func (s *Service) QueryData(ctx context.Context, req *backend.QueryDataRequest) (*backend.QueryDataResponse, error) {
-	var tsdbQuery OpenTsdbQuery
+	if len(req.Queries) == 0 {
+		return nil, fmt.Errorf("query contains no queries")
+	}
 
 	logger := logger.FromContext(ctx)
 
-	q := req.Queries[0]
+	dsInfo, err := s.getDSInfo(req.PluginContext)
+	if err != nil {
+		return nil, err
+	}
 
-	tsdbQuery.Start = q.TimeRange.From.UnixNano() / int64(time.Millisecond)
-	tsdbQuery.End = q.TimeRange.To.UnixNano() / int64(time.Millisecond)
+	result := backend.NewQueryDataResponse()
+	tsdbQuery := newOpenTsdbQuery(dsInfo, req.Queries[0].TimeRange)
+	refIDs := []string{}
 
 	for _, query := range req.Queries {
-		metric := s.buildMetric(query)
+		model, err := simplejson.NewJson(query.JSON)
+		if err != nil {
+			result.Responses[query.RefID] = backend.DataResponse{Error: err}
+			continue
+		}
+
+		if model.Get("fromAnnotations").MustBool() {
+			result.Responses[query.RefID] = s.queryAnnotations(ctx, logger, dsInfo, query, model)
+			continue
+		}
+
+		// Queries without metric are not sent to OpenTSDB
+		if model.Get("metric").MustString() == "" {
+			result.Responses[query.RefID] = backend.DataResponse{}
+			continue
+		}
+
+		metric, err := s.buildMetric(query)
+		if err != nil {
+			result.Responses[query.RefID] = backend.DataResponse{Error: err}
+			continue
+		}
 		tsdbQuery.Queries = append(tsdbQuery.Queries, metric)
+		refIDs = append(refIDs, query.RefID)
+	}
+
+	if len(tsdbQuery.Queries) == 0 {
+		return result, nil
 	}
 
 	// TODO: Don't use global variable
@@ -83,27 +152,94 @@ func (s *Service) QueryData(ctx context.Context, req *backend.QueryDataRequest)
 		logger.Debug("OpenTsdb request", "params", tsdbQuery)
 	}
 
-	dsInfo, err := s.getDSInfo(req.PluginContext)
+	responses, err := s.query(ctx, logger, dsInfo, tsdbQuery)
 	if err != nil {
-		return nil, err
+		for _, refID := range refIDs {
+			result.Responses[refID] = backend.DataResponse{Error: err}
+		}
+		return result, nil
 	}
 
-	request, err := s.createRequest(ctx, logger, dsInfo, tsdbQuery)
+	frames, err := s.toDataFrames(responses, tsdbQuery, refIDs)
+	for i, refID := range refIDs {
+		if err != nil {
+			result.Responses[refID] = backend.DataResponse{Error: err}
+			continue
+		}
+		result.Responses[refID] = backend.DataResponse{Frames: frames[i]}
+	}
+
+	return result, nil
+}
+
+func newOpenTsdbQuery(dsInfo *datasourceInfo, timeRange backend.TimeRange) OpenTsdbQuery {
+	return OpenTsdbQuery{
+		Start:        timeRange.From.UnixNano() / int64(time.Millisecond),
+		End:          timeRange.To.UnixNano() / int64(time.Millisecond),
+		MsResolution: dsInfo.TSDBResolution == tsdbResolutionMilliseconds,
+		ShowQuery:    dsInfo.TSDBVersion >= tsdbVersion3,
+	}
+}
+
+// queryAnnotations returns the annotations of the metric of the annotation query, or the global annotations
+// when the query is global, as a frame of events.
+func (s *Service) queryAnnotations(ctx context.Context, logger log.Logger, dsInfo *datasourceInfo, query backend.DataQuery, model *simplejson.Json) backend.DataResponse {
+	target := model.Get("target").MustString()
+	if target == "" {
+		return backend.DataResponse{Error: fmt.Errorf("annotation query has no metric")}
+	}
+
+	tsdbQuery := newOpenTsdbQuery(dsInfo, query.TimeRange)
+	tsdbQuery.GlobalAnnotations = true
+	tsdbQuery.Queries = []map[string]interface{}{{"aggregator": "sum", "metric": target}}
+
+	responses, err := s.query(ctx, logger, dsInfo, tsdbQuery)
 	if err != nil {
-		return &backend.QueryDataResponse{}, err
+		return backend.DataResponse{Error: err}
 	}
 
-	res, err := dsInfo.HTTPClient.Do(request)
+	var annotations []OpenTsdbAnnotation
+	if len(responses) > 0 {
+		annotations = responses[0].Annotations
+		if model.Get("isGlobal").MustBool() {
+			annotations = responses[0].GlobalAnnotations
+		}
+	}
+
+	times := make([]time.Time, 0, len(annotations))
+	timeEnds := make([]*time.Time, 0, len(annotations))
+	texts := make([]string, 0, len(annotations))
+	for _, annotation := range annotations {
+		times = append(times, time.Unix(int64(annotation.StartTime), 0).UTC())
+		var timeEnd *time.Time
+		if annotation.EndTime > 0 {
+			t := time.Unix(int64(annotation.EndTime), 0).UTC()
+			timeEnd = &t
+		}
+		timeEnds = append(timeEnds, timeEnd)
+		texts = append(texts, annotation.Description)
+	}
+
+	frame := data.NewFrame(query.RefID,
+		data.NewField("time", nil, times),
+		data.NewField("timeEnd", nil, timeEnds),
+		data.NewField("text", nil, texts))
+	frame.RefID = query.RefID
+	return backend.DataResponse{Frames: data.Frames{frame}}
+}
+
+func (s *Service) query(ctx context.Context, logger log.Logger, dsInfo *datasourceInfo, tsdbQuery OpenTsdbQuery) ([]OpenTsdbResponse, error) {
+	request, err := s.createRequest(ctx, logger, dsInfo, tsdbQuery)
 	if err != nil {
-		return &backend.QueryDataResponse{}, err
+		return nil, err
 	}
 
-	result, err := s.parseResponse(logger, res)
+	res, err := dsInfo.HTTPClient.Do(request)
 	if err != nil {
-		return &backend.QueryDataResponse{}, err
+		return nil, err
 	}
 
-	return result, nil
+	return s.parseResponse(logger, res)
 }
 
 func (s *Service) createRequest(ctx context.Context, logger log.Logger, dsInfo *datasourceInfo, data OpenTsdbQuery) (*http.Request, error) {
@@ -129,9 +265,7 @@ func (s *Service) createRequest(ctx context.Context, logger log.Logger, dsInfo *
 	return req, nil
 }
 
-func (s *Service) parseResponse(logger log.Logger, res *http.Response) (*backend.QueryDataResponse, error) {
-	resp := backend.NewQueryDataResponse()
-
+func (s *Service) parseResponse(logger log.Logger, res *http.Response) ([]OpenTsdbResponse, error) {
 	body, err := io.ReadAll(res.Body)
 	if err != nil {
 		return nil, err
@@ -148,44 +282,146 @@ func (s *Service) parseResponse(logger log.Logger, res *http.Response) (*backend
 	}
 
 	var responseData []OpenTsdbResponse
-	err = json.Unmarshal(body, &responseData)
+	err = json.Unmarshal(replaceNaN(body), &responseData)
 	if err != nil {
 		logger.Info("Failed to unmarshal opentsdb response", "error", err, "status", res.Status, "body", string(body))
 		return nil, err
 	}
 
-	frames := data.Frames{}
-	for _, val := range responseData {
-		timeVector := make([]time.Time, 0, len(val.DataPoints))
-		values := make([]float64, 0, len(val.DataPoints))
-		name := val.Metric
-		tags := val.Tags
+	return responseData, nil
+}
+
+// replaceNaN replaces the NaN values of data points, that OpenTSDB returns with the nan fill policy but aren't valid
+// JSON, with null. Only NaN tokens outside of strings are replaced, so that tags and annotations are left unchanged.
+func replaceNaN(body []byte) []byte {
+	if !bytes.Contains(body, []byte("NaN")) {
+		return body
+	}
+
+	result := make([]byte, 0, len(body))
+	inString, escaped := false, false
+	for i := 0; i < len(body); i++ {
+		c := body[i]
+		switch {
+		case inString:
+			switch {
+			case escaped:
+				escaped = false
+			case c == '\\':
+				escaped = true
+			case c == '"':
+				inString = false
+			}
+		case c == '"':
+			inString = true
+		case bytes.HasPrefix(body[i:], []byte("NaN")):
+			result = append(result, "null"...)
+			i += len("NaN") - 1
+			continue
+		}
+		result = append(result, c)
+	}
+	return result
+}
+
+// toDataFrames returns the frames of each query of the OpenTSDB query, in the order of its queries.
+func (s *Service) toDataFrames(responses []OpenTsdbResponse, tsdbQuery OpenTsdbQuery, refIDs []string) ([]data.Frames, error) {
+	frames := make([]data.Frames, len(tsdbQuery.Queries))
+	for i := range frames {
+		frames[i] = data.Frames{}
+	}
 
+	for _, val := range responses {
+		type dataPoint struct {
+			time  time.Time
+			value *float64
+		}
+		dataPoints := make([]dataPoint, 0, len(val.DataPoints))
 		for timeString, value := range val.DataPoints {
 			timestamp, err := strconv.ParseInt(timeString, 10, 64)
 			if err != nil {
-				logger.Info("Failed to unmarshal opentsdb timestamp", "timestamp", timeString)
-				return nil, err
+				return nil, fmt.Errorf("failed to parse opentsdb timestamp %q: %w", timeString, err)
+			}
+			t := time.Unix(timestamp, 0).UTC()
+			if tsdbQuery.MsResolution {
+				t = time.UnixMilli(timestamp).UTC()
 			}
-			timeVector = append(timeVector, time.Unix(timestamp, 0).UTC())
-			values = append(values, value)
+			dataPoints = append(dataPoints, dataPoint{time: t, value: value})
 		}
-		frames = append(frames, data.NewFrame(name,
+		sort.Slice(dataPoints, func(i, j int) bool {
+			return dataPoints[i].time.Before(dataPoints[j].time)
+		})
+
+		timeVector := make([]time.Time, 0, len(dataPoints))
+		values := make([]*float64, 0, len(dataPoints))
+		for _, dp := range dataPoints {
+			timeVector = append(timeVector, dp.time)
+			values = append(values, dp.value)
+		}
+
+		index := queryIndex(val, tsdbQuery.Queries)
+		frame := data.NewFrame(val.Metric,
 			data.NewField("time", nil, timeVector),
-			data.NewField("value", tags, values)))
+			data.NewField("value", val.Tags, values))
+		frame.RefID = refIDs[index]
+		frames[index] = append(frames[index], frame)
+	}
+	return frames, nil
+}
+
+// queryIndex returns the index of the query of the response. Before OpenTSDB 2.3 responses don't include their
+// query, and the query is found by the metric and tags of the response, or else is the first query.
+func queryIndex(response OpenTsdbResponse, queries []map[string]interface{}) int {
+	if response.Query != nil {
+		if response.Query.Index >= 0 && response.Query.Index < len(queries) {
+			return response.Query.Index
+		}
+		return 0
+	}
+
+	for i, query := range queries {
+		if query["metric"] != response.Metric {
+			continue
+		}
+		if _, ok := query["filters"]; ok {
+			return i
+		}
+		tags, _ := query["tags"].(map[string]interface{})
+		if matchTags(tags, response.Tags) {
+			return i
+		}
+	}
+	return 0
+}
+
+// matchTags returns true if the tags of a response match the tags of a query, that can have a value of *
+// or values separated by |.
+func matchTags(queryTags map[string]interface{}, responseTags map[string]string) bool {
+	for k, v := range queryTags {
+		value := fmt.Sprintf("%v", v)
+		if value == "*" {
+			continue
+		}
+		found := false
+		for _, option := range strings.Split(value, "|") {
+			if option == responseTags[k] {
+				found = true
+				break
+			}
+		}
+		if !found {
+			return false
+		}
 	}
-	result := resp.Responses["A"]
-	result.Frames = frames
-	resp.Responses["A"] = result
-	return resp, nil
+	return true
 }
 
-func (s *Service) buildMetric(query backend.DataQuery) map[string]interface{} {
+func (s *Service) buildMetric(query backend.DataQuery) (map[string]interface{}, error) {
 	metric := make(map[string]interface{})
 
 	model, err := simplejson.NewJson(query.JSON)
 	if err != nil {
-		return nil
+		return nil, err
 	}
 
 	// Setting metric and aggregator
@@ -199,9 +435,21 @@ func (s *Service) buildMetric(query backend.DataQuery) map[string]interface{} {
 		if downsampleInterval == "" {
 			downsampleInterval = "1m" // default value for blank
 		}
-		downsample := downsampleInterval + "-" + model.Get("downsampleAggregator").MustString()
-		if model.Get("downsampleFillPolicy").MustString() != "none" {
-			metric["downsample"] = downsample + "-" + model.Get("downsampleFillPolicy").MustString()
+		downsampleAggregator := model.Get("downsampleAggregator").MustString()
+		if downsampleAggregator == "" {
+			downsampleAggregator = "avg" // default value for blank
+		}
+		downsample := downsampleInterval + "-" + downsampleAggregator
+
+		fillPolicy := model.Get("downsampleFillPolicy").MustString()
+		if fillPolicy == "" {
+			fillPolicy = "none"
+		}
+		if !isFillPolicy(fillPolicy) {
+			return nil, fmt.Errorf("invalid downsample fill policy %q, must be one of %s", fillPolicy, strings.Join(fillPolicies, ", "))
+		}
+		if fillPolicy != "none" {
+			metric["downsample"] = downsample + "-" + fillPolicy
 		} else {
 			metric["downsample"] = downsample
 		}
@@ -242,7 +490,16 @@ func (s *Service) buildMetric(query backend.DataQuery) map[string]interface{} {
 		metric["filters"] = filters.MustArray()
 	}
 
-	return metric
+	return metric, nil
+}
+
+func isFillPolicy(fillPolicy string) bool {
+	for _, p := range fillPolicies {
+		if p == fillPolicy {
+			return true
+		}
+	}
+	return false
 }
 
 func (s *Service) getDSInfo(pluginCtx backend.PluginContext) (*datasourceInfo, error) {
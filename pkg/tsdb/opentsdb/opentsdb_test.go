@@ -2,8 +2,10 @@ package opentsdb
 
 import (
 	"context"
+	"encoding/json"
 	"io"
 	"net/http"
+	"net/http/httptest"
 	"strings"
 	"testing"
 	"time"
@@ -13,6 +15,8 @@ import (
 	"github.com/grafana/grafana-plugin-sdk-go/data"
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/require"
+
+	"github.com/grafana/grafana/pkg/infra/httpclient"
 )
 
 func TestOpenTsdbExecutor(t *testing.T) {
@@ -57,18 +61,21 @@ func TestOpenTsdbExecutor(t *testing.T) {
 			data.NewField("time", nil, []time.Time{
 				time.Date(2014, 7, 16, 20, 55, 46, 0, time.UTC),
 			}),
-			data.NewField("value", map[string]string{"env": "prod", "app": "grafana"}, []float64{
-				50}),
+			data.NewField("value", map[string]string{"env": "prod", "app": "grafana"}, []*float64{
+				float64Pointer(50)}),
 		)
+		testFrame.RefID = "A"
 
 		resp := http.Response{Body: io.NopCloser(strings.NewReader(response))}
 		resp.StatusCode = 200
-		result, err := service.parseResponse(logger, &resp)
+		responses, err := service.parseResponse(logger, &resp)
 		require.NoError(t, err)
 
-		frame := result.Responses["A"]
+		tsdbQuery := OpenTsdbQuery{Queries: []map[string]interface{}{{"metric": "test"}}}
+		frames, err := service.toDataFrames(responses, tsdbQuery, []string{"A"})
+		require.NoError(t, err)
 
-		if diff := cmp.Diff(testFrame, frame.Frames[0], data.FrameTestCompareOptions()...); diff != "" {
+		if diff := cmp.Diff(testFrame, frames[0][0], data.FrameTestCompareOptions()...); diff != "" {
 			t.Errorf("Result mismatch (-want +got):\n%s", diff)
 		}
 	})
@@ -87,7 +94,8 @@ func TestOpenTsdbExecutor(t *testing.T) {
 			),
 		}
 
-		metric := service.buildMetric(query)
+		metric, err := service.buildMetric(query)
+		require.NoError(t, err)
 
 		require.Len(t, metric, 3)
 		require.Equal(t, "cpu.average.percent", metric["metric"])
@@ -109,7 +117,8 @@ func TestOpenTsdbExecutor(t *testing.T) {
 			),
 		}
 
-		metric := service.buildMetric(query)
+		metric, err := service.buildMetric(query)
+		require.NoError(t, err)
 
 		require.Len(t, metric, 2)
 		require.Equal(t, "cpu.average.percent", metric["metric"])
@@ -130,7 +139,8 @@ func TestOpenTsdbExecutor(t *testing.T) {
 			),
 		}
 
-		metric := service.buildMetric(query)
+		metric, err := service.buildMetric(query)
+		require.NoError(t, err)
 
 		require.Len(t, metric, 3)
 		require.Equal(t, "cpu.average.percent", metric["metric"])
@@ -156,7 +166,8 @@ func TestOpenTsdbExecutor(t *testing.T) {
 			),
 		}
 
-		metric := service.buildMetric(query)
+		metric, err := service.buildMetric(query)
+		require.NoError(t, err)
 
 		require.Len(t, metric, 3)
 		require.Equal(t, "cpu.average.percent", metric["metric"])
@@ -187,7 +198,8 @@ func TestOpenTsdbExecutor(t *testing.T) {
 			),
 		}
 
-		metric := service.buildMetric(query)
+		metric, err := service.buildMetric(query)
+		require.NoError(t, err)
 
 		require.Len(t, metric, 5)
 		require.Equal(t, "cpu.average.percent", metric["metric"])
@@ -222,7 +234,8 @@ func TestOpenTsdbExecutor(t *testing.T) {
 			),
 		}
 
-		metric := service.buildMetric(query)
+		metric, err := service.buildMetric(query)
+		require.NoError(t, err)
 
 		require.Len(t, metric, 5)
 		require.Equal(t, "cpu.average.percent", metric["metric"])
@@ -242,3 +255,133 @@ func TestOpenTsdbExecutor(t *testing.T) {
 		require.Equal(t, float64(60), metricRateOptions["resetValue"])
 	})
 }
+
+func TestQueryData(t *testing.T) {
+	var requests []OpenTsdbQuery
+	tsdb := httptest.NewServer(http.HandlerFunc(func(rw http.ResponseWriter, req *http.Request) {
+		var query OpenTsdbQuery
+		require.NoError(t, json.NewDecoder(req.Body).Decode(&query))
+		requests = append(requests, query)
+
+		if query.GlobalAnnotations {
+			_, _ = rw.Write([]byte(`[{
+				"metric": "deploys", "tags": {}, "dps": {},
+				"annotations": [{"description": "deployed 1.0", "startTime": 1405544146, "endTime": 1405544246}],
+				"globalAnnotations": [{"description": "outage", "startTime": 1405544000}]
+			}]`))
+			return
+		}
+		// Responses of OpenTSDB 2.3 include the index of their query
+		_, _ = rw.Write([]byte(`[
+			{"metric": "cpu", "tags": {"host": "b"}, "dps": {"1405544206": 2, "1405544146": 1}, "query": {"index": 1}},
+			{"metric": "cpu", "tags": {"host": "a"}, "dps": {"1405544146": NaN, "1405544206": null}, "query": {"index": 0}}
+		]`))
+	}))
+	t.Cleanup(tsdb.Close)
+
+	service := ProvideService(httpclient.NewProvider())
+	pluginCtx := backend.PluginContext{
+		DataSourceInstanceSettings: &backend.DataSourceInstanceSettings{
+			ID:       1,
+			URL:      tsdb.URL,
+			JSONData: []byte(`{"tsdbVersion": 3, "tsdbResolution": 1}`),
+		},
+	}
+	timeRange := backend.TimeRange{From: time.Unix(1405544000, 0), To: time.Unix(1405545000, 0)}
+
+	t.Run("should run metric queries in one request", func(t *testing.T) {
+		requests = nil
+		resp, err := service.QueryData(context.Background(), &backend.QueryDataRequest{
+			PluginContext: pluginCtx,
+			Queries: []backend.DataQuery{
+				{RefID: "A", TimeRange: timeRange, JSON: []byte(`{"metric": "cpu", "aggregator": "sum", "tags": {"host": "a"}, "downsampleFillPolicy": "nan"}`)},
+				{RefID: "B", TimeRange: timeRange, JSON: []byte(`{"metric": "cpu", "aggregator": "sum", "tags": {"host": "b"}}`)},
+				{RefID: "C", TimeRange: timeRange, JSON: []byte(`{"aggregator": "sum"}`)},
+				{RefID: "D", TimeRange: timeRange, JSON: []byte(`{"metric": "cpu", "downsampleFillPolicy": "previous"}`)},
+			},
+		})
+		require.NoError(t, err)
+
+		require.Len(t, requests, 1)
+		require.Len(t, requests[0].Queries, 2)
+		assert.True(t, requests[0].ShowQuery)
+		assert.Equal(t, "1m-avg-nan", requests[0].Queries[0]["downsample"])
+
+		a := resp.Responses["A"]
+		require.NoError(t, a.Error)
+		require.Len(t, a.Frames, 1)
+		assert.Equal(t, "A", a.Frames[0].RefID)
+		assert.Equal(t, "a", a.Frames[0].Fields[1].Labels["host"])
+		assert.Nil(t, a.Frames[0].Fields[1].At(0))
+		assert.Nil(t, a.Frames[0].Fields[1].At(1))
+
+		b := resp.Responses["B"]
+		require.NoError(t, b.Error)
+		require.Len(t, b.Frames, 1)
+		assert.Equal(t, time.Unix(1405544146, 0).UTC(), b.Frames[0].Fields[0].At(0))
+		assert.Equal(t, 1.0, *b.Frames[0].Fields[1].At(0).(*float64))
+		assert.Equal(t, 2.0, *b.Frames[0].Fields[1].At(1).(*float64))
+
+		require.NoError(t, resp.Responses["C"].Error)
+		assert.Empty(t, resp.Responses["C"].Frames)
+		assert.EqualError(t, resp.Responses["D"].Error, `invalid downsample fill policy "previous", must be one of none, nan, null, zero`)
+	})
+
+	t.Run("should return annotations", func(t *testing.T) {
+		requests = nil
+		resp, err := service.QueryData(context.Background(), &backend.QueryDataRequest{
+			PluginContext: pluginCtx,
+			Queries: []backend.DataQuery{
+				{RefID: "Anno", TimeRange: timeRange, JSON: []byte(`{"fromAnnotations": true, "target": "deploys"}`)},
+				{RefID: "Global", TimeRange: timeRange, JSON: []byte(`{"fromAnnotations": true, "target": "deploys", "isGlobal": true}`)},
+			},
+		})
+		require.NoError(t, err)
+		require.Len(t, requests, 2)
+		assert.Equal(t, "deploys", requests[0].Queries[0]["metric"])
+
+		frames := resp.Responses["Anno"].Frames
+		require.Len(t, frames, 1)
+		require.Equal(t, 1, frames[0].Rows())
+		assert.Equal(t, time.Unix(1405544146, 0).UTC(), frames[0].Fields[0].At(0))
+		assert.Equal(t, time.Unix(1405544246, 0).UTC(), *frames[0].Fields[1].At(0).(*time.Time))
+		assert.Equal(t, "deployed 1.0", frames[0].Fields[2].At(0))
+
+		frames = resp.Responses["Global"].Frames
+		require.Len(t, frames, 1)
+		require.Equal(t, 1, frames[0].Rows())
+		assert.Nil(t, frames[0].Fields[1].At(0))
+		assert.Equal(t, "outage", frames[0].Fields[2].At(0))
+	})
+}
+
+func TestQueryIndex(t *testing.T) {
+	queries := []map[string]interface{}{
+		{"metric": "cpu", "tags": map[string]interface{}{"host": "a|b"}},
+		{"metric": "cpu", "tags": map[string]interface{}{"host": "*"}},
+		{"metric": "mem", "filters": []interface{}{map[string]interface{}{"tagk": "host"}}},
+	}
+
+	assert.Equal(t, 0, queryIndex(OpenTsdbResponse{Metric: "cpu", Tags: map[string]string{"host": "b"}}, queries))
+	assert.Equal(t, 1, queryIndex(OpenTsdbResponse{Metric: "cpu", Tags: map[string]string{"host": "c"}}, queries))
+	assert.Equal(t, 2, queryIndex(OpenTsdbResponse{Metric: "mem", Tags: map[string]string{"host": "c"}}, queries))
+	assert.Equal(t, 0, queryIndex(OpenTsdbResponse{Metric: "disk"}, queries))
+	assert.Equal(t, 2, queryIndex(OpenTsdbResponse{Metric: "cpu", Query: &OpenTsdbResponseQuery{Index: 2}}, queries))
+}
+
+func TestReplaceNaN(t *testing.T) {
+	body := `[{"metric": "cpu", "tags": {"note": "value: NaN"}, "dps": {"1405544146": NaN,"1405544206":NaN}, "annotations": [{"description": "\"NaN\": NaN"}]}]`
+
+	var responses []OpenTsdbResponse
+	require.NoError(t, json.Unmarshal(replaceNaN([]byte(body)), &responses))
+	require.Len(t, responses, 1)
+	assert.Equal(t, "value: NaN", responses[0].Tags["note"])
+	assert.Equal(t, map[string]*float64{"1405544146": nil, "1405544206": nil}, responses[0].DataPoints)
+	assert.Equal(t, `"NaN": NaN`, responses[0].Annotations[0].Description)
+
+	assert.Equal(t, `{"dps": {"1": 1.5}}`, string(replaceNaN([]byte(`{"dps": {"1": 1.5}}`))))
+}
+
+func float64Pointer(v float64) *float64 {
+	return &v
+}
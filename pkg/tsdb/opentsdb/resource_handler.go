@@ -0,0 +1,186 @@
+package opentsdb
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"path"
+	"strconv"
+
+	"github.com/grafana/grafana-plugin-sdk-go/backend/resource/httpadapter"
+)
+
+// suggestTypes are the types of the names that the suggest endpoint of OpenTSDB returns.
+var suggestTypes = []string{"metrics", "tagk", "tagv"}
+
+func (s *Service) newResourceMux() *http.ServeMux {
+	mux := http.NewServeMux()
+	mux.HandleFunc("/api/suggest", s.handleSuggest)
+	mux.HandleFunc("/api/search/lookup", s.handleLookup)
+	return mux
+}
+
+// handleSuggest returns the names of metrics, tag keys or tag values that start with the query, for the
+// suggest_metrics, suggest_tagk and suggest_tagv template variable queries.
+func (s *Service) handleSuggest(rw http.ResponseWriter, req *http.Request) {
+	dsInfo, err := s.getDSInfo(httpadapter.PluginConfigFromContext(req.Context()))
+	if err != nil {
+		writeErrorResponse(rw, http.StatusInternalServerError, err)
+		return
+	}
+
+	query := req.URL.Query()
+	suggestType := query.Get("type")
+	if !isSuggestType(suggestType) {
+		writeErrorResponse(rw, http.StatusBadRequest, fmt.Errorf("invalid suggest type %q", suggestType))
+		return
+	}
+	max, err := limitParam(query.Get("max"), dsInfo.LookupLimit)
+	if err != nil {
+		writeErrorResponse(rw, http.StatusBadRequest, err)
+		return
+	}
+
+	params := url.Values{"type": {suggestType}, "max": {max}}
+	if q := query.Get("q"); q != "" {
+		params.Set("q", q)
+	}
+
+	suggestions := []string{}
+	if code, err := doResourceRequest(req, dsInfo, "api/suggest", params, &suggestions); err != nil {
+		writeErrorResponse(rw, code, err)
+		return
+	}
+	writeJSONResponse(rw, suggestions)
+}
+
+// handleLookup returns the time series of a metric that match the tags of the query, such as
+// cpu{host=*,env=prod}, for the tag_names and tag_values template variable queries.
+func (s *Service) handleLookup(rw http.ResponseWriter, req *http.Request) {
+	dsInfo, err := s.getDSInfo(httpadapter.PluginConfigFromContext(req.Context()))
+	if err != nil {
+		writeErrorResponse(rw, http.StatusInternalServerError, err)
+		return
+	}
+
+	query := req.URL.Query()
+	m := query.Get("m")
+	if m == "" {
+		writeErrorResponse(rw, http.StatusBadRequest, fmt.Errorf("m is required"))
+		return
+	}
+	limit, err := limitParam(query.Get("limit"), dsInfo.LookupLimit)
+	if err != nil {
+		writeErrorResponse(rw, http.StatusBadRequest, err)
+		return
+	}
+
+	lookup := OpenTsdbLookupResponse{}
+	if code, err := doResourceRequest(req, dsInfo, "api/search/lookup", url.Values{"m": {m}, "limit": {limit}}, &lookup); err != nil {
+		writeErrorResponse(rw, code, err)
+		return
+	}
+	if lookup.Results == nil {
+		lookup.Results = []OpenTsdbLookupResult{}
+	}
+	writeJSONResponse(rw, lookup)
+}
+
+// doResourceRequest gets the endpoint of the OpenTSDB API with the HTTP client of the data source, so that the
+// request has the same authentication as queries, and decodes the response into v. It returns the status code of
+// the response for the resource request when the request fails.
+func doResourceRequest(req *http.Request, dsInfo *datasourceInfo, endpoint string, params url.Values, v interface{}) (int, error) {
+	logger := logger.FromContext(req.Context())
+
+	u, err := url.Parse(dsInfo.URL)
+	if err != nil {
+		return http.StatusInternalServerError, err
+	}
+	u.Path = path.Join(u.Path, endpoint)
+	u.RawQuery = params.Encode()
+
+	tsdbReq, err := http.NewRequestWithContext(req.Context(), http.MethodGet, u.String(), nil)
+	if err != nil {
+		return http.StatusInternalServerError, fmt.Errorf("failed to create request: %w", err)
+	}
+
+	res, err := dsInfo.HTTPClient.Do(tsdbReq)
+	if err != nil {
+		return http.StatusBadGateway, err
+	}
+	defer func() {
+		if err := res.Body.Close(); err != nil {
+			logger.Warn("Failed to close response body", "err", err)
+		}
+	}()
+
+	body, err := io.ReadAll(res.Body)
+	if err != nil {
+		return http.StatusBadGateway, err
+	}
+	if res.StatusCode/100 != 2 {
+		logger.Info("Request failed", "endpoint", endpoint, "status", res.Status, "body", string(body))
+		return resourceErrorStatus(res.StatusCode), fmt.Errorf("OpenTSDB request failed, status: %s", res.Status)
+	}
+	if err := json.Unmarshal(body, v); err != nil {
+		logger.Info("Failed to unmarshal opentsdb response", "endpoint", endpoint, "error", err, "body", string(body))
+		return http.StatusBadGateway, fmt.Errorf("invalid response returned by OpenTSDB: %w", err)
+	}
+	return http.StatusOK, nil
+}
+
+// resourceErrorStatus returns the status of the resource response for a failed request to OpenTSDB. Authentication
+// errors of OpenTSDB are returned as 502, as the frontend would otherwise take them for an expired Grafana session,
+// and so are server errors.
+func resourceErrorStatus(code int) int {
+	if code/100 == 4 && code != http.StatusUnauthorized && code != http.StatusForbidden {
+		return code
+	}
+	return http.StatusBadGateway
+}
+
+// limitParam returns the limit of the request, or the lookup limit of the data source if it has none.
+func limitParam(value string, lookupLimit int64) (string, error) {
+	if value == "" {
+		return strconv.FormatInt(lookupLimit, 10), nil
+	}
+	limit, err := strconv.ParseInt(value, 10, 64)
+	if err != nil || limit <= 0 {
+		return "", fmt.Errorf("invalid limit %q", value)
+	}
+	return strconv.FormatInt(limit, 10), nil
+}
+
+func isSuggestType(suggestType string) bool {
+	for _, t := range suggestTypes {
+		if t == suggestType {
+			return true
+		}
+	}
+	return false
+}
+
+func writeJSONResponse(rw http.ResponseWriter, v interface{}) {
+	body, err := json.Marshal(v)
+	if err != nil {
+		writeErrorResponse(rw, http.StatusInternalServerError, err)
+		return
+	}
+	rw.Header().Set("Content-Type", "application/json")
+	writeResponseBytes(rw, http.StatusOK, body)
+}
+
+func writeErrorResponse(rw http.ResponseWriter, code int, err error) {
+	body, _ := json.Marshal(map[string]string{"message": err.Error()})
+	rw.Header().Set("Content-Type", "application/json")
+	writeResponseBytes(rw, code, body)
+}
+
+func writeResponseBytes(rw http.ResponseWriter, code int, body []byte) {
+	rw.WriteHeader(code)
+	if _, err := rw.Write(body); err != nil {
+		logger.Error("Unable to write HTTP response", "error", err)
+	}
+}
@@ -0,0 +1,123 @@
+package opentsdb
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"strings"
+	"testing"
+
+	"github.com/grafana/grafana-plugin-sdk-go/backend"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/grafana/grafana/pkg/infra/httpclient"
+)
+
+func TestCallResource(t *testing.T) {
+	var params []url.Values
+	tsdb := httptest.NewServer(http.HandlerFunc(func(rw http.ResponseWriter, req *http.Request) {
+		params = append(params, req.URL.Query())
+		switch req.URL.Path {
+		case "/api/suggest":
+			if req.URL.Query().Get("q") == "denied" {
+				rw.WriteHeader(http.StatusUnauthorized)
+				return
+			}
+			if req.URL.Query().Get("q") == "invalid" {
+				rw.WriteHeader(http.StatusBadRequest)
+				return
+			}
+			_, _ = rw.Write([]byte(`["cpu.system", "cpu.user"]`))
+		case "/api/search/lookup":
+			_, _ = rw.Write([]byte(`{
+				"type": "LOOKUP", "metric": "cpu", "limit": 10, "totalResults": 2,
+				"results": [
+					{"metric": "cpu", "tags": {"host": "a"}, "tsuid": "000001000001000001"},
+					{"metric": "cpu", "tags": {"host": "b"}, "tsuid": "000001000001000002"}
+				]
+			}`))
+		default:
+			rw.WriteHeader(http.StatusNotFound)
+		}
+	}))
+	t.Cleanup(tsdb.Close)
+
+	service := ProvideService(httpclient.NewProvider())
+	callResource := func(t *testing.T, path string) *backend.CallResourceResponse {
+		t.Helper()
+		params = nil
+
+		sender := &fakeCallResourceResponseSender{}
+		err := service.CallResource(context.Background(), &backend.CallResourceRequest{
+			PluginContext: backend.PluginContext{
+				DataSourceInstanceSettings: &backend.DataSourceInstanceSettings{
+					ID:       1,
+					URL:      tsdb.URL,
+					JSONData: []byte(`{"lookupLimit": 50}`),
+				},
+			},
+			Method: http.MethodGet,
+			Path:   strings.SplitN(path, "?", 2)[0],
+			URL:    path,
+		}, sender)
+		require.NoError(t, err)
+		require.NotNil(t, sender.response)
+		return sender.response
+	}
+
+	t.Run("should return suggestions", func(t *testing.T) {
+		resp := callResource(t, "api/suggest?type=metrics&q=cpu")
+		require.Equal(t, http.StatusOK, resp.Status, string(resp.Body))
+		assert.JSONEq(t, `["cpu.system", "cpu.user"]`, string(resp.Body))
+
+		require.Len(t, params, 1)
+		assert.Equal(t, "metrics", params[0].Get("type"))
+		assert.Equal(t, "cpu", params[0].Get("q"))
+		assert.Equal(t, "50", params[0].Get("max"))
+	})
+
+	t.Run("should not return suggestions of invalid type", func(t *testing.T) {
+		resp := callResource(t, "api/suggest?type=series")
+		require.Equal(t, http.StatusBadRequest, resp.Status)
+		assert.JSONEq(t, `{"message": "invalid suggest type \"series\""}`, string(resp.Body))
+		assert.Empty(t, params)
+	})
+
+	t.Run("should not pass on authentication errors of OpenTSDB", func(t *testing.T) {
+		resp := callResource(t, "api/suggest?type=metrics&q=denied")
+		require.Equal(t, http.StatusBadGateway, resp.Status)
+		assert.JSONEq(t, `{"message": "OpenTSDB request failed, status: 401 Unauthorized"}`, string(resp.Body))
+	})
+
+	t.Run("should pass on client errors of OpenTSDB", func(t *testing.T) {
+		resp := callResource(t, "api/suggest?type=metrics&q=invalid")
+		require.Equal(t, http.StatusBadRequest, resp.Status)
+	})
+
+	t.Run("should look up time series", func(t *testing.T) {
+		resp := callResource(t, "api/search/lookup?m=cpu%7Bhost%3D*%7D&limit=10")
+		require.Equal(t, http.StatusOK, resp.Status, string(resp.Body))
+		assert.Contains(t, string(resp.Body), `"tags":{"host":"b"}`)
+
+		require.Len(t, params, 1)
+		assert.Equal(t, "cpu{host=*}", params[0].Get("m"))
+		assert.Equal(t, "10", params[0].Get("limit"))
+	})
+
+	t.Run("should not look up without metric", func(t *testing.T) {
+		resp := callResource(t, "api/search/lookup")
+		require.Equal(t, http.StatusBadRequest, resp.Status)
+		assert.Empty(t, params)
+	})
+}
+
+type fakeCallResourceResponseSender struct {
+	response *backend.CallResourceResponse
+}
+
+func (s *fakeCallResourceResponseSender) Send(resp *backend.CallResourceResponse) error {
+	s.response = resp
+	return nil
+}
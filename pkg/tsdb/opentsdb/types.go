@@ -1,13 +1,46 @@
 package opentsdb
 
 type OpenTsdbQuery struct {
-	Start   int64                    `json:"start"`
-	End     int64                    `json:"end"`
-	Queries []map[string]interface{} `json:"queries"`
+	Start             int64                    `json:"start"`
+	End               int64                    `json:"end"`
+	Queries           []map[string]interface{} `json:"queries"`
+	MsResolution      bool                     `json:"msResolution,omitempty"`
+	ShowQuery         bool                     `json:"showQuery,omitempty"`
+	GlobalAnnotations bool                     `json:"globalAnnotations,omitempty"`
 }
 
 type OpenTsdbResponse struct {
-	Metric     string             `json:"metric"`
-	Tags       map[string]string  `json:"tags"`
-	DataPoints map[string]float64 `json:"dps"`
+	Metric     string              `json:"metric"`
+	Tags       map[string]string   `json:"tags"`
+	DataPoints map[string]*float64 `json:"dps"`
+	// Query is only returned with the showQuery option, supported since OpenTSDB 2.3
+	Query             *OpenTsdbResponseQuery `json:"query,omitempty"`
+	Annotations       []OpenTsdbAnnotation   `json:"annotations,omitempty"`
+	GlobalAnnotations []OpenTsdbAnnotation   `json:"globalAnnotations,omitempty"`
+}
+
+type OpenTsdbResponseQuery struct {
+	Index int `json:"index"`
+}
+
+type OpenTsdbAnnotation struct {
+	Description string `json:"description"`
+	Notes       string `json:"notes"`
+	// StartTime and EndTime are Unix epoch timestamps in seconds
+	StartTime float64 `json:"startTime"`
+	EndTime   float64 `json:"endTime"`
+}
+
+type OpenTsdbLookupResponse struct {
+	Type         string                 `json:"type"`
+	Metric       string                 `json:"metric"`
+	Limit        int                    `json:"limit"`
+	TotalResults int                    `json:"totalResults"`
+	Results      []OpenTsdbLookupResult `json:"results"`
+}
+
+type OpenTsdbLookupResult struct {
+	Metric string            `json:"metric"`
+	Tags   map[string]string `json:"tags"`
+	TSUID  string            `json:"tsuid"`
 }
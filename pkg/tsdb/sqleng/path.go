@@ -0,0 +1,53 @@
+package sqleng
+
+import (
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"github.com/grafana/grafana/pkg/infra/log"
+)
+
+var (
+	ErrPathMissing    = errors.New("path of the database file is missing")
+	ErrPathNotAllowed = errors.New("path of the database file is not allowed")
+)
+
+// ResolveAllowedPath returns the absolute path of the database file of a data source that opens local files,
+// with symbolic links evaluated, if it is one of the allowed paths or is in one of the allowed directories.
+func ResolveAllowedPath(path string, allowedPaths []string, logger log.Logger) (string, error) {
+	if path == "" {
+		return "", ErrPathMissing
+	}
+
+	resolved, err := evalPath(path)
+	if err != nil {
+		if errors.Is(err, os.ErrNotExist) {
+			return "", fmt.Errorf("database file %q does not exist", path)
+		}
+		return "", fmt.Errorf("failed to resolve path of the database file: %w", err)
+	}
+
+	for _, p := range allowedPaths {
+		allowed, err := evalPath(p)
+		if err != nil {
+			logger.Warn("Failed to resolve allowed path of the data source", "path", p, "error", err)
+			continue
+		}
+		rel, err := filepath.Rel(allowed, resolved)
+		if err == nil && rel != ".." && !strings.HasPrefix(rel, ".."+string(filepath.Separator)) {
+			return resolved, nil
+		}
+	}
+	return "", ErrPathNotAllowed
+}
+
+func evalPath(path string) (string, error) {
+	abs, err := filepath.Abs(path)
+	if err != nil {
+		return "", err
+	}
+	return filepath.EvalSymlinks(abs)
+}
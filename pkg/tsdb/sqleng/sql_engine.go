@@ -41,6 +41,12 @@ type SqlQueryResultTransformer interface {
 	GetConverterList() []sqlutil.StringConverter
 }
 
+// SqlQueryResultConverters can be implemented by a SqlQueryResultTransformer to convert query results with
+// converters that are not string converters, for example for drivers that don't know the types of columns.
+type SqlQueryResultConverters interface {
+	GetConverters() []sqlutil.Converter
+}
+
 var sqlIntervalCalculator = intervalv2.NewCalculator()
 
 // NewXormEngine is an xorm.Engine factory, that can be stubbed by tests.
@@ -172,6 +178,11 @@ func (e *DataSourceHandler) Dispose() {
 	e.log.Debug("Engine disposed")
 }
 
+// Ping checks that the database of the data source can be reached.
+func (e *DataSourceHandler) Ping(ctx context.Context) error {
+	return e.engine.DB().PingContext(ctx)
+}
+
 func (e *DataSourceHandler) QueryData(ctx context.Context, req *backend.QueryDataRequest) (*backend.QueryDataResponse, error) {
 	result := backend.NewQueryDataResponse()
 	ch := make(chan DBDataResponse, len(req.Queries))
@@ -283,7 +294,15 @@ func (e *DataSourceHandler) executeQuery(query backend.DataQuery, wg *sync.WaitG
 
 	// Convert row.Rows to dataframe
 	stringConverters := e.queryResultTransformer.GetConverterList()
-	frame, err := sqlutil.FrameFromRows(rows.Rows, e.rowLimit, sqlutil.ToConverters(stringConverters...)...)
+	converters := sqlutil.ToConverters(stringConverters...)
+	if c, ok := e.queryResultTransformer.(SqlQueryResultConverters); ok {
+		converters = append(converters, c.GetConverters()...)
+	}
+	frame, err := sqlutil.FrameFromRows(rows.Rows, e.rowLimit, converters...)
+	if err == nil {
+		// The dynamic conversion of the SDK does not check for errors that happened while iterating the rows.
+		err = rows.Err()
+	}
 	if err != nil {
 		errAppendDebug("convert frame from rows error", err, interpolatedQuery)
 		return
@@ -0,0 +1,185 @@
+package sqlite
+
+import (
+	"context"
+	"database/sql"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/grafana/grafana-plugin-sdk-go/backend"
+	"github.com/grafana/grafana-plugin-sdk-go/data"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/grafana/grafana/pkg/setting"
+	"github.com/grafana/grafana/pkg/tsdb/sqleng"
+)
+
+func TestResolvePath(t *testing.T) {
+	dir := t.TempDir()
+	allowedDir := filepath.Join(dir, "allowed")
+	require.NoError(t, os.Mkdir(allowedDir, 0750))
+	allowedFile := filepath.Join(allowedDir, "metrics.db")
+	otherFile := filepath.Join(dir, "other.db")
+	for _, f := range []string{allowedFile, otherFile} {
+		require.NoError(t, os.WriteFile(f, nil, 0600))
+	}
+	link := filepath.Join(allowedDir, "link.db")
+	require.NoError(t, os.Symlink(otherFile, link))
+
+	t.Run("should allow file in allowed directory", func(t *testing.T) {
+		path, err := resolvePath(allowedFile, []string{allowedDir})
+		require.NoError(t, err)
+		require.Equal(t, allowedFile, path)
+	})
+
+	t.Run("should allow allowed file", func(t *testing.T) {
+		path, err := resolvePath(otherFile, []string{allowedDir, otherFile})
+		require.NoError(t, err)
+		require.Equal(t, otherFile, path)
+	})
+
+	t.Run("should not allow file outside of allowed paths", func(t *testing.T) {
+		_, err := resolvePath(otherFile, []string{allowedDir})
+		require.ErrorIs(t, err, errPathNotAllowed)
+
+		_, err = resolvePath(filepath.Join(allowedDir, "..", "other.db"), []string{allowedDir})
+		require.ErrorIs(t, err, errPathNotAllowed)
+	})
+
+	t.Run("should not allow symbolic link to file outside of allowed paths", func(t *testing.T) {
+		_, err := resolvePath(link, []string{allowedDir})
+		require.ErrorIs(t, err, errPathNotAllowed)
+	})
+
+	t.Run("should not allow any file without allowed paths", func(t *testing.T) {
+		_, err := resolvePath(allowedFile, nil)
+		require.ErrorIs(t, err, errPathNotAllowed)
+	})
+
+	t.Run("should fail when path is missing", func(t *testing.T) {
+		_, err := resolvePath("", []string{allowedDir})
+		require.ErrorIs(t, err, sqleng.ErrPathMissing)
+	})
+
+	t.Run("should fail when file does not exist", func(t *testing.T) {
+		_, err := resolvePath(filepath.Join(allowedDir, "missing.db"), []string{allowedDir})
+		require.Error(t, err)
+		require.Contains(t, err.Error(), "does not exist")
+	})
+}
+
+func TestSQLite(t *testing.T) {
+	dir := t.TempDir()
+	path := filepath.Join(dir, "metrics.db")
+
+	db, err := sql.Open("sqlite3", path)
+	require.NoError(t, err)
+	_, err = db.Exec(`
+		CREATE TABLE metric (time DATETIME, host TEXT, value REAL);
+		INSERT INTO metric VALUES
+			('2018-03-15 13:00:00', 'server1', 1.5),
+			('2018-03-15 13:00:00', 'server2', 3.5),
+			('2018-03-15 13:01:00', 'server1', 2.5),
+			('2018-03-15 13:01:00', 'server2', NULL);`)
+	require.NoError(t, err)
+	require.NoError(t, db.Close())
+
+	cfg := setting.NewCfg()
+	cfg.DataProxyRowLimit = 1000
+	cfg.SQLiteDataSourceAllowedPaths = []string{dir}
+	s := ProvideService(cfg)
+
+	pluginCtx := backend.PluginContext{
+		DataSourceInstanceSettings: &backend.DataSourceInstanceSettings{
+			ID:       1,
+			Database: path,
+			JSONData: []byte(`{}`),
+		},
+	}
+	timeRange := backend.TimeRange{
+		From: time.Date(2018, 3, 15, 12, 0, 0, 0, time.UTC),
+		To:   time.Date(2018, 3, 15, 14, 0, 0, 0, time.UTC),
+	}
+	query := func(t *testing.T, rawSQL, format string) backend.DataResponse {
+		t.Helper()
+		resp, err := s.QueryData(context.Background(), &backend.QueryDataRequest{
+			PluginContext: pluginCtx,
+			Queries: []backend.DataQuery{{
+				RefID:     "A",
+				JSON:      []byte(`{"rawSql": "` + rawSQL + `", "format": "` + format + `"}`),
+				TimeRange: timeRange,
+			}},
+		})
+		require.NoError(t, err)
+		return resp.Responses["A"]
+	}
+
+	t.Run("should return table", func(t *testing.T) {
+		resp := query(t, "SELECT host, count(*) AS c, max(value) AS max FROM metric WHERE $__timeFilter(time) GROUP BY host ORDER BY host", "table")
+		require.NoError(t, resp.Error)
+		require.Len(t, resp.Frames, 1)
+
+		frame := resp.Frames[0]
+		require.Equal(t, 2, frame.Rows())
+		require.Len(t, frame.Fields, 3)
+		assert.Equal(t, data.FieldTypeNullableString, frame.Fields[0].Type())
+		assert.Equal(t, "server1", *frame.Fields[0].At(0).(*string))
+		assert.Equal(t, data.FieldTypeNullableFloat64, frame.Fields[1].Type())
+		assert.Equal(t, 2.0, *frame.Fields[1].At(0).(*float64))
+		assert.Equal(t, 3.5, *frame.Fields[2].At(1).(*float64))
+	})
+
+	t.Run("should return time series", func(t *testing.T) {
+		resp := query(t, "SELECT $__timeGroupAlias(time, '1m'), host AS metric, avg(value) AS value FROM metric GROUP BY 1, 2 ORDER BY 1", "time_series")
+		require.NoError(t, resp.Error)
+		require.Len(t, resp.Frames, 1)
+
+		frame := resp.Frames[0]
+		require.Equal(t, 2, frame.Rows())
+		require.Len(t, frame.Fields, 3)
+		assert.Equal(t, time.Date(2018, 3, 15, 13, 0, 0, 0, time.UTC), frame.Fields[0].At(0).(time.Time).UTC())
+		assert.Equal(t, "server1", frame.Fields[1].Name)
+		assert.Equal(t, 2.5, *frame.Fields[1].At(1).(*float64))
+		assert.Equal(t, "server2", frame.Fields[2].Name)
+		assert.Nil(t, frame.Fields[2].At(1))
+	})
+
+	t.Run("should not write to the database", func(t *testing.T) {
+		resp := query(t, "CREATE TABLE other (value REAL)", "table")
+		require.Error(t, resp.Error)
+		require.Contains(t, resp.Error.Error(), "readonly database")
+	})
+
+	t.Run("should not attach other databases", func(t *testing.T) {
+		resp := query(t, "ATTACH DATABASE '"+filepath.Join(dir, "other.db")+"' AS other", "table")
+		require.Error(t, resp.Error)
+		require.Contains(t, resp.Error.Error(), "not authorized")
+	})
+
+	t.Run("should check health", func(t *testing.T) {
+		res, err := s.CheckHealth(context.Background(), &backend.CheckHealthRequest{PluginContext: pluginCtx})
+		require.NoError(t, err)
+		assert.Equal(t, backend.HealthStatusOk, res.Status)
+	})
+
+	t.Run("should fail health check when path is not allowed", func(t *testing.T) {
+		other := filepath.Join(t.TempDir(), "other.db")
+		require.NoError(t, os.WriteFile(other, nil, 0600))
+
+		res, err := s.CheckHealth(context.Background(), &backend.CheckHealthRequest{
+			PluginContext: backend.PluginContext{
+				DataSourceInstanceSettings: &backend.DataSourceInstanceSettings{
+					ID:       2,
+					Database: other,
+					JSONData: []byte(`{}`),
+				},
+			},
+		})
+		require.NoError(t, err)
+		assert.Equal(t, backend.HealthStatusError, res.Status)
+		assert.Equal(t, errPathNotAllowed.Error(), res.Message)
+	})
+}
@@ -4,7 +4,7 @@ import { RegisterOptions, useFormContext } from 'react-hook-form';
 
 import { GrafanaTheme2, SelectableValue } from '@grafana/data';
 import { Stack } from '@grafana/experimental';
-import { Button, Card, Field, InlineLabel, Input, InputControl, useStyles2 } from '@grafana/ui';
+import { Button, Card, Checkbox, Field, InlineLabel, Input, InputControl, useStyles2 } from '@grafana/ui';
 import { RulerRuleDTO, RulerRuleGroupDTO, RulerRulesConfigDTO } from 'app/types/unified-alerting-dto';
 
 import { logInfo, LogMessages } from '../../Analytics';
@@ -213,6 +213,7 @@ export function GrafanaEvaluationBehavior({
 }) {
   const styles = useStyles2(getStyles);
   const [showErrorHandling, setShowErrorHandling] = useState(false);
+  const { register } = useFormContext<RuleFormValues>();
 
   return (
     // TODO remove "and alert condition" for recording rules
@@ -224,6 +225,12 @@ export function GrafanaEvaluationBehavior({
           evaluateEvery={evaluateEvery}
         />
         <ForInput evaluateEvery={evaluateEvery} />
+        <Field
+          htmlFor="pause-alert-checkbox"
+          description="Paused rules are not evaluated and don't produce alert instances."
+        >
+          <Checkbox id="pause-alert-checkbox" label="Pause evaluation" {...register('isPaused')} />
+        </Field>
       </Stack>
       <CollapseToggle
         isCollapsed={!showErrorHandling}
@@ -28,6 +28,7 @@ export interface RuleFormValues {
   execErrState: GrafanaAlertStateDecision;
   folder: RuleForm | null;
   evaluateFor: string;
+  isPaused?: boolean;
 
   // cortex / loki rules
   namespace: string;
@@ -1,8 +1,9 @@
 import { PromQuery } from 'app/plugins/datasource/prometheus/types';
+import { RulerGrafanaRuleDTO } from 'app/types/unified-alerting-dto';
 
 import { RuleFormValues } from '../types/rule-form';
 
-import { formValuesToRulerGrafanaRuleDTO, getDefaultFormValues } from './rule-form';
+import { formValuesToRulerGrafanaRuleDTO, getDefaultFormValues, rulerRuleToFormValues } from './rule-form';
 
 describe('formValuesToRulerGrafanaRuleDTO', () => {
   it('should correctly convert rule form values', () => {
@@ -33,4 +34,21 @@ describe('formValuesToRulerGrafanaRuleDTO', () => {
 
     expect(formValuesToRulerGrafanaRuleDTO(values)).toMatchSnapshot();
   });
+
+  it('should keep the pause of the rule', () => {
+    const rule = formValuesToRulerGrafanaRuleDTO({ ...getDefaultFormValues(), condition: 'A', isPaused: true });
+    expect(rule.grafana_alert.is_paused).toBe(true);
+
+    const values = rulerRuleToFormValues({
+      ruleSourceName: 'grafana',
+      namespace: 'folder',
+      group: { name: 'group', rules: [] },
+      rule: {
+        ...rule,
+        grafana_alert: { ...rule.grafana_alert, uid: 'uid', namespace_uid: 'folder-uid', namespace_id: 1 },
+      } as RulerGrafanaRuleDTO,
+    });
+    expect(values.isPaused).toBe(true);
+    expect(formValuesToRulerGrafanaRuleDTO(values).grafana_alert.is_paused).toBe(true);
+  });
 });
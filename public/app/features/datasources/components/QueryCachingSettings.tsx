@@ -0,0 +1,50 @@
+import React from 'react';
+
+import { DataSourceSettings } from '@grafana/data';
+import { FieldSet, InlineField, InlineSwitch, Input } from '@grafana/ui';
+
+export interface Props {
+  dataSource: DataSourceSettings;
+  onOptionsChange: (dataSource: DataSourceSettings) => void;
+}
+
+export function QueryCachingSettings({ dataSource, onOptionsChange }: Props) {
+  const jsonData = dataSource.jsonData;
+
+  const onJsonDataChange = (property: string, value: boolean | string) => {
+    onOptionsChange({ ...dataSource, jsonData: { ...jsonData, [property]: value } });
+  };
+
+  return (
+    <FieldSet label="Query caching">
+      <InlineField
+        label="Enabled"
+        labelWidth={20}
+        tooltip="Cache the responses of queries, so that panels sending the same queries within the TTL don't query the data source again."
+      >
+        <InlineSwitch
+          id="query-caching-enabled"
+          value={jsonData.queryCachingEnabled ?? false}
+          onChange={(event: React.FormEvent<HTMLInputElement>) => {
+            onJsonDataChange('queryCachingEnabled', event.currentTarget.checked);
+          }}
+        />
+      </InlineField>
+      {jsonData.queryCachingEnabled && (
+        <InlineField
+          label="TTL"
+          labelWidth={20}
+          tooltip="For how long the responses are cached, for example 5m. Leave empty to use the default TTL of the Grafana server."
+        >
+          <Input
+            id="query-caching-ttl"
+            width={20}
+            placeholder="1m"
+            value={jsonData.queryCachingTTL ?? ''}
+            onChange={(event) => onJsonDataChange('queryCachingTTL', event.currentTarget.value)}
+          />
+        </InlineField>
+      )}
+    </FieldSet>
+  );
+}
@@ -0,0 +1,58 @@
+import { DataSourceInstanceSettings } from '@grafana/data';
+import { RAQBFieldTypes, SQLQuery, SQLSelectableValue } from 'app/features/plugins/sql/types';
+
+import { SQLiteDatasource } from '../sqlite/datasource';
+
+import { getSchema, showTables } from './duckdbMetaQuery';
+import { DuckDBOptions } from './types';
+
+// DuckDBDatasource reuses the SQL editor of the SQLite data source, and only differs by how it lists the tables and
+// the columns of the database.
+export class DuckDBDatasource extends SQLiteDatasource {
+  constructor(instanceSettings: DataSourceInstanceSettings<DuckDBOptions>) {
+    super(instanceSettings);
+  }
+
+  async fetchTables(): Promise<string[]> {
+    const tables = await this.runSql<{ table: string[] }>(showTables(), { refId: 'tables' });
+    return tables.fields.table.values.toArray().flat();
+  }
+
+  async fetchFields(query: SQLQuery): Promise<SQLSelectableValue[]> {
+    const schema = await this.runSql<{ column: string; type: string }>(getSchema(query.table), { refId: 'columns' });
+    const result: SQLSelectableValue[] = [];
+    for (let i = 0; i < schema.length; i++) {
+      const column = schema.fields.column.values.get(i);
+      const type = schema.fields.type.values.get(i) ?? '';
+      result.push({ label: column, value: column, type, ...getFieldConfig(type) });
+    }
+    return result;
+  }
+}
+
+function getFieldConfig(type: string): { raqbFieldType: RAQBFieldTypes; icon: string } {
+  switch (type.replace(/\(.*\)$/, '')) {
+    case 'BOOLEAN':
+      return { raqbFieldType: 'boolean', icon: 'toggle-off' };
+    case 'DATE':
+    case 'TIME':
+    case 'TIMESTAMP':
+    case 'TIMESTAMP WITH TIME ZONE':
+      return { raqbFieldType: 'datetime', icon: 'clock-nine' };
+    case 'TINYINT':
+    case 'SMALLINT':
+    case 'INTEGER':
+    case 'BIGINT':
+    case 'HUGEINT':
+    case 'UTINYINT':
+    case 'USMALLINT':
+    case 'UINTEGER':
+    case 'UBIGINT':
+    case 'FLOAT':
+    case 'DOUBLE':
+    case 'DECIMAL':
+      return { raqbFieldType: 'number', icon: 'calculator-alt' };
+    default:
+      return { raqbFieldType: 'text', icon: 'text' };
+  }
+}
@@ -0,0 +1,15 @@
+export function showTables() {
+  return `SELECT table_name AS "table" FROM information_schema.tables
+    WHERE table_schema = current_schema()
+    ORDER BY table_name`;
+}
+
+export function getSchema(table?: string) {
+  return `SELECT column_name AS "column", data_type AS "type" FROM information_schema.columns
+    WHERE table_schema = current_schema() AND table_name = ${quoteLiteral(table ?? '')}
+    ORDER BY ordinal_position`;
+}
+
+function quoteLiteral(value: string) {
+  return "'" + value.replace(/'/g, "''") + "'";
+}
@@ -0,0 +1,11 @@
+import { DataSourcePlugin } from '@grafana/data';
+import { SQLQuery } from 'app/features/plugins/sql/types';
+
+import { QueryEditor } from './QueryEditor';
+import { ConfigurationEditor } from './configuration/ConfigurationEditor';
+import { DuckDBDatasource } from './datasource';
+import { DuckDBOptions } from './types';
+
+export const plugin = new DataSourcePlugin<DuckDBDatasource, SQLQuery, DuckDBOptions>(DuckDBDatasource)
+  .setQueryEditor(QueryEditor)
+  .setConfigEditor(ConfigurationEditor);
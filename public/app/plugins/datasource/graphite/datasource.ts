@@ -71,6 +71,7 @@ export class GraphiteDatasource
 {
   basicAuth: string;
   url: string;
+  access: string;
   name: string;
   graphiteVersion: any;
   supportsTags: boolean;
@@ -88,6 +89,7 @@ export class GraphiteDatasource
     super(instanceSettings);
     this.basicAuth = instanceSettings.basicAuth;
     this.url = instanceSettings.url;
+    this.access = instanceSettings.access;
     this.name = instanceSettings.name;
     // graphiteVersion is set when a datasource is created but it hadn't been set in the past so we're
     // still falling back to the default behavior here for backwards compatibility (see also #17429)
@@ -612,7 +614,7 @@ export class GraphiteDatasource
     }
 
     return lastValueFrom(
-      this.doGraphiteRequest(httpOptions).pipe(
+      this.doResourceRequest(httpOptions).pipe(
         map((results: any) => {
           return _map(results.data, (metric) => {
             return {
@@ -652,7 +654,7 @@ export class GraphiteDatasource
     }
 
     return lastValueFrom(
-      this.doGraphiteRequest(httpOptions).pipe(
+      this.doResourceRequest(httpOptions).pipe(
         map((results: any) => {
           return _map(results.data.results, (metric) => {
             return {
@@ -681,7 +683,7 @@ export class GraphiteDatasource
     }
 
     return lastValueFrom(
-      this.doGraphiteRequest(httpOptions).pipe(
+      this.doResourceRequest(httpOptions).pipe(
         map((results: any) => {
           return _map(results.data, (tag) => {
             return {
@@ -708,7 +710,7 @@ export class GraphiteDatasource
     }
 
     return lastValueFrom(
-      this.doGraphiteRequest(httpOptions).pipe(
+      this.doResourceRequest(httpOptions).pipe(
         map((results: any) => {
           if (results.data && results.data.values) {
             return _map(results.data.values, (value) => {
@@ -835,7 +837,7 @@ export class GraphiteDatasource
     };
 
     return lastValueFrom(
-      this.doGraphiteRequest(httpOptions).pipe(
+      this.doResourceRequest(httpOptions).pipe(
         map((results: any) => {
           // Fix for a Graphite bug: https://github.com/graphite-project/graphite-web/issues/2609
           // There is a fix for it https://github.com/graphite-project/graphite-web/pull/2612 but
@@ -906,6 +908,36 @@ export class GraphiteDatasource
       );
   }
 
+  /**
+   * Sends the request to the resource handlers of the Graphite backend, which use the same authentication and
+   * headers as queries. Data sources with browser access keep sending their requests to Graphite directly.
+   */
+  doResourceRequest(options: {
+    method?: string;
+    url: string;
+    params?: any;
+    data?: any;
+    headers?: any;
+    requestId?: any;
+    responseType?: string;
+  }) {
+    if (this.access === 'direct') {
+      return this.doGraphiteRequest(options);
+    }
+
+    return getBackendSrv()
+      .fetch({
+        ...options,
+        url: `/api/datasources/${this.id}/resources${options.url}`,
+        inspect: { type: 'graphite' },
+      })
+      .pipe(
+        catchError((err: any) => {
+          return throwError(reduceError(err));
+        })
+      );
+  }
+
   buildGraphiteParams(options: any, scopedVars?: ScopedVars): string[] {
     const graphiteOptions = ['from', 'until', 'rawData', 'format', 'maxDataPoints', 'cacheTimeout'];
     const cleanOptions = [],
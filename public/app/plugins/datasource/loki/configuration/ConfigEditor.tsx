@@ -7,6 +7,7 @@ import { LokiOptions } from '../types';
 
 import { DerivedFields } from './DerivedFields';
 import { MaxLinesField } from './MaxLinesField';
+import { QuerySplittingSettings } from './QuerySplittingSettings';
 
 export type Props = DataSourcePluginOptionsEditorProps<LokiOptions>;
 
@@ -24,6 +25,8 @@ const makeJsonUpdater =
 
 const setMaxLines = makeJsonUpdater('maxLines');
 const setDerivedFields = makeJsonUpdater('derivedFields');
+const setQuerySplitDuration = makeJsonUpdater('querySplitDuration');
+const setQuerySplitConcurrency = makeJsonUpdater('querySplitConcurrency');
 
 export const ConfigEditor = (props: Props) => {
   const { options, onOptionsChange } = props;
@@ -50,6 +53,13 @@ export const ConfigEditor = (props: Props) => {
         </div>
       </div>
 
+      <QuerySplittingSettings
+        duration={options.jsonData.querySplitDuration || ''}
+        concurrency={options.jsonData.querySplitConcurrency}
+        onDurationChange={(value) => onOptionsChange(setQuerySplitDuration(options, value))}
+        onConcurrencyChange={(value) => onOptionsChange(setQuerySplitConcurrency(options, value))}
+      />
+
       <DerivedFields
         value={options.jsonData.derivedFields}
         onChange={(value) => onOptionsChange(setDerivedFields(options, value))}
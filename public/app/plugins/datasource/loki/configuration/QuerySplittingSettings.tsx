@@ -0,0 +1,73 @@
+import React from 'react';
+
+import { LegacyForms } from '@grafana/ui';
+const { FormField } = LegacyForms;
+
+type Props = {
+  duration: string;
+  concurrency?: number;
+  onDurationChange: (value: string) => void;
+  onConcurrencyChange: (value?: number) => void;
+};
+
+export const QuerySplittingSettings = (props: Props) => {
+  const { duration, concurrency, onDurationChange, onConcurrencyChange } = props;
+  return (
+    <>
+      <h3 className="page-heading">Query splitting</h3>
+      <div className="gf-form-group">
+        <div className="gf-form-inline">
+          <div className="gf-form">
+            <FormField
+              label="Split duration"
+              labelWidth={11}
+              inputWidth={20}
+              inputEl={
+                <input
+                  type="text"
+                  className="gf-form-input width-8 gf-form-input--has-help-icon"
+                  value={duration}
+                  onChange={(event) => onDurationChange(event.currentTarget.value)}
+                  spellCheck={false}
+                  placeholder="1d"
+                  aria-label="Query split duration"
+                />
+              }
+              tooltip={
+                <>
+                  Range queries over a longer time range are split into queries of at most this duration, for example
+                  1d or 12h. The split queries are sent to Loki in parallel and their results are merged. Leave empty
+                  to not split queries.
+                </>
+              }
+            />
+          </div>
+        </div>
+        <div className="gf-form-inline">
+          <div className="gf-form">
+            <FormField
+              label="Concurrency"
+              labelWidth={11}
+              inputWidth={20}
+              inputEl={
+                <input
+                  type="number"
+                  className="gf-form-input width-8 gf-form-input--has-help-icon"
+                  value={concurrency ?? ''}
+                  onChange={(event) => {
+                    const value = parseInt(event.currentTarget.value, 10);
+                    onConcurrencyChange(isNaN(value) ? undefined : value);
+                  }}
+                  min={1}
+                  placeholder="4"
+                  aria-label="Query split concurrency"
+                />
+              }
+              tooltip={<>The maximum number of split queries of a query that are sent to Loki at the same time.</>}
+            />
+          </div>
+        </div>
+      </div>
+    </>
+  );
+};
@@ -56,6 +56,8 @@ export interface LokiOptions extends DataSourceJsonData {
   derivedFields?: DerivedFieldConfig[];
   alertmanager?: string;
   keepCookies?: string[];
+  querySplitDuration?: string;
+  querySplitConcurrency?: number;
 }
 
 export interface LokiStats {
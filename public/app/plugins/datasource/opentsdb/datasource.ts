@@ -18,6 +18,7 @@ import { catchError, map } from 'rxjs/operators';
 
 import {
   AnnotationEvent,
+  DataFrameView,
   DataQueryRequest,
   DataQueryResponse,
   DataSourceApi,
@@ -25,7 +26,7 @@ import {
   ScopedVars,
   toDataFrame,
 } from '@grafana/data';
-import { FetchResponse, getBackendSrv } from '@grafana/runtime';
+import { BackendDataSourceResponse, FetchResponse, getBackendSrv, toDataQueryResponse } from '@grafana/runtime';
 import { getTemplateSrv, TemplateSrv } from 'app/features/templating/template_srv';
 
 import { AnnotationEditor } from './components/AnnotationEditor';
@@ -35,6 +36,7 @@ import { OpenTsdbFilter, OpenTsdbOptions, OpenTsdbQuery } from './types';
 export default class OpenTsDatasource extends DataSourceApi<OpenTsdbQuery, OpenTsdbOptions> {
   type: any;
   url: any;
+  access: any;
   name: any;
   withCredentials: any;
   basicAuth: any;
@@ -50,6 +52,7 @@ export default class OpenTsDatasource extends DataSourceApi<OpenTsdbQuery, OpenT
     super(instanceSettings);
     this.type = 'opentsdb';
     this.url = instanceSettings.url;
+    this.access = instanceSettings.access;
     this.name = instanceSettings.name;
     this.withCredentials = instanceSettings.withCredentials;
     this.basicAuth = instanceSettings.basicAuth;
@@ -157,6 +160,10 @@ export default class OpenTsDatasource extends DataSourceApi<OpenTsdbQuery, OpenT
   }
 
   annotationEvent(options: DataQueryRequest, annotation: OpenTsdbQuery): Promise<AnnotationEvent[]> {
+    if (this.access !== 'direct') {
+      return this.backendAnnotationEvent(options, annotation);
+    }
+
     const start = this.convertToTSDBTime(options.range.raw.from, false, options.timezone);
     const end = this.convertToTSDBTime(options.range.raw.to, true, options.timezone);
     const qs = [];
@@ -192,6 +199,54 @@ export default class OpenTsDatasource extends DataSourceApi<OpenTsdbQuery, OpenT
     );
   }
 
+  /**
+   * Runs the annotation query in the OpenTSDB backend, which returns the annotations of the metric, or the global
+   * annotations, as a frame of events.
+   */
+  backendAnnotationEvent(options: DataQueryRequest, annotation: OpenTsdbQuery): Promise<AnnotationEvent[]> {
+    const target = {
+      refId: annotation.refId || 'Anno',
+      datasource: this.getRef(),
+      fromAnnotations: true,
+      target: annotation.target,
+      isGlobal: annotation.isGlobal,
+    };
+
+    return lastValueFrom(
+      getBackendSrv()
+        .fetch<BackendDataSourceResponse>({
+          url: '/api/ds/query',
+          method: 'POST',
+          data: {
+            from: options.range.from.valueOf().toString(),
+            to: options.range.to.valueOf().toString(),
+            queries: [target],
+          },
+        })
+        .pipe(
+          map((res) => {
+            const response = toDataQueryResponse(res, [target]);
+            if (response.error) {
+              throw response.error;
+            }
+
+            const eventList: AnnotationEvent[] = [];
+            for (const frame of response.data) {
+              new DataFrameView(frame).forEach((row) => {
+                eventList.push({
+                  text: row.text,
+                  time: row.time,
+                  timeEnd: row.timeEnd ?? undefined,
+                  annotation: annotation,
+                });
+              });
+            }
+            return eventList;
+          })
+        )
+    );
+  }
+
   targetContainsTemplate(target: any) {
     if (target.filters && target.filters.length > 0) {
       for (let i = 0; i < target.filters.length; i++) {
@@ -257,7 +312,7 @@ export default class OpenTsDatasource extends DataSourceApi<OpenTsdbQuery, OpenT
   }
 
   _performSuggestQuery(query: string, type: string): Observable<any> {
-    return this._get('/api/suggest', { type, q: query, max: this.lookupLimit }).pipe(
+    return this._getResource('/api/suggest', { type, q: query, max: this.lookupLimit }).pipe(
       map((result: any) => {
         return result.data;
       })
@@ -281,7 +336,7 @@ export default class OpenTsDatasource extends DataSourceApi<OpenTsdbQuery, OpenT
 
     const m = metric + '{' + keysQuery + '}';
 
-    return this._get('/api/search/lookup', { m: m, limit: this.lookupLimit }).pipe(
+    return this._getResource('/api/search/lookup', { m: m, limit: this.lookupLimit }).pipe(
       map((result: any) => {
         result = result.data.results;
         const tagvs: any[] = [];
@@ -300,7 +355,7 @@ export default class OpenTsDatasource extends DataSourceApi<OpenTsdbQuery, OpenT
       return of([]);
     }
 
-    return this._get('/api/search/lookup', { m: metric, limit: 1000 }).pipe(
+    return this._getResource('/api/search/lookup', { m: metric, limit: 1000 }).pipe(
       map((result: any) => {
         result = result.data.results;
         const tagks: any[] = [];
@@ -331,6 +386,25 @@ export default class OpenTsDatasource extends DataSourceApi<OpenTsdbQuery, OpenT
     return getBackendSrv().fetch(options);
   }
 
+  /**
+   * Sends the request to the resource handlers of the OpenTSDB backend, which use the same authentication and
+   * headers as queries. Data sources with browser access keep sending their requests to OpenTSDB directly.
+   */
+  _getResource(
+    relativeUrl: string,
+    params?: { type?: string; q?: string; max?: number; m?: any; limit?: number }
+  ): Observable<FetchResponse> {
+    if (this.access === 'direct') {
+      return this._get(relativeUrl, params);
+    }
+
+    return getBackendSrv().fetch({
+      method: 'GET',
+      url: `/api/datasources/${this.id}/resources${relativeUrl}`,
+      params: params,
+    });
+  }
+
   _addCredentialOptions(options: any) {
     if (this.basicAuth || this.withCredentials) {
       options.withCredentials = true;
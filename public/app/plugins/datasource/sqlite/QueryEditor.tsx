@@ -0,0 +1,14 @@
+import React from 'react';
+
+import { QueryEditorProps } from '@grafana/data';
+import { SqlQueryEditor } from 'app/features/plugins/sql/components/QueryEditor';
+import { SQLQuery } from 'app/features/plugins/sql/types';
+
+import { SQLiteDatasource } from './datasource';
+import { SQLiteOptions } from './types';
+
+const queryHeaderProps = { isDatasetSelectorHidden: true };
+
+export function QueryEditor(props: QueryEditorProps<SQLiteDatasource, SQLQuery, SQLiteOptions>) {
+  return <SqlQueryEditor {...props} queryHeaderProps={queryHeaderProps} />;
+}
@@ -0,0 +1,87 @@
+import React, { SyntheticEvent } from 'react';
+
+import {
+  DataSourcePluginOptionsEditorProps,
+  onUpdateDatasourceJsonDataOption,
+  updateDatasourcePluginJsonDataOption,
+} from '@grafana/data';
+import { Alert, FieldSet, InlineField, Input, Link } from '@grafana/ui';
+import { ConnectionLimits } from 'app/features/plugins/sql/components/configuration/ConnectionLimits';
+
+import { SQLiteOptions } from '../types';
+
+export const ConfigurationEditor = (props: DataSourcePluginOptionsEditorProps<SQLiteOptions>) => {
+  const { options, onOptionsChange } = props;
+  const jsonData = options.jsonData;
+
+  const onDSOptionChanged = (property: keyof SQLiteOptions) => {
+    return (event: SyntheticEvent<HTMLInputElement>) => {
+      onOptionsChange({ ...options, ...{ [property]: event.currentTarget.value } });
+    };
+  };
+
+  const mediumWidth = 20;
+  const shortWidth = 15;
+  const longWidth = 40;
+
+  return (
+    <>
+      <FieldSet label="SQLite Connection" width={400}>
+        <InlineField
+          labelWidth={shortWidth}
+          label="Path"
+          tooltip={
+            <span>
+              Path of the database file on the Grafana server. The path must be allowed with
+              <code>allowed_paths</code> in the <code>[datasources.sqlite]</code> section of the Grafana configuration.
+            </span>
+          }
+        >
+          <Input
+            width={longWidth}
+            name="database"
+            value={options.database || ''}
+            placeholder="/var/lib/grafana/sqlite/metrics.db"
+            onChange={onDSOptionChanged('database')}
+          ></Input>
+        </InlineField>
+      </FieldSet>
+
+      <ConnectionLimits
+        labelWidth={shortWidth}
+        jsonData={jsonData}
+        onPropertyChanged={(property, value) => {
+          updateDatasourcePluginJsonDataOption(props, property, value);
+        }}
+      ></ConnectionLimits>
+
+      <FieldSet label="SQLite details">
+        <InlineField
+          tooltip={
+            <span>
+              A lower limit for the auto group by time interval. Recommended to be set to write frequency, for example
+              <code>1m</code> if your data is written every minute.
+            </span>
+          }
+          labelWidth={mediumWidth}
+          label="Min time interval"
+        >
+          <Input
+            placeholder="1m"
+            value={jsonData.timeInterval || ''}
+            onChange={onUpdateDatasourceJsonDataOption(props, 'timeInterval')}
+          ></Input>
+        </InlineField>
+      </FieldSet>
+
+      <Alert title="Read-only access" severity="info">
+        The database file is opened in read-only mode, and queries can&apos;t attach other database files. Only the
+        files allowed by the Grafana server administrator can be queried. Check out the{' '}
+        <Link rel="noreferrer" target="_blank" href="http://docs.grafana.org/features/datasources/sqlite/">
+          SQLite Data Source Docs
+        </Link>{' '}
+        for more information.
+      </Alert>
+    </>
+  );
+};
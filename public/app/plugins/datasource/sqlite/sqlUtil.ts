@@ -0,0 +1,90 @@
+import { isEmpty } from 'lodash';
+
+import { RAQBFieldTypes, SQLExpression, SQLQuery } from 'app/features/plugins/sql/types';
+
+// getFieldConfig follows the rules SQLite uses to find the type affinity of a column from its declared type,
+// see https://www.sqlite.org/datatype3.html#determination_of_column_affinity
+export function getFieldConfig(type: string): { raqbFieldType: RAQBFieldTypes; icon: string } {
+  const declared = type.toUpperCase();
+  if (declared.includes('BOOL')) {
+    return { raqbFieldType: 'boolean', icon: 'toggle-off' };
+  }
+  if (declared.includes('DATE') || declared.includes('TIME')) {
+    return { raqbFieldType: 'datetime', icon: 'clock-nine' };
+  }
+  if (declared.includes('INT')) {
+    return { raqbFieldType: 'number', icon: 'calculator-alt' };
+  }
+  if (declared.includes('CHAR') || declared.includes('CLOB') || declared.includes('TEXT')) {
+    return { raqbFieldType: 'text', icon: 'text' };
+  }
+  if (declared.includes('REAL') || declared.includes('FLOA') || declared.includes('DOUB') || declared.includes('NUM')) {
+    return { raqbFieldType: 'number', icon: 'calculator-alt' };
+  }
+  return { raqbFieldType: 'text', icon: 'text' };
+}
+
+export function toRawSql({ sql, table }: SQLQuery): string {
+  let rawQuery = '';
+
+  // Return early with empty string if there is no sql column
+  if (!sql || !haveColumns(sql.columns)) {
+    return rawQuery;
+  }
+
+  rawQuery += createSelectClause(sql.columns);
+
+  if (table) {
+    rawQuery += `FROM ${table} `;
+  }
+
+  if (sql.whereString) {
+    rawQuery += `WHERE ${sql.whereString} `;
+  }
+
+  if (sql.groupBy?.[0]?.property.name) {
+    const groupBy = sql.groupBy.map((g) => g.property.name).filter((g) => !isEmpty(g));
+    rawQuery += `GROUP BY ${groupBy.join(', ')} `;
+  }
+
+  if (sql.orderBy?.property.name) {
+    rawQuery += `ORDER BY ${sql.orderBy.property.name} `;
+  }
+
+  if (sql.orderBy?.property.name && sql.orderByDirection) {
+    rawQuery += `${sql.orderByDirection} `;
+  }
+
+  if (sql.limit !== undefined && sql.limit >= 0) {
+    rawQuery += `LIMIT ${sql.limit} `;
+  }
+  return rawQuery;
+}
+
+function createSelectClause(sqlColumns: NonNullable<SQLExpression['columns']>): string {
+  const columns = sqlColumns.map((c) => {
+    let rawColumn = '';
+    if (c.name && c.alias) {
+      rawColumn += `${c.name}(${c.parameters?.map((p) => `${p.name}`)}) AS ${c.alias}`;
+    } else if (c.name) {
+      rawColumn += `${c.name}(${c.parameters?.map((p) => `${p.name}`)})`;
+    } else if (c.alias) {
+      rawColumn += `${c.parameters?.map((p) => `${p.name}`)} AS ${c.alias}`;
+    } else {
+      rawColumn += `${c.parameters?.map((p) => `${p.name}`)}`;
+    }
+    return rawColumn;
+  });
+
+  return `SELECT ${columns.join(', ')} `;
+}
+
+export const haveColumns = (columns: SQLExpression['columns']): columns is NonNullable<SQLExpression['columns']> => {
+  if (!columns) {
+    return false;
+  }
+
+  const haveColumn = columns.some((c) => c.parameters?.length || c.parameters?.some((p) => p.name));
+  const haveFunction = columns.some((c) => c.name);
+  return haveColumn || haveFunction;
+};